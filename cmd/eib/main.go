@@ -14,6 +14,12 @@ func main() {
 	app.Commands = []*cli.Command{
 		cmd.NewBuildCommand(build.Run),
 		cmd.NewValidateCommand(build.Validate),
+		cmd.NewLintCommand(build.Lint),
+		cmd.NewDebugCommand(build.Debug),
+		cmd.NewVerifyCacheCommand(build.VerifyCache),
+		cmd.NewCacheCommand(build.WarmCache),
+		cmd.NewMigrateCommand(build.Migrate),
+		cmd.NewDiffCommand(build.Diff),
 		cmd.NewVersionCommand(build.Version),
 	}
 
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 )
@@ -25,14 +27,8 @@ func NewBuilder(ctx *image.Context, imageConfigurator imageConfigurator) *Builde
 	}
 }
 
-func (b *Builder) Build() error {
-	log.Audit("Generating image customization components...")
-
-	if err := b.imageConfigurator.Configure(b.context); err != nil {
-		log.Audit("Error configuring customization components.")
-		return fmt.Errorf("configuring image: %w", err)
-	}
-
+// Assemble builds the image from the base image and the combustion content generated by Configure.
+func (b *Builder) Assemble() error {
 	switch b.context.ImageDefinition.Image.ImageType {
 	case image.TypeISO:
 		log.Audit("Building ISO image...")
@@ -55,6 +51,40 @@ func (b *Builder) Build() error {
 	return nil
 }
 
+// Configure generates the combustion content of the image under the build directory, without assembling the image.
+func (b *Builder) Configure() error {
+	log.Audit("Generating image customization components...")
+
+	if err := b.imageConfigurator.Configure(b.context); err != nil {
+		log.Audit("Error configuring customization components.")
+		return fmt.Errorf("configuring image: %w", err)
+	}
+
+	if err := b.applySourceDate(); err != nil {
+		log.Audit("Error setting reproducible timestamps.")
+		return fmt.Errorf("applying source date: %w", err)
+	}
+
+	return nil
+}
+
+// applySourceDate sets the timestamps of the generated combustion content to the fixed source date,
+// so that builds from identical inputs embed identical content.
+func (b *Builder) applySourceDate() error {
+	if b.context.SourceDate.IsZero() {
+		return nil
+	}
+
+	for _, dir := range []string{b.context.CombustionDir, b.context.ArtefactsDir} {
+		if err := fileio.SetModTimes(dir, b.context.SourceDate); err != nil {
+			return fmt.Errorf("setting timestamps under '%s': %w", dir, err)
+		}
+	}
+
+	log.AuditInfof("Timestamps of the combustion content were set to %s.", b.context.SourceDate.Format(time.RFC3339))
+	return nil
+}
+
 func (b *Builder) generateBuildDirFilename(filename string) string {
 	return filepath.Join(b.context.BuildDir, filename)
 }
@@ -65,8 +95,7 @@ func (b *Builder) generateOutputImageFilename() string {
 }
 
 func (b *Builder) generateBaseImageFilename() string {
-	filename := filepath.Join(b.context.ImageConfigDir, "base-images", b.context.ImageDefinition.Image.BaseImage)
-	return filename
+	return b.context.BaseImagePath()
 }
 
 func (b *Builder) deleteExistingOutputImage() error {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,3 +89,57 @@ func TestDeleteExistingImage(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, os.IsNotExist(err))
 }
+
+func TestApplySourceDate(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ArtefactsDir = filepath.Join(ctx.BuildDir, "artefacts")
+	require.NoError(t, os.Mkdir(ctx.ArtefactsDir, os.ModePerm))
+
+	scriptPath := filepath.Join(ctx.CombustionDir, "script")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash"), 0o600))
+
+	artefactPath := filepath.Join(ctx.ArtefactsDir, "artefact")
+	require.NoError(t, os.WriteFile(artefactPath, []byte("data"), 0o600))
+
+	ctx.SourceDate = time.Unix(1700000000, 0)
+	builder := Builder{context: ctx}
+
+	// Test
+	err := builder.applySourceDate()
+
+	// Verify
+	require.NoError(t, err)
+
+	for _, path := range []string{ctx.CombustionDir, scriptPath, ctx.ArtefactsDir, artefactPath} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, ctx.SourceDate.Equal(info.ModTime()), "unexpected modification time of %s", path)
+	}
+}
+
+func TestApplySourceDate_Disabled(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	scriptPath := filepath.Join(ctx.CombustionDir, "script")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash"), 0o600))
+
+	before, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+
+	builder := Builder{context: ctx}
+
+	// Test
+	err = builder.applySourceDate()
+
+	// Verify
+	require.NoError(t, err)
+
+	after, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, before.ModTime(), after.ModTime())
+}
@@ -0,0 +1,98 @@
+package build
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	combustionIsoScriptName = "combustion-iso.sh"
+	combustionIsoLogFile    = "combustion-iso.log"
+	combustionIsoSuffix     = "-combustion.iso"
+	// combustionIsoVolumeID matches the label the combustion script mounts to access the artefacts,
+	// which is also among the labels combustion searches for its configuration.
+	combustionIsoVolumeID = "INSTALL"
+)
+
+//go:embed templates/combustion-iso.sh.tpl
+var combustionIsoTemplate string
+
+// BuildCombustionISO packages the combustion content generated by Configure into an ISO, which can be attached
+// to an existing installation instead of assembling the full image from the base image.
+func (b *Builder) BuildCombustionISO() error {
+	log.Audit("Building combustion ISO...")
+
+	outputFilename := b.generateCombustionIsoFilename()
+	if err := os.Remove(outputFilename); err != nil && !os.IsNotExist(err) {
+		log.Audit("Error building combustion ISO.")
+		return fmt.Errorf("deleting existing combustion ISO: %w", err)
+	}
+
+	if err := b.writeCombustionIsoScript(outputFilename); err != nil {
+		log.Audit("Error building combustion ISO.")
+		return fmt.Errorf("creating the combustion ISO script: %w", err)
+	}
+
+	cmd, isoLog, err := b.createIsoCommand(combustionIsoLogFile, combustionIsoScriptName)
+	if err != nil {
+		log.Audit("Error building combustion ISO.")
+		return fmt.Errorf("preparing to build the combustion ISO: %w", err)
+	}
+	defer func() {
+		if err = isoLog.Close(); err != nil {
+			zap.S().Warnf("failed to close combustion ISO log file properly: %s", err)
+		}
+	}()
+
+	if err = cmd.Run(); err != nil {
+		log.Audit("Error building combustion ISO.")
+		return fmt.Errorf("building the combustion ISO: %w", err)
+	}
+
+	b.context.CombustionISO = outputFilename
+
+	log.AuditInfof("Combustion ISO build complete, the ISO is available at '%s'.", outputFilename)
+	return nil
+}
+
+func (b *Builder) writeCombustionIsoScript(outputFilename string) error {
+	arguments := struct {
+		OutputImageFilename string
+		CombustionDir       string
+		ArtefactsDir        string
+		VolumeID            string
+	}{
+		OutputImageFilename: outputFilename,
+		CombustionDir:       b.context.CombustionDir,
+		ArtefactsDir:        b.context.ArtefactsDir,
+		VolumeID:            combustionIsoVolumeID,
+	}
+
+	contents, err := template.Parse(combustionIsoScriptName, combustionIsoTemplate, arguments)
+	if err != nil {
+		return fmt.Errorf("applying the combustion ISO script template: %w", err)
+	}
+
+	scriptName := b.generateBuildDirFilename(combustionIsoScriptName)
+	if err = os.WriteFile(scriptName, []byte(contents), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing combustion ISO script %s: %w", combustionIsoScriptName, err)
+	}
+
+	return nil
+}
+
+// generateCombustionIsoFilename names the combustion ISO after the output image, replacing its extension.
+func (b *Builder) generateCombustionIsoFilename() string {
+	name := b.context.ImageDefinition.Image.OutputImageName
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	return filepath.Join(b.context.ImageConfigDir, name+combustionIsoSuffix)
+}
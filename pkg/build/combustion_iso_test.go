@@ -0,0 +1,74 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWriteCombustionIsoScript(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ArtefactsDir = filepath.Join(ctx.BuildDir, "artefacts")
+	builder := Builder{context: ctx}
+
+	require.NoError(t, builder.writeCombustionIsoScript("/eib/edge-combustion.iso"))
+
+	scriptPath := filepath.Join(ctx.BuildDir, combustionIsoScriptName)
+
+	info, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+	b, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "OUTPUT_IMAGE=/eib/edge-combustion.iso")
+	assert.Contains(t, contents, "COMBUSTION_DIR="+ctx.CombustionDir)
+	assert.Contains(t, contents, "ARTEFACTS_DIR="+ctx.ArtefactsDir)
+	assert.Contains(t, contents, "-volid INSTALL")
+	assert.Contains(t, contents, "-map ${COMBUSTION_DIR} /combustion")
+	assert.Contains(t, contents, "-map ${ARTEFACTS_DIR} /artefacts")
+}
+
+func TestGenerateCombustionIsoFilename(t *testing.T) {
+	tests := map[string]struct {
+		outputImageName string
+		expected        string
+	}{
+		"iso": {
+			outputImageName: "edge.iso",
+			expected:        "edge-combustion.iso",
+		},
+		"qcow2": {
+			outputImageName: "edge-1.0.qcow2",
+			expected:        "edge-1.0-combustion.iso",
+		},
+		"no extension": {
+			outputImageName: "edge",
+			expected:        "edge-combustion.iso",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				context: &image.Context{
+					ImageConfigDir: "/eib",
+					ImageDefinition: &image.Definition{
+						Image: image.Image{OutputImageName: test.outputImageName},
+					},
+				},
+			}
+
+			assert.Equal(t, filepath.Join("/eib", test.expected), builder.generateCombustionIsoFilename())
+		})
+	}
+}
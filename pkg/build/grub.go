@@ -1,35 +1,53 @@
 package build
 
 import (
+	"bufio"
 	_ "embed"
 	"fmt"
+	"os"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
 )
 
 const (
 	kernelComponentName = "kernel params"
+	grubCmdlinePrefix   = "GRUB_CMDLINE_LINUX_DEFAULT="
+	crashKernelArgument = "crashkernel"
 )
 
 //go:embed templates/grub/guestfish-snippet.tpl
 var guestfishSnippet string
 
 func (b *Builder) generateGRUBGuestfishCommands() (string, error) {
+	kernelArgs := b.context.ImageDefinition.OperatingSystem.KernelArgs
+	kernelArgs.Add = b.kernelArgsToAdd()
+	kernelArgs.Remove = b.kernelArgsToRemove()
+
 	// Nothing to do if there aren't any args. Return an empty string that will be injected
 	// into the raw image guestfish modification, effectively doing nothing but not breaking
 	// the guestfish command
-	if b.context.ImageDefinition.OperatingSystem.KernelArgs == nil {
+	if kernelArgs.Add == nil && kernelArgs.Remove == nil {
 		log.AuditComponentSkipped(kernelComponentName)
 		return "", nil
 	}
 
-	argLine := strings.Join(b.context.ImageDefinition.OperatingSystem.KernelArgs, " ")
+	var removePatterns []string
+	for _, arg := range kernelArgs.Remove {
+		removePatterns = append(removePatterns, kernelArgRemovalPattern(arg))
+	}
+
+	argLine := strings.Join(kernelArgs.Add, " ")
 	values := struct {
-		KernelArgs string
+		KernelArgs     string
+		RemovePatterns []string
 	}{
-		KernelArgs: argLine,
+		KernelArgs:     argLine,
+		RemovePatterns: removePatterns,
 	}
 
 	snippet, err := template.Parse("guestfish-snippet", guestfishSnippet, values)
@@ -41,3 +59,93 @@ func (b *Builder) generateGRUBGuestfishCommands() (string, error) {
 	log.AuditComponentSuccessful(kernelComponentName)
 	return snippet, nil
 }
+
+// kernelArgsToAdd returns the configured kernel arguments, along with those enabling the requested
+// Secure Boot features and reserving the memory of the kdump crash kernel.
+func (b *Builder) kernelArgsToAdd() []string {
+	args := b.context.ImageDefinition.OperatingSystem.KernelArgs.Add
+
+	if lockdown := b.context.ImageDefinition.OperatingSystem.SecureBoot.Lockdown; lockdown != "" {
+		args = append(slices.Clone(args), fmt.Sprintf("%s=%s", lockdownKernelArgument, lockdown))
+	}
+
+	if memory := b.context.ImageDefinition.OperatingSystem.Kdump.Memory; memory != "" {
+		args = append(slices.Clone(args), fmt.Sprintf("%s=%s", crashKernelArgument, memory))
+	}
+
+	return args
+}
+
+// kernelArgsToRemove returns the configured kernel argument removals. When kdump is configured, any memory
+// reservation of the base image is removed as well, as the kernel only honours a single one.
+func (b *Builder) kernelArgsToRemove() []string {
+	args := b.context.ImageDefinition.OperatingSystem.KernelArgs.Remove
+
+	if b.context.ImageDefinition.OperatingSystem.Kdump.Memory != "" && !slices.Contains(args, crashKernelArgument) {
+		args = append(slices.Clone(args), crashKernelArgument)
+	}
+
+	return args
+}
+
+// kernelArgRemovalPattern builds the extended sed regular expression matching an argument to remove.
+// An argument without a value matches the argument with any value. Both variants contain exactly one
+// group, which the snippet relies on when referencing the groups surrounding the pattern.
+func kernelArgRemovalPattern(arg string) string {
+	escape := func(s string) string {
+		return strings.ReplaceAll(regexp.QuoteMeta(s), "/", `\/`)
+	}
+
+	key, value, hasValue := strings.Cut(arg, "=")
+	if !hasValue {
+		return escape(key) + `(=[^[:space:]"]*)?`
+	}
+
+	return escape(key) + "(=" + escape(value) + ")"
+}
+
+// reportKernelCommandLine audits the kernel command line the image was configured with,
+// as printed into the modification log by the GRUB guestfish snippet.
+func (b *Builder) reportKernelCommandLine(logFilename string) {
+	if b.kernelArgsToAdd() == nil && b.kernelArgsToRemove() == nil {
+		return
+	}
+
+	cmdline, err := findKernelCommandLine(logFilename)
+	if err != nil {
+		zap.S().Warnf("Failed to determine the resulting kernel command line: %s", err)
+		return
+	}
+
+	log.AuditInfof("Resulting kernel command line: %s", cmdline)
+}
+
+func findKernelCommandLine(logFilename string) (string, error) {
+	logFile, err := os.Open(logFilename)
+	if err != nil {
+		return "", fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	var cmdline string
+	found := false
+
+	scanner := bufio.NewScanner(logFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if value, ok := strings.CutPrefix(line, grubCmdlinePrefix); ok {
+			cmdline = strings.Join(strings.Fields(strings.Trim(value, `"`)), " ")
+			found = true
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading log file: %w", err)
+	}
+
+	if !found {
+		return "", fmt.Errorf("no kernel command line found in %s", logFilename)
+	}
+
+	return cmdline, nil
+}
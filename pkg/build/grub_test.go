@@ -1,6 +1,7 @@
 package build
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,7 @@ func TestGenerateGRUBGuestfishCommands(t *testing.T) {
 		context: &image.Context{
 			ImageDefinition: &image.Definition{
 				OperatingSystem: image.OperatingSystem{
-					KernelArgs: []string{"alpha", "beta"},
+					KernelArgs: image.KernelArgs{Add: []string{"alpha", "beta"}},
 				},
 			},
 		},
@@ -34,6 +35,58 @@ func TestGenerateGRUBGuestfishCommands(t *testing.T) {
 	assert.Contains(t, commandString, expectedDefault)
 }
 
+func TestGenerateGRUBGuestfishCommandsLockdown(t *testing.T) {
+	// Setup
+	builder := Builder{
+		context: &image.Context{
+			ImageDefinition: &image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					KernelArgs: image.KernelArgs{Add: []string{"alpha"}},
+					SecureBoot: image.SecureBoot{Lockdown: image.LockdownIntegrity},
+				},
+			},
+		},
+	}
+
+	// Test
+	commandString, err := builder.generateGRUBGuestfishCommands()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Contains(t, commandString, "sed -i '/ignition.platform/ s/$/ alpha lockdown=integrity /' /tmp/grub.cfg")
+
+	// The definition is left untouched
+	assert.Equal(t, []string{"alpha"}, builder.context.ImageDefinition.OperatingSystem.KernelArgs.Add)
+}
+
+func TestGenerateGRUBGuestfishCommandsKdump(t *testing.T) {
+	// Setup
+	builder := Builder{
+		context: &image.Context{
+			ImageDefinition: &image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					KernelArgs: image.KernelArgs{Add: []string{"alpha"}, Remove: []string{"quiet"}},
+					Kdump:      image.Kdump{Memory: "2G-64G:256M,64G-:512M"},
+				},
+			},
+		},
+	}
+
+	// Test
+	commandString, err := builder.generateGRUBGuestfishCommands()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Contains(t, commandString, "sed -i '/ignition.platform/ s/$/ alpha crashkernel=2G-64G:256M,64G-:512M /' /tmp/grub.cfg")
+
+	// - the reservation of the base image is replaced
+	assert.Contains(t, commandString, `s/([[:space:]])crashkernel(=[^[:space:]"]*)?([[:space:]]+|$)/\1/`)
+	assert.Contains(t, commandString, `s/([[:space:]])quiet(=[^[:space:]"]*)?([[:space:]]+|$)/\1/`)
+
+	// The definition is left untouched
+	assert.Equal(t, []string{"quiet"}, builder.context.ImageDefinition.OperatingSystem.KernelArgs.Remove)
+}
+
 func TestGenerateGRUBGuestfishCommandsNoArgs(t *testing.T) {
 	// Setup
 	builder := Builder{
@@ -51,3 +104,76 @@ func TestGenerateGRUBGuestfishCommandsNoArgs(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "", commandString)
 }
+
+func TestGenerateGRUBGuestfishCommandsRemovals(t *testing.T) {
+	// Setup
+	builder := Builder{
+		context: &image.Context{
+			ImageDefinition: &image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					KernelArgs: image.KernelArgs{
+						Remove: []string{"quiet", "console=ttyS0,115200", "root=/dev/sda3"},
+					},
+				},
+			},
+		},
+	}
+
+	// Test
+	commandString, err := builder.generateGRUBGuestfishCommands()
+
+	// Verify
+	require.NoError(t, err)
+
+	expectedFirstBoot := `sed -i -E '/ignition.platform/ { :a; s/([[:space:]])quiet(=[^[:space:]"]*)?([[:space:]]+|$)/\1/; ta }' /tmp/grub.cfg`
+	assert.Contains(t, commandString, expectedFirstBoot)
+
+	expectedDefault := `sed -i -E '/^GRUB_CMDLINE_LINUX_DEFAULT="/ { :a; s/([[:space:]]|")console(=ttyS0,115200)([[:space:]]+|("))/\1\4/; ta }' /tmp/grub`
+	assert.Contains(t, commandString, expectedDefault)
+
+	assert.Contains(t, commandString, `root(=\/dev\/sda3)`)
+
+	// - no additions were requested
+	assert.NotContains(t, commandString, "s/$/")
+}
+
+func TestKernelArgRemovalPattern(t *testing.T) {
+	assert.Equal(t, `quiet(=[^[:space:]"]*)?`, kernelArgRemovalPattern("quiet"))
+	assert.Equal(t, `splash(=silent)`, kernelArgRemovalPattern("splash=silent"))
+	assert.Equal(t, `rd\.break(=pre\.mount)`, kernelArgRemovalPattern("rd.break=pre.mount"))
+	assert.Equal(t, `root(=\/dev\/sda3)`, kernelArgRemovalPattern("root=/dev/sda3"))
+}
+
+func TestFindKernelCommandLine(t *testing.T) {
+	// Setup
+	logFile, err := os.CreateTemp("", "eib-raw-build-log-")
+	require.NoError(t, err)
+	defer os.Remove(logFile.Name())
+
+	contents := "[INFO] 512 byte sector check successful.\nGRUB_CMDLINE_LINUX_DEFAULT=\"splash=silent  console=ttyS1 \"\n"
+	_, err = logFile.WriteString(contents)
+	require.NoError(t, err)
+	require.NoError(t, logFile.Close())
+
+	// Test
+	cmdline, err := findKernelCommandLine(logFile.Name())
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, "splash=silent console=ttyS1", cmdline)
+}
+
+func TestFindKernelCommandLine_Missing(t *testing.T) {
+	// Setup
+	logFile, err := os.CreateTemp("", "eib-raw-build-log-")
+	require.NoError(t, err)
+	defer os.Remove(logFile.Name())
+	require.NoError(t, logFile.Close())
+
+	// Test
+	_, err = findKernelCommandLine(logFile.Name())
+
+	// Verify
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "no kernel command line found")
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/suse-edge/edge-image-builder/pkg/env"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
@@ -142,6 +143,11 @@ func (b *Builder) createIsoCommand(logFilename, scriptName string) (*exec.Cmd, *
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 
+	// xorriso uses the source date for the volume and file dates of the rebuilt ISO
+	if !b.context.SourceDate.IsZero() {
+		cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%d", env.SourceDateEpochVariable, b.context.SourceDate.Unix()))
+	}
+
 	return cmd, logFile, nil
 }
 
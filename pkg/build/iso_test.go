@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -138,6 +139,23 @@ func TestCreateIsoCommand(t *testing.T) {
 	assert.Equal(t, expectedCommandPath, cmd.Path)
 	assert.Equal(t, logFile, cmd.Stdout)
 	assert.Equal(t, logFile, cmd.Stderr)
+	assert.Nil(t, cmd.Env)
+}
+
+func TestCreateIsoCommand_SourceDate(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.SourceDate = time.Unix(1700000000, 0)
+	builder := Builder{context: ctx}
+
+	// Test
+	cmd, _, err := builder.createIsoCommand("test-log", "test-script")
+
+	// Verify
+	require.NoError(t, err)
+	assert.Contains(t, cmd.Env, "SOURCE_DATE_EPOCH=1700000000")
 }
 
 func TestFindExtractedRawImage(t *testing.T) {
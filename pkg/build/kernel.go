@@ -0,0 +1,116 @@
+package build
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	kernelScriptName    = "kernel.sh"
+	removedKernelPrefix = "[INFO] Removed kernel: "
+)
+
+// embeddedKernelRegex matches the line printed into the modification log once the pinned kernel is installed
+var embeddedKernelRegex = regexp.MustCompile(`^\[INFO\] Embedded kernel: (\S+) \((\S+)\)$`)
+
+//go:embed templates/kernel.sh.tpl
+var kernelTemplate string
+
+// writeKernelScript writes the script installing the pinned kernel from within the image, returning its location
+// or an empty string if the kernel of the base image is kept. The repository of the resolved packages is uploaded
+// to the returned directory of the image, from which the script installs the kernel.
+func (b *Builder) writeKernelScript() (script, repoDir string, err error) {
+	kernel := b.context.ImageDefinition.OperatingSystem.Packages.Kernel
+	if kernel.Version == "" {
+		return "", "", nil
+	}
+
+	if b.context.RPMRepoPath == "" {
+		return "", "", fmt.Errorf("the pinned kernel %s-%s was not resolved", kernel.Package(), kernel.Version)
+	}
+
+	values := struct {
+		RepoDir string
+		Name    string
+		Version string
+	}{
+		RepoDir: path.Join("/tmp", filepath.Base(b.context.RPMRepoPath)),
+		Name:    kernel.Package(),
+		Version: kernel.Version,
+	}
+
+	data, err := template.Parse(kernelScriptName, kernelTemplate, &values)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing %s template: %w", kernelScriptName, err)
+	}
+
+	filename := b.generateBuildDirFilename(kernelScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return "", "", fmt.Errorf("writing %s: %w", kernelScriptName, err)
+	}
+
+	return filename, values.RepoDir, nil
+}
+
+// reportEmbeddedKernel audits the pinned kernel and the kernels of the base image it replaced, as printed into
+// the modification log by the kernel script, and records the kernel release for the build report.
+func (b *Builder) reportEmbeddedKernel(logFilename string) {
+	if b.context.ImageDefinition.OperatingSystem.Packages.Kernel.Version == "" {
+		return
+	}
+
+	kernel, release, removed, err := findEmbeddedKernel(logFilename)
+	if err != nil {
+		zap.S().Warnf("Failed to determine the embedded kernel: %s", err)
+		return
+	}
+
+	b.context.KernelRelease = release
+
+	if len(removed) == 0 {
+		log.AuditInfof("Embedded kernel %s (%s).", kernel, release)
+		return
+	}
+
+	log.AuditInfof("Embedded kernel %s (%s), replacing the base image kernel(s): %s", kernel, release, strings.Join(removed, ", "))
+}
+
+func findEmbeddedKernel(logFilename string) (kernel, release string, removed []string, err error) {
+	logFile, err := os.Open(logFilename)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	scanner := bufio.NewScanner(logFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if removedKernel, ok := strings.CutPrefix(line, removedKernelPrefix); ok {
+			removed = append(removed, removedKernel)
+		} else if matches := embeddedKernelRegex.FindStringSubmatch(line); matches != nil {
+			kernel, release = matches[1], matches[2]
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return "", "", nil, fmt.Errorf("reading log file: %w", err)
+	}
+
+	if kernel == "" {
+		return "", "", nil, fmt.Errorf("no embedded kernel found in %s", logFilename)
+	}
+
+	return kernel, release, removed, nil
+}
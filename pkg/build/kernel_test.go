@@ -0,0 +1,127 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWriteKernelScript(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			OutputImageName: "output-image",
+		},
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{
+				Kernel: image.Kernel{
+					Version: "6.4.0-150600.23.25.1",
+				},
+			},
+		},
+	}
+	ctx.RPMRepoPath = filepath.Join(ctx.ArtefactsDir, "rpms", "rpm-repo")
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, repoDir, err := builder.writeKernelScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(ctx.BuildDir, kernelScriptName), filename)
+	assert.Equal(t, "/tmp/rpm-repo", repoDir)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	foundContents := string(foundBytes)
+
+	assert.Contains(t, foundContents, "zypper --non-interactive addrepo 'dir:/tmp/rpm-repo' eib-kernel")
+	assert.Contains(t, foundContents, "--auto-agree-with-licenses 'kernel-default=6.4.0-150600.23.25.1'")
+	assert.Contains(t, foundContents, `if [ "$installed" != 'kernel-default-6.4.0-150600.23.25.1' ]; then`)
+	assert.Contains(t, foundContents, `dracut --force --no-hostonly "/boot/initrd-$KERNEL_RELEASE" "$KERNEL_RELEASE"`)
+	assert.Contains(t, foundContents, `echo "[INFO] Embedded kernel: kernel-default-6.4.0-150600.23.25.1 ($KERNEL_RELEASE)"`)
+
+	// Modification script
+	outputImageFilename := builder.generateOutputImageFilename()
+	require.NoError(t, builder.writeModifyScript(outputImageFilename, true, true))
+
+	foundBytes, err = os.ReadFile(filepath.Join(ctx.BuildDir, modifyScriptName))
+	require.NoError(t, err)
+	foundContents = string(foundBytes)
+
+	assert.Contains(t, foundContents, "copy-in "+ctx.RPMRepoPath+" /tmp")
+	assert.Contains(t, foundContents, "upload "+filename+" /tmp/eib-kernel.sh")
+	assert.Contains(t, foundContents, "rm-rf /tmp/rpm-repo")
+}
+
+func TestWriteKernelScript_NotPinned(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, repoDir, err := builder.writeKernelScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Empty(t, filename)
+	assert.Empty(t, repoDir)
+
+	_, err = os.Stat(filepath.Join(ctx.BuildDir, kernelScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestWriteKernelScript_NotResolved(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{
+				Kernel: image.Kernel{
+					Name:    "kernel-rt",
+					Version: "6.4.0-150600.10.5.1",
+				},
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	// Test
+	_, _, err := builder.writeKernelScript()
+
+	// Verify
+	require.EqualError(t, err, "the pinned kernel kernel-rt-6.4.0-150600.10.5.1 was not resolved")
+}
+
+func TestFindEmbeddedKernel(t *testing.T) {
+	logFilename := filepath.Join(t.TempDir(), "raw-build.log")
+	contents := `[INFO] 512 byte sector check successful.
+[INFO] Removed kernel: kernel-default-6.4.0-150600.21.3
+[INFO] Embedded kernel: kernel-default-6.4.0-150600.23.25.1 (6.4.0-150600.23.25-default)
+`
+	require.NoError(t, os.WriteFile(logFilename, []byte(contents), 0o600))
+
+	kernel, release, removed, err := findEmbeddedKernel(logFilename)
+	require.NoError(t, err)
+
+	assert.Equal(t, "kernel-default-6.4.0-150600.23.25.1", kernel)
+	assert.Equal(t, "6.4.0-150600.23.25-default", release)
+	assert.Equal(t, []string{"kernel-default-6.4.0-150600.21.3"}, removed)
+}
+
+func TestFindEmbeddedKernel_NotFound(t *testing.T) {
+	logFilename := filepath.Join(t.TempDir(), "raw-build.log")
+	require.NoError(t, os.WriteFile(logFilename, []byte("[INFO] 512 byte sector check successful.\n"), 0o600))
+
+	_, _, _, err := findEmbeddedKernel(logFilename)
+	require.ErrorContains(t, err, "no embedded kernel found in")
+}
@@ -0,0 +1,107 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	qemuImgExec          = "/usr/bin/qemu-img"
+	qcow2ConvertLogFile  = "qcow2-convert.log"
+	qcow2IntermediateRaw = "qcow2-source.raw"
+)
+
+type qcow2ImageInfo struct {
+	VirtualSize int64 `json:"virtual-size"`
+	ActualSize  int64 `json:"actual-size"`
+}
+
+func (b *Builder) isQcow2Output() bool {
+	return b.context.ImageDefinition.Image.OutputFormat == image.OutputFormatQCOW2
+}
+
+// convertToQcow2 converts the assembled raw image into the output qcow2 image.
+func (b *Builder) convertToQcow2(rawImagePath string) error {
+	logFilename := filepath.Join(b.context.BuildDir, qcow2ConvertLogFile)
+	logFile, err := os.Create(logFilename)
+	if err != nil {
+		return fmt.Errorf("creating log file: %w", err)
+	}
+
+	defer func() {
+		if err = logFile.Close(); err != nil {
+			zap.S().Warnf("Failed to close qcow2 conversion log file properly: %s", err)
+		}
+	}()
+
+	cmd := b.createQcow2ConvertCommand(rawImagePath)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+
+	if err = cmd.Run(); err != nil {
+		return fmt.Errorf("converting raw image to qcow2: %w", err)
+	}
+
+	// The intermediate raw image is no longer needed and can be considerably large
+	if err = os.Remove(rawImagePath); err != nil {
+		zap.S().Warnf("Failed to remove intermediate raw image '%s': %s", rawImagePath, err)
+	}
+
+	info, err := b.qcow2Info()
+	if err != nil {
+		return fmt.Errorf("inspecting qcow2 image: %w", err)
+	}
+
+	b.recordQcow2Size(info)
+
+	return nil
+}
+
+// recordQcow2Size records the sizes of the converted image for the build report.
+func (b *Builder) recordQcow2Size(info *qcow2ImageInfo) {
+	b.context.Qcow2VirtualSize = info.VirtualSize
+	b.context.Qcow2ActualSize = info.ActualSize
+
+	log.AuditInfof("QCOW2 image size: %d MB virtual, %d MB actual.",
+		info.VirtualSize/(1024*1024), info.ActualSize/(1024*1024))
+}
+
+func (b *Builder) createQcow2ConvertCommand(rawImagePath string) *exec.Cmd {
+	args := []string{"convert", "-f", "raw", "-O", "qcow2"}
+	if b.context.ImageDefinition.Image.Qcow2Configuration.Compress {
+		args = append(args, "-c")
+	}
+	if options := b.qemuImgToolOptions(); options != "" {
+		args = append(args, "-o", options)
+	}
+	args = append(args, rawImagePath, b.generateOutputImageFilename())
+
+	return exec.Command(qemuImgExec, args...)
+}
+
+func (b *Builder) qcow2Info() (*qcow2ImageInfo, error) {
+	cmd := exec.Command(qemuImgExec, "info", "--output=json", b.generateOutputImageFilename())
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running qemu-img info: %w", err)
+	}
+
+	return parseQcow2Info(output)
+}
+
+func parseQcow2Info(data []byte) (*qcow2ImageInfo, error) {
+	var info qcow2ImageInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing image info: %w", err)
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,115 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestCreateQcow2ConvertCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		compress     bool
+		toolArgs     map[string]string
+		expectedArgs []string
+	}{
+		{
+			name: "Uncompressed",
+			expectedArgs: []string{
+				qemuImgExec, "convert", "-f", "raw", "-O", "qcow2", "build-dir/qcow2-source.raw", "config-dir/build-image.qcow2",
+			},
+		},
+		{
+			name:     "Compressed",
+			compress: true,
+			expectedArgs: []string{
+				qemuImgExec, "convert", "-f", "raw", "-O", "qcow2", "-c", "build-dir/qcow2-source.raw", "config-dir/build-image.qcow2",
+			},
+		},
+		{
+			name:     "Tool arguments",
+			toolArgs: map[string]string{"qcow2-cluster-size": "2M", "libguestfs-memsize": "2048"},
+			expectedArgs: []string{
+				qemuImgExec, "convert", "-f", "raw", "-O", "qcow2", "-o", "cluster_size=2M", "build-dir/qcow2-source.raw", "config-dir/build-image.qcow2",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Setup
+			builder := Builder{
+				context: &image.Context{
+					ImageConfigDir: "config-dir",
+					BuildDir:       "build-dir",
+					ToolArgs:       test.toolArgs,
+					ImageDefinition: &image.Definition{
+						Image: image.Image{
+							OutputImageName: "build-image.qcow2",
+							OutputFormat:    image.OutputFormatQCOW2,
+							Qcow2Configuration: image.Qcow2Configuration{
+								Compress: test.compress,
+							},
+						},
+					},
+				},
+			}
+
+			// Test
+			cmd := builder.createQcow2ConvertCommand(builder.generateRawImageFilename())
+
+			// Verify
+			require.NotNil(t, cmd)
+
+			assert.Equal(t, qemuImgExec, cmd.Path)
+			assert.Equal(t, test.expectedArgs, cmd.Args)
+		})
+	}
+}
+
+func TestGenerateRawImageFilename(t *testing.T) {
+	builder := Builder{
+		context: &image.Context{
+			ImageConfigDir: "config-dir",
+			BuildDir:       "build-dir",
+			ImageDefinition: &image.Definition{
+				Image: image.Image{
+					OutputImageName: "build-image",
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, "config-dir/build-image", builder.generateRawImageFilename())
+
+	builder.context.ImageDefinition.Image.OutputFormat = image.OutputFormatQCOW2
+	assert.Equal(t, "build-dir/qcow2-source.raw", builder.generateRawImageFilename())
+}
+
+func TestParseQcow2Info(t *testing.T) {
+	data := []byte(`{
+    "virtual-size": 34359738368,
+    "filename": "eib-image.qcow2",
+    "cluster-size": 65536,
+    "format": "qcow2",
+    "actual-size": 1693188096,
+    "dirty-flag": false
+}`)
+
+	info, err := parseQcow2Info(data)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 34359738368, info.VirtualSize)
+	assert.EqualValues(t, 1693188096, info.ActualSize)
+}
+
+func TestRecordQcow2Size(t *testing.T) {
+	builder := Builder{context: &image.Context{}}
+
+	builder.recordQcow2Size(&qcow2ImageInfo{VirtualSize: 34359738368, ActualSize: 1693188096})
+
+	assert.EqualValues(t, 34359738368, builder.context.Qcow2VirtualSize)
+	assert.EqualValues(t, 1693188096, builder.context.Qcow2ActualSize)
+}
@@ -1,14 +1,19 @@
 package build
 
 import (
+	"bufio"
 	_ "embed"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -18,6 +23,7 @@ const (
 	modifyScriptName        = "modify-raw-image.sh"
 	rawBuildLogFile         = "raw-build.log"
 	availableRawDiskSpaceMB = 150
+	rootSlotLayoutPrefix    = "[INFO] Root slot layout: "
 )
 
 //go:embed templates/modify-raw-image.sh.tpl
@@ -46,13 +52,36 @@ func (b *Builder) buildRawImage() error {
 		return fmt.Errorf("deleting existing RAW image: %w", err)
 	}
 
+	rawImagePath := b.generateRawImageFilename()
+
 	cmd := b.createRawImageCopyCommand()
 	if err = cmd.Run(); err != nil {
 		return fmt.Errorf("copying the base image %s to the output image location %s: %w",
-			b.context.ImageDefinition.Image.BaseImage, b.generateOutputImageFilename(), err)
+			b.context.ImageDefinition.Image.BaseImage, rawImagePath, err)
+	}
+
+	if err = b.modifyRawImage(rawImagePath, true, true); err != nil {
+		return err
+	}
+
+	if b.isQcow2Output() {
+		log.Audit("Converting RAW image to QCOW2...")
+		if err = b.convertToQcow2(rawImagePath); err != nil {
+			return fmt.Errorf("converting image: %w", err)
+		}
 	}
 
-	return b.modifyRawImage(b.generateOutputImageFilename(), true, true)
+	return nil
+}
+
+// generateRawImageFilename returns the location the raw image is assembled at. This is the output image,
+// unless the raw image is converted into a different output format afterward.
+func (b *Builder) generateRawImageFilename() string {
+	if b.isQcow2Output() {
+		return b.generateBuildDirFilename(qcow2IntermediateRaw)
+	}
+
+	return b.generateOutputImageFilename()
 }
 
 func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFilesystem bool) error {
@@ -78,12 +107,95 @@ func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFile
 		return fmt.Errorf("running the image modification script: %w", err)
 	}
 
+	b.reportKernelCommandLine(logFilename)
+	b.reportRemovedPaths(logFilename)
+	b.reportStrippedDocs(logFilename)
+	b.reportRootMountOptions(logFilename)
+	b.reportRootSlots(logFilename)
+	b.reportSignedArtefacts(logFilename)
+	b.reportEmbeddedKernel(logFilename)
+
 	return nil
 }
 
+// reportRootSlots audits the layout of the A/B root slots, as read from the assembled image and printed into
+// the modification log by the modification script, and records it for the build report.
+func (b *Builder) reportRootSlots(logFilename string) {
+	if !b.context.ImageDefinition.OperatingSystem.RawConfiguration.ABPartitions {
+		return
+	}
+
+	slots, err := findRootSlots(logFilename)
+	if err != nil {
+		zap.S().Warnf("Failed to determine the resulting root slots: %s", err)
+		return
+	}
+
+	b.context.RootSlots = slots
+	for _, slot := range slots {
+		if slot.Name == "a" {
+			log.AuditInfof("Root slot A: partition %d (label %s, %d MB, default boot entry)", slot.Partition, slot.Label, slot.SizeMB)
+		} else {
+			log.AuditInfof("Root slot B: partition %d (label %s, %d MB, boot entry eib-slot-b)", slot.Partition, slot.Label, slot.SizeMB)
+		}
+	}
+}
+
+// findRootSlots parses the slot layout lines printed by the modification script, each holding the name,
+// partition number, size in MB and file system label of a slot.
+func findRootSlots(logFilename string) ([]image.RootSlot, error) {
+	logFile, err := os.Open(logFilename)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	var slots []image.RootSlot
+
+	scanner := bufio.NewScanner(logFile)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), rootSlotLayoutPrefix)
+		if !ok {
+			continue
+		}
+
+		fields := strings.SplitN(value, " ", 4)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("invalid root slot layout '%s'", value)
+		}
+
+		partition, parseErr := strconv.Atoi(fields[1])
+		if parseErr != nil {
+			return nil, fmt.Errorf("parsing partition of root slot '%s': %w", fields[0], parseErr)
+		}
+
+		size, parseErr := strconv.ParseInt(fields[2], 10, 64)
+		if parseErr != nil {
+			return nil, fmt.Errorf("parsing size of root slot '%s': %w", fields[0], parseErr)
+		}
+
+		slot := image.RootSlot{Name: fields[0], Partition: partition, SizeMB: size}
+		if len(fields) == 4 {
+			slot.Label = fields[3]
+		}
+
+		slots = append(slots, slot)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading log file: %w", err)
+	}
+
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("no root slot layout found in %s", logFilename)
+	}
+
+	return slots, nil
+}
+
 func (b *Builder) createRawImageCopyCommand() *exec.Cmd {
 	baseImagePath := b.generateBaseImageFilename()
-	outputImagePath := b.generateOutputImageFilename()
+	outputImagePath := b.generateRawImageFilename()
 
 	cmd := exec.Command(copyExec, baseImagePath, outputImagePath)
 	return cmd
@@ -97,23 +209,68 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 		return fmt.Errorf("generating the GRUB configuration commands: %w", err)
 	}
 
+	removePathsScript, err := b.writeRemovePathsScript()
+	if err != nil {
+		return fmt.Errorf("writing the path removal script: %w", err)
+	}
+
+	stripDocsScript, err := b.writeStripDocsScript()
+	if err != nil {
+		return fmt.Errorf("writing the documentation stripping script: %w", err)
+	}
+
+	rootMountOptionsScript, err := b.writeRootMountOptionsScript()
+	if err != nil {
+		return fmt.Errorf("writing the root mount options script: %w", err)
+	}
+
+	kernelScript, kernelRepoDir, err := b.writeKernelScript()
+	if err != nil {
+		return fmt.Errorf("writing the kernel installation script: %w", err)
+	}
+
+	secureBootScript, err := b.writeSecureBootScript(imageFilename)
+	if err != nil {
+		return fmt.Errorf("writing the secure boot signing script: %w", err)
+	}
+
 	// Assemble the template values
 	values := struct {
 		ImagePath           string
 		CombustionDir       string
 		ArtefactsDir        string
 		ConfigureGRUB       string
+		RemovePathsScript   string
+		StripDocsScript     string
+		RootMountOptions    string
+		SecureBootScript    string
+		KernelScript        string
+		KernelRepo          string
+		KernelRepoDir       string
 		ConfigureCombustion bool
 		RenameFilesystem    bool
 		DiskSize            string
+		ABPartitions        bool
+		CombustionDirName   string
+		ArtefactsDirName    string
 	}{
 		ImagePath:           imageFilename,
 		CombustionDir:       b.context.CombustionDir,
 		ArtefactsDir:        b.context.ArtefactsDir,
 		ConfigureGRUB:       grubConfiguration,
+		RemovePathsScript:   removePathsScript,
+		StripDocsScript:     stripDocsScript,
+		RootMountOptions:    rootMountOptionsScript,
+		SecureBootScript:    secureBootScript,
+		KernelScript:        kernelScript,
+		KernelRepo:          b.context.RPMRepoPath,
+		KernelRepoDir:       kernelRepoDir,
 		ConfigureCombustion: includeCombustion,
 		RenameFilesystem:    renameFilesystem,
 		DiskSize:            string(b.context.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize),
+		ABPartitions:        b.context.ImageDefinition.OperatingSystem.RawConfiguration.ABPartitions,
+		CombustionDirName:   filepath.Base(b.context.CombustionDir),
+		ArtefactsDirName:    filepath.Base(b.context.ArtefactsDir),
 	}
 
 	data, err := template.Parse(modifyScriptName, modifyRawImageTemplate, &values)
@@ -136,6 +293,10 @@ func (b *Builder) createModifyCommand(writer io.Writer) *exec.Cmd {
 	cmd.Stdout = writer
 	cmd.Stderr = writer
 
+	if env := b.toolArgEnv(); len(env) != 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
 	return cmd
 }
 
@@ -0,0 +1,91 @@
+//go:build integration
+
+package build
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// TestModifyRawImage_ABPartitions assembles A/B root slots in a copy of the SL Micro raw image given through
+// EIB_TEST_RAW_BASE_IMAGE. It requires the libguestfs tools, as available in the EIB container.
+func TestModifyRawImage_ABPartitions(t *testing.T) {
+	baseImage := os.Getenv("EIB_TEST_RAW_BASE_IMAGE")
+	if baseImage == "" {
+		t.Skip("EIB_TEST_RAW_BASE_IMAGE is not set")
+	}
+
+	for _, tool := range []string{"guestfish", "virt-resize"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s is not available", tool)
+		}
+	}
+
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ArtefactsDir = filepath.Join(ctx.BuildDir, "artefacts")
+	require.NoError(t, os.MkdirAll(ctx.ArtefactsDir, os.ModePerm))
+
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			ImageType:       image.TypeRAW,
+			OutputImageName: "ab-slots.raw",
+		},
+		OperatingSystem: image.OperatingSystem{
+			RawConfiguration: image.RawConfiguration{
+				DiskSize:     "32G",
+				ABPartitions: true,
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	imagePath := filepath.Join(ctx.BuildDir, "ab-slots.raw")
+	require.NoError(t, fileio.CopyFile(baseImage, imagePath, fileio.NonExecutablePerms))
+
+	// Test
+	err := builder.modifyRawImage(imagePath, true, true)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, ctx.RootSlots, 2)
+	assert.Equal(t, image.RootSlot{Name: "a", Partition: 3, Label: "INSTALL", SizeMB: ctx.RootSlots[0].SizeMB}, ctx.RootSlots[0])
+	assert.Equal(t, image.RootSlot{Name: "b", Partition: 4, Label: "ROOT_B", SizeMB: ctx.RootSlots[0].SizeMB}, ctx.RootSlots[1])
+	assert.Greater(t, ctx.RootSlots[0].SizeMB, int64(0))
+
+	slotA := guestfish(t, imagePath, "vfs-uuid", "/dev/sda3")
+	slotB := guestfish(t, imagePath, "vfs-uuid", "/dev/sda4")
+	assert.NotEqual(t, slotA, slotB)
+
+	customConfig := guestfish(t, imagePath, "mount-ro", "/dev/sda3", "/", ":", "cat", "/boot/grub2/custom.cfg")
+	assert.Contains(t, customConfig, "--id eib-slot-b")
+	assert.Contains(t, customConfig, "--fs-uuid --set=root "+slotB)
+
+	// Slot B does not run combustion again, its fstab refers to its own root file system
+	slotBFiles := guestfish(t, imagePath, "mount-ro", "/dev/sda4", "/", ":", "ls", "/")
+	assert.NotContains(t, slotBFiles, filepath.Base(ctx.CombustionDir))
+
+	slotBFstab := guestfish(t, imagePath, "mount-ro", "/dev/sda4", "/", ":", "cat", "/etc/fstab")
+	assert.Contains(t, slotBFstab, slotB)
+	assert.NotContains(t, slotBFstab, slotA)
+}
+
+func guestfish(t *testing.T, imagePath string, args ...string) string {
+	cmd := exec.Command("guestfish", append([]string{"--ro", "--format=raw", "-a", imagePath, "run", ":"}, args...)...)
+
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	return strings.TrimSpace(string(output))
+}
@@ -51,7 +51,7 @@ func TestWriteModifyScript(t *testing.T) {
 			OutputImageName: "output-image",
 		},
 		OperatingSystem: image.OperatingSystem{
-			KernelArgs: []string{"alpha", "beta"},
+			KernelArgs: image.KernelArgs{Add: []string{"alpha", "beta"}},
 			RawConfiguration: image.RawConfiguration{
 				DiskSize: "64G",
 			},
@@ -123,6 +123,45 @@ func TestWriteModifyScript(t *testing.T) {
 	}
 }
 
+func TestWriteModifyScript_ABPartitions(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			OutputImageName: "output-image",
+		},
+		OperatingSystem: image.OperatingSystem{
+			RawConfiguration: image.RawConfiguration{
+				DiskSize:     "32G",
+				ABPartitions: true,
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+	outputImageFilename := builder.generateOutputImageFilename()
+
+	// Test
+	err := builder.writeModifyScript(outputImageFilename, true, true)
+	require.NoError(t, err)
+
+	// Verify
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.BuildDir, modifyScriptName))
+	require.NoError(t, err)
+	foundContents := string(foundBytes)
+
+	assert.Contains(t, foundContents, "truncate -s 32G")
+	assert.Contains(t, foundContents, "virt-resize --resize /dev/sda3=${SLOT_SIZE_MB}M --no-extra-partition")
+	assert.Contains(t, foundContents, "copy-device-to-device /dev/sda3 /dev/sda4")
+	assert.Contains(t, foundContents, "set-label /dev/sda4 ROOT_B")
+	assert.Contains(t, foundContents, "rm-rf /"+filepath.Base(ctx.CombustionDir))
+	assert.Contains(t, foundContents, "rm-rf /"+filepath.Base(ctx.ArtefactsDir))
+	assert.Contains(t, foundContents, "upload "+outputImageFilename+".custom.cfg /boot/grub2/custom.cfg")
+	assert.Contains(t, foundContents, "run : vfs-label /dev/sda3 : vfs-label /dev/sda4 : part-list /dev/sda")
+	assert.Contains(t, foundContents, `printf "[INFO] Root slot layout: b 4 %d %s\n", size[4] / 1048576, label[4]`)
+	assert.NotContains(t, foundContents, "virt-resize --expand /dev/sda3")
+}
+
 func TestCreateModifyCommand(t *testing.T) {
 	// Setup
 	builder := Builder{
@@ -140,4 +179,84 @@ func TestCreateModifyCommand(t *testing.T) {
 	assert.Equal(t, expectedPath, cmd.Path)
 	assert.Equal(t, io.Discard, cmd.Stdout)
 	assert.Equal(t, io.Discard, cmd.Stderr)
+	assert.Nil(t, cmd.Env)
+}
+
+func TestCreateModifyCommand_ToolArgs(t *testing.T) {
+	// Setup
+	builder := Builder{
+		context: &image.Context{
+			BuildDir: "build-dir",
+			ToolArgs: map[string]string{"libguestfs-memsize": "2048", "qcow2-cluster-size": "2M"},
+		},
+	}
+
+	// Test
+	cmd := builder.createModifyCommand(io.Discard)
+
+	// Verify
+	require.NotNil(t, cmd)
+	assert.Contains(t, cmd.Env, "LIBGUESTFS_MEMSIZE=2048")
+	assert.NotContains(t, cmd.Env, "cluster_size=2M")
+}
+
+func TestFindRootSlots(t *testing.T) {
+	// Setup
+	logFile := filepath.Join(t.TempDir(), rawBuildLogFile)
+	contents := "[INFO] 512 byte sector check successful.\n[INFO] Root slot size: 16381 MB\n" +
+		"[INFO] Root slot layout: a 3 16381 ROOT\n[INFO] Root slot layout: b 4 16381 ROOT_B\n"
+	require.NoError(t, os.WriteFile(logFile, []byte(contents), 0o600))
+
+	// Test
+	slots, err := findRootSlots(logFile)
+
+	// Verify
+	require.NoError(t, err)
+	expected := []image.RootSlot{
+		{Name: "a", Partition: 3, Label: "ROOT", SizeMB: 16381},
+		{Name: "b", Partition: 4, Label: "ROOT_B", SizeMB: 16381},
+	}
+	assert.Equal(t, expected, slots)
+
+	_, err = findRootSlots(filepath.Join(t.TempDir(), rawBuildLogFile))
+	assert.ErrorContains(t, err, "opening log file")
+}
+
+func TestFindRootSlots_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		contents      string
+		expectedError string
+	}{
+		`missing`: {
+			contents:      "[INFO] Root slot size: 16381 MB\n",
+			expectedError: "no root slot layout found",
+		},
+		`unlabeled`: {
+			contents: "[INFO] Root slot layout: a 3 16381 \n",
+		},
+		`truncated`: {
+			contents:      "[INFO] Root slot layout: a 3\n",
+			expectedError: "invalid root slot layout 'a 3'",
+		},
+		`invalid size`: {
+			contents:      "[INFO] Root slot layout: a 3 large INSTALL\n",
+			expectedError: "parsing size of root slot 'a'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logFile := filepath.Join(t.TempDir(), rawBuildLogFile)
+			require.NoError(t, os.WriteFile(logFile, []byte(test.contents), 0o600))
+
+			slots, err := findRootSlots(logFile)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []image.RootSlot{{Name: "a", Partition: 3, SizeMB: 16381}}, slots)
+		})
+	}
 }
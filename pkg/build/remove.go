@@ -0,0 +1,129 @@
+package build
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	removePathsScriptName = "remove-paths.sh"
+	pathNotFoundPrefix    = "[WARN] Path not found: "
+)
+
+// removedPathRegex matches the line printed into the modification log for each removed path
+var removedPathRegex = regexp.MustCompile(`^\[INFO\] Removed path: (.+) \((\d+) KiB\)$`)
+
+//go:embed templates/remove-paths.sh.tpl
+var removePathsTemplate string
+
+// writeRemovePathsScript writes the script removing the configured paths from within the image,
+// returning its location or an empty string if there are no paths to remove.
+func (b *Builder) writeRemovePathsScript() (string, error) {
+	paths := b.context.ImageDefinition.OperatingSystem.Remove
+	if len(paths) == 0 {
+		return "", nil
+	}
+
+	values := struct {
+		Paths []string
+	}{
+		Paths: paths,
+	}
+
+	data, err := template.Parse(removePathsScriptName, removePathsTemplate, &values)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s template: %w", removePathsScriptName, err)
+	}
+
+	filename := b.generateBuildDirFilename(removePathsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return "", fmt.Errorf("writing %s: %w", removePathsScriptName, err)
+	}
+
+	return filename, nil
+}
+
+type removedPath struct {
+	path   string
+	sizeKB int64
+}
+
+// reportRemovedPaths audits the paths removed from the image and the space they occupied,
+// as printed into the modification log by the removal script.
+func (b *Builder) reportRemovedPaths(logFilename string) {
+	if len(b.context.ImageDefinition.OperatingSystem.Remove) == 0 {
+		return
+	}
+
+	removed, notFound, err := findRemovedPaths(logFilename)
+	if err != nil {
+		zap.S().Warnf("Failed to determine the removed paths: %s", err)
+		return
+	}
+
+	var totalKB int64
+	for _, r := range removed {
+		log.AuditInfof("Removed %s from the image (%s).", r.path, formatKB(r.sizeKB))
+		totalKB += r.sizeKB
+	}
+
+	for _, path := range notFound {
+		log.AuditInfof("Path %s was not removed as it does not exist in the image.", path)
+	}
+
+	log.AuditInfof("Removed %d path(s), reclaiming %s.", len(removed), formatKB(totalKB))
+}
+
+func findRemovedPaths(logFilename string) (removed []removedPath, notFound []string, err error) {
+	logFile, err := os.Open(logFilename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	scanner := bufio.NewScanner(logFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if path, ok := strings.CutPrefix(line, pathNotFoundPrefix); ok {
+			notFound = append(notFound, path)
+			continue
+		}
+
+		matches := removedPathRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		size, err := strconv.ParseInt(matches[2], 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parsing size of removed path %s: %w", matches[1], err)
+		}
+
+		removed = append(removed, removedPath{path: matches[1], sizeKB: size})
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading log file: %w", err)
+	}
+
+	return removed, notFound, nil
+}
+
+func formatKB(sizeKB int64) string {
+	if sizeKB < 1024 {
+		return fmt.Sprintf("%d KiB", sizeKB)
+	}
+
+	return fmt.Sprintf("%.1f MiB", float64(sizeKB)/1024)
+}
@@ -0,0 +1,86 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWriteRemovePathsScript(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Remove: []string{"/usr/share/doc", "/usr/share/man/man 1"},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeRemovePathsScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(ctx.BuildDir, removePathsScriptName), filename)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(foundBytes), "for path in '/usr/share/doc' '/usr/share/man/man 1'; do")
+
+	// Modification script
+	require.NoError(t, builder.writeModifyScript(builder.generateOutputImageFilename(), true, true))
+
+	foundBytes, err = os.ReadFile(filepath.Join(ctx.BuildDir, modifyScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(foundBytes), "upload "+filename+" /tmp/eib-remove-paths.sh")
+}
+
+func TestWriteRemovePathsScript_NoPaths(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeRemovePathsScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Empty(t, filename)
+
+	_, err = os.Stat(filepath.Join(ctx.BuildDir, removePathsScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFindRemovedPaths(t *testing.T) {
+	// Setup
+	logFile := filepath.Join(t.TempDir(), rawBuildLogFile)
+	contents := "[INFO] 512 byte sector check successful.\n" +
+		"[INFO] Removed path: /usr/share/doc (20480 KiB)\n" +
+		"[WARN] Path not found: /usr/share/info\n" +
+		"[INFO] Removed path: /usr/share/man/man 1 (512 KiB)\n"
+	require.NoError(t, os.WriteFile(logFile, []byte(contents), 0o600))
+
+	// Test
+	removed, notFound, err := findRemovedPaths(logFile)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []removedPath{
+		{path: "/usr/share/doc", sizeKB: 20480},
+		{path: "/usr/share/man/man 1", sizeKB: 512},
+	}, removed)
+	assert.Equal(t, []string{"/usr/share/info"}, notFound)
+}
+
+func TestFormatKB(t *testing.T) {
+	assert.Equal(t, "512 KiB", formatKB(512))
+	assert.Equal(t, "20.0 MiB", formatKB(20480))
+	assert.Equal(t, "1.5 MiB", formatKB(1536))
+}
@@ -0,0 +1,87 @@
+package build
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	rootMountOptionsScriptName = "root-mount-options.sh"
+	rootMountOptionsPrefix     = "[INFO] Root mount options: "
+)
+
+//go:embed templates/root-mount-options.sh.tpl
+var rootMountOptionsTemplate string
+
+// writeRootMountOptionsScript writes the script merging the configured mount options into the root file system
+// entry of the image's fstab, returning its location or an empty string if the options are not customized.
+func (b *Builder) writeRootMountOptionsScript() (string, error) {
+	options := b.context.ImageDefinition.Image.RootFilesystem.MountOptions
+	if len(options) == 0 {
+		return "", nil
+	}
+
+	values := struct {
+		Options string
+	}{
+		Options: strings.Join(options, ","),
+	}
+
+	data, err := template.Parse(rootMountOptionsScriptName, rootMountOptionsTemplate, &values)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s template: %w", rootMountOptionsScriptName, err)
+	}
+
+	filename := b.generateBuildDirFilename(rootMountOptionsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return "", fmt.Errorf("writing %s: %w", rootMountOptionsScriptName, err)
+	}
+
+	return filename, nil
+}
+
+// reportRootMountOptions audits the resulting mount options of the root file system, as printed into
+// the modification log by the mount options script, and records them for the build report.
+func (b *Builder) reportRootMountOptions(logFilename string) {
+	if len(b.context.ImageDefinition.Image.RootFilesystem.MountOptions) == 0 {
+		return
+	}
+
+	options, err := findRootMountOptions(logFilename)
+	if err != nil {
+		zap.S().Warnf("Failed to determine the resulting root mount options: %s", err)
+		return
+	}
+
+	b.context.RootMountOptions = options
+	log.AuditInfof("The root file system is mounted with: %s", options)
+}
+
+func findRootMountOptions(logFilename string) (string, error) {
+	logFile, err := os.Open(logFilename)
+	if err != nil {
+		return "", fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	scanner := bufio.NewScanner(logFile)
+	for scanner.Scan() {
+		if options, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), rootMountOptionsPrefix); ok {
+			return options, nil
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading log file: %w", err)
+	}
+
+	return "", fmt.Errorf("no root mount options found in %s", logFilename)
+}
@@ -0,0 +1,88 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWriteRootMountOptionsScript(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			RootFilesystem: image.RootFilesystem{
+				MountOptions: []string{"noatime", "compress=zstd:1"},
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeRootMountOptionsScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(ctx.BuildDir, rootMountOptionsScriptName), filename)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(foundBytes), "awk -v options='noatime,compress=zstd:1' '")
+
+	// Modification script
+	require.NoError(t, builder.writeModifyScript(builder.generateOutputImageFilename(), true, true))
+
+	foundBytes, err = os.ReadFile(filepath.Join(ctx.BuildDir, modifyScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(foundBytes), "upload "+filename+" /tmp/eib-root-mount-options.sh")
+}
+
+func TestWriteRootMountOptionsScript_NoOptions(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeRootMountOptionsScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Empty(t, filename)
+
+	_, err = os.Stat(filepath.Join(ctx.BuildDir, rootMountOptionsScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFindRootMountOptions(t *testing.T) {
+	// Setup
+	logFile := filepath.Join(t.TempDir(), rawBuildLogFile)
+	contents := "[INFO] 512 byte sector check successful.\n" +
+		"[INFO] Root mount options: ro,noatime,compress=zstd:1\n"
+	require.NoError(t, os.WriteFile(logFile, []byte(contents), 0o600))
+
+	// Test
+	options, err := findRootMountOptions(logFile)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, "ro,noatime,compress=zstd:1", options)
+}
+
+func TestFindRootMountOptions_Missing(t *testing.T) {
+	// Setup
+	logFile := filepath.Join(t.TempDir(), rawBuildLogFile)
+	require.NoError(t, os.WriteFile(logFile, []byte("[INFO] 512 byte sector check successful.\n"), 0o600))
+
+	// Test
+	_, err := findRootMountOptions(logFile)
+
+	// Verify
+	require.ErrorContains(t, err, "no root mount options found")
+}
@@ -0,0 +1,116 @@
+package build
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	secureBootScriptName   = "secure-boot.sh"
+	signedKernelPrefix     = "[INFO] Signed kernel: "
+	signedModulePrefix     = "[INFO] Signed module: "
+	lockdownKernelArgument = "lockdown"
+)
+
+//go:embed templates/secure-boot.sh.tpl
+var secureBootTemplate string
+
+// writeSecureBootScript writes the script signing the boot artefacts of the image, returning its location
+// or an empty string if nothing is signed. The script runs on the build host, where the signing key is.
+func (b *Builder) writeSecureBootScript(imageFilename string) (string, error) {
+	signing := b.context.ImageDefinition.OperatingSystem.SecureBoot.Signing
+	if !signing.Kernel && !signing.Modules {
+		return "", nil
+	}
+
+	secureBootDir := filepath.Join(b.context.ImageConfigDir, combustion.SecureBootDir)
+
+	values := struct {
+		ImagePath   string
+		Key         string
+		Certificate string
+		Kernel      bool
+		Modules     bool
+		ToolArgEnv  []string
+	}{
+		ImagePath:   imageFilename,
+		Key:         filepath.Join(secureBootDir, signing.Key),
+		Certificate: filepath.Join(secureBootDir, signing.Certificate),
+		Kernel:      signing.Kernel,
+		Modules:     signing.Modules,
+		ToolArgEnv:  b.toolArgEnv(),
+	}
+
+	data, err := template.Parse(secureBootScriptName, secureBootTemplate, &values)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s template: %w", secureBootScriptName, err)
+	}
+
+	filename := b.generateBuildDirFilename(secureBootScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return "", fmt.Errorf("writing %s: %w", secureBootScriptName, err)
+	}
+
+	return filename, nil
+}
+
+// reportSignedArtefacts audits the kernels and modules signed for Secure Boot, as printed into the modification
+// log by the signing script, and records them for the build report.
+func (b *Builder) reportSignedArtefacts(logFilename string) {
+	signing := b.context.ImageDefinition.OperatingSystem.SecureBoot.Signing
+	if !signing.Kernel && !signing.Modules {
+		return
+	}
+
+	kernels, modules, err := findSignedArtefacts(logFilename)
+	if err != nil {
+		zap.S().Warnf("Failed to determine the signed boot artefacts: %s", err)
+		return
+	}
+
+	b.context.SignedKernels = kernels
+	b.context.SignedModules = modules
+
+	if signing.Kernel {
+		log.AuditInfof("Signed %d kernel image(s) for Secure Boot: %s", len(kernels), strings.Join(kernels, ", "))
+	}
+
+	if signing.Modules {
+		log.AuditInfof("Signed %d out-of-tree kernel module(s) for Secure Boot.", len(modules))
+	}
+}
+
+func findSignedArtefacts(logFilename string) (kernels, modules []string, err error) {
+	logFile, err := os.Open(logFilename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	scanner := bufio.NewScanner(logFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if kernel, ok := strings.CutPrefix(line, signedKernelPrefix); ok {
+			kernels = append(kernels, kernel)
+		} else if module, ok := strings.CutPrefix(line, signedModulePrefix); ok {
+			modules = append(modules, module)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading log file: %w", err)
+	}
+
+	return kernels, modules, nil
+}
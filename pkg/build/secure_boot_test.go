@@ -0,0 +1,167 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWriteSecureBootScript(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			OutputImageName: "output-image",
+		},
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				Signing: image.SecureBootSigning{
+					Key:         "signing.key",
+					Certificate: "signing.crt",
+					Kernel:      true,
+				},
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+	outputImageFilename := builder.generateOutputImageFilename()
+
+	// Test
+	filename, err := builder.writeSecureBootScript(outputImageFilename)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(ctx.BuildDir, secureBootScriptName), filename)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	foundContents := string(foundBytes)
+
+	keyPath := filepath.Join(ctx.ImageConfigDir, "secure-boot", "signing.key")
+	certPath := filepath.Join(ctx.ImageConfigDir, "secure-boot", "signing.crt")
+
+	assert.Contains(t, foundContents, "-a '"+outputImageFilename+"' -i")
+	assert.Contains(t, foundContents, "glob-expand '/usr/share/efi/*/shim.efi'")
+	assert.Contains(t, foundContents, "sbsign --key '"+keyPath+"' --cert '"+certPath+"'")
+	assert.NotContains(t, foundContents, "MODULES=")
+	assert.NotContains(t, foundContents, "export ")
+
+	// Modification script
+	require.NoError(t, builder.writeModifyScript(outputImageFilename, true, true))
+
+	foundBytes, err = os.ReadFile(filepath.Join(ctx.BuildDir, modifyScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(foundBytes), filename+" $BLOCKSIZE")
+}
+
+func TestWriteSecureBootScript_Modules(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				Signing: image.SecureBootSigning{
+					Key:         "signing.key",
+					Certificate: "signing.crt",
+					Modules:     true,
+				},
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeSecureBootScript("image.raw")
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	foundContents := string(foundBytes)
+
+	assert.Contains(t, foundContents, "-path '*/updates/*' -o -path '*/extra/*'")
+	assert.Contains(t, foundContents, "openssl cms -sign -binary -noattr -nocerts -md sha256 -outform DER")
+	assert.NotContains(t, foundContents, "sbsign")
+}
+
+func TestWriteSecureBootScript_ToolArgs(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				Signing: image.SecureBootSigning{
+					Key:         "signing.key",
+					Certificate: "signing.crt",
+					Kernel:      true,
+				},
+			},
+		},
+	}
+	ctx.ToolArgs = map[string]string{"libguestfs-memsize": "2048", "libguestfs-backend": "direct"}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeSecureBootScript("image.raw")
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(foundBytes), "BLOCKSIZE=$1\n\nexport LIBGUESTFS_BACKEND=direct\nexport LIBGUESTFS_MEMSIZE=2048\n")
+}
+
+func TestWriteSecureBootScript_NotSigning(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Only enrolling certificates is handled by combustion
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				MOKCertificates: []string{"edge.der"},
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeSecureBootScript("image.raw")
+
+	// Verify
+	require.NoError(t, err)
+	assert.Empty(t, filename)
+
+	_, err = os.Stat(filepath.Join(ctx.BuildDir, secureBootScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFindSignedArtefacts(t *testing.T) {
+	logFilename := filepath.Join(t.TempDir(), "raw-build.log")
+	contents := `[INFO] 512 byte sector check successful.
+[INFO] Signed kernel: /boot/vmlinuz-6.4.0-17-default
+[INFO] Module already signed: /usr/lib/modules/6.4.0-17-default/updates/vendor.ko.zst
+[INFO] Signed module: /usr/lib/modules/6.4.0-17-default/updates/driver.ko.zst
+[INFO] Signed module: /usr/lib/modules/6.4.0-17-default/extra/sensor.ko
+`
+	require.NoError(t, os.WriteFile(logFilename, []byte(contents), 0o600))
+
+	kernels, modules, err := findSignedArtefacts(logFilename)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/boot/vmlinuz-6.4.0-17-default"}, kernels)
+	assert.Equal(t, []string{
+		"/usr/lib/modules/6.4.0-17-default/updates/driver.ko.zst",
+		"/usr/lib/modules/6.4.0-17-default/extra/sensor.ko",
+	}, modules)
+}
@@ -0,0 +1,244 @@
+package build
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	seedIsoScriptName = "seed-isos.sh"
+	seedIsoLogFile    = "seed-isos.log"
+	seedIsoDirName    = "seed-isos"
+	seedIsoSuffix     = "-seed.iso"
+	// seedIsoVolumeID is the label cloud-init looks for when searching for a NoCloud seed
+	seedIsoVolumeID = "CIDATA"
+
+	// CloudInitPackage is installed along with the other packages when seed ISOs are configured, as the seeds
+	// are read by cloud-init at boot.
+	CloudInitPackage = "cloud-init"
+)
+
+//go:embed templates/seed-isos.sh.tpl
+var seedIsoTemplate string
+
+type seedIso struct {
+	SeedDir        string
+	OutputFilename string
+}
+
+// seedMetaData, seedUserData and seedNetworkConfig are the cloud-init configuration files of a NoCloud seed.
+type seedMetaData struct {
+	InstanceID    string `yaml:"instance-id"`
+	LocalHostname string `yaml:"local-hostname"`
+}
+
+type seedUserData struct {
+	Hostname    string     `yaml:"hostname"`
+	DisableRoot bool       `yaml:"disable_root"`
+	Users       []seedUser `yaml:"users,omitempty"`
+}
+
+type seedUser struct {
+	Name              string   `yaml:"name"`
+	SSHAuthorizedKeys []string `yaml:"ssh_authorized_keys"`
+}
+
+type seedNetworkConfig struct {
+	Version   int                     `yaml:"version"`
+	Ethernets map[string]seedEthernet `yaml:"ethernets"`
+}
+
+type seedEthernet struct {
+	Match       *seedMatch       `yaml:"match,omitempty"`
+	SetName     string           `yaml:"set-name,omitempty"`
+	DHCP4       bool             `yaml:"dhcp4,omitempty"`
+	Addresses   []string         `yaml:"addresses,omitempty"`
+	Routes      []seedRoute      `yaml:"routes,omitempty"`
+	Nameservers *seedNameservers `yaml:"nameservers,omitempty"`
+}
+
+type seedMatch struct {
+	MACAddress string `yaml:"macaddress"`
+}
+
+type seedRoute struct {
+	To  string `yaml:"to"`
+	Via string `yaml:"via"`
+}
+
+type seedNameservers struct {
+	Addresses []string `yaml:"addresses"`
+}
+
+// BuildSeedISOs builds the cloud-init seed ISO of each node listed in the definition, which are named after
+// the output image and the hostname of the node.
+func (b *Builder) BuildSeedISOs() error {
+	seeds := b.context.ImageDefinition.Image.SeedISOs
+	if len(seeds) == 0 {
+		return nil
+	}
+
+	log.Audit("Building cloud-init seed ISOs...")
+
+	isos, err := b.writeSeedFiles(seeds)
+	if err != nil {
+		log.Audit("Error building cloud-init seed ISOs.")
+		return fmt.Errorf("writing seed files: %w", err)
+	}
+
+	for _, iso := range isos {
+		if err = os.Remove(iso.OutputFilename); err != nil && !os.IsNotExist(err) {
+			log.Audit("Error building cloud-init seed ISOs.")
+			return fmt.Errorf("deleting existing seed ISO: %w", err)
+		}
+	}
+
+	if err = b.writeSeedIsoScript(isos); err != nil {
+		log.Audit("Error building cloud-init seed ISOs.")
+		return fmt.Errorf("creating the seed ISO script: %w", err)
+	}
+
+	cmd, isoLog, err := b.createIsoCommand(seedIsoLogFile, seedIsoScriptName)
+	if err != nil {
+		log.Audit("Error building cloud-init seed ISOs.")
+		return fmt.Errorf("preparing to build the seed ISOs: %w", err)
+	}
+	defer func() {
+		if err = isoLog.Close(); err != nil {
+			zap.S().Warnf("failed to close seed ISO log file properly: %s", err)
+		}
+	}()
+
+	if err = cmd.Run(); err != nil {
+		log.Audit("Error building cloud-init seed ISOs.")
+		return fmt.Errorf("building the seed ISOs: %w", err)
+	}
+
+	for i, seed := range seeds {
+		b.context.SeedISOs = append(b.context.SeedISOs, image.SeedISOFile{Hostname: seed.Hostname, Path: isos[i].OutputFilename})
+	}
+
+	log.AuditInfof("Built %d cloud-init seed ISO(s), available under '%s'.", len(isos), b.context.ImageConfigDir)
+	return nil
+}
+
+// writeSeedFiles writes the meta-data, user-data and, for static configurations, network-config files
+// of each seed into a directory of its own under the build directory.
+func (b *Builder) writeSeedFiles(seeds []image.SeedISO) ([]seedIso, error) {
+	var isos []seedIso
+
+	for i := range seeds {
+		seed := &seeds[i]
+
+		seedDir := filepath.Join(b.context.BuildDir, seedIsoDirName, seed.Hostname)
+		if err := os.MkdirAll(seedDir, os.ModePerm); err != nil {
+			return nil, fmt.Errorf("creating seed directory %s: %w", seedDir, err)
+		}
+
+		files := map[string]any{
+			"meta-data": seedMetaData{InstanceID: seed.Hostname, LocalHostname: seed.Hostname},
+			"user-data": newSeedUserData(seed),
+		}
+
+		if seed.Network.Interface != "" {
+			files["network-config"] = newSeedNetworkConfig(&seed.Network)
+		}
+
+		for name, contents := range files {
+			data, err := yaml.Marshal(contents)
+			if err != nil {
+				return nil, fmt.Errorf("marshalling %s of seed '%s': %w", name, seed.Hostname, err)
+			}
+
+			// cloud-init only parses the user data as cloud-config when it starts with this header
+			if name == "user-data" {
+				data = append([]byte("#cloud-config\n"), data...)
+			}
+
+			filename := filepath.Join(seedDir, name)
+			if err = os.WriteFile(filename, data, fileio.NonExecutablePerms); err != nil {
+				return nil, fmt.Errorf("writing file %s: %w", filename, err)
+			}
+		}
+
+		isos = append(isos, seedIso{SeedDir: seedDir, OutputFilename: b.generateSeedIsoFilename(seed.Hostname)})
+	}
+
+	return isos, nil
+}
+
+func newSeedUserData(seed *image.SeedISO) seedUserData {
+	// Keys injected for root are otherwise prefixed with a command refusing the login
+	userData := seedUserData{Hostname: seed.Hostname, DisableRoot: false}
+
+	if len(seed.SSHKeys) != 0 {
+		userData.Users = []seedUser{{Name: "root", SSHAuthorizedKeys: seed.SSHKeys}}
+	}
+
+	return userData
+}
+
+func newSeedNetworkConfig(network *image.SeedNetwork) seedNetworkConfig {
+	ethernet := seedEthernet{
+		DHCP4:     len(network.Addresses) == 0,
+		Addresses: network.Addresses,
+	}
+
+	if network.MACAddress != "" {
+		ethernet.Match = &seedMatch{MACAddress: strings.ToLower(network.MACAddress)}
+		ethernet.SetName = network.Interface
+	}
+
+	if network.Gateway != "" {
+		ethernet.Routes = []seedRoute{{To: "default", Via: network.Gateway}}
+	}
+
+	if len(network.DNS) != 0 {
+		ethernet.Nameservers = &seedNameservers{Addresses: network.DNS}
+	}
+
+	return seedNetworkConfig{
+		Version:   2,
+		Ethernets: map[string]seedEthernet{network.Interface: ethernet},
+	}
+}
+
+func (b *Builder) writeSeedIsoScript(isos []seedIso) error {
+	arguments := struct {
+		VolumeID string
+		ISOs     []seedIso
+	}{
+		VolumeID: seedIsoVolumeID,
+		ISOs:     isos,
+	}
+
+	contents, err := template.Parse(seedIsoScriptName, seedIsoTemplate, arguments)
+	if err != nil {
+		return fmt.Errorf("applying the seed ISO script template: %w", err)
+	}
+
+	scriptName := b.generateBuildDirFilename(seedIsoScriptName)
+	if err = os.WriteFile(scriptName, []byte(contents), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing seed ISO script %s: %w", seedIsoScriptName, err)
+	}
+
+	return nil
+}
+
+// generateSeedIsoFilename names the seed ISO of a node after the output image and the hostname of the node.
+func (b *Builder) generateSeedIsoFilename(hostname string) string {
+	name := b.context.ImageDefinition.Image.OutputImageName
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+
+	return filepath.Join(b.context.ImageConfigDir, fmt.Sprintf("%s-%s%s", name, hostname, seedIsoSuffix))
+}
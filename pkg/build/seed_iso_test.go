@@ -0,0 +1,125 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestBuildSeedISOs_NotConfigured(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	builder := Builder{context: ctx}
+
+	require.NoError(t, builder.BuildSeedISOs())
+	assert.NoFileExists(t, filepath.Join(ctx.BuildDir, seedIsoScriptName))
+	assert.Nil(t, ctx.SeedISOs)
+}
+
+func TestWriteSeedFiles(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Image.OutputImageName = "edge.raw"
+	builder := Builder{context: ctx}
+
+	seeds := []image.SeedISO{
+		{
+			Hostname: "node1",
+			SSHKeys:  []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFakeKeyForTesting admin@example.com"},
+			Network: image.SeedNetwork{
+				Interface:  "eth0",
+				MACAddress: "52:54:00:AA:BB:CC",
+				Addresses:  []string{"192.168.1.10/24"},
+				Gateway:    "192.168.1.1",
+				DNS:        []string{"192.168.1.1"},
+			},
+		},
+		{Hostname: "node2"},
+	}
+
+	isos, err := builder.writeSeedFiles(seeds)
+	require.NoError(t, err)
+
+	node1Dir := filepath.Join(ctx.BuildDir, seedIsoDirName, "node1")
+	node2Dir := filepath.Join(ctx.BuildDir, seedIsoDirName, "node2")
+	assert.Equal(t, []seedIso{
+		{SeedDir: node1Dir, OutputFilename: filepath.Join(ctx.ImageConfigDir, "edge-node1-seed.iso")},
+		{SeedDir: node2Dir, OutputFilename: filepath.Join(ctx.ImageConfigDir, "edge-node2-seed.iso")},
+	}, isos)
+
+	metaData, err := os.ReadFile(filepath.Join(node1Dir, "meta-data"))
+	require.NoError(t, err)
+	assert.Equal(t, "instance-id: node1\nlocal-hostname: node1\n", string(metaData))
+
+	userData, err := os.ReadFile(filepath.Join(node1Dir, "user-data"))
+	require.NoError(t, err)
+	assert.Equal(t, "#cloud-config\n"+
+		"hostname: node1\n"+
+		"disable_root: false\n"+
+		"users:\n"+
+		"    - name: root\n"+
+		"      ssh_authorized_keys:\n"+
+		"        - ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFakeKeyForTesting admin@example.com\n", string(userData))
+
+	info, err := os.Stat(filepath.Join(node1Dir, "network-config"))
+	require.NoError(t, err)
+	assert.Equal(t, fileio.NonExecutablePerms, info.Mode())
+
+	networkConfig, err := os.ReadFile(filepath.Join(node1Dir, "network-config"))
+	require.NoError(t, err)
+	assert.Equal(t, "version: 2\n"+
+		"ethernets:\n"+
+		"    eth0:\n"+
+		"        match:\n"+
+		"            macaddress: 52:54:00:aa:bb:cc\n"+
+		"        set-name: eth0\n"+
+		"        addresses:\n"+
+		"            - 192.168.1.10/24\n"+
+		"        routes:\n"+
+		"            - to: default\n"+
+		"              via: 192.168.1.1\n"+
+		"        nameservers:\n"+
+		"            addresses:\n"+
+		"                - 192.168.1.1\n", string(networkConfig))
+
+	// Nodes without a static configuration are left to DHCP
+	userData, err = os.ReadFile(filepath.Join(node2Dir, "user-data"))
+	require.NoError(t, err)
+	assert.Equal(t, "#cloud-config\nhostname: node2\ndisable_root: false\n", string(userData))
+	assert.NoFileExists(t, filepath.Join(node2Dir, "network-config"))
+}
+
+func TestWriteSeedIsoScript(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	builder := Builder{context: ctx}
+
+	isos := []seedIso{
+		{SeedDir: "/build/seed-isos/node1", OutputFilename: "/eib/edge-node1-seed.iso"},
+		{SeedDir: "/build/seed-isos/node2", OutputFilename: "/eib/edge-node2-seed.iso"},
+	}
+	require.NoError(t, builder.writeSeedIsoScript(isos))
+
+	scriptPath := filepath.Join(ctx.BuildDir, seedIsoScriptName)
+
+	info, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+	b, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "xorriso -outdev '/eib/edge-node1-seed.iso' \\\n        -volid CIDATA")
+	assert.Contains(t, contents, "-map '/build/seed-isos/node1' / \\")
+	assert.Contains(t, contents, "xorriso -outdev '/eib/edge-node2-seed.iso'")
+	assert.Contains(t, contents, "-map '/build/seed-isos/node2' / \\")
+}
@@ -0,0 +1,120 @@
+package build
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	stripDocsScriptName   = "strip-docs.sh"
+	docsNotFoundPrefix    = "[WARN] Excluded documentation not found: "
+	strippedDocsLogPrefix = "[INFO] Stripped documentation: "
+)
+
+// strippedDocsRegex matches the line printed into the modification log with the space reclaimed by the stripping
+var strippedDocsRegex = regexp.MustCompile(`^\[INFO\] Stripped documentation: (\d+) KiB$`)
+
+//go:embed templates/strip-docs.sh.tpl
+var stripDocsTemplate string
+
+// writeStripDocsScript writes the script stripping the documentation from within the image,
+// returning its location or an empty string if the documentation is kept.
+func (b *Builder) writeStripDocsScript() (string, error) {
+	stripDocs := b.context.ImageDefinition.OperatingSystem.StripDocs
+	if !stripDocs.Enabled {
+		return "", nil
+	}
+
+	values := struct {
+		Dirs    []string
+		Exclude []string
+	}{
+		Dirs:    image.DocumentationDirs,
+		Exclude: stripDocs.Exclude,
+	}
+
+	data, err := template.Parse(stripDocsScriptName, stripDocsTemplate, &values)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s template: %w", stripDocsScriptName, err)
+	}
+
+	filename := b.generateBuildDirFilename(stripDocsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return "", fmt.Errorf("writing %s: %w", stripDocsScriptName, err)
+	}
+
+	return filename, nil
+}
+
+// reportStrippedDocs audits the space reclaimed by stripping the documentation, as printed into the modification
+// log by the stripping script, and records it for the build report.
+func (b *Builder) reportStrippedDocs(logFilename string) {
+	if !b.context.ImageDefinition.OperatingSystem.StripDocs.Enabled {
+		return
+	}
+
+	reclaimedKB, notFound, err := findStrippedDocs(logFilename)
+	if err != nil {
+		zap.S().Warnf("Failed to determine the space reclaimed by stripping the documentation: %s", err)
+		return
+	}
+
+	b.context.StrippedDocsKB = reclaimedKB
+
+	for _, path := range notFound {
+		log.AuditInfof("Excluded documentation %s does not exist in the image.", path)
+	}
+
+	log.AuditInfof("Stripped the documentation from the image, reclaiming %s.", formatKB(reclaimedKB))
+}
+
+func findStrippedDocs(logFilename string) (reclaimedKB int64, notFound []string, err error) {
+	logFile, err := os.Open(logFilename)
+	if err != nil {
+		return 0, nil, fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	found := false
+
+	scanner := bufio.NewScanner(logFile)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if path, ok := strings.CutPrefix(line, docsNotFoundPrefix); ok {
+			notFound = append(notFound, path)
+			continue
+		}
+
+		matches := strippedDocsRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		if reclaimedKB, err = strconv.ParseInt(matches[1], 10, 64); err != nil {
+			return 0, nil, fmt.Errorf("parsing reclaimed space: %w", err)
+		}
+		found = true
+	}
+
+	if err = scanner.Err(); err != nil {
+		return 0, nil, fmt.Errorf("reading log file: %w", err)
+	}
+
+	if !found {
+		return 0, nil, fmt.Errorf("no line starting with '%s' found in %s", strippedDocsLogPrefix, logFilename)
+	}
+
+	return reclaimedKB, notFound, nil
+}
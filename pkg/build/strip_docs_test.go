@@ -0,0 +1,120 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestWriteStripDocsScript(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			StripDocs: image.StripDocs{
+				Enabled: true,
+				Exclude: []string{"/usr/share/doc/packages/openssl", "/usr/share/man/man8"},
+			},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeStripDocsScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(ctx.BuildDir, stripDocsScriptName), filename)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	found := string(foundBytes)
+	assert.Contains(t, found, "du -skc '/usr/share/doc' '/usr/share/man' '/usr/share/info'")
+	assert.Contains(t, found, "for path in '/usr/share/doc/packages/openssl' '/usr/share/man/man8'; do")
+	assert.Contains(t, found, "find \"$dir\" ! -type d ! -path '/usr/share/doc/packages/openssl' ! -path '/usr/share/doc/packages/openssl/*'"+
+		" ! -path '/usr/share/man/man8' ! -path '/usr/share/man/man8/*' -delete")
+
+	// Modification script
+	require.NoError(t, builder.writeModifyScript(builder.generateOutputImageFilename(), true, true))
+
+	foundBytes, err = os.ReadFile(filepath.Join(ctx.BuildDir, modifyScriptName))
+	require.NoError(t, err)
+	assert.Contains(t, string(foundBytes), "upload "+filename+" /tmp/eib-strip-docs.sh")
+}
+
+func TestWriteStripDocsScript_NoExclude(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			StripDocs: image.StripDocs{Enabled: true},
+		},
+	}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeStripDocsScript()
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	found := string(foundBytes)
+	assert.NotContains(t, found, "for path in")
+	assert.Contains(t, found, "find \"$dir\" ! -type d -delete")
+	assert.Contains(t, found, "find \"$dir\" -mindepth 1 -depth -type d -empty -delete")
+}
+
+func TestWriteStripDocsScript_Disabled(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	ctx.ImageDefinition = &image.Definition{}
+	builder := Builder{context: ctx}
+
+	// Test
+	filename, err := builder.writeStripDocsScript()
+
+	// Verify
+	require.NoError(t, err)
+	assert.Empty(t, filename)
+
+	_, err = os.Stat(filepath.Join(ctx.BuildDir, stripDocsScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestFindStrippedDocs(t *testing.T) {
+	// Setup
+	logFile := filepath.Join(t.TempDir(), rawBuildLogFile)
+	contents := "[INFO] 512 byte sector check successful.\n" +
+		"[WARN] Excluded documentation not found: /usr/share/man/man8\n" +
+		"[INFO] Stripped documentation: 48213 KiB\n"
+	require.NoError(t, os.WriteFile(logFile, []byte(contents), 0o600))
+
+	// Test
+	reclaimedKB, notFound, err := findStrippedDocs(logFile)
+
+	// Verify
+	require.NoError(t, err)
+	assert.EqualValues(t, 48213, reclaimedKB)
+	assert.Equal(t, []string{"/usr/share/man/man8"}, notFound)
+}
+
+func TestFindStrippedDocs_NotFound(t *testing.T) {
+	// Setup
+	logFile := filepath.Join(t.TempDir(), rawBuildLogFile)
+	require.NoError(t, os.WriteFile(logFile, []byte("[INFO] 512 byte sector check successful.\n"), 0o600))
+
+	// Test
+	_, _, err := findStrippedDocs(logFile)
+
+	// Verify
+	require.ErrorContains(t, err, "no line starting with '[INFO] Stripped documentation: ' found in")
+}
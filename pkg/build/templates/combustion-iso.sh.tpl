@@ -0,0 +1,20 @@
+#!/bin/bash
+set -euo pipefail
+
+#  Template Fields
+#  OutputImageFilename - Full path and name of the combustion ISO to create
+#  CombustionDir - Full path to the combustion directory to include in the ISO
+#  ArtefactsDir - Full path to the artefacts directory to include in the ISO
+#  VolumeID - Label of the ISO, which combustion looks for when searching for its configuration
+
+OUTPUT_IMAGE={{.OutputImageFilename}}
+COMBUSTION_DIR={{.CombustionDir}}
+ARTEFACTS_DIR={{.ArtefactsDir}}
+
+# The combustion script mounts the volume of the same label to access the artefacts
+xorriso -outdev ${OUTPUT_IMAGE} \
+        -volid {{.VolumeID}} \
+        -joliet on \
+        -map ${COMBUSTION_DIR} /combustion \
+        -map ${ARTEFACTS_DIR} /artefacts \
+        -commit
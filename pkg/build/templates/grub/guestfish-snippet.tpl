@@ -2,12 +2,25 @@
 # - Without this, the values wouldn't be used until after the first time the
 #   grub configuration is regenerated
 download /boot/grub2/grub.cfg /tmp/grub.cfg
+{{- range .RemovePatterns }}
+! sed -i -E '/ignition.platform/ { :a; s/([[:space:]]){{ . }}([[:space:]]+|$)/\1/; ta }' /tmp/grub.cfg
+{{- end }}
+{{- if .KernelArgs }}
 ! sed -i '/ignition.platform/ s/$/ {{.KernelArgs}} /' /tmp/grub.cfg
+{{- end }}
 upload /tmp/grub.cfg /boot/grub2/grub.cfg
 
 # Configure GRUB defaults
 # - Without this, when `transactional-update grub.cfg` is run it will overwrite
 #   settings used in the above change
 download /etc/default/grub /tmp/grub
+{{- range .RemovePatterns }}
+! sed -i -E '/^GRUB_CMDLINE_LINUX_DEFAULT="/ { :a; s/([[:space:]]|"){{ . }}([[:space:]]+|("))/\1\4/; ta }' /tmp/grub
+{{- end }}
+{{- if .KernelArgs }}
 ! sed -i '/^GRUB_CMDLINE_LINUX_DEFAULT="/ s/"$/ {{.KernelArgs}} "/' /tmp/grub
-upload /tmp/grub /etc/default/grub
\ No newline at end of file
+{{- end }}
+upload /tmp/grub /etc/default/grub
+
+# Report the resulting command line, which is picked up from the build log
+! grep '^GRUB_CMDLINE_LINUX_DEFAULT=' /tmp/grub
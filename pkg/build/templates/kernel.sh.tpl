@@ -0,0 +1,33 @@
+#!/bin/sh
+set -eu
+
+{{/* Template Fields */ -}}
+{{/* RepoDir - repository of the resolved packages, as uploaded into the image */ -}}
+{{/* Name    - name of the pinned kernel package */ -}}
+{{/* Version - version and release of the pinned kernel package */ -}}
+
+# Runs inside the image; the embedded kernel is picked up from the build log
+zypper --non-interactive addrepo 'dir:{{ .RepoDir }}' eib-kernel
+zypper --non-interactive --no-gpg-checks refresh eib-kernel
+
+# The other repositories are not refreshed, as the image is assembled offline
+zypper --non-interactive --no-gpg-checks --no-refresh install -r eib-kernel --oldpackage --no-recommends \
+  --auto-agree-with-licenses '{{ .Name }}={{ .Version }}'
+zypper --non-interactive removerepo eib-kernel
+
+# Kernels are multiversion packages installed alongside each other, so the kernels of the base image are
+# removed for the pinned one to be booted. Packages built for these kernels prevent their removal.
+for installed in $(rpm -q --whatprovides kernel-uname-r --qf '%{NAME}-%{VERSION}-%{RELEASE}\n' | sort -u); do
+  if [ "$installed" != '{{ .Name }}-{{ .Version }}' ]; then
+    rpm -e "$installed"
+    echo "[INFO] Removed kernel: $installed"
+  fi
+done
+
+KERNEL_RELEASE=$(rpm -q --provides '{{ .Name }}-{{ .Version }}' | awk '$1 == "kernel-uname-r" {print $3}')
+
+# The initrd is built without the host-only mode, as the image is not assembled on the hardware it boots on
+dracut --force --no-hostonly "/boot/initrd-$KERNEL_RELEASE" "$KERNEL_RELEASE"
+grub2-mkconfig -o /boot/grub2/grub.cfg
+
+echo "[INFO] Embedded kernel: {{ .Name }}-{{ .Version }} ($KERNEL_RELEASE)"
@@ -7,9 +7,20 @@ set -euo pipefail
 #  ArtefactsDir        - Full path to the artefacts directory
 #  ConfigureGRUB       - Contains the guestfish command lines to run to manipulate GRUB configuration.
 #                        If there is no specific GRUB configuration to do, this will be an empty string.
+#  RemovePathsScript   - Full path to the script removing paths from within the image, empty if there are none
+#  StripDocsScript     - Full path to the script stripping the documentation from within the image, empty to keep it
+#  RootMountOptions    - Full path to the script customizing the root mount options, empty if these are not customized
+#  SecureBootScript    - Full path to the script signing the boot artefacts on the host, empty if nothing is signed
+#  KernelScript        - Full path to the script installing the pinned kernel, empty to keep the base image kernel
+#  KernelRepo          - Full path to the repository of the resolved packages the pinned kernel is installed from
+#  KernelRepoDir       - Location the repository of the resolved packages is uploaded to within the image
 #  ConfigureCombustion - If true, the combustion and artefacts directories will be included in the raw image
 #  RenameFilesystem    - If true, the filesystem of the image will be renamed (see below for information
 #                        on why this is needed)
+#  DiskSize            - Size the raw disk image is expanded to, empty to keep the size of the base image
+#  ABPartitions        - If true, a copy of the root partition is added as a second root slot (partition 4)
+#  CombustionDirName   - Name of the combustion directory copied into the image
+#  ArtefactsDirName    - Name of the artefacts directory copied into the image
 #
 # Guestfish Command Documentation: https://libguestfs.org/guestfish.1.html
 
@@ -23,7 +34,18 @@ fi
 # Resize the raw disk image to accommodate the users desired raw disk image size
 # This is also required if embedding content into /combustion, especially for airgap.
 # Should *only* execute if the user is building a raw disk image.
-{{ if ne .DiskSize "" -}}
+{{ if .ABPartitions -}}
+# Split the disk between two root slots of equal size. The root partition (slot A) is resized to half of the
+# space following its start, the second half is left free for the copy of the root partition (slot B).
+truncate -r {{.ImagePath}} {{.ImagePath}}.expanded
+truncate -s {{.DiskSize}} {{.ImagePath}}.expanded
+ROOT_START=$(guestfish --ro --blocksize=$BLOCKSIZE --format=raw -a {{.ImagePath}} run : part-list /dev/sda | awk '/part_num: 3$/ {found=1} found && /part_start:/ {print $2; exit}')
+SLOT_SIZE_MB=$(( ($(stat -c %s {{.ImagePath}}.expanded) - ROOT_START) / 2 / 1048576 - 1 ))
+virt-resize --resize /dev/sda3=${SLOT_SIZE_MB}M --no-extra-partition {{.ImagePath}} {{.ImagePath}}.expanded
+cp {{.ImagePath}}.expanded {{.ImagePath}}
+rm -f {{.ImagePath}}.expanded
+echo "[INFO] Root slot size: ${SLOT_SIZE_MB} MB"
+{{ else if ne .DiskSize "" -}}
 truncate -r {{.ImagePath}} {{.ImagePath}}.expanded
 truncate -s {{.DiskSize}} {{.ImagePath}}.expanded
 virt-resize --expand /dev/sda3 {{.ImagePath}} {{.ImagePath}}.expanded
@@ -35,10 +57,38 @@ guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} -i <<'EOF'
   # Enables write access to the read only filesystem
   sh "btrfs property set / ro false"
 
+  {{ if ne .KernelScript "" }}
+  # Replace the kernel before the GRUB configuration is adjusted, as its installation regenerates grub.cfg
+  copy-in {{.KernelRepo}} /tmp
+  upload {{.KernelScript}} /tmp/eib-kernel.sh
+  sh "/bin/sh /tmp/eib-kernel.sh"
+  rm /tmp/eib-kernel.sh
+  rm-rf {{.KernelRepoDir}}
+  {{ end }}
+
   {{ if ne .ConfigureGRUB "" }}
   {{ .ConfigureGRUB }}
   {{ end }}
 
+  {{ if ne .RemovePathsScript "" }}
+  # Prune the configured paths before any EIB content is added to the image
+  upload {{.RemovePathsScript}} /tmp/eib-remove-paths.sh
+  sh "/bin/sh /tmp/eib-remove-paths.sh"
+  rm /tmp/eib-remove-paths.sh
+  {{ end }}
+
+  {{ if ne .StripDocsScript "" }}
+  upload {{.StripDocsScript}} /tmp/eib-strip-docs.sh
+  sh "/bin/sh /tmp/eib-strip-docs.sh"
+  rm /tmp/eib-strip-docs.sh
+  {{ end }}
+
+  {{ if ne .RootMountOptions "" }}
+  upload {{.RootMountOptions}} /tmp/eib-root-mount-options.sh
+  sh "/bin/sh /tmp/eib-root-mount-options.sh"
+  rm /tmp/eib-root-mount-options.sh
+  {{ end }}
+
   {{ if .ConfigureCombustion }}
   copy-in {{.CombustionDir}} /
   copy-in {{.ArtefactsDir}} /
@@ -56,3 +106,71 @@ guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} -i <<'EOF'
   # Resets the filesystem to read only
   sh "btrfs property set / ro true"
 EOF
+
+{{ if ne .SecureBootScript "" -}}
+# Sign the boot artefacts before slot B is copied from the root partition
+{{.SecureBootScript}} $BLOCKSIZE
+{{ end -}}
+
+{{ if .ABPartitions }}
+# Slot B starts as a copy of the configured slot A. It receives its own file system UUID, so that the
+# bootloader and fstab of each slot refer to their own root file system, and does not run combustion again.
+ROOT_END=$(guestfish --ro --blocksize=$BLOCKSIZE --format=raw -a {{.ImagePath}} run : part-list /dev/sda | awk '/part_num: 3$/ {found=1} found && /part_end:/ {print $2; exit}')
+
+guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} <<EOF
+  run
+  # Leave room for the backup partition table at the end of the disk
+  part-add /dev/sda p $(( ROOT_END / BLOCKSIZE + 1 )) -$(( 16384 / BLOCKSIZE + 2 ))
+  copy-device-to-device /dev/sda3 /dev/sda4
+  btrfstune-set-uuid-random /dev/sda4
+  set-label /dev/sda4 ROOT_B
+EOF
+
+SLOT_A_UUID=$(guestfish --ro --blocksize=$BLOCKSIZE --format=raw -a {{.ImagePath}} run : vfs-uuid /dev/sda3)
+SLOT_B_UUID=$(guestfish --ro --blocksize=$BLOCKSIZE --format=raw -a {{.ImagePath}} run : vfs-uuid /dev/sda4)
+SLOT_SUBVOLUME=$(guestfish --ro --blocksize=$BLOCKSIZE --format=raw -a {{.ImagePath}} run : mount-ro /dev/sda3 / : sh "btrfs subvolume get-default /" | awk '{print $NF}')
+
+guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} <<EOF
+  run
+  mount /dev/sda4 /
+  sh "btrfs property set / ro false"
+  sh "sed -i 's/${SLOT_A_UUID}/${SLOT_B_UUID}/g' /etc/fstab /boot/grub2/grub.cfg"
+  rm-rf /{{.CombustionDirName}}
+  rm-rf /{{.ArtefactsDirName}}
+  sh "btrfs property set / ro true"
+EOF
+
+# Slot A boots by default, slot B is selected by running "grub2-editenv - set eib_slot=b" on the node
+cat <<- EOF > {{.ImagePath}}.custom.cfg
+menuentry "Root Slot B" --id eib-slot-b {
+  search --no-floppy --fs-uuid --set=root ${SLOT_B_UUID}
+  configfile /${SLOT_SUBVOLUME}/boot/grub2/grub.cfg
+}
+
+if [ "\${eib_slot}" = "b" ]; then
+  set default=eib-slot-b
+fi
+EOF
+
+guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} <<EOF
+  run
+  mount /dev/sda3 /
+  sh "btrfs property set / ro false"
+  upload {{.ImagePath}}.custom.cfg /boot/grub2/custom.cfg
+  sh "btrfs property set / ro true"
+EOF
+
+rm -f {{.ImagePath}}.custom.cfg
+
+# Print the resulting slots as found in the image, the labels are expected to be INSTALL (or ROOT if the
+# file system is not renamed) and ROOT_B
+guestfish --ro --blocksize=$BLOCKSIZE --format=raw -a {{.ImagePath}} run : vfs-label /dev/sda3 : vfs-label /dev/sda4 : part-list /dev/sda | awk '
+  NR == 1 { label[3] = $0 }
+  NR == 2 { label[4] = $0 }
+  /part_num:/ { part = $2 }
+  /part_size:/ { size[part] = $2 }
+  END {
+    printf "[INFO] Root slot layout: a 3 %d %s\n", size[3] / 1048576, label[3]
+    printf "[INFO] Root slot layout: b 4 %d %s\n", size[4] / 1048576, label[4]
+  }'
+{{ end -}}
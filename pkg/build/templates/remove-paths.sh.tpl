@@ -0,0 +1,16 @@
+#!/bin/sh
+set -eu
+
+{{/* Template Fields */ -}}
+{{/* Paths - absolute paths removed from the image */ -}}
+
+# Runs inside the image; the reported sizes are picked up from the build log
+for path in{{ range .Paths }} '{{ . }}'{{ end }}; do
+  if [ -e "$path" ] || [ -L "$path" ]; then
+    size=$(du -sk "$path" | cut -f1)
+    rm -rf "$path"
+    echo "[INFO] Removed path: $path ($size KiB)"
+  else
+    echo "[WARN] Path not found: $path"
+  fi
+done
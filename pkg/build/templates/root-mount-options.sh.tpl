@@ -0,0 +1,46 @@
+#!/bin/sh
+set -eu
+
+{{/* Template Fields */ -}}
+{{/* Options - comma separated mount options merged into the options of the root file system */ -}}
+
+# Runs inside the image; the resulting options are picked up from the build log.
+# Options sharing a key replace the existing ones, e.g. noatime replaces relatime and nossd replaces ssd.
+awk -v options='{{ .Options }}' '
+function key(option) {
+  sub(/=.*/, "", option)
+  if (option ~ /^(no|rel|strict)?atime$/) return "atime"
+  sub(/^no/, "", option)
+  return option
+}
+
+$1 !~ /^#/ && $2 == "/" {
+  found = 1
+  count = split($4, current, ",")
+  requestedCount = split(options, requested, ",")
+
+  result = ""
+  for (i = 1; i <= count; i++) {
+    replaced = 0
+    for (j = 1; j <= requestedCount; j++) {
+      if (key(current[i]) == key(requested[j])) replaced = 1
+    }
+    if (!replaced) result = result (result == "" ? "" : ",") current[i]
+  }
+  for (j = 1; j <= requestedCount; j++) result = result (result == "" ? "" : ",") requested[j]
+
+  $4 = result
+}
+
+{ print }
+
+END { if (!found) exit 1 }
+' /etc/fstab > /tmp/eib-fstab || {
+  echo "[ERROR] No root file system entry found in /etc/fstab"
+  exit 1
+}
+
+cat /tmp/eib-fstab > /etc/fstab
+rm /tmp/eib-fstab
+
+echo "[INFO] Root mount options: $(awk '$1 !~ /^#/ && $2 == "/" { print $4 }' /etc/fstab)"
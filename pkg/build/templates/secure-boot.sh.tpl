@@ -0,0 +1,109 @@
+#!/bin/bash
+set -euo pipefail
+
+#  Template Fields
+#  ImagePath   - Full path to the image to sign the boot artefacts of
+#  Key         - Full path to the PEM encoded signing key
+#  Certificate - Full path to the PEM encoded certificate of the signing key
+#  Kernel      - If true, the kernel images are signed, replacing their existing signatures
+#  Modules     - If true, the unsigned out-of-tree kernel modules are signed
+#  ToolArgEnv  - libguestfs environment variables forwarded through --tool-arg
+#
+# Runs on the build host, the signing key is never copied into the image. The block size of the image is
+# passed as the only argument.
+
+BLOCKSIZE=$1
+{{ range .ToolArgEnv }}
+export {{ . }}
+{{- end }}
+
+WORK_DIR=$(mktemp -d)
+trap 'rm -rf "$WORK_DIR"' EXIT
+
+guestfish_image() {
+  guestfish --blocksize=$BLOCKSIZE --format=raw -a '{{.ImagePath}}' -i "$@"
+}
+
+# Booting with Secure Boot relies on the shim of the base image, which verifies the kernel against the MOK list
+if [ -z "$(guestfish_image --ro glob-expand '/usr/share/efi/*/shim.efi')" ]; then
+  echo "[ERROR] The base image does not support Secure Boot, no shim bootloader was found under /usr/share/efi"
+  exit 1
+fi
+
+MODULE_SIGNATURE_MAGIC='~Module signature appended~'
+
+# Appends a signature in the format of the kernel's sign-file: the detached PKCS#7 signature, followed by the
+# module_signature structure (identifying the PKCS#7 type and the big endian signature length) and the magic
+sign_module() {
+  local module=$1
+
+  openssl cms -sign -binary -noattr -nocerts -md sha256 -outform DER \
+    -in "$module" -signer '{{.Certificate}}' -inkey '{{.Key}}' -out "$module.p7s"
+
+  local size
+  size=$(stat -c %s "$module.p7s")
+
+  {
+    cat "$module.p7s"
+    printf '\0\0\2\0\0\0\0\0'
+    printf "$(printf '\\%03o\\%03o\\%03o\\%03o' $((size >> 24 & 255)) $((size >> 16 & 255)) $((size >> 8 & 255)) $((size & 255)))"
+    printf '%s\n' "$MODULE_SIGNATURE_MAGIC"
+  } >> "$module"
+
+  rm "$module.p7s"
+}
+
+echo 'sh "btrfs property set / ro false"' > "$WORK_DIR/upload.gf"
+COUNT=0
+
+{{ if .Kernel -}}
+for kernel in $(guestfish_image --ro glob-expand '/boot/vmlinuz-*' : glob-expand '/boot/Image-*'); do
+  case "$kernel" in
+    *.hmac) continue ;;
+  esac
+
+  COUNT=$((COUNT + 1))
+  guestfish_image --ro download "$kernel" "$WORK_DIR/kernel-$COUNT"
+  sbsign --key '{{.Key}}' --cert '{{.Certificate}}' --output "$WORK_DIR/kernel-$COUNT.signed" "$WORK_DIR/kernel-$COUNT"
+  echo "upload \"$WORK_DIR/kernel-$COUNT.signed\" \"$kernel\"" >> "$WORK_DIR/upload.gf"
+  echo "[INFO] Signed kernel: $kernel"
+done
+
+{{ end -}}
+{{ if .Modules -}}
+# The modules shipped with the kernel are already signed by the distribution
+MODULES=$(guestfish_image --ro sh "find /usr/lib/modules /lib/modules \( -path '*/updates/*' -o -path '*/extra/*' \) -name '*.ko*' -type f -exec readlink -f {} + 2>/dev/null | sort -u || true")
+
+for module in $MODULES; do
+  COUNT=$((COUNT + 1))
+  module_file="$WORK_DIR/module-$COUNT.ko"
+
+  case "$module" in
+    *.ko) guestfish_image --ro download "$module" "$module_file" ;;
+    *.ko.zst) guestfish_image --ro download "$module" "$module_file.zst" && zstd -q -d --rm "$module_file.zst" ;;
+    *.ko.xz) guestfish_image --ro download "$module" "$module_file.xz" && xz -d "$module_file.xz" ;;
+    *.ko.gz) guestfish_image --ro download "$module" "$module_file.gz" && gzip -d "$module_file.gz" ;;
+    *) continue ;;
+  esac
+
+  if [ "$(tail -c 28 "$module_file" | head -c 27)" = "$MODULE_SIGNATURE_MAGIC" ]; then
+    echo "[INFO] Module already signed: $module"
+    continue
+  fi
+
+  sign_module "$module_file"
+
+  case "$module" in
+    *.ko.zst) zstd -q --rm "$module_file" && module_file="$module_file.zst" ;;
+    *.ko.xz) xz --check=crc32 --lzma2=dict=1MiB "$module_file" && module_file="$module_file.xz" ;;
+    *.ko.gz) gzip -n "$module_file" && module_file="$module_file.gz" ;;
+  esac
+
+  echo "upload \"$module_file\" \"$module\"" >> "$WORK_DIR/upload.gf"
+  echo "[INFO] Signed module: $module"
+done
+
+{{ end -}}
+echo 'sh "btrfs property set / ro true"' >> "$WORK_DIR/upload.gf"
+
+guestfish_image --rw < "$WORK_DIR/upload.gf"
@@ -0,0 +1,15 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* VolumeID - label of the ISOs, which cloud-init looks for when searching for a NoCloud seed */ -}}
+{{/* ISOs     - directory holding the seed files of each node, along with the full path of its ISO */ -}}
+
+{{ range .ISOs -}}
+xorriso -outdev '{{ .OutputFilename }}' \
+        -volid {{ $.VolumeID }} \
+        -joliet on \
+        -map '{{ .SeedDir }}' / \
+        -commit
+
+{{ end -}}
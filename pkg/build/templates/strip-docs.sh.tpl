@@ -0,0 +1,34 @@
+#!/bin/sh
+set -eu
+
+{{/* Template Fields */ -}}
+{{/* Dirs    - documentation directories emptied within the image */ -}}
+{{/* Exclude - files and directories under the documentation directories which are kept */ -}}
+
+# Runs inside the image; the reclaimed space is picked up from the build log
+docs_size() {
+  du -skc{{ range .Dirs }} '{{ . }}'{{ end }} 2>/dev/null | tail -n 1 | cut -f1
+}
+
+{{ if .Exclude -}}
+for path in{{ range .Exclude }} '{{ . }}'{{ end }}; do
+  if [ ! -e "$path" ] && [ ! -L "$path" ]; then
+    echo "[WARN] Excluded documentation not found: $path"
+  fi
+done
+
+{{ end -}}
+BEFORE=$(docs_size)
+
+for dir in{{ range .Dirs }} '{{ . }}'{{ end }}; do
+  if [ ! -d "$dir" ]; then
+    continue
+  fi
+
+  # Files are deleted before the directories left empty, so that the parents of the excluded paths are kept
+  find "$dir" ! -type d{{ range .Exclude }} ! -path '{{ . }}' ! -path '{{ . }}/*'{{ end }} -delete
+  find "$dir" -mindepth 1 -depth -type d -empty{{ range .Exclude }} ! -path '{{ . }}' ! -path '{{ . }}/*'{{ end }} -delete
+done
+
+AFTER=$(docs_size)
+echo "[INFO] Stripped documentation: $((BEFORE - AFTER)) KiB"
@@ -0,0 +1,122 @@
+package build
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// toolArg is an option of the tooling assembling the image which the image definition does not model.
+// Such options are forwarded as is through the unsupported --tool-arg escape hatch.
+type toolArg struct {
+	// env is the environment variable of the image modification, and thus of libguestfs, set to the value
+	env string
+	// qemuImgOption is the option of the qcow2 conversion set to the value
+	qemuImgOption string
+	// valueRegex restricts the values to those the tool accepts, as they are not checked otherwise
+	valueRegex *regexp.Regexp
+}
+
+// toolArgs is the allowlist of the keys accepted by --tool-arg
+var toolArgs = map[string]toolArg{
+	"libguestfs-memsize":          {env: "LIBGUESTFS_MEMSIZE", valueRegex: regexp.MustCompile(`^[1-9][0-9]*$`)},
+	"libguestfs-backend":          {env: "LIBGUESTFS_BACKEND", valueRegex: regexp.MustCompile(`^(direct|libvirt)$`)},
+	"libguestfs-backend-settings": {env: "LIBGUESTFS_BACKEND_SETTINGS", valueRegex: regexp.MustCompile(`^(force_tcg|force_kvm)$`)},
+	"qcow2-cluster-size":          {qemuImgOption: "cluster_size", valueRegex: regexp.MustCompile(`^[1-9][0-9]*[kKmM]?$`)},
+	"qcow2-compression-type":      {qemuImgOption: "compression_type", valueRegex: regexp.MustCompile(`^(zlib|zstd)$`)},
+	"qcow2-preallocation":         {qemuImgOption: "preallocation", valueRegex: regexp.MustCompile(`^(off|metadata|falloc|full)$`)},
+}
+
+// ToolArgUsage describes the tooling run by a build, which determines the tool arguments applying to it.
+type ToolArgUsage struct {
+	// Guestfish indicates that libguestfs runs, either to assemble the image or to resolve its packages
+	Guestfish bool
+	// Qcow2Conversion indicates that the assembled image is converted into a QCOW2 image
+	Qcow2Conversion bool
+}
+
+// ToolArgNames returns the keys accepted by --tool-arg in alphabetical order.
+func ToolArgNames() []string {
+	return sortedKeys(toolArgs)
+}
+
+// ParseToolArgs parses the 'key=value' pairs given through --tool-arg, rejecting the keys outside of
+// the allowlist, the keys of tools the build does not run and the values the corresponding tool does not accept.
+func ParseToolArgs(args []string, usage ToolArgUsage) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			return nil, fmt.Errorf("tool argument '%s' is not specified as 'key=value'", arg)
+		}
+
+		option, ok := toolArgs[key]
+		if !ok {
+			return nil, fmt.Errorf("tool argument '%s' is not allowlisted", key)
+		}
+
+		if option.env != "" && !usage.Guestfish {
+			return nil, fmt.Errorf("tool argument '%s' does not apply, as libguestfs is not run by the build", key)
+		}
+
+		if option.qemuImgOption != "" && !usage.Qcow2Conversion {
+			return nil, fmt.Errorf("tool argument '%s' does not apply, as the image is not converted into a QCOW2 image", key)
+		}
+
+		if !option.valueRegex.MatchString(value) {
+			return nil, fmt.Errorf("value '%s' of tool argument '%s' is invalid", value, key)
+		}
+
+		if _, exists := parsed[key]; exists {
+			return nil, fmt.Errorf("tool argument '%s' is specified more than once", key)
+		}
+
+		parsed[key] = value
+	}
+
+	return parsed, nil
+}
+
+// ToolArgEnv returns the environment variables forwarding the given tool arguments to every libguestfs run,
+// be it while assembling the image or while resolving its packages.
+func ToolArgEnv(args map[string]string) []string {
+	var env []string
+	for _, key := range sortedKeys(args) {
+		if option := toolArgs[key]; option.env != "" {
+			env = append(env, fmt.Sprintf("%s=%s", option.env, args[key]))
+		}
+	}
+
+	return env
+}
+
+func (b *Builder) toolArgEnv() []string {
+	return ToolArgEnv(b.context.ToolArgs)
+}
+
+// qemuImgToolOptions returns the tool arguments forwarded to the qcow2 conversion as the value of its '-o' flag.
+func (b *Builder) qemuImgToolOptions() string {
+	var options []string
+	for _, key := range sortedKeys(b.context.ToolArgs) {
+		if option := toolArgs[key]; option.qemuImgOption != "" {
+			options = append(options, fmt.Sprintf("%s=%s", option.qemuImgOption, b.context.ToolArgs[key]))
+		}
+	}
+
+	return strings.Join(options, ",")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
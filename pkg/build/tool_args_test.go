@@ -0,0 +1,110 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestParseToolArgs(t *testing.T) {
+	allTools := ToolArgUsage{Guestfish: true, Qcow2Conversion: true}
+
+	tests := map[string]struct {
+		args          []string
+		usage         ToolArgUsage
+		expected      map[string]string
+		expectedError string
+	}{
+		"None": {},
+		"Valid": {
+			usage: allTools,
+			args:  []string{"libguestfs-memsize=2048", "qcow2-cluster-size=2M", "libguestfs-backend-settings=force_tcg"},
+			expected: map[string]string{
+				"libguestfs-memsize":          "2048",
+				"qcow2-cluster-size":          "2M",
+				"libguestfs-backend-settings": "force_tcg",
+			},
+		},
+		"Missing value": {
+			usage:         allTools,
+			args:          []string{"libguestfs-memsize"},
+			expectedError: "tool argument 'libguestfs-memsize' is not specified as 'key=value'",
+		},
+		"Unknown key": {
+			usage:         allTools,
+			args:          []string{"LD_PRELOAD=/tmp/lib.so"},
+			expectedError: "tool argument 'LD_PRELOAD' is not allowlisted",
+		},
+		"Invalid value": {
+			usage:         allTools,
+			args:          []string{"qcow2-compression-type=zstd,backing_file=/etc/shadow"},
+			expectedError: "value 'zstd,backing_file=/etc/shadow' of tool argument 'qcow2-compression-type' is invalid",
+		},
+		"Duplicate key": {
+			usage:         allTools,
+			args:          []string{"libguestfs-backend=direct", "libguestfs-backend=libvirt"},
+			expectedError: "tool argument 'libguestfs-backend' is specified more than once",
+		},
+		"Without libguestfs": {
+			args:          []string{"libguestfs-memsize=2048"},
+			usage:         ToolArgUsage{Qcow2Conversion: true},
+			expectedError: "tool argument 'libguestfs-memsize' does not apply, as libguestfs is not run by the build",
+		},
+		"Without qcow2 conversion": {
+			args:          []string{"libguestfs-memsize=2048", "qcow2-cluster-size=2M"},
+			usage:         ToolArgUsage{Guestfish: true},
+			expectedError: "tool argument 'qcow2-cluster-size' does not apply, as the image is not converted into a QCOW2 image",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			toolArgs, err := ParseToolArgs(test.args, test.usage)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				assert.Nil(t, toolArgs)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, toolArgs)
+		})
+	}
+}
+
+func TestToolArgNames(t *testing.T) {
+	assert.Equal(t, []string{
+		"libguestfs-backend",
+		"libguestfs-backend-settings",
+		"libguestfs-memsize",
+		"qcow2-cluster-size",
+		"qcow2-compression-type",
+		"qcow2-preallocation",
+	}, ToolArgNames())
+}
+
+func TestToolArgForwarding(t *testing.T) {
+	builder := Builder{
+		context: &image.Context{
+			ToolArgs: map[string]string{
+				"qcow2-preallocation":    "metadata",
+				"libguestfs-memsize":     "2048",
+				"qcow2-compression-type": "zstd",
+				"libguestfs-backend":     "direct",
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"LIBGUESTFS_BACKEND=direct", "LIBGUESTFS_MEMSIZE=2048"}, builder.toolArgEnv())
+	assert.Equal(t, "compression_type=zstd,preallocation=metadata", builder.qemuImgToolOptions())
+}
+
+func TestToolArgForwarding_None(t *testing.T) {
+	builder := Builder{context: &image.Context{}}
+
+	assert.Nil(t, builder.toolArgEnv())
+	assert.Empty(t, builder.qemuImgToolOptions())
+}
@@ -1,6 +1,9 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
@@ -8,83 +11,595 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"go.uber.org/zap"
 )
 
+const (
+	blobsDir      = "blobs"
+	indexFilename = "index.json"
+	lockFilename  = "index.lock"
+
+	// pendingBlobPrefix prefixes the blobs which are still being written
+	pendingBlobPrefix = "pending-"
+	// legacyKeyPrefix prefixes the index keys of the files migrated from the previous cache layout, which are
+	// only known by the FNV-1 hash of their identifier until they are looked up again
+	legacyKeyPrefix = "legacy/"
+)
+
+// Cache stores files content-addressed by their SHA256 digest.
+// An index maps the logical file identifiers to the digests of the stored blobs,
+// allowing multiple identifiers to reference a single copy of identical contents.
+//
+// The cache may be shared by concurrent builds. Updates of the index are serialized through a lock file
+// and merged with the changes of other processes, while blobs are written independently of each other.
 type Cache struct {
-	cacheDir string
+	cacheDir   string
+	mu         sync.Mutex
+	index      map[string]string
+	bytesSaved int64
+	// readOnly caches neither store files nor modify the index, nor create the lock file
+	readOnly bool
+}
+
+// ErrReadOnly is returned when storing files in a cache opened read-only.
+var ErrReadOnly = errors.New("cache is opened read-only")
+
+// Discrepancy describes a cached file which does not match the digest it was stored with.
+type Discrepancy struct {
+	Identifier     string
+	ExpectedDigest string
+	// ActualDigest is empty if the cached file is missing.
+	ActualDigest string
+}
+
+// Dir returns the path to the cache stored under the given root directory.
+func Dir(rootDir string) string {
+	return filepath.Join(rootDir, "cache")
 }
 
 func New(rootDir string) (*Cache, error) {
-	cacheDir := filepath.Join(rootDir, "cache")
-	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+	cacheDir := Dir(rootDir)
+	if err := os.MkdirAll(filepath.Join(cacheDir, blobsDir), os.ModePerm); err != nil {
 		return nil, fmt.Errorf("creating a cache directory: %w", err)
 	}
 
-	return &Cache{cacheDir: cacheDir}, nil
+	cache := &Cache{cacheDir: cacheDir}
+
+	if err := cache.migrateLegacyFiles(); err != nil {
+		return nil, fmt.Errorf("migrating files of the previous cache layout: %w", err)
+	}
+
+	index, err := cache.readIndex()
+	if err != nil {
+		return nil, fmt.Errorf("reading cache index: %w", err)
+	}
+	cache.index = index
+
+	return cache, nil
 }
 
+// Open opens the existing cache under the given root directory read-only, for inspecting it without creating
+// or modifying any of its files. The index is read without the lock file, as it is always replaced atomically.
+func Open(rootDir string) (*Cache, error) {
+	cacheDir := Dir(rootDir)
+	if _, err := os.Stat(filepath.Join(cacheDir, blobsDir)); err != nil {
+		return nil, fmt.Errorf("opening cache directory: %w", err)
+	}
+
+	cache := &Cache{cacheDir: cacheDir, readOnly: true}
+
+	index, err := cache.readIndex()
+	if err != nil {
+		return nil, fmt.Errorf("reading cache index: %w", err)
+	}
+	cache.index = index
+
+	return cache, nil
+}
+
+// Get returns the path to the file stored under the given identifier. The file is verified against its
+// digest without holding the cache lock, so that other files can be accessed in the meantime.
 func (cache *Cache) Get(fileIdentifier string) (path string, err error) {
-	path, err = cache.identifierPath(fileIdentifier)
+	digest, err := cache.lookup(fileIdentifier)
 	if err != nil {
-		return "", fmt.Errorf("searching for identifier '%s' in cache: %w", fileIdentifier, err)
+		return "", err
 	}
 
+	path = cache.blobPath(digest)
+
 	if !exists(path) {
-		return "", fs.ErrNotExist
+		zap.S().Warnf("Blob for identifier '%s' is missing from cache", fileIdentifier)
+		return "", cache.removeEntry(fileIdentifier, fs.ErrNotExist)
+	}
+
+	actualDigest, err := fileDigest(path)
+	if err != nil {
+		return "", fmt.Errorf("calculating digest of cached file with identifier '%s': %w", fileIdentifier, err)
+	}
+
+	if actualDigest != digest {
+		zap.S().Warnf("Cached file with identifier '%s' is corrupted (expected digest '%s', found '%s'), discarding it",
+			fileIdentifier, digest, actualDigest)
+
+		if cache.readOnly {
+			return "", fs.ErrNotExist
+		}
+
+		// The file may have been discarded by a concurrent lookup already
+		if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("removing corrupted cache file: %w", err)
+		}
+
+		return "", cache.removeEntry(fileIdentifier, fs.ErrNotExist)
 	}
 
 	return path, nil
 }
 
-func (cache *Cache) Put(fileIdentifier string, reader io.Reader) error {
-	path, err := cache.identifierPath(fileIdentifier)
+// lookup returns the digest of the file stored under the given identifier. Files migrated from the previous
+// cache layout are indexed under their identifier once they are looked up.
+func (cache *Cache) lookup(fileIdentifier string) (string, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if digest, ok := cache.index[fileIdentifier]; ok {
+		return digest, nil
+	}
+
+	// The file may have been stored by another process since the index was read
+	if err := cache.reloadIndex(); err != nil {
+		return "", err
+	}
+
+	if digest, ok := cache.index[fileIdentifier]; ok {
+		return digest, nil
+	}
+
+	legacyKey := legacyIndexKey(fileIdentifier)
+
+	digest, ok := cache.index[legacyKey]
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+
+	if cache.readOnly {
+		return digest, nil
+	}
+
+	zap.S().Infof("Indexing file with identifier '%s' migrated from the previous cache layout", fileIdentifier)
+
+	err := cache.updateIndex(func(index map[string]string) {
+		index[fileIdentifier] = digest
+		delete(index, legacyKey)
+	})
 	if err != nil {
-		return fmt.Errorf("searching for identifier '%s' in cache: %w", fileIdentifier, err)
+		return "", fmt.Errorf("updating cache index: %w", err)
 	}
 
-	if exists(path) {
+	return digest, nil
+}
+
+// Put stores the contents of the reader under the given identifier. The contents are read without holding
+// the cache lock, so that multiple files can be stored at the same time.
+func (cache *Cache) Put(fileIdentifier string, reader io.Reader) error {
+	if cache.readOnly {
+		return ErrReadOnly
+	}
+
+	cache.mu.Lock()
+	digest, ok := cache.index[fileIdentifier]
+	cache.mu.Unlock()
+
+	if ok && exists(cache.blobPath(digest)) {
 		zap.S().Warnf("File with identifier '%s' already exists in cache", fileIdentifier)
 		return fs.ErrExist
 	}
 
 	zap.S().Infof("Storing file with identifier '%s' in cache", fileIdentifier)
 
-	file, err := os.Create(path)
+	file, err := os.CreateTemp(filepath.Join(cache.cacheDir, blobsDir), pendingBlobPrefix)
 	if err != nil {
 		return fmt.Errorf("creating file: %w", err)
 	}
-	defer file.Close()
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	hash := sha256.New()
 
-	if _, err = io.Copy(file, reader); err != nil {
+	size, err := io.Copy(io.MultiWriter(file, hash), reader)
+	if err != nil {
 		return fmt.Errorf("storing file: %w", err)
 	}
 
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+
+	digest = hex.EncodeToString(hash.Sum(nil))
+	path := cache.blobPath(digest)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if exists(path) {
+		cache.bytesSaved += size
+		zap.S().Infof("File with identifier '%s' is identical to an already cached file with digest '%s'", fileIdentifier, digest)
+		log.AuditInfof("Deduplicated cached artefact '%s', saving %d bytes (%d bytes saved in total).",
+			filepath.Base(fileIdentifier), size, cache.bytesSaved)
+	} else if err = os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("moving file into cache: %w", err)
+	}
+
+	err = cache.updateIndex(func(index map[string]string) {
+		index[fileIdentifier] = digest
+		delete(index, legacyIndexKey(fileIdentifier))
+	})
+	if err != nil {
+		return fmt.Errorf("updating cache index: %w", err)
+	}
+
 	return nil
 }
 
-func (cache *Cache) identifierPath(fileIdentifier string) (string, error) {
-	identifier, err := identifierHash(fileIdentifier)
+// Verify checks every cached file against the digest it was stored with. Unlike Get, the cache is left
+// unmodified, with any discrepancy being returned instead. The number of verified files is returned
+// alongside the discrepancies, which are sorted by file identifier.
+func (cache *Cache) Verify() (int, []Discrepancy, error) {
+	cache.mu.Lock()
+	index := make(map[string]string, len(cache.index))
+	identifiers := make([]string, 0, len(cache.index))
+	for identifier, digest := range cache.index {
+		index[identifier] = digest
+		identifiers = append(identifiers, identifier)
+	}
+	cache.mu.Unlock()
+
+	slices.Sort(identifiers)
+
+	var discrepancies []Discrepancy
+	for _, identifier := range identifiers {
+		digest := index[identifier]
+
+		path := cache.blobPath(digest)
+		if !exists(path) {
+			discrepancies = append(discrepancies, Discrepancy{Identifier: identifier, ExpectedDigest: digest})
+			continue
+		}
+
+		actualDigest, err := fileDigest(path)
+		if err != nil {
+			return 0, nil, fmt.Errorf("calculating digest of cached file with identifier '%s': %w", identifier, err)
+		}
+
+		if actualDigest != digest {
+			discrepancies = append(discrepancies, Discrepancy{Identifier: identifier, ExpectedDigest: digest, ActualDigest: actualDigest})
+		}
+	}
+
+	return len(identifiers), discrepancies, nil
+}
+
+// Uncached returns the given identifiers which have no entry in the cache, including those stored by other
+// processes since the cache was opened, in their original order.
+func (cache *Cache) Uncached(identifiers []string) ([]string, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if err := cache.reloadIndex(); err != nil {
+		return nil, err
+	}
+
+	var uncached []string
+	for _, identifier := range identifiers {
+		_, ok := cache.index[identifier]
+		if !ok {
+			_, ok = cache.index[legacyIndexKey(identifier)]
+		}
+
+		if !ok {
+			uncached = append(uncached, identifier)
+		}
+	}
+
+	return uncached, nil
+}
+
+// Identifiers returns the sorted identifiers of the cached files, including those stored by other
+// processes since the cache was opened. The files migrated from the previous cache layout are omitted
+// until they are looked up, as their identifiers are not known before.
+func (cache *Cache) Identifiers() ([]string, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if err := cache.reloadIndex(); err != nil {
+		return nil, err
+	}
+
+	identifiers := make([]string, 0, len(cache.index))
+	for identifier := range cache.index {
+		if !strings.HasPrefix(identifier, legacyKeyPrefix) {
+			identifiers = append(identifiers, identifier)
+		}
+	}
+	slices.Sort(identifiers)
+
+	return identifiers, nil
+}
+
+// Size returns the total size in bytes of the stored blobs, counting deduplicated contents once.
+func (cache *Cache) Size() (int64, error) {
+	entries, err := os.ReadDir(filepath.Join(cache.cacheDir, blobsDir))
 	if err != nil {
-		return "", err
+		return 0, fmt.Errorf("reading blobs directory: %w", err)
 	}
 
-	return filepath.Join(cache.cacheDir, identifier), nil
+	var size int64
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), pendingBlobPrefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return 0, fmt.Errorf("reading blob info: %w", err)
+		}
+
+		size += info.Size()
+	}
+
+	return size, nil
+}
+
+// BytesSaved returns the total amount of bytes which were not stored
+// since identical content was already present in the cache.
+func (cache *Cache) BytesSaved() int64 {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	return cache.bytesSaved
+}
+
+func (cache *Cache) blobPath(digest string) string {
+	return filepath.Join(cache.cacheDir, blobsDir, digest)
+}
+
+func (cache *Cache) indexPath() string {
+	return filepath.Join(cache.cacheDir, indexFilename)
+}
+
+func (cache *Cache) readIndex() (map[string]string, error) {
+	index := map[string]string{}
+
+	data, err := os.ReadFile(cache.indexPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return index, nil
+		}
+
+		return nil, fmt.Errorf("reading index file: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("parsing index file: %w", err)
+	}
+
+	return index, nil
+}
+
+func (cache *Cache) writeIndex() error {
+	data, err := json.MarshalIndent(cache.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing index: %w", err)
+	}
+
+	// Write to a temporary file first, so that an interrupted write never leaves a corrupted index behind
+	tmpPath := cache.indexPath() + ".tmp"
+	if err = os.WriteFile(tmpPath, data, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing index file: %w", err)
+	}
+
+	if err = os.Rename(tmpPath, cache.indexPath()); err != nil {
+		return fmt.Errorf("replacing index file: %w", err)
+	}
+
+	return nil
+}
+
+func (cache *Cache) removeEntry(fileIdentifier string, cause error) error {
+	if cache.readOnly {
+		return cause
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	err := cache.updateIndex(func(index map[string]string) {
+		delete(index, fileIdentifier)
+	})
+	if err != nil {
+		return fmt.Errorf("updating cache index: %w", err)
+	}
+
+	return cause
+}
+
+// migrateLegacyFiles moves the files stored by previous versions directly under the cache directory, named by
+// the FNV-1 hash of their identifier, into the blobs. As the hash cannot be mapped back to the identifier, the
+// files are indexed under their hash until they are looked up by an identifier matching it. Only files which
+// cannot be read, and can therefore not be migrated, are removed.
+func (cache *Cache) migrateLegacyFiles() error {
+	entries, err := os.ReadDir(cache.cacheDir)
+	if err != nil {
+		return fmt.Errorf("reading cache directory: %w", err)
+	}
+
+	migrated := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if _, err = strconv.ParseUint(entry.Name(), 10, 64); err != nil {
+			continue
+		}
+
+		digest, migrateErr := cache.migrateLegacyFile(entry.Name())
+		if migrateErr != nil {
+			return migrateErr
+		}
+
+		if digest != "" {
+			migrated[legacyKeyPrefix+entry.Name()] = digest
+		}
+	}
+
+	if len(migrated) == 0 {
+		return nil
+	}
+
+	return cache.updateIndex(func(index map[string]string) {
+		for key, digest := range migrated {
+			index[key] = digest
+		}
+	})
 }
 
-func identifierHash(identifier string) (string, error) {
+// migrateLegacyFile moves the legacy file into the blobs, returning its digest, or an empty digest
+// if the file was removed instead.
+func (cache *Cache) migrateLegacyFile(name string) (string, error) {
+	path := filepath.Join(cache.cacheDir, name)
+
+	digest, err := fileDigest(path)
+	if err != nil {
+		// The file may have been migrated by another process opening the cache at the same time
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+
+		zap.S().Warnf("Removing file '%s' of the previous cache layout which cannot be migrated: %s", name, err)
+
+		if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("removing file: %w", err)
+		}
+
+		return "", nil
+	}
+
+	zap.S().Infof("Migrating file '%s' of the previous cache layout to the blob with digest '%s'", name, digest)
+
+	if exists(cache.blobPath(digest)) {
+		err = os.Remove(path)
+	} else {
+		err = os.Rename(path, cache.blobPath(digest))
+	}
+
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("moving file into cache: %w", err)
+	}
+
+	return digest, nil
+}
+
+// legacyIndexKey returns the index key a file stored under the given identifier by previous versions
+// is migrated to.
+func legacyIndexKey(fileIdentifier string) string {
 	h := fnv.New64()
+	_, _ = h.Write([]byte(fileIdentifier))
 
-	if _, err := h.Write([]byte(identifier)); err != nil {
-		return "", err
+	return legacyKeyPrefix + strconv.FormatUint(h.Sum64(), 10)
+}
+
+// updateIndex applies the update to the index as currently stored, which may have been changed by
+// other processes, and writes it back while holding the lock file.
+func (cache *Cache) updateIndex(update func(index map[string]string)) error {
+	unlock, err := cache.lockIndex()
+	if err != nil {
+		return err
 	}
+	defer unlock()
+
+	index, err := cache.readIndex()
+	if err != nil {
+		return fmt.Errorf("reading cache index: %w", err)
+	}
+
+	update(index)
+	cache.index = index
+
+	return cache.writeIndex()
+}
 
-	hash := strconv.FormatUint(h.Sum64(), 10)
+// reloadIndex replaces the index with the one currently stored, which may have been changed by other processes.
+func (cache *Cache) reloadIndex() error {
+	if cache.readOnly {
+		index, err := cache.readIndex()
+		if err != nil {
+			return fmt.Errorf("reading cache index: %w", err)
+		}
+		cache.index = index
+
+		return nil
+	}
+
+	unlock, err := cache.lockIndex()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	index, err := cache.readIndex()
+	if err != nil {
+		return fmt.Errorf("reading cache index: %w", err)
+	}
+	cache.index = index
+
+	return nil
+}
+
+// lockIndex acquires the lock file guarding the index against concurrent updates by other processes,
+// returning the function releasing it.
+func (cache *Cache) lockIndex() (func(), error) {
+	file, err := os.OpenFile(filepath.Join(cache.cacheDir, lockFilename), os.O_CREATE|os.O_RDWR, fileio.NonExecutablePerms)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	if err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("locking cache index: %w", err)
+	}
+
+	return func() {
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN); err != nil {
+			zap.S().Warnf("Unlocking cache index failed: %v", err)
+		}
+		_ = file.Close()
+	}, nil
+}
+
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("reading file: %w", err)
+	}
 
-	zap.S().Debugf("Generated hash '%s' from identifier '%s'", hash, identifier)
-	return hash, nil
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
 func exists(path string) bool {
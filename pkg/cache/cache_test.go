@@ -1,9 +1,12 @@
 package cache
 
 import (
+	"fmt"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,3 +63,291 @@ func TestCache_DoubleInsert(t *testing.T) {
 	require.NoError(t, cache.Put(fileIdentifier, strings.NewReader(fileContents)))
 	assert.ErrorIs(t, cache.Put(fileIdentifier, strings.NewReader(fileContents)), fs.ErrExist)
 }
+
+func TestCache_Deduplication(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	fileContents := "some-data"
+
+	require.NoError(t, cache.Put("v1.0.0/artefact", strings.NewReader(fileContents)))
+	require.NoError(t, cache.Put("v1.0.1/artefact", strings.NewReader(fileContents)))
+
+	firstPath, err := cache.Get("v1.0.0/artefact")
+	require.NoError(t, err)
+
+	secondPath, err := cache.Get("v1.0.1/artefact")
+	require.NoError(t, err)
+
+	assert.Equal(t, firstPath, secondPath)
+	assert.EqualValues(t, len(fileContents), cache.BytesSaved())
+
+	entries, err := os.ReadDir(filepath.Join("test-cache", "cache", blobsDir))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestCache_IndexPersisted(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	fileIdentifier := "some-cool-filename"
+	require.NoError(t, cache.Put(fileIdentifier, strings.NewReader("some-data")))
+
+	reloaded, err := New("test-cache")
+	require.NoError(t, err)
+
+	path, err := reloaded.Get(fileIdentifier)
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+}
+
+func TestCache_CorruptedEntry(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	fileIdentifier := "some-cool-filename"
+	require.NoError(t, cache.Put(fileIdentifier, strings.NewReader("some-data")))
+
+	path, err := cache.Get(fileIdentifier)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("tampered"), 0o600))
+
+	path, err = cache.Get(fileIdentifier)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Empty(t, path)
+
+	// The corrupted entry is discarded, allowing the file to be cached again
+	require.NoError(t, cache.Put(fileIdentifier, strings.NewReader("some-data")))
+}
+
+func TestNew_MigratesLegacyFiles(t *testing.T) {
+	cacheDir := filepath.Join("test-cache", "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, os.ModePerm))
+	defer func() {
+		assert.NoError(t, os.RemoveAll("test-cache"))
+	}()
+
+	// Previous versions stored files under the FNV-1 hash of their identifier
+	identifier := "https://github.com/rancher/rke2/releases/download/v1.30.3+rke2r1/rke2-images-core.linux-amd64.tar.zst"
+	legacyFile := filepath.Join(cacheDir, "16770735154450827064")
+	require.NoError(t, os.WriteFile(legacyFile, []byte("some-data"), 0o600))
+
+	unknownFile := filepath.Join(cacheDir, "14695981039346656037")
+	require.NoError(t, os.WriteFile(unknownFile, []byte("other-data"), 0o600))
+
+	unrelatedFile := filepath.Join(cacheDir, "notes.txt")
+	require.NoError(t, os.WriteFile(unrelatedFile, []byte("some-notes"), 0o600))
+
+	cache, err := New("test-cache")
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, legacyFile)
+	assert.NoFileExists(t, unknownFile)
+	assert.FileExists(t, unrelatedFile)
+
+	// The identifiers of the migrated files are unknown until they are looked up
+	identifiers, err := cache.Identifiers()
+	require.NoError(t, err)
+	assert.Empty(t, identifiers)
+
+	uncached, err := cache.Uncached([]string{identifier, "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"missing"}, uncached)
+
+	path, err := cache.Get(identifier)
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "some-data", string(contents))
+
+	identifiers, err = cache.Identifiers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{identifier}, identifiers)
+
+	// Both migrated files are stored as blobs, the one not looked up yet remaining indexed under its hash
+	verified, discrepancies, err := cache.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, 2, verified)
+	assert.Empty(t, discrepancies)
+
+	// The migration is persisted for other processes
+	other, err := New("test-cache")
+	require.NoError(t, err)
+
+	path, err = other.Get(identifier)
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+}
+
+func TestNew_MigratesDuplicateLegacyFiles(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	require.NoError(t, cache.Put("cached", strings.NewReader("some-data")))
+
+	legacyFile := filepath.Join("test-cache", "cache", "14695981039346656037")
+	require.NoError(t, os.WriteFile(legacyFile, []byte("some-data"), 0o600))
+
+	_, err := New("test-cache")
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, legacyFile)
+
+	size, err := cache.Size()
+	require.NoError(t, err)
+	assert.EqualValues(t, len("some-data"), size)
+
+	// The empty identifier hashes to the FNV-1 offset basis
+	path, err := cache.Get("")
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+}
+
+func TestCache_ConcurrentGet(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	require.NoError(t, cache.Put("some-cool-filename", strings.NewReader("some-data")))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			path, err := cache.Get("some-cool-filename")
+			assert.NoError(t, err)
+			assert.FileExists(t, path)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCache_Verify(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	require.NoError(t, cache.Put("intact", strings.NewReader("intact-data")))
+	require.NoError(t, cache.Put("corrupted", strings.NewReader("corrupted-data")))
+	require.NoError(t, cache.Put("missing", strings.NewReader("missing-data")))
+
+	corruptedDigest := cache.index["corrupted"]
+	require.NoError(t, os.WriteFile(cache.blobPath(corruptedDigest), []byte("tampered"), 0o600))
+
+	missingDigest := cache.index["missing"]
+	require.NoError(t, os.Remove(cache.blobPath(missingDigest)))
+
+	verified, discrepancies, err := cache.Verify()
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, verified)
+	require.Len(t, discrepancies, 2)
+
+	assert.Equal(t, "corrupted", discrepancies[0].Identifier)
+	assert.Equal(t, corruptedDigest, discrepancies[0].ExpectedDigest)
+	assert.NotEmpty(t, discrepancies[0].ActualDigest)
+	assert.NotEqual(t, corruptedDigest, discrepancies[0].ActualDigest)
+
+	assert.Equal(t, Discrepancy{Identifier: "missing", ExpectedDigest: missingDigest}, discrepancies[1])
+
+	// The cache is left untouched
+	assert.Equal(t, corruptedDigest, cache.index["corrupted"])
+	assert.Equal(t, missingDigest, cache.index["missing"])
+	assert.FileExists(t, cache.blobPath(corruptedDigest))
+}
+
+func TestOpen(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	require.NoError(t, cache.Put("cached", strings.NewReader("cached-data")))
+	require.NoError(t, cache.Put("corrupted", strings.NewReader("corrupted-data")))
+	require.NoError(t, os.Remove(filepath.Join(cache.cacheDir, lockFilename)))
+
+	corruptedDigest := cache.index["corrupted"]
+	require.NoError(t, os.WriteFile(cache.blobPath(corruptedDigest), []byte("tampered"), 0o600))
+
+	readOnly, err := Open("test-cache")
+	require.NoError(t, err)
+
+	uncached, err := readOnly.Uncached([]string{"missing", "cached", "other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"missing", "other"}, uncached)
+
+	_, err = readOnly.Get("corrupted")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	assert.ErrorIs(t, readOnly.Put("new", strings.NewReader("new-data")), ErrReadOnly)
+
+	// Neither the corrupted entry is discarded nor is the lock file created
+	assert.FileExists(t, cache.blobPath(corruptedDigest))
+	assert.Contains(t, readOnly.index, "corrupted")
+	assert.NoFileExists(t, filepath.Join(cache.cacheDir, lockFilename))
+}
+
+func TestOpen_MissingCache(t *testing.T) {
+	rootDir := filepath.Join(t.TempDir(), "missing")
+
+	_, err := Open(rootDir)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoDirExists(t, rootDir)
+}
+
+func TestCache_SharedBetweenInstances(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	other, err := New("test-cache")
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Put("first", strings.NewReader("first-data")))
+	require.NoError(t, other.Put("second", strings.NewReader("second-data")))
+
+	// Neither update is lost, and each instance sees the files stored by the other
+	path, err := cache.Get("second")
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	identifiers, err := other.Identifiers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, identifiers)
+}
+
+func TestCache_ConcurrentPut(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, cache.Put(fmt.Sprintf("file-%d", i), strings.NewReader(fmt.Sprintf("data-%d", i))))
+		}()
+	}
+	wg.Wait()
+
+	identifiers, err := cache.Identifiers()
+	require.NoError(t, err)
+	assert.Len(t, identifiers, 10)
+}
+
+func TestCache_Size(t *testing.T) {
+	cache, teardown := setup(t)
+	defer teardown()
+
+	size, err := cache.Size()
+	require.NoError(t, err)
+	assert.Zero(t, size)
+
+	require.NoError(t, cache.Put("first", strings.NewReader("some-data")))
+	require.NoError(t, cache.Put("second", strings.NewReader("some-data")))
+	require.NoError(t, cache.Put("third", strings.NewReader("other")))
+
+	// Deduplicated contents are only counted once
+	size, err = cache.Size()
+	require.NoError(t, err)
+	assert.EqualValues(t, len("some-data")+len("other"), size)
+}
@@ -1,14 +1,22 @@
 package build
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 
+	"github.com/containers/image/v5/types"
 	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
 	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/env"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/urfave/cli/v2"
@@ -16,70 +24,368 @@ import (
 )
 
 const (
-	buildLogFilename     = "eib-build.log"
-	checkBuildLogMessage = "Please check the eib-build.log file under the build directory for more information."
+	buildLogFilename = "eib-build.log"
+
+	// missingToolsExitCode distinguishes builds failing due to the host lacking required executables
+	missingToolsExitCode = 3
 )
 
 func Run(_ *cli.Context) error {
 	args := &cmd.BuildArgs
 
-	rootBuildDir := args.RootBuildDir
+	if args.NoColor {
+		log.DisableColor()
+	}
+
+	if args.Quiet {
+		http.DisableProgress()
+	}
+
+	rootBuildDir, buildDir, err := setupBuildDirectories(args)
+	if err != nil {
+		return err
+	}
+
+	// This needs to occur as early as possible so that the subsequent calls can use the log
+	log.ConfigureGlobalLogger(filepath.Join(buildDir, buildLogFilename), log.Rotation{
+		MaxSizeMB:  args.LogMaxSize,
+		MaxAge:     args.LogMaxAge,
+		MaxBackups: args.LogMaxBackups,
+	})
+
+	ctx, cmdErr := newBuildContext(args, buildDir)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage())
+		os.Exit(1)
+	}
+
+	opts, cmdErr := buildOptions(args, rootBuildDir)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage())
+		os.Exit(1)
+	}
+
+	if args.Explain {
+		if err = eib.Prepare(ctx, opts); err != nil {
+			exitWithBuildError(err)
+		}
+
+		if err = explainBuild(ctx, rootBuildDir, opts.Phases); err != nil {
+			exitWithError(fmt.Sprintf("Explaining the build failed. %s", checkBuildLogMessage()),
+				"An error occurred explaining the build", err)
+		}
+
+		return nil
+	}
+
+	outputs, cmdErr := prepareOutputs(args, ctx.ImageDefinition)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage())
+		os.Exit(1)
+	}
+
+	buildReport, err := eib.Build(context.Background(), ctx, opts)
+	if err != nil {
+		exitWithBuildError(err)
+	}
+
+	if args.SmokeTest {
+		if err = runSmokeTest(ctx, outputs.qemuPath, args.SmokeTestTimeout); err != nil {
+			exitWithError(fmt.Sprintf("Smoke test failed. Please check the %s file under the build directory for the boot output.",
+				smokeTestLogFilename), "Smoke test failed", err)
+		}
+	}
+
+	if err = writeReport(ctx, buildReport, args.ReportFormat); err != nil {
+		exitWithError(fmt.Sprintf("Writing the build report failed. %s", checkBuildLogMessage()),
+			"An error occurred writing the build report", err)
+	}
+
+	if args.PackerManifest != "" {
+		if err = writePackerManifest(ctx, buildReport, args.PackerManifest); err != nil {
+			exitWithError(fmt.Sprintf("Writing the Packer manifest failed. %s", checkBuildLogMessage()),
+				"An error occurred writing the Packer manifest", err)
+		}
+	}
+
+	if outputs.pushRef != nil {
+		if err = pushImage(ctx, outputs.pushRef, buildReport); err != nil {
+			exitWithError(fmt.Sprintf("Pushing the image failed. %s", checkBuildLogMessage()),
+				"An error occurred pushing the image", err)
+		}
+	}
+
+	return nil
+}
+
+// setupBuildDirectories returns the root build directory, by default under the image configuration directory,
+// along with the directory of this build created within it.
+func setupBuildDirectories(args *cmd.BuildFlags) (rootBuildDir, buildDir string, err error) {
+	rootBuildDir = args.RootBuildDir
 	if rootBuildDir == "" {
 		const defaultBuildDir = "_build"
 
 		rootBuildDir = filepath.Join(args.ConfigDir, defaultBuildDir)
-		if err := os.MkdirAll(rootBuildDir, os.ModePerm); err != nil {
-			log.Auditf("The root build directory could not be set up under the configuration directory '%s'.", args.ConfigDir)
-			return err
+		if err = os.MkdirAll(rootBuildDir, os.ModePerm); err != nil {
+			if isNotWritable(err) {
+				log.Audit(defaultBuildDirNotWritableMessage(args.ConfigDir))
+			} else {
+				log.Auditf("The root build directory could not be set up under the configuration directory '%s'.", args.ConfigDir)
+			}
+			return "", "", err
 		}
 	}
 
-	buildDir, err := eib.SetupBuildDirectory(rootBuildDir)
+	buildDir, err = eib.SetupBuildDirectory(rootBuildDir)
 	if err != nil {
-		log.Audit("The build directory could not be set up.")
-		return err
+		if args.RootBuildDir == "" && isNotWritable(err) {
+			log.Audit(defaultBuildDirNotWritableMessage(args.ConfigDir))
+		} else {
+			log.Audit("The build directory could not be set up.")
+		}
+		return "", "", err
 	}
 
-	// This needs to occur as early as possible so that the subsequent calls can use the log
-	log.ConfigureGlobalLogger(filepath.Join(buildDir, buildLogFilename))
+	return rootBuildDir, buildDir, nil
+}
 
+// newBuildContext parses and validates the image definition, reporting any warnings, and maps the flags
+// customizing the build onto the context of the build.
+func newBuildContext(args *cmd.BuildFlags, buildDir string) (*image.Context, *cmd.Error) {
 	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
-		cmd.LogError(cmdErr, checkBuildLogMessage)
-		os.Exit(1)
+		return nil, cmdErr
 	}
 
-	imageDefinition, cmdErr := parseImageDefinition(args.ConfigDir, args.DefinitionFile)
+	imageDefinition, cmdErr := parseImageDefinition(args)
 	if cmdErr != nil {
-		cmd.LogError(cmdErr, checkBuildLogMessage)
-		os.Exit(1)
+		return nil, cmdErr
 	}
 
 	combustionDir, artefactsDir, err := eib.SetupCombustionDirectory(buildDir)
 	if err != nil {
-		log.Auditf("Setting up the combustion directory failed. %s", checkBuildLogMessage)
-		zap.S().Fatalf("Failed to create combustion directories: %s", err)
+		return nil, &cmd.Error{
+			UserMessage: "Setting up the combustion directory failed.",
+			LogMessage:  fmt.Sprintf("Failed to create combustion directories: %v", err),
+		}
+	}
+
+	ctx := &image.Context{
+		ImageConfigDir:            args.ConfigDir,
+		BuildDir:                  buildDir,
+		CombustionDir:             combustionDir,
+		ArtefactsDir:              artefactsDir,
+		ImageDefinition:           imageDefinition,
+		BaseImageOverride:         args.BaseImage,
+		PreserveScriptPermissions: args.PreserveScriptPermissions,
+		AllowArchMismatch:         args.AllowArchMismatch,
+		AllowCriticalRemovals:     args.AllowCriticalRemovals,
+		ForbidLatestTags:          args.ForbidLatestTags,
+		SkipChartImageCheck:       args.SkipChartImageCheck,
+		CombustionOnly:            args.CombustionOnly,
+		SkipSpaceCheck:            args.SkipSpaceCheck,
+	}
+
+	if cmdErr = validateImageDefinition(ctx, args.Strict, args.NoWarnings); cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	if ctx.CombustionOnly {
+		log.Audit("Building only the combustion ISO, the image will not be assembled from the base image.")
+	}
+
+	if ctx.BaseImageOverride != "" {
+		log.Auditf("Using the locally provided base image '%s' instead of '%s'.",
+			ctx.BaseImageOverride, ctx.ImageDefinition.Image.BaseImage)
+		zap.S().Infof("Base image overridden with '%s'", ctx.BaseImageOverride)
+	}
+
+	return ctx, nil
+}
+
+// buildOptions maps the flags processed by the build onto its options. The image definition has already been
+// validated by newBuildContext, reporting any warnings to the user.
+func buildOptions(args *cmd.BuildFlags, rootBuildDir string) (eib.Options, *cmd.Error) {
+	maxBandwidth, cmdErr := parseMaxBandwidth(args.MaxBandwidth)
+	if cmdErr != nil {
+		return eib.Options{}, cmdErr
+	}
+
+	phases, cmdErr := parsePhases(args)
+	if cmdErr != nil {
+		return eib.Options{}, cmdErr
+	}
+
+	return eib.Options{
+		RootBuildDir:     rootBuildDir,
+		SkipValidation:   true,
+		MaxBandwidth:     maxBandwidth,
+		Phases:           phases,
+		ProfileArtifacts: args.ProfileArtifacts,
+		ExpectedHash:     args.ExpectHash,
+		Reproducible:     args.Reproducible,
+		SourceDateEpoch:  os.Getenv(env.SourceDateEpochVariable),
+		ToolArgs:         args.ToolArgs.Value(),
+	}, nil
+}
+
+// buildOutputs are the destinations of the built image beyond the build directory, other than the report.
+type buildOutputs struct {
+	pushRef  types.ImageReference
+	qemuPath string
+}
+
+// prepareOutputs checks the flags handling the built image before the build starts, so that the build does not
+// fail only once the image is assembled.
+func prepareOutputs(args *cmd.BuildFlags, definition *image.Definition) (outputs buildOutputs, cmdErr *cmd.Error) {
+	if cmdErr = validateReportFormat(args.ReportFormat); cmdErr != nil {
+		return outputs, cmdErr
+	}
+
+	if args.PackerManifest != "" {
+		if cmdErr = validatePackerManifestPath(args.PackerManifest); cmdErr != nil {
+			return outputs, cmdErr
+		}
+	}
+
+	if args.Push != "" {
+		if outputs.pushRef, cmdErr = preparePush(args.Push); cmdErr != nil {
+			return outputs, cmdErr
+		}
+	}
+
+	if args.SmokeTest {
+		if outputs.qemuPath, cmdErr = prepareSmokeTest(definition); cmdErr != nil {
+			return outputs, cmdErr
+		}
+	}
+
+	return outputs, nil
+}
+
+// optionFlags are the flags setting the options of the build reported through an eib.OptionError.
+var optionFlags = map[string]string{
+	eib.OptionExpectedHash:    "--expect-hash",
+	eib.OptionSourceDateEpoch: "--reproducible",
+	eib.OptionToolArgs:        "--tool-arg",
+}
+
+// exitWithBuildError reports why the build failed and exits, distinguishing the failures of the pre-flight
+// checks which the user can act upon from the failures of the build phases.
+func exitWithBuildError(err error) {
+	var optionErr *eib.OptionError
+	if errors.As(err, &optionErr) {
+		log.AuditError(fmt.Sprintf("The '%s' value is invalid: %s.", optionFlags[optionErr.Option], optionErr.Err))
+		zap.S().Errorf("Build aborted: %s", err)
+		os.Exit(1)
+	}
+
+	var hashErr *eib.HashMismatchError
+	if errors.As(err, &hashErr) {
+		log.AuditError(fmt.Sprintf("The hash of the image definition '%s' does not match the expected hash '%s'. "+
+			"The definition or its includes have changed.", hashErr.Hash, hashErr.Expected))
+		zap.S().Errorf("Build aborted: %s", err)
+		os.Exit(1)
 	}
 
-	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, imageDefinition)
+	var toolsErr *eib.MissingToolsError
+	if errors.As(err, &toolsErr) {
+		reportMissingTools(toolsErr)
+		os.Exit(missingToolsExitCode)
+	}
 
-	if cmdErr = validateImageDefinition(ctx); cmdErr != nil {
-		cmd.LogError(cmdErr, checkBuildLogMessage)
+	var spaceErr *eib.InsufficientSpaceError
+	if errors.As(err, &spaceErr) {
+		log.AuditError(fmt.Sprintf("The build directories lack the disk space the build is estimated to require: %s. "+
+			"Please free up space or use the --skip-space-check flag if the estimate is inaccurate.", spaceErr))
+		zap.S().Errorf("Build aborted: %s", err)
 		os.Exit(1)
 	}
 
-	defer func() {
-		if r := recover(); r != nil {
-			log.Auditf("Build failed unexpectedly. %s", checkBuildLogMessage)
-			zap.S().Fatalf("Unexpected error occurred: %s", r)
+	var downloadErr *eib.SkippedDownloadError
+	if errors.As(err, &downloadErr) {
+		message := fmt.Sprintf("The image definition configures components requiring the download phase: %s. "+
+			"Please run the download phase or remove these components from the image definition.", strings.Join(downloadErr.Components, ", "))
+		if len(downloadErr.Uncached) != 0 {
+			message += fmt.Sprintf(" The Kubernetes artefacts are reused without the download phase once cached by an earlier build, "+
+				"these are missing from the cache: %s.", strings.Join(downloadErr.Uncached, ", "))
 		}
-	}()
+		log.AuditError(message)
+		zap.S().Errorf("Build aborted: %s", err)
+		os.Exit(1)
+	}
 
-	if err = eib.Run(ctx, rootBuildDir); err != nil {
-		log.Audit(checkBuildLogMessage)
-		zap.S().Fatalf("An error occurred building the image: %s", err)
+	userMessage := checkBuildLogMessage()
+	var phaseErr *eib.PhaseError
+	if errors.As(err, &phaseErr) {
+		if phaseErr.Phase == eib.PhasePreflight {
+			userMessage = fmt.Sprintf("The pre-flight checks failed. %s", userMessage)
+		} else {
+			userMessage = fmt.Sprintf("The %s phase failed. %s", phaseErr.Phase, userMessage)
+		}
 	}
 
-	return nil
+	exitWithError(userMessage, "An error occurred building the image", err)
+}
+
+// exitWithError audits the given message and exits after writing the error to the build log.
+// With --summary-only, the message is preceded by the cause of the failure on a single line.
+// Panics are displayed in full regardless, as their cause is rarely meaningful without the stack trace.
+func exitWithError(userMessage, logMessage string, err error) {
+	var panicErr *eib.PanicError
+	switch {
+	case errors.As(err, &panicErr):
+		log.AuditError(fmt.Sprintf("%s\n%s", panicErr, panicErr.Stack))
+		zap.S().Errorf("Build panicked: %s\n%s", panicErr, panicErr.Stack)
+	case cmd.BuildArgs.SummaryOnly:
+		log.AuditError(strings.Join(strings.Fields(err.Error()), " "))
+	}
+
+	log.Audit(userMessage)
+	zap.S().Fatalf("%s: %s", logMessage, err)
+}
+
+// reportMissingTools lists every executable required by the build which is not installed, along with
+// the package providing it.
+func reportMissingTools(err *eib.MissingToolsError) {
+	lines := []string{"The following tools required by the build are not installed:"}
+	for _, tool := range err.Tools {
+		lines = append(lines, fmt.Sprintf("  - %s (required for %s): install the '%s' package",
+			tool.Name, strings.Join(tool.Features, ", "), tool.Package))
+	}
+	lines = append(lines, "All of these tools are included in the EIB container image.")
+
+	log.AuditError(strings.Join(lines, "\n"))
+	zap.S().Errorf("Build aborted: %s", err)
+}
+
+// checkBuildLogMessage directs the user to the build log, including any files the log was rotated into.
+func checkBuildLogMessage() string {
+	message := fmt.Sprintf("Please check the %s file under the build directory for more information.", buildLogFilename)
+
+	rotatedFiles := log.RotatedLogFiles()
+	if len(rotatedFiles) == 0 {
+		return message
+	}
+
+	var names []string
+	for _, f := range rotatedFiles {
+		names = append(names, filepath.Base(f))
+	}
+
+	return fmt.Sprintf("%s Earlier entries were rotated into: %s.", message, strings.Join(names, ", "))
+}
+
+// isNotWritable checks whether creating a directory failed due to a read-only filesystem or missing permissions,
+// typically when the image configuration directory is mounted read-only into the container.
+func isNotWritable(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission)
+}
+
+// defaultBuildDirNotWritableMessage suggests an alternative to the default build directory. The log file is not
+// configured yet at this point, the original error is displayed once the command fails instead.
+func defaultBuildDirNotWritableMessage(configDir string) string {
+	return fmt.Sprintf("The image configuration directory '%s' is not writable, so the default build directory cannot be "+
+		"created under it. Please use the '--build-dir' flag to specify a writable location (e.g. a separately mounted volume).", configDir)
 }
 
 func imageConfigDirExists(configDir string) *cmd.Error {
@@ -100,8 +406,23 @@ func imageConfigDirExists(configDir string) *cmd.Error {
 	}
 }
 
-func parseImageDefinition(configDir, definitionFile string) (*image.Definition, *cmd.Error) {
-	definitionFilePath := filepath.Join(configDir, definitionFile)
+func parseImageDefinition(args *cmd.BuildFlags) (*image.Definition, *cmd.Error) {
+	if args.DefinitionStdin {
+		if args.DefinitionFile != "" {
+			return nil, &cmd.Error{
+				UserMessage: "The '--definition-file' and '--definition-stdin' flags cannot be used together.",
+			}
+		}
+
+		configData, cmdErr := readDefinitionStdin(os.Stdin)
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		return parseDefinitionData(configData, args.ConfigDir, "", args.SchemaDir, "read from stdin")
+	}
+
+	definitionFilePath := filepath.Join(args.ConfigDir, args.DefinitionFile)
 
 	configData, err := os.ReadFile(definitionFilePath)
 	if err != nil {
@@ -117,25 +438,143 @@ func parseImageDefinition(configDir, definitionFile string) (*image.Definition,
 		}
 	}
 
-	imageDefinition, err := image.ParseDefinition(configData)
+	return parseDefinitionData(configData, args.ConfigDir, args.DefinitionFile, args.SchemaDir, fmt.Sprintf("file '%s'", definitionFilePath))
+}
+
+func readDefinitionStdin(stdin *os.File) ([]byte, *cmd.Error) {
+	// Reading from an interactive terminal would block until the user closes the input
+	if info, err := stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		return nil, &cmd.Error{
+			UserMessage: "The '--definition-stdin' flag requires the image definition to be piped to stdin.",
+		}
+	}
+
+	configData, err := io.ReadAll(stdin)
 	if err != nil {
 		return nil, &cmd.Error{
-			UserMessage: fmt.Sprintf("The image definition file '%s' could not be parsed.", definitionFilePath),
-			LogMessage:  fmt.Sprintf("Parsing definition file failed: %v", err),
+			UserMessage: "The image definition could not be read from stdin.",
+			LogMessage:  fmt.Sprintf("Reading definition from stdin failed: %v", err),
+		}
+	}
+
+	if len(bytes.TrimSpace(configData)) == 0 {
+		return nil, &cmd.Error{
+			UserMessage: "No image definition was provided on stdin.",
 		}
 	}
 
+	return configData, nil
+}
+
+// parseDefinitionData merges any included fragments into the definition and validates it against the schema of its
+// apiVersion, read from schemaDir if set, before parsing it. Custom schemas may extend the definition, the fields
+// outside of the image definition being ignored with a warning.
+func parseDefinitionData(configData []byte, configDir, definitionFile, schemaDir, source string) (*image.Definition, *cmd.Error) {
+	configData, err := image.ResolveIncludes(configData, configDir, definitionFile)
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The includes of the image definition %s could not be resolved: %v", source, err),
+			LogMessage:  fmt.Sprintf("Resolving definition includes failed: %v", err),
+		}
+	}
+
+	if err = image.ValidateSchema(configData, schemaDir); err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The image definition %s failed schema validation: %v", source, err),
+			LogMessage:  fmt.Sprintf("Validating definition schema failed: %v", err),
+		}
+	}
+
+	parse := image.ParseDefinition
+	if schemaDir != "" {
+		parse = image.ParseExtendedDefinition
+	}
+
+	imageDefinition, err := parse(configData)
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The image definition %s could not be parsed.", source),
+			LogMessage:  fmt.Sprintf("Parsing definition failed: %v", err),
+		}
+	}
+
+	if schemaDir != "" {
+		warnUnknownFields(configData, source)
+	}
+
 	return imageDefinition, nil
 }
 
-// Assembles the image build context with user-provided values and implementation defaults.
-func buildContext(buildDir, combustionDir, artefactsDir, configDir string, imageDefinition *image.Definition) *image.Context {
-	ctx := &image.Context{
-		ImageConfigDir:  configDir,
-		BuildDir:        buildDir,
-		CombustionDir:   combustionDir,
-		ArtefactsDir:    artefactsDir,
-		ImageDefinition: imageDefinition,
+// warnUnknownFields warns about the fields allowed by a custom schema which are not part of the image definition,
+// as these are ignored by the build.
+func warnUnknownFields(configData []byte, source string) {
+	fields, err := image.UnknownFields(configData)
+	if err != nil {
+		zap.S().Warnf("Listing unknown definition fields failed: %s", err)
+		return
+	}
+
+	for _, field := range fields {
+		log.Auditf("WARNING: The field '%s' of the image definition %s is not part of the image definition "+
+			"of this version of Edge Image Builder and is ignored.", field, source)
+		zap.S().Warnf("Ignoring unknown definition field '%s'", field)
+	}
+}
+
+// parseMaxBandwidth converts the download bandwidth limit into bytes per second, zero meaning unlimited.
+func parseMaxBandwidth(value string) (int64, *cmd.Error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	bytesPerSecond, err := http.ParseBandwidth(value)
+	if err != nil {
+		return 0, &cmd.Error{
+			UserMessage: fmt.Sprintf("The '--max-bandwidth' value '%s' is invalid, it must be given as e.g. '10MB/s' or '512KiB/s'.", value),
+			LogMessage:  fmt.Sprintf("Parsing maximum bandwidth failed: %v", err),
+		}
+	}
+
+	return bytesPerSecond, nil
+}
+
+// parsePhases determines the build phases selected through the --only and --skip flags. Pushing and smoke
+// testing the image require it to be assembled, which is replaced by packaging the combustion ISO with --combustion-only.
+func parsePhases(args *cmd.BuildFlags) (eib.Phases, *cmd.Error) {
+	if args.CombustionOnly {
+		if args.OnlyPhases != "" || args.SkipPhases != "" || args.Push != "" || args.SmokeTest {
+			return nil, &cmd.Error{
+				UserMessage: "The '--combustion-only' flag cannot be combined with the '--only', '--skip', '--push' and '--smoke-test' flags.",
+			}
+		}
+
+		return eib.Phases{eib.PhaseDownload, eib.PhaseCombustion}, nil
+	}
+
+	phases, err := eib.SelectPhases(splitPhases(args.OnlyPhases), splitPhases(args.SkipPhases))
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The selected build phases are invalid: %s.", err),
+			LogMessage:  fmt.Sprintf("Selecting build phases failed: %v", err),
+		}
+	}
+
+	if !phases.Enabled(eib.PhaseAssembly) && (args.Push != "" || args.SmokeTest) {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The '--push' and '--smoke-test' flags require the %s phase.", eib.PhaseAssembly),
+		}
 	}
-	return ctx
+
+	return phases, nil
+}
+
+func splitPhases(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
 }
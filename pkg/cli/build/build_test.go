@@ -0,0 +1,215 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const testDefinition = `apiVersion: "1.0"
+image:
+  imageType: raw
+  arch: x86_64
+  baseImage: base.raw
+  outputImageName: eib.raw
+`
+
+// stdinFile returns a file holding the given contents, standing in for the data piped to stdin.
+func stdinFile(t *testing.T, contents string) *os.File {
+	f, err := os.CreateTemp(t.TempDir(), "stdin-")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = f.Close()
+	})
+
+	_, err = f.WriteString(contents)
+	require.NoError(t, err)
+	_, err = f.Seek(0, 0)
+	require.NoError(t, err)
+
+	return f
+}
+
+func TestReadDefinitionStdin(t *testing.T) {
+	tests := map[string]struct {
+		contents        string
+		expectedMessage string
+	}{
+		"Piped": {
+			contents: testDefinition,
+		},
+		"Empty": {
+			expectedMessage: "No image definition was provided on stdin.",
+		},
+		"Whitespace only": {
+			contents:        " \n\t\n",
+			expectedMessage: "No image definition was provided on stdin.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, cmdErr := readDefinitionStdin(stdinFile(t, test.contents))
+
+			if test.expectedMessage != "" {
+				require.NotNil(t, cmdErr)
+				assert.Equal(t, test.expectedMessage, cmdErr.UserMessage)
+				assert.Nil(t, data)
+				return
+			}
+
+			require.Nil(t, cmdErr)
+			assert.Equal(t, test.contents, string(data))
+		})
+	}
+}
+
+func TestReadDefinitionStdin_Terminal(t *testing.T) {
+	// Character devices stand in for an interactive terminal, which would block the build
+	terminal, err := os.Open(os.DevNull)
+	require.NoError(t, err)
+	defer terminal.Close()
+
+	data, cmdErr := readDefinitionStdin(terminal)
+	require.NotNil(t, cmdErr)
+	assert.Equal(t, "The '--definition-stdin' flag requires the image definition to be piped to stdin.", cmdErr.UserMessage)
+	assert.Nil(t, data)
+}
+
+func TestParseImageDefinition(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "definition.yaml"), []byte(testDefinition), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "invalid.yaml"), []byte("apiVersion: \"1.0\"\nimage:\n  imageTypo: raw\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "extended.yaml"), []byte(testDefinition+"  site: lab-1\nowner: edge-team\n"), 0o600))
+
+	// The alternate schema accepts any field, extending the definition with fields which are ignored by the build
+	schemaDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(schemaDir, "1.0.json"), []byte(`{"properties": {"apiVersion": {"const": "1.0"}}}`), 0o600))
+
+	tests := map[string]struct {
+		args            cmd.BuildFlags
+		stdin           string
+		expectedMessage string
+	}{
+		"File": {
+			args: cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "definition.yaml"},
+		},
+		"Missing file": {
+			args:            cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "missing.yaml"},
+			expectedMessage: "The specified definition file '" + filepath.Join(configDir, "missing.yaml") + "' could not be found.",
+		},
+		"Invalid file": {
+			args: cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "invalid.yaml"},
+			expectedMessage: "The image definition file '" + filepath.Join(configDir, "invalid.yaml") + "' failed schema validation: " +
+				"definition does not match the schema of version '1.0': image: Additional property imageTypo is not allowed",
+		},
+		"Schema dir": {
+			args: cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "definition.yaml", SchemaDir: schemaDir},
+		},
+		"Extended file with schema dir": {
+			args: cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "extended.yaml", SchemaDir: schemaDir},
+		},
+		"Extended file without schema dir": {
+			args: cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "extended.yaml"},
+			expectedMessage: "The image definition file '" + filepath.Join(configDir, "extended.yaml") + "' failed schema validation: " +
+				"definition does not match the schema of version '1.0': (root): Additional property owner is not allowed; " +
+				"image: Additional property site is not allowed",
+		},
+		"Missing schema": {
+			args: cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "definition.yaml", SchemaDir: configDir},
+			expectedMessage: "The image definition file '" + filepath.Join(configDir, "definition.yaml") + "' failed schema validation: " +
+				"loading schema of version '1.0': no schema found at '" + filepath.Join(configDir, "1.0.json") + "'",
+		},
+		"Stdin": {
+			args:  cmd.BuildFlags{ConfigDir: configDir, DefinitionStdin: true},
+			stdin: testDefinition,
+		},
+		"Invalid stdin": {
+			args:  cmd.BuildFlags{ConfigDir: configDir, DefinitionStdin: true},
+			stdin: "apiVersion: \"1.0\"\nimage:\n  imageTypo: raw\n",
+			expectedMessage: "The image definition read from stdin failed schema validation: " +
+				"definition does not match the schema of version '1.0': image: Additional property imageTypo is not allowed",
+		},
+		"Both file and stdin": {
+			args:            cmd.BuildFlags{ConfigDir: configDir, DefinitionFile: "definition.yaml", DefinitionStdin: true},
+			expectedMessage: "The '--definition-file' and '--definition-stdin' flags cannot be used together.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if test.args.DefinitionStdin {
+				defer func(original *os.File) {
+					os.Stdin = original
+				}(os.Stdin)
+				os.Stdin = stdinFile(t, test.stdin)
+			}
+
+			definition, cmdErr := parseImageDefinition(&test.args)
+
+			if test.expectedMessage != "" {
+				require.NotNil(t, cmdErr)
+				assert.Equal(t, test.expectedMessage, cmdErr.UserMessage)
+				assert.Nil(t, definition)
+				return
+			}
+
+			require.Nil(t, cmdErr)
+			require.NotNil(t, definition)
+			assert.Equal(t, image.TypeRAW, definition.Image.ImageType)
+			assert.Equal(t, "eib.raw", definition.Image.OutputImageName)
+		})
+	}
+}
+
+func TestIsNotWritable(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"Read-only file system": {
+			err:      &fs.PathError{Op: "mkdir", Path: "/eib/_build", Err: syscall.EROFS},
+			expected: true,
+		},
+		"Permission denied": {
+			err:      fs.ErrPermission,
+			expected: true,
+		},
+		"Access denied": {
+			err:      &fs.PathError{Op: "mkdir", Path: "/eib/_build", Err: syscall.EACCES},
+			expected: true,
+		},
+		"Wrapped read-only file system": {
+			err:      fmt.Errorf("creating build directory: %w", &fs.PathError{Op: "mkdir", Path: "/eib/_build", Err: syscall.EROFS}),
+			expected: true,
+		},
+		"Wrapped unrelated error": {
+			err: fmt.Errorf("creating build directory: %w", &fs.PathError{Op: "mkdir", Path: "/eib/_build", Err: syscall.ENOSPC}),
+		},
+		"Unrelated error": {
+			err: errors.New("no space left"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isNotWritable(test.err))
+		})
+	}
+}
+
+func TestDefaultBuildDirNotWritableMessage(t *testing.T) {
+	message := defaultBuildDirNotWritableMessage("/eib")
+
+	assert.Contains(t, message, "'/eib' is not writable")
+	assert.Contains(t, message, "'--build-dir'")
+}
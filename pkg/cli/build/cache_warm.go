@@ -0,0 +1,107 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+)
+
+const cacheWarmLogFilename = "eib-cache-warm.log"
+
+func WarmCache(_ *cli.Context) error {
+	args := &cmd.CacheWarmArgs
+
+	if args.Parallel < 0 {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The number of parallel downloads must not be negative, found %d.", args.Parallel),
+		}, "")
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(args.RootBuildDir, os.ModePerm); err != nil {
+		log.Auditf("The build directory '%s' could not be set up.", args.RootBuildDir)
+		return err
+	}
+
+	log.ConfigureGlobalLogger(filepath.Join(args.RootBuildDir, cacheWarmLogFilename), log.Rotation{})
+	checkLogMessage := fmt.Sprintf("Please check the %s file under the build directory for more information.", cacheWarmLogFilename)
+
+	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
+		cmd.LogError(cmdErr, checkLogMessage)
+		os.Exit(1)
+	}
+
+	var targets []eib.WarmTarget
+	for _, definitionFile := range args.DefinitionFiles.Value() {
+		target, cmdErr := parseWarmTarget(args.ConfigDir, definitionFile, args.SchemaDir)
+		if cmdErr != nil {
+			cmd.LogError(cmdErr, checkLogMessage)
+			os.Exit(1)
+		}
+
+		targets = append(targets, *target)
+	}
+
+	result, err := eib.WarmCache(context.Background(), args.RootBuildDir, targets, args.Parallel)
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("Warming the cache failed: %v", err),
+			LogMessage:  fmt.Sprintf("Warming cache failed: %v", err),
+		}, checkLogMessage)
+		os.Exit(1)
+	}
+
+	for _, name := range result.Skipped {
+		log.Auditf("The definition '%s' has no cacheable artefacts, only the Kubernetes artefacts are cached.", name)
+	}
+
+	if len(result.Fetched) == 0 {
+		log.Audit("All artefacts were already cached, nothing was fetched.")
+	} else {
+		log.Auditf("Fetched %d artefact(s) into the cache:", len(result.Fetched))
+		for _, identifier := range result.Fetched {
+			log.Auditf("  %s", identifier)
+		}
+	}
+
+	log.Auditf("The cache holds %d artefact(s) totalling %.1f MiB.", result.Entries, float64(result.Size)/(1024*1024))
+
+	return nil
+}
+
+func parseWarmTarget(configDir, definitionFile, schemaDir string) (*eib.WarmTarget, *cmd.Error) {
+	definitionFilePath := filepath.Join(configDir, definitionFile)
+
+	configData, err := os.ReadFile(definitionFilePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &cmd.Error{
+				UserMessage: fmt.Sprintf("The specified definition file '%s' could not be found.", definitionFilePath),
+			}
+		}
+
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified definition file '%s' could not be read.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Reading definition file failed: %v", err),
+		}
+	}
+
+	definition, cmdErr := parseDefinitionData(configData, configDir, definitionFile, schemaDir, fmt.Sprintf("file '%s'", definitionFilePath))
+	if cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	return &eib.WarmTarget{
+		Name:       definitionFile,
+		ConfigDir:  configDir,
+		Definition: definition,
+	}, nil
+}
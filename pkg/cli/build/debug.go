@@ -0,0 +1,157 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	defaultDebugShell = "/bin/bash"
+
+	// Remounts the build directory as read-only within a private mount namespace,
+	// so that the changes are neither visible to nor persisted on the host.
+	readOnlyShellScript = `mount --bind "$EIB_BUILD_DIR" "$EIB_BUILD_DIR" && ` +
+		`mount -o remount,bind,ro "$EIB_BUILD_DIR" && exec "$1"`
+)
+
+func Debug(_ *cli.Context) error {
+	args := &cmd.DebugArgs
+
+	buildDir, cmdErr := resolveBuildDir(args.BuildDir)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, "")
+		os.Exit(1)
+	}
+
+	env := debugEnvironment(buildDir)
+	command := debugShellCommand(debugShell(), args.Writable)
+
+	if args.PrintCommand {
+		log.Audit(printableCommand(buildDir, env, command))
+		return nil
+	}
+
+	if args.Writable {
+		log.Auditf("Opening a shell in '%s'. Changes made to the build directory will be persisted.", buildDir)
+	} else {
+		log.Auditf("Opening a shell in '%s'. The build directory is mounted as read-only.", buildDir)
+	}
+
+	shell := exec.Command(command[0], command[1:]...)
+	shell.Dir = buildDir
+	shell.Env = append(os.Environ(), env...)
+	shell.Stdin = os.Stdin
+	shell.Stdout = os.Stdout
+	shell.Stderr = os.Stderr
+
+	if err := shell.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// The exit code of the last command executed in the shell is not an EIB failure
+			return nil
+		}
+
+		log.AuditError("The debug shell could not be started. Use '--writable' if mount namespaces are not permitted.")
+		return fmt.Errorf("running debug shell: %w", err)
+	}
+
+	return nil
+}
+
+// resolveBuildDir makes the build directory absolute, as the shell is started within it while the environment
+// variables referencing it are used from any directory, and checks that it holds a build.
+func resolveBuildDir(buildDir string) (string, *cmd.Error) {
+	absBuildDir, err := filepath.Abs(buildDir)
+	if err != nil {
+		return "", &cmd.Error{
+			UserMessage: fmt.Sprintf("Unable to resolve the absolute path of the directory '%s': %v", buildDir, err),
+		}
+	}
+
+	if cmdErr := buildDirExists(absBuildDir); cmdErr != nil {
+		return "", cmdErr
+	}
+
+	return absBuildDir, nil
+}
+
+func buildDirExists(buildDir string) *cmd.Error {
+	combustionDir, artefactsDir := eib.CombustionDirectories(buildDir)
+
+	for _, dir := range []string{buildDir, combustionDir, artefactsDir} {
+		if _, err := os.Stat(dir); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return &cmd.Error{
+					UserMessage: fmt.Sprintf("The directory '%s' could not be found. Make sure to specify the directory "+
+						"of a particular build (e.g. '_build/build-Jan02_15-04-05').", dir),
+				}
+			}
+
+			// No log file is configured when debugging, the error is displayed directly instead
+			return &cmd.Error{
+				UserMessage: fmt.Sprintf("Unable to check the filesystem for the directory '%s': %v", dir, err),
+			}
+		}
+	}
+
+	return nil
+}
+
+func debugEnvironment(buildDir string) []string {
+	combustionDir, artefactsDir := eib.CombustionDirectories(buildDir)
+
+	return []string{
+		"EIB_BUILD_DIR=" + buildDir,
+		"EIB_COMBUSTION_DIR=" + combustionDir,
+		// Matches the variable referenced by the generated combustion scripts
+		"ARTEFACTS_DIR=" + artefactsDir,
+	}
+}
+
+func debugShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+
+	return defaultDebugShell
+}
+
+func debugShellCommand(shell string, writable bool) []string {
+	if writable {
+		return []string{shell}
+	}
+
+	return []string{"unshare", "--mount", "--map-root-user", "--", "/bin/sh", "-c", readOnlyShellScript, "eib-debug", shell}
+}
+
+func printableCommand(buildDir string, env, command []string) string {
+	parts := []string{"cd", shellQuote(buildDir), "&&", "env"}
+
+	for _, variable := range env {
+		parts = append(parts, shellQuote(variable))
+	}
+
+	for _, arg := range command {
+		parts = append(parts, shellQuote(arg))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\"'`$&|;<>()\\*?[]#~!{}") {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,148 @@
+package build
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		expected string
+	}{
+		"Plain": {
+			value:    "/builds/build-Jan02_15-04-05",
+			expected: "/builds/build-Jan02_15-04-05",
+		},
+		"Assignment": {
+			value:    "EIB_BUILD_DIR=/builds/eib",
+			expected: "EIB_BUILD_DIR=/builds/eib",
+		},
+		"Empty": {
+			value:    "",
+			expected: "''",
+		},
+		"Whitespace": {
+			value:    "/my builds/eib",
+			expected: "'/my builds/eib'",
+		},
+		"Single quote": {
+			value:    "it's",
+			expected: `'it'"'"'s'`,
+		},
+		"Expansion": {
+			value:    `$HOME/$(id)`,
+			expected: `'$HOME/$(id)'`,
+		},
+		"Glob": {
+			value:    "*.raw",
+			expected: "'*.raw'",
+		},
+		"Newline": {
+			value:    "a\nb",
+			expected: "'a\nb'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			quoted := shellQuote(test.value)
+			assert.Equal(t, test.expected, quoted)
+
+			// The shell has to read back the exact value
+			output, err := exec.Command("/bin/sh", "-c", "printf '%s' "+quoted).Output()
+			require.NoError(t, err)
+			assert.Equal(t, test.value, string(output))
+		})
+	}
+}
+
+func TestPrintableCommand(t *testing.T) {
+	tests := map[string]struct {
+		buildDir string
+		writable bool
+		expected string
+	}{
+		"Writable": {
+			buildDir: "/builds/eib",
+			writable: true,
+			expected: "cd /builds/eib && env EIB_BUILD_DIR=/builds/eib EIB_COMBUSTION_DIR=/builds/eib/combustion " +
+				"ARTEFACTS_DIR=/builds/eib/artefacts /bin/bash",
+		},
+		"Writable with spaces": {
+			buildDir: "/my builds/eib",
+			writable: true,
+			expected: "cd '/my builds/eib' && env 'EIB_BUILD_DIR=/my builds/eib' 'EIB_COMBUSTION_DIR=/my builds/eib/combustion' " +
+				"'ARTEFACTS_DIR=/my builds/eib/artefacts' /bin/bash",
+		},
+		"Read-only": {
+			buildDir: "/builds/eib",
+			expected: "cd /builds/eib && env EIB_BUILD_DIR=/builds/eib EIB_COMBUSTION_DIR=/builds/eib/combustion " +
+				"ARTEFACTS_DIR=/builds/eib/artefacts unshare --mount --map-root-user -- /bin/sh -c " +
+				`'mount --bind "$EIB_BUILD_DIR" "$EIB_BUILD_DIR" && mount -o remount,bind,ro "$EIB_BUILD_DIR" && exec "$1"' ` +
+				"eib-debug /bin/bash",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			command := debugShellCommand("/bin/bash", test.writable)
+			assert.Equal(t, test.expected, printableCommand(test.buildDir, debugEnvironment(test.buildDir), command))
+		})
+	}
+}
+
+func TestBuildDirExists(t *testing.T) {
+	buildDir := t.TempDir()
+
+	cmdErr := buildDirExists(buildDir)
+	require.NotNil(t, cmdErr)
+	assert.Contains(t, cmdErr.UserMessage, "The directory '"+filepath.Join(buildDir, "combustion")+"' could not be found.")
+
+	require.NoError(t, os.Mkdir(filepath.Join(buildDir, "combustion"), 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(buildDir, "artefacts"), 0o755))
+	assert.Nil(t, buildDirExists(buildDir))
+}
+
+func TestResolveBuildDir_Relative(t *testing.T) {
+	rootDir := t.TempDir()
+	buildDir := filepath.Join(rootDir, "_build", "build-Jan02_15-04-05")
+	require.NoError(t, os.MkdirAll(filepath.Join(buildDir, "combustion"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(buildDir, "artefacts"), 0o755))
+
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(rootDir))
+	defer func() {
+		require.NoError(t, os.Chdir(workingDir))
+	}()
+
+	resolved, cmdErr := resolveBuildDir(filepath.Join("_build", "build-Jan02_15-04-05"))
+	require.Nil(t, cmdErr)
+
+	// The shell runs within the build directory, relative paths would resolve against it instead
+	expectedDir, err := filepath.EvalSymlinks(buildDir)
+	require.NoError(t, err)
+	actualDir, err := filepath.EvalSymlinks(resolved)
+	require.NoError(t, err)
+	assert.True(t, filepath.IsAbs(resolved))
+	assert.Equal(t, expectedDir, actualDir)
+
+	assert.Equal(t, []string{
+		"EIB_BUILD_DIR=" + resolved,
+		"EIB_COMBUSTION_DIR=" + filepath.Join(resolved, "combustion"),
+		"ARTEFACTS_DIR=" + filepath.Join(resolved, "artefacts"),
+	}, debugEnvironment(resolved))
+}
+
+func TestResolveBuildDir_Missing(t *testing.T) {
+	_, cmdErr := resolveBuildDir(filepath.Join(t.TempDir(), "build-Jan02_15-04-05"))
+
+	require.NotNil(t, cmdErr)
+	assert.Contains(t, cmdErr.UserMessage, "could not be found")
+}
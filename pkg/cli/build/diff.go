@@ -0,0 +1,80 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Diff prints the fields which differ between two image definitions, one per line, to stdout.
+func Diff(ctx *cli.Context) error {
+	// No log file is configured when comparing definitions, the details of any error are displayed directly instead
+	if ctx.Args().Len() != 2 {
+		cmd.LogError(&cmd.Error{
+			UserMessage: "The 'diff' command requires exactly two definition files to compare.",
+		}, "")
+		os.Exit(1)
+	}
+
+	from, cmdErr := readDefinitionFile(ctx.Args().Get(0), cmd.DiffArgs.SchemaDir)
+	if cmdErr != nil {
+		cmd.LogError(inlineError(cmdErr), "")
+		os.Exit(1)
+	}
+
+	to, cmdErr := readDefinitionFile(ctx.Args().Get(1), cmd.DiffArgs.SchemaDir)
+	if cmdErr != nil {
+		cmd.LogError(inlineError(cmdErr), "")
+		os.Exit(1)
+	}
+
+	changes, err := image.DiffDefinitions(from, to)
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The image definitions could not be compared: %v", err),
+		}, "")
+		os.Exit(1)
+	}
+
+	if len(changes) == 0 {
+		log.Audit("The image definitions are equivalent.")
+		return nil
+	}
+
+	for _, change := range changes {
+		if _, err = fmt.Fprintln(os.Stdout, change.String()); err != nil {
+			return fmt.Errorf("writing definition diff: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readDefinitionFile parses the definition file, resolving its includes relative to the directory
+// containing it, which is the image configuration directory for definitions following the usual layout.
+func readDefinitionFile(definitionFilePath, schemaDir string) (*image.Definition, *cmd.Error) {
+	configData, err := os.ReadFile(definitionFilePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &cmd.Error{
+				UserMessage: fmt.Sprintf("The specified definition file '%s' could not be found.", definitionFilePath),
+			}
+		}
+
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified definition file '%s' could not be read.", definitionFilePath),
+			LogMessage:  fmt.Sprintf("Reading definition file failed: %v", err),
+		}
+	}
+
+	configDir, definitionFile := filepath.Split(definitionFilePath)
+
+	return parseDefinitionData(configData, configDir, definitionFile, schemaDir, fmt.Sprintf("file '%s'", definitionFilePath))
+}
@@ -0,0 +1,51 @@
+package build
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+)
+
+// explainBuild walks through the phases of the build for the validated definition, without running them.
+func explainBuild(ctx *image.Context, rootBuildDir string, phases eib.Phases) error {
+	explanation, err := eib.Explain(ctx, rootBuildDir, phases)
+	if err != nil {
+		return fmt.Errorf("explaining build: %w", err)
+	}
+
+	def := ctx.ImageDefinition
+	log.Auditf("The image definition is valid. Building '%s' (%s, %s) would run the following phases, "+
+		"nothing is downloaded or built with --explain.", def.Image.OutputImageName, def.Image.ImageType, def.Image.Arch)
+
+	for _, phase := range explanation.Phases {
+		log.Audit("")
+
+		if !phase.Enabled {
+			log.Auditf("==> %s: skipped", phase.Phase)
+			continue
+		}
+
+		log.Auditf("==> %s: %s.", phase.Phase, phase.Summary)
+		for _, step := range phase.Steps {
+			log.Auditf("  - %s", step)
+		}
+	}
+
+	log.Audit("")
+
+	if len(explanation.Tools) != 0 {
+		var tools []string
+		for _, tool := range explanation.Tools {
+			tools = append(tools, tool.Name)
+		}
+
+		log.Auditf("Host tools required by the build: %s.", strings.Join(tools, ", "))
+	}
+
+	log.Auditf("Estimated disk space required by the build, excluding the downloaded artefacts: %s.", explanation.RequiredSpace)
+
+	return nil
+}
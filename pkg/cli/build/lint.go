@@ -0,0 +1,106 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Lint reports the files in the image configuration directory which no part of the build consumes.
+// The findings are informational only and never affect the exit code.
+func Lint(_ *cli.Context) error {
+	args := &cmd.BuildArgs
+
+	if args.NoColor {
+		log.DisableColor()
+	}
+
+	// No log file is configured when linting, the details of any error are displayed directly instead
+	if cmdErr := imageConfigDirExists(args.ConfigDir); cmdErr != nil {
+		cmd.LogError(inlineError(cmdErr), "")
+		os.Exit(1)
+	}
+
+	imageDefinition, cmdErr := parseImageDefinition(args)
+	if cmdErr != nil {
+		cmd.LogError(inlineError(cmdErr), "")
+		os.Exit(1)
+	}
+
+	references, cmdErr := lintReferences(args.ConfigDir, args.DefinitionFile, imageDefinition)
+	if cmdErr != nil {
+		cmd.LogError(inlineError(cmdErr), "")
+		os.Exit(1)
+	}
+
+	unreferenced, err := combustion.UnreferencedFiles(args.ConfigDir, references)
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The image configuration directory could not be scanned: %v", err),
+		}, "")
+		os.Exit(1)
+	}
+
+	if len(unreferenced) == 0 {
+		log.Audit("All files in the image configuration directory are referenced by the image definition.")
+		return nil
+	}
+
+	log.Auditf("The following %d file(s) in the image configuration directory are not referenced by the image definition:",
+		len(unreferenced))
+	for _, file := range unreferenced {
+		log.Auditf("  %s", file)
+	}
+
+	return nil
+}
+
+// lintReferences adds the files consumed outside the combustion components to their references,
+// namely the definition along with its included fragments, the base image and the built image.
+func lintReferences(configDir, definitionFile string, definition *image.Definition) ([]combustion.Reference, *cmd.Error) {
+	data, err := os.ReadFile(filepath.Join(configDir, definitionFile))
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified definition file '%s' could not be read.", definitionFile),
+		}
+	}
+
+	included, err := image.IncludedFiles(data, configDir)
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The includes of the image definition could not be resolved: %v", err),
+		}
+	}
+
+	references := combustion.References(definition)
+	for _, path := range append([]string{definitionFile}, included...) {
+		references = append(references, combustion.Reference{Path: path})
+	}
+
+	if definition.Image.BaseImage != "" {
+		references = append(references, combustion.Reference{Path: filepath.Join("base-images", definition.Image.BaseImage)})
+	}
+
+	if definition.Image.OutputImageName != "" {
+		references = append(references, combustion.Reference{Path: definition.Image.OutputImageName})
+	}
+
+	return references, nil
+}
+
+// inlineError merges the details otherwise written to the log file into the displayed message.
+func inlineError(err *cmd.Error) *cmd.Error {
+	if err.LogMessage == "" {
+		return err
+	}
+
+	return &cmd.Error{
+		UserMessage: fmt.Sprintf("%s %s", err.UserMessage, err.LogMessage),
+	}
+}
@@ -0,0 +1,73 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+)
+
+func Migrate(_ *cli.Context) error {
+	args := &cmd.MigrateArgs
+
+	migrated, version, cmdErr := migrateDefinitionFile(args.DefinitionFile)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, "")
+		os.Exit(1)
+	}
+
+	if args.OutputFile == "" {
+		if _, err := os.Stdout.Write(migrated); err != nil {
+			return fmt.Errorf("writing migrated definition: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := os.WriteFile(args.OutputFile, migrated, fileio.NonExecutablePerms); err != nil {
+		// No log file is configured when migrating a definition, the error is displayed directly instead
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The migrated definition could not be written to '%s': %v", args.OutputFile, err),
+		}, "")
+		os.Exit(1)
+	}
+
+	if version == image.LatestAPIVersion {
+		log.Auditf("The image definition is already on schema version '%s' and was written to '%s'.", version, args.OutputFile)
+	} else {
+		log.Auditf("The image definition was migrated from schema version '%s' to '%s' and written to '%s'.",
+			version, image.LatestAPIVersion, args.OutputFile)
+	}
+
+	return nil
+}
+
+func migrateDefinitionFile(definitionFile string) ([]byte, string, *cmd.Error) {
+	data, err := os.ReadFile(definitionFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, "", &cmd.Error{
+				UserMessage: fmt.Sprintf("The specified definition file '%s' could not be found.", definitionFile),
+			}
+		}
+
+		return nil, "", &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified definition file '%s' could not be read: %v", definitionFile, err),
+		}
+	}
+
+	migrated, version, err := image.MigrateDefinition(data)
+	if err != nil {
+		return nil, "", &cmd.Error{
+			UserMessage: fmt.Sprintf("The definition file '%s' could not be migrated: %v", definitionFile, err),
+		}
+	}
+
+	return migrated, version, nil
+}
@@ -0,0 +1,79 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+	"go.uber.org/zap"
+)
+
+// validatePackerManifestPath checks ahead of the build that the Packer manifest can be written to the path,
+// which must be located in an existing directory and may only refer to an existing Packer manifest.
+func validatePackerManifestPath(path string) *cmd.Error {
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The directory of the Packer manifest '%s' does not exist.", path),
+			LogMessage:  fmt.Sprintf("Packer manifest directory not found for %s: %v", path, err),
+		}
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err != nil:
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The Packer manifest '%s' could not be read.", path),
+			LogMessage:  fmt.Sprintf("Reading Packer manifest file info failed: %v", err),
+		}
+	case info.IsDir():
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The Packer manifest '%s' must be a file rather than a directory.", path),
+			LogMessage:  fmt.Sprintf("Packer manifest path %s is a directory", path),
+		}
+	}
+
+	// Existing manifests are appended to, which is only possible if they are valid
+	if _, err = report.ReadPackerManifest(path); err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The existing file '%s' is not a valid Packer manifest.", path),
+			LogMessage:  fmt.Sprintf("Reading existing Packer manifest failed: %v", err),
+		}
+	}
+
+	return nil
+}
+
+// writePackerManifest records the built image, or the combustion ISO in combustion only mode, in the Packer manifest.
+func writePackerManifest(ctx *image.Context, buildReport *report.Report, path string) error {
+	artifactPath := filepath.Join(ctx.ImageConfigDir, ctx.ImageDefinition.Image.OutputImageName)
+	if ctx.CombustionISO != "" {
+		artifactPath = ctx.CombustionISO
+	}
+
+	info, err := os.Stat(artifactPath)
+	if err != nil {
+		return fmt.Errorf("reading artifact file info: %w", err)
+	}
+
+	build, err := report.NewPackerBuild(buildReport, []report.PackerFile{{Name: artifactPath, Size: info.Size()}})
+	if err != nil {
+		return fmt.Errorf("describing build: %w", err)
+	}
+
+	if err = report.WritePackerManifest(build, path); err != nil {
+		return err
+	}
+
+	log.Auditf("The Packer manifest was written to '%s'.", path)
+	zap.S().Infof("Packer manifest written to %s", path)
+
+	return nil
+}
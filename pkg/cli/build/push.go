@@ -0,0 +1,60 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/containers/image/v5/types"
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/oci"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+	"go.uber.org/zap"
+)
+
+const ociLayoutDir = "oci-artifact"
+
+// preparePush validates the push reference and the registry credentials ahead of the build,
+// so that a build is not wasted on a reference which cannot be pushed to.
+func preparePush(ref string) (types.ImageReference, *cmd.Error) {
+	imageRef, err := oci.ParseReference(ref)
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The push reference '%s' is invalid.", ref),
+			LogMessage:  fmt.Sprintf("Parsing push reference failed: %v", err),
+		}
+	}
+
+	err = oci.CheckAuth(context.Background(), &types.SystemContext{}, imageRef)
+	switch {
+	case errors.Is(err, oci.ErrNoCredentials):
+		log.Auditf("No credentials were found for '%s', the image will be pushed anonymously.", ref)
+	case err != nil:
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("Authenticating with the registry for '%s' failed.", ref),
+			LogMessage:  fmt.Sprintf("Checking registry authentication failed: %v", err),
+		}
+	}
+
+	return imageRef, nil
+}
+
+func pushImage(ctx *image.Context, imageRef types.ImageReference, buildReport *report.Report) error {
+	imagePath := filepath.Join(ctx.ImageConfigDir, ctx.ImageDefinition.Image.OutputImageName)
+	layoutDir := filepath.Join(ctx.BuildDir, ociLayoutDir)
+
+	log.Auditf("Pushing the image to '%s'...", imageRef.DockerReference())
+
+	pushedDigest, err := oci.Push(context.Background(), &types.SystemContext{}, imageRef, imagePath, buildReport, layoutDir)
+	if err != nil {
+		return err
+	}
+
+	log.Auditf("Pushed the image to '%s' with digest '%s'.", imageRef.DockerReference(), pushedDigest)
+	zap.S().Infof("Pushed artifact %s@%s", imageRef.DockerReference(), pushedDigest)
+
+	return nil
+}
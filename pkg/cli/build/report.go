@@ -0,0 +1,36 @@
+package build
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+	"go.uber.org/zap"
+)
+
+func validateReportFormat(format string) *cmd.Error {
+	if slices.Contains(report.Formats, format) {
+		return nil
+	}
+
+	return &cmd.Error{
+		UserMessage: fmt.Sprintf("The report format '%s' is invalid, it must be one of: %s.", format, strings.Join(report.Formats, ", ")),
+		LogMessage:  fmt.Sprintf("Unsupported report format: %s", format),
+	}
+}
+
+func writeReport(ctx *image.Context, buildReport *report.Report, format string) error {
+	path, err := report.Write(buildReport, ctx.BuildDir, format)
+	if err != nil {
+		return err
+	}
+
+	log.Auditf("The build report was written to '%s'.", path)
+	zap.S().Infof("Build report written to %s", path)
+
+	return nil
+}
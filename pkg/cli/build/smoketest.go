@@ -0,0 +1,63 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/smoketest"
+	"go.uber.org/zap"
+)
+
+const smokeTestLogFilename = "smoke-test.log"
+
+// prepareSmokeTest checks that the smoke test can be run ahead of the build and returns the path to QEMU.
+func prepareSmokeTest(definition *image.Definition) (string, *cmd.Error) {
+	if definition.Image.ImageType != image.TypeRAW {
+		return "", &cmd.Error{
+			UserMessage: "The smoke test is only supported for raw images.",
+		}
+	}
+
+	qemuPath, err := smoketest.FindQEMU(definition.Image.Arch)
+	if err != nil {
+		return "", &cmd.Error{
+			UserMessage: fmt.Sprintf("The smoke test requires QEMU for %s (qemu-system-%s), which could not be found.",
+				definition.Image.Arch, definition.Image.Arch),
+			LogMessage: fmt.Sprintf("Finding QEMU failed: %v", err),
+		}
+	}
+
+	return qemuPath, nil
+}
+
+func runSmokeTest(ctx *image.Context, qemuPath string, timeout time.Duration) error {
+	config := &smoketest.Config{
+		QEMUPath:    qemuPath,
+		ImagePath:   filepath.Join(ctx.ImageConfigDir, ctx.ImageDefinition.Image.OutputImageName),
+		ImageFormat: ctx.ImageDefinition.Image.OutputFormat,
+		Arch:        ctx.ImageDefinition.Image.Arch,
+		LogPath:     filepath.Join(ctx.BuildDir, smokeTestLogFilename),
+		Marker:      smoketest.DefaultMarker,
+		Timeout:     timeout,
+	}
+
+	log.Auditf("Running the smoke test, waiting up to %s for the image to boot...", timeout)
+
+	if err := smoketest.Run(config); err != nil {
+		if errors.Is(err, smoketest.ErrTimeout) {
+			log.Auditf("The image did not finish booting within %s.", timeout)
+		}
+
+		return err
+	}
+
+	log.Audit("Smoke test passed, the image booted successfully.")
+	zap.S().Infof("Smoke test boot log written to %s", config.LogPath)
+
+	return nil
+}
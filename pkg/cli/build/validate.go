@@ -4,15 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"slices"
 	"strings"
 	"time"
 
 	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
-	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
 )
 
 const (
@@ -22,6 +22,10 @@ const (
 func Validate(_ *cli.Context) error {
 	args := &cmd.BuildArgs
 
+	if args.NoColor {
+		log.DisableColor()
+	}
+
 	validationDir := filepath.Join(args.ConfigDir, "_validation")
 	if err := os.MkdirAll(validationDir, os.ModePerm); err != nil {
 		log.Auditf("The validation directory could not be setup under the configuration directory '%s'.", args.ConfigDir)
@@ -31,7 +35,7 @@ func Validate(_ *cli.Context) error {
 	// This needs to occur as early as possible so that the subsequent calls can use the log
 	timestamp := time.Now().Format("Jan02_15-04-05")
 	logFilename := filepath.Join(validationDir, fmt.Sprintf("eib-validate-%s.log", timestamp))
-	log.ConfigureGlobalLogger(logFilename)
+	log.ConfigureGlobalLogger(logFilename, log.Rotation{})
 
 	log.AuditInfo("Checking image config dir...")
 
@@ -42,61 +46,116 @@ func Validate(_ *cli.Context) error {
 
 	log.AuditInfo("Parsing image definition...")
 
-	imageDefinition, err := parseImageDefinition(args.ConfigDir, args.DefinitionFile)
+	imageDefinition, err := parseImageDefinition(args)
 	if err != nil {
 		cmd.LogError(err, checkValidationLogMessage)
 		os.Exit(1)
 	}
 
 	ctx := &image.Context{
-		ImageConfigDir:  args.ConfigDir,
-		ImageDefinition: imageDefinition,
+		ImageConfigDir:            args.ConfigDir,
+		ImageDefinition:           imageDefinition,
+		BaseImageOverride:         args.BaseImage,
+		PreserveScriptPermissions: args.PreserveScriptPermissions,
+		AllowArchMismatch:         args.AllowArchMismatch,
+		AllowCriticalRemovals:     args.AllowCriticalRemovals,
+		ForbidLatestTags:          args.ForbidLatestTags,
+		SkipChartImageCheck:       args.SkipChartImageCheck,
 	}
 
 	log.AuditInfo("Validating image definition...")
 
-	if err = validateImageDefinition(ctx); err != nil {
+	if err = validateImageDefinition(ctx, args.Strict, args.NoWarnings); err != nil {
 		cmd.LogError(err, checkValidationLogMessage)
 		os.Exit(1)
 	}
 
 	log.AuditInfo("The specified image definition is valid.")
 
+	if hash, hashErr := image.DefinitionHash(imageDefinition); hashErr == nil {
+		log.AuditInfof("Definition hash: %s", hash)
+	} else {
+		zap.S().Warnf("Hashing definition failed: %s", hashErr)
+	}
+
 	return nil
 }
 
-func validateImageDefinition(ctx *image.Context) *cmd.Error {
-	failedValidations := validation.ValidateDefinition(ctx)
-	if len(failedValidations) == 0 {
+// validateImageDefinition reports any warnings separately from the returned errors. Strict mode takes
+// precedence over noWarnings, in which case the warnings are returned alongside the errors instead.
+// Warnings are always written to the log file, even when they are not reported.
+func validateImageDefinition(ctx *image.Context, strict, noWarnings bool) *cmd.Error {
+	findings := eib.ValidateDefinition(ctx)
+	if len(findings) == 0 {
 		return nil
 	}
 
-	logMessageBuilder := strings.Builder{}
-	userMessageBuilder := strings.Builder{}
+	var errs, warnings []eib.Finding
+	for _, finding := range findings {
+		if finding.Severity == eib.SeverityWarning {
+			zap.S().Warnf("Image definition validation warning: %s", finding.Message)
+			warnings = append(warnings, finding)
+			continue
+		}
 
-	userMessageBuilder.WriteString("Image definition validation found the following errors:\n")
-	logMessageBuilder.WriteString("Image definition validation failures:\n")
+		errs = append(errs, finding)
+	}
+
+	if !strict {
+		if len(warnings) != 0 && !noWarnings {
+			log.Audit(formatFindings("Image definition validation found the following warnings:", warnings, nil))
+		}
 
-	orderedComponentNames := make([]string, 0, len(failedValidations))
-	for c := range failedValidations {
-		orderedComponentNames = append(orderedComponentNames, c)
+		warnings = nil
 	}
-	slices.Sort(orderedComponentNames)
 
-	for _, componentName := range orderedComponentNames {
-		userMessageBuilder.WriteString("  " + componentName + "\n")
+	if len(errs) == 0 && len(warnings) == 0 {
+		return nil
+	}
 
-		for _, cf := range failedValidations[componentName] {
-			userMessageBuilder.WriteString("    " + cf.UserMessage + "\n")
-			logMessageBuilder.WriteString("  " + cf.UserMessage + "\n")
-			if cf.Error != nil {
-				logMessageBuilder.WriteString("    " + cf.Error.Error() + "\n")
-			}
-		}
+	logMessageBuilder := strings.Builder{}
+	logMessageBuilder.WriteString("Image definition validation failures:\n")
+
+	var userMessages []string
+	if len(errs) != 0 {
+		userMessages = append(userMessages, formatFindings("Image definition validation found the following errors:",
+			errs, &logMessageBuilder))
+	}
+	if len(warnings) != 0 {
+		userMessages = append(userMessages, formatFindings("Image definition validation found the following warnings, "+
+			"which are treated as errors in strict mode:", warnings, &logMessageBuilder))
 	}
 
 	return &cmd.Error{
-		UserMessage: userMessageBuilder.String(),
+		UserMessage: strings.Join(userMessages, ""),
 		LogMessage:  logMessageBuilder.String(),
 	}
 }
+
+// formatFindings lists the findings, which are ordered by component, grouped by component, additionally
+// writing them along with their underlying errors to the log message builder if one is provided.
+func formatFindings(header string, findings []eib.Finding, logMessageBuilder *strings.Builder) string {
+	userMessageBuilder := strings.Builder{}
+	userMessageBuilder.WriteString(header + "\n")
+
+	var component string
+	for _, finding := range findings {
+		if finding.Component != component {
+			component = finding.Component
+			userMessageBuilder.WriteString("  " + component + "\n")
+		}
+
+		userMessageBuilder.WriteString("    " + finding.Message + "\n")
+
+		if logMessageBuilder == nil {
+			continue
+		}
+
+		logMessageBuilder.WriteString("  " + finding.Message + "\n")
+		if finding.Err != nil {
+			logMessageBuilder.WriteString("    " + finding.Err.Error() + "\n")
+		}
+	}
+
+	return userMessageBuilder.String()
+}
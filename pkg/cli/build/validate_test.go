@@ -0,0 +1,123 @@
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const installDeviceWarning = "The 'isoConfiguration/installDevice' field is not set in the 'operatingSystem' section, " +
+	"the installation will not run unattended and prompts for the disk to install to."
+
+// validationContext returns the context of an ISO definition whose only finding is the missing install device
+// warning, along with an error finding if withError is set.
+func validationContext(t *testing.T, withError bool) *image.Context {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "base-images", "base.iso"), []byte("iso"), 0o600))
+
+	definition := &image.Definition{
+		APIVersion: image.LatestAPIVersion,
+		Image: image.Image{
+			ImageType:       image.TypeISO,
+			Arch:            image.ArchTypeX86,
+			BaseImage:       "base.iso",
+			OutputImageName: "eib.iso",
+		},
+	}
+	if withError {
+		definition.Image.OutputImageName = ""
+	}
+
+	return &image.Context{ImageConfigDir: configDir, ImageDefinition: definition}
+}
+
+// observeWarnings replaces the global logger for the duration of the test, returning the logged warnings.
+func observeWarnings(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zap.WarnLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+
+	return logs
+}
+
+func TestValidateImageDefinition(t *testing.T) {
+	tests := map[string]struct {
+		withError           bool
+		strict              bool
+		noWarnings          bool
+		expectedError       bool
+		expectedWarnings    bool
+		expectedErrorReport bool
+	}{
+		"Warnings": {},
+		"Warnings without reporting them": {
+			noWarnings: true,
+		},
+		"Warnings in strict mode": {
+			strict:           true,
+			expectedError:    true,
+			expectedWarnings: true,
+		},
+		"Warnings in strict mode without reporting them": {
+			strict:           true,
+			noWarnings:       true,
+			expectedError:    true,
+			expectedWarnings: true,
+		},
+		"Errors and warnings": {
+			withError:           true,
+			expectedError:       true,
+			expectedErrorReport: true,
+		},
+		"Errors and warnings without reporting them": {
+			withError:           true,
+			noWarnings:          true,
+			expectedError:       true,
+			expectedErrorReport: true,
+		},
+		"Errors and warnings in strict mode": {
+			withError:           true,
+			strict:              true,
+			expectedError:       true,
+			expectedWarnings:    true,
+			expectedErrorReport: true,
+		},
+		"Errors and warnings in strict mode without reporting them": {
+			withError:           true,
+			strict:              true,
+			noWarnings:          true,
+			expectedError:       true,
+			expectedWarnings:    true,
+			expectedErrorReport: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logs := observeWarnings(t)
+
+			cmdErr := validateImageDefinition(validationContext(t, test.withError), test.strict, test.noWarnings)
+
+			// Warnings are written to the log file whether they are reported or not
+			assert.Equal(t, 1, logs.FilterMessage("Image definition validation warning: "+installDeviceWarning).Len())
+
+			if !test.expectedError {
+				assert.Nil(t, cmdErr)
+				return
+			}
+
+			require.NotNil(t, cmdErr)
+			assert.Equal(t, test.expectedErrorReport,
+				strings.Contains(cmdErr.UserMessage, "Image definition validation found the following errors:"))
+			assert.Equal(t, test.expectedWarnings,
+				strings.Contains(cmdErr.UserMessage, "which are treated as errors in strict mode:\n  Image\n    "+installDeviceWarning))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/urfave/cli/v2"
+)
+
+// uncachedArtefacts are the artefacts required by a definition which are missing from the cache.
+type uncachedArtefacts struct {
+	definitionFile string
+	identifiers    []string
+}
+
+func VerifyCache(_ *cli.Context) error {
+	args := &cmd.VerifyCacheArgs
+
+	if cmdErr := cacheDirExists(args.RootBuildDir); cmdErr != nil {
+		cmd.LogError(cmdErr, "")
+		os.Exit(1)
+	}
+
+	// The cache may be in use by builds, it is therefore only read
+	c, err := cache.Open(args.RootBuildDir)
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The cache could not be opened: %v", err),
+		}, "")
+		os.Exit(1)
+	}
+
+	verified, discrepancies, err := c.Verify()
+	if err != nil {
+		cmd.LogError(&cmd.Error{
+			UserMessage: fmt.Sprintf("The cached artefacts could not be verified: %v", err),
+		}, "")
+		os.Exit(1)
+	}
+
+	uncached, cmdErr := findUncachedArtefacts(c, args.ConfigDir, args.DefinitionFiles.Value(), args.SchemaDir)
+	if cmdErr != nil {
+		cmd.LogError(cmdErr, "")
+		os.Exit(1)
+	}
+
+	if len(discrepancies) == 0 && len(uncached) == 0 {
+		log.Auditf("All %d cached artefact(s) match their recorded digests.", verified)
+		if len(args.DefinitionFiles.Value()) != 0 {
+			log.Audit("All artefacts required by the given definition(s) are cached.")
+		}
+		return nil
+	}
+
+	if len(discrepancies) != 0 {
+		log.Auditf("Cache verification found %d discrepancy(ies) among %d cached artefact(s):", len(discrepancies), verified)
+		for _, d := range discrepancies {
+			log.Audit(describeDiscrepancy(&d))
+		}
+	}
+
+	for _, u := range uncached {
+		log.Auditf("The definition '%s' requires %d artefact(s) missing from the cache:", u.definitionFile, len(u.identifiers))
+		for _, identifier := range u.identifiers {
+			log.Auditf("  %s", identifier)
+		}
+	}
+
+	os.Exit(1)
+	return nil
+}
+
+// findUncachedArtefacts returns the artefacts which the given definitions require but the cache lacks,
+// omitting the definitions whose artefacts are all cached.
+func findUncachedArtefacts(c *cache.Cache, configDir string, definitionFiles []string, schemaDir string) ([]uncachedArtefacts, *cmd.Error) {
+	if len(definitionFiles) == 0 {
+		return nil, nil
+	}
+
+	if cmdErr := imageConfigDirExists(configDir); cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	var uncached []uncachedArtefacts
+	for _, definitionFile := range definitionFiles {
+		target, cmdErr := parseWarmTarget(configDir, definitionFile, schemaDir)
+		if cmdErr != nil {
+			return nil, cmdErr
+		}
+
+		required, err := eib.CachedArtefacts(target)
+		if err != nil {
+			return nil, &cmd.Error{
+				UserMessage: fmt.Sprintf("The artefacts required by the definition '%s' could not be determined: %v", definitionFile, err),
+			}
+		}
+
+		identifiers, err := c.Uncached(required)
+		if err != nil {
+			return nil, &cmd.Error{
+				UserMessage: fmt.Sprintf("The cache could not be read: %v", err),
+			}
+		}
+
+		if len(identifiers) != 0 {
+			uncached = append(uncached, uncachedArtefacts{definitionFile: definitionFile, identifiers: identifiers})
+		}
+	}
+
+	return uncached, nil
+}
+
+func describeDiscrepancy(d *cache.Discrepancy) string {
+	if d.ActualDigest == "" {
+		return fmt.Sprintf("  %s: missing (expected digest %s)", d.Identifier, d.ExpectedDigest)
+	}
+
+	return fmt.Sprintf("  %s: digest mismatch (expected %s, found %s)", d.Identifier, d.ExpectedDigest, d.ActualDigest)
+}
+
+func cacheDirExists(rootBuildDir string) *cmd.Error {
+	cacheDir := cache.Dir(rootBuildDir)
+
+	_, err := os.Stat(cacheDir)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("No cache was found under the build directory '%s'.", rootBuildDir),
+		}
+	}
+
+	// No log file is configured when verifying the cache, the error is displayed directly instead
+	return &cmd.Error{
+		UserMessage: fmt.Sprintf("Unable to check the filesystem for the cache directory '%s': %v", cacheDir, err),
+	}
+}
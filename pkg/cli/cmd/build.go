@@ -2,14 +2,46 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/urfave/cli/v2"
 )
 
 type BuildFlags struct {
-	DefinitionFile string
-	ConfigDir      string
-	RootBuildDir   string
+	DefinitionFile            string
+	DefinitionStdin           bool
+	ConfigDir                 string
+	SchemaDir                 string
+	RootBuildDir              string
+	BaseImage                 string
+	Strict                    bool
+	NoWarnings                bool
+	PreserveScriptPermissions bool
+	AllowArchMismatch         bool
+	AllowCriticalRemovals     bool
+	ForbidLatestTags          bool
+	SkipChartImageCheck       bool
+	NoColor                   bool
+	LogMaxSize                int
+	LogMaxAge                 time.Duration
+	LogMaxBackups             int
+	Push                      string
+	Reproducible              bool
+	SmokeTest                 bool
+	SmokeTestTimeout          time.Duration
+	Quiet                     bool
+	ReportFormat              string
+	MaxBandwidth              string
+	OnlyPhases                string
+	SkipPhases                string
+	SummaryOnly               bool
+	CombustionOnly            bool
+	SkipSpaceCheck            bool
+	PackerManifest            string
+	ProfileArtifacts          bool
+	Explain                   bool
+	ExpectHash                string
+	ToolArgs                  cli.StringSlice
 }
 
 var BuildArgs BuildFlags
@@ -22,12 +54,126 @@ func NewBuildCommand(action func(*cli.Context) error) *cli.Command {
 		Action:    action,
 		Flags: []cli.Flag{
 			DefinitionFileFlag,
+			DefinitionStdinFlag,
 			ConfigDirFlag,
+			SchemaDirFlag,
+			BaseImageFlag,
+			StrictFlag,
+			NoWarningsFlag,
+			PreserveScriptPermissionsFlag,
+			AllowArchMismatchFlag,
+			AllowCriticalRemovalsFlag,
+			ForbidLatestTagsFlag,
+			SkipChartImageCheckFlag,
+			NoColorFlag,
 			&cli.StringFlag{
 				Name:        "build-dir",
 				Usage:       "Full path to the directory to store build artifacts",
 				Destination: &BuildArgs.RootBuildDir,
 			},
+			&cli.IntFlag{
+				Name:        "log-max-size",
+				Usage:       "Size in megabytes after which the build log is rotated (disabled by default)",
+				Destination: &BuildArgs.LogMaxSize,
+			},
+			&cli.DurationFlag{
+				Name:        "log-max-age",
+				Usage:       "Duration (e.g. 30m) after which the build log is rotated (disabled by default)",
+				Destination: &BuildArgs.LogMaxAge,
+			},
+			&cli.IntFlag{
+				Name:        "log-max-backups",
+				Usage:       "Number of rotated build logs to retain",
+				Value:       1,
+				Destination: &BuildArgs.LogMaxBackups,
+			},
+			&cli.StringFlag{
+				Name:        "push",
+				Usage:       "Registry reference (e.g. registry.example.com/images/edge:1.0) to push the built image to as an OCI artifact",
+				Destination: &BuildArgs.Push,
+			},
+			&cli.BoolFlag{
+				Name:        "reproducible",
+				Usage:       "Use fixed timestamps for the generated content, taken from SOURCE_DATE_EPOCH if set or the Unix epoch otherwise",
+				Destination: &BuildArgs.Reproducible,
+			},
+			&cli.BoolFlag{
+				Name:        "smoke-test",
+				Usage:       "Boot the built raw image in QEMU and fail if it does not boot successfully",
+				Destination: &BuildArgs.SmokeTest,
+			},
+			&cli.DurationFlag{
+				Name:        "smoke-test-timeout",
+				Usage:       "Duration to wait for the image to boot during the smoke test",
+				Value:       10 * time.Minute,
+				Destination: &BuildArgs.SmokeTestTimeout,
+			},
+			&cli.BoolFlag{
+				Name:        "quiet",
+				Usage:       "Do not report the progress of downloads, which is otherwise shown as progress bars when attached to a terminal",
+				Destination: &BuildArgs.Quiet,
+			},
+			&cli.StringFlag{
+				Name:        "report-format",
+				Usage:       "Format of the build report written to the build directory (json or yaml)",
+				Value:       "json",
+				Destination: &BuildArgs.ReportFormat,
+			},
+			&cli.StringFlag{
+				Name:        "max-bandwidth",
+				Usage:       "Limit the aggregate download throughput (e.g. 10MB/s or 512KiB/s, unlimited by default)",
+				Destination: &BuildArgs.MaxBandwidth,
+			},
+			&cli.StringFlag{
+				Name:        "only",
+				Usage:       "Comma-separated build phases to run exclusively (download, combustion, assembly)",
+				Destination: &BuildArgs.OnlyPhases,
+			},
+			&cli.StringFlag{
+				Name:        "skip",
+				Usage:       "Comma-separated build phases to skip (download, combustion, assembly)",
+				Destination: &BuildArgs.SkipPhases,
+			},
+			&cli.BoolFlag{
+				Name:        "summary-only",
+				Usage:       "Display only the cause of a failed build on a single line, leaving the details to the build log",
+				Destination: &BuildArgs.SummaryOnly,
+			},
+			&cli.BoolFlag{
+				Name:        "combustion-only",
+				Usage:       "Package only the combustion content into an ISO, without assembling the image from the base image",
+				Destination: &BuildArgs.CombustionOnly,
+			},
+			&cli.BoolFlag{
+				Name:        "skip-space-check",
+				Usage:       "Do not check that the build and output directories have the disk space the build is estimated to require",
+				Destination: &BuildArgs.SkipSpaceCheck,
+			},
+			&cli.StringFlag{
+				Name:        "packer-manifest",
+				Usage:       "Path to a Packer manifest to record the built image in, which is created or appended to",
+				Destination: &BuildArgs.PackerManifest,
+			},
+			&cli.BoolFlag{
+				Name:        "profile-artifacts",
+				Usage:       "Record the duration of each artifact download in the build report and list the slowest downloads",
+				Destination: &BuildArgs.ProfileArtifacts,
+			},
+			&cli.BoolFlag{
+				Name:        "explain",
+				Usage:       "Describe what each build phase will do for the image definition once it is validated, without building the image",
+				Destination: &BuildArgs.Explain,
+			},
+			&cli.StringFlag{
+				Name:        "expect-hash",
+				Usage:       "Fail the build unless the image definition, with its includes resolved, has the given SHA-256 hash",
+				Destination: &BuildArgs.ExpectHash,
+			},
+			&cli.StringSliceFlag{
+				Name:        "tool-arg",
+				Usage:       "Unsupported; 'key=value' option forwarded to the tooling of the build, restricted to an allowlist of keys (can be repeated)",
+				Destination: &BuildArgs.ToolArgs,
+			},
 		},
 	}
 }
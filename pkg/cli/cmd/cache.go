@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+type CacheWarmFlags struct {
+	ConfigDir       string
+	DefinitionFiles cli.StringSlice
+	RootBuildDir    string
+	Parallel        int
+	SchemaDir       string
+}
+
+var CacheWarmArgs CacheWarmFlags
+
+func NewCacheCommand(warmAction func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Manage the artefact cache shared between builds",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "warm",
+				Usage:     "Download the cacheable artefacts of the given image definitions into the cache without building them",
+				UsageText: fmt.Sprintf("%s cache warm --build-dir <dir> --definition-file <file> [--definition-file <file> ...] [OPTIONS]", appName),
+				Action:    warmAction,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:        "definition-file",
+						Usage:       "Name of an image definition file, relative to the configuration directory (can be repeated)",
+						Required:    true,
+						Destination: &CacheWarmArgs.DefinitionFiles,
+					},
+					&cli.StringFlag{
+						Name:        "config-dir",
+						Usage:       "Full path to the image configuration directory",
+						Value:       "/eib",
+						Destination: &CacheWarmArgs.ConfigDir,
+					},
+					&cli.StringFlag{
+						Name:        "build-dir",
+						Usage:       "Full path to the directory builds store their artifacts in, holding the cache (e.g. '/eib/_build')",
+						Required:    true,
+						Destination: &CacheWarmArgs.RootBuildDir,
+					},
+					&cli.IntFlag{
+						Name:        "parallel",
+						Usage:       "Number of definitions whose artefacts are downloaded at the same time (unlimited if 0)",
+						Value:       2,
+						Destination: &CacheWarmArgs.Parallel,
+					},
+					&cli.StringFlag{
+						Name:        "schema-dir",
+						Usage:       "Full path to a directory of JSON schemas, named after the definition apiVersion, to validate the image definitions against",
+						Destination: &CacheWarmArgs.SchemaDir,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+type DebugFlags struct {
+	BuildDir     string
+	PrintCommand bool
+	Writable     bool
+}
+
+var DebugArgs DebugFlags
+
+func NewDebugCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "debug",
+		Usage:     "Open a shell in the build directory of a previous build",
+		UsageText: fmt.Sprintf("%s debug --build-dir <dir> [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "build-dir",
+				Usage:       "Path to the directory of a particular build, either absolute or relative to the working directory (e.g. '_build/build-Jan02_15-04-05')",
+				Required:    true,
+				Destination: &DebugArgs.BuildDir,
+			},
+			&cli.BoolFlag{
+				Name:        "print-command",
+				Usage:       "Print the command opening the shell instead of running it",
+				Destination: &DebugArgs.PrintCommand,
+			},
+			&cli.BoolFlag{
+				Name:        "writable",
+				Usage:       "Allow modifying the contents of the build directory from the shell",
+				Destination: &DebugArgs.Writable,
+			},
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+type DiffFlags struct {
+	SchemaDir string
+}
+
+var DiffArgs DiffFlags
+
+func NewDiffCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Compare two image definitions field by field, ignoring their formatting and key ordering",
+		UsageText: fmt.Sprintf("%s diff [OPTIONS] <definition-file> <other-definition-file>", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "schema-dir",
+				Usage:       "Full path to a directory of JSON schemas, named after the definition apiVersion, to validate the image definitions against",
+				Destination: &DiffArgs.SchemaDir,
+			},
+		},
+	}
+}
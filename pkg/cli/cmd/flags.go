@@ -8,10 +8,66 @@ var (
 		Usage:       "Name of the image definition file",
 		Destination: &BuildArgs.DefinitionFile,
 	}
+	DefinitionStdinFlag = &cli.BoolFlag{
+		Name:        "definition-stdin",
+		Usage:       "Read the image definition from stdin instead of a definition file",
+		Destination: &BuildArgs.DefinitionStdin,
+	}
 	ConfigDirFlag = &cli.StringFlag{
 		Name:        "config-dir",
 		Usage:       "Full path to the image configuration directory",
 		Value:       "/eib",
 		Destination: &BuildArgs.ConfigDir,
 	}
+	SchemaDirFlag = &cli.StringFlag{
+		Name:        "schema-dir",
+		Usage:       "Full path to a directory of JSON schemas, named after the definition apiVersion, to validate the image definition against",
+		Destination: &BuildArgs.SchemaDir,
+	}
+	BaseImageFlag = &cli.StringFlag{
+		Name:        "base-image",
+		Usage:       "Full path to a local base image overriding the one specified in the image definition",
+		Destination: &BuildArgs.BaseImage,
+	}
+	StrictFlag = &cli.BoolFlag{
+		Name:        "strict",
+		Usage:       "Treat image definition validation warnings as errors",
+		Destination: &BuildArgs.Strict,
+	}
+	NoWarningsFlag = &cli.BoolFlag{
+		Name:        "no-warnings",
+		Usage:       "Do not report image definition validation warnings (ignored with --strict)",
+		Destination: &BuildArgs.NoWarnings,
+	}
+	PreserveScriptPermissionsFlag = &cli.BoolFlag{
+		Name:        "preserve-script-permissions",
+		Usage:       "Do not make non-executable custom scripts executable, warning about them instead",
+		Destination: &BuildArgs.PreserveScriptPermissions,
+	}
+	AllowArchMismatchFlag = &cli.BoolFlag{
+		Name:        "allow-arch-mismatch",
+		Usage:       "Warn instead of failing when the base image is built for a different architecture than requested",
+		Destination: &BuildArgs.AllowArchMismatch,
+	}
+	AllowCriticalRemovalsFlag = &cli.BoolFlag{
+		Name:        "allow-critical-removals",
+		Usage:       "Warn instead of failing when critical system paths are removed from the base image",
+		Destination: &BuildArgs.AllowCriticalRemovals,
+	}
+	ForbidLatestTagsFlag = &cli.BoolFlag{
+		Name:        "forbid-latest-tags",
+		Usage:       "Fail instead of warning when embedded images use the mutable 'latest' tag, explicitly or by omitting the tag",
+		EnvVars:     []string{"EIB_FORBID_LATEST_TAGS"},
+		Destination: &BuildArgs.ForbidLatestTags,
+	}
+	SkipChartImageCheckFlag = &cli.BoolFlag{
+		Name:        "skip-chart-image-check",
+		Usage:       "Do not check the images referenced by Helm chart values against the embedded images pinned by digest",
+		Destination: &BuildArgs.SkipChartImageCheck,
+	}
+	NoColorFlag = &cli.BoolFlag{
+		Name:        "no-color",
+		Usage:       "Disable colored console output",
+		Destination: &BuildArgs.NoColor,
+	}
 )
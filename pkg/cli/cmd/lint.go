@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+func NewLintCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "lint",
+		Usage:     "Report files in the image configuration directory which are not referenced by the image definition",
+		UsageText: fmt.Sprintf("%s lint [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			DefinitionFileFlag,
+			ConfigDirFlag,
+			SchemaDirFlag,
+			NoColorFlag,
+		},
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+type MigrateFlags struct {
+	DefinitionFile string
+	OutputFile     string
+}
+
+var MigrateArgs MigrateFlags
+
+func NewMigrateCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "migrate",
+		Usage:     "Upgrade an image definition to the latest definition schema version",
+		UsageText: fmt.Sprintf("%s migrate --definition-file <file> [--output <file>]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "definition-file",
+				Usage:       "Path to the image definition file to migrate",
+				Required:    true,
+				Destination: &MigrateArgs.DefinitionFile,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Usage:       "Path to write the migrated image definition to, instead of printing it to stdout",
+				Destination: &MigrateArgs.OutputFile,
+			},
+		},
+	}
+}
@@ -14,7 +14,18 @@ func NewValidateCommand(action func(*cli.Context) error) *cli.Command {
 		Action:    action,
 		Flags: []cli.Flag{
 			DefinitionFileFlag,
+			DefinitionStdinFlag,
 			ConfigDirFlag,
+			SchemaDirFlag,
+			BaseImageFlag,
+			StrictFlag,
+			NoWarningsFlag,
+			PreserveScriptPermissionsFlag,
+			AllowArchMismatchFlag,
+			AllowCriticalRemovalsFlag,
+			ForbidLatestTagsFlag,
+			SkipChartImageCheckFlag,
+			NoColorFlag,
 		},
 	}
 }
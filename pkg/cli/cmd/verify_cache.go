@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+type VerifyCacheFlags struct {
+	RootBuildDir    string
+	ConfigDir       string
+	DefinitionFiles cli.StringSlice
+	SchemaDir       string
+}
+
+var VerifyCacheArgs VerifyCacheFlags
+
+func NewVerifyCacheCommand(action func(*cli.Context) error) *cli.Command {
+	return &cli.Command{
+		Name:      "verify-cache",
+		Usage:     "Verify the cached artefacts against their recorded digests without downloading them",
+		UsageText: fmt.Sprintf("%s verify-cache --build-dir <dir> [--definition-file <file> ...] [OPTIONS]", appName),
+		Action:    action,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "build-dir",
+				Usage:       "Full path to the directory builds store their artifacts in, holding the cache (e.g. '/eib/_build')",
+				Required:    true,
+				Destination: &VerifyCacheArgs.RootBuildDir,
+			},
+			&cli.StringSliceFlag{
+				Name:        "definition-file",
+				Usage:       "Name of an image definition file, relative to the configuration directory, whose required artefacts must be cached (can be repeated)",
+				Destination: &VerifyCacheArgs.DefinitionFiles,
+			},
+			&cli.StringFlag{
+				Name:        "config-dir",
+				Usage:       "Full path to the image configuration directory",
+				Value:       "/eib",
+				Destination: &VerifyCacheArgs.ConfigDir,
+			},
+			&cli.StringFlag{
+				Name:        "schema-dir",
+				Usage:       "Full path to a directory of JSON schemas, named after the definition apiVersion, to validate the image definitions against",
+				Destination: &VerifyCacheArgs.SchemaDir,
+			},
+		},
+	}
+}
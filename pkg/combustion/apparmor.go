@@ -0,0 +1,86 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	apparmorComponentName = "apparmor"
+	apparmorScriptName    = "17-apparmor.sh"
+	AppArmorConfigDir     = "apparmor"
+)
+
+//go:embed templates/17-apparmor.sh.tpl
+var apparmorScriptTemplate string
+
+func configureAppArmor(ctx *image.Context) ([]string, error) {
+	profiles := ctx.ImageDefinition.OperatingSystem.AppArmor.Profiles
+	if len(profiles) == 0 {
+		log.AuditComponentSkipped(apparmorComponentName)
+		return nil, nil
+	}
+
+	if err := copyAppArmorProfiles(ctx, profiles); err != nil {
+		log.AuditComponentFailed(apparmorComponentName)
+		return nil, err
+	}
+
+	if err := writeAppArmorScript(ctx, profiles); err != nil {
+		log.AuditComponentFailed(apparmorComponentName)
+		return nil, err
+	}
+
+	log.AuditInfof("Embedded AppArmor profiles [%s]; they are only installed if AppArmor is the active LSM on the node.",
+		strings.Join(profiles, ", "))
+
+	log.AuditComponentSuccessful(apparmorComponentName)
+	return []string{apparmorScriptName}, nil
+}
+
+func copyAppArmorProfiles(ctx *image.Context, profiles []string) error {
+	srcDir := generateComponentPath(ctx, AppArmorConfigDir)
+	destDir := filepath.Join(ctx.CombustionDir, AppArmorConfigDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating apparmor directory '%s': %w", destDir, err)
+	}
+
+	for _, profile := range profiles {
+		if err := fileio.CopyFile(filepath.Join(srcDir, profile), filepath.Join(destDir, profile), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying apparmor profile '%s': %w", profile, err)
+		}
+	}
+
+	return nil
+}
+
+func writeAppArmorScript(ctx *image.Context, profiles []string) error {
+	values := struct {
+		AppArmorDir string
+		Profiles    []string
+	}{
+		AppArmorDir: AppArmorConfigDir,
+		Profiles:    profiles,
+	}
+
+	data, err := template.Parse(apparmorScriptName, apparmorScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", apparmorScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, apparmorScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
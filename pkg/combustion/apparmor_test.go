@@ -0,0 +1,76 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureAppArmor_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureAppArmor(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureAppArmor(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, AppArmorConfigDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "usr.bin.workload"), []byte("/usr/bin/workload {\n}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "unused"), []byte("profile unused {\n}\n"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			AppArmor: image.AppArmor{
+				Profiles: []string{"usr.bin.workload"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureAppArmor(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, apparmorScriptName, scripts[0])
+
+	// - profiles
+	destDir := filepath.Join(ctx.CombustionDir, AppArmorConfigDir)
+	assert.FileExists(t, filepath.Join(destDir, "usr.bin.workload"))
+	assert.NoFileExists(t, filepath.Join(destDir, "unused"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, apparmorScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "grep -qw apparmor /sys/kernel/security/lsm")
+	assert.Contains(t, foundContents, "install -m 644 ./apparmor/usr.bin.workload /etc/apparmor.d/usr.bin.workload")
+	assert.Contains(t, foundContents, "apparmor_parser --replace /etc/apparmor.d/usr.bin.workload")
+	assert.Contains(t, foundContents, "systemctl enable apparmor.service")
+	assert.NotContains(t, foundContents, "unused")
+}
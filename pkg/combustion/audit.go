@@ -0,0 +1,126 @@
+package combustion
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	auditComponentName = "audit"
+	auditScriptName    = "16-audit.sh"
+	AuditConfigDir     = "audit"
+	// AuditInlineRulesFile holds the rules specified directly in the definition. It is sorted ahead of
+	// the '99-*' files conventionally used to finalize (e.g. lock) the audit configuration.
+	AuditInlineRulesFile = "50-eib.rules"
+)
+
+//go:embed templates/16-audit.sh.tpl
+var auditScriptTemplate string
+
+func configureAudit(ctx *image.Context) ([]string, error) {
+	audit := ctx.ImageDefinition.OperatingSystem.Audit
+	if len(audit.RuleFiles) == 0 && len(audit.Rules) == 0 {
+		log.AuditComponentSkipped(auditComponentName)
+		return nil, nil
+	}
+
+	fileRules, err := copyAuditRuleFiles(ctx, audit.RuleFiles)
+	if err != nil {
+		log.AuditComponentFailed(auditComponentName)
+		return nil, err
+	}
+
+	if err = writeAuditScript(ctx, &audit); err != nil {
+		log.AuditComponentFailed(auditComponentName)
+		return nil, err
+	}
+
+	log.AuditInfof("Embedded %d audit rule(s) from rule files [%s] and %d inline audit rule(s).",
+		fileRules, strings.Join(audit.RuleFiles, ", "), len(audit.Rules))
+
+	log.AuditComponentSuccessful(auditComponentName)
+	return []string{auditScriptName}, nil
+}
+
+// copyAuditRuleFiles copies the rule files into the combustion directory and returns the number of rules they contain.
+func copyAuditRuleFiles(ctx *image.Context, ruleFiles []string) (int, error) {
+	if len(ruleFiles) == 0 {
+		return 0, nil
+	}
+
+	srcDir := generateComponentPath(ctx, AuditConfigDir)
+	destDir := filepath.Join(ctx.CombustionDir, AuditConfigDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return 0, fmt.Errorf("creating audit directory '%s': %w", destDir, err)
+	}
+
+	var ruleCount int
+	for _, ruleFile := range ruleFiles {
+		data, err := os.ReadFile(filepath.Join(srcDir, ruleFile))
+		if err != nil {
+			return 0, fmt.Errorf("reading audit rule file '%s': %w", ruleFile, err)
+		}
+
+		if err = os.WriteFile(filepath.Join(destDir, ruleFile), data, fileio.NonExecutablePerms); err != nil {
+			return 0, fmt.Errorf("copying audit rule file '%s': %w", ruleFile, err)
+		}
+
+		ruleCount += len(AuditRules(data))
+	}
+
+	return ruleCount, nil
+}
+
+func writeAuditScript(ctx *image.Context, audit *image.Audit) error {
+	values := struct {
+		AuditDir        string
+		RuleFiles       []string
+		Rules           []string
+		InlineRulesFile string
+	}{
+		AuditDir:        AuditConfigDir,
+		RuleFiles:       audit.RuleFiles,
+		Rules:           audit.Rules,
+		InlineRulesFile: AuditInlineRulesFile,
+	}
+
+	data, err := template.Parse(auditScriptName, auditScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", auditScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, auditScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
+
+// AuditRules returns the rules in the contents of an audit rule file, skipping blank lines and comments.
+func AuditRules(data []byte) []string {
+	var rules []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rules = append(rules, line)
+	}
+
+	return rules
+}
@@ -0,0 +1,112 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureAudit_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureAudit(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureAudit(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, AuditConfigDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "30-stig.rules"),
+		[]byte("# STIG rules\n-w /etc/passwd -p wa -k identity\n\n-a always,exit -F arch=b64 -S adjtimex -k time-change\n"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Audit: image.Audit{
+				RuleFiles: []string{"30-stig.rules"},
+				Rules:     []string{"-w /etc/shadow -p wa -k shadow"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureAudit(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, auditScriptName, scripts[0])
+
+	// - rule files
+	copiedRules := filepath.Join(ctx.CombustionDir, AuditConfigDir, "30-stig.rules")
+	assert.FileExists(t, copiedRules)
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, auditScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "install -m 600 ./audit/30-stig.rules /etc/audit/rules.d/30-stig.rules")
+	assert.Contains(t, foundContents, "cat <<'EOF' > /etc/audit/rules.d/50-eib.rules\n-w /etc/shadow -p wa -k shadow\nEOF")
+	assert.Contains(t, foundContents, "systemctl enable auditd.service")
+}
+
+func TestConfigureAudit_RuleFilesOnly(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, AuditConfigDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "10-base.rules"), []byte("-D\n"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Audit: image.Audit{
+				RuleFiles: []string{"10-base.rules"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureAudit(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, auditScriptName))
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(foundBytes), AuditInlineRulesFile)
+}
+
+func TestAuditRules(t *testing.T) {
+	data := []byte("# comment\n\n  -D  \n-b 8192\n   # indented comment\n-w /etc/sudoers -p wa -k scope\n")
+
+	rules := AuditRules(data)
+
+	assert.Equal(t, []string{"-D", "-b 8192", "-w /etc/sudoers -p wa -k scope"}, rules)
+}
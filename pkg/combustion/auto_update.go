@@ -0,0 +1,64 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	autoUpdateComponentName = "auto update"
+	autoUpdateScriptName    = "18-auto-update.sh"
+)
+
+//go:embed templates/18-auto-update.sh.tpl
+var autoUpdateScriptTemplate string
+
+func configureAutoUpdate(ctx *image.Context) ([]string, error) {
+	autoUpdate := ctx.ImageDefinition.OperatingSystem.AutoUpdate
+	if autoUpdate == (image.AutoUpdate{}) {
+		log.AuditComponentSkipped(autoUpdateComponentName)
+		return nil, nil
+	}
+
+	data, err := template.Parse(autoUpdateScriptName, autoUpdateScriptTemplate, &autoUpdate)
+	if err != nil {
+		log.AuditComponentFailed(autoUpdateComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", autoUpdateScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, autoUpdateScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(autoUpdateComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfo(describeAutoUpdate(&autoUpdate))
+
+	log.AuditComponentSuccessful(autoUpdateComponentName)
+	return []string{autoUpdateScriptName}, nil
+}
+
+func describeAutoUpdate(autoUpdate *image.AutoUpdate) string {
+	updates := "are left unchanged"
+	if autoUpdate.Enabled {
+		schedule := "the default schedule"
+		if autoUpdate.Schedule != "" {
+			schedule = fmt.Sprintf("the schedule '%s'", autoUpdate.Schedule)
+		}
+		updates = fmt.Sprintf("are installed on %s", schedule)
+	}
+
+	rebootPolicy := "the default reboot policy"
+	if autoUpdate.RebootPolicy != "" {
+		rebootPolicy = fmt.Sprintf("the '%s' reboot policy", autoUpdate.RebootPolicy)
+	}
+
+	return fmt.Sprintf("Automatic OS updates %s, with %s.", updates, rebootPolicy)
+}
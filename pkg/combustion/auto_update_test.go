@@ -0,0 +1,122 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureAutoUpdate_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureAutoUpdate(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureAutoUpdate(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			AutoUpdate: image.AutoUpdate{
+				Enabled:      true,
+				Schedule:     "Sat *-*-* 02:00",
+				RebootPolicy: "maint_window",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureAutoUpdate(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, autoUpdateScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, autoUpdateScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "/etc/systemd/system/transactional-update.timer.d/50-eib.conf")
+	assert.Contains(t, foundContents, "OnCalendar=\nOnCalendar=Sat *-*-* 02:00")
+	assert.Contains(t, foundContents, "systemctl enable transactional-update.timer")
+	assert.Contains(t, foundContents, "strategy=maint_window")
+	assert.Contains(t, foundContents, "systemctl enable rebootmgr.service")
+}
+
+func TestConfigureAutoUpdate_DefaultSchedule(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			AutoUpdate: image.AutoUpdate{
+				Enabled: true,
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureAutoUpdate(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, autoUpdateScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "systemctl enable transactional-update.timer")
+	assert.NotContains(t, foundContents, "OnCalendar")
+	assert.NotContains(t, foundContents, "rebootmgr")
+}
+
+func TestDescribeAutoUpdate(t *testing.T) {
+	tests := map[string]struct {
+		AutoUpdate image.AutoUpdate
+		Expected   string
+	}{
+		`full`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, Schedule: "daily", RebootPolicy: "instantly"},
+			Expected:   "Automatic OS updates are installed on the schedule 'daily', with the 'instantly' reboot policy.",
+		},
+		`defaults`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true},
+			Expected:   "Automatic OS updates are installed on the default schedule, with the default reboot policy.",
+		},
+		`reboot policy only`: {
+			AutoUpdate: image.AutoUpdate{RebootPolicy: "off"},
+			Expected:   "Automatic OS updates are left unchanged, with the 'off' reboot policy.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, describeAutoUpdate(&test.AutoUpdate))
+		})
+	}
+}
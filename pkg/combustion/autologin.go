@@ -0,0 +1,75 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	autologinComponentName = "autologin"
+	// autologinScriptName runs after the users are created, so that the login user exists
+	autologinScriptName = "13g-autologin.sh"
+
+	gettyService       = "getty"
+	serialGettyService = "serial-getty"
+)
+
+//go:embed templates/13g-autologin.sh.tpl
+var autologinScriptTemplate string
+
+func configureAutologin(ctx *image.Context) ([]string, error) {
+	autologin := ctx.ImageDefinition.OperatingSystem.Autologin
+	if autologin.User == "" {
+		log.AuditComponentSkipped(autologinComponentName)
+		return nil, nil
+	}
+
+	tty := autologin.Console()
+	serial := isSerialConsole(tty)
+
+	service := gettyService
+	if serial {
+		service = serialGettyService
+	}
+
+	values := struct {
+		User    string
+		Service string
+		TTY     string
+		Serial  bool
+	}{
+		User:    autologin.User,
+		Service: service,
+		TTY:     tty,
+		Serial:  serial,
+	}
+
+	data, err := template.Parse(autologinScriptName, autologinScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(autologinComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", autologinScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, autologinScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(autologinComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("WARNING: The user '%s' will be logged in on %s without authentication.", autologin.User, tty)
+
+	log.AuditComponentSuccessful(autologinComponentName)
+	return []string{autologinScriptName}, nil
+}
+
+func isSerialConsole(tty string) bool {
+	return strings.HasPrefix(tty, "ttyS") || strings.HasPrefix(tty, "ttyAMA") || strings.HasPrefix(tty, "hvc")
+}
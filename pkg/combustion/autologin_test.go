@@ -0,0 +1,94 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureAutologin_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureAutologin(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureAutologin(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Autologin: image.Autologin{
+				User: "kiosk",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureAutologin(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, autologinScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, autologinScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<- 'EOF' > /etc/systemd/system/getty@tty1.service.d/autologin.conf")
+	assert.Contains(t, foundContents, "ExecStart=\nExecStart=-/sbin/agetty -o '-p -f -- \\\\u' --noclear --autologin kiosk %I $TERM\nEOF")
+	assert.Contains(t, foundContents, "systemctl enable getty@tty1.service")
+}
+
+func TestConfigureAutologin_SerialConsole(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Autologin: image.Autologin{
+				User: "kiosk",
+				TTY:  "ttyS0",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureAutologin(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, autologinScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<- 'EOF' > /etc/systemd/system/serial-getty@ttyS0.service.d/autologin.conf")
+	assert.Contains(t, foundContents, "--keep-baud 115200,57600,38400,9600 --autologin kiosk %I $TERM")
+	assert.NotContains(t, foundContents, "--noclear")
+	assert.Contains(t, foundContents, "systemctl enable serial-getty@ttyS0.service")
+}
@@ -0,0 +1,67 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	bootValidationComponentName = "boot validation"
+	// Runs after all other components so that the fully configured system is validated
+	bootValidationScriptName = "49-boot-validation.sh"
+	bootValidationUserScript = "boot-validation-script"
+)
+
+//go:embed templates/49-boot-validation.sh.tpl
+var bootValidationTemplate string
+
+func configureBootValidation(ctx *image.Context) ([]string, error) {
+	bootValidation := ctx.ImageDefinition.OperatingSystem.BootValidation
+	if bootValidation.Script == "" {
+		log.AuditComponentSkipped(bootValidationComponentName)
+		return nil, nil
+	}
+
+	src := filepath.Join(ctx.ImageConfigDir, bootValidation.Script)
+	dest := filepath.Join(ctx.CombustionDir, bootValidationUserScript)
+	if err := fileio.CopyFile(src, dest, fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(bootValidationComponentName)
+		return nil, fmt.Errorf("copying boot validation script: %w", err)
+	}
+
+	values := struct {
+		Script        string
+		IgnoreFailure bool
+	}{
+		Script:        bootValidationUserScript,
+		IgnoreFailure: bootValidation.IgnoreFailure,
+	}
+
+	data, err := template.Parse(bootValidationScriptName, bootValidationTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(bootValidationComponentName)
+		return nil, fmt.Errorf("parsing boot validation script template: %w", err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, bootValidationScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(bootValidationComponentName)
+		return nil, fmt.Errorf("writing boot validation script: %w", err)
+	}
+
+	if bootValidation.IgnoreFailure {
+		log.AuditInfof("Embedded boot validation script '%s'; failures will be reported without failing the boot.", bootValidation.Script)
+	} else {
+		log.AuditInfof("Embedded boot validation script '%s'; failures will fail the boot.", bootValidation.Script)
+	}
+
+	log.AuditComponentSuccessful(bootValidationComponentName)
+	return []string{bootValidationScriptName}, nil
+}
@@ -0,0 +1,105 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureBootValidation_NoScript(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureBootValidation(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureBootValidation(t *testing.T) {
+	tests := map[string]struct {
+		IgnoreFailure    bool
+		ExpectedContents []string
+		MissingContents  []string
+	}{
+		`fail boot`: {
+			ExpectedContents: []string{
+				"if ./boot-validation-script; then",
+				"failing the boot",
+				`exit "${rc}"`,
+			},
+			MissingContents: []string{
+				"continuing the boot",
+			},
+		},
+		`ignore failure`: {
+			IgnoreFailure: true,
+			ExpectedContents: []string{
+				"if ./boot-validation-script; then",
+				"continuing the boot",
+			},
+			MissingContents: []string{
+				`exit "${rc}"`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			userScript := "#!/bin/bash\nsystemctl is-active sshd\n"
+			require.NoError(t, os.WriteFile(filepath.Join(ctx.ImageConfigDir, "validate.sh"), []byte(userScript), 0o600))
+
+			ctx.ImageDefinition = &image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					BootValidation: image.BootValidation{
+						Script:        "validate.sh",
+						IgnoreFailure: test.IgnoreFailure,
+					},
+				},
+			}
+
+			// Test
+			scripts, err := configureBootValidation(ctx)
+
+			// Verify
+			require.NoError(t, err)
+
+			require.Len(t, scripts, 1)
+			assert.Equal(t, bootValidationScriptName, scripts[0])
+
+			copiedFilename := filepath.Join(ctx.CombustionDir, bootValidationUserScript)
+			copiedBytes, err := os.ReadFile(copiedFilename)
+			require.NoError(t, err)
+			assert.Equal(t, userScript, string(copiedBytes))
+
+			stats, err := os.Stat(copiedFilename)
+			require.NoError(t, err)
+			assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+			foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, bootValidationScriptName))
+			require.NoError(t, err)
+
+			foundContents := string(foundBytes)
+			for _, expected := range test.ExpectedContents {
+				assert.Contains(t, foundContents, expected)
+			}
+			for _, missing := range test.MissingContents {
+				assert.NotContains(t, foundContents, missing)
+			}
+		})
+	}
+}
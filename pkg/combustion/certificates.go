@@ -1,10 +1,14 @@
 package combustion
 
 import (
+	"bytes"
+	"crypto/x509"
 	_ "embed"
+	"encoding/pem"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
@@ -16,68 +20,206 @@ import (
 const (
 	certsComponentName = "certificates"
 	certsScriptName    = "07-certificates.sh"
-	certsConfigDir     = "certificates"
+	CertificatesDir    = "certificates"
 )
 
+var certsFileExtensions = []string{".pem", ".crt"}
+
 //go:embed templates/07-certificates.sh.tpl
 var certsScriptTemplate string
 
+// CertificateBundle holds the CA certificates of a file in the certificates directory, which may contain
+// a full chain of intermediate CAs along with their root CA.
+type CertificateBundle struct {
+	// Name is the name of the certificate file.
+	Name         string
+	Certificates []*x509.Certificate
+}
+
 func configureCertificates(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, certsConfigDir) {
+	if !isComponentConfigured(ctx, CertificatesDir) {
 		log.AuditComponentSkipped(certsComponentName)
 		zap.S().Info("skipping certificate configuration, no certificates provided")
 		return nil, nil
 	}
 
-	if err := copyCertificates(ctx); err != nil {
+	bundles, err := copyCertificates(ctx)
+	if err != nil {
 		log.AuditComponentFailed(certsComponentName)
 		return nil, err
 	}
 
-	if err := writeCertificatesScript(ctx); err != nil {
+	if err = writeCertificatesScript(ctx, bundles); err != nil {
 		log.AuditComponentFailed(certsComponentName)
 		return nil, err
 	}
 
+	for _, bundle := range bundles {
+		installed := image.CertificateFile{File: bundle.Name}
+		for _, cert := range bundle.Certificates {
+			installed.Subjects = append(installed.Subjects, cert.Subject.String())
+		}
+		ctx.Certificates = append(ctx.Certificates, installed)
+	}
+
 	log.AuditComponentSuccessful(certsComponentName)
 	return []string{certsScriptName}, nil
 }
 
-func copyCertificates(ctx *image.Context) error {
-	srcDir := filepath.Join(ctx.ImageConfigDir, certsConfigDir)
-	destDir := filepath.Join(ctx.CombustionDir, certsConfigDir)
+// IsCertificateFile reports whether the file in the certificates directory is installed as a certificate.
+func IsCertificateFile(name string) bool {
+	return slices.Contains(certsFileExtensions, filepath.Ext(name))
+}
+
+// copyCertificates copies the certificate files into the combustion directory, rewriting each one with its
+// certificates ordered as installed on the node (see OrderCertificates).
+func copyCertificates(ctx *image.Context) ([]CertificateBundle, error) {
+	srcDir := filepath.Join(ctx.ImageConfigDir, CertificatesDir)
+	destDir := filepath.Join(ctx.CombustionDir, CertificatesDir)
 
 	dirEntries, err := os.ReadDir(srcDir)
 	if err != nil {
-		return fmt.Errorf("reading the certificates directory at %s: %w", srcDir, err)
+		return nil, fmt.Errorf("reading the certificates directory at %s: %w", srcDir, err)
 	}
 
 	if len(dirEntries) == 0 {
-		return fmt.Errorf("no certificates found in directory %s", srcDir)
+		return nil, fmt.Errorf("no certificates found in directory %s", srcDir)
+	}
+
+	var bundles []CertificateBundle
+	for _, entry := range dirEntries {
+		if entry.IsDir() || !IsCertificateFile(entry.Name()) {
+			zap.S().Debugf("Skipping %s as it is not a certificate file", entry.Name())
+			continue
+		}
+
+		data, readErr := os.ReadFile(filepath.Join(srcDir, entry.Name()))
+		if readErr != nil {
+			return nil, fmt.Errorf("reading certificate file %s: %w", entry.Name(), readErr)
+		}
+
+		certs, parseErr := ParseCertificates(data)
+		if parseErr != nil {
+			return nil, fmt.Errorf("parsing certificate file %s: %w", entry.Name(), parseErr)
+		}
+
+		bundles = append(bundles, CertificateBundle{Name: entry.Name(), Certificates: certs})
+	}
+
+	OrderCertificates(bundles)
+
+	if err = os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("creating certificates directory '%s': %w", destDir, err)
+	}
+
+	for _, bundle := range bundles {
+		var data bytes.Buffer
+		for _, cert := range bundle.Certificates {
+			if err = pem.Encode(&data, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+				return nil, fmt.Errorf("encoding certificate of %s: %w", bundle.Name, err)
+			}
+		}
+
+		destPath := filepath.Join(destDir, bundle.Name)
+		if err = os.WriteFile(destPath, data.Bytes(), fileio.NonExecutablePerms); err != nil {
+			return nil, fmt.Errorf("writing certificate file %s: %w", destPath, err)
+		}
 	}
 
-	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
-		return fmt.Errorf("creating certificates directory '%s': %w", destDir, err)
+	return bundles, nil
+}
+
+// ParseCertificates decodes the PEM encoded certificates of a certificate file, in the order they are listed.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
 	}
 
-	if err := fileio.CopyFiles(srcDir, destDir, ".pem", false); err != nil {
-		return fmt.Errorf("copying pem files: %w", err)
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificates found")
 	}
 
-	if err := fileio.CopyFiles(srcDir, destDir, ".crt", false); err != nil {
-		return fmt.Errorf("copying certificates: %w", err)
+	return certs, nil
+}
+
+// CertificateIssuer returns the certificate among the given ones which issued and signed the certificate,
+// which is the certificate itself for a self-signed root CA. Nil is returned if the issuer is not included.
+func CertificateIssuer(cert *x509.Certificate, certs []*x509.Certificate) *x509.Certificate {
+	for _, candidate := range certs {
+		if bytes.Equal(cert.RawIssuer, candidate.RawSubject) && cert.CheckSignatureFrom(candidate) == nil {
+			return candidate
+		}
 	}
 
 	return nil
 }
 
-func writeCertificatesScript(ctx *image.Context) error {
+// OrderCertificates orders the certificates so that each one follows the CA it was issued by, both within the
+// bundles and across them. Root CAs come first, followed by the intermediate CAs in the order of their chain.
+// Certificates issued by a CA which is not included are treated as the top of their chain.
+func OrderCertificates(bundles []CertificateBundle) {
+	var all []*x509.Certificate
+	for _, bundle := range bundles {
+		all = append(all, bundle.Certificates...)
+	}
+
+	depths := map[*x509.Certificate]int{}
+	for _, cert := range all {
+		// The depth is limited to the number of certificates in case of issuer cycles
+		depth := 0
+		for current := cert; depth < len(all); depth++ {
+			issuer := CertificateIssuer(current, all)
+			if issuer == nil || issuer == current {
+				break
+			}
+			current = issuer
+		}
+		depths[cert] = depth
+	}
+
+	byDepth := func(a, b *x509.Certificate) int {
+		return depths[a] - depths[b]
+	}
+
+	for _, bundle := range bundles {
+		slices.SortStableFunc(bundle.Certificates, byDepth)
+	}
+
+	slices.SortStableFunc(bundles, func(a, b CertificateBundle) int {
+		return byDepth(a.Certificates[0], b.Certificates[0])
+	})
+}
+
+func writeCertificatesScript(ctx *image.Context, bundles []CertificateBundle) error {
 	destFilename := filepath.Join(ctx.CombustionDir, certsScriptName)
 
+	var files []string
+	for _, bundle := range bundles {
+		files = append(files, bundle.Name)
+	}
+
 	values := struct {
 		CertificatesDir string
+		Files           []string
 	}{
-		CertificatesDir: certsConfigDir,
+		CertificatesDir: CertificatesDir,
+		Files:           files,
 	}
 	data, err := template.Parse(certsScriptName, certsScriptTemplate, &values)
 	if err != nil {
@@ -1,45 +1,113 @@
 package combustion
 
 import (
-	"fmt"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCA creates a CA certificate issued by the given CA, or a self-signed root CA if none is given.
+func newTestCA(t *testing.T, name string, issuer *testCA) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	parent, signer := template, key
+	if issuer != nil {
+		parent, signer = issuer.cert, issuer.key
+	}
+
+	data, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(data)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+func encodeTestCAs(cas ...*testCA) []byte {
+	var data []byte
+	for _, ca := range cas {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})...)
+	}
+
+	return data
+}
+
 func setupCertificatesConfigDir(t *testing.T) (ctx *image.Context, teardown func()) {
 	ctx, teardown = setupContext(t)
 
-	testCertsDir := filepath.Join(ctx.ImageConfigDir, certsConfigDir)
+	testCertsDir := filepath.Join(ctx.ImageConfigDir, CertificatesDir)
 	err := os.Mkdir(testCertsDir, 0o755)
 	require.NoError(t, err)
 
-	testFilenames := []string{"foo", "bar.pem", "baz.pem", "wombat.crt"}
-	for _, filename := range testFilenames {
+	root := newTestCA(t, "Root CA", nil)
+	intermediate := newTestCA(t, "Intermediate CA", root)
+	issuing := newTestCA(t, "Issuing CA", intermediate)
+
+	testFiles := map[string][]byte{
+		"foo":        []byte(""),
+		"bar.pem":    encodeTestCAs(issuing, intermediate),
+		"baz.pem":    encodeTestCAs(root),
+		"wombat.crt": encodeTestCAs(newTestCA(t, "Other Root CA", nil)),
+	}
+	for filename, data := range testFiles {
 		path := filepath.Join(testCertsDir, filename)
-		err = os.WriteFile(path, []byte(""), 0o600)
+		err = os.WriteFile(path, data, 0o600)
 		require.NoError(t, err)
 	}
 
 	return
 }
 
+func certificateSubjects(certs []*x509.Certificate) []string {
+	var subjects []string
+	for _, cert := range certs {
+		subjects = append(subjects, cert.Subject.CommonName)
+	}
+
+	return subjects
+}
+
 func TestCopyCertificatesEmptyDirectory(t *testing.T) {
 	// Setup
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
-	testCertsDir := filepath.Join(ctx.ImageConfigDir, certsConfigDir)
+	testCertsDir := filepath.Join(ctx.ImageConfigDir, CertificatesDir)
 	err := os.Mkdir(testCertsDir, 0o755)
 	require.NoError(t, err)
 	defer os.RemoveAll(testCertsDir)
 
 	// Test
-	err = copyCertificates(ctx)
+	_, err = copyCertificates(ctx)
 
 	// Verify
 	require.Error(t, err)
@@ -51,12 +119,12 @@ func TestCopyCertificates(t *testing.T) {
 	defer teardown()
 
 	// Test
-	err := copyCertificates(ctx)
+	bundles, err := copyCertificates(ctx)
 
 	// Verify
 	require.NoError(t, err)
 
-	expectedCertsDir := filepath.Join(ctx.CombustionDir, certsConfigDir)
+	expectedCertsDir := filepath.Join(ctx.CombustionDir, CertificatesDir)
 	expectedFilenames := []string{"bar.pem", "baz.pem", "wombat.crt"}
 	entries, err := os.ReadDir(expectedCertsDir)
 	require.NoError(t, err)
@@ -64,6 +132,89 @@ func TestCopyCertificates(t *testing.T) {
 	for _, entry := range entries {
 		assert.Contains(t, expectedFilenames, entry.Name())
 	}
+
+	// The file holding the root CA is installed ahead of the chain issued by it
+	require.Len(t, bundles, 3)
+	assert.Equal(t, "baz.pem", bundles[0].Name)
+	assert.Equal(t, "wombat.crt", bundles[1].Name)
+	assert.Equal(t, "bar.pem", bundles[2].Name)
+	assert.Equal(t, []string{"Intermediate CA", "Issuing CA"}, certificateSubjects(bundles[2].Certificates))
+
+	data, err := os.ReadFile(filepath.Join(expectedCertsDir, "bar.pem"))
+	require.NoError(t, err)
+	copied, err := ParseCertificates(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Intermediate CA", "Issuing CA"}, certificateSubjects(copied))
+}
+
+func TestCopyCertificatesInvalidFile(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	testCertsDir := filepath.Join(ctx.ImageConfigDir, CertificatesDir)
+	require.NoError(t, os.Mkdir(testCertsDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(testCertsDir, "ca.pem"), []byte("not a certificate"), 0o600))
+
+	// Test
+	_, err := copyCertificates(ctx)
+
+	// Verify
+	require.EqualError(t, err, "parsing certificate file ca.pem: no PEM encoded certificates found")
+}
+
+func TestParseCertificates(t *testing.T) {
+	root := newTestCA(t, "Root CA", nil)
+	intermediate := newTestCA(t, "Intermediate CA", root)
+
+	certs, err := ParseCertificates(encodeTestCAs(intermediate, root))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Intermediate CA", "Root CA"}, certificateSubjects(certs))
+
+	key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})
+	_, err = ParseCertificates(append(encodeTestCAs(root), key...))
+	require.EqualError(t, err, "unsupported PEM block type 'PRIVATE KEY'")
+
+	invalid := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})
+	_, err = ParseCertificates(append(encodeTestCAs(root), invalid...))
+	require.ErrorContains(t, err, "parsing certificate 2")
+}
+
+func TestCertificateIssuer(t *testing.T) {
+	root := newTestCA(t, "Root CA", nil)
+	intermediate := newTestCA(t, "Intermediate CA", root)
+	impostor := newTestCA(t, "Root CA", nil)
+
+	certs := []*x509.Certificate{impostor.cert, intermediate.cert, root.cert}
+
+	assert.Equal(t, root.cert, CertificateIssuer(intermediate.cert, certs))
+	assert.Equal(t, root.cert, CertificateIssuer(root.cert, certs))
+	assert.Nil(t, CertificateIssuer(intermediate.cert, []*x509.Certificate{impostor.cert}))
+}
+
+func TestOrderCertificates(t *testing.T) {
+	root := newTestCA(t, "Root CA", nil)
+	intermediate := newTestCA(t, "Intermediate CA", root)
+	issuing := newTestCA(t, "Issuing CA", intermediate)
+	otherRoot := newTestCA(t, "Other Root CA", nil)
+	external := newTestCA(t, "External Intermediate CA", newTestCA(t, "External Root CA", nil))
+
+	bundles := []CertificateBundle{
+		{Name: "chain.pem", Certificates: []*x509.Certificate{issuing.cert, intermediate.cert}},
+		{Name: "external.pem", Certificates: []*x509.Certificate{external.cert}},
+		{Name: "roots.pem", Certificates: []*x509.Certificate{otherRoot.cert, root.cert}},
+	}
+
+	OrderCertificates(bundles)
+
+	var names []string
+	for _, bundle := range bundles {
+		names = append(names, bundle.Name)
+	}
+
+	assert.Equal(t, []string{"external.pem", "roots.pem", "chain.pem"}, names)
+	assert.Equal(t, []string{"Intermediate CA", "Issuing CA"}, certificateSubjects(bundles[2].Certificates))
+	assert.Equal(t, []string{"Other Root CA", "Root CA"}, certificateSubjects(bundles[1].Certificates))
 }
 
 func TestWriteCertificatesScript(t *testing.T) {
@@ -71,8 +222,10 @@ func TestWriteCertificatesScript(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
+	bundles := []CertificateBundle{{Name: "root.pem"}, {Name: "chain.crt"}}
+
 	// Test
-	err := writeCertificatesScript(ctx)
+	err := writeCertificatesScript(ctx, bundles)
 
 	// Verify
 	require.NoError(t, err)
@@ -81,6 +234,8 @@ func TestWriteCertificatesScript(t *testing.T) {
 	foundBytes, err := os.ReadFile(scriptFilename)
 	require.NoError(t, err)
 	found := string(foundBytes)
-	assert.Contains(t, found, fmt.Sprintf("cp ./%s/* /etc/pki/trust/anchors/.", certsConfigDir))
-	assert.Contains(t, found, "update-ca-certificates")
+	assert.Contains(t, found, "set -euo pipefail\n\n"+
+		"cp './certificates/root.pem' /etc/pki/trust/anchors/.\n"+
+		"cp './certificates/chain.crt' /etc/pki/trust/anchors/.\n"+
+		"update-ca-certificates -v\n")
 }
@@ -1,6 +1,7 @@
 package combustion
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -31,12 +32,12 @@ type networkConfiguratorInstaller interface {
 }
 
 type kubernetesScriptDownloader interface {
-	DownloadInstallScript(distribution, destinationPath string) (string, error)
+	DownloadInstallScript(ctx context.Context, distribution, destinationPath string) (string, error)
 }
 
 type kubernetesArtefactDownloader interface {
-	DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error
-	DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error
+	DownloadRKE2Artefacts(ctx context.Context, arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error
+	DownloadK3sArtefacts(ctx context.Context, arch image.Arch, version, installPath, imagesPath string) error
 }
 
 type rpmResolver interface {
@@ -47,6 +48,10 @@ type rpmRepoCreator interface {
 	Create(path string) error
 }
 
+type containerImageArchiver interface {
+	SaveImage(ctx context.Context, containerImage string, arch image.Arch, archivePath string) error
+}
+
 type Combustion struct {
 	NetworkConfigGenerator       networkConfigGenerator
 	NetworkConfiguratorInstaller networkConfiguratorInstaller
@@ -55,6 +60,33 @@ type Combustion struct {
 	RPMResolver                  rpmResolver
 	RPMRepoCreator               rpmRepoCreator
 	HelmClient                   image.HelmClient
+	ContainerImageArchiver       containerImageArchiver
+}
+
+// DownloadComponents returns the names of the configured components which download artefacts while generating
+// the combustion content, in the order they are configured.
+func DownloadComponents(ctx *image.Context) []string {
+	definition := ctx.ImageDefinition
+
+	components := []struct {
+		name       string
+		configured bool
+	}{
+		{name: rpmComponentName, configured: !SkipRPMComponent(ctx)},
+		{name: registryComponentName, configured: IsEmbeddedArtifactRegistryConfigured(ctx)},
+		{name: podmanComponentName, configured: len(definition.OperatingSystem.Podman.Images) != 0},
+		{name: imageArchivesComponentName, configured: len(definition.OperatingSystem.ImageArchives.Images) != 0},
+		{name: K8sComponentName, configured: definition.Kubernetes.Version != ""},
+	}
+
+	var names []string
+	for _, component := range components {
+		if component.configured {
+			names = append(names, component.name)
+		}
+	}
+
+	return names
 }
 
 // Configure iterates over all separate Combustion components and configures them independently.
@@ -85,6 +117,22 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     messageComponentName,
 			runnable: configureMessage,
 		},
+		{
+			name:     releaseComponentName,
+			runnable: configureRelease,
+		},
+		{
+			name:     environmentComponentName,
+			runnable: configureEnvironment,
+		},
+		{
+			name:     shellDefaultsComponentName,
+			runnable: configureShellDefaults,
+		},
+		{
+			name:     machineIDComponentName,
+			runnable: configureMachineID,
+		},
 		{
 			name:     customComponentName,
 			runnable: configureCustomFiles,
@@ -97,6 +145,34 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     networkComponentName,
 			runnable: c.configureNetwork,
 		},
+		{
+			name:     networkProfilesComponentName,
+			runnable: c.configureNetworkProfiles,
+		},
+		{
+			name:     hostnameComponentName,
+			runnable: configureHostname,
+		},
+		{
+			name:     networkdComponentName,
+			runnable: configureNetworkd,
+		},
+		{
+			name:     dnsComponentName,
+			runnable: configureDNS,
+		},
+		{
+			name:     dnsmasqComponentName,
+			runnable: configureDnsmasq,
+		},
+		{
+			name:     hostsComponentName,
+			runnable: configureHosts,
+		},
+		{
+			name:     knownHostsComponentName,
+			runnable: configureKnownHosts,
+		},
 		{
 			name:     groupsComponentName,
 			runnable: configureGroups,
@@ -105,10 +181,34 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     usersComponentName,
 			runnable: configureUsers,
 		},
+		{
+			name:     sudoComponentName,
+			runnable: configureSudo,
+		},
+		{
+			name:     directoriesComponentName,
+			runnable: configureDirectories,
+		},
+		{
+			name:     pamComponentName,
+			runnable: configurePAM,
+		},
+		{
+			name:     secureBootComponentName,
+			runnable: configureSecureBoot,
+		},
+		{
+			name:     autologinComponentName,
+			runnable: configureAutologin,
+		},
 		{
 			name:     proxyComponentName,
 			runnable: configureProxy,
 		},
+		{
+			name:     packageLocksComponentName,
+			runnable: configurePackageLocks,
+		},
 		{
 			name:     rpmComponentName,
 			runnable: c.configureRPMs,
@@ -117,6 +217,62 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     systemdComponentName,
 			runnable: configureSystemd,
 		},
+		{
+			name:     sshdComponentName,
+			runnable: configureSSHD,
+		},
+		{
+			name:     fail2banComponentName,
+			runnable: configureFail2ban,
+		},
+		{
+			name:     auditComponentName,
+			runnable: configureAudit,
+		},
+		{
+			name:     apparmorComponentName,
+			runnable: configureAppArmor,
+		},
+		{
+			name:     autoUpdateComponentName,
+			runnable: configureAutoUpdate,
+		},
+		{
+			name:     journaldComponentName,
+			runnable: configureJournald,
+		},
+		{
+			name:     logrotateComponentName,
+			runnable: configureLogrotate,
+		},
+		{
+			name:     udevComponentName,
+			runnable: configureUdev,
+		},
+		{
+			name:     scheduledJobsComponentName,
+			runnable: configureScheduledJobs,
+		},
+		{
+			name:     localesComponentName,
+			runnable: configureLocales,
+		},
+		{
+			name:     gpuDriversComponentName,
+			runnable: configureGPUDrivers,
+		},
+		{
+			name:     zramComponentName,
+			runnable: configureZram,
+		},
+		{
+			name:     kdumpComponentName,
+			runnable: configureKdump,
+		},
+		{
+			name:     readOnlyRootComponentName,
+			runnable: configureReadOnlyRoot,
+		},
 		{
 			name:     elementalComponentName,
 			runnable: configureElemental,
@@ -129,18 +285,30 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     registryComponentName,
 			runnable: c.configureRegistry,
 		},
+		{
+			name:     podmanComponentName,
+			runnable: c.configurePodman,
+		},
+		{
+			name:     imageArchivesComponentName,
+			runnable: c.configureImageArchives,
+		},
 		{
 			name:     keymapComponentName,
 			runnable: configureKeymap,
 		},
 		{
-			name:     k8sComponentName,
+			name:     K8sComponentName,
 			runnable: c.configureKubernetes,
 		},
 		{
 			name:     certsComponentName,
 			runnable: configureCertificates,
 		},
+		{
+			name:     bootValidationComponentName,
+			runnable: configureBootValidation,
+		},
 	}
 
 	for _, component := range combustionComponents {
@@ -153,7 +321,7 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 	}
 
 	var networkScript string
-	if isComponentConfigured(ctx, networkConfigDir) {
+	if isComponentConfigured(ctx, NetworkConfigDir) {
 		networkScript = networkConfigScriptName
 	}
 
@@ -65,3 +65,16 @@ func TestIsComponentConfigured(t *testing.T) {
 	assert.False(t, isComponentConfigured(ctx, "missing-component"))
 	assert.False(t, isComponentConfigured(ctx, ""))
 }
+
+func TestDownloadComponents(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	assert.Empty(t, DownloadComponents(ctx))
+
+	ctx.ImageDefinition.OperatingSystem.ImageArchives.Images = []image.ContainerImage{{Name: "nginx:1.25"}}
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages = []image.ContainerImage{{Name: "nginx:1.25"}}
+	ctx.ImageDefinition.Kubernetes.Version = "v1.30.3+k3s1"
+
+	assert.Equal(t, []string{registryComponentName, imageArchivesComponentName, K8sComponentName}, DownloadComponents(ctx))
+}
@@ -0,0 +1,97 @@
+package combustion
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+)
+
+const (
+	containerdDir = "containerd"
+
+	containerdConfigTemplate   = "config.toml.tmpl"
+	containerdConfigV3Template = "config-v3.toml.tmpl"
+
+	// containerdBaseTemplate renders the configuration generated by the distribution, which a partial
+	// configuration is appended to
+	containerdBaseTemplate = "{{ template \"base\" . }}\n"
+)
+
+// ContainerdConfigMode returns the mode the containerd configuration override is applied with.
+func ContainerdConfigMode(containerd *image.Containerd) string {
+	if containerd.Mode == "" {
+		return image.ContainerdConfigModePartial
+	}
+
+	return containerd.Mode
+}
+
+// ContainerdConfigPath returns the path of the containerd configuration override in the image configuration directory.
+func ContainerdConfigPath(ctx *image.Context) string {
+	return filepath.Join(ctx.ImageConfigDir, ctx.ImageDefinition.Kubernetes.Containerd.ConfigFile)
+}
+
+// HasContainerdTemplateActions reports whether the containerd configuration contains template actions, which are
+// rendered by the distribution on the node and prevent the configuration from being parsed at build time.
+func HasContainerdTemplateActions(data []byte) bool {
+	return bytes.Contains(data, []byte("{{"))
+}
+
+// configureContainerd stores the containerd configuration template installed on the nodes, returning its path
+// in the artefacts and the name which the distribution looks it up by.
+func configureContainerd(ctx *image.Context) (string, string, error) {
+	containerd := &ctx.ImageDefinition.Kubernetes.Containerd
+	if containerd.ConfigFile == "" {
+		return "", "", nil
+	}
+
+	data, err := os.ReadFile(ContainerdConfigPath(ctx))
+	if err != nil {
+		return "", "", fmt.Errorf("reading containerd config: %w", err)
+	}
+
+	templateName := containerdConfigTemplate
+	if !HasContainerdTemplateActions(data) {
+		var config struct {
+			Version int `toml:"version"`
+		}
+
+		if _, err = toml.Decode(string(data), &config); err != nil {
+			return "", "", fmt.Errorf("parsing containerd config: %w", err)
+		}
+
+		// containerd 2.0 only accepts version 3 configurations, which are kept apart from the ones of containerd 1.x
+		if config.Version == 3 {
+			templateName = containerdConfigV3Template
+		}
+	}
+
+	mode := ContainerdConfigMode(containerd)
+	if mode == image.ContainerdConfigModePartial {
+		data = append([]byte(containerdBaseTemplate), data...)
+	}
+
+	configPath := filepath.Join(K8sDir, containerdDir, templateName)
+	configDest := filepath.Join(ctx.ArtefactsDir, configPath)
+	if err = os.MkdirAll(filepath.Dir(configDest), os.ModePerm); err != nil {
+		return "", "", fmt.Errorf("creating containerd config dir: %w", err)
+	}
+
+	if err = os.WriteFile(configDest, data, fileio.NonExecutablePerms); err != nil {
+		return "", "", fmt.Errorf("writing containerd config: %w", err)
+	}
+
+	if mode == image.ContainerdConfigModeFull {
+		log.AuditInfof("The containerd configuration '%s' will replace the one generated by Kubernetes.", containerd.ConfigFile)
+	} else {
+		log.AuditInfof("The containerd configuration '%s' will be appended to the one generated by Kubernetes.", containerd.ConfigFile)
+	}
+
+	return prependArtefactPath(configPath), templateName, nil
+}
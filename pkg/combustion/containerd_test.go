@@ -0,0 +1,128 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureContainerd_NotConfigured(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	configPath, templateName, err := configureContainerd(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, configPath)
+	assert.Empty(t, templateName)
+}
+
+func TestConfigureContainerd(t *testing.T) {
+	tests := map[string]struct {
+		mode             string
+		config           string
+		expectedTemplate string
+		expectedContents string
+	}{
+		"Partial config": {
+			config: "[plugins.\"io.containerd.grpc.v1.cri\".containerd.runtimes.nvidia]\n" +
+				"  runtime_type = \"io.containerd.runc.v2\"\n",
+			expectedTemplate: "config.toml.tmpl",
+			expectedContents: "{{ template \"base\" . }}\n" +
+				"[plugins.\"io.containerd.grpc.v1.cri\".containerd.runtimes.nvidia]\n" +
+				"  runtime_type = \"io.containerd.runc.v2\"\n",
+		},
+		"Full config": {
+			mode:             image.ContainerdConfigModeFull,
+			config:           "version = 2\n",
+			expectedTemplate: "config.toml.tmpl",
+			expectedContents: "version = 2\n",
+		},
+		"Full version 3 config": {
+			mode:             image.ContainerdConfigModeFull,
+			config:           "version = 3\n",
+			expectedTemplate: "config-v3.toml.tmpl",
+			expectedContents: "version = 3\n",
+		},
+		"Full config with template actions": {
+			mode:             image.ContainerdConfigModeFull,
+			config:           "version = 2\nroot = \"{{ .NodeConfig.Containerd.Root }}\"\n",
+			expectedTemplate: "config.toml.tmpl",
+			expectedContents: "version = 2\nroot = \"{{ .NodeConfig.Containerd.Root }}\"\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			ctx.ImageDefinition.Kubernetes.Containerd = image.Containerd{
+				ConfigFile: "containerd.toml",
+				Mode:       test.mode,
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(ctx.ImageConfigDir, "containerd.toml"), []byte(test.config), fileio.NonExecutablePerms))
+
+			configPath, templateName, err := configureContainerd(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "$ARTEFACTS_DIR/kubernetes/containerd/"+test.expectedTemplate, configPath)
+			assert.Equal(t, test.expectedTemplate, templateName)
+
+			b, err := os.ReadFile(filepath.Join(ctx.ArtefactsDir, "kubernetes", "containerd", test.expectedTemplate))
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedContents, string(b))
+		})
+	}
+}
+
+func TestConfigureContainerd_InvalidConfig(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes.Containerd = image.Containerd{ConfigFile: "containerd.toml"}
+	require.NoError(t, os.WriteFile(filepath.Join(ctx.ImageConfigDir, "containerd.toml"), []byte("version = "), fileio.NonExecutablePerms))
+
+	_, _, err := configureContainerd(ctx)
+	require.ErrorContains(t, err, "parsing containerd config")
+}
+
+func TestConfigureKubernetes_ContainerdConfig(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes = image.Kubernetes{
+		Version: "v1.30.3+rke2r1",
+		Containerd: image.Containerd{
+			ConfigFile: "containerd.toml",
+		},
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(ctx.ImageConfigDir, "containerd.toml"), []byte("[debug]\n  level = \"info\"\n"), fileio.NonExecutablePerms))
+
+	c := Combustion{
+		KubernetesScriptDownloader: mockKubernetesScriptDownloader{
+			downloadScript: func(distribution, destPath string) (string, error) {
+				return kubernetesScriptInstaller, nil
+			},
+		},
+		KubernetesArtefactDownloader: mockKubernetesArtefactDownloader{
+			downloadRKE2Artefacts: func(arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error {
+				return nil
+			},
+		},
+	}
+
+	scripts, err := c.configureKubernetes(ctx)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scripts[0]))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "mkdir -p /var/lib/rancher/rke2/agent/etc/containerd/\n"+
+		"cp $ARTEFACTS_DIR/kubernetes/containerd/config.toml.tmpl /var/lib/rancher/rke2/agent/etc/containerd/config.toml.tmpl")
+}
@@ -11,14 +11,14 @@ import (
 )
 
 const (
-	customDir           = "custom"
-	customScriptsDir    = "scripts"
+	CustomDir           = "custom"
+	CustomScriptsDir    = "scripts"
 	customFilesDir      = "files"
 	customComponentName = "custom files"
 )
 
 func configureCustomFiles(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, customDir) {
+	if !isComponentConfigured(ctx, CustomDir) {
 		log.AuditComponentSkipped(customComponentName)
 		return nil, nil
 	}
@@ -40,16 +40,64 @@ func configureCustomFiles(ctx *image.Context) ([]string, error) {
 }
 
 func handleCustomFiles(ctx *image.Context) error {
-	fullFilesDir := generateComponentPath(ctx, filepath.Join(customDir, customFilesDir))
+	fullFilesDir := generateComponentPath(ctx, filepath.Join(CustomDir, customFilesDir))
 	_, err := copyCustomFiles(fullFilesDir, ctx.CombustionDir, nil)
 	return err
 }
 
 func handleCustomScripts(ctx *image.Context) ([]string, error) {
-	fullScriptsDir := generateComponentPath(ctx, filepath.Join(customDir, customScriptsDir))
+	fullScriptsDir := generateComponentPath(ctx, filepath.Join(CustomDir, CustomScriptsDir))
+
+	if ctx.PreserveScriptPermissions {
+		return copyCustomFiles(fullScriptsDir, ctx.CombustionDir, nil)
+	}
+
+	nonExecutable, err := NonExecutableCustomScripts(ctx.ImageConfigDir)
+	if err != nil {
+		return nil, fmt.Errorf("checking custom script permissions: %w", err)
+	}
+
 	executablePerms := fileio.ExecutablePerms
 	scripts, err := copyCustomFiles(fullScriptsDir, ctx.CombustionDir, &executablePerms)
-	return scripts, err
+	if err != nil {
+		return nil, err
+	}
+
+	for _, script := range nonExecutable {
+		log.AuditInfof("Custom script '%s' was not executable and has been made executable.", script)
+	}
+
+	return scripts, nil
+}
+
+// NonExecutableCustomScripts returns the names of the custom scripts under the image configuration
+// directory which have no execute permission set. Combustion fails on such scripts unless they are
+// made executable during the build.
+func NonExecutableCustomScripts(imageConfigDir string) ([]string, error) {
+	scriptsDir := filepath.Join(imageConfigDir, CustomDir, CustomScriptsDir)
+
+	entries, err := os.ReadDir(scriptsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading the custom scripts directory at %s: %w", scriptsDir, err)
+	}
+
+	var nonExecutable []string
+	for _, entry := range entries {
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			return nil, fmt.Errorf("reading file info: %w", infoErr)
+		}
+
+		if info.Mode().IsRegular() && info.Mode().Perm()&0o111 == 0 {
+			nonExecutable = append(nonExecutable, entry.Name())
+		}
+	}
+
+	return nonExecutable, nil
 }
 
 func copyCustomFiles(fromDir, toDir string, filePermissions *os.FileMode) ([]string, error) {
@@ -16,10 +16,10 @@ func TestConfigureCustomFiles(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
-	scriptsDir := filepath.Join(ctx.ImageConfigDir, customDir, customScriptsDir)
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, CustomDir, CustomScriptsDir)
 	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
 
-	filesDir := filepath.Join(ctx.ImageConfigDir, customDir, customFilesDir)
+	filesDir := filepath.Join(ctx.ImageConfigDir, CustomDir, customFilesDir)
 	require.NoError(t, os.MkdirAll(filesDir, os.ModePerm))
 
 	files := map[string]struct {
@@ -121,7 +121,7 @@ func TestCopyCustomFiles_EmptyFromDir(t *testing.T) {
 	defer teardown()
 
 	// - from directory to look in
-	fullScriptsDir := filepath.Join(ctx.ImageConfigDir, customDir, customScriptsDir)
+	fullScriptsDir := filepath.Join(ctx.ImageConfigDir, CustomDir, CustomScriptsDir)
 	err := os.MkdirAll(fullScriptsDir, os.ModePerm)
 	require.NoError(t, err)
 
@@ -133,3 +133,64 @@ func TestCopyCustomFiles_EmptyFromDir(t *testing.T) {
 	assert.ErrorContains(t, err, "no files found in directory")
 	assert.Nil(t, scripts)
 }
+
+func TestConfigureCustomFiles_PreserveScriptPermissions(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.PreserveScriptPermissions = true
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, CustomDir, CustomScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "foo.sh"), nil, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "bar.sh"), nil, 0o644))
+
+	// Test
+	scripts, err := configureCustomFiles(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"foo.sh", "bar.sh"}, scripts)
+
+	stats, err := os.Stat(filepath.Join(ctx.CombustionDir, "foo.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o755), stats.Mode())
+
+	stats, err = os.Stat(filepath.Join(ctx.CombustionDir, "bar.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0o644), stats.Mode())
+}
+
+func TestNonExecutableCustomScripts(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, CustomDir, CustomScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "executable.sh"), nil, 0o744))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "group-executable.sh"), nil, 0o654))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "plain.sh"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "readonly.sh"), nil, 0o400))
+
+	// Test
+	scripts, err := NonExecutableCustomScripts(ctx.ImageConfigDir)
+
+	// Verify
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"plain.sh", "readonly.sh"}, scripts)
+}
+
+func TestNonExecutableCustomScripts_NoScriptsDir(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	scripts, err := NonExecutableCustomScripts(ctx.ImageConfigDir)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
@@ -0,0 +1,79 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	directoriesComponentName = "directories"
+	directoriesScriptName    = "13d-directories.sh"
+
+	defaultDirectoryOwnership = "root"
+	defaultDirectoryMode      = "0755"
+)
+
+//go:embed templates/13d-directories.sh.tpl
+var directoriesScriptTemplate string
+
+func configureDirectories(ctx *image.Context) ([]string, error) {
+	definedDirectories := ctx.ImageDefinition.OperatingSystem.Directories
+	if len(definedDirectories) == 0 {
+		log.AuditComponentSkipped(directoriesComponentName)
+		return nil, nil
+	}
+
+	directories := make([]image.Directory, 0, len(definedDirectories))
+	for _, directory := range definedDirectories {
+		directories = append(directories, directoryWithDefaults(directory))
+	}
+
+	values := struct {
+		Directories []image.Directory
+	}{
+		Directories: directories,
+	}
+
+	data, err := template.Parse(directoriesScriptName, directoriesScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(directoriesComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", directoriesScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, directoriesScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(directoriesComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	for _, directory := range directories {
+		log.AuditInfof("Directory %s is created owned by %s:%s with mode %s.", directory.Path, directory.Owner, directory.Group, directory.Mode)
+	}
+
+	log.AuditComponentSuccessful(directoriesComponentName)
+	return []string{directoriesScriptName}, nil
+}
+
+// directoryWithDefaults fills in the ownership and mode which are not explicitly configured.
+func directoryWithDefaults(directory image.Directory) image.Directory {
+	if directory.Owner == "" {
+		directory.Owner = defaultDirectoryOwnership
+	}
+
+	if directory.Group == "" {
+		directory.Group = defaultDirectoryOwnership
+	}
+
+	if directory.Mode == "" {
+		directory.Mode = defaultDirectoryMode
+	}
+
+	return directory
+}
@@ -0,0 +1,66 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureDirectories_NoDirectories(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureDirectories(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+
+	_, err = os.Stat(filepath.Join(ctx.CombustionDir, directoriesScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfigureDirectories(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Directories: []image.Directory{
+				{Path: "/var/lib/app"},
+				{Path: "/srv/app data", Owner: "app", Group: "1000", Mode: "0750"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureDirectories(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, directoriesScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, directoriesScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "mount_covering '/var/lib/app'\ninstall -d -m 0755 -o root -g root '/var/lib/app'\n")
+	assert.Contains(t, foundContents, "mount_covering '/srv/app data'\ninstall -d -m 0750 -o app -g 1000 '/srv/app data'\n")
+}
@@ -0,0 +1,94 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	dnsComponentName = "dns"
+	dnsScriptName    = "04-dns.sh"
+)
+
+//go:embed templates/04-dns.sh.tpl
+var dnsScriptTemplate string
+
+// IsDNSConfigured reports whether any of the systemd-resolved settings are specified.
+func IsDNSConfigured(dns *image.DNS) bool {
+	return len(dns.Servers) != 0 || len(dns.FallbackServers) != 0 || dns.DNSOverTLS != ""
+}
+
+func configureDNS(ctx *image.Context) ([]string, error) {
+	dns := ctx.ImageDefinition.OperatingSystem.DNS
+	if !IsDNSConfigured(&dns) {
+		log.AuditComponentSkipped(dnsComponentName)
+		return nil, nil
+	}
+
+	values := struct {
+		Config string
+	}{
+		Config: resolvedConfig(&dns),
+	}
+
+	data, err := template.Parse(dnsScriptName, dnsScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(dnsComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", dnsScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, dnsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(dnsComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("DNS is resolved by systemd-resolved with %s.", describeDNS(&dns))
+
+	log.AuditComponentSuccessful(dnsComponentName)
+	return []string{dnsScriptName}, nil
+}
+
+// resolvedConfig returns the [Resolve] settings. The fallback servers are always set, as the compiled in
+// defaults would otherwise be used whenever the configured servers are unreachable.
+func resolvedConfig(dns *image.DNS) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "DNS=%s\n", strings.Join(dns.Servers, " "))
+	fmt.Fprintf(&sb, "FallbackDNS=%s\n", strings.Join(dns.FallbackServers, " "))
+	fmt.Fprintf(&sb, "DNSOverTLS=%s\n", dnsOverTLS(dns))
+	// Routes all queries to the configured servers rather than to any per-link servers
+	sb.WriteString("Domains=~.\n")
+
+	return sb.String()
+}
+
+func dnsOverTLS(dns *image.DNS) string {
+	if dns.DNSOverTLS == "" {
+		return image.DNSOverTLSYes
+	}
+
+	return dns.DNSOverTLS
+}
+
+func describeDNS(dns *image.DNS) string {
+	servers := "no servers"
+	if len(dns.Servers) != 0 {
+		servers = fmt.Sprintf("servers [%s]", strings.Join(dns.Servers, ", "))
+	}
+
+	fallback := "no fallback servers"
+	if len(dns.FallbackServers) != 0 {
+		fallback = fmt.Sprintf("fallback servers [%s]", strings.Join(dns.FallbackServers, ", "))
+	}
+
+	return fmt.Sprintf("%s, %s and DNS-over-TLS '%s'", servers, fallback, dnsOverTLS(dns))
+}
@@ -0,0 +1,89 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureDNS_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureDNS(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+
+	_, err = os.Stat(filepath.Join(ctx.CombustionDir, dnsScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfigureDNS(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			DNS: image.DNS{
+				Servers: []string{"9.9.9.9#dns.quad9.net", "[2620:fe::fe]:853#dns.quad9.net"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureDNS(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, dnsScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, dnsScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	expected := "[Resolve]\n" +
+		"DNS=9.9.9.9#dns.quad9.net [2620:fe::fe]:853#dns.quad9.net\n" +
+		"FallbackDNS=\n" +
+		"DNSOverTLS=yes\n" +
+		"Domains=~.\n" +
+		"EOF\n"
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, expected)
+	assert.Contains(t, foundContents, "dns=none\n")
+	assert.Contains(t, foundContents, "systemctl enable systemd-resolved.service")
+}
+
+func TestResolvedConfig(t *testing.T) {
+	dns := &image.DNS{
+		Servers:         []string{"1.1.1.1#cloudflare-dns.com"},
+		FallbackServers: []string{"9.9.9.9", "8.8.8.8"},
+		DNSOverTLS:      image.DNSOverTLSOpportunistic,
+	}
+
+	expected := "DNS=1.1.1.1#cloudflare-dns.com\n" +
+		"FallbackDNS=9.9.9.9 8.8.8.8\n" +
+		"DNSOverTLS=opportunistic\n" +
+		"Domains=~.\n"
+	assert.Equal(t, expected, resolvedConfig(dns))
+
+	assert.Equal(t, "servers [1.1.1.1#cloudflare-dns.com], fallback servers [9.9.9.9, 8.8.8.8] and DNS-over-TLS 'opportunistic'", describeDNS(dns))
+}
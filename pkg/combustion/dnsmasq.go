@@ -0,0 +1,78 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	dnsmasqComponentName = "dnsmasq"
+	dnsmasqScriptName    = "11a-dnsmasq.sh"
+
+	// DnsmasqPackage is installed along with the other packages when dnsmasq is configured.
+	DnsmasqPackage = "dnsmasq"
+)
+
+//go:embed templates/11a-dnsmasq.sh.tpl
+var dnsmasqScriptTemplate string
+
+// IsDnsmasqConfigured checks whether dnsmasq is configured to serve DHCP or DNS on the node.
+func IsDnsmasqConfigured(dnsmasq *image.Dnsmasq) bool {
+	return len(dnsmasq.Interfaces) != 0 || dnsmasq.Domain != "" || len(dnsmasq.Upstreams) != 0 ||
+		len(dnsmasq.Ranges) != 0 || len(dnsmasq.StaticLeases) != 0
+}
+
+// configureDnsmasq writes the dnsmasq configuration, dnsmasq itself is installed with the other packages.
+func configureDnsmasq(ctx *image.Context) ([]string, error) {
+	dnsmasq := &ctx.ImageDefinition.OperatingSystem.Dnsmasq
+
+	if !IsDnsmasqConfigured(dnsmasq) {
+		log.AuditComponentSkipped(dnsmasqComponentName)
+		return nil, nil
+	}
+
+	data, err := template.Parse(dnsmasqScriptName, dnsmasqScriptTemplate, dnsmasq)
+	if err != nil {
+		log.AuditComponentFailed(dnsmasqComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", dnsmasqScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, dnsmasqScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(dnsmasqComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("dnsmasq will serve %s.", DnsmasqDescription(dnsmasq))
+
+	log.AuditComponentSuccessful(dnsmasqComponentName)
+	return []string{dnsmasqScriptName}, nil
+}
+
+// DnsmasqDescription describes the DHCP ranges, static leases and interfaces served by dnsmasq.
+func DnsmasqDescription(dnsmasq *image.Dnsmasq) string {
+	description := "DNS"
+	if len(dnsmasq.Ranges) != 0 || len(dnsmasq.StaticLeases) != 0 {
+		var ranges []string
+		for _, r := range dnsmasq.Ranges {
+			ranges = append(ranges, r.Start+"-"+r.End)
+		}
+
+		description = fmt.Sprintf("DHCP and DNS, leasing %d range(s) [%s] and %d static lease(s),",
+			len(dnsmasq.Ranges), strings.Join(ranges, ", "), len(dnsmasq.StaticLeases))
+	}
+
+	if len(dnsmasq.Interfaces) == 0 {
+		return description + " on all interfaces"
+	}
+
+	return fmt.Sprintf("%s on %s", description, strings.Join(dnsmasq.Interfaces, ", "))
+}
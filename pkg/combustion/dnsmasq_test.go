@@ -0,0 +1,116 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureDnsmasq_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	scripts, err := configureDnsmasq(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, dnsmasqScriptName))
+}
+
+func TestConfigureDnsmasq(t *testing.T) {
+	tests := map[string]struct {
+		dnsmasq          image.Dnsmasq
+		expectedContents string
+	}{
+		"DNS only": {
+			dnsmasq: image.Dnsmasq{
+				Upstreams: []string{"9.9.9.9"},
+			},
+			expectedContents: "bind-dynamic\n" +
+				"no-resolv\n" +
+				"server=9.9.9.9\n" +
+				"EOF",
+		},
+		"DHCP and DNS": {
+			dnsmasq: image.Dnsmasq{
+				Interfaces: []string{"eth1", "eth2"},
+				Domain:     "sensors.lan",
+				Upstreams:  []string{"9.9.9.9", "1.1.1.1"},
+				Ranges: []image.DHCPRange{
+					{Start: "192.168.50.100", End: "192.168.50.200", LeaseTime: "12h"},
+					{Start: "192.168.60.100", End: "192.168.60.200"},
+				},
+				StaticLeases: []image.StaticLease{
+					{MAC: "52:54:00:aa:bb:cc", IP: "192.168.50.10", Hostname: "sensor-01"},
+					{MAC: "52:54:00:aa:bb:cd", IP: "192.168.50.11"},
+				},
+			},
+			expectedContents: "bind-dynamic\n" +
+				"interface=eth1\n" +
+				"interface=eth2\n" +
+				"domain=sensors.lan\n" +
+				"local=/sensors.lan/\n" +
+				"expand-hosts\n" +
+				"no-resolv\n" +
+				"server=9.9.9.9\n" +
+				"server=1.1.1.1\n" +
+				"dhcp-range=192.168.50.100,192.168.50.200,12h\n" +
+				"dhcp-range=192.168.60.100,192.168.60.200\n" +
+				"dhcp-host=52:54:00:aa:bb:cc,192.168.50.10,sensor-01\n" +
+				"dhcp-host=52:54:00:aa:bb:cd,192.168.50.11\n" +
+				"EOF",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			ctx.ImageDefinition.OperatingSystem.Dnsmasq = test.dnsmasq
+
+			// Test
+			scripts, err := configureDnsmasq(ctx)
+
+			// Verify
+			require.NoError(t, err)
+			assert.Equal(t, []string{dnsmasqScriptName}, scripts)
+
+			scriptPath := filepath.Join(ctx.CombustionDir, dnsmasqScriptName)
+
+			info, err := os.Stat(scriptPath)
+			require.NoError(t, err)
+			assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+			b, err := os.ReadFile(scriptPath)
+			require.NoError(t, err)
+
+			contents := string(b)
+			assert.Contains(t, contents, "cat <<'EOF' > /etc/dnsmasq.d/eib.conf\n"+
+				"# Binding to the addresses of the served interfaces only leaves the stub listener of systemd-resolved untouched\n"+
+				test.expectedContents)
+			assert.Contains(t, contents, "systemctl enable dnsmasq.service")
+		})
+	}
+}
+
+func TestDnsmasqDescription(t *testing.T) {
+	assert.Equal(t, "DNS on all interfaces", DnsmasqDescription(&image.Dnsmasq{Upstreams: []string{"9.9.9.9"}}))
+
+	dnsmasq := &image.Dnsmasq{
+		Interfaces:   []string{"eth1"},
+		Ranges:       []image.DHCPRange{{Start: "192.168.50.100", End: "192.168.50.200"}},
+		StaticLeases: []image.StaticLease{{MAC: "52:54:00:aa:bb:cc", IP: "192.168.50.10"}},
+	}
+	assert.Equal(t, "DHCP and DNS, leasing 1 range(s) [192.168.50.100-192.168.50.200] and 1 static lease(s), on eth1",
+		DnsmasqDescription(dnsmasq))
+}
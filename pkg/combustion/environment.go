@@ -0,0 +1,83 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	environmentComponentName = "environment"
+	environmentScriptName    = "46-environment.sh"
+	environmentFile          = "/etc/environment"
+)
+
+//go:embed templates/46-environment.sh.tpl
+var environmentScriptTemplate string
+
+func configureEnvironment(ctx *image.Context) ([]string, error) {
+	variables := ctx.ImageDefinition.OperatingSystem.Environment
+	if len(variables) == 0 {
+		log.AuditComponentSkipped(environmentComponentName)
+		return nil, nil
+	}
+
+	names := environmentVariableNames(variables)
+
+	var sb strings.Builder
+	// Values are validated to not require any escaping
+	for _, name := range names {
+		fmt.Fprintf(&sb, "%s=\"%s\"\n", name, variables[name].Value)
+	}
+
+	values := struct {
+		Names       []string
+		Environment string
+	}{
+		Names:       names,
+		Environment: sb.String(),
+	}
+
+	data, err := template.Parse(environmentScriptName, environmentScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(environmentComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", environmentScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, environmentScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(environmentComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	for _, name := range names {
+		value := fmt.Sprintf("'%s'", variables[name].Value)
+		if variables[name].Sensitive {
+			value = "a sensitive value (redacted)"
+		}
+
+		log.AuditInfof("Environment variable %s is set to %s in %s.", name, value, environmentFile)
+	}
+
+	log.AuditComponentSuccessful(environmentComponentName)
+	return []string{environmentScriptName}, nil
+}
+
+// environmentVariableNames returns the names of the variables sorted in the order they are written.
+func environmentVariableNames(variables map[string]image.EnvironmentVariable) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
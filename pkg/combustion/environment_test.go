@@ -0,0 +1,75 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureEnvironment_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureEnvironment(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+
+	_, err = os.Stat(filepath.Join(ctx.CombustionDir, environmentScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfigureEnvironment(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Environment: map[string]image.EnvironmentVariable{
+				"AGENT_URL":   {Value: "https://agent.example.com:8443"},
+				"AGENT_TOKEN": {Value: "s3cr3t", Sensitive: true},
+				"AGENT_NAME":  {Value: "edge node 1"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureEnvironment(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, environmentScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, environmentScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "sed -i '/^\\(export \\)\\?AGENT_NAME=/d' /etc/environment\n"+
+		"sed -i '/^\\(export \\)\\?AGENT_TOKEN=/d' /etc/environment\n"+
+		"sed -i '/^\\(export \\)\\?AGENT_URL=/d' /etc/environment\n")
+
+	expected := "cat <<- 'EOF' >> /etc/environment\n" +
+		"AGENT_NAME=\"edge node 1\"\n" +
+		"AGENT_TOKEN=\"s3cr3t\"\n" +
+		"AGENT_URL=\"https://agent.example.com:8443\"\n" +
+		"EOF\n"
+	assert.Contains(t, foundContents, expected)
+}
@@ -0,0 +1,62 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	fail2banComponentName = "fail2ban"
+	fail2banScriptName    = "15a-fail2ban.sh"
+
+	// Fail2banPackage is installed along with the other packages when fail2ban jails are configured.
+	Fail2banPackage = "fail2ban"
+)
+
+//go:embed templates/15a-fail2ban.sh.tpl
+var fail2banScriptTemplate string
+
+// configureFail2ban enables the configured fail2ban jails, fail2ban itself is installed with the other packages.
+func configureFail2ban(ctx *image.Context) ([]string, error) {
+	fail2ban := &ctx.ImageDefinition.OperatingSystem.Fail2ban
+
+	if len(fail2ban.Jails) == 0 {
+		log.AuditComponentSkipped(fail2banComponentName)
+		return nil, nil
+	}
+
+	data, err := template.Parse(fail2banScriptName, fail2banScriptTemplate, fail2ban)
+	if err != nil {
+		log.AuditComponentFailed(fail2banComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", fail2banScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, fail2banScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(fail2banComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("The fail2ban jails %s will be enabled.", strings.Join(Fail2banJails(fail2ban), ", "))
+
+	log.AuditComponentSuccessful(fail2banComponentName)
+	return []string{fail2banScriptName}, nil
+}
+
+// Fail2banJails returns the names of the enabled fail2ban jails.
+func Fail2banJails(fail2ban *image.Fail2ban) []string {
+	var jails []string
+	for _, jail := range fail2ban.Jails {
+		jails = append(jails, jail.Name)
+	}
+
+	return jails
+}
@@ -0,0 +1,115 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureFail2ban_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	scripts, err := configureFail2ban(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, fail2banScriptName))
+}
+
+func TestConfigureFail2ban(t *testing.T) {
+	tests := map[string]struct {
+		fail2ban         image.Fail2ban
+		expectedContents string
+	}{
+		"Package defaults": {
+			fail2ban: image.Fail2ban{
+				Jails: []image.Fail2banJail{{Name: "sshd"}},
+			},
+			expectedContents: "[DEFAULT]\n" +
+				"# The services of the node log to the journal rather than to files\n" +
+				"backend = systemd\n" +
+				"\n" +
+				"[sshd]\n" +
+				"enabled = true\n" +
+				"EOF",
+		},
+		"Overridden defaults": {
+			fail2ban: image.Fail2ban{
+				BanTime:   "1h",
+				FindTime:  "10m",
+				MaxRetry:  5,
+				IgnoreIPs: []string{"10.0.0.0/8", "192.168.1.10"},
+				Jails: []image.Fail2banJail{
+					{Name: "sshd", Port: "ssh,2222", BanTime: "-1", MaxRetry: 3},
+					{Name: "cockpit", FindTime: "1d"},
+				},
+			},
+			expectedContents: "[DEFAULT]\n" +
+				"# The services of the node log to the journal rather than to files\n" +
+				"backend = systemd\n" +
+				"bantime = 1h\n" +
+				"findtime = 10m\n" +
+				"maxretry = 5\n" +
+				"ignoreip = 10.0.0.0/8 192.168.1.10\n" +
+				"\n" +
+				"[sshd]\n" +
+				"enabled = true\n" +
+				"port = ssh,2222\n" +
+				"bantime = -1\n" +
+				"maxretry = 3\n" +
+				"\n" +
+				"[cockpit]\n" +
+				"enabled = true\n" +
+				"findtime = 1d\n" +
+				"EOF",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			ctx.ImageDefinition.OperatingSystem.Fail2ban = test.fail2ban
+
+			// Test
+			scripts, err := configureFail2ban(ctx)
+
+			// Verify
+			require.NoError(t, err)
+			assert.Equal(t, []string{fail2banScriptName}, scripts)
+
+			scriptPath := filepath.Join(ctx.CombustionDir, fail2banScriptName)
+
+			info, err := os.Stat(scriptPath)
+			require.NoError(t, err)
+			assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+			b, err := os.ReadFile(scriptPath)
+			require.NoError(t, err)
+
+			contents := string(b)
+			assert.Contains(t, contents, "cat <<'EOF' > /etc/fail2ban/jail.d/eib.local\n"+test.expectedContents)
+			assert.Contains(t, contents, "systemctl enable fail2ban.service")
+		})
+	}
+}
+
+func TestFail2banJails(t *testing.T) {
+	fail2ban := &image.Fail2ban{
+		Jails: []image.Fail2banJail{{Name: "sshd"}, {Name: "cockpit"}},
+	}
+
+	assert.Equal(t, []string{"sshd", "cockpit"}, Fail2banJails(fail2ban))
+	assert.Nil(t, Fail2banJails(&image.Fail2ban{}))
+}
@@ -0,0 +1,57 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	gpuDriversComponentName = "gpu drivers"
+	gpuDriversScriptName    = "10a-gpu-drivers.sh"
+)
+
+//go:embed templates/10a-gpu-drivers.sh.tpl
+var gpuDriversScriptTemplate string
+
+// configureGPUDrivers configures the kernel modules of the GPU driver. The driver packages themselves
+// are installed by the RPM component.
+func configureGPUDrivers(ctx *image.Context) ([]string, error) {
+	drivers := &ctx.ImageDefinition.OperatingSystem.GPUDrivers
+	modules := &drivers.KernelModules
+
+	if len(modules.Load) == 0 && len(modules.Options) == 0 && len(modules.Blacklist) == 0 {
+		log.AuditComponentSkipped(gpuDriversComponentName)
+		return nil, nil
+	}
+
+	data, err := template.Parse(gpuDriversScriptName, gpuDriversScriptTemplate, modules)
+	if err != nil {
+		log.AuditComponentFailed(gpuDriversComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", gpuDriversScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, gpuDriversScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(gpuDriversComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	if len(modules.Load) > 0 {
+		log.AuditInfof("Loading the GPU driver kernel modules [%s] at boot.", strings.Join(modules.Load, ", "))
+	}
+
+	if len(modules.Blacklist) > 0 {
+		log.AuditInfof("Blacklisting the kernel modules [%s].", strings.Join(modules.Blacklist, ", "))
+	}
+
+	log.AuditComponentSuccessful(gpuDriversComponentName)
+	return []string{gpuDriversScriptName}, nil
+}
@@ -0,0 +1,109 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureGPUDrivers_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			GPUDrivers: image.GPUDrivers{
+				Packages: []string{"nvidia-open-driver-G06-signed-kmp-default"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureGPUDrivers(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, gpuDriversScriptName))
+}
+
+func TestConfigureGPUDrivers(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			GPUDrivers: image.GPUDrivers{
+				Packages: []string{"nvidia-open-driver-G06-signed-kmp-default"},
+				KernelModules: image.GPUKernelModules{
+					Load: []string{"nvidia", "nvidia-uvm"},
+					Options: map[string]string{
+						"nvidia_drm": "modeset=1",
+						"nvidia":     "NVreg_EnableGpuFirmware=1",
+					},
+					Blacklist: []string{"nouveau"},
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureGPUDrivers(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, gpuDriversScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, gpuDriversScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<- 'EOF' > /etc/modules-load.d/eib-gpu-drivers.conf\nnvidia\nnvidia-uvm\nEOF")
+	assert.Contains(t, foundContents, "cat <<- 'EOF' > /etc/modprobe.d/50-eib-gpu-drivers.conf\n"+
+		"options nvidia NVreg_EnableGpuFirmware=1\noptions nvidia_drm modeset=1\nblacklist nouveau\nEOF")
+}
+
+func TestConfigureGPUDrivers_BlacklistOnly(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			GPUDrivers: image.GPUDrivers{
+				KernelModules: image.GPUKernelModules{
+					Blacklist: []string{"nouveau"},
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureGPUDrivers(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, gpuDriversScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.NotContains(t, foundContents, "modules-load.d")
+	assert.Contains(t, foundContents, "blacklist nouveau")
+	assert.NotContains(t, foundContents, "options")
+}
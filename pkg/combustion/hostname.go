@@ -0,0 +1,54 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	hostnameComponentName = "hostname"
+	// hostnameScriptName runs after the network configuration, so that the static hostname takes precedence
+	// over the one set by nmc
+	hostnameScriptName = "05a-hostname.sh"
+)
+
+//go:embed templates/05a-hostname.sh.tpl
+var hostnameScriptTemplate string
+
+func configureHostname(ctx *image.Context) ([]string, error) {
+	hostname := ctx.ImageDefinition.OperatingSystem.Hostname
+	if hostname == "" {
+		log.AuditComponentSkipped(hostnameComponentName)
+		return nil, nil
+	}
+
+	values := struct {
+		Hostname string
+	}{
+		Hostname: hostname,
+	}
+
+	data, err := template.Parse(hostnameScriptName, hostnameScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(hostnameComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", hostnameScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, hostnameScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(hostnameComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("The hostname of the node will be set to '%s'.", hostname)
+
+	log.AuditComponentSuccessful(hostnameComponentName)
+	return []string{hostnameScriptName}, nil
+}
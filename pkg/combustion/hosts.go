@@ -0,0 +1,57 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	hostsComponentName = "hosts"
+	hostsScriptName    = "09-hosts.sh"
+)
+
+//go:embed templates/09-hosts.sh.tpl
+var hostsScriptTemplate string
+
+func configureHosts(ctx *image.Context) ([]string, error) {
+	hosts := ctx.ImageDefinition.OperatingSystem.Hosts
+	if len(hosts) == 0 {
+		log.AuditComponentSkipped(hostsComponentName)
+		return nil, nil
+	}
+
+	values := struct {
+		Hosts []image.HostEntry
+	}{
+		Hosts: hosts,
+	}
+
+	data, err := template.Parse(hostsScriptName, hostsScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(hostsComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", hostsScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, hostsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(hostsComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	var entries []string
+	for _, host := range hosts {
+		entries = append(entries, fmt.Sprintf("%s (%s)", host.IP, strings.Join(host.Hostnames, ", ")))
+	}
+	log.AuditInfof("Adding %d static entries to /etc/hosts: %s", len(hosts), strings.Join(entries, "; "))
+
+	log.AuditComponentSuccessful(hostsComponentName)
+	return []string{hostsScriptName}, nil
+}
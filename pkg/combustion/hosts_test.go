@@ -0,0 +1,66 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureHosts_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureHosts(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureHosts(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Hosts: []image.HostEntry{
+				{IP: "192.168.1.10", Hostnames: []string{"registry.local", "registry"}},
+				{IP: "fd00::10", Hostnames: []string{"mirror.local"}},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureHosts(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, hostsScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, hostsScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<'EOF' >> /etc/hosts")
+	assert.Contains(t, foundContents, "# Static host entries added by Edge Image Builder\n"+
+		"192.168.1.10 registry.local registry\n"+
+		"fd00::10 mirror.local\n"+
+		"EOF")
+}
@@ -0,0 +1,96 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	imageArchivesComponentName = "image archives"
+	imageArchivesScriptName    = "28-image-archives.sh"
+	imageArchivesDir           = "image-archives"
+)
+
+//go:embed templates/28-image-archives.sh.tpl
+var imageArchivesScriptTemplate string
+
+// configureImageArchives places the container images on the node as archives, which are loaded on demand
+// rather than imported into a container runtime. The placed archives are recorded for the build report.
+func (c *Combustion) configureImageArchives(ctx *image.Context) ([]string, error) {
+	archives := &ctx.ImageDefinition.OperatingSystem.ImageArchives
+	if len(archives.Images) == 0 {
+		log.AuditComponentSkipped(imageArchivesComponentName)
+		return nil, nil
+	}
+
+	destDir := filepath.Join(ctx.ArtefactsDir, imageArchivesDir)
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		log.AuditComponentFailed(imageArchivesComponentName)
+		return nil, fmt.Errorf("creating image archives directory '%s': %w", destDir, err)
+	}
+
+	var placed []image.ImageArchive
+	for _, img := range archives.Images {
+		archiveName := imageArchiveName(img.Name)
+		archivePath := filepath.Join(destDir, archiveName)
+
+		zap.S().Infof("Archiving image '%s' as '%s'", img.Name, archiveName)
+
+		if err := c.ContainerImageArchiver.SaveImage(ctx.Context(), img.Name, ctx.ImageDefinition.Image.Arch, archivePath); err != nil {
+			log.AuditComponentFailed(imageArchivesComponentName)
+			return nil, fmt.Errorf("archiving image '%s': %w", img.Name, err)
+		}
+
+		info, err := os.Stat(archivePath)
+		if err != nil {
+			log.AuditComponentFailed(imageArchivesComponentName)
+			return nil, fmt.Errorf("reading archive of image '%s': %w", img.Name, err)
+		}
+
+		placed = append(placed, image.ImageArchive{
+			Image: img.Name,
+			Path:  filepath.Join(archives.Path, archiveName),
+			Size:  info.Size(),
+		})
+	}
+
+	values := struct {
+		ArchivesDir string
+		Path        string
+	}{
+		ArchivesDir: prependArtefactPath(imageArchivesDir),
+		Path:        filepath.Clean(archives.Path),
+	}
+
+	data, err := template.Parse(imageArchivesScriptName, imageArchivesScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(imageArchivesComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", imageArchivesScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, imageArchivesScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(imageArchivesComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	ctx.ImageArchives = placed
+
+	names := make([]string, 0, len(placed))
+	for _, archive := range placed {
+		names = append(names, archive.Image)
+	}
+	log.AuditInfof("Placing container image archives in '%s': [%s].", archives.Path, strings.Join(names, ", "))
+
+	log.AuditComponentSuccessful(imageArchivesComponentName)
+	return []string{imageArchivesScriptName}, nil
+}
@@ -0,0 +1,116 @@
+package combustion
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureImageArchives_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	var c Combustion
+
+	// Test
+	scripts, err := c.configureImageArchives(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+	assert.Nil(t, ctx.ImageArchives)
+}
+
+func TestConfigureImageArchives(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			Arch: image.ArchTypeX86,
+		},
+		OperatingSystem: image.OperatingSystem{
+			ImageArchives: image.ImageArchives{
+				Path: "/var/lib/images/",
+				Images: []image.ContainerImage{
+					{Name: "registry.example.com/tools/loader:1.0"},
+					{Name: "nginx:1.25"},
+				},
+			},
+		},
+	}
+
+	c := Combustion{
+		ContainerImageArchiver: mockContainerImageArchiver{
+			saveImageFunc: func(containerImage string, arch image.Arch, archivePath string) error {
+				assert.Equal(t, image.ArchTypeX86, arch)
+
+				return os.WriteFile(archivePath, []byte(containerImage), fileio.NonExecutablePerms)
+			},
+		},
+	}
+
+	// Test
+	scripts, err := c.configureImageArchives(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{imageArchivesScriptName}, scripts)
+
+	assert.FileExists(t, filepath.Join(ctx.ArtefactsDir, imageArchivesDir, "registry.example.com_tools_loader_1.0.tar"))
+	assert.FileExists(t, filepath.Join(ctx.ArtefactsDir, imageArchivesDir, "nginx_1.25.tar"))
+
+	assert.Equal(t, []image.ImageArchive{
+		{Image: "registry.example.com/tools/loader:1.0", Path: "/var/lib/images/registry.example.com_tools_loader_1.0.tar", Size: 37},
+		{Image: "nginx:1.25", Path: "/var/lib/images/nginx_1.25.tar", Size: 10},
+	}, ctx.ImageArchives)
+
+	scriptPath := filepath.Join(ctx.CombustionDir, imageArchivesScriptName)
+
+	info, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+	b, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "dir='/var/lib/images'")
+	assert.Contains(t, contents, "mkdir -p '/var/lib/images'")
+	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/image-archives/*.tar '/var/lib/images/'")
+}
+
+func TestConfigureImageArchives_SaveFailure(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.ImageArchives = image.ImageArchives{
+		Path:   "/opt/images",
+		Images: []image.ContainerImage{{Name: "nginx:1.25"}},
+	}
+
+	c := Combustion{
+		ContainerImageArchiver: mockContainerImageArchiver{
+			saveImageFunc: func(string, image.Arch, string) error {
+				return errors.New("manifest unknown")
+			},
+		},
+	}
+
+	// Test
+	scripts, err := c.configureImageArchives(ctx)
+
+	// Verify
+	require.EqualError(t, err, "archiving image 'nginx:1.25': manifest unknown")
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, imageArchivesScriptName))
+}
@@ -0,0 +1,66 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	journaldComponentName = "journald"
+	journaldScriptName    = "19-journald.sh"
+)
+
+//go:embed templates/19-journald.sh.tpl
+var journaldScriptTemplate string
+
+func configureJournald(ctx *image.Context) ([]string, error) {
+	journald := ctx.ImageDefinition.OperatingSystem.Journald
+	if journald == (image.Journald{}) {
+		log.AuditComponentSkipped(journaldComponentName)
+		return nil, nil
+	}
+
+	data, err := template.Parse(journaldScriptName, journaldScriptTemplate, &journald)
+	if err != nil {
+		log.AuditComponentFailed(journaldComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", journaldScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, journaldScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(journaldComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("The journal will be configured with %s.", describeJournald(&journald))
+
+	log.AuditComponentSuccessful(journaldComponentName)
+	return []string{journaldScriptName}, nil
+}
+
+// describeJournald lists the configured settings, while any setting left unset keeps the journald default.
+func describeJournald(journald *image.Journald) string {
+	var settings []string
+
+	add := func(name, value string) {
+		if value != "" {
+			settings = append(settings, fmt.Sprintf("%s '%s'", name, value))
+		}
+	}
+
+	add("storage", journald.Storage)
+	add("system max use", journald.SystemMaxUse)
+	add("system max file size", journald.SystemMaxFileSize)
+	add("runtime max use", journald.RuntimeMaxUse)
+	add("max retention", journald.MaxRetention)
+
+	return strings.Join(settings, ", ")
+}
@@ -0,0 +1,80 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureJournald_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureJournald(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureJournald(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Journald: image.Journald{
+				Storage:      "persistent",
+				SystemMaxUse: "1G",
+				MaxRetention: "1month",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureJournald(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, journaldScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, journaldScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<- EOF > /etc/systemd/journald.conf.d/50-eib.conf\n"+
+		"[Journal]\n"+
+		"Storage=persistent\n"+
+		"SystemMaxUse=1G\n"+
+		"MaxRetentionSec=1month\n"+
+		"EOF")
+	assert.Contains(t, foundContents, "mkdir -p /var/log/journal")
+	assert.NotContains(t, foundContents, "SystemMaxFileSize")
+	assert.NotContains(t, foundContents, "RuntimeMaxUse")
+}
+
+func TestDescribeJournald(t *testing.T) {
+	journald := &image.Journald{
+		Storage:       "volatile",
+		RuntimeMaxUse: "64M",
+	}
+
+	assert.Equal(t, "storage 'volatile', runtime max use '64M'", describeJournald(journald))
+}
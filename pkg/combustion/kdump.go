@@ -0,0 +1,62 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	kdumpComponentName = "kdump"
+	kdumpScriptName    = "19b-kdump.sh"
+
+	// KdumpPackage is installed along with the other packages when kdump is configured.
+	KdumpPackage = "kdump"
+)
+
+//go:embed templates/19b-kdump.sh.tpl
+var kdumpScriptTemplate string
+
+// configureKdump enables kdump and configures where the dumps are saved to. The memory of the crash kernel
+// is reserved through the kernel command line while assembling the image.
+func configureKdump(ctx *image.Context) ([]string, error) {
+	kdump := &ctx.ImageDefinition.OperatingSystem.Kdump
+
+	if kdump.Memory == "" {
+		log.AuditComponentSkipped(kdumpComponentName)
+		return nil, nil
+	}
+
+	data, err := template.Parse(kdumpScriptName, kdumpScriptTemplate, kdump)
+	if err != nil {
+		log.AuditComponentFailed(kdumpComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", kdumpScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, kdumpScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(kdumpComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("kdump will be enabled with %s reserved for the crash kernel, saving the dumps to %s.",
+		kdump.Memory, KdumpSavePath(kdump))
+
+	log.AuditComponentSuccessful(kdumpComponentName)
+	return []string{kdumpScriptName}, nil
+}
+
+// KdumpSavePath returns the directory the dumps are saved to.
+func KdumpSavePath(kdump *image.Kdump) string {
+	if kdump.SavePath == "" {
+		return image.KdumpDefaultSavePath
+	}
+
+	return kdump.SavePath
+}
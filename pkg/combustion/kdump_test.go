@@ -0,0 +1,85 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureKdump_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	scripts, err := configureKdump(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, kdumpScriptName))
+}
+
+func TestConfigureKdump(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Kdump = image.Kdump{
+		Memory:   "512M",
+		SavePath: "/var/lib/crash",
+	}
+
+	// Test
+	scripts, err := configureKdump(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{kdumpScriptName}, scripts)
+
+	scriptPath := filepath.Join(ctx.CombustionDir, kdumpScriptName)
+
+	info, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+	b, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "mkdir -p '/var/lib/crash'")
+	assert.Contains(t, contents, `sed -i 's|^KDUMP_SAVEDIR=.*|KDUMP_SAVEDIR="file:///var/lib/crash"|' /etc/sysconfig/kdump`)
+	assert.Contains(t, contents, "systemctl enable kdump.service")
+}
+
+func TestConfigureKdump_DefaultSavePath(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Kdump = image.Kdump{Memory: "512M"}
+
+	// Test
+	scripts, err := configureKdump(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{kdumpScriptName}, scripts)
+
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, kdumpScriptName))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.NotContains(t, contents, "KDUMP_SAVEDIR")
+	assert.Contains(t, contents, "systemctl enable kdump.service")
+}
+
+func TestKdumpSavePath(t *testing.T) {
+	assert.Equal(t, "/var/crash", KdumpSavePath(&image.Kdump{Memory: "512M"}))
+	assert.Equal(t, "/var/lib/crash", KdumpSavePath(&image.Kdump{Memory: "512M", SavePath: "/var/lib/crash"}))
+}
@@ -0,0 +1,57 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	knownHostsComponentName = "known hosts"
+	knownHostsScriptName    = "09a-known-hosts.sh"
+)
+
+//go:embed templates/09a-known-hosts.sh.tpl
+var knownHostsScriptTemplate string
+
+func configureKnownHosts(ctx *image.Context) ([]string, error) {
+	knownHosts := ctx.ImageDefinition.OperatingSystem.KnownHosts
+	if len(knownHosts) == 0 {
+		log.AuditComponentSkipped(knownHostsComponentName)
+		return nil, nil
+	}
+
+	values := struct {
+		KnownHosts []image.KnownHost
+	}{
+		KnownHosts: knownHosts,
+	}
+
+	data, err := template.Parse(knownHostsScriptName, knownHostsScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(knownHostsComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", knownHostsScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, knownHostsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(knownHostsComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	var entries []string
+	for _, knownHost := range knownHosts {
+		entries = append(entries, fmt.Sprintf("%s (%s)", knownHost.Host, strings.Fields(knownHost.Key)[0]))
+	}
+	log.AuditInfof("Adding %d SSH host keys to /etc/ssh/ssh_known_hosts: %s", len(knownHosts), strings.Join(entries, "; "))
+
+	log.AuditComponentSuccessful(knownHostsComponentName)
+	return []string{knownHostsScriptName}, nil
+}
@@ -0,0 +1,66 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureKnownHosts_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureKnownHosts(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureKnownHosts(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			KnownHosts: []image.KnownHost{
+				{Host: "git.example.com", Key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIPOAHN0CdWw9HIwQ/eAq2s5xUztoAjAXPJH88XRfllmN"},
+				{Host: "[registry.example.com]:2222", Key: "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTY="},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureKnownHosts(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, knownHostsScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, knownHostsScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<'EOF' >> /etc/ssh/ssh_known_hosts")
+	assert.Contains(t, foundContents, "# SSH host keys added by Edge Image Builder\n"+
+		"git.example.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIPOAHN0CdWw9HIwQ/eAq2s5xUztoAjAXPJH88XRfllmN\n"+
+		"[registry.example.com]:2222 ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTY=\n"+
+		"EOF")
+}
@@ -18,7 +18,7 @@ import (
 )
 
 const (
-	k8sComponentName = "kubernetes"
+	K8sComponentName = "kubernetes"
 
 	K8sDir          = "kubernetes"
 	k8sConfigDir    = "config"
@@ -29,8 +29,16 @@ const (
 	k8sInitServerConfigFile = "init_server.yaml"
 	k8sServerConfigFile     = "server.yaml"
 	k8sAgentConfigFile      = "agent.yaml"
+	k8sNodeConfigsDir       = "nodes"
 
 	k8sInstallScript = "20-k8s-install.sh"
+
+	k8sInstallGateDefaultTimeout = 300
+
+	k8sKubeconfigServiceK3s  = "k3s.service"
+	k8sKubeconfigServiceRKE2 = "rke2-server.service"
+	k8sKubeconfigSourceK3s   = "/etc/rancher/k3s/k3s.yaml"
+	k8sKubeconfigSourceRKE2  = "/etc/rancher/rke2/rke2.yaml"
 )
 
 var (
@@ -48,19 +56,25 @@ var (
 
 	//go:embed templates/k8s-vip.yaml.tpl
 	k8sVIPManifest string
+
+	//go:embed templates/k8s-install-gate.sh.tpl
+	k8sInstallGate string
+
+	//go:embed templates/k8s-kubeconfig.sh.tpl
+	k8sKubeconfig string
 )
 
 func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 	version := ctx.ImageDefinition.Kubernetes.Version
 
 	if version == "" {
-		log.AuditComponentSkipped(k8sComponentName)
+		log.AuditComponentSkipped(K8sComponentName)
 		return nil, nil
 	}
 
 	configureFunc := c.kubernetesConfigurator(version)
 	if configureFunc == nil {
-		log.AuditComponentFailed(k8sComponentName)
+		log.AuditComponentFailed(K8sComponentName)
 		return nil, fmt.Errorf("cannot configure kubernetes version: %s", version)
 	}
 
@@ -78,7 +92,7 @@ func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 
 	cluster, err := kubernetes.NewCluster(&ctx.ImageDefinition.Kubernetes, configPath)
 	if err != nil {
-		log.AuditComponentFailed(k8sComponentName)
+		log.AuditComponentFailed(K8sComponentName)
 		return nil, fmt.Errorf("initialising cluster config: %w", err)
 	}
 
@@ -88,17 +102,35 @@ func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 	}
 
 	if err = storeKubernetesClusterConfig(cluster, artefactsPath); err != nil {
-		log.AuditComponentFailed(k8sComponentName)
+		log.AuditComponentFailed(K8sComponentName)
 		return nil, fmt.Errorf("storing cluster config: %w", err)
 	}
 
+	for i := range ctx.ImageDefinition.Kubernetes.Nodes {
+		node := &ctx.ImageDefinition.Kubernetes.Nodes[i]
+		if metadata := NodeMetadata(node); metadata != "" {
+			log.AuditInfof("Kubernetes node%s will be registered with %s.", nodeDescription(node.Hostname), metadata)
+		}
+	}
+
+	if strategy := ctx.ImageDefinition.Kubernetes.InstallGate.Strategy; strategy != "" {
+		log.AuditInfof("Kubernetes installation will be gated using the '%s' strategy.", strategy)
+	}
+
+	if path := ctx.ImageDefinition.Kubernetes.Kubeconfig.Path; path != "" {
+		log.AuditInfof("The admin kubeconfig will be placed at '%s' on server nodes.", path)
+		log.Audit("WARNING: The admin kubeconfig grants full access to the cluster. " +
+			"Make sure its location is not readable by unprivileged users or exposed by other services.")
+		zap.S().Warnf("Admin kubeconfig is requested to be placed at '%s'", path)
+	}
+
 	script, err := configureFunc(ctx, cluster)
 	if err != nil {
-		log.AuditComponentFailed(k8sComponentName)
+		log.AuditComponentFailed(K8sComponentName)
 		return nil, fmt.Errorf("configuring kubernetes components: %w", err)
 	}
 
-	log.AuditComponentSuccessful(k8sComponentName)
+	log.AuditComponentSuccessful(K8sComponentName)
 	return []string{script}, nil
 }
 
@@ -116,7 +148,7 @@ func (c *Combustion) kubernetesConfigurator(version string) func(*image.Context,
 func (c *Combustion) downloadKubernetesInstallScript(ctx *image.Context, distribution string) (string, error) {
 	path := kubernetesArtefactsPath(ctx)
 
-	installScript, err := c.KubernetesScriptDownloader.DownloadInstallScript(distribution, path)
+	installScript, err := c.KubernetesScriptDownloader.DownloadInstallScript(ctx.Context(), distribution, path)
 	if err != nil {
 		return "", fmt.Errorf("downloading install script: %w", err)
 	}
@@ -142,6 +174,11 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 		return "", fmt.Errorf("configuring kubernetes manifests: %w", err)
 	}
 
+	containerdConfig, containerdConfigFile, err := configureContainerd(ctx)
+	if err != nil {
+		return "", fmt.Errorf("configuring containerd: %w", err)
+	}
+
 	templateValues := map[string]any{
 		"installScript":   installScript,
 		"apiVIP":          ctx.ImageDefinition.Kubernetes.Network.APIVIP,
@@ -151,6 +188,9 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 		"manifestsPath":   manifestsPath,
 		"configFilePath":  prependArtefactPath(K8sDir),
 		"registryMirrors": prependArtefactPath(filepath.Join(K8sDir, registryMirrorsFileName)),
+
+		"containerdConfig":     containerdConfig,
+		"containerdConfigFile": containerdConfigFile,
 	}
 
 	singleNode := len(ctx.ImageDefinition.Kubernetes.Nodes) < 2
@@ -174,6 +214,7 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 	templateValues["nodes"] = ctx.ImageDefinition.Kubernetes.Nodes
 	templateValues["initialiser"] = cluster.InitialiserName
 	templateValues["initialiserConfigFile"] = k8sInitServerConfigFile
+	templateValues["nodeConfigsDir"] = k8sNodeConfigsDir
 
 	return storeKubernetesInstaller(ctx, "multi-node-k3s", k3sMultiNodeInstaller, templateValues)
 }
@@ -192,6 +233,7 @@ func (c *Combustion) downloadK3sArtefacts(ctx *image.Context) (binaryPath, image
 	}
 
 	if err = c.KubernetesArtefactDownloader.DownloadK3sArtefacts(
+		ctx.Context(),
 		ctx.ImageDefinition.Image.Arch,
 		ctx.ImageDefinition.Kubernetes.Version,
 		installDestination,
@@ -237,6 +279,11 @@ func (c *Combustion) configureRKE2(ctx *image.Context, cluster *kubernetes.Clust
 		return "", fmt.Errorf("configuring kubernetes manifests: %w", err)
 	}
 
+	containerdConfig, containerdConfigFile, err := configureContainerd(ctx)
+	if err != nil {
+		return "", fmt.Errorf("configuring containerd: %w", err)
+	}
+
 	templateValues := map[string]any{
 		"installScript":   installScript,
 		"apiVIP":          ctx.ImageDefinition.Kubernetes.Network.APIVIP,
@@ -246,6 +293,9 @@ func (c *Combustion) configureRKE2(ctx *image.Context, cluster *kubernetes.Clust
 		"manifestsPath":   manifestsPath,
 		"configFilePath":  prependArtefactPath(K8sDir),
 		"registryMirrors": prependArtefactPath(filepath.Join(K8sDir, registryMirrorsFileName)),
+
+		"containerdConfig":     containerdConfig,
+		"containerdConfigFile": containerdConfigFile,
 	}
 
 	singleNode := len(ctx.ImageDefinition.Kubernetes.Nodes) < 2
@@ -262,11 +312,24 @@ func (c *Combustion) configureRKE2(ctx *image.Context, cluster *kubernetes.Clust
 	templateValues["nodes"] = ctx.ImageDefinition.Kubernetes.Nodes
 	templateValues["initialiser"] = cluster.InitialiserName
 	templateValues["initialiserConfigFile"] = k8sInitServerConfigFile
+	templateValues["nodeConfigsDir"] = k8sNodeConfigsDir
 
 	return storeKubernetesInstaller(ctx, "multi-node-rke2", rke2MultiNodeInstaller, templateValues)
 }
 
-func storeKubernetesInstaller(ctx *image.Context, templateName, templateContents string, templateValues any) (string, error) {
+func storeKubernetesInstaller(ctx *image.Context, templateName, templateContents string, templateValues map[string]any) (string, error) {
+	installGate, err := kubernetesInstallGate(&ctx.ImageDefinition.Kubernetes.InstallGate)
+	if err != nil {
+		return "", fmt.Errorf("generating install gate: %w", err)
+	}
+	templateValues["installGate"] = installGate
+
+	kubeconfig, err := kubernetesKubeconfig(&ctx.ImageDefinition.Kubernetes)
+	if err != nil {
+		return "", fmt.Errorf("generating kubeconfig distribution: %w", err)
+	}
+	templateValues["kubeconfig"] = kubeconfig
+
 	data, err := template.Parse(templateName, templateContents, templateValues)
 	if err != nil {
 		return "", fmt.Errorf("parsing '%s' template: %w", templateName, err)
@@ -280,6 +343,52 @@ func storeKubernetesInstaller(ctx *image.Context, templateName, templateContents
 	return k8sInstallScript, nil
 }
 
+func kubernetesInstallGate(gate *image.InstallGate) (string, error) {
+	if gate.Strategy == "" {
+		return "", nil
+	}
+
+	values := *gate
+	if values.Timeout == 0 {
+		values.Timeout = k8sInstallGateDefaultTimeout
+	}
+
+	// The URL is single-quoted in the script, so that the shell does not interpret any of its characters
+	values.URL = strings.ReplaceAll(values.URL, "'", `'\''`)
+
+	data, err := template.Parse("k8s-install-gate", k8sInstallGate, values)
+	if err != nil {
+		return "", fmt.Errorf("parsing install gate template: %w", err)
+	}
+
+	return strings.TrimSpace(data), nil
+}
+
+func kubernetesKubeconfig(k8s *image.Kubernetes) (string, error) {
+	if k8s.Kubeconfig.Path == "" {
+		return "", nil
+	}
+
+	values := map[string]string{
+		"Path":    k8s.Kubeconfig.Path,
+		"Server":  k8s.Kubeconfig.Server,
+		"Source":  k8sKubeconfigSourceK3s,
+		"Service": k8sKubeconfigServiceK3s,
+	}
+
+	if strings.Contains(k8s.Version, image.KubernetesDistroRKE2) {
+		values["Source"] = k8sKubeconfigSourceRKE2
+		values["Service"] = k8sKubeconfigServiceRKE2
+	}
+
+	data, err := template.Parse("k8s-kubeconfig", k8sKubeconfig, values)
+	if err != nil {
+		return "", fmt.Errorf("parsing kubeconfig template: %w", err)
+	}
+
+	return strings.TrimSpace(data), nil
+}
+
 func (c *Combustion) downloadRKE2Artefacts(ctx *image.Context, cluster *kubernetes.Cluster) (installPath, imagesPath string, err error) {
 	cni, multusEnabled, err := cluster.ExtractCNI()
 	if err != nil {
@@ -299,6 +408,7 @@ func (c *Combustion) downloadRKE2Artefacts(ctx *image.Context, cluster *kubernet
 	}
 
 	if err = c.KubernetesArtefactDownloader.DownloadRKE2Artefacts(
+		ctx.Context(),
 		ctx.ImageDefinition.Image.Arch,
 		ctx.ImageDefinition.Kubernetes.Version,
 		cni,
@@ -324,6 +434,29 @@ func kubernetesVIPManifest(k *image.Kubernetes) (string, error) {
 	return template.Parse("k8s-vip", k8sVIPManifest, &manifest)
 }
 
+// NodeMetadata describes the labels and taints the node is registered with.
+func NodeMetadata(node *image.Node) string {
+	var metadata []string
+
+	if len(node.Labels) > 0 {
+		metadata = append(metadata, "labels "+strings.Join(node.Labels, ", "))
+	}
+
+	if len(node.Taints) > 0 {
+		metadata = append(metadata, "taints "+strings.Join(node.Taints, ", "))
+	}
+
+	return strings.Join(metadata, " and ")
+}
+
+func nodeDescription(hostname string) string {
+	if hostname == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" '%s'", hostname)
+}
+
 func storeKubernetesClusterConfig(cluster *kubernetes.Cluster, destPath string) error {
 	serverConfig := filepath.Join(destPath, k8sServerConfigFile)
 	if err := storeKubernetesConfig(cluster.ServerConfig, serverConfig); err != nil {
@@ -346,6 +479,23 @@ func storeKubernetesClusterConfig(cluster *kubernetes.Cluster, destPath string)
 		}
 	}
 
+	if len(cluster.NodeConfigs) == 0 {
+		return nil
+	}
+
+	nodeConfigsDir := filepath.Join(destPath, k8sNodeConfigsDir)
+	if err := os.MkdirAll(nodeConfigsDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating node configs dir: %w", err)
+	}
+
+	for hostname, config := range cluster.NodeConfigs {
+		nodeConfig := filepath.Join(nodeConfigsDir, hostname+".yaml")
+
+		if err := storeKubernetesConfig(config, nodeConfig); err != nil {
+			return fmt.Errorf("storing config file of node '%s': %w", hostname, err)
+		}
+	}
+
 	return nil
 }
 
@@ -360,6 +510,7 @@ func storeKubernetesConfig(config map[string]any, configPath string) error {
 
 func configureManifests(ctx *image.Context) (string, error) {
 	manifestURLs := ctx.ImageDefinition.Kubernetes.Manifests.URLs
+	manifestsDirectory := ctx.ImageDefinition.Kubernetes.Manifests.Directory
 	localManifestsConfigured := isComponentConfigured(ctx, filepath.Join(K8sDir, k8sManifestsDir))
 
 	manifestsPath := filepath.Join(K8sDir, k8sManifestsDir)
@@ -381,7 +532,7 @@ func configureManifests(ctx *image.Context) (string, error) {
 		}
 	}
 
-	if !localManifestsConfigured && len(manifestURLs) == 0 {
+	if !localManifestsConfigured && len(manifestURLs) == 0 && manifestsDirectory == "" {
 		// The registry component would have already created and populated the manifests path if helm resources are configured
 		// or required. This is a hack until the dependencies between the different combustion components are resolved.
 		if _, err := os.Stat(manifestDestDir); err == nil {
@@ -408,8 +559,14 @@ func configureManifests(ctx *image.Context) (string, error) {
 		}
 	}
 
+	if manifestsDirectory != "" {
+		if err = copyManifestsDirectory(manifestsDirectoryPath(ctx), manifestDestDir); err != nil {
+			return "", fmt.Errorf("copying manifests directory to combustion dir: %w", err)
+		}
+	}
+
 	if len(manifestURLs) != 0 {
-		_, err = registry.DownloadManifests(manifestURLs, manifestDestDir)
+		_, err = registry.DownloadManifests(ctx.Context(), manifestURLs, manifestDestDir)
 		if err != nil {
 			return "", fmt.Errorf("downloading manifests to combustion dir: %w", err)
 		}
@@ -418,10 +575,62 @@ func configureManifests(ctx *image.Context) (string, error) {
 	return prependArtefactPath(manifestsPath), nil
 }
 
+// copyManifestsDirectory copies the manifests from the user specified directory, preserving
+// their lexical ordering which Kubernetes also uses when applying them at boot.
+func copyManifestsDirectory(srcDir, destDir string) error {
+	manifestPaths, err := registry.ManifestPaths(srcDir)
+	if err != nil {
+		return fmt.Errorf("listing manifests: %w", err)
+	}
+
+	if len(manifestPaths) == 0 {
+		return fmt.Errorf("no manifests found in directory '%s'", srcDir)
+	}
+
+	var manifestNames []string
+
+	for _, manifestPath := range manifestPaths {
+		manifestName := filepath.Base(manifestPath)
+
+		kinds, err := registry.ManifestKinds(manifestPath)
+		if err != nil {
+			return fmt.Errorf("parsing manifest '%s': %w", manifestName, err)
+		}
+
+		for _, kind := range kinds {
+			if !registry.IsKnownKind(kind) {
+				log.Auditf("WARNING: Manifest '%s' contains a resource of unknown kind '%s'. "+
+					"Ensure the matching custom resource definition is available in the cluster.", manifestName, kind)
+				zap.S().Warnf("Manifest '%s' contains unknown kind '%s'", manifestName, kind)
+			}
+		}
+
+		destPath := filepath.Join(destDir, manifestName)
+		if _, err = os.Stat(destPath); err == nil {
+			return fmt.Errorf("manifest '%s' conflicts with an already embedded manifest", manifestName)
+		}
+
+		if err = fileio.CopyFile(manifestPath, destPath, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying manifest '%s': %w", manifestName, err)
+		}
+
+		manifestNames = append(manifestNames, manifestName)
+	}
+
+	log.AuditInfof("Embedding %d manifest(s) from '%s' in the following order: %s",
+		len(manifestNames), filepath.Base(srcDir), strings.Join(manifestNames, ", "))
+
+	return nil
+}
+
 func KubernetesConfigPath(ctx *image.Context) string {
 	return filepath.Join(ctx.ImageConfigDir, K8sDir, k8sConfigDir, k8sServerConfigFile)
 }
 
+func manifestsDirectoryPath(ctx *image.Context) string {
+	return filepath.Join(ctx.ImageConfigDir, ctx.ImageDefinition.Kubernetes.Manifests.Directory)
+}
+
 func kubernetesArtefactsPath(ctx *image.Context) string {
 	return filepath.Join(ctx.ArtefactsDir, K8sDir)
 }
@@ -1,9 +1,11 @@
 package combustion
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,7 +21,7 @@ type mockKubernetesScriptDownloader struct {
 	downloadScript func(distribution, destPath string) (string, error)
 }
 
-func (m mockKubernetesScriptDownloader) DownloadInstallScript(distribution, destPath string) (string, error) {
+func (m mockKubernetesScriptDownloader) DownloadInstallScript(_ context.Context, distribution, destPath string) (string, error) {
 	if m.downloadScript != nil {
 		return m.downloadScript(distribution, destPath)
 	}
@@ -33,6 +35,7 @@ type mockKubernetesArtefactDownloader struct {
 }
 
 func (m mockKubernetesArtefactDownloader) DownloadRKE2Artefacts(
+	_ context.Context,
 	arch image.Arch,
 	version string,
 	cni string,
@@ -47,7 +50,7 @@ func (m mockKubernetesArtefactDownloader) DownloadRKE2Artefacts(
 	panic("not implemented")
 }
 
-func (m mockKubernetesArtefactDownloader) DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error {
+func (m mockKubernetesArtefactDownloader) DownloadK3sArtefacts(_ context.Context, arch image.Arch, version, installPath, imagesPath string) error {
 	if m.downloadK3sArtefacts != nil {
 		return m.downloadK3sArtefacts(arch, version, installPath, imagesPath)
 	}
@@ -385,6 +388,76 @@ func TestConfigureKubernetes_SuccessfulMultiNodeK3sCluster(t *testing.T) {
 	assert.Nil(t, configContents["cluster-init"])
 }
 
+func TestConfigureKubernetes_NodeMetadataMultiNode(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes = image.Kubernetes{
+		Version: "v1.29.0+k3s1",
+		Network: image.Network{
+			APIVIP: "192.168.122.100",
+		},
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+			},
+			{
+				Hostname: "node2.suse.com",
+				Type:     "agent",
+				Labels:   []string{"zone=edge-1"},
+				Taints:   []string{"dedicated=gpu:NoSchedule"},
+			},
+		},
+	}
+
+	c := Combustion{
+		KubernetesScriptDownloader: mockKubernetesScriptDownloader{
+			downloadScript: func(distribution, destPath string) (string, error) {
+				return kubernetesScriptInstaller, nil
+			},
+		},
+		KubernetesArtefactDownloader: mockKubernetesArtefactDownloader{
+			downloadK3sArtefacts: func(arch image.Arch, version, installPath, imagesPath string) error {
+				binary := filepath.Join(installPath, "cool-k3s-binary")
+				return os.WriteFile(binary, nil, os.ModePerm)
+			},
+		},
+	}
+
+	scripts, err := c.configureKubernetes(ctx)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scripts[0]))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "if [ -f $ARTEFACTS_DIR/kubernetes/nodes/$HOSTNAME.yaml ]; then")
+	assert.Contains(t, contents, "CONFIGFILE=$ARTEFACTS_DIR/kubernetes/nodes/$HOSTNAME.yaml")
+
+	assert.NoFileExists(t, filepath.Join(ctx.ArtefactsDir, "kubernetes", "nodes", "node1.suse.com.yaml"))
+
+	b, err = os.ReadFile(filepath.Join(ctx.ArtefactsDir, "kubernetes", "nodes", "node2.suse.com.yaml"))
+	require.NoError(t, err)
+
+	var configContents map[string]any
+	require.NoError(t, yaml.Unmarshal(b, &configContents))
+
+	assert.Equal(t, "https://192.168.122.100:6443", configContents["server"])
+	assert.Equal(t, []any{"zone=edge-1"}, configContents["node-label"])
+	assert.Equal(t, []any{"dedicated=gpu:NoSchedule"}, configContents["node-taint"])
+}
+
+func TestNodeMetadata(t *testing.T) {
+	assert.Empty(t, NodeMetadata(&image.Node{Hostname: "node1.suse.com"}))
+	assert.Equal(t, "labels zone=edge-1", NodeMetadata(&image.Node{Labels: []string{"zone=edge-1"}}))
+	assert.Equal(t, "labels zone=edge-1, gpu=true and taints dedicated=gpu:NoSchedule", NodeMetadata(&image.Node{
+		Labels: []string{"zone=edge-1", "gpu=true"},
+		Taints: []string{"dedicated=gpu:NoSchedule"},
+	}))
+}
+
 func TestConfigureKubernetes_SuccessfulSingleNodeRKE2Cluster(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
@@ -621,3 +694,303 @@ func TestConfigureManifestsNoSetup(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "", manifestsPath)
 }
+
+func TestConfigureManifests_Directory(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes.Manifests.Directory = "ordered-manifests"
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, "ordered-manifests")
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+
+	manifest := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: foo\n"
+	for _, name := range []string{"20-second.yaml", "10-first.yml", "30-third.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, name), []byte(manifest), fileio.NonExecutablePerms))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "README.md"), []byte("ignored"), fileio.NonExecutablePerms))
+
+	// Test
+	manifestsPath, err := configureManifests(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, "$ARTEFACTS_DIR/kubernetes/manifests", manifestsPath)
+
+	entries, err := os.ReadDir(filepath.Join(ctx.ArtefactsDir, K8sDir, k8sManifestsDir))
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"10-first.yml", "20-second.yaml", "30-third.yaml"}, names)
+}
+
+func TestConfigureManifests_DirectoryInvalidManifest(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes.Manifests.Directory = "ordered-manifests"
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, "ordered-manifests")
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "invalid.yaml"), []byte("foo: bar\n"), fileio.NonExecutablePerms))
+
+	// Test
+	_, err := configureManifests(ctx)
+
+	// Verify
+	require.ErrorContains(t, err, "copying manifests directory to combustion dir: parsing manifest 'invalid.yaml'")
+}
+
+func TestKubernetesInstallGate(t *testing.T) {
+	tests := []struct {
+		name             string
+		gate             image.InstallGate
+		expectedContains []string
+		expectedMissing  []string
+	}{
+		{
+			name: "Not configured",
+			gate: image.InstallGate{},
+		},
+		{
+			name: "Delay",
+			gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyDelay,
+				Delay:    60,
+			},
+			expectedContains: []string{
+				"sleep 60",
+			},
+			expectedMissing: []string{
+				"readiness_check",
+			},
+		},
+		{
+			name: "Command with default timeout",
+			gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyCommand,
+				Command:  "ping -c 1 registry.suse.com",
+			},
+			expectedContains: []string{
+				"ping -c 1 registry.suse.com",
+				"until readiness_check; do",
+				"GATE_DEADLINE=$(( $(date +%s) + 300 ))",
+			},
+			expectedMissing: []string{
+				"Delaying Kubernetes installation",
+				"curl",
+			},
+		},
+		{
+			name: "URL with delay and timeout",
+			gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				URL:      "https://registry.suse.com",
+				Delay:    10,
+				Timeout:  600,
+			},
+			expectedContains: []string{
+				"sleep 10",
+				"curl --silent --fail --output /dev/null 'https://registry.suse.com'",
+				"GATE_DEADLINE=$(( $(date +%s) + 600 ))",
+			},
+		},
+		{
+			name: "URL with shell metacharacters",
+			gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				URL:      "https://registry.suse.com/$(rm -rf /)?q='`reboot`",
+			},
+			expectedContains: []string{
+				"curl --silent --fail --output /dev/null 'https://registry.suse.com/$(rm -rf /)?q='\\''`reboot`'",
+			},
+			expectedMissing: []string{
+				"\"https://registry.suse.com",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gate, err := kubernetesInstallGate(&test.gate)
+			require.NoError(t, err)
+
+			if test.gate.Strategy == "" {
+				assert.Empty(t, gate)
+				return
+			}
+
+			for _, expected := range test.expectedContains {
+				assert.Contains(t, gate, expected)
+			}
+
+			for _, missing := range test.expectedMissing {
+				assert.NotContains(t, gate, missing)
+			}
+		})
+	}
+}
+
+func TestConfigureKubernetes_InstallGate(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes = image.Kubernetes{
+		Version: "v1.29.0+k3s1",
+		InstallGate: image.InstallGate{
+			Strategy: image.InstallGateStrategyCommand,
+			Command:  "nslookup registry.suse.com",
+		},
+	}
+
+	c := Combustion{
+		KubernetesScriptDownloader: mockKubernetesScriptDownloader{
+			downloadScript: func(distribution, destPath string) (string, error) {
+				return kubernetesScriptInstaller, nil
+			},
+		},
+		KubernetesArtefactDownloader: mockKubernetesArtefactDownloader{
+			downloadK3sArtefacts: func(arch image.Arch, version string, installPath, imagesPath string) error {
+				binary := filepath.Join(installPath, "cool-k3s-binary")
+				return os.WriteFile(binary, nil, os.ModePerm)
+			},
+		},
+	}
+
+	scripts, err := c.configureKubernetes(ctx)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scripts[0]))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "nslookup registry.suse.com")
+	assert.Contains(t, contents, "until readiness_check; do")
+
+	// The readiness check must take place before any of the installation steps
+	assert.Less(t, strings.Index(contents, "until readiness_check; do"), strings.Index(contents, "mount /var"))
+}
+
+func TestKubernetesKubeconfig(t *testing.T) {
+	tests := []struct {
+		name             string
+		k8s              image.Kubernetes
+		expectedContains []string
+		expectedMissing  []string
+	}{
+		{
+			name: "Not configured",
+			k8s: image.Kubernetes{
+				Version: "v1.29.0+rke2r1",
+			},
+		},
+		{
+			name: "RKE2",
+			k8s: image.Kubernetes{
+				Version: "v1.29.0+rke2r1",
+				Kubeconfig: image.Kubeconfig{
+					Path: "/root/.kube/config",
+				},
+			},
+			expectedContains: []string{
+				"After=rke2-server.service",
+				"ExecStart=/usr/bin/install -D -m 600 /etc/rancher/rke2/rke2.yaml /root/.kube/config",
+				"systemctl enable eib-kubeconfig.service",
+			},
+			expectedMissing: []string{
+				"sed",
+			},
+		},
+		{
+			name: "K3s with server address",
+			k8s: image.Kubernetes{
+				Version: "v1.29.0+k3s1",
+				Kubeconfig: image.Kubeconfig{
+					Path:   "/opt/kubeconfig.yaml",
+					Server: "https://192.168.122.100:6443",
+				},
+			},
+			expectedContains: []string{
+				"After=k3s.service",
+				"ExecStart=/usr/bin/install -D -m 600 /etc/rancher/k3s/k3s.yaml /opt/kubeconfig.yaml",
+				`ExecStart=/usr/bin/sed -i "s|server: .*|server: https://192.168.122.100:6443|" /opt/kubeconfig.yaml`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kubeconfig, err := kubernetesKubeconfig(&test.k8s)
+			require.NoError(t, err)
+
+			if test.k8s.Kubeconfig.Path == "" {
+				assert.Empty(t, kubeconfig)
+				return
+			}
+
+			for _, expected := range test.expectedContains {
+				assert.Contains(t, kubeconfig, expected)
+			}
+
+			for _, missing := range test.expectedMissing {
+				assert.NotContains(t, kubeconfig, missing)
+			}
+		})
+	}
+}
+
+func TestConfigureKubernetes_KubeconfigMultiNode(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes = image.Kubernetes{
+		Version: "v1.29.0+k3s1",
+		Nodes: []image.Node{
+			{
+				Hostname: "node1",
+				Type:     image.KubernetesNodeTypeServer,
+			},
+			{
+				Hostname: "node2",
+				Type:     image.KubernetesNodeTypeAgent,
+			},
+		},
+		Kubeconfig: image.Kubeconfig{
+			Path: "/root/.kube/config",
+		},
+	}
+
+	c := Combustion{
+		KubernetesScriptDownloader: mockKubernetesScriptDownloader{
+			downloadScript: func(distribution, destPath string) (string, error) {
+				return kubernetesScriptInstaller, nil
+			},
+		},
+		KubernetesArtefactDownloader: mockKubernetesArtefactDownloader{
+			downloadK3sArtefacts: func(arch image.Arch, version string, installPath, imagesPath string) error {
+				binary := filepath.Join(installPath, "cool-k3s-binary")
+				return os.WriteFile(binary, nil, os.ModePerm)
+			},
+		},
+	}
+
+	scripts, err := c.configureKubernetes(ctx)
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	b, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scripts[0]))
+	require.NoError(t, err)
+
+	contents := string(b)
+	assert.Contains(t, contents, "if [ \"$NODETYPE\" = \"server\" ]; then\n# The admin kubeconfig is only generated")
+	assert.Contains(t, contents, "ExecStart=/usr/bin/install -D -m 600 /etc/rancher/k3s/k3s.yaml /root/.kube/config")
+
+	// The kubeconfig can only be distributed once Kubernetes is installed
+	assert.Less(t, strings.Index(contents, kubernetesScriptInstaller), strings.Index(contents, "eib-kubeconfig.service"))
+}
@@ -0,0 +1,86 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	localesComponentName = "locales"
+	localesScriptName    = "12a-locales.sh"
+	localeCodeset        = "UTF-8"
+)
+
+//go:embed templates/12a-locales.sh.tpl
+var localesScriptTemplate string
+
+func configureLocales(ctx *image.Context) ([]string, error) {
+	locales := &ctx.ImageDefinition.OperatingSystem.Locales
+	if len(locales.Keep) == 0 && locales.Default == "" {
+		log.AuditComponentSkipped(localesComponentName)
+		return nil, nil
+	}
+
+	var keep, languages []string
+	for _, locale := range locales.Keep {
+		name := LocaleName(locale)
+		keep = append(keep, name)
+
+		language, _, _ := strings.Cut(name, "_")
+		if !slices.Contains(languages, language) {
+			languages = append(languages, language)
+		}
+	}
+
+	defaultLocale := locales.Default
+	if defaultLocale == "" {
+		defaultLocale = locales.Keep[0]
+	}
+	defaultLocale = fmt.Sprintf("%s.%s", LocaleName(defaultLocale), localeCodeset)
+
+	values := struct {
+		Keep      []string
+		Languages []string
+		Default   string
+	}{
+		Keep:      keep,
+		Languages: languages,
+		Default:   defaultLocale,
+	}
+
+	data, err := template.Parse(localesScriptName, localesScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(localesComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", localesScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, localesScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(localesComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	if len(keep) > 0 {
+		log.AuditInfof("Locales other than %s are removed from the node, which reports the reclaimed space in the combustion output.",
+			strings.Join(keep, ", "))
+	}
+	log.AuditInfof("The system locale is set to %s.", defaultLocale)
+
+	log.AuditComponentSuccessful(localesComponentName)
+	return []string{localesScriptName}, nil
+}
+
+// LocaleName returns the locale without its codeset, e.g. en_US for en_US.UTF-8.
+func LocaleName(locale string) string {
+	name, _, _ := strings.Cut(locale, ".")
+	return name
+}
@@ -0,0 +1,95 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureLocales_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureLocales(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+
+	_, err = os.Stat(filepath.Join(ctx.CombustionDir, localesScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfigureLocales(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Locales: image.Locales{
+				Keep: []string{"en_US.UTF-8", "en_GB", "de_DE.utf8"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureLocales(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, localesScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, localesScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "    C|POSIX|en_US|en_GB|de_DE) return 0 ;;")
+	assert.Contains(t, foundContents, "    en|de|en_US|en_GB|de_DE) return 0 ;;")
+	assert.Contains(t, foundContents, `echo "Kept locales: en_US en_GB de_DE; reclaimed $((before - $(locale_size))) KiB"`)
+	assert.Contains(t, foundContents, "echo \"LANG=en_US.UTF-8\" >> /etc/locale.conf")
+}
+
+func TestConfigureLocales_DefaultOnly(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Locales: image.Locales{
+				Default: "de_DE",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureLocales(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, localesScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.NotContains(t, foundContents, "rm -rf")
+	assert.Contains(t, foundContents, "echo \"LANG=de_DE.UTF-8\" >> /etc/locale.conf")
+}
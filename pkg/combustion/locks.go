@@ -0,0 +1,85 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	packageLocksComponentName = "package locks"
+	// Sorts before the RPM installation script, so that excluded packages cannot be pulled in as dependencies
+	packageLocksScriptName = "10-package-locks.sh"
+)
+
+//go:embed templates/10-package-locks.sh.tpl
+var packageLocksTemplate string
+
+func configurePackageLocks(ctx *image.Context) ([]string, error) {
+	packages := ctx.ImageDefinition.OperatingSystem.Packages
+
+	// The pinned kernel is embedded while assembling the image, locking it prevents updates from replacing it
+	locks := slices.Clone(packages.Locks)
+	if packages.Kernel.Version != "" {
+		locks = append(locks, packages.Kernel.Package())
+	}
+
+	if len(locks) == 0 && len(packages.Exclude) == 0 {
+		log.AuditComponentSkipped(packageLocksComponentName)
+		return nil, nil
+	}
+
+	values := struct {
+		Locks   string
+		Exclude string
+	}{
+		Locks:   quotePackages(locks),
+		Exclude: quotePackages(packages.Exclude),
+	}
+
+	data, err := template.Parse(packageLocksScriptName, packageLocksTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(packageLocksComponentName)
+		return nil, fmt.Errorf("applying package locks script template: %w", err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, packageLocksScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(packageLocksComponentName)
+		return nil, fmt.Errorf("writing package locks combustion file: %w", err)
+	}
+
+	if len(packages.Locks) > 0 {
+		log.AuditInfof("Locking packages at their base image versions: %s", strings.Join(packages.Locks, ", "))
+	}
+
+	if packages.Kernel.Version != "" {
+		log.AuditInfof("Locking the pinned kernel %s at version %s", packages.Kernel.Package(), packages.Kernel.Version)
+	}
+
+	if len(packages.Exclude) > 0 {
+		log.AuditInfof("Excluding packages from installation: %s", strings.Join(packages.Exclude, ", "))
+	}
+
+	log.AuditComponentSuccessful(packageLocksComponentName)
+	return []string{packageLocksScriptName}, nil
+}
+
+// quotePackages prevents the shell from expanding wildcards in package names,
+// leaving their interpretation to zypper.
+func quotePackages(packages []string) string {
+	quoted := make([]string, 0, len(packages))
+	for _, p := range packages {
+		quoted = append(quoted, "'"+p+"'")
+	}
+
+	return strings.Join(quoted, " ")
+}
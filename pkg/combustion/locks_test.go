@@ -0,0 +1,99 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigurePackageLocks_NoLocks(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{
+				PKGList: []string{"foo"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configurePackageLocks(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigurePackageLocks(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{
+				Locks:   []string{"kernel-default", "kernel-firmware-*"},
+				Exclude: []string{"apparmor-parser"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configurePackageLocks(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, packageLocksScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, packageLocksScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "zypper --non-interactive addlock 'kernel-default' 'kernel-firmware-*'")
+	assert.Contains(t, foundContents, "zypper --non-interactive addlock 'apparmor-parser'")
+}
+
+func TestConfigurePackageLocks_PinnedKernel(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{
+				Kernel: image.Kernel{
+					Version: "6.4.0-150600.23.25.1",
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configurePackageLocks(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, packageLocksScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "zypper --non-interactive addlock 'kernel-default'\n")
+	assert.Empty(t, ctx.ImageDefinition.OperatingSystem.Packages.Locks)
+}
@@ -0,0 +1,134 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	logrotateComponentName = "logrotate"
+	logrotateScriptName    = "19-logrotate.sh"
+	logrotateDir           = "logrotate"
+)
+
+//go:embed templates/19-logrotate.sh.tpl
+var logrotateScriptTemplate string
+
+func configureLogrotate(ctx *image.Context) ([]string, error) {
+	rotations := ctx.ImageDefinition.OperatingSystem.Logrotate
+	if len(rotations) == 0 {
+		log.AuditComponentSkipped(logrotateComponentName)
+		return nil, nil
+	}
+
+	if err := writeLogrotateConfigs(ctx, rotations); err != nil {
+		log.AuditComponentFailed(logrotateComponentName)
+		return nil, err
+	}
+
+	if err := writeLogrotateScript(ctx, rotations); err != nil {
+		log.AuditComponentFailed(logrotateComponentName)
+		return nil, err
+	}
+
+	names := make([]string, 0, len(rotations))
+	for _, rotation := range rotations {
+		names = append(names, rotation.Name)
+	}
+	log.AuditInfof("Installing logrotate configurations [%s] under /etc/logrotate.d.", strings.Join(names, ", "))
+
+	log.AuditComponentSuccessful(logrotateComponentName)
+	return []string{logrotateScriptName}, nil
+}
+
+func writeLogrotateConfigs(ctx *image.Context, rotations []image.LogRotation) error {
+	destDir := filepath.Join(ctx.CombustionDir, logrotateDir)
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating logrotate directory '%s': %w", destDir, err)
+	}
+
+	for _, rotation := range rotations {
+		filename := filepath.Join(destDir, logrotateConfigName(rotation.Name))
+		if err := os.WriteFile(filename, []byte(logrotateConfig(&rotation)), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("writing logrotate configuration '%s': %w", rotation.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// logrotateConfig renders the configuration of the rotation, skipping missing and empty files
+// instead of failing the rotation of all logs.
+func logrotateConfig(rotation *image.LogRotation) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s {\n", strings.Join(rotation.Paths, " "))
+
+	if rotation.Frequency != "" {
+		fmt.Fprintf(&sb, "    %s\n", rotation.Frequency)
+	}
+
+	if rotation.MaxSize != "" {
+		// Without a frequency the files are rotated based on their size alone
+		directive := "maxsize"
+		if rotation.Frequency == "" {
+			directive = "size"
+		}
+		fmt.Fprintf(&sb, "    %s %s\n", directive, rotation.MaxSize)
+	}
+
+	if rotation.Rotate > 0 {
+		fmt.Fprintf(&sb, "    rotate %d\n", rotation.Rotate)
+	}
+
+	if rotation.Compress {
+		sb.WriteString("    compress\n")
+	}
+
+	if rotation.CopyTruncate {
+		sb.WriteString("    copytruncate\n")
+	}
+
+	sb.WriteString("    missingok\n")
+	sb.WriteString("    notifempty\n")
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+func logrotateConfigName(name string) string {
+	return "eib-" + name
+}
+
+func writeLogrotateScript(ctx *image.Context, rotations []image.LogRotation) error {
+	values := struct {
+		LogrotateDir string
+		Configs      []string
+	}{
+		LogrotateDir: logrotateDir,
+	}
+
+	for _, rotation := range rotations {
+		values.Configs = append(values.Configs, logrotateConfigName(rotation.Name))
+	}
+
+	data, err := template.Parse(logrotateScriptName, logrotateScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", logrotateScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, logrotateScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureLogrotate_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureLogrotate(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureLogrotate(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Logrotate: []image.LogRotation{
+				{
+					Name:      "workload",
+					Paths:     []string{"/var/log/workload/*.log", "/var/lib/workload/audit.log"},
+					Frequency: image.LogRotationDaily,
+					Rotate:    7,
+					MaxSize:   "100M",
+					Compress:  true,
+				},
+				{
+					Name:         "agent",
+					Paths:        []string{"/var/log/agent.log"},
+					MaxSize:      "10M",
+					CopyTruncate: true,
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureLogrotate(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{logrotateScriptName}, scripts)
+
+	// - configuration files
+	workloadConfig, err := os.ReadFile(filepath.Join(ctx.CombustionDir, logrotateDir, "eib-workload"))
+	require.NoError(t, err)
+	assert.Equal(t, "/var/log/workload/*.log /var/lib/workload/audit.log {\n"+
+		"    daily\n"+
+		"    maxsize 100M\n"+
+		"    rotate 7\n"+
+		"    compress\n"+
+		"    missingok\n"+
+		"    notifempty\n"+
+		"}\n", string(workloadConfig))
+
+	agentConfig, err := os.ReadFile(filepath.Join(ctx.CombustionDir, logrotateDir, "eib-agent"))
+	require.NoError(t, err)
+	assert.Equal(t, "/var/log/agent.log {\n"+
+		"    size 10M\n"+
+		"    copytruncate\n"+
+		"    missingok\n"+
+		"    notifempty\n"+
+		"}\n", string(agentConfig))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, logrotateScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "install -m 644 ./logrotate/eib-workload /etc/logrotate.d/eib-workload\n"+
+		"install -m 644 ./logrotate/eib-agent /etc/logrotate.d/eib-agent\n")
+	assert.Contains(t, foundContents, "systemctl enable logrotate.timer")
+}
@@ -0,0 +1,75 @@
+package combustion
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	machineIDComponentName = "machine id"
+	machineIDScriptName    = "10-machine-id.sh"
+)
+
+//go:embed templates/10-machine-id.sh.tpl
+var machineIDScriptTemplate string
+
+func configureMachineID(ctx *image.Context) ([]string, error) {
+	machineID := &ctx.ImageDefinition.OperatingSystem.MachineID
+	if *machineID == (image.MachineID{}) {
+		log.AuditComponentSkipped(machineIDComponentName)
+		return nil, nil
+	}
+
+	id := machineID.Value
+	if machineID.Seed != "" {
+		id = seededMachineID(machineID.Seed)
+	}
+
+	values := struct {
+		MachineID string
+	}{
+		MachineID: id,
+	}
+
+	data, err := template.Parse(machineIDScriptName, machineIDScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(machineIDComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", machineIDScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, machineIDScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(machineIDComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	if id == "" {
+		log.AuditInfo("The machine ID is cleared and generated by each node on its first boot.")
+	} else {
+		log.AuditInfof("The machine ID of all nodes deployed from the image is set to %s.", id)
+	}
+
+	log.AuditComponentSuccessful(machineIDComponentName)
+	return []string{machineIDScriptName}, nil
+}
+
+// seededMachineID derives a machine ID from the seed, formatted as a version 4 UUID
+// in the same way as the IDs generated by systemd.
+func seededMachineID(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+
+	id := sum[:16]
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	return hex.EncodeToString(id)
+}
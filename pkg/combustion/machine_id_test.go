@@ -0,0 +1,95 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureMachineID_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureMachineID(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+
+	_, err = os.Stat(filepath.Join(ctx.CombustionDir, machineIDScriptName))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfigureMachineID(t *testing.T) {
+	tests := map[string]struct {
+		MachineID        image.MachineID
+		ExpectedContents string
+	}{
+		`clear`: {
+			MachineID:        image.MachineID{Clear: true},
+			ExpectedContents: ": > /etc/machine-id\n",
+		},
+		`value`: {
+			MachineID:        image.MachineID{Value: "4c4c4544003910528052b4c04f4e5432"},
+			ExpectedContents: "echo \"4c4c4544003910528052b4c04f4e5432\" > /etc/machine-id\n",
+		},
+		`seed`: {
+			MachineID:        image.MachineID{Seed: "edge-fleet"},
+			ExpectedContents: "echo \"" + seededMachineID("edge-fleet") + "\" > /etc/machine-id\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			ctx.ImageDefinition = &image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					MachineID: test.MachineID,
+				},
+			}
+
+			// Test
+			scripts, err := configureMachineID(ctx)
+
+			// Verify
+			require.NoError(t, err)
+
+			require.Len(t, scripts, 1)
+			assert.Equal(t, machineIDScriptName, scripts[0])
+
+			expectedFilename := filepath.Join(ctx.CombustionDir, machineIDScriptName)
+			foundBytes, err := os.ReadFile(expectedFilename)
+			require.NoError(t, err)
+
+			stats, err := os.Stat(expectedFilename)
+			require.NoError(t, err)
+			assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+			assert.Contains(t, string(foundBytes), test.ExpectedContents)
+		})
+	}
+}
+
+func TestSeededMachineID(t *testing.T) {
+	id := seededMachineID("edge-fleet")
+
+	assert.Len(t, id, 32)
+	assert.Equal(t, id, seededMachineID("edge-fleet"))
+	assert.NotEqual(t, id, seededMachineID("other-fleet"))
+
+	// Version 4 and RFC 4122 variant, as generated by systemd
+	assert.Equal(t, byte('4'), id[12])
+	assert.Contains(t, "89ab", string(id[16]))
+}
@@ -20,7 +20,7 @@ const (
 	nmcExecutable        = "nmc"
 	// Used for both input component source and
 	// output configurations subdirectory under combustion.
-	networkConfigDir        = "network"
+	NetworkConfigDir        = "network"
 	networkConfigScriptName = "05-configure-network.sh"
 	networkCustomScriptName = "configure-network.sh"
 )
@@ -52,7 +52,7 @@ var configureNetworkScript string
 func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err error) {
 	zap.S().Info("Configuring network component...")
 
-	if !isComponentConfigured(ctx, networkConfigDir) {
+	if !isComponentConfigured(ctx, NetworkConfigDir) {
 		log.AuditComponentSkipped(networkComponentName)
 		zap.S().Info("Skipping network component, configuration is not provided")
 		return nil, nil
@@ -62,7 +62,7 @@ func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err
 		logComponentStatus(networkComponentName, err)
 	}()
 
-	networkPath := generateComponentPath(ctx, networkConfigDir)
+	networkPath := generateComponentPath(ctx, NetworkConfigDir)
 
 	entries, err := os.ReadDir(networkPath)
 	if err != nil {
@@ -114,8 +114,8 @@ func (c *Combustion) generateNetworkConfig(ctx *image.Context) error {
 		}
 	}()
 
-	configDir := generateComponentPath(ctx, networkConfigDir)
-	outputDir := filepath.Join(ctx.CombustionDir, networkConfigDir)
+	configDir := generateComponentPath(ctx, NetworkConfigDir)
+	outputDir := filepath.Join(ctx.CombustionDir, NetworkConfigDir)
 
 	return c.NetworkConfigGenerator.GenerateNetworkConfig(configDir, outputDir, logFile)
 }
@@ -131,7 +131,7 @@ func writeNetworkConfigurationScript(scriptPath string) error {
 	values := struct {
 		ConfigDir string
 	}{
-		ConfigDir: networkConfigDir,
+		ConfigDir: NetworkConfigDir,
 	}
 
 	data, err := template.Parse(networkConfigScriptName, configureNetworkScript, &values)
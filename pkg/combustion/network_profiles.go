@@ -0,0 +1,117 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	networkProfilesComponentName = "network profiles"
+	// Used for both input component source and
+	// output configurations subdirectory under combustion.
+	NetworkProfilesDir        = "network-profiles"
+	networkProfilesScriptName = "05-network-profiles.sh"
+)
+
+//go:embed templates/05-network-profiles.sh.tpl
+var networkProfilesScriptTemplate string
+
+// Configures the network profiles, generating the nmstate configuration of each profile and writing the script
+// applying the profile selected on the kernel command line.
+//
+// Example result file layout:
+//
+//	combustion
+//	├── network-profiles
+//	│   ├── office
+//	│   │   └── node1.example.com
+//	│   │       └── eth0.nmconnection
+//	│   └── field
+//	│       └── node1.example.com
+//	│           └── wlan0.nmconnection
+//	├── nmc
+//	└── 05-network-profiles.sh
+func (c *Combustion) configureNetworkProfiles(ctx *image.Context) (scripts []string, err error) {
+	profiles := &ctx.ImageDefinition.OperatingSystem.NetworkProfiles
+	if len(profiles.Profiles) == 0 {
+		log.AuditComponentSkipped(networkProfilesComponentName)
+		return nil, nil
+	}
+
+	defer func() {
+		logComponentStatus(networkProfilesComponentName, err)
+	}()
+
+	if err = c.installNetworkConfigurator(ctx); err != nil {
+		return nil, fmt.Errorf("installing configurator: %w", err)
+	}
+
+	for _, profile := range profiles.Profiles {
+		if err = c.generateNetworkProfileConfig(ctx, profile); err != nil {
+			return nil, fmt.Errorf("generating network config of profile '%s': %w", profile, err)
+		}
+	}
+
+	if err = writeNetworkProfilesScript(ctx, profiles); err != nil {
+		return nil, fmt.Errorf("writing network profiles script: %w", err)
+	}
+
+	log.AuditInfof("Embedded the network profiles [%s], selected on first boot through the '%s' kernel argument "+
+		"and defaulting to '%s'.", strings.Join(profiles.Profiles, ", "), profiles.SelectionArg(), profiles.Default)
+
+	return []string{networkProfilesScriptName}, nil
+}
+
+func (c *Combustion) generateNetworkProfileConfig(ctx *image.Context, profile string) error {
+	logFilename := filepath.Join(ctx.BuildDir, fmt.Sprintf("network-config-%s.log", profile))
+	logFile, err := os.Create(logFilename)
+	if err != nil {
+		return fmt.Errorf("creating log file: %w", err)
+	}
+
+	defer func() {
+		if err = logFile.Close(); err != nil {
+			zap.S().Warnf("Failed to close network log file properly: %s", err)
+		}
+	}()
+
+	configDir := filepath.Join(generateComponentPath(ctx, NetworkProfilesDir), profile)
+	outputDir := filepath.Join(ctx.CombustionDir, NetworkProfilesDir, profile)
+
+	return c.NetworkConfigGenerator.GenerateNetworkConfig(configDir, outputDir, logFile)
+}
+
+func writeNetworkProfilesScript(ctx *image.Context, profiles *image.NetworkProfiles) error {
+	values := struct {
+		ProfilesDir string
+		KernelArg   string
+		Default     string
+		Profiles    []string
+	}{
+		ProfilesDir: NetworkProfilesDir,
+		KernelArg:   profiles.SelectionArg(),
+		Default:     profiles.Default,
+		Profiles:    profiles.Profiles,
+	}
+
+	data, err := template.Parse(networkProfilesScriptName, networkProfilesScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("parsing network profiles template: %w", err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, networkProfilesScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing network profiles script: %w", err)
+	}
+
+	return nil
+}
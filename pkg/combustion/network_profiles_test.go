@@ -0,0 +1,122 @@
+package combustion
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureNetworkProfiles_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	var c Combustion
+
+	// Test
+	scripts, err := c.configureNetworkProfiles(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureNetworkProfiles(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "office",
+				Profiles: []string{"office", "field"},
+			},
+		},
+	}
+
+	var generated []string
+	c := Combustion{
+		NetworkConfigGenerator: mockNetworkConfigGenerator{
+			generateNetworkConfigFunc: func(configDir, outputDir string, outputWriter io.Writer) error {
+				generated = append(generated, fmt.Sprintf("%s -> %s", configDir, outputDir))
+				return nil
+			},
+		},
+		NetworkConfiguratorInstaller: mockNetworkConfiguratorInstaller{
+			installConfiguratorFunc: func(sourcePath, installPath string) error {
+				return nil
+			},
+		},
+	}
+
+	// Test
+	scripts, err := c.configureNetworkProfiles(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{networkProfilesScriptName}, scripts)
+
+	assert.Equal(t, []string{
+		fmt.Sprintf("%s -> %s", filepath.Join(ctx.ImageConfigDir, "network-profiles", "office"), filepath.Join(ctx.CombustionDir, "network-profiles", "office")),
+		fmt.Sprintf("%s -> %s", filepath.Join(ctx.ImageConfigDir, "network-profiles", "field"), filepath.Join(ctx.CombustionDir, "network-profiles", "field")),
+	}, generated)
+
+	scriptPath := filepath.Join(ctx.CombustionDir, networkProfilesScriptName)
+	foundBytes, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "PROFILE='office'")
+	assert.Contains(t, foundContents, "'eib.network_profile='*) PROFILE=\"${arg#*=}\" ;;")
+	assert.Contains(t, foundContents, "  office|field) ;;")
+	assert.Contains(t, foundContents, "./nmc apply --config-dir \"network-profiles/$PROFILE\" || true")
+}
+
+func TestConfigureNetworkProfiles_GeneratingConfigFails(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			NetworkProfiles: image.NetworkProfiles{
+				KernelArg: "site.network",
+				Default:   "office",
+				Profiles:  []string{"office"},
+			},
+		},
+	}
+
+	c := Combustion{
+		NetworkConfigGenerator: mockNetworkConfigGenerator{
+			generateNetworkConfigFunc: func(configDir, outputDir string, outputWriter io.Writer) error {
+				return fmt.Errorf("no config for you")
+			},
+		},
+		NetworkConfiguratorInstaller: mockNetworkConfiguratorInstaller{
+			installConfiguratorFunc: func(sourcePath, installPath string) error {
+				return nil
+			},
+		},
+	}
+
+	// Test
+	scripts, err := c.configureNetworkProfiles(ctx)
+
+	// Verify
+	require.Error(t, err)
+	assert.EqualError(t, err, "generating network config of profile 'office': no config for you")
+	assert.Nil(t, scripts)
+}
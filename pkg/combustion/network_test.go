@@ -62,7 +62,7 @@ func TestConfigureNetwork_EmptyDirectory(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
-	networkDir := filepath.Join(ctx.ImageConfigDir, networkConfigDir)
+	networkDir := filepath.Join(ctx.ImageConfigDir, NetworkConfigDir)
 	require.NoError(t, os.Mkdir(networkDir, 0o700))
 
 	var c Combustion
@@ -121,7 +121,7 @@ func TestConfigureNetwork(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
-	networkDir := filepath.Join(ctx.ImageConfigDir, networkConfigDir)
+	networkDir := filepath.Join(ctx.ImageConfigDir, NetworkConfigDir)
 	require.NoError(t, os.Mkdir(networkDir, 0o700))
 
 	networkConfig := filepath.Join(networkDir, "config.yaml")
@@ -162,7 +162,7 @@ func TestConfigureNetwork_CustomScript(t *testing.T) {
 		},
 	}
 
-	networkDir := filepath.Join(ctx.ImageConfigDir, networkConfigDir)
+	networkDir := filepath.Join(ctx.ImageConfigDir, NetworkConfigDir)
 	require.NoError(t, os.Mkdir(networkDir, 0o700))
 
 	customScriptPath := filepath.Join(networkDir, networkCustomScriptName)
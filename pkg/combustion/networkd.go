@@ -0,0 +1,86 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	networkdComponentName = "networkd"
+	networkdScriptName    = "06-networkd.sh"
+	NetworkdConfigDir     = "networkd"
+)
+
+//go:embed templates/06-networkd.sh.tpl
+var networkdScriptTemplate string
+
+func configureNetworkd(ctx *image.Context) ([]string, error) {
+	configFiles := ctx.ImageDefinition.OperatingSystem.Networkd.ConfigFiles
+	if len(configFiles) == 0 {
+		log.AuditComponentSkipped(networkdComponentName)
+		return nil, nil
+	}
+
+	if err := copyNetworkdConfigFiles(ctx, configFiles); err != nil {
+		log.AuditComponentFailed(networkdComponentName)
+		return nil, err
+	}
+
+	if err := writeNetworkdScript(ctx, configFiles); err != nil {
+		log.AuditComponentFailed(networkdComponentName)
+		return nil, err
+	}
+
+	log.AuditInfof("Embedded systemd-networkd configuration [%s]; NetworkManager is disabled on the node.",
+		strings.Join(configFiles, ", "))
+
+	log.AuditComponentSuccessful(networkdComponentName)
+	return []string{networkdScriptName}, nil
+}
+
+func copyNetworkdConfigFiles(ctx *image.Context, configFiles []string) error {
+	srcDir := generateComponentPath(ctx, NetworkdConfigDir)
+	destDir := filepath.Join(ctx.CombustionDir, NetworkdConfigDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating networkd directory '%s': %w", destDir, err)
+	}
+
+	for _, configFile := range configFiles {
+		if err := fileio.CopyFile(filepath.Join(srcDir, configFile), filepath.Join(destDir, configFile), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying networkd configuration file '%s': %w", configFile, err)
+		}
+	}
+
+	return nil
+}
+
+func writeNetworkdScript(ctx *image.Context, configFiles []string) error {
+	values := struct {
+		NetworkdDir string
+		ConfigFiles []string
+	}{
+		NetworkdDir: NetworkdConfigDir,
+		ConfigFiles: configFiles,
+	}
+
+	data, err := template.Parse(networkdScriptName, networkdScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", networkdScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, networkdScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
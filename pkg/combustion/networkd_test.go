@@ -0,0 +1,78 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureNetworkd_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureNetworkd(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureNetworkd(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, NetworkdConfigDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "10-eth0.network"), []byte("[Match]\nName=eth0\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "20-br0.netdev"), []byte("[NetDev]\nName=br0\nKind=bridge\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "unused.network"), []byte("[Match]\nName=eth1\n"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Networkd: image.Networkd{
+				ConfigFiles: []string{"10-eth0.network", "20-br0.netdev"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureNetworkd(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, networkdScriptName, scripts[0])
+
+	// - configuration files
+	destDir := filepath.Join(ctx.CombustionDir, NetworkdConfigDir)
+	assert.FileExists(t, filepath.Join(destDir, "10-eth0.network"))
+	assert.FileExists(t, filepath.Join(destDir, "20-br0.netdev"))
+	assert.NoFileExists(t, filepath.Join(destDir, "unused.network"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, networkdScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "install -m 644 ./networkd/10-eth0.network /etc/systemd/network/10-eth0.network")
+	assert.Contains(t, foundContents, "install -m 644 ./networkd/20-br0.netdev /etc/systemd/network/20-br0.netdev")
+	assert.Contains(t, foundContents, "systemctl disable NetworkManager.service")
+	assert.Contains(t, foundContents, "systemctl enable systemd-networkd.service")
+	assert.NotContains(t, foundContents, "unused")
+}
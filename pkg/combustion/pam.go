@@ -0,0 +1,85 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	pamComponentName = "pam"
+	pamScriptName    = "13e-pam.sh"
+	PAMConfigDir     = "pam"
+)
+
+//go:embed templates/13e-pam.sh.tpl
+var pamScriptTemplate string
+
+func configurePAM(ctx *image.Context) ([]string, error) {
+	configFiles := ctx.ImageDefinition.OperatingSystem.PAM.ConfigFiles
+	if len(configFiles) == 0 {
+		log.AuditComponentSkipped(pamComponentName)
+		return nil, nil
+	}
+
+	if err := copyPAMConfigFiles(ctx, configFiles); err != nil {
+		log.AuditComponentFailed(pamComponentName)
+		return nil, err
+	}
+
+	if err := writePAMScript(ctx, configFiles); err != nil {
+		log.AuditComponentFailed(pamComponentName)
+		return nil, err
+	}
+
+	log.AuditInfof("Installing PAM configuration files [%s] under /etc/pam.d.", strings.Join(configFiles, ", "))
+
+	log.AuditComponentSuccessful(pamComponentName)
+	return []string{pamScriptName}, nil
+}
+
+func copyPAMConfigFiles(ctx *image.Context, configFiles []string) error {
+	srcDir := generateComponentPath(ctx, PAMConfigDir)
+	destDir := filepath.Join(ctx.CombustionDir, PAMConfigDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating pam directory '%s': %w", destDir, err)
+	}
+
+	for _, configFile := range configFiles {
+		if err := fileio.CopyFile(filepath.Join(srcDir, configFile), filepath.Join(destDir, configFile), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying PAM configuration file '%s': %w", configFile, err)
+		}
+	}
+
+	return nil
+}
+
+func writePAMScript(ctx *image.Context, configFiles []string) error {
+	values := struct {
+		PAMDir      string
+		ConfigFiles []string
+	}{
+		PAMDir:      PAMConfigDir,
+		ConfigFiles: configFiles,
+	}
+
+	data, err := template.Parse(pamScriptName, pamScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", pamScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, pamScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigurePAM_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configurePAM(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigurePAM(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, PAMConfigDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "workload"), []byte("auth required pam_unix.so\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "unused"), []byte("auth required pam_deny.so\n"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			PAM: image.PAM{
+				ConfigFiles: []string{"workload"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configurePAM(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, pamScriptName, scripts[0])
+
+	// - configuration files
+	destDir := filepath.Join(ctx.CombustionDir, PAMConfigDir)
+	assert.FileExists(t, filepath.Join(destDir, "workload"))
+	assert.NoFileExists(t, filepath.Join(destDir, "unused"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, pamScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "rm -f /etc/pam.d/workload\ninstall -m 644 ./pam/workload /etc/pam.d/workload")
+	assert.NotContains(t, foundContents, "unused")
+}
@@ -0,0 +1,95 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"go.uber.org/zap"
+)
+
+const (
+	podmanComponentName = "podman images"
+	podmanScriptName    = "27-podman-images.sh"
+	podmanImagesDir     = "podman-images"
+)
+
+//go:embed templates/27-podman-images.sh.tpl
+var podmanScriptTemplate string
+
+func (c *Combustion) configurePodman(ctx *image.Context) ([]string, error) {
+	images := ctx.ImageDefinition.OperatingSystem.Podman.Images
+	if len(images) == 0 {
+		log.AuditComponentSkipped(podmanComponentName)
+		return nil, nil
+	}
+
+	if err := c.archivePodmanImages(ctx, images); err != nil {
+		log.AuditComponentFailed(podmanComponentName)
+		return nil, fmt.Errorf("archiving podman images: %w", err)
+	}
+
+	if err := writePodmanScript(ctx); err != nil {
+		log.AuditComponentFailed(podmanComponentName)
+		return nil, err
+	}
+
+	names := make([]string, 0, len(images))
+	for _, img := range images {
+		names = append(names, img.Name)
+	}
+	log.AuditInfof("Preloading container images into podman on the first boot: [%s].", strings.Join(names, ", "))
+
+	log.AuditComponentSuccessful(podmanComponentName)
+	return []string{podmanScriptName}, nil
+}
+
+func (c *Combustion) archivePodmanImages(ctx *image.Context, images []image.ContainerImage) error {
+	destDir := filepath.Join(ctx.ArtefactsDir, podmanImagesDir)
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating podman images directory '%s': %w", destDir, err)
+	}
+
+	for _, img := range images {
+		archiveName := imageArchiveName(img.Name)
+
+		zap.S().Infof("Archiving podman image '%s' as '%s'", img.Name, archiveName)
+
+		if err := c.ContainerImageArchiver.SaveImage(ctx.Context(), img.Name, ctx.ImageDefinition.Image.Arch, filepath.Join(destDir, archiveName)); err != nil {
+			return fmt.Errorf("archiving image '%s': %w", img.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// imageArchiveName derives the file name of the archive of the container image from its reference.
+func imageArchiveName(containerImage string) string {
+	return strings.NewReplacer("/", "_", ":", "_").Replace(containerImage) + ".tar"
+}
+
+func writePodmanScript(ctx *image.Context) error {
+	values := struct {
+		ImagesDir string
+	}{
+		ImagesDir: prependArtefactPath(podmanImagesDir),
+	}
+
+	data, err := template.Parse(podmanScriptName, podmanScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", podmanScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, podmanScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package combustion
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+type mockContainerImageArchiver struct {
+	saveImageFunc func(containerImage string, arch image.Arch, archivePath string) error
+}
+
+func (m mockContainerImageArchiver) SaveImage(_ context.Context, containerImage string, arch image.Arch, archivePath string) error {
+	if m.saveImageFunc != nil {
+		return m.saveImageFunc(containerImage, arch, archivePath)
+	}
+
+	panic("not implemented")
+}
+
+func TestConfigurePodman_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	var c Combustion
+
+	// Test
+	scripts, err := c.configurePodman(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigurePodman(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			Arch: image.ArchTypeARM,
+		},
+		OperatingSystem: image.OperatingSystem{
+			Podman: image.Podman{
+				Images: []image.ContainerImage{
+					{Name: "registry.example.com/workloads/app:1.0"},
+					{Name: "nginx"},
+				},
+			},
+		},
+	}
+
+	var archived []string
+	c := Combustion{
+		ContainerImageArchiver: mockContainerImageArchiver{
+			saveImageFunc: func(containerImage string, arch image.Arch, archivePath string) error {
+				assert.Equal(t, image.ArchTypeARM, arch)
+				archived = append(archived, containerImage)
+
+				return os.WriteFile(archivePath, []byte(containerImage), fileio.NonExecutablePerms)
+			},
+		},
+	}
+
+	// Test
+	scripts, err := c.configurePodman(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, podmanScriptName, scripts[0])
+
+	assert.Equal(t, []string{"registry.example.com/workloads/app:1.0", "nginx"}, archived)
+
+	// - archives
+	imagesDir := filepath.Join(ctx.ArtefactsDir, podmanImagesDir)
+	assert.FileExists(t, filepath.Join(imagesDir, "registry.example.com_workloads_app_1.0.tar"))
+	assert.FileExists(t, filepath.Join(imagesDir, "nginx.tar"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, podmanScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cp $ARTEFACTS_DIR/podman-images/*.tar /opt/eib-podman-images/")
+	assert.Contains(t, foundContents, `podman load -i "\$archive" && rm "\$archive"`)
+	assert.Contains(t, foundContents, "systemctl enable eib-podman-images.service")
+}
+
+func TestConfigurePodman_ArchiveFailure(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Podman: image.Podman{
+				Images: []image.ContainerImage{
+					{Name: "nginx"},
+				},
+			},
+		},
+	}
+
+	c := Combustion{
+		ContainerImageArchiver: mockContainerImageArchiver{
+			saveImageFunc: func(string, image.Arch, string) error {
+				return os.ErrNotExist
+			},
+		},
+	}
+
+	// Test
+	scripts, err := c.configurePodman(ctx)
+
+	// Verify
+	require.EqualError(t, err, "archiving podman images: archiving image 'nginx': file does not exist")
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, podmanScriptName))
+}
@@ -0,0 +1,105 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	readOnlyRootComponentName = "read-only root"
+	readOnlyRootScriptName    = "19c-read-only-root.sh"
+
+	// overlaysDir holds the upper and work directories of the persistent overlays on the node.
+	overlaysDir = "/var/lib/eib-overlays"
+)
+
+//go:embed templates/19c-read-only-root.sh.tpl
+var readOnlyRootScriptTemplate string
+
+// configureReadOnlyRoot mounts the root file system read-only and adds the writable mounts of the overlays
+// to /etc/fstab, which are mounted from the next boot on.
+func configureReadOnlyRoot(ctx *image.Context) ([]string, error) {
+	readOnlyRoot := &ctx.ImageDefinition.OperatingSystem.ReadOnlyRoot
+
+	if !readOnlyRoot.Enabled {
+		log.AuditComponentSkipped(readOnlyRootComponentName)
+		return nil, nil
+	}
+
+	type overlay struct {
+		Path     string
+		UpperDir string
+		WorkDir  string
+		// Entry is the line added to /etc/fstab
+		Entry string
+	}
+
+	var overlays []overlay
+	var persistent bool
+	for _, o := range readOnlyRoot.Overlays {
+		entry := overlay{Path: o.Path}
+
+		switch o.Type {
+		case image.OverlayTypeTmpfs:
+			options := "mode=0755"
+			if o.Size != "" {
+				options += ",size=" + o.Size
+			}
+			entry.Entry = fmt.Sprintf("tmpfs %s tmpfs %s 0 0", o.Path, options)
+		case image.OverlayTypePersistent:
+			dir := filepath.Join(overlaysDir, overlayName(o.Path))
+			entry.UpperDir = filepath.Join(dir, "upper")
+			entry.WorkDir = filepath.Join(dir, "work")
+			entry.Entry = fmt.Sprintf("overlay %s overlay lowerdir=%s,upperdir=%s,workdir=%s,x-systemd.requires-mounts-for=/var 0 0",
+				o.Path, o.Path, entry.UpperDir, entry.WorkDir)
+			persistent = true
+		}
+
+		overlays = append(overlays, entry)
+		ctx.Overlays = append(ctx.Overlays, image.OverlayMount{
+			Path:     o.Path,
+			Type:     o.Type,
+			Size:     o.Size,
+			UpperDir: entry.UpperDir,
+		})
+	}
+
+	values := struct {
+		Overlays   []overlay
+		Persistent bool
+	}{
+		Overlays:   overlays,
+		Persistent: persistent,
+	}
+
+	data, err := template.Parse(readOnlyRootScriptName, readOnlyRootScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(readOnlyRootComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", readOnlyRootScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, readOnlyRootScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(readOnlyRootComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("The root file system will be mounted read-only with %d writable overlay(s).", len(overlays))
+
+	log.AuditComponentSuccessful(readOnlyRootComponentName)
+	return []string{readOnlyRootScriptName}, nil
+}
+
+// overlayName names the directory storing the changes of a persistent overlay after its path, in the same way
+// systemd names mount units (e.g. "usr-lib-app" for /usr/lib/app).
+func overlayName(path string) string {
+	return strings.ReplaceAll(strings.Trim(filepath.Clean(path), "/"), "/", "-")
+}
@@ -0,0 +1,102 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureReadOnlyRoot_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	scripts, err := configureReadOnlyRoot(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, readOnlyRootScriptName))
+}
+
+func TestConfigureReadOnlyRoot(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.ReadOnlyRoot = image.ReadOnlyRoot{
+		Enabled: true,
+		Overlays: []image.Overlay{
+			{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs, Size: "256M"},
+			{Path: "/usr/lib/app/data", Type: image.OverlayTypePersistent},
+		},
+	}
+
+	// Test
+	scripts, err := configureReadOnlyRoot(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{readOnlyRootScriptName}, scripts)
+
+	scriptPath := filepath.Join(ctx.CombustionDir, readOnlyRootScriptName)
+
+	info, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+	contents, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+
+	found := string(contents)
+	assert.Contains(t, found, `result = "ro"`)
+	assert.Contains(t, found, "mount /var\n")
+	assert.Contains(t, found, "mkdir -p '/usr/lib/app/cache'\n"+
+		"echo 'tmpfs /usr/lib/app/cache tmpfs mode=0755,size=256M 0 0' >> /etc/fstab\n")
+	assert.Contains(t, found, "mkdir -p '/usr/lib/app/data'\n"+
+		"mkdir -p '/var/lib/eib-overlays/usr-lib-app-data/upper' '/var/lib/eib-overlays/usr-lib-app-data/work'\n"+
+		"echo 'overlay /usr/lib/app/data overlay lowerdir=/usr/lib/app/data,"+
+		"upperdir=/var/lib/eib-overlays/usr-lib-app-data/upper,workdir=/var/lib/eib-overlays/usr-lib-app-data/work,"+
+		"x-systemd.requires-mounts-for=/var 0 0' >> /etc/fstab\n")
+	assert.Contains(t, found, "umount \"$mounted\"")
+
+	assert.Equal(t, []image.OverlayMount{
+		{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs, Size: "256M"},
+		{Path: "/usr/lib/app/data", Type: image.OverlayTypePersistent, UpperDir: "/var/lib/eib-overlays/usr-lib-app-data/upper"},
+	}, ctx.Overlays)
+}
+
+func TestConfigureReadOnlyRoot_TmpfsOnly(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.ReadOnlyRoot = image.ReadOnlyRoot{
+		Enabled:  true,
+		Overlays: []image.Overlay{{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs}},
+	}
+
+	// Test
+	_, err := configureReadOnlyRoot(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.CombustionDir, readOnlyRootScriptName))
+	require.NoError(t, err)
+
+	found := string(contents)
+	assert.Contains(t, found, "echo 'tmpfs /usr/lib/app/cache tmpfs mode=0755 0 0' >> /etc/fstab\n")
+	assert.NotContains(t, found, "mount /var")
+}
+
+func TestOverlayName(t *testing.T) {
+	assert.Equal(t, "usr-lib-app", overlayName("/usr/lib/app"))
+	assert.Equal(t, "usr-lib-app", overlayName("/usr/lib/app/"))
+}
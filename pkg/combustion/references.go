@@ -0,0 +1,157 @@
+package combustion
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// Reference is a path under the image configuration directory consumed when building an image.
+type Reference struct {
+	// Path is relative to the image configuration directory.
+	Path string
+	// Extensions limits a referenced directory to its top-level files with one of the given extensions,
+	// directories referenced without extensions are consumed with all of their contents.
+	Extensions []string
+}
+
+// References lists the files and directories under the image configuration directory which the combustion
+// components consume for the given definition, whether or not they exist.
+func References(def *image.Definition) []Reference {
+	manifestExtensions := []string{".yaml", ".yml"}
+
+	references := []Reference{
+		{Path: filepath.Join(CustomDir, customFilesDir)},
+		{Path: filepath.Join(CustomDir, CustomScriptsDir)},
+		{Path: NetworkConfigDir},
+		{Path: CertificatesDir, Extensions: certsFileExtensions},
+		{Path: filepath.Join(elementalConfigDir, elementalConfigName)},
+		{Path: rpmDir, Extensions: []string{".rpm"}},
+		{Path: filepath.Join(rpmDir, gpgDir)},
+		{Path: filepath.Join(K8sDir, k8sConfigDir, k8sServerConfigFile)},
+		{Path: filepath.Join(K8sDir, k8sConfigDir, k8sAgentConfigFile)},
+		{Path: filepath.Join(K8sDir, k8sManifestsDir), Extensions: manifestExtensions},
+	}
+
+	if dir := def.Kubernetes.Manifests.Directory; dir != "" {
+		references = append(references, Reference{Path: dir, Extensions: manifestExtensions})
+	}
+
+	if configFile := def.Kubernetes.Containerd.ConfigFile; configFile != "" {
+		references = append(references, Reference{Path: configFile})
+	}
+
+	for _, chart := range def.Kubernetes.Helm.Charts {
+		if chart.ValuesFile != "" {
+			references = append(references, Reference{Path: filepath.Join(K8sDir, HelmDir, ValuesDir, chart.ValuesFile)})
+		}
+	}
+
+	for _, repository := range def.Kubernetes.Helm.Repositories {
+		if repository.CAFile != "" {
+			references = append(references, Reference{Path: filepath.Join(K8sDir, HelmDir, CertsDir, repository.CAFile)})
+		}
+	}
+
+	operatingSystem := &def.OperatingSystem
+	for _, hostKey := range operatingSystem.SSHD.HostKeys {
+		references = append(references,
+			Reference{Path: filepath.Join(SSHDConfigDir, hostKey)},
+			Reference{Path: filepath.Join(SSHDConfigDir, hostKey+".pub")})
+	}
+
+	references = appendFileReferences(references, AuditConfigDir, operatingSystem.Audit.RuleFiles)
+	references = appendFileReferences(references, AppArmorConfigDir, operatingSystem.AppArmor.Profiles)
+	references = appendFileReferences(references, UdevConfigDir, operatingSystem.Udev.Rules)
+	references = appendFileReferences(references, NetworkdConfigDir, operatingSystem.Networkd.ConfigFiles)
+	references = appendFileReferences(references, NetworkProfilesDir, operatingSystem.NetworkProfiles.Profiles)
+	references = appendFileReferences(references, PAMConfigDir, operatingSystem.PAM.ConfigFiles)
+	references = appendFileReferences(references, SecureBootDir, operatingSystem.SecureBoot.MOKCertificates)
+
+	signing := &operatingSystem.SecureBoot.Signing
+	for _, file := range []string{signing.Key, signing.Certificate} {
+		if file != "" {
+			references = append(references, Reference{Path: filepath.Join(SecureBootDir, file)})
+		}
+	}
+
+	if script := operatingSystem.BootValidation.Script; script != "" {
+		references = append(references, Reference{Path: script})
+	}
+
+	return references
+}
+
+func appendFileReferences(references []Reference, dir string, files []string) []Reference {
+	for _, file := range files {
+		references = append(references, Reference{Path: filepath.Join(dir, file)})
+	}
+
+	return references
+}
+
+// UnreferencedFiles walks the image configuration directory and returns the files which are not covered by
+// any of the references, relative to the directory. Hidden entries and the directories EIB writes its own
+// output to (e.g. _build) are skipped.
+func UnreferencedFiles(configDir string, references []Reference) ([]string, error) {
+	var unreferenced []string
+
+	err := filepath.WalkDir(configDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(configDir, path)
+		if err != nil {
+			return fmt.Errorf("determining relative path of '%s': %w", path, err)
+		}
+
+		if relPath == "." {
+			return nil
+		}
+
+		name := entry.Name()
+		outputDir := entry.IsDir() && filepath.Dir(relPath) == "." && strings.HasPrefix(name, "_")
+		if strings.HasPrefix(name, ".") || outputDir {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if entry.IsDir() || isReferenced(relPath, references) {
+			return nil
+		}
+
+		unreferenced = append(unreferenced, relPath)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking image configuration directory: %w", err)
+	}
+
+	return unreferenced, nil
+}
+
+func isReferenced(path string, references []Reference) bool {
+	for _, reference := range references {
+		referencePath := filepath.Clean(reference.Path)
+
+		if len(reference.Extensions) > 0 {
+			if filepath.Dir(path) == referencePath && slices.Contains(reference.Extensions, filepath.Ext(path)) {
+				return true
+			}
+			continue
+		}
+
+		if path == referencePath || strings.HasPrefix(path, referencePath+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
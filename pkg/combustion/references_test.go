@@ -0,0 +1,310 @@
+package combustion
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestUnreferencedFiles(t *testing.T) {
+	// Setup
+	configDir := t.TempDir()
+
+	files := []string{
+		"definition.yaml",
+		"old-definition.yaml",
+		".gitignore",
+		".git/config",
+		"_build/build-1/eib-build.log",
+		"custom/scripts/01-setup.sh",
+		"custom/files/app/config.json",
+		"custom/notes.txt",
+		"certificates/ca.pem",
+		"certificates/README.md",
+		"rpms/tool.rpm",
+		"rpms/gpg-keys/key.asc",
+		"rpms/old/tool.rpm",
+		"kubernetes/config/server.yaml",
+		"kubernetes/config/old-server.yaml",
+		"kubernetes/manifests/app.yaml",
+		"kubernetes/manifests/app.yaml.bak",
+		"kubernetes/helm/values/apache.yaml",
+		"kubernetes/helm/values/unused.yaml",
+		"kubernetes/helm/certs/repo.crt",
+		"kubernetes/containerd.toml",
+		"sshd/ssh_host_ed25519_key",
+		"sshd/ssh_host_ed25519_key.pub",
+		"sshd/ssh_host_rsa_key",
+		"udev/99-app.rules",
+		"network-profiles/office/host.yaml",
+		"network-profiles/lab/host.yaml",
+		"scripts/validate.sh",
+		"pam/sshd",
+		"pam/login",
+		"secure-boot/edge-mok.der",
+		"secure-boot/signing.key",
+		"secure-boot/signing.crt",
+	}
+	for _, file := range files {
+		path := filepath.Join(configDir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	def := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SSHD: image.SSHD{
+				HostKeys: []string{"ssh_host_ed25519_key"},
+			},
+			BootValidation: image.BootValidation{
+				Script: "scripts/validate.sh",
+			},
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "office",
+				Profiles: []string{"office"},
+			},
+			PAM: image.PAM{
+				ConfigFiles: []string{"sshd"},
+			},
+			SecureBoot: image.SecureBoot{
+				MOKCertificates: []string{"edge-mok.der"},
+				Signing: image.SecureBootSigning{
+					Key:         "signing.key",
+					Certificate: "signing.crt",
+				},
+			},
+		},
+		Kubernetes: image.Kubernetes{
+			Containerd: image.Containerd{
+				ConfigFile: "kubernetes/containerd.toml",
+			},
+			Helm: image.Helm{
+				Charts:       []image.HelmChart{{Name: "apache", ValuesFile: "apache.yaml"}},
+				Repositories: []image.HelmRepository{{Name: "repo", CAFile: "repo.crt"}},
+			},
+		},
+	}
+
+	references := append(References(def), Reference{Path: "definition.yaml"})
+
+	// Test
+	unreferenced, err := UnreferencedFiles(configDir, references)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"certificates/README.md",
+		"custom/notes.txt",
+		"kubernetes/config/old-server.yaml",
+		"kubernetes/helm/values/unused.yaml",
+		"kubernetes/manifests/app.yaml.bak",
+		"network-profiles/lab/host.yaml",
+		"old-definition.yaml",
+		"pam/login",
+		"rpms/old/tool.rpm",
+		"sshd/ssh_host_rsa_key",
+		"udev/99-app.rules",
+	}, unreferenced)
+}
+
+func TestUnreferencedFiles_MissingConfigDir(t *testing.T) {
+	_, err := UnreferencedFiles(filepath.Join(t.TempDir(), "missing"), nil)
+	require.ErrorContains(t, err, "walking image configuration directory")
+}
+
+// TestReferences_ComponentReads fails when a component reads from the image configuration directory without
+// a matching reference, which would get the files it reads reported as unreferenced by 'eib lint'.
+func TestReferences_ComponentReads(t *testing.T) {
+	def := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SSHD:            image.SSHD{HostKeys: []string{"ssh_host_ed25519_key"}},
+			Audit:           image.Audit{RuleFiles: []string{"app.rules"}},
+			AppArmor:        image.AppArmor{Profiles: []string{"usr.bin.app"}},
+			Udev:            image.Udev{Rules: []string{"99-app.rules"}},
+			Networkd:        image.Networkd{ConfigFiles: []string{"10-eth0.network"}},
+			NetworkProfiles: image.NetworkProfiles{Profiles: []string{"office"}},
+			PAM:             image.PAM{ConfigFiles: []string{"sshd"}},
+			BootValidation:  image.BootValidation{Script: "scripts/validate.sh"},
+			SecureBoot: image.SecureBoot{
+				MOKCertificates: []string{"edge-mok.der"},
+				Signing:         image.SecureBootSigning{Key: "signing.key", Certificate: "signing.crt"},
+			},
+		},
+		Kubernetes: image.Kubernetes{
+			Containerd: image.Containerd{ConfigFile: "kubernetes/containerd.toml"},
+			Manifests:  image.Manifests{Directory: "manifests"},
+			Helm: image.Helm{
+				Charts:       []image.HelmChart{{Name: "apache", ValuesFile: "apache.yaml"}},
+				Repositories: []image.HelmRepository{{Name: "repo", CAFile: "repo.crt"}},
+			},
+		},
+	}
+
+	// Paths taken from the definition, keyed by the expression the components read them with
+	definitionPaths := map[string]string{
+		"bootValidation.Script":                                def.OperatingSystem.BootValidation.Script,
+		"ctx.ImageDefinition.Kubernetes.Containerd.ConfigFile": def.Kubernetes.Containerd.ConfigFile,
+		"ctx.ImageDefinition.Kubernetes.Manifests.Directory":   def.Kubernetes.Manifests.Directory,
+	}
+
+	// Helpers resolving a component directory passed in by their callers
+	helpers := []string{"generateComponentPath", "isComponentConfigured"}
+
+	references := References(def)
+
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	var parsed []*ast.File
+	constants := map[string]string{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		f, parseErr := parser.ParseFile(fset, file, nil, 0)
+		require.NoError(t, parseErr)
+		parsed = append(parsed, f)
+
+		for _, obj := range f.Scope.Objects {
+			if value, ok := constantValue(obj); ok {
+				constants[obj.Name] = value
+			}
+		}
+	}
+
+	var resolve func(expr ast.Expr) (string, bool)
+	resolve = func(expr ast.Expr) (string, bool) {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			value, unquoteErr := strconv.Unquote(e.Value)
+			return value, unquoteErr == nil
+		case *ast.Ident:
+			if value, ok := constants[e.Name]; ok {
+				return value, true
+			}
+			if e.Obj != nil {
+				if assign, ok := e.Obj.Decl.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+					for i, lhs := range assign.Lhs {
+						if ident, ok := lhs.(*ast.Ident); ok && ident.Name == e.Name {
+							return resolve(assign.Rhs[i])
+						}
+					}
+				}
+			}
+		case *ast.CallExpr:
+			if isFilepathJoin(e) {
+				var elements []string
+				for _, arg := range e.Args {
+					element, ok := resolve(arg)
+					if !ok {
+						return "", false
+					}
+					elements = append(elements, element)
+				}
+				return filepath.Join(elements...), true
+			}
+		}
+
+		value, ok := definitionPaths[types.ExprString(expr)]
+		return value, ok
+	}
+
+	for _, f := range parsed {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && slices.Contains(helpers, fn.Name.Name) {
+				continue
+			}
+
+			ast.Inspect(decl, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				var path ast.Expr
+				switch {
+				case isCall(call, "generateComponentPath") && len(call.Args) == 2:
+					path = call.Args[1]
+				case isFilepathJoin(call) && len(call.Args) > 1 && isImageConfigDir(call.Args[0]):
+					path = &ast.CallExpr{Fun: call.Fun, Args: call.Args[1:]}
+				default:
+					return true
+				}
+
+				position := fset.Position(call.Pos())
+				resolved, ok := resolve(path)
+				if !ok {
+					t.Errorf("%s: unable to resolve the path '%s' read from the image configuration directory, "+
+						"add it to the definition paths of the test", position, types.ExprString(path))
+					return true
+				}
+
+				assert.Truef(t, coversPath(references, resolved),
+					"%s: '%s' is read from the image configuration directory without a matching reference", position, resolved)
+				return true
+			})
+		}
+	}
+}
+
+func constantValue(obj *ast.Object) (string, bool) {
+	spec, ok := obj.Decl.(*ast.ValueSpec)
+	if !ok || obj.Kind != ast.Con {
+		return "", false
+	}
+
+	for i, name := range spec.Names {
+		if name.Name != obj.Name || i >= len(spec.Values) {
+			continue
+		}
+
+		if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			value, err := strconv.Unquote(lit.Value)
+			return value, err == nil
+		}
+	}
+
+	return "", false
+}
+
+func isCall(call *ast.CallExpr, name string) bool {
+	ident, ok := call.Fun.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+func isFilepathJoin(call *ast.CallExpr) bool {
+	return types.ExprString(call.Fun) == "filepath.Join"
+}
+
+func isImageConfigDir(expr ast.Expr) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	return ok && selector.Sel.Name == "ImageConfigDir"
+}
+
+// coversPath reports whether a reference is the path or lies beneath or above it, the latter for
+// component directories whose contents are referenced file by file.
+func coversPath(references []Reference, path string) bool {
+	for _, reference := range references {
+		referencePath := filepath.Clean(reference.Path)
+		if referencePath == path ||
+			strings.HasPrefix(referencePath, path+string(filepath.Separator)) ||
+			strings.HasPrefix(path, referencePath+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -8,9 +8,11 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/schollz/progressbar/v3"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/registry"
@@ -28,6 +30,11 @@ const (
 	registryDir             = "registry"
 	registryPort            = "6545"
 	registryMirrorsFileName = "registries.yaml"
+	haulerStoresDir         = "hauler-stores"
+
+	// helmChartCRDsManifestPrefix sorts the CRD manifests of Helm charts before the HelmChart resources,
+	// which are named after the charts
+	helmChartCRDsManifestPrefix = "00-crds-"
 
 	HelmDir   = "helm"
 	ValuesDir = "values"
@@ -44,6 +51,11 @@ var (
 
 func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
 	if !IsEmbeddedArtifactRegistryConfigured(ctx) {
+		if err := writeRuntimeRegistryMirrors(ctx); err != nil {
+			log.AuditComponentFailed(registryComponentName)
+			return nil, fmt.Errorf("writing runtime registry mirrors: %w", err)
+		}
+
 		log.AuditComponentSkipped(registryComponentName)
 		return nil, nil
 	}
@@ -55,6 +67,11 @@ func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
 	}
 
 	if !configured {
+		if err = writeRuntimeRegistryMirrors(ctx); err != nil {
+			log.AuditComponentFailed(registryComponentName)
+			return nil, fmt.Errorf("writing runtime registry mirrors: %w", err)
+		}
+
 		log.AuditComponentSkipped(registryComponentName)
 		zap.S().Info("Skipping embedded artifact registry since the provided manifests/helm charts contain no images")
 		return nil, nil
@@ -70,8 +87,8 @@ func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
 	return []string{script}, nil
 }
 
-func addImageToHauler(ctx *image.Context, containerImage string) error {
-	args := []string{"store", "add", "image", containerImage, "-p", fmt.Sprintf("linux/%s", ctx.ImageDefinition.Image.Arch.Short())}
+func addImageToHauler(ctx *image.Context, containerImage, storeDir string) error {
+	args := []string{"store", "add", "image", containerImage, "-p", fmt.Sprintf("linux/%s", ctx.ImageDefinition.Image.Arch.Short()), "--store", storeDir}
 
 	cmd, registryLog, err := createRegistryCommand(ctx, hauler, args)
 	if err != nil {
@@ -90,8 +107,8 @@ func addImageToHauler(ctx *image.Context, containerImage string) error {
 	return nil
 }
 
-func generateRegistryTar(ctx *image.Context, imageTarDest string) error {
-	args := []string{"store", "save", "--filename", imageTarDest}
+func generateRegistryTar(ctx *image.Context, imageTarDest, storeDir string) error {
+	args := []string{"store", "save", "--filename", imageTarDest, "--store", storeDir}
 
 	cmd, registryLog, err := createRegistryCommand(ctx, hauler, args)
 	if err != nil {
@@ -107,7 +124,7 @@ func generateRegistryTar(ctx *image.Context, imageTarDest string) error {
 		return fmt.Errorf("creating registry tar: %w: ", err)
 	}
 
-	if err = os.RemoveAll("store"); err != nil {
+	if err = os.RemoveAll(storeDir); err != nil {
 		return fmt.Errorf("removing registry store: %w", err)
 	}
 
@@ -146,7 +163,7 @@ func createRegistryCommand(ctx *image.Context, commandName string, args []string
 		return nil, nil, fmt.Errorf("error opening registry log file %s: %w", registryLogFileName, err)
 	}
 
-	cmd := exec.Command(commandName, args...)
+	cmd := exec.CommandContext(ctx.Context(), commandName, args...)
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 
@@ -157,6 +174,7 @@ func IsEmbeddedArtifactRegistryConfigured(ctx *image.Context) bool {
 	return len(ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages) != 0 ||
 		len(ctx.ImageDefinition.Kubernetes.Manifests.URLs) != 0 ||
 		len(ctx.ImageDefinition.Kubernetes.Helm.Charts) != 0 ||
+		ctx.ImageDefinition.Kubernetes.Manifests.Directory != "" ||
 		isComponentConfigured(ctx, filepath.Join(K8sDir, k8sManifestsDir))
 }
 
@@ -175,7 +193,76 @@ func getImageHostnames(containerImages []string) []string {
 	return hostnames
 }
 
+// registryMirror is a registry entry of the registries.yaml file, listing the endpoints in the order
+// in which the container runtime tries them.
+type registryMirror struct {
+	Registry  string
+	Endpoints []string
+	Rewrites  []registryRewrite
+}
+
+type registryRewrite struct {
+	Pattern     string
+	Replacement string
+}
+
+// writeRegistryMirrors serves the images of the given registries, as well as docker.io, from the embedded
+// artifact registry before falling back to any runtime mirrors configured for them.
 func writeRegistryMirrors(ctx *image.Context, hostnames []string) error {
+	embedded := append([]string{"docker.io"}, hostnames...)
+	return writeRegistriesFile(ctx, registryMirrors(embedded, ctx.ImageDefinition.EmbeddedArtifactRegistry.Mirrors))
+}
+
+// writeRuntimeRegistryMirrors configures the runtime mirrors when there is no embedded artifact registry.
+func writeRuntimeRegistryMirrors(ctx *image.Context) error {
+	mirrors := ctx.ImageDefinition.EmbeddedArtifactRegistry.Mirrors
+	if len(mirrors) == 0 || ctx.ImageDefinition.Kubernetes.Version == "" {
+		return nil
+	}
+
+	return writeRegistriesFile(ctx, registryMirrors(nil, mirrors))
+}
+
+func registryMirrors(embeddedHostnames []string, runtimeMirrors []image.RegistryMirror) []registryMirror {
+	var mirrors []registryMirror
+
+	localEndpoint := fmt.Sprintf("http://localhost:%s", registryPort)
+	for _, hostname := range embeddedHostnames {
+		mirrors = append(mirrors, registryMirror{
+			Registry:  hostname,
+			Endpoints: []string{localEndpoint},
+		})
+	}
+
+	for _, runtimeMirror := range runtimeMirrors {
+		index := slices.IndexFunc(mirrors, func(m registryMirror) bool {
+			return m.Registry == runtimeMirror.Registry
+		})
+		if index == -1 {
+			mirrors = append(mirrors, registryMirror{Registry: runtimeMirror.Registry})
+			index = len(mirrors) - 1
+		}
+
+		mirrors[index].Endpoints = append(mirrors[index].Endpoints, runtimeMirror.Endpoints...)
+
+		patterns := make([]string, 0, len(runtimeMirror.Rewrite))
+		for pattern := range runtimeMirror.Rewrite {
+			patterns = append(patterns, pattern)
+		}
+		slices.Sort(patterns)
+
+		for _, pattern := range patterns {
+			mirrors[index].Rewrites = append(mirrors[index].Rewrites, registryRewrite{
+				Pattern:     pattern,
+				Replacement: runtimeMirror.Rewrite[pattern],
+			})
+		}
+	}
+
+	return mirrors
+}
+
+func writeRegistriesFile(ctx *image.Context, mirrors []registryMirror) error {
 	artefactsPath := kubernetesArtefactsPath(ctx)
 	if err := os.MkdirAll(artefactsPath, os.ModePerm); err != nil {
 		return fmt.Errorf("creating kubernetes artefacts path: %w", err)
@@ -183,11 +270,9 @@ func writeRegistryMirrors(ctx *image.Context, hostnames []string) error {
 
 	registriesYamlFile := filepath.Join(artefactsPath, registryMirrorsFileName)
 	registriesDef := struct {
-		Hostnames []string
-		Port      string
+		Mirrors []registryMirror
 	}{
-		Hostnames: hostnames,
-		Port:      registryPort,
+		Mirrors: mirrors,
 	}
 
 	data, err := template.Parse(registryMirrorsFileName, k8sRegistryMirrors, registriesDef)
@@ -199,6 +284,10 @@ func writeRegistryMirrors(ctx *image.Context, hostnames []string) error {
 		return fmt.Errorf("writing file %s: %w", registryMirrorsFileName, err)
 	}
 
+	for _, m := range mirrors {
+		log.AuditInfof("Container runtime pulls %s images from: %s", m.Registry, strings.Join(m.Endpoints, ", "))
+	}
+
 	return nil
 }
 
@@ -281,11 +370,16 @@ func parseManifests(ctx *image.Context) ([]string, error) {
 		manifestSrcDir = filepath.Join(ctx.ImageConfigDir, componentDir)
 	}
 
-	if manifestSrcDir != "" && ctx.ImageDefinition.Kubernetes.Version == "" {
+	var manifestsDirectory string
+	if ctx.ImageDefinition.Kubernetes.Manifests.Directory != "" {
+		manifestsDirectory = manifestsDirectoryPath(ctx)
+	}
+
+	if (manifestSrcDir != "" || manifestsDirectory != "") && ctx.ImageDefinition.Kubernetes.Version == "" {
 		return nil, fmt.Errorf("kubernetes manifests are provided but kubernetes version is not configured")
 	}
 
-	return registry.ManifestImages(ctx.ImageDefinition.Kubernetes.Manifests.URLs, manifestSrcDir)
+	return registry.ManifestImages(ctx.Context(), ctx.ImageDefinition.Kubernetes.Manifests.URLs, manifestSrcDir, manifestsDirectory)
 }
 
 func (c *Combustion) parseHelmCharts(ctx *image.Context) ([]*registry.HelmChart, error) {
@@ -329,6 +423,51 @@ func storeHelmCharts(ctx *image.Context, helmCharts []*registry.HelmChart) error
 		}
 	}
 
+	return storeHelmChartCRDs(ctx, helmCharts, manifestsDir)
+}
+
+// storeHelmChartCRDs stores the custom resource definitions of the charts which apply them ahead of their
+// releases. The manifests are named so that they sort, and are thereby applied, before the HelmChart resources
+// installing the releases.
+func storeHelmChartCRDs(ctx *image.Context, helmCharts []*registry.HelmChart, manifestsDir string) error {
+	crdCharts := map[string]string{}
+
+	for _, chart := range helmCharts {
+		name := chart.CRD.Metadata.Name
+
+		index := slices.IndexFunc(ctx.ImageDefinition.Kubernetes.Helm.Charts, func(c image.HelmChart) bool {
+			return c.Name == name
+		})
+		if index == -1 || !ctx.ImageDefinition.Kubernetes.Helm.Charts[index].ApplyCRDsFirst {
+			continue
+		}
+
+		if len(chart.CRDNames) == 0 {
+			log.Auditf("WARNING: Helm chart '%s' is configured to apply its CRDs first, but contains no CRDs.", name)
+			zap.S().Warnf("Helm chart '%s' contains no CRDs to apply ahead of the release", name)
+			continue
+		}
+
+		for _, crd := range chart.CRDNames {
+			if other, ok := crdCharts[crd]; ok {
+				return fmt.Errorf("CRD '%s' is provided by both the '%s' and '%s' helm charts", crd, other, name)
+			}
+			crdCharts[crd] = name
+		}
+
+		manifestName := fmt.Sprintf("%s%s.yaml", helmChartCRDsManifestPrefix, name)
+		if err := os.WriteFile(filepath.Join(manifestsDir, manifestName), []byte(chart.CRDsManifest), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("storing manifest '%s': %w", manifestName, err)
+		}
+
+		ctx.HelmChartCRDs = append(ctx.HelmChartCRDs, image.HelmChartCRDs{
+			Chart:    name,
+			Manifest: manifestName,
+			Names:    chart.CRDNames,
+		})
+		log.AuditInfof("The %d CRD(s) of Helm chart '%s' will be applied ahead of its release.", len(chart.CRDNames), name)
+	}
+
 	return nil
 }
 
@@ -340,23 +479,46 @@ func populateRegistry(ctx *image.Context, images []string) error {
 	bar := progressbar.Default(int64(len(images)), "Populating Embedded Artifact Registry...")
 	zap.S().Infof("Adding the following images to the embedded artifact registry:\n%s", images)
 
-	for _, i := range images {
-		if err := addImageToHauler(ctx, i); err != nil {
-			return fmt.Errorf("adding image to hauler: %w", err)
+	storesDir := filepath.Join(ctx.BuildDir, haulerStoresDir)
+	if err := os.MkdirAll(storesDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating hauler stores dir: %w", err)
+	}
+
+	scheduler := newPullScheduler(ctx.ImageDefinition.EmbeddedArtifactRegistry.PullLimits)
+
+	return scheduler.run(ctx.Context(), images, func(containerImage string) error {
+		convertedImage := strings.ReplaceAll(containerImage, "/", "_")
+
+		// Images are pulled concurrently, each into a store of its own
+		storeDir := filepath.Join(storesDir, convertedImage)
+		started := time.Now()
+		if err := addImageToHauler(ctx, containerImage, storeDir); err != nil {
+			return fmt.Errorf("adding image '%s' to hauler: %w", containerImage, err)
 		}
+		finished := time.Now()
 
-		convertedImage := strings.ReplaceAll(i, "/", "_")
 		convertedImageName := fmt.Sprintf("%s-%s", convertedImage, registryTarSuffix)
 
 		imageTarDest := filepath.Join(registryArtefactsPath(ctx), convertedImageName)
-		if err := generateRegistryTar(ctx, imageTarDest); err != nil {
+		if err := generateRegistryTar(ctx, imageTarDest, storeDir); err != nil {
 			return fmt.Errorf("generating hauler store tar: %w", err)
 		}
 
+		// The size of the store archive stands in for the pulled bytes, which hauler does not report
+		if info, err := os.Stat(imageTarDest); err == nil {
+			http.RecordDownload(ctx.Context(), http.DownloadRecord{
+				Artifact: containerImage,
+				Source:   imageRegistry(containerImage),
+				Bytes:    info.Size(),
+				Started:  started,
+				Finished: finished,
+			})
+		}
+
 		if err := bar.Add(1); err != nil {
 			zap.S().Debugf("Error incrementing the progress bar: %s", err)
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
@@ -0,0 +1,245 @@
+package combustion
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// anyRegistry selects the pull limits applied to each registry without limits of its own.
+const anyRegistry = "*"
+
+// pullScheduler pulls the embedded images while respecting the pull limits of their registries. Images of
+// different registries are pulled in parallel, while registries without limits pull one image at a time.
+type pullScheduler struct {
+	limits []image.RegistryPullLimit
+
+	mu       sync.Mutex
+	limiters map[string]*registryLimiter
+
+	// now and sleep are replaced in tests to avoid waiting for the rate limits
+	now   func() time.Time
+	sleep func(context.Context, time.Duration) error
+}
+
+// registryLimiter enforces the pull limits of a single registry.
+type registryLimiter struct {
+	registry string
+	// limited is set for registries with configured limits, whose throttling is reported
+	limited  bool
+	slots    chan struct{}
+	interval time.Duration
+
+	mu        sync.Mutex
+	next      time.Time
+	throttled int
+}
+
+func newPullScheduler(limits []image.RegistryPullLimit) *pullScheduler {
+	return &pullScheduler{
+		limits:   limits,
+		limiters: map[string]*registryLimiter{},
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// run pulls all images, stopping at the first failure or once the context is canceled.
+func (s *pullScheduler) run(ctx context.Context, images []string, pull func(containerImage string) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errGroup, ctx := errgroup.WithContext(ctx)
+
+	for _, containerImage := range images {
+		limiter := s.limiter(imageRegistry(containerImage))
+
+		errGroup.Go(func() error {
+			if err := s.acquire(ctx, limiter); err != nil {
+				return err
+			}
+
+			err := pull(containerImage)
+			if err != nil {
+				// Cancel the pending pulls before their slot is released
+				cancel()
+			}
+			limiter.release()
+
+			return err
+		})
+	}
+
+	err := errGroup.Wait()
+	s.reportThrottling()
+
+	return err
+}
+
+func (s *pullScheduler) limiter(registry string) *registryLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limiter, ok := s.limiters[registry]; ok {
+		return limiter
+	}
+
+	limit, limited := s.registryLimit(registry)
+
+	concurrency := limit.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var interval time.Duration
+	if limit.PullsPerMinute > 0 {
+		interval = time.Minute / time.Duration(limit.PullsPerMinute)
+	}
+
+	limiter := &registryLimiter{
+		registry: registry,
+		limited:  limited,
+		slots:    make(chan struct{}, concurrency),
+		interval: interval,
+	}
+	s.limiters[registry] = limiter
+
+	if limited {
+		zap.S().Infof("Pulling images from '%s' with a concurrency of %d and a rate limit of %d pull(s) per minute",
+			registry, concurrency, limit.PullsPerMinute)
+	}
+
+	return limiter
+}
+
+// registryLimit returns the limits configured for the registry, falling back to those of all registries.
+func (s *pullScheduler) registryLimit(registry string) (image.RegistryPullLimit, bool) {
+	var fallback *image.RegistryPullLimit
+
+	for i := range s.limits {
+		switch s.limits[i].Registry {
+		case registry:
+			return s.limits[i], true
+		case anyRegistry:
+			fallback = &s.limits[i]
+		}
+	}
+
+	if fallback != nil {
+		return *fallback, true
+	}
+
+	return image.RegistryPullLimit{}, false
+}
+
+// acquire waits for a free pull slot of the registry and for its rate limit to allow another pull.
+func (s *pullScheduler) acquire(ctx context.Context, limiter *registryLimiter) error {
+	select {
+	case limiter.slots <- struct{}{}:
+	default:
+		if limiter.limited {
+			limiter.recordThrottling()
+			zap.S().Infof("Throttling pull from '%s' until one of its %d concurrent pulls completes", limiter.registry, cap(limiter.slots))
+		}
+
+		select {
+		case limiter.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if wait := s.reserve(limiter); wait > 0 {
+		limiter.recordThrottling()
+		zap.S().Infof("Throttling pull from '%s' for %s to respect its rate limit", limiter.registry, wait.Round(time.Millisecond))
+
+		if err := s.sleep(ctx, wait); err != nil {
+			limiter.release()
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		limiter.release()
+		return err
+	}
+
+	return nil
+}
+
+// reserve schedules the next pull from the registry according to its rate limit, returning the time to wait for it.
+func (s *pullScheduler) reserve(limiter *registryLimiter) time.Duration {
+	if limiter.interval == 0 {
+		return 0
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := s.now()
+	start := limiter.next
+	if start.Before(now) {
+		start = now
+	}
+	limiter.next = start.Add(limiter.interval)
+
+	return start.Sub(now)
+}
+
+func (s *pullScheduler) reportThrottling() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var registries []string
+	for registry := range s.limiters {
+		registries = append(registries, registry)
+	}
+	slices.Sort(registries)
+
+	for _, registry := range registries {
+		if throttled := s.limiters[registry].throttled; throttled > 0 {
+			log.AuditInfof("Pulls from '%s' were throttled %d time(s) to respect its pull limits.", registry, throttled)
+		}
+	}
+}
+
+func (l *registryLimiter) release() {
+	<-l.slots
+}
+
+func (l *registryLimiter) recordThrottling() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.throttled++
+}
+
+// imageRegistry returns the registry host of the image, which is docker.io for images without one.
+func imageRegistry(containerImage string) string {
+	named, err := reference.ParseNormalizedNamed(containerImage)
+	if err != nil {
+		// Invalid references fail when being pulled and are not grouped with any registry
+		return containerImage
+	}
+
+	return reference.Domain(named)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,150 @@
+package combustion
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestImageRegistry(t *testing.T) {
+	assert.Equal(t, "docker.io", imageRegistry("nginx:1.25"))
+	assert.Equal(t, "docker.io", imageRegistry("library/nginx:1.25"))
+	assert.Equal(t, "registry.suse.com", imageRegistry("registry.suse.com/suse/sle15:15.5"))
+	assert.Equal(t, "localhost:5000", imageRegistry("localhost:5000/app:1.0"))
+	assert.Equal(t, "Invalid:Reference", imageRegistry("Invalid:Reference"))
+}
+
+func TestPullScheduler_Concurrency(t *testing.T) {
+	scheduler := newPullScheduler([]image.RegistryPullLimit{
+		{Registry: "docker.io", Concurrency: 2},
+	})
+
+	images := []string{
+		"nginx:1.25", "alpine:3.19", "busybox:1.36", "redis:7.2",
+		"registry.suse.com/suse/sle15:15.5", "registry.suse.com/suse/sle15:15.6",
+	}
+
+	var mu sync.Mutex
+	active := map[string]int{}
+	maxActive := map[string]int{}
+	var pulled atomic.Int32
+
+	err := scheduler.run(context.Background(), images, func(containerImage string) error {
+		registry := imageRegistry(containerImage)
+
+		mu.Lock()
+		active[registry]++
+		maxActive[registry] = max(maxActive[registry], active[registry])
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		active[registry]--
+		mu.Unlock()
+
+		pulled.Add(1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.EqualValues(t, len(images), pulled.Load())
+	assert.Equal(t, 2, maxActive["docker.io"])
+	// Registries without limits pull one image at a time
+	assert.Equal(t, 1, maxActive["registry.suse.com"])
+
+	assert.Positive(t, scheduler.limiters["docker.io"].throttled)
+	assert.Zero(t, scheduler.limiters["registry.suse.com"].throttled)
+}
+
+func TestPullScheduler_RateLimit(t *testing.T) {
+	scheduler := newPullScheduler([]image.RegistryPullLimit{
+		{Registry: "*", Concurrency: 3, PullsPerMinute: 30},
+	})
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	scheduler.now = func() time.Time { return now }
+
+	var mu sync.Mutex
+	var waits []time.Duration
+	scheduler.sleep = func(_ context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		waits = append(waits, d)
+		return nil
+	}
+
+	images := []string{"nginx:1.25", "alpine:3.19", "busybox:1.36", "registry.suse.com/suse/sle15:15.5"}
+	require.NoError(t, scheduler.run(context.Background(), images, func(string) error { return nil }))
+
+	// The limits of all registries apply to each registry separately
+	slices.Sort(waits)
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second}, waits)
+
+	assert.Equal(t, 2, scheduler.limiters["docker.io"].throttled)
+	assert.Zero(t, scheduler.limiters["registry.suse.com"].throttled)
+}
+
+func TestPullScheduler_RegistryLimit(t *testing.T) {
+	scheduler := newPullScheduler([]image.RegistryPullLimit{
+		{Registry: "*", Concurrency: 4},
+		{Registry: "docker.io", Concurrency: 2, PullsPerMinute: 10},
+	})
+
+	limit, limited := scheduler.registryLimit("docker.io")
+	assert.True(t, limited)
+	assert.Equal(t, 2, limit.Concurrency)
+	assert.Equal(t, 10, limit.PullsPerMinute)
+
+	limit, limited = scheduler.registryLimit("ghcr.io")
+	assert.True(t, limited)
+	assert.Equal(t, 4, limit.Concurrency)
+
+	_, limited = newPullScheduler(nil).registryLimit("ghcr.io")
+	assert.False(t, limited)
+
+	limiter := scheduler.limiter("docker.io")
+	assert.Equal(t, 2, cap(limiter.slots))
+	assert.Equal(t, 6*time.Second, limiter.interval)
+}
+
+func TestPullScheduler_Failure(t *testing.T) {
+	scheduler := newPullScheduler(nil)
+
+	var pulled atomic.Int32
+	err := scheduler.run(context.Background(), []string{"nginx:1.25", "alpine:3.19", "busybox:1.36"}, func(containerImage string) error {
+		pulled.Add(1)
+		return errors.New("429 too many requests")
+	})
+
+	require.Error(t, err)
+	assert.EqualError(t, err, "429 too many requests")
+	// The remaining pulls of the registry are canceled after the first failure
+	assert.EqualValues(t, 1, pulled.Load())
+}
+
+func TestPullScheduler_Canceled(t *testing.T) {
+	scheduler := newPullScheduler(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var pulled atomic.Int32
+	err := scheduler.run(ctx, []string{"nginx:1.25", "alpine:3.19"}, func(string) error {
+		pulled.Add(1)
+		return nil
+	})
+
+	// The pulls of a canceled build are not started
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, pulled.Load())
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/registry"
+	"gopkg.in/yaml.v3"
 )
 
 func TestCreateRegistryCommand(t *testing.T) {
@@ -192,6 +193,82 @@ func TestWriteRegistryMirrorsValid(t *testing.T) {
 	assert.Contains(t, found, "quay.io")
 }
 
+func TestWriteRegistryMirrorsWithRuntimeMirrors(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.Mirrors = []image.RegistryMirror{
+		{
+			Registry:  "docker.io",
+			Endpoints: []string{"https://mirror.example.com"},
+		},
+		{
+			Registry:  "registry.suse.com",
+			Endpoints: []string{"https://mirror.example.com"},
+			Rewrite: map[string]string{
+				`^suse/(.*)`: "suse-mirror/$1",
+				`^rancher\.`: "rancher-mirror/",
+			},
+		},
+	}
+
+	// Test
+	err := writeRegistryMirrors(ctx, []string{"quay.io"})
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.ArtefactsDir, K8sDir, registryMirrorsFileName))
+	require.NoError(t, err)
+
+	var registries struct {
+		Mirrors map[string]struct {
+			Endpoint []string          `yaml:"endpoint"`
+			Rewrite  map[string]string `yaml:"rewrite"`
+		} `yaml:"mirrors"`
+	}
+	require.NoError(t, yaml.Unmarshal(foundBytes, &registries))
+
+	require.Len(t, registries.Mirrors, 3)
+	assert.Equal(t, []string{"http://localhost:6545", "https://mirror.example.com"}, registries.Mirrors["docker.io"].Endpoint)
+	assert.Equal(t, []string{"http://localhost:6545"}, registries.Mirrors["quay.io"].Endpoint)
+	assert.Empty(t, registries.Mirrors["quay.io"].Rewrite)
+	assert.Equal(t, []string{"https://mirror.example.com"}, registries.Mirrors["registry.suse.com"].Endpoint)
+	assert.Equal(t, map[string]string{`^suse/(.*)`: "suse-mirror/$1", `^rancher\.`: "rancher-mirror/"},
+		registries.Mirrors["registry.suse.com"].Rewrite)
+}
+
+func TestWriteRuntimeRegistryMirrors(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.Mirrors = []image.RegistryMirror{
+		{
+			Registry:  "docker.io",
+			Endpoints: []string{"https://mirror.example.com"},
+		},
+	}
+	registriesFile := filepath.Join(ctx.ArtefactsDir, K8sDir, registryMirrorsFileName)
+
+	// Test
+	require.NoError(t, writeRuntimeRegistryMirrors(ctx))
+
+	// Verify
+	_, err := os.Stat(registriesFile)
+	require.ErrorIs(t, err, os.ErrNotExist, "mirrors are only written for Kubernetes")
+
+	ctx.ImageDefinition.Kubernetes.Version = "v1.29.0+k3s1"
+	require.NoError(t, writeRuntimeRegistryMirrors(ctx))
+
+	foundBytes, err := os.ReadFile(registriesFile)
+	require.NoError(t, err)
+
+	expected := "mirrors:\n  docker.io:\n    endpoint:\n      - \"https://mirror.example.com\"\n"
+	assert.Equal(t, expected, string(foundBytes))
+}
+
 func TestGetImageHostnames(t *testing.T) {
 	// Setup
 	images := []string{
@@ -291,3 +368,69 @@ spec:
 
 	assert.Equal(t, apacheContent, string(contents))
 }
+
+func TestStoreHelmCharts_CRDsFirst(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes.Helm.Charts = []image.HelmChart{
+		{Name: "cert-manager", ApplyCRDsFirst: true},
+		{Name: "apache"},
+	}
+
+	crdsManifest := `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: certificates.cert-manager.io
+`
+
+	charts := []*registry.HelmChart{
+		{
+			CRD:          registry.NewHelmCRD(&image.HelmChart{Name: "cert-manager"}, "some-content", "", "https://charts.jetstack.io"),
+			CRDsManifest: crdsManifest,
+			CRDNames:     []string{"certificates.cert-manager.io"},
+		},
+		{
+			CRD: registry.NewHelmCRD(&image.HelmChart{Name: "apache"}, "some-content", "", "oci://registry-1.docker.io/bitnamicharts"),
+		},
+	}
+
+	require.NoError(t, storeHelmCharts(ctx, charts))
+
+	manifestsDir := filepath.Join(ctx.ArtefactsDir, K8sDir, k8sManifestsDir)
+	contents, err := os.ReadFile(filepath.Join(manifestsDir, "00-crds-cert-manager.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, crdsManifest, string(contents))
+
+	assert.NoFileExists(t, filepath.Join(manifestsDir, "00-crds-apache.yaml"))
+	assert.FileExists(t, filepath.Join(manifestsDir, "cert-manager.yaml"))
+	assert.FileExists(t, filepath.Join(manifestsDir, "apache.yaml"))
+
+	assert.Equal(t, []image.HelmChartCRDs{
+		{Chart: "cert-manager", Manifest: "00-crds-cert-manager.yaml", Names: []string{"certificates.cert-manager.io"}},
+	}, ctx.HelmChartCRDs)
+}
+
+func TestStoreHelmCharts_DuplicateCRDs(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes.Helm.Charts = []image.HelmChart{
+		{Name: "cert-manager", ApplyCRDsFirst: true},
+		{Name: "cert-manager-fork", ApplyCRDsFirst: true},
+	}
+
+	charts := []*registry.HelmChart{
+		{
+			CRD:      registry.NewHelmCRD(&image.HelmChart{Name: "cert-manager"}, "some-content", "", ""),
+			CRDNames: []string{"certificates.cert-manager.io"},
+		},
+		{
+			CRD:      registry.NewHelmCRD(&image.HelmChart{Name: "cert-manager-fork"}, "some-content", "", ""),
+			CRDNames: []string{"issuers.cert-manager.io", "certificates.cert-manager.io"},
+		},
+	}
+
+	err := storeHelmCharts(ctx, charts)
+	require.EqualError(t, err, "CRD 'certificates.cert-manager.io' is provided by both the 'cert-manager' and 'cert-manager-fork' helm charts")
+}
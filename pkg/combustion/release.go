@@ -0,0 +1,95 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	releaseComponentName = "release"
+	releaseScriptName    = "47-release.sh"
+	releaseFile          = "/etc/eib-release"
+	releaseLabelPrefix   = "EIB_LABEL_"
+)
+
+//go:embed templates/47-release.sh.tpl
+var releaseScriptTemplate string
+
+func configureRelease(ctx *image.Context) ([]string, error) {
+	contents, err := releaseContents(ctx, time.Now())
+	if err != nil {
+		log.AuditComponentFailed(releaseComponentName)
+		return nil, err
+	}
+
+	values := struct {
+		Release string
+	}{
+		Release: contents,
+	}
+
+	data, err := template.Parse(releaseScriptName, releaseScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(releaseComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", releaseScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, releaseScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(releaseComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("Writing the build identity to %s: %s", releaseFile, strings.ReplaceAll(strings.TrimSpace(contents), "\n", ", "))
+
+	log.AuditComponentSuccessful(releaseComponentName)
+	return []string{releaseScriptName}, nil
+}
+
+// releaseContents describes the build in the os-release format. The build date is the fixed source date
+// of reproducible builds, falling back to the given time otherwise. Labels are sorted by name.
+func releaseContents(ctx *image.Context, now time.Time) (string, error) {
+	hash := ctx.DefinitionHash
+	if hash == "" {
+		var err error
+		if hash, err = image.DefinitionHash(ctx.ImageDefinition); err != nil {
+			return "", fmt.Errorf("hashing image definition: %w", err)
+		}
+	}
+
+	buildDate := ctx.SourceDate
+	if buildDate.IsZero() {
+		buildDate = now
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "EIB_VERSION=%q\n", version.GetVersion())
+	fmt.Fprintf(&sb, "EIB_BUILD_DATE=%q\n", buildDate.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "EIB_DEFINITION_SHA256=%q\n", hash)
+
+	labels := ctx.ImageDefinition.OperatingSystem.Release.Labels
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// Label values are validated to not require any escaping
+	for _, name := range names {
+		fmt.Fprintf(&sb, "%s%s=\"%s\"\n", releaseLabelPrefix, name, labels[name])
+	}
+
+	return sb.String(), nil
+}
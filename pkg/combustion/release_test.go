@@ -0,0 +1,74 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+func TestConfigureRelease(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.DefinitionHash = "3a5b"
+	ctx.SourceDate = time.Unix(1714557600, 0)
+	ctx.ImageDefinition.OperatingSystem.Release.Labels = map[string]string{
+		"SITE":     "berlin-02",
+		"CUSTOMER": "ACME Corp",
+	}
+
+	// Test
+	scripts, err := configureRelease(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, releaseScriptName, scripts[0])
+
+	scriptPath := filepath.Join(ctx.CombustionDir, releaseScriptName)
+	info, err := os.Stat(scriptPath)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+	foundBytes, err := os.ReadFile(scriptPath)
+	require.NoError(t, err)
+	found := string(foundBytes)
+
+	expected := "cat <<- 'EOF' > /etc/eib-release\n" +
+		"EIB_VERSION=\"" + version.GetVersion() + "\"\n" +
+		"EIB_BUILD_DATE=\"2024-05-01T10:00:00Z\"\n" +
+		"EIB_DEFINITION_SHA256=\"3a5b\"\n" +
+		"EIB_LABEL_CUSTOMER=\"ACME Corp\"\n" +
+		"EIB_LABEL_SITE=\"berlin-02\"\n" +
+		"EOF\n"
+	assert.Contains(t, found, expected)
+}
+
+func TestReleaseContents_Defaults(t *testing.T) {
+	ctx := &image.Context{
+		ImageDefinition: &image.Definition{
+			APIVersion: "1.0",
+		},
+	}
+
+	hash, err := image.DefinitionHash(ctx.ImageDefinition)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 6, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	contents, err := releaseContents(ctx, now)
+	require.NoError(t, err)
+
+	assert.Contains(t, contents, "EIB_BUILD_DATE=\"2024-06-01T10:30:00Z\"\n")
+	assert.Contains(t, contents, "EIB_DEFINITION_SHA256=\""+hash+"\"\n")
+	assert.NotContains(t, contents, releaseLabelPrefix)
+}
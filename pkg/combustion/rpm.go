@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
@@ -71,14 +72,67 @@ func (c *Combustion) configureRPMs(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("creating resolved rpm repository: %w", err)
 	}
 
-	script, err := writeRPMScript(ctx, repoPath, pkgsList)
+	// The pinned kernel is installed while assembling the image, which leaves nothing to install on boot
+	// when it is the only package to resolve
+	var scripts []string
+	if len(pkgsList) > 0 || ctx.ImageDefinition.OperatingSystem.Packages.Kernel.Version == "" {
+		var script string
+		if script, err = writeRPMScript(ctx, repoPath, pkgsList); err != nil {
+			log.AuditComponentFailed(rpmComponentName)
+			return nil, fmt.Errorf("writing the RPM install script %s: %w", installRPMsScriptName, err)
+		}
+		scripts = append(scripts, script)
+	}
+
+	resolved, err := listResolvedPackages(repoPath)
 	if err != nil {
 		log.AuditComponentFailed(rpmComponentName)
-		return nil, fmt.Errorf("writing the RPM install script %s: %w", installRPMsScriptName, err)
+		return nil, fmt.Errorf("listing resolved packages: %w", err)
+	}
+	ctx.ResolvedPackages = resolved
+	ctx.RPMRepoPath = repoPath
+
+	var size int64
+	for _, pkg := range resolved {
+		size += pkg.Size
 	}
+	log.AuditInfof("Embedding %d RPM(s) (%.1f MiB), resolved from %d requested package(s) along with their dependencies.",
+		len(resolved), float64(size)/(1<<20), len(pkgsList))
 
 	log.AuditComponentSuccessful(rpmComponentName)
-	return []string{script}, nil
+	return scripts, nil
+}
+
+// listResolvedPackages lists the RPM files of the resolved repository, including the side-loaded ones.
+func listResolvedPackages(repoPath string) ([]image.ResolvedPackage, error) {
+	var resolved []image.ResolvedPackage
+
+	err := filepath.WalkDir(repoPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".rpm" {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("reading file info of '%s': %w", path, err)
+		}
+
+		resolved = append(resolved, image.ResolvedPackage{File: entry.Name(), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(resolved, func(a, b image.ResolvedPackage) int {
+		return strings.Compare(a.File, b.File)
+	})
+
+	return resolved, nil
 }
 
 // SkipRPMComponent determines whether RPM configuration is needed
@@ -106,7 +160,7 @@ func SkipRPMComponent(ctx *image.Context) bool {
 			}
 		}
 
-		if !foundRpm && len(pkg.PKGList) == 0 {
+		if !foundRpm && len(pkg.PKGList) == 0 && pkg.Kernel.Version == "" {
 			// Rare case where the rpms directory is specified but empty and no packages
 			// are listed. Without this, RPM resolution will trigger and error out about there
 			// being "Too few arguments".
@@ -121,7 +175,7 @@ func SkipRPMComponent(ctx *image.Context) bool {
 		// User provided standalone or third party RPMs, so do not skip the RPM component
 		return false
 	}
-	if len(pkg.PKGList) > 0 {
+	if len(pkg.PKGList) > 0 || pkg.Kernel.Version != "" {
 		// User provided PackageHub or third party packages, so do not skip the RPM component
 		return false
 	}
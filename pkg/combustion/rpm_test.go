@@ -286,12 +286,18 @@ func TestConfigureRPMs_GPGFailures(t *testing.T) {
 
 func TestConfigureRPMs_SuccessfulConfig(t *testing.T) {
 	expectedRepoName := "bar"
-	expectedDir := "/foo/bar"
 	expectedPkg := []string{"foo", "bar"}
 
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
+	expectedDir := filepath.Join(ctx.ArtefactsDir, expectedRepoName)
+	require.NoError(t, os.MkdirAll(filepath.Join(expectedDir, "x86_64"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(expectedDir, "repodata"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(expectedDir, "x86_64", "foo-1.0-1.x86_64.rpm"), []byte("foo"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(expectedDir, "x86_64", "bar-2.0-1.x86_64.rpm"), []byte("bar-rpm"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(expectedDir, "repodata", "repomd.xml"), nil, 0o600))
+
 	ctx.ImageDefinition.OperatingSystem.Packages = image.Packages{
 		PKGList: []string{"foo", "bar"},
 		AdditionalRepos: []image.AddRepo{
@@ -356,4 +362,10 @@ func TestConfigureRPMs_SuccessfulConfig(t *testing.T) {
 	assert.Contains(t, foundContents, zypperAR)
 	assert.Contains(t, foundContents, zypperInstall)
 	assert.Contains(t, foundContents, zypperRR)
+
+	expectedResolved := []image.ResolvedPackage{
+		{File: "bar-2.0-1.x86_64.rpm", Size: 7},
+		{File: "foo-1.0-1.x86_64.rpm", Size: 3},
+	}
+	assert.Equal(t, expectedResolved, ctx.ResolvedPackages)
 }
@@ -0,0 +1,114 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	scheduledJobsComponentName = "scheduled jobs"
+	scheduledJobsScriptName    = "14b-scheduled-jobs.sh"
+	scheduledJobsDir           = "scheduled-jobs"
+)
+
+//go:embed templates/14b-scheduled-jobs.sh.tpl
+var scheduledJobsScriptTemplate string
+
+func configureScheduledJobs(ctx *image.Context) ([]string, error) {
+	jobs := ctx.ImageDefinition.OperatingSystem.ScheduledJobs
+	if len(jobs) == 0 {
+		log.AuditComponentSkipped(scheduledJobsComponentName)
+		return nil, nil
+	}
+
+	if err := writeScheduledJobScripts(ctx, jobs); err != nil {
+		log.AuditComponentFailed(scheduledJobsComponentName)
+		return nil, err
+	}
+
+	if err := writeScheduledJobsScript(ctx, jobs); err != nil {
+		log.AuditComponentFailed(scheduledJobsComponentName)
+		return nil, err
+	}
+
+	log.AuditInfof("Embedding scheduled jobs [%s].", describeScheduledJobs(jobs))
+
+	log.AuditComponentSuccessful(scheduledJobsComponentName)
+	return []string{scheduledJobsScriptName}, nil
+}
+
+// writeScheduledJobScripts stores the command of each job in its own script, sparing the
+// commands from being quoted for use in the unit or crontab files.
+func writeScheduledJobScripts(ctx *image.Context, jobs []image.ScheduledJob) error {
+	destDir := filepath.Join(ctx.CombustionDir, scheduledJobsDir)
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating scheduled jobs directory '%s': %w", destDir, err)
+	}
+
+	for _, job := range jobs {
+		script := fmt.Sprintf("#!/bin/sh\n%s\n", strings.TrimSpace(job.Command))
+
+		filename := filepath.Join(destDir, job.Name+".sh")
+		if err := os.WriteFile(filename, []byte(script), fileio.ExecutablePerms); err != nil {
+			return fmt.Errorf("writing script for scheduled job '%s': %w", job.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeScheduledJobsScript(ctx *image.Context, jobs []image.ScheduledJob) error {
+	values := struct {
+		JobsDir string
+		Jobs    []image.ScheduledJob
+		Cron    bool
+	}{
+		JobsDir: scheduledJobsDir,
+	}
+
+	for _, job := range jobs {
+		job.Backend = scheduledJobBackend(&job)
+		if job.Backend == image.ScheduledJobBackendCron {
+			values.Cron = true
+		}
+
+		values.Jobs = append(values.Jobs, job)
+	}
+
+	data, err := template.Parse(scheduledJobsScriptName, scheduledJobsScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", scheduledJobsScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, scheduledJobsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+func scheduledJobBackend(job *image.ScheduledJob) string {
+	if job.Backend == "" {
+		return image.ScheduledJobBackendTimer
+	}
+
+	return job.Backend
+}
+
+func describeScheduledJobs(jobs []image.ScheduledJob) string {
+	descriptions := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%s '%s')", job.Name, scheduledJobBackend(&job), job.Schedule))
+	}
+
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,121 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureScheduledJobs_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureScheduledJobs(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureScheduledJobs(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			ScheduledJobs: []image.ScheduledJob{
+				{
+					Name:     "prune-images",
+					Schedule: "Sun *-*-* 03:00",
+					Command:  "podman image prune --all --force",
+				},
+				{
+					Name:     "rotate-exports",
+					Schedule: "*/15 * * * *",
+					Command:  "find /var/exports -mtime +7 -delete\nlogger \"exports rotated\"",
+					Backend:  image.ScheduledJobBackendCron,
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureScheduledJobs(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, scheduledJobsScriptName, scripts[0])
+
+	// - job scripts
+	jobScript, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scheduledJobsDir, "rotate-exports.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\nfind /var/exports -mtime +7 -delete\nlogger \"exports rotated\"\n", string(jobScript))
+	assert.FileExists(t, filepath.Join(ctx.CombustionDir, scheduledJobsDir, "prune-images.sh"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, scheduledJobsScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+
+	// -- timer backend
+	assert.Contains(t, foundContents, "install -m 755 ./scheduled-jobs/prune-images.sh /opt/eib-scheduled-jobs/prune-images.sh")
+	assert.Contains(t, foundContents, "ExecStart=/opt/eib-scheduled-jobs/prune-images.sh")
+	assert.Contains(t, foundContents, "OnCalendar=Sun *-*-* 03:00")
+	assert.Contains(t, foundContents, "systemctl enable eib-job-prune-images.timer")
+
+	// -- cron backend
+	assert.Contains(t, foundContents, "install -m 755 ./scheduled-jobs/rotate-exports.sh /opt/eib-scheduled-jobs/rotate-exports.sh")
+	assert.Contains(t, foundContents, "cat <<- EOF > /etc/cron.d/eib-rotate-exports\n*/15 * * * * root /opt/eib-scheduled-jobs/rotate-exports.sh\nEOF")
+	assert.NotContains(t, foundContents, "eib-job-rotate-exports")
+	assert.Contains(t, foundContents, "systemctl enable cron.service")
+}
+
+func TestConfigureScheduledJobs_TimersOnly(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			ScheduledJobs: []image.ScheduledJob{
+				{
+					Name:     "trim",
+					Schedule: "weekly",
+					Command:  "fstrim --all",
+					Backend:  image.ScheduledJobBackendTimer,
+				},
+			},
+		},
+	}
+
+	// Test
+	_, err := configureScheduledJobs(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, scheduledJobsScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "OnCalendar=weekly")
+	assert.NotContains(t, foundContents, "cron")
+}
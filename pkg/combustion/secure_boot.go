@@ -0,0 +1,86 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	secureBootComponentName = "secure boot"
+	secureBootScriptName    = "13f-secure-boot.sh"
+	SecureBootDir           = "secure-boot"
+)
+
+//go:embed templates/13f-secure-boot.sh.tpl
+var secureBootScriptTemplate string
+
+func configureSecureBoot(ctx *image.Context) ([]string, error) {
+	certificates := ctx.ImageDefinition.OperatingSystem.SecureBoot.MOKCertificates
+	if len(certificates) == 0 {
+		log.AuditComponentSkipped(secureBootComponentName)
+		return nil, nil
+	}
+
+	if err := copyMOKCertificates(ctx, certificates); err != nil {
+		log.AuditComponentFailed(secureBootComponentName)
+		return nil, err
+	}
+
+	if err := writeSecureBootScript(ctx, certificates); err != nil {
+		log.AuditComponentFailed(secureBootComponentName)
+		return nil, err
+	}
+
+	log.AuditInfof("Requesting the enrollment of the MOK certificates [%s] on first boot. "+
+		"The enrollment must be confirmed in the MOK manager with the root password on the following boot.", strings.Join(certificates, ", "))
+
+	log.AuditComponentSuccessful(secureBootComponentName)
+	return []string{secureBootScriptName}, nil
+}
+
+func copyMOKCertificates(ctx *image.Context, certificates []string) error {
+	srcDir := generateComponentPath(ctx, SecureBootDir)
+	destDir := filepath.Join(ctx.CombustionDir, SecureBootDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating secure boot directory '%s': %w", destDir, err)
+	}
+
+	for _, certificate := range certificates {
+		if err := fileio.CopyFile(filepath.Join(srcDir, certificate), filepath.Join(destDir, certificate), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying MOK certificate '%s': %w", certificate, err)
+		}
+	}
+
+	return nil
+}
+
+func writeSecureBootScript(ctx *image.Context, certificates []string) error {
+	values := struct {
+		SecureBootDir string
+		Certificates  []string
+	}{
+		SecureBootDir: SecureBootDir,
+		Certificates:  certificates,
+	}
+
+	data, err := template.Parse(secureBootScriptName, secureBootScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", secureBootScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, secureBootScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
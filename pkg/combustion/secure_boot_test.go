@@ -0,0 +1,85 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureSecureBoot_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Signing is applied while assembling the image
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				Signing: image.SecureBootSigning{Key: "signing.key", Certificate: "signing.crt", Kernel: true},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSecureBoot(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureSecureBoot(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, SecureBootDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "edge.der"), []byte("edge"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "vendor.der"), []byte("vendor"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "signing.key"), []byte("private"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				MOKCertificates: []string{"edge.der", "vendor.der"},
+				Signing:         image.SecureBootSigning{Key: "signing.key"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSecureBoot(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, secureBootScriptName, scripts[0])
+
+	// - certificates, without the signing key
+	destDir := filepath.Join(ctx.CombustionDir, SecureBootDir)
+	assert.FileExists(t, filepath.Join(destDir, "edge.der"))
+	assert.FileExists(t, filepath.Join(destDir, "vendor.der"))
+	assert.NoFileExists(t, filepath.Join(destDir, "signing.key"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, secureBootScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "install -m 644 ./secure-boot/edge.der /etc/uefi/certs/edge.der\n")
+	assert.Contains(t, foundContents, "install -m 644 ./secure-boot/vendor.der /etc/uefi/certs/vendor.der\n")
+	assert.Contains(t, foundContents, "mokutil --import /etc/uefi/certs/edge.der /etc/uefi/certs/vendor.der --root-pw\n")
+	assert.NotContains(t, foundContents, "signing.key")
+}
@@ -0,0 +1,96 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	shellDefaultsComponentName = "shell defaults"
+	shellDefaultsScriptName    = "45-shell-defaults.sh"
+	shellDefaultsFile          = "/etc/profile.d/eib-shell-defaults.sh"
+)
+
+//go:embed templates/45-shell-defaults.sh.tpl
+var shellDefaultsScriptTemplate string
+
+// isShellDefaultsConfigured reports whether any shell default is set.
+func isShellDefaultsConfigured(defaults *image.ShellDefaults) bool {
+	return defaults.Editor != "" || defaults.Pager != "" || len(defaults.Environment) != 0
+}
+
+func configureShellDefaults(ctx *image.Context) ([]string, error) {
+	defaults := &ctx.ImageDefinition.OperatingSystem.ShellDefaults
+	if !isShellDefaultsConfigured(defaults) {
+		log.AuditComponentSkipped(shellDefaultsComponentName)
+		return nil, nil
+	}
+
+	variables := shellDefaultsVariables(defaults)
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var sb strings.Builder
+	// Values are validated to not require any escaping
+	for _, name := range names {
+		fmt.Fprintf(&sb, "export %s=\"%s\"\n", name, variables[name])
+	}
+
+	values := struct {
+		File      string
+		Variables string
+	}{
+		File:      shellDefaultsFile,
+		Variables: sb.String(),
+	}
+
+	data, err := template.Parse(shellDefaultsScriptName, shellDefaultsScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(shellDefaultsComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", shellDefaultsScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, shellDefaultsScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(shellDefaultsComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("Login shells will default to %s through %s.", strings.Join(names, ", "), shellDefaultsFile)
+
+	log.AuditComponentSuccessful(shellDefaultsComponentName)
+	return []string{shellDefaultsScriptName}, nil
+}
+
+// shellDefaultsVariables returns the variables exported by login shells, the editor being set as both
+// EDITOR and VISUAL since tools differ in which of them they read.
+func shellDefaultsVariables(defaults *image.ShellDefaults) map[string]string {
+	variables := make(map[string]string, len(defaults.Environment)+3)
+	for name, value := range defaults.Environment {
+		variables[name] = value
+	}
+
+	if defaults.Editor != "" {
+		variables["EDITOR"] = defaults.Editor
+		variables["VISUAL"] = defaults.Editor
+	}
+
+	if defaults.Pager != "" {
+		variables["PAGER"] = defaults.Pager
+	}
+
+	return variables
+}
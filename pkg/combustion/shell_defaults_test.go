@@ -0,0 +1,72 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureShellDefaults_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureShellDefaults(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureShellDefaults(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			ShellDefaults: image.ShellDefaults{
+				Editor:      "vim",
+				Pager:       "less -R",
+				Environment: map[string]string{"HISTSIZE": "5000"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureShellDefaults(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{shellDefaultsScriptName}, scripts)
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, shellDefaultsScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<- 'EOF' > /etc/profile.d/eib-shell-defaults.sh\n"+
+		"# Shell defaults set by the Edge Image Builder\n"+
+		"export EDITOR=\"vim\"\n"+
+		"export HISTSIZE=\"5000\"\n"+
+		"export PAGER=\"less -R\"\n"+
+		"export VISUAL=\"vim\"\n"+
+		"EOF\n")
+}
+
+func TestShellDefaultsVariables(t *testing.T) {
+	assert.Equal(t, map[string]string{"PAGER": "most"}, shellDefaultsVariables(&image.ShellDefaults{Pager: "most"}))
+	assert.Empty(t, shellDefaultsVariables(&image.ShellDefaults{}))
+}
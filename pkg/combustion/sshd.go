@@ -0,0 +1,126 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	sshdComponentName = "sshd"
+	sshdScriptName    = "15-sshd.sh"
+	SSHDConfigDir     = "sshd"
+)
+
+//go:embed templates/15-sshd.sh.tpl
+var sshdScriptTemplate string
+
+type sshdOption struct {
+	Key   string
+	Value string
+}
+
+func configureSSHD(ctx *image.Context) ([]string, error) {
+	sshd := ctx.ImageDefinition.OperatingSystem.SSHD
+	if len(sshd.AllowedKeyTypes) == 0 && len(sshd.HostKeys) == 0 && len(sshd.Options) == 0 {
+		log.AuditComponentSkipped(sshdComponentName)
+		return nil, nil
+	}
+
+	if err := copyHostKeys(ctx, sshd.HostKeys); err != nil {
+		log.AuditComponentFailed(sshdComponentName)
+		return nil, err
+	}
+
+	if err := writeSSHDScript(ctx, &sshd); err != nil {
+		log.AuditComponentFailed(sshdComponentName)
+		return nil, err
+	}
+
+	// Only the names of the host keys are reported, their contents are never logged
+	log.AuditInfof("sshd configuration: host keys [%s], allowed key types [%s], options [%s].",
+		strings.Join(sshd.HostKeys, ", "), strings.Join(sshd.AllowedKeyTypes, ", "), strings.Join(sortedOptionKeys(sshd.Options), ", "))
+
+	log.AuditComponentSuccessful(sshdComponentName)
+	return []string{sshdScriptName}, nil
+}
+
+func copyHostKeys(ctx *image.Context, hostKeys []string) error {
+	if len(hostKeys) == 0 {
+		return nil
+	}
+
+	srcDir := generateComponentPath(ctx, SSHDConfigDir)
+	destDir := filepath.Join(ctx.CombustionDir, SSHDConfigDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating sshd directory '%s': %w", destDir, err)
+	}
+
+	for _, hostKey := range hostKeys {
+		if err := fileio.CopyFile(filepath.Join(srcDir, hostKey), filepath.Join(destDir, hostKey), fileio.PrivatePerms); err != nil {
+			return fmt.Errorf("copying host key '%s': %w", hostKey, err)
+		}
+
+		publicKey := hostKey + ".pub"
+		if _, err := os.Stat(filepath.Join(srcDir, publicKey)); err != nil {
+			continue
+		}
+
+		if err := fileio.CopyFile(filepath.Join(srcDir, publicKey), filepath.Join(destDir, publicKey), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying public host key '%s': %w", publicKey, err)
+		}
+	}
+
+	return nil
+}
+
+func writeSSHDScript(ctx *image.Context, sshd *image.SSHD) error {
+	var options []sshdOption
+	for _, key := range sortedOptionKeys(sshd.Options) {
+		options = append(options, sshdOption{Key: key, Value: sshd.Options[key]})
+	}
+
+	values := struct {
+		SSHDDir         string
+		HostKeys        []string
+		AllowedKeyTypes string
+		Options         []sshdOption
+	}{
+		SSHDDir:         SSHDConfigDir,
+		HostKeys:        sshd.HostKeys,
+		AllowedKeyTypes: strings.Join(sshd.AllowedKeyTypes, ","),
+		Options:         options,
+	}
+
+	data, err := template.Parse(sshdScriptName, sshdScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", sshdScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, sshdScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
+
+// sortedOptionKeys keeps the generated configuration stable between builds.
+func sortedOptionKeys(options map[string]string) []string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
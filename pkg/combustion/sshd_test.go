@@ -0,0 +1,125 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureSSHD_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureSSHD(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureSSHD(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, SSHDConfigDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "ssh_host_ed25519_key"), []byte("private"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "ssh_host_ed25519_key.pub"), []byte("public"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "ssh_host_ecdsa_key"), []byte("private"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SSHD: image.SSHD{
+				AllowedKeyTypes: []string{"ssh-ed25519", "ecdsa-sha2-nistp256"},
+				HostKeys:        []string{"ssh_host_ed25519_key", "ssh_host_ecdsa_key"},
+				Options: map[string]string{
+					"PermitRootLogin":        "prohibit-password",
+					"PasswordAuthentication": "no",
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSSHD(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, sshdScriptName, scripts[0])
+
+	// - host keys
+	destDir := filepath.Join(ctx.CombustionDir, SSHDConfigDir)
+
+	stats, err := os.Stat(filepath.Join(destDir, "ssh_host_ed25519_key"))
+	require.NoError(t, err)
+	assert.Equal(t, fileio.PrivatePerms, stats.Mode())
+
+	stats, err = os.Stat(filepath.Join(destDir, "ssh_host_ed25519_key.pub"))
+	require.NoError(t, err)
+	assert.Equal(t, fileio.NonExecutablePerms, stats.Mode())
+
+	assert.FileExists(t, filepath.Join(destDir, "ssh_host_ecdsa_key"))
+	assert.NoFileExists(t, filepath.Join(destDir, "ssh_host_ecdsa_key.pub"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, sshdScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err = os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "install -m 600 ./sshd/ssh_host_ed25519_key /etc/ssh/ssh_host_ed25519_key")
+	assert.Contains(t, foundContents, "install -m 600 ./sshd/ssh_host_ecdsa_key /etc/ssh/ssh_host_ecdsa_key")
+	assert.Contains(t, foundContents, "HostKey /etc/ssh/ssh_host_ed25519_key\nHostKey /etc/ssh/ssh_host_ecdsa_key\n")
+	assert.Contains(t, foundContents, "HostKeyAlgorithms ssh-ed25519,ecdsa-sha2-nistp256\n")
+	assert.Contains(t, foundContents, "PubkeyAcceptedAlgorithms ssh-ed25519,ecdsa-sha2-nistp256\n")
+	assert.Contains(t, foundContents, "PasswordAuthentication no\nPermitRootLogin prohibit-password\nEOF")
+}
+
+func TestConfigureSSHD_OptionsOnly(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SSHD: image.SSHD{
+				Options: map[string]string{
+					"MaxAuthTries": "3",
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSSHD(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{sshdScriptName}, scripts)
+
+	assert.NoDirExists(t, filepath.Join(ctx.CombustionDir, SSHDConfigDir))
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, sshdScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<'EOF' > /etc/ssh/sshd_config.d/10-eib.conf\nMaxAuthTries 3\nEOF")
+	assert.NotContains(t, foundContents, "HostKey")
+	assert.NotContains(t, foundContents, "install -m")
+}
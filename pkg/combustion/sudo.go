@@ -0,0 +1,114 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	sudoComponentName = "sudo"
+	sudoScriptName    = "13c-sudo.sh"
+)
+
+//go:embed templates/13c-sudo.sh.tpl
+var sudoScriptTemplate string
+
+type sudoPolicy struct {
+	File string
+	Rule string
+}
+
+func configureSudo(ctx *image.Context) ([]string, error) {
+	var policies []sudoPolicy
+	var descriptions []string
+
+	for _, user := range ctx.ImageDefinition.OperatingSystem.Users {
+		rule := sudoRule(user.Username, &user.Sudo)
+		if rule == "" {
+			continue
+		}
+
+		policies = append(policies, sudoPolicy{
+			File: sudoersFilename(user.Username),
+			Rule: rule,
+		})
+		descriptions = append(descriptions, fmt.Sprintf("User '%s' is granted sudo for %s.", user.Username, describeSudo(&user.Sudo)))
+	}
+
+	if len(policies) == 0 {
+		log.AuditComponentSkipped(sudoComponentName)
+		return nil, nil
+	}
+
+	values := struct {
+		Policies []sudoPolicy
+	}{
+		Policies: policies,
+	}
+
+	data, err := template.Parse(sudoScriptName, sudoScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(sudoComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", sudoScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, sudoScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(sudoComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	for _, description := range descriptions {
+		log.AuditInfo(description)
+	}
+
+	log.AuditComponentSuccessful(sudoComponentName)
+	return []string{sudoScriptName}, nil
+}
+
+// sudoRule returns the sudoers rule granting the user its privileges, or an empty string if none are granted.
+func sudoRule(username string, sudo *image.UserSudo) string {
+	var commands string
+	switch sudo.Policy {
+	case image.SudoPolicyAll:
+		commands = "ALL"
+	case image.SudoPolicyCommands:
+		commands = strings.Join(sudo.Commands, ", ")
+	default:
+		return ""
+	}
+
+	if sudo.NoPassword {
+		commands = "NOPASSWD: " + commands
+	}
+
+	return fmt.Sprintf("%s ALL=(ALL) %s", username, commands)
+}
+
+// sudoersFilename is the name of the file under /etc/sudoers.d holding the rule of the user. Names containing
+// a dot are skipped by sudo, which is why any dot in the username is replaced.
+func sudoersFilename(username string) string {
+	return "50-eib-" + strings.ReplaceAll(username, ".", "_")
+}
+
+func describeSudo(sudo *image.UserSudo) string {
+	commands := "all commands"
+	if sudo.Policy == image.SudoPolicyCommands {
+		commands = fmt.Sprintf("the commands [%s]", strings.Join(sudo.Commands, ", "))
+	}
+
+	password := "with"
+	if sudo.NoPassword {
+		password = "without"
+	}
+
+	return fmt.Sprintf("%s %s a password", commands, password)
+}
@@ -0,0 +1,93 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureSudo_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Users: []image.OperatingSystemUser{
+				{Username: "alice"},
+				{Username: "bob", Sudo: image.UserSudo{Policy: image.SudoPolicyNone}},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSudo(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureSudo(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Users: []image.OperatingSystemUser{
+				{
+					Username: "ops",
+					Sudo:     image.UserSudo{Policy: image.SudoPolicyAll, NoPassword: true},
+				},
+				{
+					Username: "guest",
+				},
+				{
+					Username: "j.doe",
+					Sudo: image.UserSudo{
+						Policy:   image.SudoPolicyCommands,
+						Commands: []string{"/usr/bin/systemctl restart k3s", "/usr/bin/journalctl"},
+					},
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSudo(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, sudoScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, sudoScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "ops ALL=(ALL) NOPASSWD: ALL\n")
+	assert.Contains(t, foundContents, "install -m 440 \"$sudoers_file\" /etc/sudoers.d/50-eib-ops\n")
+	assert.Contains(t, foundContents, "j.doe ALL=(ALL) /usr/bin/systemctl restart k3s, /usr/bin/journalctl\n")
+	assert.Contains(t, foundContents, "install -m 440 \"$sudoers_file\" /etc/sudoers.d/50-eib-j_doe\n")
+	assert.Contains(t, foundContents, "visudo -c -q -f \"$sudoers_file\"")
+	assert.NotContains(t, foundContents, "guest")
+}
+
+func TestDescribeSudo(t *testing.T) {
+	assert.Equal(t, "all commands without a password",
+		describeSudo(&image.UserSudo{Policy: image.SudoPolicyAll, NoPassword: true}))
+	assert.Equal(t, "the commands [/usr/bin/id, /usr/bin/ls] with a password",
+		describeSudo(&image.UserSudo{Policy: image.SudoPolicyCommands, Commands: []string{"/usr/bin/id", "/usr/bin/ls"}}))
+}
@@ -21,9 +21,9 @@ const (
 var systemdTemplate string
 
 func configureSystemd(ctx *image.Context) ([]string, error) {
-	// Nothing to do if both lists are empty
+	// Nothing to do if both lists are empty and the default target is unchanged
 	systemd := ctx.ImageDefinition.OperatingSystem.Systemd
-	if len(systemd.Enable) == 0 && len(systemd.Disable) == 0 {
+	if len(systemd.Enable) == 0 && len(systemd.Disable) == 0 && systemd.DefaultTarget == "" {
 		log.AuditComponentSkipped(systemdComponentName)
 		return nil, nil
 	}
@@ -41,6 +41,10 @@ func configureSystemd(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("writing systemd combustion file: %w", err)
 	}
 
+	if systemd.DefaultTarget != "" {
+		log.AuditInfof("The system will boot into the '%s' systemd target by default.", systemd.DefaultTarget)
+	}
+
 	log.AuditComponentSuccessful(systemdComponentName)
 	return []string{systemdScriptName}, nil
 }
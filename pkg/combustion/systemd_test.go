@@ -71,4 +71,37 @@ func TestConfigureSystemd_BothServiceTypes(t *testing.T) {
 	assert.Contains(t, foundContents, "systemctl mask disable0")
 	assert.Contains(t, foundContents, "systemctl disable disable1")
 	assert.Contains(t, foundContents, "systemctl mask disable1")
+
+	// - Default target
+	assert.NotContains(t, foundContents, "systemctl set-default")
+}
+
+func TestConfigureSystemd_DefaultTarget(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Systemd: image.Systemd{
+				DefaultTarget: "multi-user.target",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSystemd(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, systemdScriptName, scripts[0])
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, systemdScriptName))
+	require.NoError(t, err)
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "systemctl set-default multi-user.target")
+	assert.NotContains(t, foundContents, "systemctl enable")
 }
@@ -0,0 +1,31 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Config - settings of the [Resolve] section of the systemd-resolved configuration */ -}}
+
+# Failing here is preferable to silently leaving the node on unencrypted DNS
+if ! systemctl list-unit-files systemd-resolved.service > /dev/null 2>&1; then
+  echo "systemd-resolved is not installed on the node" >&2
+  exit 1
+fi
+
+mkdir -p /etc/systemd/resolved.conf.d
+cat <<- 'EOF' > /etc/systemd/resolved.conf.d/50-eib-dns.conf
+[Resolve]
+{{ .Config -}}
+EOF
+chmod 644 /etc/systemd/resolved.conf.d/50-eib-dns.conf
+
+# NetworkManager would otherwise write the servers it learns to /etc/resolv.conf, bypassing systemd-resolved
+if systemctl list-unit-files NetworkManager.service > /dev/null 2>&1; then
+  mkdir -p /etc/NetworkManager/conf.d
+  cat <<- 'EOF' > /etc/NetworkManager/conf.d/50-eib-dns.conf
+[main]
+dns=none
+EOF
+  chmod 644 /etc/NetworkManager/conf.d/50-eib-dns.conf
+fi
+
+ln -sf ../run/systemd/resolve/stub-resolv.conf /etc/resolv.conf
+systemctl enable systemd-resolved.service
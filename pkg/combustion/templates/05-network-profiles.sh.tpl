@@ -0,0 +1,28 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* ProfilesDir - directory holding the generated configurations of the profiles */ -}}
+{{/* KernelArg   - kernel command line key whose value names the applied profile */ -}}
+{{/* Default     - profile applied when the kernel argument is not set */ -}}
+{{/* Profiles    - names of the embedded profiles */ -}}
+
+PROFILE='{{ .Default }}'
+for arg in $(cat /proc/cmdline); do
+  case "$arg" in
+    '{{ .KernelArg }}='*) PROFILE="${arg#*=}" ;;
+  esac
+done
+
+case "$PROFILE" in
+  {{ join .Profiles "|" }}) ;;
+  *)
+    echo "[WARN] Unknown network profile '$PROFILE' selected through {{ .KernelArg }}, applying '{{ .Default }}' instead"
+    PROFILE='{{ .Default }}'
+    ;;
+esac
+
+echo "Applying the network profile '$PROFILE'"
+
+# Use "|| true" in order to allow for DHCP configurations in cases where nmc fails
+./nmc apply --config-dir "{{ .ProfilesDir }}/$PROFILE" || true
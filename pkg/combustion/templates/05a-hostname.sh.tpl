@@ -0,0 +1,11 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Hostname - static hostname of the node */ -}}
+
+echo '{{ .Hostname }}' > /etc/hostname
+
+# systemd-hostnamed is not available while combustion runs, in which case the hostname is applied from
+# /etc/hostname on boot
+hostnamectl set-hostname --static '{{ .Hostname }}' 2>/dev/null || true
@@ -0,0 +1,19 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* NetworkdDir - directory holding the provided configuration files */ -}}
+{{/* ConfigFiles - names of the provided configuration files */ -}}
+
+mkdir -p /etc/systemd/network
+
+{{ range .ConfigFiles -}}
+install -m 644 ./{{ $.NetworkdDir }}/{{ . }} /etc/systemd/network/{{ . }}
+{{ end -}}
+
+# NetworkManager would otherwise compete with networkd over the configured interfaces
+if systemctl list-unit-files NetworkManager.service > /dev/null 2>&1; then
+  systemctl disable NetworkManager.service
+fi
+
+systemctl enable systemd-networkd.service
@@ -1,5 +1,11 @@
 #!/bin/bash
 set -euo pipefail
 
-cp ./{{ .CertificatesDir }}/* /etc/pki/trust/anchors/.
+{{/* Template Fields */ -}}
+{{/* CertificatesDir - directory holding the certificate files */ -}}
+{{/* Files           - certificate files, each following the files holding the CAs it was issued by */ -}}
+
+{{ range .Files -}}
+cp './{{ $.CertificatesDir }}/{{ . }}' /etc/pki/trust/anchors/.
+{{ end -}}
 update-ca-certificates -v
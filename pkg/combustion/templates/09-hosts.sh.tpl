@@ -0,0 +1,13 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Hosts - static entries, each mapping an IP address to one or more hostnames */ -}}
+
+cat <<'EOF' >> /etc/hosts
+
+# Static host entries added by Edge Image Builder
+{{- range .Hosts }}
+{{ .IP }} {{ join .Hostnames " " }}
+{{- end }}
+EOF
@@ -0,0 +1,16 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* KnownHosts - pinned SSH host keys, each for a single host */ -}}
+
+mkdir -p /etc/ssh
+
+cat <<'EOF' >> /etc/ssh/ssh_known_hosts
+
+# SSH host keys added by Edge Image Builder
+{{- range .KnownHosts }}
+{{ .Host }} {{ .Key }}
+{{- end }}
+EOF
+chmod 644 /etc/ssh/ssh_known_hosts
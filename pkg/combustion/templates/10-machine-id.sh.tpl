@@ -0,0 +1,18 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* MachineID - machine ID of the node, which is cleared if empty */ -}}
+
+{{ if .MachineID -}}
+echo "{{ .MachineID }}" > /etc/machine-id
+{{ else -}}
+# systemd generates a new machine ID once the root is switched to, as the file is left empty
+: > /etc/machine-id
+{{ end -}}
+chmod 444 /etc/machine-id
+
+# D-Bus keeps its own copy of the machine ID on some systems
+if [ -f /var/lib/dbus/machine-id ] && [ ! -L /var/lib/dbus/machine-id ]; then
+  ln -sf /etc/machine-id /var/lib/dbus/machine-id
+fi
@@ -0,0 +1,13 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Locks   - quoted list of packages held at their current version */ -}}
+{{/* Exclude - quoted list of packages prevented from being installed */ -}}
+
+{{- if .Locks }}
+zypper --non-interactive addlock {{ .Locks }}
+{{- end }}
+{{- if .Exclude }}
+zypper --non-interactive addlock {{ .Exclude }}
+{{- end }}
@@ -0,0 +1,28 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Load      - kernel modules of the GPU driver loaded at boot */ -}}
+{{/* Options   - parameters of the kernel modules, keyed by module */ -}}
+{{/* Blacklist - kernel modules prevented from being loaded */ -}}
+
+{{ if .Load -}}
+mkdir -p /etc/modules-load.d
+cat <<- 'EOF' > /etc/modules-load.d/eib-gpu-drivers.conf
+{{ range .Load -}}
+{{ . }}
+{{ end -}}
+EOF
+{{ end -}}
+
+{{ if or .Options .Blacklist -}}
+mkdir -p /etc/modprobe.d
+cat <<- 'EOF' > /etc/modprobe.d/50-eib-gpu-drivers.conf
+{{ range $module, $options := .Options -}}
+options {{ $module }} {{ $options }}
+{{ end -}}
+{{ range .Blacklist -}}
+blacklist {{ . }}
+{{ end -}}
+EOF
+{{ end -}}
@@ -0,0 +1,44 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Interfaces   - network interfaces served by dnsmasq, all interfaces if empty */ -}}
+{{/* Domain       - local domain of the served devices */ -}}
+{{/* Upstreams    - DNS servers the queries are forwarded to, those of /etc/resolv.conf if empty */ -}}
+{{/* Ranges       - ranges of addresses leased dynamically */ -}}
+{{/* StaticLeases - addresses leased to the devices with the given MAC addresses */ -}}
+
+mkdir -p /etc/dnsmasq.d
+
+# The drop-in directory is not read by default
+if ! grep -q "^conf-dir=/etc/dnsmasq.d" /etc/dnsmasq.conf 2>/dev/null; then
+  echo "conf-dir=/etc/dnsmasq.d/,*.conf" >> /etc/dnsmasq.conf
+fi
+
+cat <<'EOF' > /etc/dnsmasq.d/eib.conf
+# Binding to the addresses of the served interfaces only leaves the stub listener of systemd-resolved untouched
+bind-dynamic
+{{- range .Interfaces }}
+interface={{ . }}
+{{- end }}
+{{- if .Domain }}
+domain={{ .Domain }}
+local=/{{ .Domain }}/
+expand-hosts
+{{- end }}
+{{- if .Upstreams }}
+no-resolv
+{{- range .Upstreams }}
+server={{ . }}
+{{- end }}
+{{- end }}
+{{- range .Ranges }}
+dhcp-range={{ .Start }},{{ .End }}{{ if .LeaseTime }},{{ .LeaseTime }}{{ end }}
+{{- end }}
+{{- range .StaticLeases }}
+dhcp-host={{ .MAC }},{{ .IP }}{{ if .Hostname }},{{ .Hostname }}{{ end }}
+{{- end }}
+EOF
+chmod 644 /etc/dnsmasq.d/eib.conf
+
+systemctl enable dnsmasq.service
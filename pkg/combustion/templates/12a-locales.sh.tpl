@@ -0,0 +1,53 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Keep - kept locales without their codeset (e.g. en_US), the rest are removed if set */ -}}
+{{/* Languages - languages of the kept locales, whose message translations are kept */ -}}
+{{/* Default - locale set as the system locale */ -}}
+
+{{ if .Keep -}}
+# Compiled locales are named with their codeset and modifier (e.g. de_DE.utf8@euro)
+keep_locale() {
+  local name=${1%%@*}
+  case "${name%%.*}" in
+    C|POSIX{{ range .Keep }}|{{ . }}{{ end }}) return 0 ;;
+  esac
+  return 1
+}
+
+keep_translation() {
+  case "${1%%@*}" in
+    {{ range $i, $language := .Languages }}{{ if $i }}|{{ end }}{{ $language }}{{ end }}{{ range .Keep }}|{{ . }}{{ end }}) return 0 ;;
+  esac
+  return 1
+}
+
+locale_size() {
+  { du -sk /usr/lib/locale /usr/share/locale 2> /dev/null || true; } | awk '{ total += $1 } END { print total + 0 }'
+}
+
+before=$(locale_size)
+
+for path in /usr/lib/locale/*/; do
+  [ -d "$path" ] || continue
+  keep_locale "$(basename "$path")" || rm -rf "$path"
+done
+
+if [ -f /usr/lib/locale/locale-archive ]; then
+  for name in $(localedef --list-archive); do
+    keep_locale "$name" || localedef --delete-from-archive "$name"
+  done
+fi
+
+for path in /usr/share/locale/*/; do
+  [ -d "$path" ] || continue
+  keep_translation "$(basename "$path")" || rm -rf "$path"
+done
+
+echo "Kept locales:{{ range .Keep }} {{ . }}{{ end }}; reclaimed $((before - $(locale_size))) KiB"
+
+{{ end -}}
+touch /etc/locale.conf
+sed -i '/^LANG=/d' /etc/locale.conf
+echo "LANG={{ .Default }}" >> /etc/locale.conf
@@ -24,16 +24,33 @@ mount /home
 {{- if $user.SecondaryGroups }}
   {{- $secondary_groups = (printf "-G %v " (join $user.SecondaryGroups ",")) }}
 {{- end }}
-useradd {{ $create_home }}{{ $uid }}{{ $primary_group }}{{ $secondary_groups }}{{$user.Username}}
+{{- $shell := ""}}
+{{- if $user.Shell }}
+  {{- $shell = (printf "-s %v " $user.Shell) }}
+{{- end }}
+{{- $home := (printf "/home/%v" $user.Username) }}
+{{- $home_dir := ""}}
+{{- if $user.HomeDir }}
+  {{- $home = $user.HomeDir }}
+  {{- $home_dir = (printf "-d %v " $user.HomeDir) }}
+{{- end }}
+useradd {{ $create_home }}{{ $home_dir }}{{ $uid }}{{ $primary_group }}{{ $secondary_groups }}{{ $shell }}{{$user.Username}}
 
 {{- if $user.EncryptedPassword }}
 echo '{{$user.Username}}:{{$user.EncryptedPassword}}' | chpasswd -e
 {{- end }}
 
+{{- if $user.PasswordExpire }}
+chage -M {{$user.PasswordExpire}} {{$user.Username}}
+{{- end }}
+{{- if $user.ForceChange }}
+chage -d 0 {{$user.Username}}
+{{- end }}
+
 {{- range $user.SSHKeys }}
-mkdir -pm700 /home/{{$user.Username}}/.ssh/
-echo '{{.}}' >> /home/{{$user.Username}}/.ssh/authorized_keys
-chown -R {{$user.Username}} /home/{{$user.Username}}/.ssh
+mkdir -pm700 {{$home}}/.ssh/
+echo '{{.}}' >> {{$home}}/.ssh/authorized_keys
+chown -R {{$user.Username}} {{$home}}/.ssh
 {{- end }}
 # ---
 {{- else }}
@@ -43,6 +60,13 @@ chown -R {{$user.Username}} /home/{{$user.Username}}/.ssh
 echo '{{$user.Username}}:{{$user.EncryptedPassword}}' | chpasswd -e
 {{- end }}
 
+{{- if $user.PasswordExpire }}
+chage -M {{$user.PasswordExpire}} {{$user.Username}}
+{{- end }}
+{{- if $user.ForceChange }}
+chage -d 0 {{$user.Username}}
+{{- end }}
+
 {{- range $user.SSHKeys }}
 mkdir -pm700 /{{$user.Username}}/.ssh/
 echo '{{.}}' >> /{{$user.Username}}/.ssh/authorized_keys
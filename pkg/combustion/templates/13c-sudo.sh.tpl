@@ -0,0 +1,19 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Policies - sudoers files, each holding the rule granted to a single user */ -}}
+
+mkdir -p /etc/sudoers.d
+
+# Each file is checked before being installed, as a malformed file under /etc/sudoers.d breaks sudo entirely
+{{ range .Policies -}}
+sudoers_file=$(mktemp)
+cat <<'EOF' > "$sudoers_file"
+{{ .Rule }}
+EOF
+visudo -c -q -f "$sudoers_file"
+install -m 440 "$sudoers_file" /etc/sudoers.d/{{ .File }}
+rm "$sudoers_file"
+
+{{ end -}}
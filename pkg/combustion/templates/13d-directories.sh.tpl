@@ -0,0 +1,31 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Directories - directories created with their ownership and mode */ -}}
+
+# Directories on separately mounted subvolumes such as /var or /home would otherwise be
+# hidden once these are mounted on boot, so the fstab mount covering each path is mounted first
+mounted=()
+mount_covering() {
+  local dir=$1
+  while [ "$dir" != "/" ]; do
+    if findmnt --fstab --noheadings --mountpoint "$dir" > /dev/null; then
+      if ! mountpoint -q "$dir"; then
+        mount "$dir"
+        mounted+=("$dir")
+      fi
+      return
+    fi
+    dir=$(dirname "$dir")
+  done
+}
+
+{{ range .Directories -}}
+mount_covering '{{ .Path }}'
+install -d -m {{ .Mode }} -o {{ .Owner }} -g {{ .Group }} '{{ .Path }}'
+
+{{ end -}}
+for dir in "${mounted[@]}"; do
+  umount "$dir"
+done
@@ -0,0 +1,14 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* PAMDir      - directory holding the provided configuration files */ -}}
+{{/* ConfigFiles - names of the provided configuration files */ -}}
+
+mkdir -p /etc/pam.d
+
+# Existing files may be symlinks managed by pam-config (e.g. common-auth), which are replaced rather than followed
+{{ range .ConfigFiles -}}
+rm -f /etc/pam.d/{{ . }}
+install -m 644 ./{{ $.PAMDir }}/{{ . }} /etc/pam.d/{{ . }}
+{{ end -}}
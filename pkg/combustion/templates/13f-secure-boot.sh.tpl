@@ -0,0 +1,21 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* SecureBootDir - directory holding the provided MOK certificates */ -}}
+{{/* Certificates  - names of the DER encoded certificates to enroll */ -}}
+
+mkdir -p /etc/uefi/certs
+
+{{ range .Certificates -}}
+install -m 644 ./{{ $.SecureBootDir }}/{{ . }} /etc/uefi/certs/{{ . }}
+{{ end -}}
+
+# Enrollment can only be requested from the firmware of nodes booted through UEFI
+if [ ! -d /sys/firmware/efi/efivars ]; then
+  echo "[WARN] The node was not booted through UEFI, the MOK certificates were not enrolled"
+  exit 0
+fi
+
+# The request is confirmed in the MOK manager on the next boot by entering the root password
+mokutil --import{{ range .Certificates }} /etc/uefi/certs/{{ . }}{{ end }} --root-pw
@@ -0,0 +1,24 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* User    - name of the user logged in without authentication */ -}}
+{{/* Service - getty template unit serving the console */ -}}
+{{/* TTY     - console the user is logged in on */ -}}
+{{/* Serial  - if true, the console is a serial console */ -}}
+
+mkdir -p /etc/systemd/system/{{ .Service }}@{{ .TTY }}.service.d
+
+# The empty ExecStart resets the command of the packaged unit before replacing it
+cat <<- 'EOF' > /etc/systemd/system/{{ .Service }}@{{ .TTY }}.service.d/autologin.conf
+[Service]
+ExecStart=
+{{ if .Serial -}}
+ExecStart=-/sbin/agetty -o '-p -f -- \\u' --keep-baud 115200,57600,38400,9600 --autologin {{ .User }} %I $TERM
+{{ else -}}
+ExecStart=-/sbin/agetty -o '-p -f -- \\u' --noclear --autologin {{ .User }} %I $TERM
+{{ end -}}
+EOF
+chmod 644 /etc/systemd/system/{{ .Service }}@{{ .TTY }}.service.d/autologin.conf
+
+systemctl enable {{ .Service }}@{{ .TTY }}.service
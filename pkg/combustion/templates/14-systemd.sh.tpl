@@ -8,4 +8,8 @@ set -euo pipefail
 
 {{ range .Enable }}
   systemctl enable {{ . }}
+{{ end }}
+
+{{ if .DefaultTarget }}
+  systemctl set-default {{ .DefaultTarget }}
 {{ end }}
\ No newline at end of file
@@ -0,0 +1,33 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* UdevDir - directory holding the provided rule files */ -}}
+{{/* Rules   - names of the provided rule files */ -}}
+
+mkdir -p /etc/udev/rules.d
+
+{{ range .Rules -}}
+install -m 644 ./{{ $.UdevDir }}/{{ . }} /etc/udev/rules.d/{{ . }}
+{{ end -}}
+
+# Devices discovered before the rules are read (e.g. by the initrd) are only affected once the rules are reapplied
+cat <<- EOF > /etc/systemd/system/eib-udev-reload.service
+[Unit]
+Description=Apply Embedded udev Rules
+After=systemd-udevd.service systemd-udev-trigger.service
+Before=network-pre.target
+Wants=network-pre.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/bin/udevadm control --reload-rules
+ExecStart=/usr/bin/udevadm trigger --action=add
+ExecStart=/usr/bin/udevadm settle
+
+[Install]
+WantedBy=multi-user.target
+EOF
+
+systemctl enable eib-udev-reload.service
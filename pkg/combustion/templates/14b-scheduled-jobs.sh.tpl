@@ -0,0 +1,45 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* JobsDir - directory holding the generated job scripts */ -}}
+{{/* Jobs    - scheduled jobs, each with a resolved backend */ -}}
+{{/* Cron    - whether any job uses the cron backend */ -}}
+
+mkdir -p /opt/eib-scheduled-jobs
+
+{{ range .Jobs -}}
+install -m 755 ./{{ $.JobsDir }}/{{ .Name }}.sh /opt/eib-scheduled-jobs/{{ .Name }}.sh
+{{ if eq .Backend "cron" -}}
+cat <<- EOF > /etc/cron.d/eib-{{ .Name }}
+{{ .Schedule }} root /opt/eib-scheduled-jobs/{{ .Name }}.sh
+EOF
+{{- else -}}
+cat <<- EOF > /etc/systemd/system/eib-job-{{ .Name }}.service
+[Unit]
+Description=Scheduled Job {{ .Name }}
+
+[Service]
+Type=oneshot
+ExecStart=/opt/eib-scheduled-jobs/{{ .Name }}.sh
+EOF
+
+cat <<- EOF > /etc/systemd/system/eib-job-{{ .Name }}.timer
+[Unit]
+Description=Schedule of Job {{ .Name }}
+
+[Timer]
+OnCalendar={{ .Schedule }}
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+EOF
+
+systemctl enable eib-job-{{ .Name }}.timer
+{{- end }}
+
+{{ end -}}
+{{ if .Cron -}}
+systemctl enable cron.service
+{{ end -}}
@@ -0,0 +1,36 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* SSHDDir         - directory holding the provided host keys */ -}}
+{{/* HostKeys        - names of the provided private host keys */ -}}
+{{/* AllowedKeyTypes - comma separated list of accepted key algorithms */ -}}
+{{/* Options         - additional sshd keywords and values */ -}}
+
+mkdir -p /etc/ssh/sshd_config.d
+
+# sshd uses the first obtained value for each keyword, so the drop-in directory must be read before the defaults
+if ! grep -q "^Include /etc/ssh/sshd_config.d/\*.conf" /etc/ssh/sshd_config; then
+  sed -i '1i Include /etc/ssh/sshd_config.d/*.conf' /etc/ssh/sshd_config
+fi
+
+{{ range .HostKeys -}}
+install -m 600 ./{{ $.SSHDDir }}/{{ . }} /etc/ssh/{{ . }}
+if [ -f ./{{ $.SSHDDir }}/{{ . }}.pub ]; then
+  install -m 644 ./{{ $.SSHDDir }}/{{ . }}.pub /etc/ssh/{{ . }}.pub
+fi
+
+{{ end -}}
+cat <<'EOF' > /etc/ssh/sshd_config.d/10-eib.conf
+{{- range .HostKeys }}
+HostKey /etc/ssh/{{ . }}
+{{- end }}
+{{- if .AllowedKeyTypes }}
+HostKeyAlgorithms {{ .AllowedKeyTypes }}
+PubkeyAcceptedAlgorithms {{ .AllowedKeyTypes }}
+{{- end }}
+{{- range .Options }}
+{{ .Key }} {{ .Value }}
+{{- end }}
+EOF
+chmod 600 /etc/ssh/sshd_config.d/10-eib.conf
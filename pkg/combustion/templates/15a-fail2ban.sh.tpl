@@ -0,0 +1,50 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* BanTime   - default duration an address is banned for */ -}}
+{{/* FindTime  - default window in which the failures of an address are counted */ -}}
+{{/* MaxRetry  - default number of failures after which an address is banned */ -}}
+{{/* IgnoreIPs - addresses and networks which are never banned */ -}}
+{{/* Jails     - enabled jails, along with their overrides of the defaults */ -}}
+
+mkdir -p /etc/fail2ban/jail.d
+
+# Drop-in .local files take precedence over the jail.conf shipped with the package
+cat <<'EOF' > /etc/fail2ban/jail.d/eib.local
+[DEFAULT]
+# The services of the node log to the journal rather than to files
+backend = systemd
+{{- if .BanTime }}
+bantime = {{ .BanTime }}
+{{- end }}
+{{- if .FindTime }}
+findtime = {{ .FindTime }}
+{{- end }}
+{{- if .MaxRetry }}
+maxretry = {{ .MaxRetry }}
+{{- end }}
+{{- if .IgnoreIPs }}
+ignoreip = {{ join .IgnoreIPs " " }}
+{{- end }}
+{{- range .Jails }}
+
+[{{ .Name }}]
+enabled = true
+{{- if .Port }}
+port = {{ .Port }}
+{{- end }}
+{{- if .BanTime }}
+bantime = {{ .BanTime }}
+{{- end }}
+{{- if .FindTime }}
+findtime = {{ .FindTime }}
+{{- end }}
+{{- if .MaxRetry }}
+maxretry = {{ .MaxRetry }}
+{{- end }}
+{{- end }}
+EOF
+chmod 644 /etc/fail2ban/jail.d/eib.local
+
+systemctl enable fail2ban.service
@@ -0,0 +1,25 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* AuditDir        - directory holding the provided rule files */ -}}
+{{/* RuleFiles       - names of the provided rule files */ -}}
+{{/* Rules           - rules specified directly in the definition */ -}}
+{{/* InlineRulesFile - name of the rule file the inline rules are written to */ -}}
+
+mkdir -p /etc/audit/rules.d
+
+{{ range .RuleFiles -}}
+install -m 600 ./{{ $.AuditDir }}/{{ . }} /etc/audit/rules.d/{{ . }}
+{{ end -}}
+
+{{ if .Rules -}}
+cat <<'EOF' > /etc/audit/rules.d/{{ .InlineRulesFile }}
+{{- range .Rules }}
+{{ . }}
+{{- end }}
+EOF
+chmod 600 /etc/audit/rules.d/{{ .InlineRulesFile }}
+
+{{ end -}}
+systemctl enable auditd.service
@@ -0,0 +1,29 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* AppArmorDir - directory holding the provided profiles */ -}}
+{{/* Profiles    - names of the provided profile files */ -}}
+
+# The profiles are only of use if AppArmor is the active LSM (e.g. not when SELinux is in use)
+apparmor_active() {
+  if [ -r /sys/kernel/security/lsm ]; then
+    grep -qw apparmor /sys/kernel/security/lsm
+  else
+    [ "$(cat /sys/module/apparmor/parameters/enabled 2>/dev/null)" = "Y" ]
+  fi
+}
+
+if ! apparmor_active; then
+  echo "AppArmor is not the active LSM, skipping the installation of AppArmor profiles"
+  exit 0
+fi
+
+mkdir -p /etc/apparmor.d
+
+{{ range .Profiles -}}
+install -m 644 ./{{ $.AppArmorDir }}/{{ . }} /etc/apparmor.d/{{ . }}
+apparmor_parser --replace /etc/apparmor.d/{{ . }}
+{{ end -}}
+
+systemctl enable apparmor.service
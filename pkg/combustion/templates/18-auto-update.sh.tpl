@@ -0,0 +1,29 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Enabled      - whether the transactional-update timer is enabled */ -}}
+{{/* Schedule     - systemd calendar expression replacing the default timer schedule */ -}}
+{{/* RebootPolicy - rebootmgr strategy applied to reboots requested by updates */ -}}
+
+{{ if .Enabled -}}
+{{ if .Schedule -}}
+mkdir -p /etc/systemd/system/transactional-update.timer.d
+cat <<- EOF > /etc/systemd/system/transactional-update.timer.d/50-eib.conf
+[Timer]
+OnCalendar=
+OnCalendar={{ .Schedule }}
+EOF
+
+{{ end -}}
+systemctl enable transactional-update.timer
+
+{{ end -}}
+{{ if .RebootPolicy -}}
+cat <<- EOF > /etc/rebootmgr.conf
+[rebootmgr]
+strategy={{ .RebootPolicy }}
+EOF
+
+systemctl enable rebootmgr.service
+{{ end -}}
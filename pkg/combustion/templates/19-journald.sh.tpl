@@ -0,0 +1,35 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Storage           - where the journal is stored (volatile, persistent, auto or none) */ -}}
+{{/* SystemMaxUse      - maximum disk space used by the persistent journal */ -}}
+{{/* SystemMaxFileSize - maximum size of individual persistent journal files */ -}}
+{{/* RuntimeMaxUse     - maximum memory used by the volatile journal */ -}}
+{{/* MaxRetention      - maximum time journal entries are retained */ -}}
+
+mkdir -p /etc/systemd/journald.conf.d
+
+cat <<- EOF > /etc/systemd/journald.conf.d/50-eib.conf
+[Journal]
+{{- if .Storage }}
+Storage={{ .Storage }}
+{{- end }}
+{{- if .SystemMaxUse }}
+SystemMaxUse={{ .SystemMaxUse }}
+{{- end }}
+{{- if .SystemMaxFileSize }}
+SystemMaxFileSize={{ .SystemMaxFileSize }}
+{{- end }}
+{{- if .RuntimeMaxUse }}
+RuntimeMaxUse={{ .RuntimeMaxUse }}
+{{- end }}
+{{- if .MaxRetention }}
+MaxRetentionSec={{ .MaxRetention }}
+{{- end }}
+EOF
+{{- if eq .Storage "persistent" }}
+
+# Created upfront so that the journal is persisted from the first boot
+mkdir -p /var/log/journal
+{{- end }}
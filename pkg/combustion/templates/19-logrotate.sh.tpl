@@ -0,0 +1,14 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* LogrotateDir - directory holding the generated configuration files */ -}}
+{{/* Configs      - names of the generated configuration files */ -}}
+
+mkdir -p /etc/logrotate.d
+
+{{ range .Configs -}}
+install -m 644 ./{{ $.LogrotateDir }}/{{ . }} /etc/logrotate.d/{{ . }}
+{{ end -}}
+
+systemctl enable logrotate.timer
@@ -0,0 +1,15 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Size      - size of the zram device in MiB, or an expression of the memory of the node */ -}}
+{{/* Algorithm - compression algorithm of the zram device */ -}}
+
+mkdir -p /etc/systemd
+cat <<- 'EOF' > /etc/systemd/zram-generator.conf
+[zram0]
+zram-size = {{ .Size }}
+{{- if .Algorithm }}
+compression-algorithm = {{ .Algorithm }}
+{{- end }}
+EOF
@@ -0,0 +1,18 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* SavePath - directory the dumps are saved to, the kdump default if empty */ -}}
+
+{{ if .SavePath -}}
+mkdir -p '{{ .SavePath }}'
+
+if grep -q "^KDUMP_SAVEDIR=" /etc/sysconfig/kdump; then
+  sed -i 's|^KDUMP_SAVEDIR=.*|KDUMP_SAVEDIR="file://{{ .SavePath }}"|' /etc/sysconfig/kdump
+else
+  echo 'KDUMP_SAVEDIR="file://{{ .SavePath }}"' >> /etc/sysconfig/kdump
+fi
+
+{{ end -}}
+# The memory of the crash kernel is reserved by the crashkernel kernel argument set while assembling the image
+systemctl enable kdump.service
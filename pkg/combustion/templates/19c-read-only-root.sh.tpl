@@ -0,0 +1,52 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Overlays   - writable mounts added to /etc/fstab, with the directories storing the changes of persistent ones */ -}}
+{{/* Persistent - whether any overlay stores its changes under /var */ -}}
+
+# The rw option of the root file system is replaced, and ro is added if neither is set
+awk '
+$1 !~ /^#/ && $2 == "/" {
+  found = 1
+  count = split($4, current, ",")
+  result = "ro"
+  for (i = 1; i <= count; i++) {
+    if (current[i] != "ro" && current[i] != "rw") result = result "," current[i]
+  }
+  $4 = result
+}
+
+{ print }
+
+END { if (!found) exit 1 }
+' /etc/fstab > /tmp/eib-fstab || {
+  echo "[ERROR] No root file system entry found in /etc/fstab"
+  exit 1
+}
+
+cat /tmp/eib-fstab > /etc/fstab
+rm /tmp/eib-fstab
+
+{{ if .Persistent -}}
+# The changes of persistent overlays are stored on the separately mounted /var subvolume
+mounted=""
+if ! mountpoint -q /var; then
+  mount /var
+  mounted="/var"
+fi
+
+{{ end -}}
+{{ range .Overlays -}}
+mkdir -p '{{ .Path }}'
+{{ if .UpperDir -}}
+mkdir -p '{{ .UpperDir }}' '{{ .WorkDir }}'
+{{ end -}}
+echo '{{ .Entry }}' >> /etc/fstab
+
+{{ end -}}
+{{ if .Persistent -}}
+if [ -n "$mounted" ]; then
+  umount "$mounted"
+fi
+{{ end -}}
@@ -0,0 +1,26 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* ImagesDir - directory holding the image archives */ -}}
+
+mkdir -p /opt/eib-podman-images
+cp {{ .ImagesDir }}/*.tar /opt/eib-podman-images/
+
+# The archives are removed once loaded, so that the images are only loaded on the first boot
+cat <<- EOF > /etc/systemd/system/eib-podman-images.service
+[Unit]
+Description=Load Embedded Podman Images
+After=local-fs.target
+ConditionDirectoryNotEmpty=/opt/eib-podman-images
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/sh -c 'for archive in /opt/eib-podman-images/*.tar; do podman load -i "\$archive" && rm "\$archive"; done'
+
+[Install]
+WantedBy=multi-user.target
+EOF
+
+systemctl enable eib-podman-images.service
@@ -0,0 +1,28 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* ArchivesDir - directory holding the image archives */ -}}
+{{/* Path        - directory on the node the archives are placed in */ -}}
+
+# The directory would otherwise be hidden once separately mounted subvolumes such as /var
+# are mounted on boot, so the fstab mount covering it is mounted first
+dir='{{ .Path }}'
+mounted=""
+while [ "$dir" != "/" ]; do
+  if findmnt --fstab --noheadings --mountpoint "$dir" > /dev/null; then
+    if ! mountpoint -q "$dir"; then
+      mount "$dir"
+      mounted="$dir"
+    fi
+    break
+  fi
+  dir=$(dirname "$dir")
+done
+
+mkdir -p '{{ .Path }}'
+cp {{ .ArchivesDir }}/*.tar '{{ .Path }}/'
+
+if [ -n "$mounted" ]; then
+  umount "$mounted"
+fi
@@ -0,0 +1,14 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* File      - profile.d script sourced by login shells */ -}}
+{{/* Variables - export statements written to the script */ -}}
+
+mkdir -p /etc/profile.d
+
+cat <<- 'EOF' > {{ .File }}
+# Shell defaults set by the Edge Image Builder
+{{ .Variables -}}
+EOF
+chmod 644 {{ .File }}
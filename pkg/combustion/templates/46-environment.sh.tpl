@@ -0,0 +1,16 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Names - names of the environment variables, replacing any existing definitions */ -}}
+{{/* Environment - lines appended to /etc/environment */ -}}
+
+touch /etc/environment
+{{ range .Names -}}
+sed -i '/^\(export \)\?{{ . }}=/d' /etc/environment
+{{ end -}}
+
+cat <<- 'EOF' >> /etc/environment
+{{ .Environment -}}
+EOF
+chmod 644 /etc/environment
@@ -0,0 +1,10 @@
+#!/bin/bash
+set -euo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Release - contents of the release file describing the build */ -}}
+
+cat <<- 'EOF' > /etc/eib-release
+{{ .Release -}}
+EOF
+chmod 644 /etc/eib-release
@@ -0,0 +1,20 @@
+#!/bin/bash
+set -uo pipefail
+
+{{/* Template Fields */ -}}
+{{/* Script        - name of the user provided validation script */ -}}
+{{/* IgnoreFailure - whether the boot continues if the validation fails */ -}}
+
+echo "Running boot validation script..."
+
+if ./{{ .Script }}; then
+  echo "Boot validation succeeded"
+else
+  rc=$?
+{{- if .IgnoreFailure }}
+  echo "WARNING: Boot validation failed with exit code ${rc}, continuing the boot"
+{{- else }}
+  echo "ERROR: Boot validation failed with exit code ${rc}, failing the boot"
+  exit "${rc}"
+{{- end }}
+fi
@@ -1,5 +1,9 @@
 #!/bin/bash
 set -euo pipefail
+{{- if .installGate }}
+
+{{ .installGate }}
+{{- end }}
 
 declare -A hosts
 
@@ -27,6 +31,11 @@ mount /var
 mkdir -p /var/lib/rancher/k3s/agent/images/
 cp {{ .imagesPath }}/* /var/lib/rancher/k3s/agent/images/
 
+{{- if .containerdConfig }}
+mkdir -p /var/lib/rancher/k3s/agent/etc/containerd/
+cp {{ .containerdConfig }} /var/lib/rancher/k3s/agent/etc/containerd/{{ .containerdConfigFile }}
+{{- end }}
+
 CONFIGFILE={{ .configFilePath }}/$NODETYPE.yaml
 
 if [ "$HOSTNAME" = {{ .initialiser }} ]; then
@@ -38,6 +47,10 @@ if [ "$HOSTNAME" = {{ .initialiser }} ]; then
     {{- end }}
 fi
 
+if [ -f {{ .configFilePath }}/{{ .nodeConfigsDir }}/$HOSTNAME.yaml ]; then
+    CONFIGFILE={{ .configFilePath }}/{{ .nodeConfigsDir }}/$HOSTNAME.yaml
+fi
+
 umount /var
 
 {{- if and .apiVIP .apiHost }}
@@ -61,3 +74,9 @@ cp {{ .binaryPath }} $INSTALL_K3S_BIN_DIR/k3s
 chmod +x $INSTALL_K3S_BIN_DIR/k3s
 
 sh {{ .installScript }}
+{{- if .kubeconfig }}
+
+if [ "$NODETYPE" = "server" ]; then
+{{ .kubeconfig }}
+fi
+{{- end }}
@@ -1,11 +1,20 @@
 #!/bin/bash
 set -euo pipefail
+{{- if .installGate }}
+
+{{ .installGate }}
+{{- end }}
 
 mount /var
 
 mkdir -p /var/lib/rancher/k3s/agent/images/
 cp {{ .imagesPath }}/* /var/lib/rancher/k3s/agent/images/
 
+{{- if .containerdConfig }}
+mkdir -p /var/lib/rancher/k3s/agent/etc/containerd/
+cp {{ .containerdConfig }} /var/lib/rancher/k3s/agent/etc/containerd/{{ .containerdConfigFile }}
+{{- end }}
+
 {{- if .manifestsPath }}
 mkdir -p /var/lib/rancher/k3s/server/manifests/
 cp {{ .manifestsPath }}/* /var/lib/rancher/k3s/server/manifests/
@@ -33,3 +42,7 @@ cp {{ .binaryPath }} $INSTALL_K3S_BIN_DIR/k3s
 chmod +x $INSTALL_K3S_BIN_DIR/k3s
 
 sh {{ .installScript }}
+{{- if .kubeconfig }}
+
+{{ .kubeconfig }}
+{{- end }}
@@ -0,0 +1,25 @@
+{{- if .Delay }}
+echo "Delaying Kubernetes installation by {{ .Delay }} seconds..."
+sleep {{ .Delay }}
+{{- end }}
+{{- if or (eq .Strategy "command") (eq .Strategy "url") }}
+
+readiness_check() {
+{{- if eq .Strategy "command" }}
+{{ .Command }}
+{{- else }}
+curl --silent --fail --output /dev/null '{{ .URL }}'
+{{- end }}
+}
+
+GATE_DEADLINE=$(( $(date +%s) + {{ .Timeout }} ))
+until readiness_check; do
+  if (( $(date +%s) >= GATE_DEADLINE )); then
+    echo "Readiness check did not pass within {{ .Timeout }} seconds, aborting Kubernetes installation"
+    exit 1
+  fi
+
+  echo "Waiting for the readiness check to pass before installing Kubernetes..."
+  sleep 5
+done
+{{- end }}
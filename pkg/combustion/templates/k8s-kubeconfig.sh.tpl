@@ -0,0 +1,26 @@
+{{/* Template Fields */ -}}
+{{/* Path    - location the admin kubeconfig is copied to */ -}}
+{{/* Server  - optional API server address replacing the local one */ -}}
+{{/* Source  - admin kubeconfig generated by the Kubernetes server */ -}}
+{{/* Service - Kubernetes server service generating the admin kubeconfig */ -}}
+
+# The admin kubeconfig is only generated once the Kubernetes server has started
+cat <<- EOF > /etc/systemd/system/eib-kubeconfig.service
+[Unit]
+Description=Distribute the Kubernetes Admin Kubeconfig
+After={{ .Service }}
+Requires={{ .Service }}
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/bin/install -D -m 600 {{ .Source }} {{ .Path }}
+{{- if .Server }}
+ExecStart=/usr/bin/sed -i "s|server: .*|server: {{ .Server }}|" {{ .Path }}
+{{- end }}
+
+[Install]
+WantedBy=multi-user.target
+EOF
+
+systemctl enable eib-kubeconfig.service
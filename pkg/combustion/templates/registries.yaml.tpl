@@ -1,9 +1,14 @@
 mirrors:
-  docker.io:
+{{- range .Mirrors }}
+  {{ .Registry }}:
     endpoint:
-      - "http://localhost:{{ .Port }}"
-{{- range .Hostnames }}
-  {{ . }}:
-    endpoint:
-      - "http://localhost:{{ $.Port }}"
-{{- end }}
\ No newline at end of file
+{{- range .Endpoints }}
+      - {{ printf "%q" . }}
+{{- end }}
+{{- if .Rewrites }}
+    rewrite:
+{{- range .Rewrites }}
+      {{ printf "%q" .Pattern }}: {{ printf "%q" .Replacement }}
+{{- end }}
+{{- end }}
+{{- end }}
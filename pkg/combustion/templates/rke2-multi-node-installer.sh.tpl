@@ -1,5 +1,9 @@
 #!/bin/bash
 set -euo pipefail
+{{- if .installGate }}
+
+{{ .installGate }}
+{{- end }}
 
 declare -A hosts
 
@@ -27,6 +31,11 @@ mount /var
 mkdir -p /var/lib/rancher/rke2/agent/images/
 cp {{ .imagesPath }}/* /var/lib/rancher/rke2/agent/images/
 
+{{- if .containerdConfig }}
+mkdir -p /var/lib/rancher/rke2/agent/etc/containerd/
+cp {{ .containerdConfig }} /var/lib/rancher/rke2/agent/etc/containerd/{{ .containerdConfigFile }}
+{{- end }}
+
 CONFIGFILE={{ .configFilePath }}/$NODETYPE.yaml
 
 if [ "$HOSTNAME" = {{ .initialiser }} ]; then
@@ -38,6 +47,10 @@ if [ "$HOSTNAME" = {{ .initialiser }} ]; then
     {{- end }}
 fi
 
+if [ -f {{ .configFilePath }}/{{ .nodeConfigsDir }}/$HOSTNAME.yaml ]; then
+    CONFIGFILE={{ .configFilePath }}/{{ .nodeConfigsDir }}/$HOSTNAME.yaml
+fi
+
 umount /var
 
 {{- if .apiHost }}
@@ -61,3 +74,9 @@ mkdir -p /opt/cni
 sh {{ .installScript }}
 
 systemctl enable rke2-$NODETYPE.service
+{{- if .kubeconfig }}
+
+if [ "$NODETYPE" = "server" ]; then
+{{ .kubeconfig }}
+fi
+{{- end }}
@@ -1,11 +1,20 @@
 #!/bin/bash
 set -euo pipefail
+{{- if .installGate }}
+
+{{ .installGate }}
+{{- end }}
 
 mount /var
 
 mkdir -p /var/lib/rancher/rke2/agent/images/
 cp {{ .imagesPath }}/* /var/lib/rancher/rke2/agent/images/
 
+{{- if .containerdConfig }}
+mkdir -p /var/lib/rancher/rke2/agent/etc/containerd/
+cp {{ .containerdConfig }} /var/lib/rancher/rke2/agent/etc/containerd/{{ .containerdConfigFile }}
+{{- end }}
+
 {{- if .manifestsPath }}
 mkdir -p /var/lib/rancher/rke2/server/manifests/
 cp {{ .manifestsPath }}/* /var/lib/rancher/rke2/server/manifests/
@@ -34,3 +43,7 @@ mkdir -p /opt/cni
 sh {{ .installScript }}
 
 systemctl enable rke2-server.service
+{{- if .kubeconfig }}
+
+{{ .kubeconfig }}
+{{- end }}
@@ -0,0 +1,85 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	udevComponentName = "udev"
+	udevScriptName    = "14a-udev.sh"
+	UdevConfigDir     = "udev"
+)
+
+//go:embed templates/14a-udev.sh.tpl
+var udevScriptTemplate string
+
+func configureUdev(ctx *image.Context) ([]string, error) {
+	rules := ctx.ImageDefinition.OperatingSystem.Udev.Rules
+	if len(rules) == 0 {
+		log.AuditComponentSkipped(udevComponentName)
+		return nil, nil
+	}
+
+	if err := copyUdevRules(ctx, rules); err != nil {
+		log.AuditComponentFailed(udevComponentName)
+		return nil, err
+	}
+
+	if err := writeUdevScript(ctx, rules); err != nil {
+		log.AuditComponentFailed(udevComponentName)
+		return nil, err
+	}
+
+	log.AuditInfof("Installing udev rules [%s], which are applied at boot.", strings.Join(rules, ", "))
+
+	log.AuditComponentSuccessful(udevComponentName)
+	return []string{udevScriptName}, nil
+}
+
+func copyUdevRules(ctx *image.Context, rules []string) error {
+	srcDir := generateComponentPath(ctx, UdevConfigDir)
+	destDir := filepath.Join(ctx.CombustionDir, UdevConfigDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating udev directory '%s': %w", destDir, err)
+	}
+
+	for _, rule := range rules {
+		if err := fileio.CopyFile(filepath.Join(srcDir, rule), filepath.Join(destDir, rule), fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying udev rule '%s': %w", rule, err)
+		}
+	}
+
+	return nil
+}
+
+func writeUdevScript(ctx *image.Context, rules []string) error {
+	values := struct {
+		UdevDir string
+		Rules   []string
+	}{
+		UdevDir: UdevConfigDir,
+		Rules:   rules,
+	}
+
+	data, err := template.Parse(udevScriptName, udevScriptTemplate, &values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", udevScriptName, err)
+	}
+
+	destFilename := filepath.Join(ctx.CombustionDir, udevScriptName)
+	if err = os.WriteFile(destFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", destFilename, err)
+	}
+
+	return nil
+}
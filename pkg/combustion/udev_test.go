@@ -0,0 +1,76 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureUdev_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{}
+
+	// Test
+	scripts, err := configureUdev(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureUdev(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	srcDir := filepath.Join(ctx.ImageConfigDir, UdevConfigDir)
+	require.NoError(t, os.Mkdir(srcDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "70-net.rules"), []byte(`SUBSYSTEM=="net", NAME="lan0"`+"\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "99-unused.rules"), []byte(`SUBSYSTEM=="block"`+"\n"), 0o600))
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Udev: image.Udev{
+				Rules: []string{"70-net.rules"},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureUdev(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, udevScriptName, scripts[0])
+
+	// - rules
+	destDir := filepath.Join(ctx.CombustionDir, UdevConfigDir)
+	assert.FileExists(t, filepath.Join(destDir, "70-net.rules"))
+	assert.NoFileExists(t, filepath.Join(destDir, "99-unused.rules"))
+
+	// - script
+	expectedFilename := filepath.Join(ctx.CombustionDir, udevScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "install -m 644 ./udev/70-net.rules /etc/udev/rules.d/70-net.rules")
+	assert.Contains(t, foundContents, "ExecStart=/usr/bin/udevadm control --reload-rules")
+	assert.Contains(t, foundContents, "ExecStart=/usr/bin/udevadm trigger --action=add")
+	assert.Contains(t, foundContents, "systemctl enable eib-udev-reload.service")
+	assert.NotContains(t, foundContents, "unused")
+}
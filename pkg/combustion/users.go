@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
@@ -40,6 +42,49 @@ func configureUsers(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("writing %s to the combustion directory: %w", usersScriptName, err)
 	}
 
+	for _, user := range ctx.ImageDefinition.OperatingSystem.Users {
+		log.AuditInfof("User '%s' will be configured with %s.", user.Username, describeUser(&user))
+	}
+
 	log.AuditComponentSuccessful(usersComponentName)
 	return []string{usersScriptName}, nil
 }
+
+// describeUser summarizes the settings of a user, excluding its credentials, while any
+// setting left to the operating system is reported as its default.
+func describeUser(user *image.OperatingSystemUser) string {
+	valueOrDefault := func(value string) string {
+		if value == "" {
+			return "default"
+		}
+		return fmt.Sprintf("'%s'", value)
+	}
+
+	uid := "default"
+	if user.UID != 0 {
+		uid = strconv.Itoa(user.UID)
+	}
+
+	homeDir := user.HomeDir
+	switch {
+	case homeDir != "":
+	case user.Username == "root":
+		homeDir = "/root"
+	default:
+		homeDir = "/home/" + user.Username
+	}
+
+	description := fmt.Sprintf("uid %s, primary group %s, groups [%s], shell %s, home directory %s (created: %t), %d SSH key(s)",
+		uid, valueOrDefault(user.PrimaryGroup), strings.Join(user.SecondaryGroups, ", "), valueOrDefault(user.Shell),
+		valueOrDefault(homeDir), user.CreateHomeDir, len(user.SSHKeys))
+
+	if user.PasswordExpire > 0 {
+		description += fmt.Sprintf(", password expiring after %d day(s)", user.PasswordExpire)
+	}
+
+	if user.ForceChange {
+		description += ", password change forced on first login"
+	}
+
+	return description
+}
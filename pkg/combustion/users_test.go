@@ -38,10 +38,24 @@ func TestConfigureUsers(t *testing.T) {
 					Username: "gamma",
 					SSHKeys:  []string{"gammakey"},
 				},
+				{
+					Username:      "delta",
+					SSHKeys:       []string{"deltakey"},
+					CreateHomeDir: true,
+					Shell:         "/usr/sbin/nologin",
+					HomeDir:       "/var/lib/delta",
+				},
+				{
+					Username:          "epsilon",
+					EncryptedPassword: "epsilon123",
+					PasswordExpire:    90,
+					ForceChange:       true,
+				},
 				{
 					Username:          "root",
 					EncryptedPassword: "root123",
 					SSHKeys:           []string{"rootkey1", "rootkey2"},
+					ForceChange:       true,
 				},
 			},
 		},
@@ -88,6 +102,18 @@ func TestConfigureUsers(t *testing.T) {
 	assert.Contains(t, foundContents, "echo 'gammakey' >> /home/gamma/.ssh/authorized_keys")
 	assert.Contains(t, foundContents, "chown -R gamma /home/gamma/.ssh")
 
+	// - Shell and custom home directory
+	assert.Contains(t, foundContents, "useradd -m -d /var/lib/delta -s /usr/sbin/nologin delta")
+	assert.Contains(t, foundContents, "mkdir -pm700 /var/lib/delta/.ssh/")
+	assert.Contains(t, foundContents, "echo 'deltakey' >> /var/lib/delta/.ssh/authorized_keys")
+	assert.Contains(t, foundContents, "chown -R delta /var/lib/delta/.ssh")
+	assert.NotContains(t, foundContents, "/home/delta")
+
+	// - Password expiry
+	assert.Contains(t, foundContents, "echo 'epsilon:epsilon123' | chpasswd -e\nchage -M 90 epsilon\nchage -d 0 epsilon\n")
+	assert.NotContains(t, foundContents, "chage -M 90 root")
+	assert.NotContains(t, foundContents, "chage -d 0 alpha")
+
 	// - Special handling for root
 	assert.NotContains(t, foundContents, "useradd root")
 	assert.Contains(t, foundContents, "echo 'root:root123' | chpasswd -e\n")
@@ -95,6 +121,7 @@ func TestConfigureUsers(t *testing.T) {
 	assert.Contains(t, foundContents, "echo 'rootkey1' >> /root/.ssh/authorized_keys")
 	assert.Contains(t, foundContents, "echo 'rootkey2' >> /root/.ssh/authorized_keys")
 	assert.NotContains(t, foundContents, "chown -R root")
+	assert.Contains(t, foundContents, "echo 'root:root123' | chpasswd -e\nchage -d 0 root\n")
 }
 
 func TestConfigureUsers_NoUsers(t *testing.T) {
@@ -114,3 +141,43 @@ func TestConfigureUsers_NoUsers(t *testing.T) {
 	_, err = os.ReadFile(expectedFilename)
 	require.ErrorIs(t, err, os.ErrNotExist)
 }
+
+func TestDescribeUser(t *testing.T) {
+	tests := map[string]struct {
+		user     image.OperatingSystemUser
+		expected string
+	}{
+		"defaults": {
+			user:     image.OperatingSystemUser{Username: "alpha", EncryptedPassword: "secret"},
+			expected: "uid default, primary group default, groups [], shell default, home directory '/home/alpha' (created: false), 0 SSH key(s)",
+		},
+		"all fields": {
+			user: image.OperatingSystemUser{
+				Username:        "beta",
+				UID:             2000,
+				SSHKeys:         []string{"key1", "key2"},
+				PrimaryGroup:    "svc",
+				SecondaryGroups: []string{"wheel", "users"},
+				CreateHomeDir:   true,
+				Shell:           "/bin/bash",
+				HomeDir:         "/var/lib/beta",
+			},
+			expected: "uid 2000, primary group 'svc', groups [wheel, users], shell '/bin/bash', home directory '/var/lib/beta' (created: true), 2 SSH key(s)",
+		},
+		"password expiry": {
+			user:     image.OperatingSystemUser{Username: "gamma", EncryptedPassword: "secret", PasswordExpire: 90, ForceChange: true},
+			expected: "uid default, primary group default, groups [], shell default, home directory '/home/gamma' (created: false), 0 SSH key(s), password expiring after 90 day(s), password change forced on first login",
+		},
+		"root": {
+			user:     image.OperatingSystemUser{Username: "root", SSHKeys: []string{"key"}},
+			expected: "uid default, primary group default, groups [], shell default, home directory '/root' (created: false), 1 SSH key(s)",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, describeUser(&test.user))
+			assert.NotContains(t, describeUser(&test.user), "secret")
+		})
+	}
+}
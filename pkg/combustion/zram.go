@@ -0,0 +1,89 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	zramComponentName = "zram"
+	zramScriptName    = "19a-zram.sh"
+
+	// ZramGeneratorPackage is installed along with the other packages when zram is configured.
+	ZramGeneratorPackage = "zram-generator"
+)
+
+//go:embed templates/19a-zram.sh.tpl
+var zramScriptTemplate string
+
+// IsZramConfigured reports whether the definition sets up a zram device, which requires zram-generator.
+func IsZramConfigured(zram *image.Zram) bool {
+	return zram.Size != "" || zram.Ratio != 0
+}
+
+// configureZram configures the zram swap device, which is set up by zram-generator at boot.
+func configureZram(ctx *image.Context) ([]string, error) {
+	zram := &ctx.ImageDefinition.OperatingSystem.Zram
+
+	if !IsZramConfigured(zram) {
+		log.AuditComponentSkipped(zramComponentName)
+		return nil, nil
+	}
+
+	values := struct {
+		Size      string
+		Algorithm string
+	}{
+		Size:      zramSize(zram),
+		Algorithm: zram.Algorithm,
+	}
+
+	data, err := template.Parse(zramScriptName, zramScriptTemplate, &values)
+	if err != nil {
+		log.AuditComponentFailed(zramComponentName)
+		return nil, fmt.Errorf("applying template to %s: %w", zramScriptName, err)
+	}
+
+	filename := filepath.Join(ctx.CombustionDir, zramScriptName)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		log.AuditComponentFailed(zramComponentName)
+		return nil, fmt.Errorf("writing file %s: %w", filename, err)
+	}
+
+	log.AuditInfof("A zram swap device %s will be configured.", ZramDescription(zram))
+
+	log.AuditComponentSuccessful(zramComponentName)
+	return []string{zramScriptName}, nil
+}
+
+// zramSize returns the size of the device in the format of zram-generator, which is either
+// in MiB or an expression of the memory of the node.
+func zramSize(zram *image.Zram) string {
+	if zram.Size != "" {
+		return strconv.FormatInt(zram.Size.ToMB(), 10)
+	}
+
+	return "ram * " + strconv.FormatFloat(zram.Ratio, 'f', -1, 64)
+}
+
+// ZramDescription describes the size and compression algorithm of the zram device.
+func ZramDescription(zram *image.Zram) string {
+	description := fmt.Sprintf("of %s", zram.Size)
+	if zram.Size == "" {
+		description = fmt.Sprintf("of %s%% of the memory", strconv.FormatFloat(zram.Ratio*100, 'f', -1, 64))
+	}
+
+	if zram.Algorithm != "" {
+		description += fmt.Sprintf(" compressed with %s", zram.Algorithm)
+	}
+
+	return description
+}
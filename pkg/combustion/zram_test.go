@@ -0,0 +1,81 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureZram_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	scripts, err := configureZram(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, zramScriptName))
+}
+
+func TestConfigureZram(t *testing.T) {
+	tests := map[string]struct {
+		zram             image.Zram
+		expectedContents string
+	}{
+		"Fixed size": {
+			zram: image.Zram{Size: "4G", Algorithm: "zstd"},
+			expectedContents: "[zram0]\n" +
+				"zram-size = 4096\n" +
+				"compression-algorithm = zstd\n" +
+				"EOF",
+		},
+		"Ratio": {
+			zram: image.Zram{Ratio: 0.5},
+			expectedContents: "[zram0]\n" +
+				"zram-size = ram * 0.5\n" +
+				"EOF",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			ctx.ImageDefinition.OperatingSystem.Zram = test.zram
+
+			// Test
+			scripts, err := configureZram(ctx)
+
+			// Verify
+			require.NoError(t, err)
+			assert.Equal(t, []string{zramScriptName}, scripts)
+
+			scriptPath := filepath.Join(ctx.CombustionDir, zramScriptName)
+
+			info, err := os.Stat(scriptPath)
+			require.NoError(t, err)
+			assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+			b, err := os.ReadFile(scriptPath)
+			require.NoError(t, err)
+
+			contents := string(b)
+			assert.Contains(t, contents, "cat <<- 'EOF' > /etc/systemd/zram-generator.conf\n"+test.expectedContents)
+		})
+	}
+}
+
+func TestZramDescription(t *testing.T) {
+	assert.Equal(t, "of 4G compressed with zstd", ZramDescription(&image.Zram{Size: "4G", Algorithm: "zstd"}))
+	assert.Equal(t, "of 50% of the memory", ZramDescription(&image.Zram{Ratio: 0.5}))
+}
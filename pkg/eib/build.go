@@ -0,0 +1,279 @@
+package eib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+)
+
+// Options customizes a build triggered through Build.
+type Options struct {
+	// RootBuildDir is the directory shared between builds, holding among others the artefact cache.
+	RootBuildDir string
+	// Strict treats validation warnings as errors.
+	Strict bool
+	// SkipValidation skips validating the image definition, for callers which have already validated it.
+	SkipValidation bool
+	// MaxBandwidth limits the aggregate download throughput of the build in bytes per second, unlimited if zero.
+	MaxBandwidth int64
+	// Phases restricts the build to the given phases (see SelectPhases), all of which are run if empty.
+	Phases Phases
+	// ProfileArtifacts records the timing of each artifact download in the report and audits a summary of them.
+	ProfileArtifacts bool
+	// ExpectedHash fails the build unless the image definition, with its includes resolved, has the given SHA-256
+	// hash (see image.DefinitionHash). The hash is not checked if empty.
+	ExpectedHash string
+	// Reproducible fixes the timestamps of the build to SourceDateEpoch, or to the Unix epoch if it is not set.
+	Reproducible bool
+	// SourceDateEpoch is the SOURCE_DATE_EPOCH timestamp of reproducible builds, honored even without Reproducible.
+	SourceDateEpoch string
+	// ToolArgs are the unsupported 'key=value' options forwarded to the build tooling (see build.ParseToolArgs).
+	// Only the options of the tools run by the selected phases are accepted.
+	ToolArgs []string
+}
+
+// ValidationError is returned by Build when the image definition fails validation.
+type ValidationError struct {
+	// Failures are the failed validations grouped by component. In strict mode these include the warnings.
+	Failures map[string][]validation.FailedValidation
+	// Findings are the same failed validations, ordered by component (see ValidateDefinition).
+	Findings []Finding
+}
+
+func (e *ValidationError) Error() string {
+	components := make([]string, 0, len(e.Failures))
+	var count int
+	for component, failures := range e.Failures {
+		components = append(components, component)
+		count += len(failures)
+	}
+	slices.Sort(components)
+
+	return fmt.Sprintf("image definition validation failed with %d failure(s) in: %s", count, strings.Join(components, ", "))
+}
+
+// PanicError is returned by Build when the build panics, retaining the stack trace of the panic.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("build failed unexpectedly: %v", e.Value)
+}
+
+// Build prepares the build context from the options (see Prepare) and builds the image it describes, returning the
+// report describing the built image. The build, combustion and artefacts directories of the context must
+// already exist (see SetupBuildDirectory and SetupCombustionDirectory).
+//
+// Unlike the CLI, Build never exits the process; all failures, including unexpected panics during the
+// build, are returned as errors. Canceling the context aborts the downloads in progress and stops the build
+// before its next phase. The download settings of the options only apply to this build, so that builds may
+// run concurrently.
+func Build(ctx context.Context, buildCtx *image.Context, opts Options) (buildReport *report.Report, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			buildReport = nil
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	if err = ctx.Err(); err != nil {
+		return nil, fmt.Errorf("build canceled: %w", err)
+	}
+
+	if opts.RootBuildDir == "" {
+		return nil, errors.New("root build directory not specified")
+	}
+
+	if err = Prepare(buildCtx, opts); err != nil {
+		return nil, err
+	}
+
+	ctx = http.WithBandwidthLimit(ctx, opts.MaxBandwidth)
+	if opts.MaxBandwidth > 0 {
+		log.AuditInfof("Downloads will be limited to %s.", http.FormatBandwidth(opts.MaxBandwidth))
+	}
+
+	if opts.ProfileArtifacts {
+		ctx = http.WithDownloadProfile(ctx)
+	}
+
+	if err = Run(ctx, buildCtx, opts.RootBuildDir, opts.Phases); err != nil {
+		return nil, fmt.Errorf("building image: %w", err)
+	}
+
+	buildReport = NewReport(buildCtx)
+
+	if opts.ProfileArtifacts {
+		records := http.DownloadRecords(ctx)
+		buildReport.Downloads = newReportDownloads(records)
+
+		if len(records) == 0 {
+			log.AuditInfo("No artifacts were downloaded, all of them were either cached or not needed.")
+		} else {
+			log.AuditInfof("Artifact downloads, from the slowest to the fastest:\n%s", http.FormatDownloadSummary(records))
+		}
+	}
+
+	return buildReport, nil
+}
+
+func newReportDownloads(records []http.DownloadRecord) []report.Download {
+	downloads := make([]report.Download, 0, len(records))
+	for _, record := range records {
+		downloads = append(downloads, report.Download{
+			Artifact: record.Artifact,
+			Source:   record.Source,
+			Bytes:    record.Bytes,
+			Started:  record.Started.UTC().Format(time.RFC3339Nano),
+			Finished: record.Finished.UTC().Format(time.RFC3339Nano),
+			Seconds:  record.Duration().Seconds(),
+		})
+	}
+
+	return downloads
+}
+
+// validateDefinition returns a ValidationError if any validation fails, ignoring warnings unless in strict mode.
+func validateDefinition(buildCtx *image.Context, strict bool) error {
+	failures := map[string][]validation.FailedValidation{}
+
+	for component, componentFailures := range validation.ValidateDefinition(buildCtx) {
+		for _, failure := range componentFailures {
+			if failure.Warning && !strict {
+				continue
+			}
+
+			failures[component] = append(failures[component], failure)
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Failures: failures, Findings: newFindings(failures)}
+}
+
+// NewReport describes the build, which is timestamped with the fixed source date of reproducible builds
+// and identifies the definition by the same hash as /etc/eib-release in the image.
+func NewReport(buildCtx *image.Context) *report.Report {
+	created := buildCtx.SourceDate
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	buildReport := report.New(buildCtx.ImageDefinition, created)
+	// The release is determined from the base image actually built from, which may be overridden
+	buildReport.BaseImageOverride = buildCtx.BaseImageOverride
+	buildReport.BaseImageRelease = image.BaseImageRelease(buildCtx.BaseImagePath())
+	buildReport.DefinitionHash = buildCtx.DefinitionHash
+	buildReport.RootMountOptions = buildCtx.RootMountOptions
+	buildReport.CombustionISO = buildCtx.CombustionISO
+
+	if buildCtx.Qcow2VirtualSize != 0 {
+		buildReport.Qcow2 = &report.Qcow2{
+			VirtualSize: buildCtx.Qcow2VirtualSize,
+			ActualSize:  buildCtx.Qcow2ActualSize,
+		}
+	}
+
+	for _, seed := range buildCtx.SeedISOs {
+		buildReport.SeedISOs = append(buildReport.SeedISOs, report.SeedISO{
+			Hostname: seed.Hostname,
+			Path:     seed.Path,
+		})
+	}
+
+	for _, archive := range buildCtx.ImageArchives {
+		buildReport.ImageArchives = append(buildReport.ImageArchives, report.ImageArchive{
+			Image: archive.Image,
+			Path:  archive.Path,
+			Size:  archive.Size,
+		})
+	}
+
+	for _, cert := range buildCtx.Certificates {
+		buildReport.Certificates = append(buildReport.Certificates, report.Certificate{
+			File:     cert.File,
+			Subjects: cert.Subjects,
+		})
+	}
+
+	for _, crds := range buildCtx.HelmChartCRDs {
+		buildReport.HelmChartCRDs = append(buildReport.HelmChartCRDs, report.HelmChartCRDs{
+			Chart:    crds.Chart,
+			Manifest: crds.Manifest,
+			CRDs:     crds.Names,
+		})
+	}
+
+	if buildReport.SecureBoot != nil {
+		buildReport.SecureBoot.SignedKernels = buildCtx.SignedKernels
+		buildReport.SecureBoot.SignedModules = buildCtx.SignedModules
+	}
+
+	if buildReport.Kernel != nil {
+		buildReport.Kernel.Release = buildCtx.KernelRelease
+	}
+
+	if buildReport.StripDocs != nil {
+		buildReport.StripDocs.ReclaimedKB = buildCtx.StrippedDocsKB
+	}
+
+	for _, slot := range buildCtx.RootSlots {
+		buildReport.RootSlots = append(buildReport.RootSlots, report.RootSlot{
+			Name:      slot.Name,
+			Partition: slot.Partition,
+			Label:     slot.Label,
+			SizeMB:    slot.SizeMB,
+			Default:   slot.Name == "a",
+		})
+	}
+
+	if buildCtx.ImageDefinition.OperatingSystem.ReadOnlyRoot.Enabled {
+		buildReport.ReadOnlyRoot = &report.ReadOnlyRoot{Overlays: []report.Overlay{}}
+		for _, overlay := range buildCtx.Overlays {
+			buildReport.ReadOnlyRoot.Overlays = append(buildReport.ReadOnlyRoot.Overlays, report.Overlay{
+				Path:     overlay.Path,
+				Type:     overlay.Type,
+				Size:     overlay.Size,
+				UpperDir: overlay.UpperDir,
+			})
+		}
+	}
+
+	if len(buildCtx.ResolvedPackages) > 0 {
+		buildReport.Packages = newReportPackages(buildCtx)
+	}
+
+	return buildReport
+}
+
+func newReportPackages(buildCtx *image.Context) *report.Packages {
+	packages := &report.Packages{
+		Requested: buildCtx.ImageDefinition.OperatingSystem.Packages.PKGList,
+		Count:     len(buildCtx.ResolvedPackages),
+	}
+
+	for _, pkg := range buildCtx.ResolvedPackages {
+		packages.Resolved = append(packages.Resolved, report.ResolvedPackage{
+			File: pkg.File,
+			Size: pkg.Size,
+		})
+		packages.Size += pkg.Size
+	}
+
+	return packages
+}
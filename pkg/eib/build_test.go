@@ -0,0 +1,266 @@
+package eib
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+)
+
+func TestBuild_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	buildReport, err := Build(ctx, &image.Context{ImageDefinition: &image.Definition{}}, Options{RootBuildDir: t.TempDir()})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, buildReport)
+}
+
+func TestBuild_MissingRootBuildDir(t *testing.T) {
+	buildReport, err := Build(context.Background(), &image.Context{ImageDefinition: &image.Definition{}}, Options{})
+	require.EqualError(t, err, "root build directory not specified")
+	assert.Nil(t, buildReport)
+}
+
+func TestBuild_InvalidDefinition(t *testing.T) {
+	buildCtx := &image.Context{
+		ImageConfigDir:  t.TempDir(),
+		ImageDefinition: &image.Definition{},
+	}
+
+	buildReport, err := Build(context.Background(), buildCtx, Options{RootBuildDir: t.TempDir()})
+	require.Error(t, err)
+	assert.Nil(t, buildReport)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.NotEmpty(t, validationErr.Failures)
+	assert.NotEmpty(t, validationErr.Findings)
+}
+
+func TestBuild_PreparePanic(t *testing.T) {
+	// Panics preparing the build are recovered, like those of the build itself
+	buildReport, err := Build(context.Background(), &image.Context{}, Options{RootBuildDir: t.TempDir()})
+	assert.Nil(t, buildReport)
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	assert.NotEmpty(t, panicErr.Stack)
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{
+		Failures: map[string][]validation.FailedValidation{
+			"Operating System": {{UserMessage: "first"}, {UserMessage: "second"}},
+			"Image":            {{UserMessage: "third"}},
+		},
+	}
+
+	assert.EqualError(t, err, "image definition validation failed with 3 failure(s) in: Image, Operating System")
+}
+
+func TestPanicError(t *testing.T) {
+	err := &PanicError{Value: "boom", Stack: []byte("goroutine 1")}
+
+	assert.EqualError(t, err, "build failed unexpectedly: boom")
+}
+
+func TestNewReport(t *testing.T) {
+	definition := &image.Definition{
+		Image: image.Image{
+			OutputImageName: "edge.raw",
+		},
+	}
+
+	buildReport := NewReport(&image.Context{ImageDefinition: definition})
+	assert.Equal(t, "edge.raw", buildReport.ImageName)
+	assert.NotEmpty(t, buildReport.Created)
+
+	sourceDate := time.Unix(0, 0)
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, SourceDate: sourceDate})
+	assert.Equal(t, "1970-01-01T00:00:00Z", buildReport.Created)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, DefinitionHash: "3a5b"})
+	assert.Equal(t, "3a5b", buildReport.DefinitionHash)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, RootMountOptions: "ro,noatime"})
+	assert.Equal(t, "ro,noatime", buildReport.RootMountOptions)
+
+	buildReport = NewReport(&image.Context{
+		ImageDefinition: definition,
+		RootSlots: []image.RootSlot{
+			{Name: "a", Partition: 3, Label: "ROOT", SizeMB: 16381},
+			{Name: "b", Partition: 4, Label: "ROOT_B", SizeMB: 16381},
+		},
+	})
+	assert.Equal(t, []report.RootSlot{
+		{Name: "a", Partition: 3, Label: "ROOT", SizeMB: 16381, Default: true},
+		{Name: "b", Partition: 4, Label: "ROOT_B", SizeMB: 16381},
+	}, buildReport.RootSlots)
+
+	releaseDefinition := &image.Definition{Image: image.Image{BaseImage: "SL-Micro.x86_64-6.0-Base-GM.raw"}}
+	buildReport = NewReport(&image.Context{ImageDefinition: releaseDefinition})
+	assert.Equal(t, "SL Micro 6.0", buildReport.BaseImageRelease)
+
+	assert.Equal(t, "SL-Micro.x86_64-6.0-Base-GM.raw", buildReport.BaseImage)
+	assert.Empty(t, buildReport.BaseImageOverride)
+
+	// The overridden base image is reported alongside the one of the definition, with its own release
+	buildReport = NewReport(&image.Context{ImageDefinition: releaseDefinition, BaseImageOverride: "/images/SL-Micro.x86_64-6.1-Base-GM.raw"})
+	assert.Equal(t, "SL-Micro.x86_64-6.0-Base-GM.raw", buildReport.BaseImage)
+	assert.Equal(t, "/images/SL-Micro.x86_64-6.1-Base-GM.raw", buildReport.BaseImageOverride)
+	assert.Equal(t, "SL Micro 6.1", buildReport.BaseImageRelease)
+
+	signedDefinition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				Signing: image.SecureBootSigning{Key: "edge.key", Certificate: "edge.crt", Kernel: true, Modules: true},
+			},
+		},
+	}
+	buildReport = NewReport(&image.Context{
+		ImageDefinition: signedDefinition,
+		SignedKernels:   []string{"/boot/vmlinuz-6.4.0-17-default"},
+		SignedModules:   []string{"/usr/lib/modules/6.4.0-17-default/updates/driver.ko.zst"},
+	})
+	assert.Equal(t, &report.SecureBoot{
+		SignKernel:    true,
+		SignModules:   true,
+		SignedKernels: []string{"/boot/vmlinuz-6.4.0-17-default"},
+		SignedModules: []string{"/usr/lib/modules/6.4.0-17-default/updates/driver.ko.zst"},
+	}, buildReport.SecureBoot)
+
+	// Signed artefacts are only reported when requested
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, SignedKernels: []string{"/boot/vmlinuz"}})
+	assert.Nil(t, buildReport.SecureBoot)
+
+	kernelDefinition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{
+				Kernel: image.Kernel{Name: "kernel-default-base", Version: "6.4.0-150600.23.25.1"},
+			},
+		},
+	}
+	buildReport = NewReport(&image.Context{ImageDefinition: kernelDefinition, KernelRelease: "6.4.0-150600.23.25-default"})
+	assert.Equal(t, &report.Kernel{
+		Package: "kernel-default-base",
+		Version: "6.4.0-150600.23.25.1",
+		Release: "6.4.0-150600.23.25-default",
+	}, buildReport.Kernel)
+
+	stripDocsDefinition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			StripDocs: image.StripDocs{Enabled: true},
+		},
+	}
+	buildReport = NewReport(&image.Context{ImageDefinition: stripDocsDefinition, StrippedDocsKB: 48213})
+	assert.Equal(t, &report.StripDocs{ReclaimedKB: 48213}, buildReport.StripDocs)
+
+	assert.Nil(t, NewReport(&image.Context{ImageDefinition: definition}).Qcow2)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, Qcow2VirtualSize: 34359738368, Qcow2ActualSize: 1693188096})
+	assert.Equal(t, &report.Qcow2{VirtualSize: 34359738368, ActualSize: 1693188096}, buildReport.Qcow2)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, CombustionISO: "/eib/edge-combustion.iso"})
+	assert.Equal(t, "/eib/edge-combustion.iso", buildReport.CombustionISO)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, SeedISOs: []image.SeedISOFile{
+		{Hostname: "node1", Path: "/eib/edge-node1-seed.iso"},
+	}})
+	assert.Equal(t, []report.SeedISO{{Hostname: "node1", Path: "/eib/edge-node1-seed.iso"}}, buildReport.SeedISOs)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, ImageArchives: []image.ImageArchive{
+		{Image: "nginx:1.25", Path: "/opt/images/nginx_1.25.tar", Size: 1024},
+	}})
+	assert.Equal(t, []report.ImageArchive{
+		{Image: "nginx:1.25", Path: "/opt/images/nginx_1.25.tar", Size: 1024},
+	}, buildReport.ImageArchives)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, Certificates: []image.CertificateFile{
+		{File: "chain.pem", Subjects: []string{"CN=Root CA", "CN=Intermediate CA"}},
+	}})
+	assert.Equal(t, []report.Certificate{
+		{File: "chain.pem", Subjects: []string{"CN=Root CA", "CN=Intermediate CA"}},
+	}, buildReport.Certificates)
+	assert.Nil(t, buildReport.ReadOnlyRoot)
+	assert.Nil(t, buildReport.HelmChartCRDs)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: definition, HelmChartCRDs: []image.HelmChartCRDs{
+		{Chart: "cert-manager", Manifest: "00-crds-cert-manager.yaml", Names: []string{"certificates.cert-manager.io"}},
+	}})
+	assert.Equal(t, []report.HelmChartCRDs{
+		{Chart: "cert-manager", Manifest: "00-crds-cert-manager.yaml", CRDs: []string{"certificates.cert-manager.io"}},
+	}, buildReport.HelmChartCRDs)
+
+	readOnlyDefinition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			ReadOnlyRoot: image.ReadOnlyRoot{Enabled: true},
+		},
+	}
+	buildReport = NewReport(&image.Context{ImageDefinition: readOnlyDefinition})
+	assert.Equal(t, &report.ReadOnlyRoot{Overlays: []report.Overlay{}}, buildReport.ReadOnlyRoot)
+
+	buildReport = NewReport(&image.Context{ImageDefinition: readOnlyDefinition, Overlays: []image.OverlayMount{
+		{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs, Size: "256m"},
+		{Path: "/usr/lib/app/data", Type: image.OverlayTypePersistent, UpperDir: "/var/lib/eib-overlays/usr-lib-app-data/upper"},
+	}})
+	assert.Equal(t, &report.ReadOnlyRoot{Overlays: []report.Overlay{
+		{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs, Size: "256m"},
+		{Path: "/usr/lib/app/data", Type: image.OverlayTypePersistent, UpperDir: "/var/lib/eib-overlays/usr-lib-app-data/upper"},
+	}}, buildReport.ReadOnlyRoot)
+	assert.Nil(t, buildReport.Packages)
+
+	packagesDefinition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{PKGList: []string{"nvidia-open"}},
+		},
+	}
+	buildReport = NewReport(&image.Context{ImageDefinition: packagesDefinition, ResolvedPackages: []image.ResolvedPackage{
+		{File: "nvidia-open-550.54.14-1.x86_64.rpm", Size: 2048},
+		{File: "kernel-firmware-nvidia-20240201-1.noarch.rpm", Size: 1024},
+	}})
+	assert.Equal(t, &report.Packages{
+		Requested: []string{"nvidia-open"},
+		Resolved: []report.ResolvedPackage{
+			{File: "nvidia-open-550.54.14-1.x86_64.rpm", Size: 2048},
+			{File: "kernel-firmware-nvidia-20240201-1.noarch.rpm", Size: 1024},
+		},
+		Count: 2,
+		Size:  3072,
+	}, buildReport.Packages)
+}
+
+func TestNewReportDownloads(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	downloads := newReportDownloads([]http.DownloadRecord{
+		{
+			Artifact: "install.sh",
+			Source:   "https://get.rke2.io",
+			Bytes:    512,
+			Started:  started,
+			Finished: started.Add(1500 * time.Millisecond),
+		},
+	})
+
+	assert.Equal(t, []report.Download{
+		{
+			Artifact: "install.sh",
+			Source:   "https://get.rke2.io",
+			Bytes:    512,
+			Started:  "2024-01-01T00:00:00Z",
+			Finished: "2024-01-01T00:00:01.5Z",
+			Seconds:  1.5,
+		},
+	}, downloads)
+
+	assert.Empty(t, newReportDownloads(nil))
+}
@@ -1,11 +1,14 @@
 package eib
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/suse-edge/edge-image-builder/pkg/build"
@@ -17,45 +20,200 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/network"
+	"github.com/suse-edge/edge-image-builder/pkg/oci"
 	"github.com/suse-edge/edge-image-builder/pkg/podman"
 	"github.com/suse-edge/edge-image-builder/pkg/rpm"
 	"github.com/suse-edge/edge-image-builder/pkg/rpm/resolver"
 	"go.uber.org/zap"
 )
 
-func Run(ctx *image.Context, rootBuildDir string) error {
+// Run builds the image described by the build context, running only the given phases (all of them if empty).
+// Cancelling the context aborts the downloads in progress and stops the build before its next phase.
+func Run(ctx context.Context, buildCtx *image.Context, rootBuildDir string, phases Phases) error {
+	hash, err := image.DefinitionHash(buildCtx.ImageDefinition)
+	if err != nil {
+		return fmt.Errorf("hashing image definition: %w", err)
+	}
+	buildCtx.DefinitionHash = hash
+
+	log.AuditInfof("Running the build phases: %s.", phases)
+
+	if err = runPreflight(func() error {
+		return preflight(buildCtx, rootBuildDir, phases)
+	}); err != nil {
+		return err
+	}
+
+	if release := image.BaseImageRelease(buildCtx.BaseImagePath()); release != "" && buildCtx.ImageDefinition.Kubernetes.Version != "" {
+		log.AuditInfof("Installing Kubernetes %s on a %s base image.", buildCtx.ImageDefinition.Kubernetes.Version, release)
+	}
+
+	c := &combustion.Combustion{
+		NetworkConfigGenerator:       network.ConfigGenerator{},
+		NetworkConfiguratorInstaller: network.ConfiguratorInstaller{},
+	}
+	err = runPhase(ctx, phases, PhaseDownload, func() error {
+		if bootstrapErr := bootstrapDownloads(buildCtx, rootBuildDir, c); bootstrapErr != nil {
+			log.Audit("Bootstrapping dependency services failed.")
+			return fmt.Errorf("building combustion: %w", bootstrapErr)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	builder := build.NewBuilder(buildCtx, c)
+
+	err = runPhase(ctx, phases, PhaseCombustion, func() error {
+		if !phases.Enabled(PhaseDownload) {
+			if bootstrapErr := bootstrapCachedArtefacts(buildCtx, rootBuildDir, c); bootstrapErr != nil {
+				return fmt.Errorf("reusing cached artefacts: %w", bootstrapErr)
+			}
+		}
+
+		if configureErr := builder.Configure(); configureErr != nil {
+			return configureErr
+		}
+
+		if buildCtx.CombustionOnly {
+			if isoErr := builder.BuildCombustionISO(); isoErr != nil {
+				return isoErr
+			}
+
+			return builder.BuildSeedISOs()
+		}
+
+		return nil
+	})
+	if err != nil || buildCtx.CombustionOnly {
+		return err
+	}
+
+	if !phases.Enabled(PhaseAssembly) {
+		log.AuditInfof("Skipping the image assembly, the combustion content is available under '%s'.", buildCtx.CombustionDir)
+		return nil
+	}
+
+	return runPhase(ctx, phases, PhaseAssembly, func() error {
+		if assembleErr := builder.Assemble(); assembleErr != nil {
+			return assembleErr
+		}
+
+		return builder.BuildSeedISOs()
+	})
+}
+
+// preflight checks the free disk space and the tools required by the build before any phase runs. It first
+// determines the additional packages and Helm charts to download, as these may require more tools. Without the
+// download phase, the build fails if any component requires artefacts which are not cached rather than leaving it
+// out of the image, before anything is downloaded.
+func preflight(ctx *image.Context, rootBuildDir string, phases Phases) error {
+	if !ctx.SkipSpaceCheck {
+		if err := checkFreeSpace(ctx, rootBuildDir, phases); err != nil {
+			return err
+		}
+	}
+
 	if err := appendKubernetesSELinuxRPMs(ctx); err != nil {
-		log.Auditf("Bootstrapping dependency services failed.")
 		return fmt.Errorf("configuring kubernetes selinux policy: %w", err)
 	}
 
 	appendElementalRPMs(ctx)
+	appendGPUDriverRPMs(ctx)
+	appendSectionRPMs(ctx)
 	appendHelm(ctx)
 
-	c, err := buildCombustion(ctx, rootBuildDir)
+	if !phases.Enabled(PhaseDownload) {
+		if err := checkSkippedDownloads(ctx, rootBuildDir); err != nil {
+			return err
+		}
+	}
+
+	return checkRequiredTools(ctx, phases)
+}
+
+// checkSkippedDownloads returns a SkippedDownloadError listing the configured components which require the
+// download phase. Kubernetes artefacts are reused from the cache of earlier builds, so the Kubernetes component
+// only requires the download phase if any of its artefacts is not cached.
+func checkSkippedDownloads(ctx *image.Context, rootBuildDir string) error {
+	var components, uncached []string
+
+	for _, component := range combustion.DownloadComponents(ctx) {
+		if component == combustion.K8sComponentName {
+			identifiers, err := uncachedKubernetesArtefacts(ctx, rootBuildDir)
+			if err != nil {
+				return fmt.Errorf("looking up cached kubernetes artefacts: %w", err)
+			}
+
+			if len(identifiers) == 0 {
+				log.AuditInfo("The Kubernetes artefacts are cached and will be reused without the download phase.")
+				continue
+			}
+
+			uncached = identifiers
+		}
+
+		components = append(components, component)
+	}
+
+	if len(components) == 0 {
+		return nil
+	}
+
+	return &SkippedDownloadError{Components: components, Uncached: uncached}
+}
+
+// uncachedKubernetesArtefacts returns the cache identifiers of the Kubernetes artefacts of the build, including the
+// install script, which are missing from the cache under the root build directory.
+func uncachedKubernetesArtefacts(ctx *image.Context, rootBuildDir string) ([]string, error) {
+	identifiers, err := CachedArtefacts(&WarmTarget{
+		Name:       "the image definition",
+		ConfigDir:  ctx.ImageConfigDir,
+		Definition: ctx.ImageDefinition,
+	})
 	if err != nil {
-		log.Audit("Bootstrapping dependency services failed.")
-		return fmt.Errorf("building combustion: %w", err)
+		return nil, err
+	}
+
+	distribution := image.KubernetesDistroK3S
+	if strings.Contains(ctx.ImageDefinition.Kubernetes.Version, image.KubernetesDistroRKE2) {
+		distribution = image.KubernetesDistroRKE2
 	}
+	identifiers = append(identifiers, kubernetes.InstallScriptIdentifier(distribution))
 
-	builder := build.NewBuilder(ctx, c)
-	return builder.Build()
+	c, err := cache.Open(rootBuildDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return identifiers, nil
+		}
+
+		return nil, fmt.Errorf("opening cache: %w", err)
+	}
+
+	return c.Uncached(identifiers)
 }
 
-func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
+// kubernetesSELinuxEnabled reports whether SELinux is enabled in the Kubernetes configuration.
+func kubernetesSELinuxEnabled(ctx *image.Context) (bool, error) {
 	if ctx.ImageDefinition.Kubernetes.Version == "" {
-		return nil
+		return false, nil
 	}
 
-	configPath := combustion.KubernetesConfigPath(ctx)
-	config, err := kubernetes.ParseKubernetesConfig(configPath)
+	config, err := kubernetes.ParseKubernetesConfig(combustion.KubernetesConfigPath(ctx))
 	if err != nil {
-		return fmt.Errorf("parsing kubernetes server config: %w", err)
+		return false, fmt.Errorf("parsing kubernetes server config: %w", err)
 	}
 
 	selinuxEnabled, _ := config["selinux"].(bool)
-	if !selinuxEnabled {
-		return nil
+	return selinuxEnabled, nil
+}
+
+func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
+	selinuxEnabled, err := kubernetesSELinuxEnabled(ctx)
+	if err != nil || !selinuxEnabled {
+		return err
 	}
 
 	log.AuditInfo("SELinux is enabled in the Kubernetes configuration. " +
@@ -73,16 +231,22 @@ func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
 
 	appendRPMs(ctx, repository, selinuxPackage)
 
+	return nil
+}
+
+// downloadKubernetesSELinuxSigningKey downloads the key the Kubernetes SELinux packages are signed with.
+func downloadKubernetesSELinuxSigningKey(ctx *image.Context) error {
+	selinuxEnabled, err := kubernetesSELinuxEnabled(ctx)
+	if err != nil || !selinuxEnabled {
+		return err
+	}
+
 	gpgKeysDir := combustion.GPGKeysPath(ctx)
 	if err = os.MkdirAll(gpgKeysDir, os.ModePerm); err != nil {
 		return fmt.Errorf("creating directory '%s': %w", gpgKeysDir, err)
 	}
 
-	if err = kubernetes.DownloadSELinuxRPMsSigningKey(gpgKeysDir); err != nil {
-		return fmt.Errorf("downloading signing key: %w", err)
-	}
-
-	return nil
+	return kubernetes.DownloadSELinuxRPMsSigningKey(ctx.Context(), gpgKeysDir)
 }
 
 func appendElementalRPMs(ctx *image.Context) {
@@ -100,6 +264,63 @@ func appendElementalRPMs(ctx *image.Context) {
 	appendRPMs(ctx, image.AddRepo{URL: env.ElementalPackageRepository}, combustion.ElementalPackages...)
 }
 
+// appendGPUDriverRPMs installs the GPU driver packages along with the other packages, adding their repository
+// unless it is already configured.
+func appendGPUDriverRPMs(ctx *image.Context) {
+	drivers := &ctx.ImageDefinition.OperatingSystem.GPUDrivers
+	if len(drivers.Packages) == 0 {
+		return
+	}
+
+	if drivers.Version != "" {
+		log.AuditInfof("GPU driver %s is configured. The necessary RPM packages will be downloaded.", drivers.Version)
+	} else {
+		log.AuditInfo("A GPU driver is configured. The necessary RPM packages will be downloaded.")
+	}
+
+	packages := &ctx.ImageDefinition.OperatingSystem.Packages
+	repositoryConfigured := slices.ContainsFunc(packages.AdditionalRepos, func(repo image.AddRepo) bool {
+		return repo.URL == drivers.Repository.URL
+	})
+
+	if drivers.Repository.URL == "" || repositoryConfigured {
+		packages.PKGList = append(packages.PKGList, drivers.Packages...)
+		return
+	}
+
+	appendRPMs(ctx, drivers.Repository, drivers.Packages...)
+}
+
+// appendSectionRPMs installs the packages required by the configured sections of the definition along with
+// the other packages, unless they are already part of the package list.
+func appendSectionRPMs(ctx *image.Context) {
+	operatingSystem := &ctx.ImageDefinition.OperatingSystem
+	def := ctx.ImageDefinition
+
+	sections := []struct {
+		name       string
+		pkg        string
+		configured bool
+	}{
+		{name: "fail2ban", pkg: combustion.Fail2banPackage, configured: len(operatingSystem.Fail2ban.Jails) != 0},
+		{name: "dnsmasq", pkg: combustion.DnsmasqPackage, configured: combustion.IsDnsmasqConfigured(&operatingSystem.Dnsmasq)},
+		{name: "kdump", pkg: combustion.KdumpPackage, configured: operatingSystem.Kdump.Memory != ""},
+		{name: "zram", pkg: combustion.ZramGeneratorPackage, configured: combustion.IsZramConfigured(&operatingSystem.Zram)},
+		{name: "seedISOs", pkg: build.CloudInitPackage, configured: len(def.Image.SeedISOs) != 0},
+	}
+
+	packages := &operatingSystem.Packages
+	for _, section := range sections {
+		if !section.configured || slices.Contains(packages.PKGList, section.pkg) {
+			continue
+		}
+
+		log.AuditInfof("%s is configured. The necessary RPM packages will be downloaded.", section.name)
+
+		packages.PKGList = append(packages.PKGList, section.pkg)
+	}
+}
+
 func appendRPMs(ctx *image.Context, repository image.AddRepo, packages ...string) {
 	repositories := ctx.ImageDefinition.OperatingSystem.Packages.AdditionalRepos
 	repositories = append(repositories, repository)
@@ -118,21 +339,22 @@ func appendHelm(ctx *image.Context) {
 	ctx.ImageDefinition.Kubernetes.Helm.Repositories = append(ctx.ImageDefinition.Kubernetes.Helm.Repositories, componentRepos...)
 }
 
-func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion, error) {
-	combustionHandler := &combustion.Combustion{
-		NetworkConfigGenerator:       network.ConfigGenerator{},
-		NetworkConfiguratorInstaller: network.ConfiguratorInstaller{},
+// bootstrapDownloads sets up the services of the combustion handler downloading the artefacts, which are
+// downloaded while generating the combustion content.
+func bootstrapDownloads(ctx *image.Context, rootDir string, combustionHandler *combustion.Combustion) error {
+	if err := downloadKubernetesSELinuxSigningKey(ctx); err != nil {
+		return fmt.Errorf("downloading kubernetes selinux signing key: %w", err)
 	}
 
 	if !combustion.SkipRPMComponent(ctx) {
-		p, err := podman.New(ctx.BuildDir)
+		p, err := podman.New(ctx.Context(), ctx.BuildDir)
 		if err != nil {
-			return nil, fmt.Errorf("setting up Podman instance: %w", err)
+			return fmt.Errorf("setting up Podman instance: %w", err)
 		}
 
-		imgPath := filepath.Join(ctx.ImageConfigDir, "base-images", ctx.ImageDefinition.Image.BaseImage)
+		imgPath := ctx.BaseImagePath()
 		imgType := ctx.ImageDefinition.Image.ImageType
-		baseBuilder := resolver.NewTarballBuilder(ctx.BuildDir, imgPath, imgType, p)
+		baseBuilder := resolver.NewTarballBuilder(ctx.BuildDir, imgPath, imgType, build.ToolArgEnv(ctx.ToolArgs), p)
 
 		combustionHandler.RPMResolver = resolver.New(ctx.BuildDir, p, baseBuilder, "")
 		combustionHandler.RPMRepoCreator = rpm.NewRepoCreator(ctx.BuildDir)
@@ -143,19 +365,50 @@ func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion
 		combustionHandler.HelmClient = helm.New(ctx.BuildDir, certsDir)
 	}
 
+	if len(ctx.ImageDefinition.OperatingSystem.Podman.Images) != 0 || len(ctx.ImageDefinition.OperatingSystem.ImageArchives.Images) != 0 {
+		combustionHandler.ContainerImageArchiver = oci.ImageArchiver{}
+	}
+
 	if ctx.ImageDefinition.Kubernetes.Version != "" {
 		c, err := cache.New(rootDir)
 		if err != nil {
-			return nil, fmt.Errorf("initialising cache instance: %w", err)
+			return fmt.Errorf("initialising cache instance: %w", err)
 		}
 
-		combustionHandler.KubernetesScriptDownloader = kubernetes.ScriptDownloader{}
+		combustionHandler.KubernetesScriptDownloader = kubernetes.ScriptDownloader{
+			Cache: c,
+		}
 		combustionHandler.KubernetesArtefactDownloader = kubernetes.ArtefactDownloader{
 			Cache: c,
 		}
 	}
 
-	return combustionHandler, nil
+	return nil
+}
+
+// bootstrapCachedArtefacts sets up the services of the combustion handler copying the cached artefacts without the
+// download phase. Only the Kubernetes artefacts are reused, the pre-flight checks having ensured that they are all
+// cached and that no other component requires downloads.
+func bootstrapCachedArtefacts(ctx *image.Context, rootDir string, combustionHandler *combustion.Combustion) error {
+	if ctx.ImageDefinition.Kubernetes.Version == "" {
+		return nil
+	}
+
+	c, err := cache.Open(rootDir)
+	if err != nil {
+		return fmt.Errorf("opening cache: %w", err)
+	}
+
+	combustionHandler.KubernetesScriptDownloader = kubernetes.ScriptDownloader{
+		Cache:   c,
+		Offline: true,
+	}
+	combustionHandler.KubernetesArtefactDownloader = kubernetes.ArtefactDownloader{
+		Cache:   c,
+		Offline: true,
+	}
+
+	return nil
 }
 
 func SetupBuildDirectory(rootDir string) (string, error) {
@@ -169,15 +422,20 @@ func SetupBuildDirectory(rootDir string) (string, error) {
 }
 
 func SetupCombustionDirectory(buildDir string) (combustionDir, artefactsDir string, err error) {
-	combustionDir = filepath.Join(buildDir, "combustion")
+	combustionDir, artefactsDir = CombustionDirectories(buildDir)
+
 	if err = os.MkdirAll(combustionDir, os.ModePerm); err != nil {
 		return "", "", fmt.Errorf("creating a combustion directory: %w", err)
 	}
 
-	artefactsDir = filepath.Join(buildDir, "artefacts")
 	if err = os.MkdirAll(artefactsDir, os.ModePerm); err != nil {
 		return "", "", fmt.Errorf("creating an artefacts directory: %w", err)
 	}
 
 	return combustionDir, artefactsDir, nil
 }
+
+// CombustionDirectories returns the paths to the combustion and artefacts directories of the given build directory.
+func CombustionDirectories(buildDir string) (combustionDir, artefactsDir string) {
+	return filepath.Join(buildDir, "combustion"), filepath.Join(buildDir, "artefacts")
+}
@@ -1,12 +1,19 @@
 package eib
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
 )
 
 func TestSetupBuildDirectory_EmptyRootDir(t *testing.T) {
@@ -55,3 +62,280 @@ func TestSetupBuildDir_NonEmptyRootDir(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendGPUDriverRPMs(t *testing.T) {
+	repository := image.AddRepo{URL: "https://download.nvidia.com/suse/sle15sp6/"}
+	driverPackages := []string{"nvidia-open-driver-G06-signed-kmp-default", "nvidia-compute-utils-G06"}
+
+	tests := map[string]struct {
+		Packages         image.Packages
+		Repository       image.AddRepo
+		ExpectedPackages image.Packages
+	}{
+		"Driver repository": {
+			Packages:   image.Packages{PKGList: []string{"vim"}},
+			Repository: repository,
+			ExpectedPackages: image.Packages{
+				PKGList:         append([]string{"vim"}, driverPackages...),
+				AdditionalRepos: []image.AddRepo{repository},
+			},
+		},
+		"Repository already configured": {
+			Packages:   image.Packages{AdditionalRepos: []image.AddRepo{repository}},
+			Repository: repository,
+			ExpectedPackages: image.Packages{
+				PKGList:         driverPackages,
+				AdditionalRepos: []image.AddRepo{repository},
+			},
+		},
+		"No repository": {
+			ExpectedPackages: image.Packages{PKGList: driverPackages},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageDefinition: &image.Definition{
+					OperatingSystem: image.OperatingSystem{
+						Packages: test.Packages,
+						GPUDrivers: image.GPUDrivers{
+							Repository: test.Repository,
+							Packages:   driverPackages,
+						},
+					},
+				},
+			}
+
+			appendGPUDriverRPMs(ctx)
+			assert.Equal(t, test.ExpectedPackages, ctx.ImageDefinition.OperatingSystem.Packages)
+		})
+	}
+}
+
+func TestAppendSectionRPMs(t *testing.T) {
+	fail2ban := image.Fail2ban{Jails: []image.Fail2banJail{{Name: "sshd"}}}
+	dnsmasq := image.Dnsmasq{Interfaces: []string{"eth1"}}
+	kdump := image.Kdump{Memory: "512M"}
+	zram := image.Zram{Size: "4G"}
+	seedISOs := []image.SeedISO{{Hostname: "node1"}}
+
+	tests := map[string]struct {
+		Fail2ban        image.Fail2ban
+		Dnsmasq         image.Dnsmasq
+		Kdump           image.Kdump
+		Zram            image.Zram
+		SeedISOs        []image.SeedISO
+		PKGList         []string
+		ExpectedPKGList []string
+	}{
+		"Not configured": {
+			PKGList:         []string{"vim"},
+			ExpectedPKGList: []string{"vim"},
+		},
+		"Sections configured": {
+			Fail2ban:        fail2ban,
+			Dnsmasq:         dnsmasq,
+			Kdump:           kdump,
+			Zram:            zram,
+			SeedISOs:        seedISOs,
+			PKGList:         []string{"vim"},
+			ExpectedPKGList: []string{"vim", "fail2ban", "dnsmasq", "kdump", "zram-generator", "cloud-init"},
+		},
+		"Already listed": {
+			Fail2ban:        fail2ban,
+			Dnsmasq:         dnsmasq,
+			Kdump:           kdump,
+			Zram:            zram,
+			SeedISOs:        seedISOs,
+			PKGList:         []string{"cloud-init", "dnsmasq", "fail2ban", "kdump", "zram-generator"},
+			ExpectedPKGList: []string{"cloud-init", "dnsmasq", "fail2ban", "kdump", "zram-generator"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageDefinition: &image.Definition{
+					Image: image.Image{SeedISOs: test.SeedISOs},
+					OperatingSystem: image.OperatingSystem{
+						Packages: image.Packages{PKGList: test.PKGList},
+						Fail2ban: test.Fail2ban,
+						Dnsmasq:  test.Dnsmasq,
+						Kdump:    test.Kdump,
+						Zram:     test.Zram,
+					},
+				},
+			}
+
+			appendSectionRPMs(ctx)
+			assert.Equal(t, test.ExpectedPKGList, ctx.ImageDefinition.OperatingSystem.Packages.PKGList)
+		})
+	}
+}
+
+func TestRun_SkippedDownloadPreflightError(t *testing.T) {
+	defer func(original func(string) (string, error)) {
+		lookPath = original
+	}(lookPath)
+
+	lookPath = func(string) (string, error) {
+		return "", errors.New("executable file not found in $PATH")
+	}
+
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		BuildDir:       t.TempDir(),
+		SkipSpaceCheck: true,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{ImageType: image.TypeRAW, OutputImageName: "eib.raw"},
+		},
+	}
+
+	err := Run(context.Background(), ctx, t.TempDir(), Phases{PhaseCombustion, PhaseAssembly})
+
+	// The missing tools are reported by the pre-flight checks rather than by the skipped download phase
+	var phaseErr *PhaseError
+	require.ErrorAs(t, err, &phaseErr)
+	assert.Equal(t, PhasePreflight, phaseErr.Phase)
+
+	var toolsErr *MissingToolsError
+	require.ErrorAs(t, err, &toolsErr)
+	assert.Equal(t, []string{"guestfish"}, toolNames(toolsErr.Tools))
+}
+
+func TestRun_SkippedDownloadConfiguredComponents(t *testing.T) {
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		BuildDir:       t.TempDir(),
+		SkipSpaceCheck: true,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{ImageType: image.TypeRAW, OutputImageName: "eib.raw"},
+			OperatingSystem: image.OperatingSystem{
+				Packages: image.Packages{PKGList: []string{"vim"}},
+				Podman:   image.Podman{Images: []image.ContainerImage{{Name: "nginx:1.25"}}},
+			},
+		},
+	}
+
+	err := Run(context.Background(), ctx, t.TempDir(), Phases{PhaseCombustion, PhaseAssembly})
+
+	// Components requiring downloads fail the build instead of being left out of the image
+	var phaseErr *PhaseError
+	require.ErrorAs(t, err, &phaseErr)
+	assert.Equal(t, PhasePreflight, phaseErr.Phase)
+
+	var downloadErr *SkippedDownloadError
+	require.ErrorAs(t, err, &downloadErr)
+	assert.Equal(t, []string{"RPM", "podman images"}, downloadErr.Components)
+	assert.EqualError(t, downloadErr, "the download phase is required by the configured components: RPM, podman images")
+}
+
+func TestRun_SkippedDownloadSELinux(t *testing.T) {
+	configDir := t.TempDir()
+	k8sConfigDir := filepath.Join(configDir, "kubernetes", "config")
+	require.NoError(t, os.MkdirAll(k8sConfigDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(k8sConfigDir, "server.yaml"), []byte("selinux: true\n"), 0o600))
+
+	ctx := &image.Context{
+		ImageConfigDir: configDir,
+		BuildDir:       t.TempDir(),
+		SkipSpaceCheck: true,
+		ImageDefinition: &image.Definition{
+			Image:      image.Image{ImageType: image.TypeRAW, Arch: image.ArchTypeX86, OutputImageName: "eib.raw"},
+			Kubernetes: image.Kubernetes{Version: "v1.30.3+k3s1"},
+		},
+	}
+
+	err := Run(context.Background(), ctx, t.TempDir(), Phases{PhaseCombustion, PhaseAssembly})
+
+	// The SELinux packages require the download phase, whose signing key is not downloaded by the pre-flight checks
+	var downloadErr *SkippedDownloadError
+	require.ErrorAs(t, err, &downloadErr)
+	assert.Equal(t, []string{"RPM", "kubernetes"}, downloadErr.Components)
+	assert.NoDirExists(t, combustion.GPGKeysPath(ctx))
+}
+
+func TestCheckSkippedDownloads_Kubernetes(t *testing.T) {
+	newContext := func() *image.Context {
+		return &image.Context{
+			ImageConfigDir: t.TempDir(),
+			ImageDefinition: &image.Definition{
+				Image:      image.Image{Arch: image.ArchTypeX86},
+				Kubernetes: image.Kubernetes{Version: "v1.30.3+k3s1"},
+			},
+		}
+	}
+
+	identifiers := []string{
+		"v1.30.3+k3s1/k3s-airgap-images-amd64.tar.zst",
+		"v1.30.3+k3s1/k3s",
+		"install-scripts/k3s_installer.sh",
+	}
+
+	rootBuildDir := t.TempDir()
+
+	// Without a cache, all artefacts are missing
+	err := checkSkippedDownloads(newContext(), rootBuildDir)
+
+	var downloadErr *SkippedDownloadError
+	require.ErrorAs(t, err, &downloadErr)
+	assert.Equal(t, []string{"kubernetes"}, downloadErr.Components)
+	assert.Equal(t, identifiers, downloadErr.Uncached)
+	assert.EqualError(t, err, "the download phase is required by the configured components: kubernetes "+
+		"(uncached Kubernetes artefacts: v1.30.3+k3s1/k3s-airgap-images-amd64.tar.zst, v1.30.3+k3s1/k3s, install-scripts/k3s_installer.sh)")
+
+	c, err := cache.New(rootBuildDir)
+	require.NoError(t, err)
+	require.NoError(t, c.Put(identifiers[0], strings.NewReader("images")))
+
+	// Only the artefacts missing from the cache are reported
+	err = checkSkippedDownloads(newContext(), rootBuildDir)
+	require.ErrorAs(t, err, &downloadErr)
+	assert.Equal(t, identifiers[1:], downloadErr.Uncached)
+
+	require.NoError(t, c.Put(identifiers[1], strings.NewReader("binary")))
+	require.NoError(t, c.Put(identifiers[2], strings.NewReader("script")))
+
+	// The cached artefacts are reused without the download phase
+	require.NoError(t, checkSkippedDownloads(newContext(), rootBuildDir))
+
+	// Components which are not cached still require the download phase
+	ctx := newContext()
+	ctx.ImageDefinition.OperatingSystem.Podman.Images = []image.ContainerImage{{Name: "nginx:1.25"}}
+
+	err = checkSkippedDownloads(ctx, rootBuildDir)
+	require.ErrorAs(t, err, &downloadErr)
+	assert.Equal(t, []string{"podman images"}, downloadErr.Components)
+	assert.Empty(t, downloadErr.Uncached)
+}
+
+func TestBootstrapCachedArtefacts(t *testing.T) {
+	rootBuildDir := t.TempDir()
+
+	_, err := cache.New(rootBuildDir)
+	require.NoError(t, err)
+
+	ctx := &image.Context{
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{Version: "v1.30.3+k3s1"},
+		},
+	}
+
+	c := &combustion.Combustion{}
+	require.NoError(t, bootstrapCachedArtefacts(ctx, rootBuildDir, c))
+
+	scriptDownloader, ok := c.KubernetesScriptDownloader.(kubernetes.ScriptDownloader)
+	require.True(t, ok)
+	assert.True(t, scriptDownloader.Offline)
+
+	artefactDownloader, ok := c.KubernetesArtefactDownloader.(kubernetes.ArtefactDownloader)
+	require.True(t, ok)
+	assert.True(t, artefactDownloader.Offline)
+
+	// Nothing is set up without Kubernetes
+	c = &combustion.Combustion{}
+	ctx.ImageDefinition.Kubernetes.Version = ""
+	require.NoError(t, bootstrapCachedArtefacts(ctx, rootBuildDir, c))
+	assert.Nil(t, c.KubernetesArtefactDownloader)
+}
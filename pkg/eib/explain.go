@@ -0,0 +1,472 @@
+package eib
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/build"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// Explanation walks through what a build does for a given definition, as displayed with the --explain flag.
+type Explanation struct {
+	Phases []PhaseExplanation
+	// Tools are the executables the build invokes, which are checked for before it starts.
+	Tools []RequiredTool
+	// RequiredSpace is the formatted disk space the build is estimated to require, see estimateRequiredSpace.
+	RequiredSpace string
+}
+
+// PhaseExplanation describes the steps of a build phase tailored to the definition, the steps of disabled
+// phases being omitted.
+type PhaseExplanation struct {
+	Phase   Phase
+	Summary string
+	Enabled bool
+	Steps   []string
+}
+
+// explainedStep is a step of a phase, only explained when the definition configures it.
+type explainedStep struct {
+	set         bool
+	description string
+}
+
+var phaseSummaries = map[Phase]string{
+	PhaseDownload:   "Bootstraps the services downloading the artefacts, which are downloaded while generating the combustion content",
+	PhaseCombustion: "Generates the combustion content, whose scripts configure the node when it boots for the first time",
+	PhaseAssembly:   "Assembles the image from the base image and the combustion content",
+}
+
+// Explain describes what each of the given phases does for the definition of the context, without running them
+// or downloading anything. The phases are all enabled if empty.
+func Explain(ctx *image.Context, rootBuildDir string, phases Phases) (*Explanation, error) {
+	buildSpace, outputSpace, err := estimateRequiredSpace(ctx, rootBuildDir, phases)
+	if err != nil {
+		return nil, fmt.Errorf("estimating required disk space: %w", err)
+	}
+
+	explanation := &Explanation{
+		Tools:         requiredTools(ctx, phases),
+		RequiredSpace: formatSpace(buildSpace + outputSpace),
+	}
+
+	for _, phase := range AllPhases {
+		phaseExplanation := PhaseExplanation{
+			Phase:   phase,
+			Summary: phaseSummaries[phase],
+			Enabled: phases.Enabled(phase),
+		}
+
+		if phaseExplanation.Enabled {
+			switch phase {
+			case PhaseDownload:
+				phaseExplanation.Steps = explainDownload(ctx)
+			case PhaseCombustion:
+				phaseExplanation.Steps = explainCombustion(ctx)
+			case PhaseAssembly:
+				phaseExplanation.Steps = explainAssembly(ctx)
+			}
+		}
+
+		explanation.Phases = append(explanation.Phases, phaseExplanation)
+	}
+
+	return explanation, nil
+}
+
+func explainDownload(ctx *image.Context) []string {
+	def := ctx.ImageDefinition
+
+	var charts []string
+	componentCharts, _ := combustion.ComponentHelmCharts(ctx)
+	for _, chart := range append(def.Kubernetes.Helm.Charts, componentCharts...) {
+		charts = append(charts, chart.Name)
+	}
+
+	steps := []explainedStep{
+		{
+			set:         ResolvesRPMs(ctx),
+			description: explainRPMResolution(ctx),
+		},
+		{
+			set: def.Kubernetes.Version != "",
+			description: fmt.Sprintf("Download the %s installer and artefacts of Kubernetes %s for %s, which are cached "+
+				"under the build directory for later builds.", kubernetesDistribution(def.Kubernetes.Version), def.Kubernetes.Version, def.Image.Arch),
+		},
+		{
+			set:         len(def.Kubernetes.Manifests.URLs) != 0,
+			description: fmt.Sprintf("Download %d Kubernetes manifest(s) to apply once the cluster is running.", len(def.Kubernetes.Manifests.URLs)),
+		},
+		{
+			set: len(charts) != 0,
+			description: fmt.Sprintf("Pull and template %d Helm chart(s) (%s) to find the container images they reference.",
+				len(charts), strings.Join(charts, ", ")),
+		},
+		{
+			set:         combustion.IsEmbeddedArtifactRegistryConfigured(ctx),
+			description: explainRegistryImages(len(def.EmbeddedArtifactRegistry.ContainerImages)),
+		},
+		{
+			set:         len(def.OperatingSystem.Podman.Images) != 0,
+			description: fmt.Sprintf("Pull %d container image(s) which are loaded into Podman on first boot.", len(def.OperatingSystem.Podman.Images)),
+		},
+		{
+			set: len(def.OperatingSystem.ImageArchives.Images) != 0,
+			description: fmt.Sprintf("Pull %d container image(s) which are placed as archives under '%s'.",
+				len(def.OperatingSystem.ImageArchives.Images), def.OperatingSystem.ImageArchives.Path),
+		},
+	}
+
+	return explainedSteps(steps, "Nothing is downloaded, the definition configures no packages, container images or Kubernetes.")
+}
+
+// ResolvesRPMs checks whether packages are resolved, including the packages the build adds to the definition
+// for the GPU drivers, the Elemental registration and the sections requiring packages. The resolution runs
+// libguestfs against the base image, in addition to the image assembly.
+func ResolvesRPMs(ctx *image.Context) bool {
+	if len(ctx.ImageDefinition.OperatingSystem.GPUDrivers.Packages) != 0 {
+		return true
+	}
+
+	if len(ctx.ImageDefinition.OperatingSystem.Fail2ban.Jails) != 0 || combustion.IsDnsmasqConfigured(&ctx.ImageDefinition.OperatingSystem.Dnsmasq) {
+		return true
+	}
+
+	if ctx.ImageDefinition.OperatingSystem.Kdump.Memory != "" || combustion.IsZramConfigured(&ctx.ImageDefinition.OperatingSystem.Zram) {
+		return true
+	}
+
+	if len(ctx.ImageDefinition.Image.SeedISOs) != 0 {
+		return true
+	}
+
+	if _, err := os.Stat(combustion.ElementalPath(ctx)); err == nil {
+		return true
+	}
+
+	return !combustion.SkipRPMComponent(ctx)
+}
+
+func explainRegistryImages(images int) string {
+	if images == 0 {
+		return "Pull the container images referenced by the Kubernetes manifests and Helm charts into the stores of " +
+			"the embedded artifact registry."
+	}
+
+	return fmt.Sprintf("Pull %d container image(s), along with the images referenced by the Kubernetes manifests "+
+		"and Helm charts, into the stores of the embedded artifact registry.", images)
+}
+
+// explainRPMResolution describes the packages resolved from the base image, including those the build adds on
+// top of the ones of the definition.
+func explainRPMResolution(ctx *image.Context) string {
+	packages := &ctx.ImageDefinition.OperatingSystem.Packages
+
+	var sources []string
+	if len(packages.PKGList) != 0 {
+		sources = append(sources, fmt.Sprintf("%d package(s) of the package list", len(packages.PKGList)))
+	}
+
+	if drivers := ctx.ImageDefinition.OperatingSystem.GPUDrivers; len(drivers.Packages) != 0 {
+		sources = append(sources, fmt.Sprintf("%d GPU driver package(s)", len(drivers.Packages)))
+	}
+
+	if fail2ban := ctx.ImageDefinition.OperatingSystem.Fail2ban; len(fail2ban.Jails) != 0 && !slices.Contains(packages.PKGList, combustion.Fail2banPackage) {
+		sources = append(sources, "the fail2ban package")
+	}
+
+	if combustion.IsDnsmasqConfigured(&ctx.ImageDefinition.OperatingSystem.Dnsmasq) && !slices.Contains(packages.PKGList, combustion.DnsmasqPackage) {
+		sources = append(sources, "the dnsmasq package")
+	}
+
+	if ctx.ImageDefinition.OperatingSystem.Kdump.Memory != "" && !slices.Contains(packages.PKGList, combustion.KdumpPackage) {
+		sources = append(sources, "the kdump package")
+	}
+
+	if combustion.IsZramConfigured(&ctx.ImageDefinition.OperatingSystem.Zram) && !slices.Contains(packages.PKGList, combustion.ZramGeneratorPackage) {
+		sources = append(sources, "the zram-generator package")
+	}
+
+	if len(ctx.ImageDefinition.Image.SeedISOs) != 0 && !slices.Contains(packages.PKGList, build.CloudInitPackage) {
+		sources = append(sources, "the cloud-init package")
+	}
+
+	if packages.Kernel.Version != "" {
+		sources = append(sources, fmt.Sprintf("the pinned kernel %s-%s", packages.Kernel.Package(), packages.Kernel.Version))
+	}
+
+	if _, err := os.Stat(combustion.ElementalPath(ctx)); err == nil {
+		sources = append(sources, "the Elemental registration packages")
+	}
+
+	if _, err := os.Stat(combustion.RPMsPath(ctx)); err == nil {
+		sources = append(sources, "the side-loaded RPMs")
+	}
+
+	var repositories []string
+	if packages.RegCode != "" {
+		repositories = append(repositories, "the SUSE repositories")
+	}
+
+	if len(packages.AdditionalRepos) != 0 {
+		repositories = append(repositories, fmt.Sprintf("%d additional repositories", len(packages.AdditionalRepos)))
+	}
+
+	description := fmt.Sprintf("Resolve the dependencies of %s against the base image in a Podman container",
+		joinDescriptions(sources))
+	if len(repositories) != 0 {
+		description += fmt.Sprintf(" using %s", strings.Join(repositories, " and "))
+	}
+
+	return description + ", downloading them into an RPM repository embedded in the image."
+}
+
+func explainCombustion(ctx *image.Context) []string {
+	def := ctx.ImageDefinition
+	operatingSystem := &def.OperatingSystem
+
+	var users []string
+	for _, user := range operatingSystem.Users {
+		users = append(users, user.Username)
+	}
+
+	steps := []explainedStep{
+		{
+			set:         isConfigured(ctx, combustion.CustomDir),
+			description: "Run the custom scripts and copy the custom files of the 'custom' directory, ahead of the built-in configuration.",
+		},
+		{
+			set:         operatingSystem.Hostname != "",
+			description: fmt.Sprintf("Set the hostname to '%s'.", operatingSystem.Hostname),
+		},
+		{
+			set:         isConfigured(ctx, combustion.NetworkConfigDir),
+			description: "Apply the nmstate network configuration of the 'network' directory, generated with nmc.",
+		},
+		{
+			set: len(operatingSystem.NetworkProfiles.Profiles) != 0,
+			description: fmt.Sprintf("Apply the network profile (%s) selected through the '%s' kernel argument, "+
+				"defaulting to '%s', with nmc.", strings.Join(operatingSystem.NetworkProfiles.Profiles, ", "),
+				operatingSystem.NetworkProfiles.SelectionArg(), operatingSystem.NetworkProfiles.Default),
+		},
+		{
+			set:         isConfigured(ctx, combustion.NetworkdConfigDir),
+			description: "Install the systemd-networkd configuration of the 'networkd' directory.",
+		},
+		{
+			set:         combustion.IsDNSConfigured(&operatingSystem.DNS),
+			description: "Configure the DNS resolution of systemd-resolved.",
+		},
+		{
+			set:         combustion.IsDnsmasqConfigured(&operatingSystem.Dnsmasq),
+			description: fmt.Sprintf("Serve %s with dnsmasq.", combustion.DnsmasqDescription(&operatingSystem.Dnsmasq)),
+		},
+		{
+			set:         len(operatingSystem.Hosts) != 0,
+			description: fmt.Sprintf("Add %d static entries to /etc/hosts.", len(operatingSystem.Hosts)),
+		},
+		{
+			set:         len(operatingSystem.KnownHosts) != 0,
+			description: fmt.Sprintf("Pin %d SSH host key(s) in the system-wide known_hosts.", len(operatingSystem.KnownHosts)),
+		},
+		{
+			set:         len(operatingSystem.Groups) != 0,
+			description: fmt.Sprintf("Create %d group(s).", len(operatingSystem.Groups)),
+		},
+		{
+			set:         len(users) != 0,
+			description: fmt.Sprintf("Create or configure %d user(s): %s.", len(users), strings.Join(users, ", ")),
+		},
+		{
+			set:         operatingSystem.Autologin.User != "",
+			description: fmt.Sprintf("Log the user '%s' in on %s without authentication.", operatingSystem.Autologin.User, operatingSystem.Autologin.Console()),
+		},
+		{
+			set:         operatingSystem.Time.Timezone != "" || len(operatingSystem.Time.NtpConfiguration.Pools) != 0 || len(operatingSystem.Time.NtpConfiguration.Servers) != 0,
+			description: "Configure the timezone and the NTP time sources.",
+		},
+		{
+			set:         operatingSystem.Proxy.HTTPProxy != "" || operatingSystem.Proxy.HTTPSProxy != "",
+			description: "Configure the system-wide HTTP proxy.",
+		},
+		{
+			set:         ResolvesRPMs(ctx),
+			description: "Install the resolved packages from the embedded RPM repository.",
+		},
+		{
+			set:         len(operatingSystem.Packages.Locks) != 0 || len(operatingSystem.Packages.Exclude) != 0 || operatingSystem.Packages.Kernel.Version != "",
+			description: "Lock the packages held at their version and excluded from installation.",
+		},
+		{
+			set:         len(operatingSystem.Systemd.Enable) != 0 || len(operatingSystem.Systemd.Disable) != 0,
+			description: fmt.Sprintf("Enable %d and disable %d systemd unit(s).", len(operatingSystem.Systemd.Enable), len(operatingSystem.Systemd.Disable)),
+		},
+		{
+			set:         len(operatingSystem.Fail2ban.Jails) != 0,
+			description: fmt.Sprintf("Enable the fail2ban jail(s): %s.", strings.Join(combustion.Fail2banJails(&operatingSystem.Fail2ban), ", ")),
+		},
+		{
+			set:         operatingSystem.Suma.Host != "",
+			description: fmt.Sprintf("Register the node with the SUSE Manager server '%s'.", operatingSystem.Suma.Host),
+		},
+		{
+			set:         len(operatingSystem.ScheduledJobs) != 0,
+			description: fmt.Sprintf("Schedule %d job(s).", len(operatingSystem.ScheduledJobs)),
+		},
+		{
+			set:         len(operatingSystem.SecureBoot.MOKCertificates) != 0,
+			description: fmt.Sprintf("Request the enrollment of %d MOK certificate(s).", len(operatingSystem.SecureBoot.MOKCertificates)),
+		},
+		{
+			set:         operatingSystem.Kdump.Memory != "",
+			description: fmt.Sprintf("Enable kdump, saving the dumps to %s.", combustion.KdumpSavePath(&operatingSystem.Kdump)),
+		},
+		{
+			set:         operatingSystem.ReadOnlyRoot.Enabled,
+			description: fmt.Sprintf("Mount the root file system read-only with %d writable overlay(s).", len(operatingSystem.ReadOnlyRoot.Overlays)),
+		},
+		{
+			set:         combustion.IsEmbeddedArtifactRegistryConfigured(ctx),
+			description: "Serve the pulled container images and Helm charts from the embedded artifact registry.",
+		},
+		{
+			set: def.Kubernetes.Version != "",
+			description: fmt.Sprintf("Install Kubernetes %s, forming a cluster of %d node(s).",
+				def.Kubernetes.Version, max(len(def.Kubernetes.Nodes), 1)),
+		},
+		{
+			set:         ctx.CombustionOnly,
+			description: "Package the combustion content into an ISO labeled INSTALL, named after the output image, in the image configuration directory.",
+		},
+		{
+			set:         ctx.CombustionOnly && len(def.Image.SeedISOs) != 0,
+			description: explainSeedISOs(def.Image.SeedISOs),
+		},
+	}
+
+	// The message and release components always run, leaving the combustion content never empty
+	return explainedSteps(steps, "Only record the build in /etc/eib-release, the definition configures no other component.")
+}
+
+func explainAssembly(ctx *image.Context) []string {
+	if ctx.CombustionOnly {
+		return nil
+	}
+
+	def := ctx.ImageDefinition
+	operatingSystem := &def.OperatingSystem
+	signing := operatingSystem.SecureBoot.Signing
+
+	installDevice := operatingSystem.IsoConfiguration.InstallDevice
+	if installDevice == "" {
+		installDevice = "the disk selected when booting the installer"
+	}
+
+	steps := []explainedStep{
+		{
+			set:         def.Image.ImageType == image.TypeISO,
+			description: fmt.Sprintf("Extract the raw image from the base image '%s' and rebuild it with the combustion content into '%s', which installs onto %s.", def.Image.BaseImage, def.Image.OutputImageName, installDevice),
+		},
+		{
+			set:         def.Image.ImageType == image.TypeRAW,
+			description: fmt.Sprintf("Copy the base image '%s' to '%s'.", def.Image.BaseImage, def.Image.OutputImageName),
+		},
+		{
+			set:         operatingSystem.RawConfiguration.DiskSize != "",
+			description: fmt.Sprintf("Grow the disk to %s.", operatingSystem.RawConfiguration.DiskSize),
+		},
+		{
+			set:         operatingSystem.RawConfiguration.ABPartitions,
+			description: "Add a second root partition for A/B updates.",
+		},
+		{
+			set:         operatingSystem.Packages.Kernel.Version != "",
+			description: fmt.Sprintf("Replace the kernel of the base image with %s-%s.", operatingSystem.Packages.Kernel.Package(), operatingSystem.Packages.Kernel.Version),
+		},
+		{
+			set:         len(operatingSystem.KernelArgs.Add) != 0 || len(operatingSystem.KernelArgs.Remove) != 0,
+			description: fmt.Sprintf("Add %d and remove %d kernel argument(s).", len(operatingSystem.KernelArgs.Add), len(operatingSystem.KernelArgs.Remove)),
+		},
+		{
+			set:         operatingSystem.Kdump.Memory != "",
+			description: fmt.Sprintf("Reserve %s for the kdump crash kernel through the 'crashkernel' kernel argument.", operatingSystem.Kdump.Memory),
+		},
+		{
+			set:         len(operatingSystem.Remove) != 0,
+			description: fmt.Sprintf("Remove %d path(s) from the image.", len(operatingSystem.Remove)),
+		},
+		{
+			set:         operatingSystem.StripDocs.Enabled,
+			description: fmt.Sprintf("Strip the documentation, man and info pages, keeping %d excluded path(s).", len(operatingSystem.StripDocs.Exclude)),
+		},
+		{
+			set:         len(ctx.ToolArgs) != 0,
+			description: fmt.Sprintf("Forward %d unsupported tool argument(s) to the tooling modifying and converting the image.", len(ctx.ToolArgs)),
+		},
+		{
+			set:         signing.Kernel || signing.Modules,
+			description: "Sign the boot artefacts for Secure Boot on the build host, the signing key is not copied into the image.",
+		},
+		{
+			set:         def.Image.ImageType == image.TypeRAW,
+			description: "Copy the combustion content into the image, to be run when it boots for the first time.",
+		},
+		{
+			set:         def.Image.OutputFormat == image.OutputFormatQCOW2,
+			description: "Convert the image to the QCOW2 format.",
+		},
+		{
+			set:         len(def.Image.SeedISOs) != 0,
+			description: explainSeedISOs(def.Image.SeedISOs),
+		},
+	}
+
+	return explainedSteps(steps, "")
+}
+
+func explainSeedISOs(seeds []image.SeedISO) string {
+	hostnames := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		hostnames = append(hostnames, seed.Hostname)
+	}
+
+	return fmt.Sprintf("Build the cloud-init seed ISO(s) of the node(s) %s, labeled CIDATA, in the image configuration directory.",
+		strings.Join(hostnames, ", "))
+}
+
+// explainedSteps lists the descriptions of the configured steps, falling back to the given description otherwise.
+func explainedSteps(steps []explainedStep, fallback string) []string {
+	var descriptions []string
+	for _, step := range steps {
+		if step.set {
+			descriptions = append(descriptions, step.description)
+		}
+	}
+
+	if len(descriptions) == 0 && fallback != "" {
+		return []string{fallback}
+	}
+
+	return descriptions
+}
+
+// joinDescriptions joins the given descriptions into a sentence, the last one being joined with 'and'.
+func joinDescriptions(descriptions []string) string {
+	if len(descriptions) < 2 {
+		return strings.Join(descriptions, "")
+	}
+
+	return strings.Join(descriptions[:len(descriptions)-1], ", ") + " and " + descriptions[len(descriptions)-1]
+}
+
+func kubernetesDistribution(version string) string {
+	if strings.Contains(version, image.KubernetesDistroK3S) {
+		return "K3s"
+	}
+
+	return "RKE2"
+}
@@ -0,0 +1,136 @@
+package eib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestExplain(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "network"), os.ModePerm))
+
+	ctx := &image.Context{
+		ImageConfigDir: configDir,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       image.TypeRAW,
+				Arch:            image.ArchTypeX86,
+				BaseImage:       "sl-micro.raw",
+				OutputImageName: "edge.raw",
+			},
+			OperatingSystem: image.OperatingSystem{
+				Hostname: "edge",
+				Users:    []image.OperatingSystemUser{{Username: "alpha"}, {Username: "beta"}},
+				Packages: image.Packages{
+					PKGList:         []string{"wget2"},
+					Kernel:          image.Kernel{Version: "6.4.0-150600.23.25.1"},
+					AdditionalRepos: []image.AddRepo{{URL: "https://foo.bar"}},
+				},
+				RawConfiguration: image.RawConfiguration{DiskSize: "64G"},
+			},
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+				Helm: image.Helm{
+					Charts: []image.HelmChart{{Name: "apache"}},
+				},
+			},
+		},
+	}
+
+	explanation, err := Explain(ctx, filepath.Join(configDir, "_build"), nil)
+	require.NoError(t, err)
+	require.Len(t, explanation.Phases, 3)
+
+	download := explanation.Phases[0]
+	assert.Equal(t, PhaseDownload, download.Phase)
+	assert.True(t, download.Enabled)
+	assert.Equal(t, []string{
+		"Resolve the dependencies of 1 package(s) of the package list and the pinned kernel kernel-default-6.4.0-150600.23.25.1 " +
+			"against the base image in a Podman container using 1 additional repositories, downloading them into an RPM " +
+			"repository embedded in the image.",
+		"Download the RKE2 installer and artefacts of Kubernetes v1.30.3+rke2r1 for x86_64, which are cached under the " +
+			"build directory for later builds.",
+		"Pull and template 1 Helm chart(s) (apache) to find the container images they reference.",
+		"Pull the container images referenced by the Kubernetes manifests and Helm charts into the stores of the " +
+			"embedded artifact registry.",
+	}, download.Steps)
+
+	combustion := explanation.Phases[1]
+	assert.Equal(t, PhaseCombustion, combustion.Phase)
+	assert.Equal(t, []string{
+		"Set the hostname to 'edge'.",
+		"Apply the nmstate network configuration of the 'network' directory, generated with nmc.",
+		"Create or configure 2 user(s): alpha, beta.",
+		"Install the resolved packages from the embedded RPM repository.",
+		"Lock the packages held at their version and excluded from installation.",
+		"Serve the pulled container images and Helm charts from the embedded artifact registry.",
+		"Install Kubernetes v1.30.3+rke2r1, forming a cluster of 1 node(s).",
+	}, combustion.Steps)
+
+	assembly := explanation.Phases[2]
+	assert.Equal(t, PhaseAssembly, assembly.Phase)
+	assert.Equal(t, []string{
+		"Copy the base image 'sl-micro.raw' to 'edge.raw'.",
+		"Grow the disk to 64G.",
+		"Replace the kernel of the base image with kernel-default-6.4.0-150600.23.25.1.",
+		"Copy the combustion content into the image, to be run when it boots for the first time.",
+	}, assembly.Steps)
+
+	assert.Contains(t, toolNames(explanation.Tools), "virt-resize")
+	assert.NotEmpty(t, explanation.RequiredSpace)
+}
+
+func TestExplain_SelectedPhases(t *testing.T) {
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       image.TypeISO,
+				BaseImage:       "sl-micro.iso",
+				OutputImageName: "edge.iso",
+			},
+		},
+	}
+
+	explanation, err := Explain(ctx, filepath.Join(ctx.ImageConfigDir, "_build"), Phases{PhaseCombustion, PhaseAssembly})
+	require.NoError(t, err)
+	require.Len(t, explanation.Phases, 3)
+
+	assert.False(t, explanation.Phases[0].Enabled)
+	assert.Nil(t, explanation.Phases[0].Steps)
+
+	assert.Equal(t, []string{
+		"Only record the build in /etc/eib-release, the definition configures no other component.",
+	}, explanation.Phases[1].Steps)
+
+	assert.Equal(t, []string{
+		"Extract the raw image from the base image 'sl-micro.iso' and rebuild it with the combustion content into " +
+			"'edge.iso', which installs onto the disk selected when booting the installer.",
+	}, explanation.Phases[2].Steps)
+}
+
+func TestExplain_CombustionOnly(t *testing.T) {
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		CombustionOnly: true,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{ImageType: image.TypeRAW},
+		},
+	}
+
+	explanation, err := Explain(ctx, filepath.Join(ctx.ImageConfigDir, "_build"), Phases{PhaseDownload, PhaseCombustion})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Nothing is downloaded, the definition configures no packages, container images or Kubernetes.",
+	}, explanation.Phases[0].Steps)
+	assert.Equal(t, []string{
+		"Package the combustion content into an ISO labeled INSTALL, named after the output image, in the image configuration directory.",
+	}, explanation.Phases[1].Steps)
+	assert.False(t, explanation.Phases[2].Enabled)
+}
@@ -0,0 +1,177 @@
+package eib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+)
+
+// Phase is a named stage of the build which can be enabled or disabled for targeted builds.
+type Phase string
+
+const (
+	// PhaseDownload downloads the artefacts of the RPM, embedded registry, podman and Kubernetes components.
+	PhaseDownload Phase = "download"
+	// PhaseCombustion generates the combustion content under the build directory.
+	PhaseCombustion Phase = "combustion"
+	// PhaseAssembly assembles the image from the base image and the combustion content.
+	PhaseAssembly Phase = "assembly"
+
+	// PhasePreflight identifies the checks run before the enabled phases. It always runs and cannot be selected.
+	PhasePreflight Phase = "preflight"
+)
+
+// AllPhases lists every phase in the order in which they are run.
+var AllPhases = []Phase{PhaseDownload, PhaseCombustion, PhaseAssembly}
+
+// Phases is the set of phases enabled for a build.
+type Phases []Phase
+
+// Enabled reports whether the given phase is run. An empty set enables every phase.
+func (p Phases) Enabled(phase Phase) bool {
+	return len(p) == 0 || slices.Contains(p, phase)
+}
+
+// position returns the number of the phase among the enabled ones along with their total. The number is
+// zero if the phase is disabled.
+func (p Phases) position(phase Phase) (number, total int) {
+	phases := p
+	if len(phases) == 0 {
+		phases = AllPhases
+	}
+
+	return slices.Index(phases, phase) + 1, len(phases)
+}
+
+func (p Phases) String() string {
+	phases := p
+	if len(phases) == 0 {
+		phases = AllPhases
+	}
+
+	names := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		names = append(names, string(phase))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// SelectPhases returns the phases enabled by either running only the given phases or skipping them,
+// ordered as they are run. Every phase is enabled if neither is specified. The selection has to be
+// coherent, as both downloading artefacts and assembling the image depend on the combustion phase.
+func SelectPhases(only, skip []string) (Phases, error) {
+	if len(only) != 0 && len(skip) != 0 {
+		return nil, errors.New("phases cannot be both selected and skipped")
+	}
+
+	selected, err := parsePhases(slices.Concat(only, skip))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(selected) == 0 {
+		return Phases(AllPhases), nil
+	}
+
+	var phases Phases
+	for _, phase := range AllPhases {
+		if slices.Contains(selected, phase) == (len(only) != 0) {
+			phases = append(phases, phase)
+		}
+	}
+
+	if len(phases) == 0 {
+		return nil, errors.New("at least one phase must be enabled")
+	}
+
+	if !slices.Contains(phases, PhaseCombustion) {
+		return nil, fmt.Errorf("the %s phase requires the %s phase", phases[0], PhaseCombustion)
+	}
+
+	return phases, nil
+}
+
+func parsePhases(names []string) ([]Phase, error) {
+	var phases []Phase
+	for _, name := range names {
+		phase := Phase(name)
+		if !slices.Contains(AllPhases, phase) {
+			return nil, fmt.Errorf("unknown phase '%s', must be one of: %s", name, Phases(AllPhases))
+		}
+
+		phases = append(phases, phase)
+	}
+
+	return phases, nil
+}
+
+// PhaseError is returned by Run when a build phase fails, identifying the phase which produced the error.
+type PhaseError struct {
+	Phase Phase
+	Err   error
+}
+
+func (e *PhaseError) Error() string {
+	return fmt.Sprintf("%s failed: %s", e.Phase, e.Err)
+}
+
+func (e *PhaseError) Unwrap() error {
+	return e.Err
+}
+
+// SkippedDownloadError is returned by Run when the download phase is disabled while components requiring
+// downloaded artefacts are configured, which would otherwise be missing from the image.
+type SkippedDownloadError struct {
+	Components []string
+	// Uncached are the cache identifiers of the Kubernetes artefacts missing from the cache, if the Kubernetes
+	// component is one of the Components.
+	Uncached []string
+}
+
+func (e *SkippedDownloadError) Error() string {
+	message := fmt.Sprintf("the %s phase is required by the configured components: %s", PhaseDownload, strings.Join(e.Components, ", "))
+	if len(e.Uncached) != 0 {
+		message += fmt.Sprintf(" (uncached Kubernetes artefacts: %s)", strings.Join(e.Uncached, ", "))
+	}
+
+	return message
+}
+
+// runPreflight runs the checks preceding the enabled phases, returning their errors as a PhaseError.
+func runPreflight(run func() error) error {
+	if err := run(); err != nil {
+		return &PhaseError{Phase: PhasePreflight, Err: err}
+	}
+
+	return nil
+}
+
+// runPhase runs the given phase if it is enabled, marking its start and completion in the audit log.
+// The phase is not started once the context is canceled. Errors are returned as a PhaseError.
+func runPhase(ctx context.Context, phases Phases, phase Phase, run func() error) error {
+	number, total := phases.position(phase)
+	if number == 0 {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return &PhaseError{Phase: phase, Err: fmt.Errorf("build canceled: %w", err)}
+	}
+
+	log.AuditPhaseStarted(string(phase), number, total)
+	start := time.Now()
+
+	if err := run(); err != nil {
+		log.AuditPhaseFailed(string(phase), number, total, time.Since(start))
+		return &PhaseError{Phase: phase, Err: err}
+	}
+
+	log.AuditPhaseCompleted(string(phase), number, total, time.Since(start))
+	return nil
+}
@@ -0,0 +1,183 @@
+package eib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPhases(t *testing.T) {
+	tests := map[string]struct {
+		Only           []string
+		Skip           []string
+		ExpectedPhases Phases
+		ExpectedError  string
+	}{
+		`no selection`: {
+			ExpectedPhases: Phases{PhaseDownload, PhaseCombustion, PhaseAssembly},
+		},
+		`only combustion`: {
+			Only:           []string{"combustion"},
+			ExpectedPhases: Phases{PhaseCombustion},
+		},
+		`only unordered`: {
+			Only:           []string{"combustion", "download"},
+			ExpectedPhases: Phases{PhaseDownload, PhaseCombustion},
+		},
+		`skip download`: {
+			Skip:           []string{"download"},
+			ExpectedPhases: Phases{PhaseCombustion, PhaseAssembly},
+		},
+		`skip download and assembly`: {
+			Skip:           []string{"assembly", "download"},
+			ExpectedPhases: Phases{PhaseCombustion},
+		},
+		`only and skip`: {
+			Only:          []string{"combustion"},
+			Skip:          []string{"download"},
+			ExpectedError: "phases cannot be both selected and skipped",
+		},
+		`unknown phase`: {
+			Skip:          []string{"upload"},
+			ExpectedError: "unknown phase 'upload', must be one of: download, combustion, assembly",
+		},
+		`preflight`: {
+			Skip:          []string{"preflight"},
+			ExpectedError: "unknown phase 'preflight', must be one of: download, combustion, assembly",
+		},
+		`only assembly`: {
+			Only:          []string{"assembly"},
+			ExpectedError: "the assembly phase requires the combustion phase",
+		},
+		`skip combustion`: {
+			Skip:          []string{"combustion"},
+			ExpectedError: "the download phase requires the combustion phase",
+		},
+		`skip all`: {
+			Skip:          []string{"download", "combustion", "assembly"},
+			ExpectedError: "at least one phase must be enabled",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			phases, err := SelectPhases(test.Only, test.Skip)
+			if test.ExpectedError != "" {
+				require.EqualError(t, err, test.ExpectedError)
+				assert.Nil(t, phases)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedPhases, phases)
+		})
+	}
+}
+
+func TestPhasesEnabled(t *testing.T) {
+	assert.True(t, Phases{}.Enabled(PhaseAssembly))
+	assert.True(t, Phases{PhaseCombustion}.Enabled(PhaseCombustion))
+	assert.False(t, Phases{PhaseCombustion}.Enabled(PhaseDownload))
+
+	assert.Equal(t, "download, combustion, assembly", Phases{}.String())
+	assert.Equal(t, "combustion, assembly", Phases{PhaseCombustion, PhaseAssembly}.String())
+}
+
+func TestPhasesPosition(t *testing.T) {
+	tests := map[string]struct {
+		Phases         Phases
+		Phase          Phase
+		ExpectedNumber int
+		ExpectedTotal  int
+	}{
+		`all phases`: {
+			Phase:          PhaseAssembly,
+			ExpectedNumber: 3,
+			ExpectedTotal:  3,
+		},
+		`skipped download`: {
+			Phases:         Phases{PhaseCombustion, PhaseAssembly},
+			Phase:          PhaseCombustion,
+			ExpectedNumber: 1,
+			ExpectedTotal:  2,
+		},
+		`disabled phase`: {
+			Phases:         Phases{PhaseDownload, PhaseCombustion},
+			Phase:          PhaseAssembly,
+			ExpectedNumber: 0,
+			ExpectedTotal:  2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			number, total := test.Phases.position(test.Phase)
+			assert.Equal(t, test.ExpectedNumber, number)
+			assert.Equal(t, test.ExpectedTotal, total)
+		})
+	}
+}
+
+func TestRunPhase(t *testing.T) {
+	var runs int
+	run := func() error {
+		runs++
+		return nil
+	}
+
+	require.NoError(t, runPhase(context.Background(), Phases{PhaseCombustion}, PhaseCombustion, run))
+	require.NoError(t, runPhase(context.Background(), Phases{PhaseCombustion}, PhaseDownload, run))
+	assert.Equal(t, 1, runs)
+
+	copyErr := errors.New("copying base image")
+	err := runPhase(context.Background(), nil, PhaseAssembly, func() error {
+		return fmt.Errorf("assembling image: %w", copyErr)
+	})
+	assert.EqualError(t, err, "assembly failed: assembling image: copying base image")
+	assert.ErrorIs(t, err, copyErr)
+
+	var phaseErr *PhaseError
+	require.ErrorAs(t, err, &phaseErr)
+	assert.Equal(t, PhaseAssembly, phaseErr.Phase)
+
+	// Disabled phases are not run, so their errors cannot be attributed to them
+	require.NoError(t, runPhase(context.Background(), Phases{PhaseCombustion}, PhaseDownload, func() error {
+		return copyErr
+	}))
+}
+
+func TestRunPhase_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var runs int
+	err := runPhase(ctx, Phases{PhaseCombustion}, PhaseCombustion, func() error {
+		runs++
+		return nil
+	})
+
+	assert.Zero(t, runs)
+	assert.EqualError(t, err, "combustion failed: build canceled: context canceled")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRunPreflight(t *testing.T) {
+	require.NoError(t, runPreflight(func() error { return nil }))
+
+	toolsErr := &MissingToolsError{Tools: []RequiredTool{{Name: "xorriso"}}}
+	err := runPreflight(func() error {
+		return toolsErr
+	})
+
+	var phaseErr *PhaseError
+	require.ErrorAs(t, err, &phaseErr)
+	assert.Equal(t, PhasePreflight, phaseErr.Phase)
+
+	var foundToolsErr *MissingToolsError
+	require.ErrorAs(t, err, &foundToolsErr)
+	assert.Equal(t, toolsErr, foundToolsErr)
+}
@@ -0,0 +1,169 @@
+package eib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+)
+
+// RequiredTool is a host executable which the enabled features of a build depend on.
+type RequiredTool struct {
+	// Name is the executable looked up in the PATH.
+	Name string
+	// Package is the package providing the executable, as installed in the EIB container image.
+	Package string
+	// Features are the parts of the build requiring the executable.
+	Features []string
+}
+
+// MissingToolsError is returned by Build when executables required by the build are not installed.
+type MissingToolsError struct {
+	Tools []RequiredTool
+}
+
+func (e *MissingToolsError) Error() string {
+	names := make([]string, 0, len(e.Tools))
+	for _, tool := range e.Tools {
+		names = append(names, tool.Name)
+	}
+
+	return fmt.Sprintf("required tools are not installed: %s", strings.Join(names, ", "))
+}
+
+// lookPath is replaced in tests to simulate the installed executables
+var lookPath = exec.LookPath
+
+// checkRequiredTools verifies that every executable required by the build is installed before any
+// long-running work starts, so that all missing ones are reported together.
+func checkRequiredTools(ctx *image.Context, phases Phases) error {
+	var missing []RequiredTool
+
+	for _, tool := range requiredTools(ctx, phases) {
+		path, err := lookPath(tool.Name)
+		if err != nil {
+			zap.S().Warnf("Required tool %s not found: %s", tool.Name, err)
+			missing = append(missing, tool)
+			continue
+		}
+
+		zap.S().Debugf("Found required tool %s at %s", tool.Name, path)
+	}
+
+	if len(missing) != 0 {
+		return &MissingToolsError{Tools: missing}
+	}
+
+	return nil
+}
+
+// requiredTools lists the executables the build invokes for the configured features and enabled phases.
+func requiredTools(ctx *image.Context, phases Phases) []RequiredTool {
+	def := ctx.ImageDefinition
+
+	var tools []RequiredTool
+	require := func(feature, name, pkg string) {
+		i := slices.IndexFunc(tools, func(tool RequiredTool) bool {
+			return tool.Name == name
+		})
+		if i == -1 {
+			tools = append(tools, RequiredTool{Name: name, Package: pkg})
+			i = len(tools) - 1
+		}
+
+		if !slices.Contains(tools[i].Features, feature) {
+			tools[i].Features = append(tools[i].Features, feature)
+		}
+	}
+
+	if phases.Enabled(PhaseDownload) {
+		if !combustion.SkipRPMComponent(ctx) {
+			const feature = "resolving RPMs"
+
+			require(feature, "podman", "podman")
+			require(feature, "createrepo", "createrepo_c")
+			require(feature, "guestfish", "libguestfs")
+			require(feature, "virt-tar-out", "libguestfs")
+			require(feature, "pigz", "pigz")
+
+			if def.Image.ImageType == image.TypeISO {
+				require(feature, "xorriso", "xorriso")
+				require(feature, "unsquashfs", "squashfs")
+			}
+		}
+
+		if combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
+			require("embedding artifacts", "hauler", "hauler")
+		}
+
+		if len(def.Kubernetes.Helm.Charts) != 0 {
+			require("templating Helm charts", "helm", "helm")
+		}
+	}
+
+	// The network configuration is generated with the combustion content, which is always configured
+	if isConfigured(ctx, combustion.NetworkConfigDir) || len(def.OperatingSystem.NetworkProfiles.Profiles) != 0 {
+		require("generating network configurations", "nmc", "nm-configurator")
+	}
+
+	switch {
+	case ctx.CombustionOnly:
+		require("building the combustion ISO", "xorriso", "xorriso")
+	case !phases.Enabled(PhaseAssembly):
+	case def.Image.ImageType == image.TypeISO:
+		const feature = "building ISO images"
+
+		require(feature, "xorriso", "xorriso")
+		require(feature, "unsquashfs", "squashfs")
+		require(feature, "mksquashfs", "squashfs")
+		require(feature, "guestfish", "libguestfs")
+	case def.Image.ImageType == image.TypeRAW:
+		const feature = "modifying RAW images"
+
+		require(feature, "guestfish", "libguestfs")
+		if def.OperatingSystem.RawConfiguration.DiskSize != "" || def.OperatingSystem.RawConfiguration.ABPartitions {
+			require(feature, "virt-resize", "guestfs-tools")
+		}
+	}
+
+	if len(def.Image.SeedISOs) != 0 && (ctx.CombustionOnly || phases.Enabled(PhaseAssembly)) {
+		require("building cloud-init seed ISOs", "xorriso", "xorriso")
+	}
+
+	if signing := def.OperatingSystem.SecureBoot.Signing; phases.Enabled(PhaseAssembly) && !ctx.CombustionOnly {
+		const feature = "signing boot artefacts"
+
+		if signing.Kernel {
+			require(feature, "sbsign", "sbsigntools")
+		}
+
+		if signing.Modules {
+			require(feature, "openssl", "openssl")
+			require(feature, "zstd", "zstd")
+			require(feature, "xz", "xz")
+		}
+	}
+
+	if phases.Enabled(PhaseAssembly) && !ctx.CombustionOnly && def.Image.OutputFormat == image.OutputFormatQCOW2 {
+		require("converting images to QCOW2", "qemu-img", "qemu-tools")
+	}
+
+	return tools
+}
+
+func isConfigured(ctx *image.Context, componentDir string) bool {
+	_, err := os.Stat(filepath.Join(ctx.ImageConfigDir, componentDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		zap.S().Warnf("Looking for '%s' dir failed unexpectedly: %s", componentDir, err)
+	}
+
+	return err == nil
+}
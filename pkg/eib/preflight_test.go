@@ -0,0 +1,202 @@
+package eib
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func toolNames(tools []RequiredTool) []string {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+
+	return names
+}
+
+func TestRequiredTools(t *testing.T) {
+	tests := map[string]struct {
+		definition     image.Definition
+		combustionOnly bool
+		phases         Phases
+		expectedTools  []string
+	}{
+		"RAW image": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW},
+			},
+			expectedTools: []string{"guestfish"},
+		},
+		"Resized RAW image converted to QCOW2": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW, OutputFormat: image.OutputFormatQCOW2},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{DiskSize: "64G"},
+				},
+			},
+			expectedTools: []string{"guestfish", "virt-resize", "qemu-img"},
+		},
+		"ISO image with packages and embedded images": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeISO},
+				OperatingSystem: image.OperatingSystem{
+					Packages: image.Packages{PKGList: []string{"vim"}},
+				},
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{{Name: "nginx:1.25"}},
+				},
+			},
+			expectedTools: []string{"podman", "createrepo", "guestfish", "virt-tar-out", "pigz", "xorriso", "unsquashfs",
+				"hauler", "mksquashfs"},
+		},
+		"Helm charts without downloads": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW},
+				Kubernetes: image.Kubernetes{
+					Helm: image.Helm{Charts: []image.HelmChart{{Name: "metallb"}}},
+				},
+			},
+			phases:        Phases{PhaseCombustion, PhaseAssembly},
+			expectedTools: []string{"guestfish"},
+		},
+		"Helm charts without assembly": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeISO},
+				Kubernetes: image.Kubernetes{
+					Helm: image.Helm{Charts: []image.HelmChart{{Name: "metallb"}}},
+				},
+			},
+			phases:        Phases{PhaseDownload, PhaseCombustion},
+			expectedTools: []string{"hauler", "helm"},
+		},
+		"RAW image signed for Secure Boot": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW},
+				OperatingSystem: image.OperatingSystem{
+					SecureBoot: image.SecureBoot{
+						Signing: image.SecureBootSigning{Kernel: true, Modules: true},
+					},
+				},
+			},
+			expectedTools: []string{"guestfish", "sbsign", "openssl", "zstd", "xz"},
+		},
+		"RAW image with network profiles": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW},
+				OperatingSystem: image.OperatingSystem{
+					NetworkProfiles: image.NetworkProfiles{Default: "office", Profiles: []string{"office", "field"}},
+				},
+			},
+			expectedTools: []string{"nmc", "guestfish"},
+		},
+		"Combustion ISO": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeISO, OutputFormat: image.OutputFormatQCOW2},
+			},
+			combustionOnly: true,
+			phases:         Phases{PhaseDownload, PhaseCombustion},
+			expectedTools:  []string{"xorriso"},
+		},
+		"Seed ISOs": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW, SeedISOs: []image.SeedISO{{Hostname: "node1"}}},
+			},
+			phases:        Phases{PhaseAssembly},
+			expectedTools: []string{"guestfish", "xorriso"},
+		},
+		"Seed ISOs without assembly": {
+			definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW, SeedISOs: []image.SeedISO{{Hostname: "node1"}}},
+			},
+			phases: Phases{PhaseDownload, PhaseCombustion},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageConfigDir:  t.TempDir(),
+				ImageDefinition: &test.definition,
+				CombustionOnly:  test.combustionOnly,
+			}
+
+			assert.Equal(t, test.expectedTools, toolNames(requiredTools(ctx, test.phases)))
+		})
+	}
+}
+
+func TestRequiredTools_Network(t *testing.T) {
+	ctx := &image.Context{
+		ImageConfigDir:  t.TempDir(),
+		ImageDefinition: &image.Definition{},
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(ctx.ImageConfigDir, "network"), os.ModePerm))
+
+	tools := requiredTools(ctx, Phases{PhaseDownload, PhaseCombustion})
+	assert.Equal(t, []RequiredTool{
+		{Name: "nmc", Package: "nm-configurator", Features: []string{"generating network configurations"}},
+	}, tools)
+}
+
+func TestRequiredTools_SharedTool(t *testing.T) {
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		ImageDefinition: &image.Definition{
+			Image: image.Image{ImageType: image.TypeRAW},
+			OperatingSystem: image.OperatingSystem{
+				Packages: image.Packages{PKGList: []string{"vim"}},
+			},
+		},
+	}
+
+	tools := requiredTools(ctx, nil)
+	i := slices.IndexFunc(tools, func(tool RequiredTool) bool {
+		return tool.Name == "guestfish"
+	})
+	require.NotEqual(t, -1, i)
+
+	assert.Equal(t, "libguestfs", tools[i].Package)
+	assert.Equal(t, []string{"resolving RPMs", "modifying RAW images"}, tools[i].Features)
+}
+
+func TestCheckRequiredTools(t *testing.T) {
+	defer func(original func(string) (string, error)) {
+		lookPath = original
+	}(lookPath)
+
+	lookPath = func(name string) (string, error) {
+		if name == "guestfish" {
+			return "/usr/bin/guestfish", nil
+		}
+
+		return "", errors.New("executable file not found in $PATH")
+	}
+
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		ImageDefinition: &image.Definition{
+			Image: image.Image{ImageType: image.TypeRAW, OutputFormat: image.OutputFormatQCOW2},
+			EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{{Name: "nginx:1.25"}},
+			},
+		},
+	}
+
+	err := checkRequiredTools(ctx, nil)
+	require.Error(t, err)
+
+	var toolsErr *MissingToolsError
+	require.ErrorAs(t, err, &toolsErr)
+	assert.Equal(t, []string{"hauler", "qemu-img"}, toolNames(toolsErr.Tools))
+	assert.EqualError(t, err, "required tools are not installed: hauler, qemu-img")
+
+	ctx.ImageDefinition = &image.Definition{Image: image.Image{ImageType: image.TypeRAW}}
+	assert.NoError(t, checkRequiredTools(ctx, nil))
+}
@@ -0,0 +1,151 @@
+package eib
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/build"
+	"github.com/suse-edge/edge-image-builder/pkg/env"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
+)
+
+const (
+	OptionExpectedHash    = "ExpectedHash"
+	OptionSourceDateEpoch = "SourceDateEpoch"
+	OptionToolArgs        = "ToolArgs"
+)
+
+// definitionHashRegex matches the hexadecimal SHA-256 hashes accepted as the expected definition hash
+var definitionHashRegex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// OptionError is returned by Prepare and Build when one of the Options is invalid.
+type OptionError struct {
+	// Option is the name of the invalid field of the Options, e.g. OptionToolArgs.
+	Option string
+	Err    error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("invalid %s option: %s", e.Option, e.Err)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// HashMismatchError is returned by Prepare and Build when the image definition does not have the expected hash,
+// meaning that the definition or its includes have changed since the hash was recorded.
+type HashMismatchError struct {
+	Hash     string
+	Expected string
+}
+
+func (e *HashMismatchError) Error() string {
+	return fmt.Sprintf("definition hash %s does not match the expected hash %s", e.Hash, e.Expected)
+}
+
+// Prepare validates the image definition, unless skipped, and applies the options to the build context ahead of
+// the build: it checks the hash of the definition, fixes the timestamps of reproducible builds and parses the
+// arguments forwarded to the build tooling. Build prepares the context itself, calling Prepare is only needed to
+// Explain a build without running it.
+func Prepare(buildCtx *image.Context, opts Options) error {
+	if !opts.SkipValidation {
+		if err := validateDefinition(buildCtx, opts.Strict); err != nil {
+			return err
+		}
+	}
+
+	if opts.ExpectedHash != "" {
+		if err := checkDefinitionHash(buildCtx.ImageDefinition, opts.ExpectedHash); err != nil {
+			return err
+		}
+	}
+
+	sourceDate, err := parseSourceDate(opts.SourceDateEpoch, opts.Reproducible)
+	if err != nil {
+		return &OptionError{Option: OptionSourceDateEpoch, Err: err}
+	}
+	buildCtx.SourceDate = sourceDate
+
+	if buildCtx.ToolArgs, err = parseToolArgs(buildCtx, opts.ToolArgs, opts.Phases); err != nil {
+		return &OptionError{Option: OptionToolArgs, Err: err}
+	}
+
+	return nil
+}
+
+// checkDefinitionHash detects changes made to the definition or its includes since the expected hash was recorded.
+func checkDefinitionHash(definition *image.Definition, expected string) error {
+	if !definitionHashRegex.MatchString(expected) {
+		return &OptionError{
+			Option: OptionExpectedHash,
+			Err:    fmt.Errorf("'%s' is not a SHA-256 hash of 64 hexadecimal characters", expected),
+		}
+	}
+
+	hash, err := image.DefinitionHash(definition)
+	if err != nil {
+		return fmt.Errorf("hashing image definition: %w", err)
+	}
+
+	if !strings.EqualFold(hash, expected) {
+		return &HashMismatchError{Hash: hash, Expected: expected}
+	}
+
+	log.AuditInfof("The image definition matches the expected hash %s.", hash)
+	return nil
+}
+
+// parseSourceDate determines the fixed timestamp of a reproducible build. The SOURCE_DATE_EPOCH value is honored
+// whenever it is set, while reproducible builds alone fall back to the Unix epoch. A zero time is returned otherwise.
+func parseSourceDate(epoch string, reproducible bool) (time.Time, error) {
+	if epoch == "" {
+		if !reproducible {
+			return time.Time{}, nil
+		}
+
+		epoch = "0"
+	}
+
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, fmt.Errorf("the %s value '%s' must be a non-negative number of seconds since the Unix epoch",
+			env.SourceDateEpochVariable, epoch)
+	}
+
+	sourceDate := time.Unix(seconds, 0).UTC()
+	log.Auditf("Building reproducibly with timestamps fixed to %s.", sourceDate.Format(time.RFC3339))
+
+	return sourceDate, nil
+}
+
+// parseToolArgs parses the options forwarded to the build tooling. These are an escape hatch for options the image
+// definition does not model, so their use is flagged as unsupported.
+func parseToolArgs(buildCtx *image.Context, values []string, phases Phases) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	// Only the arguments of the tools actually run by the build are accepted, rather than being silently ignored
+	assembles := !buildCtx.CombustionOnly && phases.Enabled(PhaseAssembly)
+	usage := build.ToolArgUsage{
+		Guestfish:       assembles || (phases.Enabled(PhaseDownload) && ResolvesRPMs(buildCtx)),
+		Qcow2Conversion: assembles && buildCtx.ImageDefinition.Image.OutputFormat == image.OutputFormatQCOW2,
+	}
+
+	toolArgs, err := build.ParseToolArgs(values, usage)
+	if err != nil {
+		return nil, fmt.Errorf("%w, the supported keys are: %s", err, strings.Join(build.ToolArgNames(), ", "))
+	}
+
+	log.Auditf("UNSUPPORTED: Forwarding %s to the build tooling. Tool arguments are an escape hatch "+
+		"outside of the supported configuration, builds relying on them may break between releases.", strings.Join(values, ", "))
+	zap.S().Warnf("Unsupported tool arguments forwarded to the build tooling: %s", strings.Join(values, ", "))
+
+	return toolArgs, nil
+}
@@ -0,0 +1,178 @@
+package eib
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestCheckDefinitionHash(t *testing.T) {
+	definition := &image.Definition{
+		APIVersion: "1.0",
+		Image: image.Image{
+			ImageType:       image.TypeRAW,
+			Arch:            image.ArchTypeX86,
+			BaseImage:       "base.raw",
+			OutputImageName: "eib.raw",
+		},
+	}
+
+	hash, err := image.DefinitionHash(definition)
+	require.NoError(t, err)
+
+	mismatch := strings.Repeat("0", 64)
+
+	tests := map[string]struct {
+		expected      string
+		expectedError string
+		mismatch      bool
+	}{
+		"Matching": {
+			expected: hash,
+		},
+		"Matching upper case": {
+			expected: strings.ToUpper(hash),
+		},
+		"Mismatch": {
+			expected:      mismatch,
+			expectedError: "definition hash " + hash + " does not match the expected hash " + mismatch,
+			mismatch:      true,
+		},
+		"Too short": {
+			expected:      hash[:63],
+			expectedError: "invalid ExpectedHash option: '" + hash[:63] + "' is not a SHA-256 hash of 64 hexadecimal characters",
+		},
+		"Not hexadecimal": {
+			expected:      strings.Repeat("g", 64),
+			expectedError: "invalid ExpectedHash option: '" + strings.Repeat("g", 64) + "' is not a SHA-256 hash of 64 hexadecimal characters",
+		},
+		"Prefixed algorithm": {
+			expected:      "sha256:" + hash,
+			expectedError: "invalid ExpectedHash option: 'sha256:" + hash + "' is not a SHA-256 hash of 64 hexadecimal characters",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkDefinitionHash(definition, test.expected)
+
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, test.expectedError)
+
+			if test.mismatch {
+				var hashErr *HashMismatchError
+				require.ErrorAs(t, err, &hashErr)
+				assert.Equal(t, hash, hashErr.Hash)
+				assert.Equal(t, mismatch, hashErr.Expected)
+			} else {
+				var optionErr *OptionError
+				require.ErrorAs(t, err, &optionErr)
+				assert.Equal(t, OptionExpectedHash, optionErr.Option)
+			}
+		})
+	}
+}
+
+func TestParseSourceDate(t *testing.T) {
+	tests := map[string]struct {
+		epoch         string
+		reproducible  bool
+		expectedDate  time.Time
+		expectedError string
+	}{
+		"Not reproducible": {},
+		"Reproducible": {
+			reproducible: true,
+			expectedDate: time.Unix(0, 0).UTC(),
+		},
+		"Epoch": {
+			epoch:        "1700000000",
+			expectedDate: time.Unix(1700000000, 0).UTC(),
+		},
+		"Epoch and reproducible": {
+			epoch:        "1700000000",
+			reproducible: true,
+			expectedDate: time.Unix(1700000000, 0).UTC(),
+		},
+		"Negative epoch": {
+			epoch:         "-1",
+			expectedError: "the SOURCE_DATE_EPOCH value '-1' must be a non-negative number of seconds since the Unix epoch",
+		},
+		"Invalid epoch": {
+			epoch:         "yesterday",
+			expectedError: "the SOURCE_DATE_EPOCH value 'yesterday' must be a non-negative number of seconds since the Unix epoch",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sourceDate, err := parseSourceDate(test.epoch, test.reproducible)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedDate, sourceDate)
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	newContext := func() *image.Context {
+		return &image.Context{
+			ImageDefinition: &image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW, OutputImageName: "eib.raw"},
+			},
+		}
+	}
+
+	t.Run("Options applied", func(t *testing.T) {
+		buildCtx := newContext()
+
+		err := Prepare(buildCtx, Options{
+			SkipValidation:  true,
+			SourceDateEpoch: "1700000000",
+			ToolArgs:        []string{"libguestfs-memsize=2048"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(1700000000, 0).UTC(), buildCtx.SourceDate)
+		assert.Equal(t, map[string]string{"libguestfs-memsize": "2048"}, buildCtx.ToolArgs)
+	})
+
+	t.Run("Tool arguments of skipped phases", func(t *testing.T) {
+		buildCtx := newContext()
+
+		err := Prepare(buildCtx, Options{
+			SkipValidation: true,
+			Phases:         Phases{PhaseCombustion},
+			ToolArgs:       []string{"libguestfs-memsize=2048"},
+		})
+
+		var optionErr *OptionError
+		require.ErrorAs(t, err, &optionErr)
+		assert.Equal(t, OptionToolArgs, optionErr.Option)
+		assert.ErrorContains(t, err, "does not apply, as libguestfs is not run by the build, the supported keys are: ")
+	})
+
+	t.Run("Invalid definition", func(t *testing.T) {
+		buildCtx := newContext()
+		buildCtx.ImageDefinition.Image.ImageType = "vmdk"
+
+		err := Prepare(buildCtx, Options{SourceDateEpoch: "1700000000"})
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.True(t, buildCtx.SourceDate.IsZero())
+	})
+}
@@ -0,0 +1,199 @@
+package eib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
+)
+
+// The root file system is kept as a raw image within the squashfs of SL Micro ISOs, which it is
+// extracted from during the assembly. The extracted raw image is estimated to take up to three
+// times the size of the ISO.
+const isoRawImageRatio = 3
+
+// InsufficientSpaceError is returned by Build when a directory used by the build lacks the space the build
+// is estimated to require.
+type InsufficientSpaceError struct {
+	// Dir is the directory lacking space, representing every directory of the build on the same file system.
+	Dir       string
+	Required  int64
+	Available int64
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	return fmt.Sprintf("insufficient disk space in '%s': the build requires an estimated %s but only %s is available, %s short",
+		e.Dir, formatSpace(e.Required), formatSpace(e.Available), formatSpace(e.Required-e.Available))
+}
+
+// fileSystemSpace is replaced in tests to simulate the file systems of the build directories
+var fileSystemSpace = func(dir string) (device uint64, available int64, err error) {
+	var stat syscall.Stat_t
+	if err = syscall.Stat(dir, &stat); err != nil {
+		return 0, 0, fmt.Errorf("reading file info: %w", err)
+	}
+
+	var statfs syscall.Statfs_t
+	if err = syscall.Statfs(dir, &statfs); err != nil {
+		return 0, 0, fmt.Errorf("reading file system info: %w", err)
+	}
+
+	return stat.Dev, int64(statfs.Bavail) * statfs.Bsize, nil
+}
+
+// checkFreeSpace fails the build early if the build or output directory lack the space the build is estimated
+// to require, rather than leaving the build to fail late once the file system fills up. The space required by
+// directories sharing a file system is added up.
+func checkFreeSpace(ctx *image.Context, rootBuildDir string, phases Phases) error {
+	buildSpace, outputSpace, err := estimateRequiredSpace(ctx, rootBuildDir, phases)
+	if err != nil {
+		return fmt.Errorf("estimating required disk space: %w", err)
+	}
+
+	outputDir := filepath.Dir(filepath.Join(ctx.ImageConfigDir, ctx.ImageDefinition.Image.OutputImageName))
+
+	type fileSystem struct {
+		dir       string
+		required  int64
+		available int64
+	}
+
+	var fileSystems []*fileSystem
+	devices := map[uint64]*fileSystem{}
+
+	for _, dir := range []struct {
+		path     string
+		required int64
+	}{
+		{path: ctx.BuildDir, required: buildSpace},
+		{path: outputDir, required: outputSpace},
+	} {
+		device, available, spaceErr := fileSystemSpace(dir.path)
+		if spaceErr != nil {
+			return fmt.Errorf("determining available disk space in '%s': %w", dir.path, spaceErr)
+		}
+
+		target, ok := devices[device]
+		if !ok {
+			target = &fileSystem{dir: dir.path, available: available}
+			devices[device] = target
+			fileSystems = append(fileSystems, target)
+		}
+		target.required += dir.required
+	}
+
+	for _, target := range fileSystems {
+		zap.S().Debugf("Estimated %d bytes required in '%s', %d bytes available", target.required, target.dir, target.available)
+
+		if target.required > target.available {
+			return &InsufficientSpaceError{Dir: target.dir, Required: target.required, Available: target.available}
+		}
+	}
+
+	log.AuditInfof("Estimated disk space required by the build: %s.", formatSpace(buildSpace+outputSpace))
+
+	return nil
+}
+
+// estimateRequiredSpace estimates the space taken up in the build and output directories from the size of the
+// base image and of the content provided in the image configuration directory, which is copied into the build.
+// The size of the artefacts downloaded during the build is not known upfront and is not accounted for.
+func estimateRequiredSpace(ctx *image.Context, rootBuildDir string, phases Phases) (buildSpace, outputSpace int64, err error) {
+	content, err := configContentSize(ctx, rootBuildDir)
+	if err != nil {
+		return 0, 0, fmt.Errorf("calculating image configuration size: %w", err)
+	}
+
+	if phases.Enabled(PhaseDownload) || phases.Enabled(PhaseCombustion) {
+		buildSpace += content
+	}
+
+	if ctx.CombustionOnly {
+		return buildSpace, content, nil
+	}
+
+	if !phases.Enabled(PhaseAssembly) {
+		return buildSpace, 0, nil
+	}
+
+	var baseImage int64
+	if info, statErr := os.Stat(ctx.BaseImagePath()); statErr == nil {
+		baseImage = info.Size()
+	}
+
+	def := ctx.ImageDefinition
+	switch def.Image.ImageType {
+	case image.TypeISO:
+		buildSpace += baseImage + isoRawImageRatio*baseImage
+		outputSpace = baseImage + content
+	case image.TypeRAW:
+		rawImage := max(baseImage, def.OperatingSystem.RawConfiguration.DiskSize.ToMB()*1024*1024)
+		if def.Image.OutputFormat == image.OutputFormatQCOW2 {
+			// The raw image is assembled in the build directory and converted into the output image
+			buildSpace += rawImage
+			outputSpace = baseImage + content
+		} else {
+			outputSpace = rawImage
+		}
+	}
+
+	return buildSpace, outputSpace, nil
+}
+
+// configContentSize sums up the size of the files within the subdirectories of the image configuration
+// directory, holding the content of the configured components. The base images and the build directories
+// are excluded, as are the files at the top of the directory such as the definition and earlier output images.
+func configContentSize(ctx *image.Context, rootBuildDir string) (int64, error) {
+	excluded := []string{filepath.Join(ctx.ImageConfigDir, "base-images"), filepath.Clean(rootBuildDir)}
+
+	var size int64
+	err := filepath.WalkDir(ctx.ImageConfigDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrPermission) {
+				zap.S().Warnf("Skipping unreadable path '%s' while estimating the required disk space", path)
+				return nil
+			}
+			return err
+		}
+
+		if entry.IsDir() {
+			for _, dir := range excluded {
+				if path == dir {
+					return filepath.SkipDir
+				}
+			}
+
+			return nil
+		}
+
+		if filepath.Dir(path) == filepath.Clean(ctx.ImageConfigDir) || !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+func formatSpace(bytes int64) string {
+	const mib = 1024 * 1024
+
+	if bytes < 1024*mib {
+		return fmt.Sprintf("%d MiB", (bytes+mib-1)/mib)
+	}
+
+	return fmt.Sprintf("%.1f GiB", float64(bytes)/(1024*mib))
+}
@@ -0,0 +1,145 @@
+package eib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const mib = 1024 * 1024
+
+func writeSizedFile(t *testing.T, path string, size int64) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o600))
+}
+
+func spaceTestContext(t *testing.T, imageType string) (ctx *image.Context, rootBuildDir string) {
+	configDir := t.TempDir()
+	rootBuildDir = filepath.Join(configDir, "_build")
+
+	ctx = &image.Context{
+		ImageConfigDir: configDir,
+		BuildDir:       filepath.Join(rootBuildDir, "build-1"),
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       imageType,
+				BaseImage:       "base.img",
+				OutputImageName: "output.img",
+			},
+		},
+	}
+
+	writeSizedFile(t, filepath.Join(configDir, "base-images", "base.img"), 4*mib)
+	writeSizedFile(t, filepath.Join(configDir, "rpms", "custom.rpm"), 2*mib)
+	writeSizedFile(t, filepath.Join(configDir, "definition.yaml"), 1024)
+	writeSizedFile(t, filepath.Join(rootBuildDir, "cache", "artefact"), 8*mib)
+	require.NoError(t, os.MkdirAll(ctx.BuildDir, os.ModePerm))
+
+	return ctx, rootBuildDir
+}
+
+func TestEstimateRequiredSpace(t *testing.T) {
+	tests := map[string]struct {
+		imageType      string
+		outputFormat   string
+		diskSize       image.DiskSize
+		combustionOnly bool
+		phases         Phases
+		expectedBuild  int64
+		expectedOutput int64
+	}{
+		"ISO image": {
+			imageType:      image.TypeISO,
+			expectedBuild:  2*mib + 4*mib + 3*4*mib,
+			expectedOutput: 4*mib + 2*mib,
+		},
+		"RAW image": {
+			imageType:      image.TypeRAW,
+			expectedBuild:  2 * mib,
+			expectedOutput: 4 * mib,
+		},
+		"Resized RAW image converted to QCOW2": {
+			imageType:      image.TypeRAW,
+			outputFormat:   image.OutputFormatQCOW2,
+			diskSize:       "16M",
+			expectedBuild:  2*mib + 16*mib,
+			expectedOutput: 4*mib + 2*mib,
+		},
+		"Combustion ISO": {
+			imageType:      image.TypeRAW,
+			combustionOnly: true,
+			expectedBuild:  2 * mib,
+			expectedOutput: 2 * mib,
+		},
+		"Assembly only": {
+			imageType:      image.TypeRAW,
+			phases:         Phases{PhaseAssembly},
+			expectedOutput: 4 * mib,
+		},
+		"Without assembly": {
+			imageType:     image.TypeISO,
+			phases:        Phases{PhaseDownload, PhaseCombustion},
+			expectedBuild: 2 * mib,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, rootBuildDir := spaceTestContext(t, test.imageType)
+			ctx.ImageDefinition.Image.OutputFormat = test.outputFormat
+			ctx.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize = test.diskSize
+			ctx.CombustionOnly = test.combustionOnly
+
+			buildSpace, outputSpace, err := estimateRequiredSpace(ctx, rootBuildDir, test.phases)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedBuild, buildSpace)
+			assert.Equal(t, test.expectedOutput, outputSpace)
+		})
+	}
+}
+
+func TestCheckFreeSpace(t *testing.T) {
+	defer func(original func(string) (uint64, int64, error)) {
+		fileSystemSpace = original
+	}(fileSystemSpace)
+
+	ctx, rootBuildDir := spaceTestContext(t, image.TypeRAW)
+
+	// The build directory and the output directory are on separate file systems
+	fileSystemSpace = func(dir string) (uint64, int64, error) {
+		if dir == ctx.BuildDir {
+			return 1, 3 * mib, nil
+		}
+
+		return 2, 3 * mib, nil
+	}
+
+	err := checkFreeSpace(ctx, rootBuildDir, nil)
+	require.Error(t, err)
+
+	var spaceErr *InsufficientSpaceError
+	require.ErrorAs(t, err, &spaceErr)
+	assert.Equal(t, &InsufficientSpaceError{Dir: ctx.ImageConfigDir, Required: 4 * mib, Available: 3 * mib}, spaceErr)
+	assert.EqualError(t, err, "insufficient disk space in '"+ctx.ImageConfigDir+
+		"': the build requires an estimated 4 MiB but only 3 MiB is available, 1 MiB short")
+
+	// Both directories are on the same file system, which has to fit both
+	fileSystemSpace = func(string) (uint64, int64, error) {
+		return 1, 5 * mib, nil
+	}
+
+	err = checkFreeSpace(ctx, rootBuildDir, nil)
+	require.ErrorAs(t, err, &spaceErr)
+	assert.Equal(t, &InsufficientSpaceError{Dir: ctx.BuildDir, Required: 6 * mib, Available: 5 * mib}, spaceErr)
+
+	fileSystemSpace = func(string) (uint64, int64, error) {
+		return 1, 6 * mib, nil
+	}
+
+	assert.NoError(t, checkFreeSpace(ctx, rootBuildDir, nil))
+}
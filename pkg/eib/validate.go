@@ -0,0 +1,76 @@
+package eib
+
+import (
+	"slices"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+)
+
+// Severity is the severity of a validation finding.
+type Severity string
+
+const (
+	// SeverityError findings prevent the image from being built.
+	SeverityError Severity = "error"
+	// SeverityWarning findings only prevent the image from being built in strict mode.
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is an issue found while validating the image definition.
+type Finding struct {
+	Severity Severity `json:"severity" yaml:"severity"`
+	// Component is the section of the definition the finding belongs to (e.g. "Operating System").
+	Component string `json:"component" yaml:"component"`
+	// Field is the path of the definition field the finding concerns (e.g. "operatingSystem/zram/size"),
+	// empty if the finding does not concern a single field.
+	Field   string `json:"field,omitempty" yaml:"field,omitempty"`
+	Message string `json:"message" yaml:"message"`
+	// Err is the underlying error, if any, which is not meant to be shown to end users.
+	Err error `json:"-" yaml:"-"`
+}
+
+// Blocking reports whether the finding prevents the image from being built.
+func (f Finding) Blocking(strict bool) bool {
+	return f.Severity == SeverityError || strict
+}
+
+// ValidateDefinition validates the image definition of the build context without building the image,
+// returning the findings ordered by component. It neither logs nor prints them, leaving their rendering
+// to the caller. An empty result means the definition is valid.
+func ValidateDefinition(buildCtx *image.Context) []Finding {
+	return newFindings(validation.ValidateDefinition(buildCtx))
+}
+
+func newFindings(failures map[string][]validation.FailedValidation) []Finding {
+	components := make([]string, 0, len(failures))
+	for component := range failures {
+		components = append(components, component)
+	}
+	slices.Sort(components)
+
+	var findings []Finding
+	for _, component := range components {
+		for _, failure := range failures[component] {
+			findings = append(findings, newFinding(component, failure))
+		}
+	}
+
+	return findings
+}
+
+func newFinding(component string, failure validation.FailedValidation) Finding {
+	finding := Finding{
+		Severity:  SeverityError,
+		Component: component,
+		Field:     failure.Field,
+		Message:   failure.UserMessage,
+		Err:       failure.Error,
+	}
+
+	if failure.Warning {
+		finding.Severity = SeverityWarning
+	}
+
+	return finding
+}
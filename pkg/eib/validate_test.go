@@ -0,0 +1,73 @@
+package eib
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
+)
+
+func TestValidateDefinition(t *testing.T) {
+	findings := ValidateDefinition(&image.Context{
+		ImageConfigDir:  t.TempDir(),
+		ImageDefinition: &image.Definition{APIVersion: "1.0"},
+	})
+	require.NotEmpty(t, findings)
+
+	for _, finding := range findings {
+		assert.NotEmpty(t, finding.Component)
+		assert.NotEmpty(t, finding.Message)
+	}
+	assert.Contains(t, findings, Finding{
+		Severity:  SeverityError,
+		Component: "Image",
+		Field:     "image/imageType",
+		Message:   "The 'imageType' field is required in the 'image' section.",
+	})
+}
+
+func TestNewFindings(t *testing.T) {
+	failures := map[string][]validation.FailedValidation{
+		"Operating System": {
+			{Field: "operatingSystem/packages/packageList", UserMessage: "The 'packageList' field contains duplicate packages: foo"},
+			{UserMessage: "The custom script 'setup.sh' is not executable.", Warning: true},
+		},
+		"Image": {
+			{UserMessage: "The specified base image 'base.raw' cannot be read.", Error: errors.New("permission denied")},
+		},
+	}
+
+	findings := newFindings(failures)
+	assert.Equal(t, []Finding{
+		{
+			Severity:  SeverityError,
+			Component: "Image",
+			Message:   "The specified base image 'base.raw' cannot be read.",
+			Err:       errors.New("permission denied"),
+		},
+		{
+			Severity:  SeverityError,
+			Component: "Operating System",
+			Field:     "operatingSystem/packages/packageList",
+			Message:   "The 'packageList' field contains duplicate packages: foo",
+		},
+		{
+			Severity:  SeverityWarning,
+			Component: "Operating System",
+			Message:   "The custom script 'setup.sh' is not executable.",
+		},
+	}, findings)
+}
+
+func TestFindingBlocking(t *testing.T) {
+	errorFinding := Finding{Severity: SeverityError}
+	warningFinding := Finding{Severity: SeverityWarning}
+
+	assert.True(t, errorFinding.Blocking(false))
+	assert.True(t, errorFinding.Blocking(true))
+	assert.False(t, warningFinding.Blocking(false))
+	assert.True(t, warningFinding.Blocking(true))
+}
@@ -0,0 +1,258 @@
+package eib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"golang.org/x/sync/errgroup"
+)
+
+// WarmTarget is an image definition whose artefacts are downloaded into the cache by WarmCache.
+type WarmTarget struct {
+	// Name identifies the definition in messages, typically the path of its definition file.
+	Name string
+	// ConfigDir is the image configuration directory of the definition, holding the Kubernetes configuration.
+	ConfigDir  string
+	Definition *image.Definition
+}
+
+// WarmResult describes the outcome of WarmCache.
+type WarmResult struct {
+	// Fetched are the sorted identifiers of the artefacts added to the cache.
+	Fetched []string
+	// Skipped are the names of the targets without any cacheable artefacts.
+	Skipped []string
+	// Entries is the number of artefacts in the cache once warmed.
+	Entries int
+	// Size is the total size in bytes of the cache once warmed.
+	Size int64
+}
+
+type artefactDownloader interface {
+	DownloadRKE2Artefacts(ctx context.Context, arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error
+	DownloadK3sArtefacts(ctx context.Context, arch image.Arch, version, installPath, imagesPath string) error
+}
+
+// warmJob is a unique set of artefacts to download, shared by all targets requiring it.
+type warmJob struct {
+	arch          image.Arch
+	version       string
+	cni           string
+	multusEnabled bool
+	targets       []string
+}
+
+// WarmCache downloads the artefacts required by the targets into the cache under the root build directory
+// without building any image. Only the Kubernetes artefacts are cached and thus downloaded.
+//
+// Up to parallelism sets of artefacts are downloaded at the same time, unlimited if zero. Sets sharing the
+// same Kubernetes version, and thus possibly some of their artefacts, are downloaded one after the other.
+// The cache may be used by builds or other warmers in the meantime.
+func WarmCache(ctx context.Context, rootBuildDir string, targets []WarmTarget, parallelism int) (*WarmResult, error) {
+	if rootBuildDir == "" {
+		return nil, errors.New("root build directory not specified")
+	}
+
+	c, err := cache.New(rootBuildDir)
+	if err != nil {
+		return nil, fmt.Errorf("initialising cache instance: %w", err)
+	}
+
+	return warmCache(ctx, c, kubernetes.ArtefactDownloader{Cache: c}, rootBuildDir, targets, parallelism)
+}
+
+func warmCache(ctx context.Context, c *cache.Cache, downloader artefactDownloader, rootBuildDir string,
+	targets []WarmTarget, parallelism int,
+) (*WarmResult, error) {
+	jobs, skipped, err := newWarmJobs(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := c.Identifiers()
+	if err != nil {
+		return nil, fmt.Errorf("listing cached artefacts: %w", err)
+	}
+
+	errGroup, ctx := errgroup.WithContext(ctx)
+	if parallelism > 0 {
+		errGroup.SetLimit(parallelism)
+	}
+
+	for _, group := range groupWarmJobs(jobs) {
+		errGroup.Go(func() error {
+			for _, job := range group {
+				if err := ctx.Err(); err != nil {
+					return fmt.Errorf("warming canceled: %w", err)
+				}
+
+				if err := runWarmJob(ctx, downloader, rootBuildDir, job); err != nil {
+					return fmt.Errorf("downloading %s artefacts for %s: %w", job.version, strings.Join(job.targets, ", "), err)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	if err = errGroup.Wait(); err != nil {
+		return nil, err
+	}
+
+	after, err := c.Identifiers()
+	if err != nil {
+		return nil, fmt.Errorf("listing cached artefacts: %w", err)
+	}
+
+	size, err := c.Size()
+	if err != nil {
+		return nil, fmt.Errorf("calculating cache size: %w", err)
+	}
+
+	result := &WarmResult{
+		Skipped: skipped,
+		Entries: len(after),
+		Size:    size,
+	}
+
+	for _, identifier := range after {
+		if _, found := slices.BinarySearch(before, identifier); !found {
+			result.Fetched = append(result.Fetched, identifier)
+		}
+	}
+
+	return result, nil
+}
+
+// newWarmJobs resolves the artefacts required by each target, merging the targets requiring the same ones.
+func newWarmJobs(targets []WarmTarget) ([]*warmJob, []string, error) {
+	var jobs []*warmJob
+	var skipped []string
+
+	for i := range targets {
+		job, err := newWarmJob(&targets[i])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if job == nil {
+			skipped = append(skipped, targets[i].Name)
+			continue
+		}
+
+		index := slices.IndexFunc(jobs, func(j *warmJob) bool {
+			return j.arch == job.arch && j.version == job.version && j.cni == job.cni && j.multusEnabled == job.multusEnabled
+		})
+		if index >= 0 {
+			jobs[index].targets = append(jobs[index].targets, targets[i].Name)
+			continue
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, skipped, nil
+}
+
+// newWarmJob resolves the artefacts required by the target, returning nil if it has none.
+func newWarmJob(target *WarmTarget) (*warmJob, error) {
+	k8s := target.Definition.Kubernetes
+	if k8s.Version == "" {
+		return nil, nil
+	}
+
+	job := &warmJob{
+		arch:    target.Definition.Image.Arch,
+		version: k8s.Version,
+		targets: []string{target.Name},
+	}
+
+	if strings.Contains(k8s.Version, image.KubernetesDistroRKE2) {
+		configPath := filepath.Join(target.ConfigDir, combustion.K8sDir, "config")
+
+		cluster, err := kubernetes.NewCluster(&k8s, configPath)
+		if err != nil {
+			return nil, fmt.Errorf("initialising cluster config of %s: %w", target.Name, err)
+		}
+
+		if job.cni, job.multusEnabled, err = cluster.ExtractCNI(); err != nil {
+			return nil, fmt.Errorf("extracting CNI from cluster config of %s: %w", target.Name, err)
+		}
+	}
+
+	return job, nil
+}
+
+// CachedArtefacts returns the identifiers of the cached artefacts the target requires, the same which
+// WarmCache downloads for it. Targets without Kubernetes do not require any.
+func CachedArtefacts(target *WarmTarget) ([]string, error) {
+	job, err := newWarmJob(target)
+	if err != nil || job == nil {
+		return nil, err
+	}
+
+	identifiers, err := kubernetes.ArtefactIdentifiers(job.arch, job.version, job.cni, job.multusEnabled)
+	if err != nil {
+		return nil, fmt.Errorf("resolving the artefacts of %s: %w", target.Name, err)
+	}
+
+	return identifiers, nil
+}
+
+// groupWarmJobs groups the jobs by Kubernetes version and architecture, the jobs of which share cached artefacts
+// and are therefore not run concurrently.
+func groupWarmJobs(jobs []*warmJob) [][]*warmJob {
+	var groups [][]*warmJob
+
+	for _, job := range jobs {
+		index := slices.IndexFunc(groups, func(group []*warmJob) bool {
+			return group[0].arch == job.arch && group[0].version == job.version
+		})
+		if index >= 0 {
+			groups[index] = append(groups[index], job)
+			continue
+		}
+
+		groups = append(groups, []*warmJob{job})
+	}
+
+	return groups
+}
+
+func runWarmJob(ctx context.Context, downloader artefactDownloader, rootBuildDir string, job *warmJob) error {
+	// The artefacts are also copied out of the cache, which is discarded afterwards
+	downloadDir, err := os.MkdirTemp(rootBuildDir, "warm-")
+	if err != nil {
+		return fmt.Errorf("creating download directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(downloadDir)
+	}()
+
+	installPath := filepath.Join(downloadDir, "install")
+	imagesPath := filepath.Join(downloadDir, "images")
+
+	for _, path := range []string{installPath, imagesPath} {
+		if err = os.MkdirAll(path, os.ModePerm); err != nil {
+			return fmt.Errorf("creating download directory: %w", err)
+		}
+	}
+
+	log.AuditInfof("Warming the cache with the %s artefacts of %s...", job.version, strings.Join(job.targets, ", "))
+
+	if strings.Contains(job.version, image.KubernetesDistroRKE2) {
+		return downloader.DownloadRKE2Artefacts(ctx, job.arch, job.version, job.cni, job.multusEnabled, installPath, imagesPath)
+	}
+
+	return downloader.DownloadK3sArtefacts(ctx, job.arch, job.version, installPath, imagesPath)
+}
@@ -0,0 +1,162 @@
+package eib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// fakeArtefactDownloader caches a single artefact per download unless already cached, failing if the same version is
+// downloaded concurrently.
+type fakeArtefactDownloader struct {
+	cache *cache.Cache
+
+	mu        sync.Mutex
+	active    map[string]bool
+	downloads []string
+	failure   error
+}
+
+func (d *fakeArtefactDownloader) DownloadRKE2Artefacts(_ context.Context, _ image.Arch, version, cni string, multusEnabled bool, _, _ string) error {
+	return d.download(version, fmt.Sprintf("%s-%t", cni, multusEnabled))
+}
+
+func (d *fakeArtefactDownloader) DownloadK3sArtefacts(_ context.Context, _ image.Arch, version, _, _ string) error {
+	return d.download(version, "k3s")
+}
+
+func (d *fakeArtefactDownloader) download(version, artefact string) error {
+	d.mu.Lock()
+	if d.active[version] {
+		d.mu.Unlock()
+		return fmt.Errorf("version '%s' is already being downloaded", version)
+	}
+	d.active[version] = true
+	d.downloads = append(d.downloads, version+"/"+artefact)
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		delete(d.active, version)
+		d.mu.Unlock()
+	}()
+
+	if d.failure != nil {
+		return d.failure
+	}
+
+	if _, err := d.cache.Get(version + "/" + artefact); err == nil {
+		return nil
+	}
+
+	return d.cache.Put(version+"/"+artefact, strings.NewReader(version+artefact))
+}
+
+func warmTarget(name, version string) WarmTarget {
+	return WarmTarget{
+		Name:      name,
+		ConfigDir: "nonexistent",
+		Definition: &image.Definition{
+			Image:      image.Image{Arch: image.ArchTypeX86},
+			Kubernetes: image.Kubernetes{Version: version},
+		},
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	rootBuildDir := t.TempDir()
+
+	c, err := cache.New(rootBuildDir)
+	require.NoError(t, err)
+	require.NoError(t, c.Put("v1.30.3+k3s1/k3s", strings.NewReader("v1.30.3+k3s1k3s")))
+
+	downloader := &fakeArtefactDownloader{cache: c, active: map[string]bool{}}
+
+	targets := []WarmTarget{
+		warmTarget("k3s.yaml", "v1.30.3+k3s1"),
+		warmTarget("rke2.yaml", "v1.30.3+rke2r1"),
+		warmTarget("rke2-copy.yaml", "v1.30.3+rke2r1"),
+		warmTarget("k3s-next.yaml", "v1.31.1+k3s1"),
+		{Name: "os-only.yaml", Definition: &image.Definition{}},
+	}
+
+	result, err := warmCache(context.Background(), c, downloader, rootBuildDir, targets, 2)
+	require.NoError(t, err)
+
+	// Identical sets of artefacts are only downloaded once
+	assert.ElementsMatch(t, []string{"v1.30.3+k3s1/k3s", "v1.30.3+rke2r1/cilium-false", "v1.31.1+k3s1/k3s"}, downloader.downloads)
+
+	// Artefacts which were already cached are not reported as fetched
+	assert.Equal(t, []string{"v1.30.3+rke2r1/cilium-false", "v1.31.1+k3s1/k3s"}, result.Fetched)
+	assert.Equal(t, []string{"os-only.yaml"}, result.Skipped)
+	assert.Equal(t, 3, result.Entries)
+	assert.EqualValues(t, len("v1.30.3+k3s1k3s")+len("v1.30.3+rke2r1cilium-false")+len("v1.31.1+k3s1k3s"), result.Size)
+
+	// The temporary download directories are removed
+	entries, err := os.ReadDir(rootBuildDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, filepath.Base(cache.Dir(rootBuildDir)), entries[0].Name())
+}
+
+func TestWarmCache_DownloadFailure(t *testing.T) {
+	rootBuildDir := t.TempDir()
+
+	c, err := cache.New(rootBuildDir)
+	require.NoError(t, err)
+
+	downloader := &fakeArtefactDownloader{cache: c, active: map[string]bool{}, failure: fmt.Errorf("connection refused")}
+
+	targets := []WarmTarget{
+		warmTarget("first.yaml", "v1.30.3+k3s1"),
+		warmTarget("second.yaml", "v1.30.3+k3s1"),
+	}
+
+	result, err := warmCache(context.Background(), c, downloader, rootBuildDir, targets, 0)
+	require.EqualError(t, err, "downloading v1.30.3+k3s1 artefacts for first.yaml, second.yaml: connection refused")
+	assert.Nil(t, result)
+}
+
+func TestWarmCache_Canceled(t *testing.T) {
+	rootBuildDir := t.TempDir()
+
+	c, err := cache.New(rootBuildDir)
+	require.NoError(t, err)
+
+	downloader := &fakeArtefactDownloader{cache: c, active: map[string]bool{}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = warmCache(ctx, c, downloader, rootBuildDir, []WarmTarget{warmTarget("k3s.yaml", "v1.30.3+k3s1")}, 1)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, downloader.downloads)
+}
+
+func TestWarmCache_RootBuildDirMissing(t *testing.T) {
+	_, err := WarmCache(context.Background(), "", nil, 1)
+	require.EqualError(t, err, "root build directory not specified")
+}
+
+func TestCachedArtefacts(t *testing.T) {
+	target := warmTarget("k3s.yaml", "v1.30.3+k3s1")
+
+	identifiers, err := CachedArtefacts(&target)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1.30.3+k3s1/k3s-airgap-images-amd64.tar.zst", "v1.30.3+k3s1/k3s"}, identifiers)
+
+	target = warmTarget("os.yaml", "")
+
+	identifiers, err = CachedArtefacts(&target)
+	require.NoError(t, err)
+	assert.Empty(t, identifiers)
+}
@@ -4,3 +4,7 @@ var (
 	EdgeHelmRepository         = "https://suse-edge.github.io/charts"
 	ElementalPackageRepository = "https://download.opensuse.org/repositories/isv:/Rancher:/Elemental:/Maintenance:/5.5/standard/"
 )
+
+// SourceDateEpochVariable is the environment variable holding the fixed timestamp of a reproducible build,
+// see https://reproducible-builds.org/specs/source-date-epoch/
+const SourceDateEpochVariable = "SOURCE_DATE_EPOCH"
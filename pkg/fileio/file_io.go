@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -15,6 +17,8 @@ const (
 	ExecutablePerms os.FileMode = 0o744
 	// NonExecutablePerms are Linux permissions (rw-r--r--) for non-executable files (configs, RPMs, etc.)
 	NonExecutablePerms os.FileMode = 0o644
+	// PrivatePerms are Linux permissions (rw-------) for sensitive files (private keys, etc.)
+	PrivatePerms os.FileMode = 0o600
 )
 
 func CopyFile(src string, dest string, perms os.FileMode) error {
@@ -130,3 +134,23 @@ func createFileWithPerms(dest string, perms os.FileMode) (*os.File, error) {
 
 	return file, nil
 }
+
+// SetModTimes sets the access and modification times of the given directory and everything beneath it.
+// Symbolic links are not followed and their own times are left untouched.
+func SetModTimes(root string, t time.Time) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		if err = os.Chtimes(path, t, t); err != nil {
+			return fmt.Errorf("setting times of '%s': %w", path, err)
+		}
+
+		return nil
+	})
+}
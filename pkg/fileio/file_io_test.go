@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -246,3 +247,30 @@ func assertDir(t *testing.T, dirPath string, expectedFileNames []string, expecte
 
 	assert.Equal(t, expectedFileNames, fileNames)
 }
+
+func TestSetModTimes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "eib-set-mod-times-test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	nestedDir := filepath.Join(tmpDir, "nested")
+	require.NoError(t, os.Mkdir(nestedDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "script.sh"), []byte("#!/bin/bash"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(nestedDir, "artefact"), []byte("data"), 0o600))
+	require.NoError(t, os.Symlink("/does/not/exist", filepath.Join(tmpDir, "dangling")))
+
+	sourceDate := time.Unix(1700000000, 0)
+
+	require.NoError(t, SetModTimes(tmpDir, sourceDate))
+
+	for _, path := range []string{tmpDir, nestedDir, filepath.Join(tmpDir, "script.sh"), filepath.Join(nestedDir, "artefact")} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, sourceDate.Equal(info.ModTime()), "unexpected modification time of %s", path)
+	}
+}
+
+func TestSetModTimesMissingRoot(t *testing.T) {
+	err := SetModTimes("does-not-exist", time.Unix(0, 0))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
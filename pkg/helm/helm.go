@@ -17,6 +17,7 @@ import (
 
 const (
 	templateLogFileName   = "helm-template.log"
+	showCRDsLogFileName   = "helm-show-crds.log"
 	pullLogFileName       = "helm-pull.log"
 	repoAddLogFileName    = "helm-repo-add.log"
 	registryLoginFileName = "helm-registry-login.log"
@@ -268,6 +269,42 @@ func templateCommand(chart, repository, version, valuesFilePath, kubeVersion, ta
 	return cmd
 }
 
+// ShowCRDs returns the custom resource definitions in the 'crds' directories of the chart and its subcharts.
+func (h *Helm) ShowCRDs(chartPath string) (string, error) {
+	logFile := filepath.Join(h.outputDir, showCRDsLogFileName)
+
+	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
+	if err != nil {
+		return "", fmt.Errorf("opening log file: %w", err)
+	}
+	defer func() {
+		if err = file.Close(); err != nil {
+			zap.S().Warnf("Closing %s file failed: %s", logFile, err)
+		}
+	}()
+
+	crdsBuffer := new(strings.Builder)
+	cmd := showCRDsCommand(chartPath, io.MultiWriter(file, crdsBuffer), file)
+
+	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
+		return "", fmt.Errorf("writing command prefix to log file: %w", err)
+	}
+
+	if err = cmd.Run(); err != nil {
+		return "", fmt.Errorf("executing command: %w", err)
+	}
+
+	return crdsBuffer.String(), nil
+}
+
+func showCRDsCommand(chartPath string, stdout, stderr io.Writer) *exec.Cmd {
+	cmd := exec.Command("helm", "show", "crds", chartPath)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	return cmd
+}
+
 func parseChartContents(chartContents string) ([]map[string]any, error) {
 	var resources []map[string]any
 
@@ -562,6 +562,17 @@ func TestTemplateCommand(t *testing.T) {
 	}
 }
 
+func TestShowCRDsCommand(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	cmd := showCRDsCommand("/build/helm/cert-manager-v1.14.2.tgz", &stdout, &stderr)
+
+	assert.Equal(t, []string{"helm", "show", "crds", "/build/helm/cert-manager-v1.14.2.tgz"}, cmd.Args)
+	assert.Equal(t, &stdout, cmd.Stdout)
+	assert.Equal(t, &stderr, cmd.Stderr)
+}
+
 func TestParseChartContents_InvalidPayload(t *testing.T) {
 	contents := `---
 # Source: some-invalid.yaml
@@ -7,8 +7,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
-	"github.com/schollz/progressbar/v3"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"go.uber.org/zap"
 )
@@ -18,28 +18,37 @@ import (
 // Optionally provide an additional cache writer in cases where the pending download
 // must be stored to other locations alongside the given path.
 func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error {
+	_, err := downloadFile(ctx, url, path, cache, "")
+	return err
+}
+
+// downloadFile reports the progress of the download prefixed by the given string and
+// returns the number of bytes downloaded.
+func downloadFile(ctx context.Context, url, path string, cache io.Writer, prefix string) (int64, error) {
 	filename := filepath.Base(path)
 
 	zap.S().Infof("Downloading file '%s' from '%s' to '%s'...", filename, url, filepath.Dir(path))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return 0, fmt.Errorf("creating request: %w", err)
 	}
 
+	started := time.Now()
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("executing request: %w", err)
+		return 0, fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	file, err := os.Create(path)
 	if err != nil {
-		return fmt.Errorf("creating file: %w", err)
+		return 0, fmt.Errorf("creating file: %w", err)
 	}
 	defer file.Close()
 
@@ -50,22 +59,39 @@ func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error
 		writers = append(writers, cache)
 	}
 
-	message := fmt.Sprintf("Downloading file: %s", filename)
+	message := fmt.Sprintf("%sDownloading file: %s", prefix, filename)
 
-	if resp.ContentLength == -1 {
+	switch {
+	case !progressReported, progressBarsEnabled && resp.ContentLength == -1:
 		// Only audit the message since progress bars of unknown length
 		// (i.e. spinners) are not properly rendered.
 		log.Audit(message)
-	} else {
-		bar := progressbar.DefaultBytes(resp.ContentLength, message)
-		writers = append(writers, bar)
+	case progressBarsEnabled:
+		writers = append(writers, newProgressBar(resp.ContentLength, message))
+	default:
+		log.Audit(message)
+		writers = append(writers, newProgressLogger(resp.ContentLength, message))
 	}
 
-	if _, err = io.Copy(io.MultiWriter(writers...), resp.Body); err != nil {
-		return fmt.Errorf("storing response: %w", err)
+	var body io.Reader = resp.Body
+	if limiter := bandwidthLimiter(ctx); limiter != nil {
+		body = newThrottledReader(ctx, body, limiter)
+	}
+
+	written, err := io.Copy(io.MultiWriter(writers...), body)
+	if err != nil {
+		return 0, fmt.Errorf("storing response: %w", err)
 	}
 
 	zap.S().Infof("Downloading file '%s' completed", filename)
 
-	return nil
+	RecordDownload(ctx, DownloadRecord{
+		Artifact: filename,
+		Source:   url,
+		Bytes:    written,
+		Started:  started,
+		Finished: time.Now(),
+	})
+
+	return written, nil
 }
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// DownloadRecord describes the timing of a completed artifact download.
+type DownloadRecord struct {
+	// Artifact names the downloaded artifact, e.g. the file name or the container image reference.
+	Artifact string
+	// Source is the location the artifact was downloaded from.
+	Source   string
+	Bytes    int64
+	Started  time.Time
+	Finished time.Time
+}
+
+// Duration is how long the download took.
+func (r DownloadRecord) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
+
+// downloadProfile collects the records of the downloads, which may complete concurrently.
+type downloadProfile struct {
+	mu      sync.Mutex
+	records []DownloadRecord
+}
+
+// profileKey is the context key of the profile recording the downloads made with the context.
+type profileKey struct{}
+
+// WithDownloadProfile returns a copy of the context recording the timing of all downloads made with it,
+// discarding the records of any parent context.
+func WithDownloadProfile(ctx context.Context) context.Context {
+	return context.WithValue(ctx, profileKey{}, &downloadProfile{})
+}
+
+// downloadProfileOf returns the profile of the context, nil if downloads are not profiled.
+func downloadProfileOf(ctx context.Context) *downloadProfile {
+	profile, _ := ctx.Value(profileKey{}).(*downloadProfile)
+	return profile
+}
+
+// RecordDownload adds the record of a download which did not go through this package (e.g. a container
+// image pull), ignored unless the downloads of the context are profiled.
+func RecordDownload(ctx context.Context, record DownloadRecord) {
+	profile := downloadProfileOf(ctx)
+	if profile == nil {
+		return
+	}
+
+	profile.mu.Lock()
+	defer profile.mu.Unlock()
+
+	profile.records = append(profile.records, record)
+}
+
+// DownloadRecords returns the records of the downloads completed with the context since profiling started,
+// ordered by the time they started.
+func DownloadRecords(ctx context.Context) []DownloadRecord {
+	profile := downloadProfileOf(ctx)
+	if profile == nil {
+		return nil
+	}
+
+	profile.mu.Lock()
+	defer profile.mu.Unlock()
+
+	records := slices.Clone(profile.records)
+	slices.SortStableFunc(records, func(a, b DownloadRecord) int {
+		return a.Started.Compare(b.Started)
+	})
+
+	return records
+}
+
+// FormatDownloadSummary lists the downloads from the slowest to the fastest, along with their size and
+// transfer rate.
+func FormatDownloadSummary(records []DownloadRecord) string {
+	sorted := slices.Clone(records)
+	slices.SortStableFunc(sorted, func(a, b DownloadRecord) int {
+		return cmp.Compare(b.Duration(), a.Duration())
+	})
+
+	var sb strings.Builder
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ARTIFACT\tDURATION\tSIZE\tRATE")
+	for _, record := range sorted {
+		duration := record.Duration()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s/s\n", record.Artifact, duration.Round(time.Millisecond),
+			formatBytes(record.Bytes), formatBytes(int64(transferRate(record.Bytes, duration))))
+	}
+	_ = w.Flush()
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
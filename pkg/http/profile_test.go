@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileDownloads(t *testing.T) {
+	contents := strings.Repeat("x", 4096)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(contents))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	ctx := WithDownloadProfile(context.Background())
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = DownloadFile(ctx, server.URL, filepath.Join(dir, fmt.Sprintf("file-%d", i)), nil)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	records := DownloadRecords(ctx)
+	require.Len(t, records, len(errs))
+
+	var artifacts []string
+	for i, record := range records {
+		artifacts = append(artifacts, record.Artifact)
+		assert.Equal(t, server.URL, record.Source)
+		assert.Equal(t, int64(len(contents)), record.Bytes)
+		assert.False(t, record.Finished.Before(record.Started))
+
+		if i > 0 {
+			assert.False(t, record.Started.Before(records[i-1].Started))
+		}
+	}
+	assert.ElementsMatch(t, []string{"file-0", "file-1", "file-2", "file-3", "file-4"}, artifacts)
+}
+
+func TestProfileDownloads_Disabled(t *testing.T) {
+	ctx := context.Background()
+
+	RecordDownload(ctx, DownloadRecord{Artifact: "nginx:1.25"})
+
+	assert.Nil(t, DownloadRecords(ctx))
+}
+
+func TestProfileDownloads_Restarted(t *testing.T) {
+	ctx := WithDownloadProfile(context.Background())
+
+	RecordDownload(ctx, DownloadRecord{Artifact: "nginx:1.25"})
+	restarted := WithDownloadProfile(ctx)
+
+	assert.Empty(t, DownloadRecords(restarted))
+	assert.Len(t, DownloadRecords(ctx), 1)
+}
+
+func TestFormatDownloadSummary(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	records := []DownloadRecord{
+		{Artifact: "rke2-images.linux-amd64.tar.zst", Bytes: 20 << 20, Started: started, Finished: started.Add(2 * time.Second)},
+		{Artifact: "docker.io/library/nginx:1.25", Bytes: 40 << 20, Started: started, Finished: started.Add(8 * time.Second)},
+		{Artifact: "install.sh", Bytes: 512, Started: started, Finished: started.Add(250 * time.Millisecond)},
+	}
+
+	expected := "" +
+		"ARTIFACT                         DURATION  SIZE      RATE\n" +
+		"docker.io/library/nginx:1.25     8s        40.0 MiB  5.0 MiB/s\n" +
+		"rke2-images.linux-amd64.tar.zst  2s        20.0 MiB  10.0 MiB/s\n" +
+		"install.sh                       250ms     512 B     2.0 KiB/s"
+
+	assert.Equal(t, expected, FormatDownloadSummary(records))
+}
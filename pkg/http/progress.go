@@ -0,0 +1,181 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
+)
+
+var (
+	// progressBarsEnabled renders progress bars only when they can be displayed properly.
+	progressBarsEnabled = isTerminal(os.Stdout)
+	// progressReported indicates whether download progress is reported on the console at all.
+	progressReported = true
+	// progressInterval is how often the progress is reported when progress bars are not rendered.
+	progressInterval = 10 * time.Second
+)
+
+// DisableProgress stops the download progress from being reported, leaving only a single message per download.
+func DisableProgress() {
+	progressReported = false
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func newProgressBar(size int64, message string) *progressbar.ProgressBar {
+	// Same as progressbar.DefaultBytes, but written to stdout along with the rest of the console output
+	return progressbar.NewOptions64(
+		size,
+		progressbar.OptionSetDescription(message),
+		progressbar.OptionSetWriter(os.Stdout),
+		progressbar.OptionShowBytes(true),
+		progressbar.OptionSetWidth(10),
+		progressbar.OptionThrottle(65*time.Millisecond),
+		progressbar.OptionShowCount(),
+		progressbar.OptionOnCompletion(func() {
+			fmt.Fprint(os.Stdout, "\n")
+		}),
+		progressbar.OptionSpinnerType(14),
+		progressbar.OptionFullWidth(),
+		progressbar.OptionSetRenderBlankState(true),
+	)
+}
+
+// progressLogger periodically reports the progress of a download in place of a progress bar,
+// which would otherwise clutter non-interactive output (e.g. CI logs).
+type progressLogger struct {
+	message    string
+	size       int64
+	written    int64
+	started    time.Time
+	lastReport time.Time
+}
+
+func newProgressLogger(size int64, message string) *progressLogger {
+	now := time.Now()
+
+	return &progressLogger{
+		message:    message,
+		size:       size,
+		started:    now,
+		lastReport: now,
+	}
+}
+
+func (p *progressLogger) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+
+	if now := time.Now(); now.Sub(p.lastReport) >= progressInterval {
+		p.lastReport = now
+		log.AuditInfof("%s: %s", p.message, p.status(now))
+	}
+
+	return len(b), nil
+}
+
+func (p *progressLogger) status(now time.Time) string {
+	rate := transferRate(p.written, now.Sub(p.started))
+
+	if p.size <= 0 {
+		return fmt.Sprintf("%s (%s/s)", formatBytes(p.written), formatBytes(int64(rate)))
+	}
+
+	percent := p.written * 100 / p.size
+	if rate == 0 {
+		return fmt.Sprintf("%s of %s (%d%%)", formatBytes(p.written), formatBytes(p.size), percent)
+	}
+
+	remaining := time.Duration(float64(p.size-p.written) / rate * float64(time.Second))
+	return fmt.Sprintf("%s of %s (%d%%, %s/s, %s remaining)",
+		formatBytes(p.written), formatBytes(p.size), percent, formatBytes(int64(rate)), remaining.Round(time.Second))
+}
+
+// Batch reports the aggregate progress of a series of related downloads, such as the
+// artefacts of a Kubernetes distribution. It must not be used by concurrent downloads.
+type Batch struct {
+	total     int
+	completed int
+	bytes     int64
+	started   time.Time
+}
+
+// NewBatch creates a batch of the given number of downloads.
+func NewBatch(total int) *Batch {
+	return &Batch{
+		total:   total,
+		started: time.Now(),
+	}
+}
+
+// DownloadFile downloads a file as part of the batch, see DownloadFile.
+func (b *Batch) DownloadFile(ctx context.Context, url, path string, cache io.Writer) error {
+	prefix := fmt.Sprintf("(%d/%d) ", b.completed+1, b.total)
+
+	written, err := downloadFile(ctx, url, path, cache, prefix)
+	if err != nil {
+		return err
+	}
+
+	b.bytes += written
+	b.completed++
+
+	return nil
+}
+
+// Skip marks a download of the batch as not needed (e.g. when the file is already cached).
+func (b *Batch) Skip() {
+	b.completed++
+}
+
+// Done reports the totals of the downloads in the batch.
+func (b *Batch) Done() {
+	if b.bytes == 0 {
+		return
+	}
+
+	elapsed := time.Since(b.started)
+	message := fmt.Sprintf("Downloaded %s in %s (%s/s).",
+		formatBytes(b.bytes), elapsed.Round(time.Second), formatBytes(int64(transferRate(b.bytes, elapsed))))
+
+	if progressReported {
+		log.AuditInfo(message)
+	} else {
+		zap.S().Info(message)
+	}
+}
+
+func transferRate(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(bytes) / elapsed.Seconds()
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:                 "0 B",
+		1023:              "1023 B",
+		1024:              "1.0 KiB",
+		1536:              "1.5 KiB",
+		5 * 1024 * 1024:   "5.0 MiB",
+		3 << 30:           "3.0 GiB",
+		1<<40 + 512*1<<30: "1.5 TiB",
+	}
+
+	for bytes, expected := range tests {
+		assert.Equal(t, expected, formatBytes(bytes))
+	}
+}
+
+func TestProgressLoggerStatus(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := started.Add(10 * time.Second)
+
+	tests := map[string]struct {
+		size     int64
+		written  int64
+		now      time.Time
+		expected string
+	}{
+		"Known size": {
+			size:     100 << 20,
+			written:  25 << 20,
+			now:      now,
+			expected: "25.0 MiB of 100.0 MiB (25%, 2.5 MiB/s, 30s remaining)",
+		},
+		"Unknown size": {
+			size:     -1,
+			written:  25 << 20,
+			now:      now,
+			expected: "25.0 MiB (2.5 MiB/s)",
+		},
+		"Nothing written": {
+			size:     100 << 20,
+			now:      now,
+			expected: "0 B of 100.0 MiB (0%)",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := &progressLogger{
+				size:    test.size,
+				written: test.written,
+				started: started,
+			}
+
+			assert.Equal(t, test.expected, p.status(test.now))
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	contents := strings.Repeat("x", 4096)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(contents))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	defaultInterval := progressInterval
+	progressInterval = 0
+	defer func() {
+		progressInterval = defaultInterval
+	}()
+
+	batch := NewBatch(3)
+	batch.Skip()
+
+	require.NoError(t, batch.DownloadFile(context.Background(), server.URL, filepath.Join(dir, "first"), nil))
+	require.NoError(t, batch.DownloadFile(context.Background(), server.URL, filepath.Join(dir, "second"), nil))
+
+	batch.Done()
+
+	assert.Equal(t, 3, batch.completed)
+	assert.Equal(t, int64(2*len(contents)), batch.bytes)
+
+	for _, name := range []string{"first", "second"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, contents, string(data))
+	}
+}
+
+func TestBatch_DownloadFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	batch := NewBatch(1)
+
+	err := batch.DownloadFile(context.Background(), server.URL, filepath.Join(t.TempDir(), "missing"), nil)
+	require.EqualError(t, err, "unexpected status code: 404")
+
+	assert.Zero(t, batch.completed)
+	assert.Zero(t, batch.bytes)
+}
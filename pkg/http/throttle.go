@@ -0,0 +1,147 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	bandwidthRegex = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMG]i?B|B)(?:/s)?$`)
+
+	bandwidthUnits = map[string]float64{
+		"B":   1,
+		"KB":  1000,
+		"MB":  1000 * 1000,
+		"GB":  1000 * 1000 * 1000,
+		"KIB": 1024,
+		"MIB": 1024 * 1024,
+		"GIB": 1024 * 1024 * 1024,
+	}
+)
+
+// ParseBandwidth parses a bandwidth given in bytes per second with an optional decimal (KB, MB, GB)
+// or binary (KiB, MiB, GiB) unit prefix, e.g. "10MB/s", returning the number of bytes per second.
+func ParseBandwidth(value string) (int64, error) {
+	matches := bandwidthRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid bandwidth '%s', expected a value such as '10MB/s' or '512KiB/s'", value)
+	}
+
+	amount, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bandwidth '%s': %w", value, err)
+	}
+
+	bytesPerSecond := int64(amount * bandwidthUnits[strings.ToUpper(matches[2])])
+	if bytesPerSecond <= 0 {
+		return 0, fmt.Errorf("invalid bandwidth '%s', the limit must be at least 1 B/s", value)
+	}
+
+	return bytesPerSecond, nil
+}
+
+// FormatBandwidth describes the given bytes per second in a human-readable way.
+func FormatBandwidth(bytesPerSecond int64) string {
+	return fmt.Sprintf("%s/s", formatBytes(bytesPerSecond))
+}
+
+// bandwidthKey is the context key of the limiter shared by the downloads made with the context.
+type bandwidthKey struct{}
+
+// WithBandwidthLimit returns a copy of the context limiting the aggregate throughput of all downloads made
+// with it, including concurrent ones, to the given number of bytes per second. A limit of zero removes any
+// limit set by a parent context.
+func WithBandwidthLimit(ctx context.Context, bytesPerSecond int64) context.Context {
+	if bytesPerSecond <= 0 {
+		return context.WithValue(ctx, bandwidthKey{}, (*limiter)(nil))
+	}
+
+	return context.WithValue(ctx, bandwidthKey{}, newLimiter(bytesPerSecond))
+}
+
+// bandwidthLimiter returns the limiter of the context, nil if downloads are unlimited.
+func bandwidthLimiter(ctx context.Context) *limiter {
+	l, _ := ctx.Value(bandwidthKey{}).(*limiter)
+	return l
+}
+
+// limiter is a token bucket shared between the throttled readers, refilled at the configured rate
+// and holding at most a second worth of tokens.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(bytesPerSecond int64) *limiter {
+	return &limiter{
+		rate: float64(bytesPerSecond),
+		last: time.Now(),
+	}
+}
+
+// reserve takes the given number of tokens, returning how long to wait before they are available.
+func (l *limiter) reserve(n int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.rate)
+	l.last = now
+	l.tokens -= float64(n)
+
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// burst is the largest read the limiter allows at once.
+func (l *limiter) burst() int {
+	return max(int(l.rate), 1)
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *limiter
+}
+
+func newThrottledReader(ctx context.Context, reader io.Reader, l *limiter) *throttledReader {
+	return &throttledReader{
+		ctx:     ctx,
+		reader:  reader,
+		limiter: l,
+	}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > t.limiter.burst() {
+		p = p[:t.limiter.burst()]
+	}
+
+	n, err := t.reader.Read(p)
+	if n <= 0 {
+		return n, err
+	}
+
+	if delay := t.limiter.reserve(n, time.Now()); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-t.ctx.Done():
+			return n, t.ctx.Err()
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBandwidth(t *testing.T) {
+	tests := map[string]int64{
+		"10MB/s":   10 * 1000 * 1000,
+		"512KiB/s": 512 * 1024,
+		"1.5GiB/s": 3 * 512 * 1024 * 1024,
+		"100 KB/s": 100 * 1000,
+		"2MiB":     2 * 1024 * 1024,
+		"800B/s":   800,
+	}
+
+	for value, expected := range tests {
+		bytesPerSecond, err := ParseBandwidth(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, bytesPerSecond, value)
+	}
+}
+
+func TestParseBandwidth_Invalid(t *testing.T) {
+	for _, value := range []string{"", "fast", "10", "10Mb/s", "10MB/s per link", "-1MB/s", "0MB/s", "10TB/s"} {
+		_, err := ParseBandwidth(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLimiterReserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := &limiter{rate: 1000, last: now}
+
+	// The bucket starts empty
+	assert.Equal(t, 500*time.Millisecond, l.reserve(500, now))
+
+	// Concurrent readers share the same bucket
+	assert.Equal(t, time.Second, l.reserve(500, now))
+
+	// The bucket is refilled over time, but never beyond a second worth of tokens
+	assert.Equal(t, time.Duration(0), l.reserve(1000, now.Add(3*time.Second)))
+	assert.Equal(t, 100*time.Millisecond, l.reserve(1100, now.Add(4*time.Second)))
+}
+
+func TestThrottledReader(t *testing.T) {
+	data := bytes.Repeat([]byte("a"), 3000)
+	l := newLimiter(10000)
+
+	started := time.Now()
+	read, err := io.ReadAll(newThrottledReader(context.Background(), bytes.NewReader(data), l))
+	require.NoError(t, err)
+
+	assert.Equal(t, data, read)
+	assert.GreaterOrEqual(t, time.Since(started), 250*time.Millisecond)
+}
+
+func TestThrottledReader_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := io.ReadAll(newThrottledReader(ctx, strings.NewReader("some data"), newLimiter(1)))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWithBandwidthLimit(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, bandwidthLimiter(ctx))
+
+	limited := WithBandwidthLimit(ctx, 1024)
+	require.NotNil(t, bandwidthLimiter(limited))
+	assert.Equal(t, float64(1024), bandwidthLimiter(limited).rate)
+
+	assert.Nil(t, bandwidthLimiter(WithBandwidthLimit(limited, 0)))
+	assert.Nil(t, bandwidthLimiter(ctx))
+}
+
+func TestDownloadFile_ConcurrentBandwidthLimits(t *testing.T) {
+	contents := strings.Repeat("x", 2000)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(contents))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	// Concurrent builds limit their downloads independently of each other
+	limits := []int64{0, 4000}
+	durations := make([]time.Duration, len(limits))
+	errs := make([]error, len(limits))
+
+	var wg sync.WaitGroup
+	for i, limit := range limits {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx := WithBandwidthLimit(context.Background(), limit)
+			started := time.Now()
+			errs[i] = DownloadFile(ctx, server.URL, filepath.Join(dir, fmt.Sprintf("file-%d", i)), nil)
+			durations[i] = time.Since(started)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.Less(t, durations[0], 250*time.Millisecond)
+	assert.GreaterOrEqual(t, durations[1], 250*time.Millisecond)
+}
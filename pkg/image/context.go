@@ -1,10 +1,19 @@
 package image
 
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
 type HelmClient interface {
 	AddRepo(repository *HelmRepository) error
 	RegistryLogin(repository *HelmRepository) error
 	Pull(chart string, repository *HelmRepository, version, destDir string) (string, error)
 	Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error)
+	ShowCRDs(chartPath string) (string, error)
 }
 
 type LocalRPMConfig struct {
@@ -25,4 +34,168 @@ type Context struct {
 	ArtefactsDir string
 	// ImageDefinition contains the image definition properties.
 	ImageDefinition *Definition
+	// BaseImageOverride is an optional path to a local base image used instead of the one under the base images directory.
+	BaseImageOverride string
+	// PreserveScriptPermissions disables making custom scripts executable when they are copied into the build.
+	PreserveScriptPermissions bool
+	// AllowArchMismatch reports a base image built for a different architecture than requested as a warning.
+	AllowArchMismatch bool
+	// AllowCriticalRemovals reports the removal of critical system paths from the base image as a warning.
+	AllowCriticalRemovals bool
+	// ForbidLatestTags reports embedded images using the mutable 'latest' tag as an error rather than a warning.
+	ForbidLatestTags bool
+	// SkipChartImageCheck disables checking the images referenced by Helm chart values against the images pinned by digest.
+	SkipChartImageCheck bool
+	// CombustionOnly packages the combustion content into an ISO instead of assembling the image from the base image.
+	CombustionOnly bool
+	// SkipSpaceCheck disables checking that the build and output directories have the space the build is estimated to require.
+	SkipSpaceCheck bool
+	// ToolArgs are the unsupported options forwarded to the tooling assembling the image through --tool-arg,
+	// keyed by their allowlisted names.
+	ToolArgs map[string]string
+	// SourceDate is the fixed timestamp applied to the generated content for a reproducible build.
+	// The zero value disables reproducible timestamps.
+	SourceDate time.Time
+	// DefinitionHash identifies the image definition as provided, before any build time additions are made to it.
+	DefinitionHash string
+	// RootMountOptions are the mount options of the root file system found in the assembled image,
+	// if these were customized.
+	RootMountOptions string
+	// SignedKernels and SignedModules are the paths of the kernel images and kernel modules signed for Secure Boot
+	// while assembling the image.
+	SignedKernels []string
+	SignedModules []string
+	// RPMRepoPath is the repository of the resolved packages, empty if no packages were resolved.
+	RPMRepoPath string
+	// Qcow2VirtualSize and Qcow2ActualSize are the disk size presented to the guest and the size of the file of the
+	// converted qcow2 image, in bytes.
+	Qcow2VirtualSize int64
+	Qcow2ActualSize  int64
+	// StrippedDocsKB is the space reclaimed by stripping the documentation of the base image, in KiB.
+	StrippedDocsKB int64
+	// KernelRelease is the release of the kernel pinned while assembling the image, as reported by 'uname -r'.
+	KernelRelease string
+	// CombustionISO is the path to the combustion ISO built in combustion only mode.
+	CombustionISO string
+	// SeedISOs are the cloud-init seed ISOs built alongside the image.
+	SeedISOs []SeedISOFile
+	// ImageArchives are the container image archives placed on the node.
+	ImageArchives []ImageArchive
+	// Certificates are the CA certificate files installed on the node, in the order they are installed.
+	Certificates []CertificateFile
+	// Overlays are the writable mounts over the read-only root file system of the node.
+	Overlays []OverlayMount
+	// RootSlots are the A/B root partitions found in the assembled raw image.
+	RootSlots []RootSlot
+	// ResolvedPackages are the RPMs embedded in the image, i.e. the requested packages and side-loaded RPMs
+	// along with the dependencies missing from the base image.
+	ResolvedPackages []ResolvedPackage
+	// HelmChartCRDs are the custom resource definitions of Helm charts which are applied ahead of the releases.
+	HelmChartCRDs []HelmChartCRDs
+
+	// ctx is the context the build runs with, see SetContext.
+	ctx context.Context
+}
+
+// HelmChartCRDs are the custom resource definitions of a Helm chart which are applied ahead of its release.
+type HelmChartCRDs struct {
+	// Chart is the name of the chart.
+	Chart string
+	// Manifest is the name of the manifest holding the custom resource definitions.
+	Manifest string
+	// Names are the names of the custom resource definitions.
+	Names []string
+}
+
+// ResolvedPackage is an RPM embedded in the image by the dependency resolution.
+type ResolvedPackage struct {
+	// File is the name of the RPM file.
+	File string
+	// Size is the size of the RPM file in bytes.
+	Size int64
+}
+
+// RootSlot is one of the A/B root partitions of a raw image.
+type RootSlot struct {
+	// Name is either "a", the slot booted by default, or "b".
+	Name      string
+	Partition int
+	// Label is the file system label of the partition.
+	Label  string
+	SizeMB int64
+}
+
+// OverlayMount is a writable mount over a directory of the read-only root file system.
+type OverlayMount struct {
+	Path string
+	Type string
+	// Size limits the size of tmpfs mounts.
+	Size string
+	// UpperDir stores the changes of persistent overlays.
+	UpperDir string
+}
+
+// CertificateFile is a file of CA certificates installed in the trust store of the node.
+type CertificateFile struct {
+	// File is the name of the certificate file.
+	File string
+	// Subjects are the subjects of the certificates in the file, each following the CA it was issued by.
+	Subjects []string
+}
+
+// SeedISOFile is a cloud-init seed ISO built for a node.
+type SeedISOFile struct {
+	// Hostname is the hostname of the node.
+	Hostname string
+	// Path is the location of the ISO.
+	Path string
+}
+
+// ImageArchive is a container image archive placed on the node.
+type ImageArchive struct {
+	// Image is the reference of the archived container image.
+	Image string
+	// Path is the location of the archive on the node.
+	Path string
+	// Size is the size of the archive in bytes.
+	Size int64
+}
+
+// SetContext sets the context the build runs with. Its cancellation aborts the downloads in progress, which
+// are also subject to the download settings it carries (e.g. http.WithBandwidthLimit).
+func (c *Context) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// Context returns the context the build runs with, the background context unless set through SetContext.
+func (c *Context) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
+}
+
+// BaseImagePath returns the path to the base image the build is performed on.
+func (c *Context) BaseImagePath() string {
+	if c.BaseImageOverride != "" {
+		return c.BaseImageOverride
+	}
+
+	return filepath.Join(c.ImageConfigDir, "base-images", c.ImageDefinition.Image.BaseImage)
+}
+
+// baseImageReleaseRegex matches the product and its version in the conventional image names
+// (e.g. SLE-Micro.x86_64-5.5.0-Default-GM.raw or SL-Micro.x86_64-6.0-Base-GM.raw).
+var baseImageReleaseRegex = regexp.MustCompile(`^(SLE?)-Micro\.(?:x86_64|aarch64)-(\d+\.\d+)(?:[.-]|$)`)
+
+// BaseImageRelease returns the operating system release of a base image (e.g. "SL Micro 6.0") based on its
+// file name, or an empty string if the name does not follow the conventional naming.
+func BaseImageRelease(filename string) string {
+	match := baseImageReleaseRegex.FindStringSubmatch(filepath.Base(filename))
+	if match == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s Micro %s", match[1], match[2])
 }
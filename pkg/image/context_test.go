@@ -0,0 +1,25 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseImageRelease(t *testing.T) {
+	tests := map[string]string{
+		"SLE-Micro.x86_64-5.5.0-Default-SelfInstall-GM2.install.iso": "SLE Micro 5.5",
+		"SLE-Micro.x86_64-5.5.0-Default-GM.raw":                      "SLE Micro 5.5",
+		"SL-Micro.x86_64-6.0-Base-GM.raw":                            "SL Micro 6.0",
+		"/base-images/SL-Micro.aarch64-6.1-Base-RT-GM.raw":           "SL Micro 6.1",
+		"slemicro5.5.iso":               "",
+		"SL-Micro.x86_64-6-Base-GM.raw": "",
+		"base-image.iso":                "",
+	}
+
+	for filename, expected := range tests {
+		t.Run(filename, func(t *testing.T) {
+			assert.Equal(t, expected, BaseImageRelease(filename))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package image
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -14,6 +15,9 @@ const (
 	TypeISO = "iso"
 	TypeRAW = "raw"
 
+	OutputFormatRAW   = "raw"
+	OutputFormatQCOW2 = "qcow2"
+
 	ArchTypeX86 Arch = "x86_64"
 	ArchTypeARM Arch = "aarch64"
 
@@ -27,6 +31,20 @@ const (
 	CNITypeCilium = "cilium"
 	CNITypeCanal  = "canal"
 	CNITypeCalico = "calico"
+
+	InstallGateStrategyDelay   = "delay"
+	InstallGateStrategyCommand = "command"
+	InstallGateStrategyURL     = "url"
+
+	ContainerdConfigModeFull    = "full"
+	ContainerdConfigModePartial = "partial"
+
+	SudoPolicyNone     = "none"
+	SudoPolicyAll      = "all"
+	SudoPolicyCommands = "commands"
+
+	ScheduledJobBackendTimer = "timer"
+	ScheduledJobBackendCron  = "cron"
 )
 
 var (
@@ -56,24 +74,514 @@ func (a Arch) Short() string {
 }
 
 type Image struct {
-	ImageType       string `yaml:"imageType"`
-	Arch            Arch   `yaml:"arch"`
-	BaseImage       string `yaml:"baseImage"`
-	OutputImageName string `yaml:"outputImageName"`
+	ImageType          string             `yaml:"imageType"`
+	Arch               Arch               `yaml:"arch"`
+	BaseImage          string             `yaml:"baseImage"`
+	OutputImageName    string             `yaml:"outputImageName"`
+	OutputFormat       string             `yaml:"outputFormat"`
+	Qcow2Configuration Qcow2Configuration `yaml:"qcow2Configuration"`
+	RootFilesystem     RootFilesystem     `yaml:"rootFilesystem"`
+	// SeedISOs are the cloud-init seed ISOs built alongside the image, each providing the identity of a node.
+	SeedISOs []SeedISO `yaml:"seedISOs"`
+}
+
+// SeedISO is a cloud-init NoCloud seed ISO, which lets a single golden image boot as a specific node.
+type SeedISO struct {
+	// Hostname is the hostname of the node, which also names the ISO and serves as the cloud-init instance ID.
+	Hostname string `yaml:"hostname"`
+	// SSHKeys are the public keys authorized to log in as root on the node.
+	SSHKeys []string `yaml:"sshKeys"`
+	// Network is the static configuration of a network interface of the node, which is left to DHCP if omitted.
+	Network SeedNetwork `yaml:"network"`
+}
+
+// SeedNetwork is the static configuration of a network interface, written as a version 2 network configuration.
+type SeedNetwork struct {
+	// Interface is the name of the interface, e.g. "eth0".
+	Interface string `yaml:"interface"`
+	// MACAddress matches the interface by its MAC address, renaming it to Interface.
+	MACAddress string `yaml:"macAddress"`
+	// Addresses are the addresses of the interface in CIDR notation, DHCP is used if empty.
+	Addresses []string `yaml:"addresses"`
+	Gateway   string   `yaml:"gateway"`
+	DNS       []string `yaml:"dns"`
+}
+
+const RootFilesystemBtrfs = "btrfs"
+
+// RootFilesystem customizes how the root file system of raw images is mounted.
+type RootFilesystem struct {
+	// Type is the file system of the base image's root partition, which is btrfs for all supported base images.
+	Type string `yaml:"type"`
+	// MountOptions are merged into the options of the root file system in /etc/fstab, replacing conflicting ones.
+	MountOptions []string `yaml:"mountOptions"`
+}
+
+type Qcow2Configuration struct {
+	Compress bool `yaml:"compress"`
 }
 
 type OperatingSystem struct {
-	KernelArgs       []string               `yaml:"kernelArgs"`
-	Groups           []OperatingSystemGroup `yaml:"groups"`
-	Users            []OperatingSystemUser  `yaml:"users"`
-	Systemd          Systemd                `yaml:"systemd"`
-	Suma             Suma                   `yaml:"suma"`
-	Packages         Packages               `yaml:"packages"`
-	IsoConfiguration IsoConfiguration       `yaml:"isoConfiguration"`
-	RawConfiguration RawConfiguration       `yaml:"rawConfiguration"`
-	Time             Time                   `yaml:"time"`
-	Proxy            Proxy                  `yaml:"proxy"`
-	Keymap           string                 `yaml:"keymap"`
+	KernelArgs       KernelArgs                     `yaml:"kernelArgs"`
+	Groups           []OperatingSystemGroup         `yaml:"groups"`
+	Users            []OperatingSystemUser          `yaml:"users"`
+	Systemd          Systemd                        `yaml:"systemd"`
+	Suma             Suma                           `yaml:"suma"`
+	Packages         Packages                       `yaml:"packages"`
+	IsoConfiguration IsoConfiguration               `yaml:"isoConfiguration"`
+	RawConfiguration RawConfiguration               `yaml:"rawConfiguration"`
+	Time             Time                           `yaml:"time"`
+	Proxy            Proxy                          `yaml:"proxy"`
+	Keymap           string                         `yaml:"keymap"`
+	Hostname         string                         `yaml:"hostname"`
+	Autologin        Autologin                      `yaml:"autologin"`
+	BootValidation   BootValidation                 `yaml:"bootValidation"`
+	SSHD             SSHD                           `yaml:"sshd"`
+	Fail2ban         Fail2ban                       `yaml:"fail2ban"`
+	Audit            Audit                          `yaml:"audit"`
+	AppArmor         AppArmor                       `yaml:"apparmor"`
+	Networkd         Networkd                       `yaml:"networkd"`
+	NetworkProfiles  NetworkProfiles                `yaml:"networkProfiles"`
+	Podman           Podman                         `yaml:"podman"`
+	ImageArchives    ImageArchives                  `yaml:"imageArchives"`
+	AutoUpdate       AutoUpdate                     `yaml:"autoUpdate"`
+	Hosts            []HostEntry                    `yaml:"hosts"`
+	KnownHosts       []KnownHost                    `yaml:"knownHosts"`
+	Journald         Journald                       `yaml:"journald"`
+	Udev             Udev                           `yaml:"udev"`
+	PAM              PAM                            `yaml:"pam"`
+	ScheduledJobs    []ScheduledJob                 `yaml:"scheduledJobs"`
+	Logrotate        []LogRotation                  `yaml:"logrotate"`
+	Release          Release                        `yaml:"release"`
+	Remove           []string                       `yaml:"remove"`
+	StripDocs        StripDocs                      `yaml:"stripDocs"`
+	Directories      []Directory                    `yaml:"directories"`
+	Environment      map[string]EnvironmentVariable `yaml:"environment"`
+	ShellDefaults    ShellDefaults                  `yaml:"shellDefaults"`
+	DNS              DNS                            `yaml:"dns"`
+	Dnsmasq          Dnsmasq                        `yaml:"dnsmasq"`
+	Locales          Locales                        `yaml:"locales"`
+	MachineID        MachineID                      `yaml:"machineID"`
+	GPUDrivers       GPUDrivers                     `yaml:"gpuDrivers"`
+	Zram             Zram                           `yaml:"zram"`
+	Kdump            Kdump                          `yaml:"kdump"`
+	ReadOnlyRoot     ReadOnlyRoot                   `yaml:"readOnlyRoot"`
+	SecureBoot       SecureBoot                     `yaml:"secureBoot"`
+}
+
+const (
+	OverlayTypeTmpfs      = "tmpfs"
+	OverlayTypePersistent = "persistent"
+)
+
+// DocumentationDirs are the directories emptied from the base image when its documentation is stripped.
+var DocumentationDirs = []string{"/usr/share/doc", "/usr/share/man", "/usr/share/info"}
+
+// StripDocs removes the documentation, man and info pages of the base image while it is assembled.
+type StripDocs struct {
+	Enabled bool `yaml:"enabled"`
+	// Exclude lists the files and directories under the documentation directories which are kept.
+	Exclude []string `yaml:"exclude"`
+}
+
+// ReadOnlyRoot mounts the root file system read-only, with writable mounts over the directories which need to be
+// written to at runtime.
+type ReadOnlyRoot struct {
+	Enabled  bool      `yaml:"enabled"`
+	Overlays []Overlay `yaml:"overlays"`
+}
+
+// Overlay makes a directory of the read-only root file system writable.
+type Overlay struct {
+	Path string `yaml:"path"`
+	// Type is either tmpfs, for an empty directory which is discarded on reboot, or persistent, for an overlay
+	// of the directory whose changes are stored under /var.
+	Type string `yaml:"type"`
+	// Size limits the size of tmpfs mounts, e.g. "256m" or "10%" of the memory of the node.
+	Size string `yaml:"size"`
+}
+
+const (
+	LockdownIntegrity       = "integrity"
+	LockdownConfidentiality = "confidentiality"
+)
+
+// SecureBoot enrolls additional Machine Owner Keys (MOKs) on the node and signs the kernel and kernel modules
+// of the image with a key trusted through them, so that the image boots with Secure Boot enabled.
+type SecureBoot struct {
+	// MOKCertificates are the DER encoded certificates in the secure-boot directory whose enrollment is
+	// requested on first boot, confirmed in the MOK manager with the root password on the following boot.
+	MOKCertificates []string `yaml:"mokCertificates"`
+	// Signing signs the boot artefacts of the base image during the assembly of the image.
+	Signing SecureBootSigning `yaml:"signing"`
+	// Lockdown is the kernel lockdown mode enforced on boot, either integrity or confidentiality.
+	Lockdown string `yaml:"lockdown"`
+}
+
+// SecureBootSigning references the key pair in the secure-boot directory the boot artefacts are signed with.
+// The key never becomes part of the image.
+type SecureBootSigning struct {
+	// Key is the PEM encoded private key.
+	Key string `yaml:"key"`
+	// Certificate is the PEM encoded certificate of the key.
+	Certificate string `yaml:"certificate"`
+	// Kernel signs the kernel images, replacing their existing signatures.
+	Kernel bool `yaml:"kernel"`
+	// Modules signs the out-of-tree kernel modules (those under the updates and extra directories) which are
+	// not signed yet.
+	Modules bool `yaml:"modules"`
+}
+
+// Zram configures a compressed swap device in memory through zram-generator.
+type Zram struct {
+	// Size is the fixed size of the device, e.g. "4G".
+	Size DiskSize `yaml:"size"`
+	// Ratio is the size of the device as a fraction of the memory of the node, e.g. 0.5.
+	Ratio float64 `yaml:"ratio"`
+	// Algorithm is the compression algorithm, the kernel default is used if omitted.
+	Algorithm string `yaml:"algorithm"`
+}
+
+// KdumpDefaultSavePath is the directory kdump saves the dumps to unless configured otherwise.
+const KdumpDefaultSavePath = "/var/crash"
+
+// Kdump reserves memory for a crash kernel and enables kdump, which saves a dump of the memory of the node
+// when its kernel crashes.
+type Kdump struct {
+	// Memory is the memory reserved for the crash kernel, set as the value of the 'crashkernel' kernel argument,
+	// e.g. "512M" or "2G-64G:256M,64G-:512M".
+	Memory string `yaml:"memory"`
+	// SavePath is the directory the dumps are saved to, KdumpDefaultSavePath is used if omitted.
+	SavePath string `yaml:"savePath"`
+}
+
+// GPUDrivers installs GPU drivers, such as the NVIDIA drivers, along with the configuration of their kernel modules.
+type GPUDrivers struct {
+	// Version is the version of the driver, which is included in the build report.
+	Version string `yaml:"version"`
+	// Repository provides the driver packages. If omitted, they are resolved from the side-loaded RPMs
+	// and the repositories configured under 'packages'.
+	Repository AddRepo `yaml:"repository"`
+	// Packages are the driver packages, installed along with the other packages of the image.
+	Packages []string `yaml:"packages"`
+	// KernelModules configures the kernel modules of the driver.
+	KernelModules GPUKernelModules `yaml:"kernelModules"`
+}
+
+// GPUKernelModules configures how the kernel modules of a GPU driver are loaded on the node.
+type GPUKernelModules struct {
+	// Load lists the modules loaded at boot.
+	Load []string `yaml:"load"`
+	// Options maps modules to the parameters they are loaded with (e.g. "NVreg_EnableGpuFirmware=1").
+	Options map[string]string `yaml:"options"`
+	// Blacklist lists the modules prevented from being loaded, such as conflicting open source drivers.
+	Blacklist []string `yaml:"blacklist"`
+}
+
+// MachineID controls the /etc/machine-id of the node. At most one of the fields may be set.
+type MachineID struct {
+	// Clear empties the machine ID so that each node deployed from the image generates its own on the first boot.
+	Clear bool `yaml:"clear"`
+	// Value is a fixed machine ID of 32 lowercase hexadecimal characters.
+	Value string `yaml:"value"`
+	// Seed derives a fixed machine ID from the given string, producing the same ID in every build.
+	Seed string `yaml:"seed"`
+}
+
+// Locales restricts the locales available on the node, removing the compiled locales and message
+// translations of the base image and installed packages which are not kept.
+type Locales struct {
+	// Keep lists the kept locales (e.g. en_US.UTF-8). The base image's locales are kept if empty.
+	Keep []string `yaml:"keep"`
+	// Default is set as the system locale, defaulting to the first kept locale.
+	Default string `yaml:"default"`
+}
+
+const (
+	DNSOverTLSYes           = "yes"
+	DNSOverTLSOpportunistic = "opportunistic"
+	DNSOverTLSNo            = "no"
+)
+
+// DNS configures systemd-resolved as the resolver of the node, taking precedence over the servers provided by DHCP.
+type DNS struct {
+	// Servers are given as an address with an optional port and TLS server name (e.g. 9.9.9.9:853#dns.quad9.net).
+	Servers         []string `yaml:"servers"`
+	FallbackServers []string `yaml:"fallbackServers"`
+	// DNSOverTLS is one of "yes" (default), "opportunistic" or "no".
+	DNSOverTLS string `yaml:"dnsOverTLS"`
+}
+
+// Dnsmasq runs dnsmasq on the node, serving DHCP and DNS to the devices of the networks behind it.
+type Dnsmasq struct {
+	// Interfaces restricts dnsmasq to the given network interfaces, all interfaces are served if omitted.
+	Interfaces []string `yaml:"interfaces"`
+	// Domain is the local domain of the served devices, whose names are resolved from the leases.
+	Domain string `yaml:"domain"`
+	// Upstreams are the DNS servers queries are forwarded to, those of /etc/resolv.conf are used if omitted.
+	Upstreams    []string      `yaml:"upstreams"`
+	Ranges       []DHCPRange   `yaml:"ranges"`
+	StaticLeases []StaticLease `yaml:"staticLeases"`
+}
+
+// DHCPRange is a range of IPv4 addresses leased dynamically.
+type DHCPRange struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// LeaseTime is given in seconds or with a unit suffix (e.g. "12h"), the dnsmasq default of 1h is used if omitted.
+	LeaseTime string `yaml:"leaseTime"`
+}
+
+// StaticLease always leases the same IPv4 address to the device with the given MAC address.
+type StaticLease struct {
+	MAC      string `yaml:"mac"`
+	IP       string `yaml:"ip"`
+	Hostname string `yaml:"hostname"`
+}
+
+// EnvironmentVariable is a system wide environment variable written to /etc/environment.
+type EnvironmentVariable struct {
+	Value string `yaml:"value"`
+	// Sensitive keeps the value out of the build log.
+	Sensitive bool `yaml:"sensitive"`
+}
+
+// ShellDefaults are set for the login shells of all users through /etc/profile.d.
+type ShellDefaults struct {
+	// Editor is the command set as EDITOR and VISUAL, e.g. "vim".
+	Editor string `yaml:"editor"`
+	// Pager is the command set as PAGER, e.g. "less".
+	Pager string `yaml:"pager"`
+	// Environment are additional variables exported by login shells.
+	Environment map[string]string `yaml:"environment"`
+}
+
+// Directory is created on the node with the given ownership and permissions, typically as a host path
+// bind mounted into container workloads.
+type Directory struct {
+	Path string `yaml:"path"`
+	// Owner and Group are names or numeric IDs, defaulting to root.
+	Owner string `yaml:"owner"`
+	Group string `yaml:"group"`
+	// Mode is the octal permission mode, defaulting to 0755.
+	Mode string `yaml:"mode"`
+}
+
+// Release holds the user provided labels written to /etc/eib-release alongside the build identity.
+type Release struct {
+	Labels map[string]string `yaml:"labels"`
+}
+
+type ScheduledJob struct {
+	Name string `yaml:"name"`
+	// Schedule is a systemd calendar expression, or a crontab schedule when using the cron backend.
+	Schedule string `yaml:"schedule"`
+	// Command is run as root by /bin/sh each time the job is triggered.
+	Command string `yaml:"command"`
+	// Backend selects between systemd timers (default) and crontab entries.
+	Backend string `yaml:"backend"`
+}
+
+const (
+	LogRotationDaily   = "daily"
+	LogRotationWeekly  = "weekly"
+	LogRotationMonthly = "monthly"
+	LogRotationYearly  = "yearly"
+)
+
+// LogRotation rotates the log files of applications through a logrotate configuration file.
+type LogRotation struct {
+	Name string `yaml:"name"`
+	// Paths are absolute paths of the log files, which may contain glob patterns.
+	Paths []string `yaml:"paths"`
+	// Frequency is one of daily, weekly, monthly or yearly. If unset, files are rotated based on MaxSize alone,
+	// or at the default frequency of logrotate.
+	Frequency string `yaml:"frequency"`
+	// Rotate is the number of rotated files kept, defaulting to the logrotate default if zero.
+	Rotate int `yaml:"rotate"`
+	// MaxSize rotates files exceeding the given size, e.g. "100M".
+	MaxSize      string `yaml:"maxSize"`
+	Compress     bool   `yaml:"compress"`
+	CopyTruncate bool   `yaml:"copyTruncate"`
+}
+
+// Journald holds the journald settings written to a drop-in; unset settings keep the journald defaults.
+type Journald struct {
+	Storage           string `yaml:"storage"`
+	SystemMaxUse      string `yaml:"systemMaxUse"`
+	SystemMaxFileSize string `yaml:"systemMaxFileSize"`
+	RuntimeMaxUse     string `yaml:"runtimeMaxUse"`
+	MaxRetention      string `yaml:"maxRetention"`
+}
+
+// Autologin logs a user in on a console without authentication, e.g. on kiosk devices.
+type Autologin struct {
+	// User is the name of a user configured in the definition.
+	User string `yaml:"user"`
+	// TTY is the console the user is logged in on, tty1 if unset. Serial consoles such as ttyS0 are supported.
+	TTY string `yaml:"tty"`
+}
+
+// Console returns the TTY the user is logged in on, defaulting to tty1.
+func (a Autologin) Console() string {
+	if a.TTY == "" {
+		return "tty1"
+	}
+
+	return a.TTY
+}
+
+// HostEntry is a static mapping of an IP address to hostnames, appended to /etc/hosts.
+type HostEntry struct {
+	IP        string   `yaml:"ip"`
+	Hostnames []string `yaml:"hostnames"`
+}
+
+// KnownHost is a pinned SSH host key, added to the system-wide /etc/ssh/ssh_known_hosts.
+type KnownHost struct {
+	// Host is the hostname or IP address of the server, with the port in the '[host]:port' form if not 22.
+	Host string `yaml:"host"`
+	// Key is the public host key of the server in the '<type> <base64>' form of an OpenSSH public key.
+	Key string `yaml:"key"`
+}
+
+type SSHD struct {
+	// AllowedKeyTypes restricts the host key and public key authentication algorithms accepted by sshd.
+	AllowedKeyTypes []string `yaml:"allowedKeyTypes"`
+	// HostKeys lists the names of private host key files provided under the 'sshd' configuration directory.
+	HostKeys []string `yaml:"hostKeys"`
+	// Options are additional sshd configuration keywords and their values.
+	Options map[string]string `yaml:"options"`
+}
+
+// Fail2ban protects the node against brute-force attacks, banning the addresses which repeatedly fail to
+// authenticate with the services watched by the jails.
+type Fail2ban struct {
+	// BanTime, FindTime and MaxRetry are the defaults of all jails, the fail2ban defaults are used if omitted.
+	BanTime  string `yaml:"banTime"`
+	FindTime string `yaml:"findTime"`
+	MaxRetry int    `yaml:"maxRetry"`
+	// IgnoreIPs lists the addresses and networks which are never banned.
+	IgnoreIPs []string       `yaml:"ignoreIPs"`
+	Jails     []Fail2banJail `yaml:"jails"`
+}
+
+// Fail2banJail enables a jail of fail2ban, optionally overriding the defaults of the section.
+type Fail2banJail struct {
+	Name     string `yaml:"name"`
+	Port     string `yaml:"port"`
+	BanTime  string `yaml:"banTime"`
+	FindTime string `yaml:"findTime"`
+	MaxRetry int    `yaml:"maxRetry"`
+}
+
+type KernelArgs struct {
+	// Add lists the arguments appended to the kernel command line.
+	Add []string `yaml:"add"`
+	// Remove lists the arguments removed from the kernel command line of the base image. Entries without
+	// a value remove the argument regardless of its value.
+	Remove []string `yaml:"remove"`
+}
+
+// UnmarshalYAML accepts both the 'add'/'remove' mapping and a plain list of arguments to add,
+// which was the only supported format before removals were introduced.
+func (k *KernelArgs) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		return value.Decode(&k.Add)
+	}
+
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: kernelArgs must be either a list or a mapping", value.Line)
+	}
+
+	// Decoding a node does not inherit the strict field checking of the definition decoder
+	for i := 0; i < len(value.Content); i += 2 {
+		if key := value.Content[i].Value; key != "add" && key != "remove" {
+			return fmt.Errorf("line %d: field %s not found in type image.KernelArgs", value.Content[i].Line, key)
+		}
+	}
+
+	type plainKernelArgs KernelArgs
+	return value.Decode((*plainKernelArgs)(k))
+}
+
+type Audit struct {
+	// RuleFiles lists the names of audit rule files provided under the 'audit' configuration directory.
+	RuleFiles []string `yaml:"ruleFiles"`
+	// Rules are individual audit rules, as accepted by auditctl (e.g. '-w /etc/shadow -p wa -k shadow').
+	Rules []string `yaml:"rules"`
+}
+
+type AppArmor struct {
+	// Profiles lists the names of AppArmor profile files provided under the 'apparmor' configuration directory.
+	Profiles []string `yaml:"profiles"`
+}
+
+type Udev struct {
+	// Rules lists the names of udev rule files provided under the 'udev' configuration directory.
+	Rules []string `yaml:"rules"`
+}
+
+type PAM struct {
+	// ConfigFiles lists the names of PAM service configuration files provided under the 'pam' configuration directory.
+	ConfigFiles []string `yaml:"configFiles"`
+}
+
+type Networkd struct {
+	// ConfigFiles lists the names of .network and .netdev files provided under the 'networkd' configuration directory.
+	ConfigFiles []string `yaml:"configFiles"`
+}
+
+// DefaultNetworkProfileKernelArg is the kernel command line key selecting the network profile if none is configured.
+const DefaultNetworkProfileKernelArg = "eib.network_profile"
+
+// NetworkProfiles are alternative nmstate network configurations embedded in the image, one of which is applied on
+// first boot depending on a kernel command line argument.
+type NetworkProfiles struct {
+	// KernelArg is the kernel command line key whose value names the applied profile.
+	KernelArg string `yaml:"kernelArg"`
+	// Default is the profile applied when the kernel argument is not set.
+	Default string `yaml:"default"`
+	// Profiles lists the names of the profiles, each provided as a subdirectory of the 'network-profiles' directory.
+	Profiles []string `yaml:"profiles"`
+}
+
+// SelectionArg returns the kernel command line key selecting the profile, defaulting to DefaultNetworkProfileKernelArg.
+func (p *NetworkProfiles) SelectionArg() string {
+	if p.KernelArg == "" {
+		return DefaultNetworkProfileKernelArg
+	}
+
+	return p.KernelArg
+}
+
+type Podman struct {
+	// Images lists the container images which are embedded in the image and loaded into podman on the first boot.
+	Images []ContainerImage `yaml:"images"`
+}
+
+// ImageArchives places container images on the node as archives in the format of 'docker save', which are not
+// loaded into any container runtime.
+type ImageArchives struct {
+	// Path is the absolute path of the directory on the node the archives are placed in.
+	Path   string           `yaml:"path"`
+	Images []ContainerImage `yaml:"images"`
+}
+
+type AutoUpdate struct {
+	// Enabled enables the transactional-update timer, which installs updates automatically.
+	Enabled bool `yaml:"enabled"`
+	// Schedule is a systemd calendar expression replacing the default schedule of the timer.
+	Schedule string `yaml:"schedule"`
+	// RebootPolicy is the rebootmgr strategy applied to the reboots required to activate updates.
+	RebootPolicy string `yaml:"rebootPolicy"`
+}
+
+type BootValidation struct {
+	// Script is the path to the validation script, relative to the image configuration directory.
+	Script string `yaml:"script"`
+	// IgnoreFailure allows the boot to continue when the validation script fails.
+	IgnoreFailure bool `yaml:"ignoreFailure"`
 }
 
 type IsoConfiguration struct {
@@ -116,7 +624,8 @@ func (d DiskSize) ToMB() int64 {
 }
 
 type RawConfiguration struct {
-	DiskSize DiskSize `yaml:"diskSize"`
+	DiskSize     DiskSize `yaml:"diskSize"`
+	ABPartitions bool     `yaml:"abPartitions"`
 }
 
 type Packages struct {
@@ -124,6 +633,30 @@ type Packages struct {
 	PKGList         []string  `yaml:"packageList"`
 	AdditionalRepos []AddRepo `yaml:"additionalRepos"`
 	RegCode         string    `yaml:"sccRegistrationCode"`
+	// Locks lists packages held at the version present in the base image.
+	Locks []string `yaml:"locks"`
+	// Exclude lists packages which are prevented from being installed.
+	Exclude []string `yaml:"exclude"`
+	// Kernel pins the kernel installed into the image in place of the one shipped with the base image.
+	Kernel Kernel `yaml:"kernel"`
+}
+
+// Kernel is a specific version of a kernel package, resolved from the configured repositories and installed
+// while assembling the image.
+type Kernel struct {
+	// Name is the kernel package, kernel-default if unset.
+	Name string `yaml:"name"`
+	// Version is the version and release of the package, e.g. 6.4.0-150600.23.25.1.
+	Version string `yaml:"version"`
+}
+
+// Package returns the name of the kernel package, defaulting to kernel-default.
+func (k Kernel) Package() string {
+	if k.Name == "" {
+		return "kernel-default"
+	}
+
+	return k.Name
 }
 
 type AddRepo struct {
@@ -139,6 +672,23 @@ type OperatingSystemUser struct {
 	PrimaryGroup      string   `yaml:"primaryGroup"`
 	SecondaryGroups   []string `yaml:"secondaryGroups"`
 	CreateHomeDir     bool     `yaml:"createHomeDir"`
+	Shell             string   `yaml:"shell"`
+	HomeDir           string   `yaml:"homeDir"`
+	Sudo              UserSudo `yaml:"sudo"`
+	// PasswordExpire is the maximum number of days the password remains valid, unlimited if zero.
+	PasswordExpire int `yaml:"passwordExpire"`
+	// ForceChange expires the password so that it has to be changed on the first login.
+	ForceChange bool `yaml:"forceChange"`
+}
+
+// UserSudo holds the privileges granted to a user through a dedicated file under /etc/sudoers.d.
+type UserSudo struct {
+	// Policy is one of 'none', 'all' or 'commands'; no privileges are granted if it is unset.
+	Policy string `yaml:"policy"`
+	// Commands lists the commands the user may run when the policy is 'commands'.
+	Commands []string `yaml:"commands"`
+	// NoPassword allows running the permitted commands without entering the user's password.
+	NoPassword bool `yaml:"noPassword"`
 }
 
 type OperatingSystemGroup struct {
@@ -149,6 +699,8 @@ type OperatingSystemGroup struct {
 type Systemd struct {
 	Enable  []string `yaml:"enable"`
 	Disable []string `yaml:"disable"`
+	// DefaultTarget is the target the system boots into; the base image's default is kept if unset.
+	DefaultTarget string `yaml:"defaultTarget"`
 }
 
 type Suma struct {
@@ -174,7 +726,29 @@ type Proxy struct {
 }
 
 type EmbeddedArtifactRegistry struct {
-	ContainerImages []ContainerImage `yaml:"images"`
+	ContainerImages []ContainerImage    `yaml:"images"`
+	Mirrors         []RegistryMirror    `yaml:"mirrors"`
+	PullLimits      []RegistryPullLimit `yaml:"pullLimits"`
+}
+
+// RegistryPullLimit restricts the pulls of the images embedded from a registry at build time,
+// in order to stay within the quotas of the registry.
+type RegistryPullLimit struct {
+	// Registry is the registry host name with an optional port, or "*" for registries without limits of their own.
+	Registry string `yaml:"registry"`
+	// Concurrency is the maximum number of images pulled from the registry at the same time.
+	Concurrency int `yaml:"concurrency"`
+	// PullsPerMinute is the maximum number of images pulled from the registry per minute, unlimited if zero.
+	PullsPerMinute int `yaml:"pullsPerMinute"`
+}
+
+// RegistryMirror configures the endpoints the container runtime of the node pulls the images of a registry
+// from, after the embedded artifact registry. Rewrite maps regular expressions matching repository names
+// to their replacement on these endpoints.
+type RegistryMirror struct {
+	Registry  string            `yaml:"registry"`
+	Endpoints []string          `yaml:"endpoints"`
+	Rewrite   map[string]string `yaml:"rewrite"`
 }
 
 type ContainerImage struct {
@@ -182,11 +756,14 @@ type ContainerImage struct {
 }
 
 type Kubernetes struct {
-	Version   string    `yaml:"version"`
-	Network   Network   `yaml:"network"`
-	Nodes     []Node    `yaml:"nodes"`
-	Manifests Manifests `yaml:"manifests"`
-	Helm      Helm      `yaml:"helm"`
+	Version     string      `yaml:"version"`
+	Network     Network     `yaml:"network"`
+	Nodes       []Node      `yaml:"nodes"`
+	Manifests   Manifests   `yaml:"manifests"`
+	Helm        Helm        `yaml:"helm"`
+	InstallGate InstallGate `yaml:"installGate"`
+	Kubeconfig  Kubeconfig  `yaml:"kubeconfig"`
+	Containerd  Containerd  `yaml:"containerd"`
 }
 
 type Network struct {
@@ -198,10 +775,44 @@ type Node struct {
 	Hostname    string `yaml:"hostname"`
 	Type        string `yaml:"type"`
 	Initialiser bool   `yaml:"initializer"`
+	// Labels are applied to the node when it registers with the cluster, in the form of "key=value".
+	Labels []string `yaml:"labels"`
+	// Taints are applied to the node when it registers with the cluster, in the form of "key[=value]:effect".
+	Taints []string `yaml:"taints"`
+}
+
+// InstallGate holds the configuration for delaying the Kubernetes installation
+// on first boot until the node is ready for it.
+type Kubeconfig struct {
+	// Path is the location on server nodes the admin kubeconfig is copied to.
+	Path string `yaml:"path"`
+	// Server optionally replaces the API server address of the copied kubeconfig.
+	Server string `yaml:"server"`
+}
+
+// Containerd holds the configuration override of the container runtime embedded in the Kubernetes distribution.
+type Containerd struct {
+	// ConfigFile is the path of the containerd configuration, relative to the image configuration directory.
+	ConfigFile string `yaml:"configFile"`
+	// Mode is either "full", replacing the configuration generated by the distribution,
+	// or "partial" (the default), appending to it.
+	Mode string `yaml:"mode"`
+}
+
+type InstallGate struct {
+	Strategy string `yaml:"strategy"`
+	// Delay is the number of seconds to wait before the installation
+	// (or before the readiness checks are started).
+	Delay   int    `yaml:"delay"`
+	Command string `yaml:"command"`
+	URL     string `yaml:"url"`
+	// Timeout is the number of seconds to wait for the readiness check to pass.
+	Timeout int `yaml:"timeout"`
 }
 
 type Manifests struct {
-	URLs []string `yaml:"urls"`
+	URLs      []string `yaml:"urls"`
+	Directory string   `yaml:"directory"`
 }
 
 type Helm struct {
@@ -217,6 +828,7 @@ type HelmChart struct {
 	CreateNamespace       bool   `yaml:"createNamespace"`
 	InstallationNamespace string `yaml:"installationNamespace"`
 	ValuesFile            string `yaml:"valuesFile"`
+	ApplyCRDsFirst        bool   `yaml:"applyCRDsFirst"`
 }
 
 type HelmRepository struct {
@@ -234,10 +846,21 @@ type HelmAuthentication struct {
 }
 
 func ParseDefinition(data []byte) (*Definition, error) {
+	return parseDefinition(data, true)
+}
+
+// ParseExtendedDefinition parses a definition validated against a custom schema (see ValidateSchema), which may
+// extend the definition with fields of its own. Unlike ParseDefinition, fields outside of the Definition type are
+// ignored rather than rejected, UnknownFields listing them.
+func ParseExtendedDefinition(data []byte) (*Definition, error) {
+	return parseDefinition(data, false)
+}
+
+func parseDefinition(data []byte, strict bool) (*Definition, error) {
 	var definition Definition
 
 	decoder := yaml.NewDecoder(bytes.NewReader(data))
-	decoder.KnownFields(true)
+	decoder.KnownFields(strict)
 
 	if err := decoder.Decode(&definition); err != nil {
 		return nil, fmt.Errorf("could not parse the image definition: %w", err)
@@ -246,3 +869,24 @@ func ParseDefinition(data []byte) (*Definition, error) {
 
 	return &definition, nil
 }
+
+// RenderDefinition serializes the definition as YAML in its canonical form, which lists every field in
+// the order of the schema and is independent of the formatting, comments and includes of the definition file.
+func RenderDefinition(definition *Definition) ([]byte, error) {
+	data, err := yaml.Marshal(definition)
+	if err != nil {
+		return nil, fmt.Errorf("serializing definition: %w", err)
+	}
+
+	return data, nil
+}
+
+// DefinitionHash returns the SHA-256 checksum of the canonical rendering of the definition.
+func DefinitionHash(definition *Definition) (string, error) {
+	data, err := RenderDefinition(definition)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
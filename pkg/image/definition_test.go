@@ -29,13 +29,33 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, "slemicro5.5.iso", definition.Image.BaseImage)
 	assert.Equal(t, "eibimage.iso", definition.Image.OutputImageName)
 
+	// - Image -> Seed ISOs
+	expectedSeedISOs := []SeedISO{
+		{
+			Hostname: "node1",
+			SSHKeys:  []string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFakeKeyForTesting admin@example.com"},
+			Network: SeedNetwork{
+				Interface:  "eth0",
+				MACAddress: "52:54:00:12:34:56",
+				Addresses:  []string{"192.168.122.10/24"},
+				Gateway:    "192.168.122.1",
+				DNS:        []string{"192.168.122.1"},
+			},
+		},
+		{
+			Hostname: "node2",
+		},
+	}
+	assert.Equal(t, expectedSeedISOs, definition.Image.SeedISOs)
+
 	// - Operating System -> Kernel Arguments
 	expectedKernelArgs := []string{
 		"alpha=foo",
 		"beta=bar",
 		"baz",
 	}
-	assert.Equal(t, expectedKernelArgs, definition.OperatingSystem.KernelArgs)
+	assert.Equal(t, expectedKernelArgs, definition.OperatingSystem.KernelArgs.Add)
+	assert.Nil(t, definition.OperatingSystem.KernelArgs.Remove)
 
 	// Operating System -> Groups
 	groupConfigs := definition.OperatingSystem.Groups
@@ -49,6 +69,8 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, "alpha", userConfigs[0].Username)
 	assert.Equal(t, 2000, userConfigs[0].UID)
 	assert.Equal(t, "$6$bZfTI3Wj05fdxQcB$W1HJQTKw/MaGTCwK75ic9putEquJvYO7vMnDBVAfuAMFW58/79abky4mx9.8znK0UZwSKng9dVosnYQR1toH71", userConfigs[0].EncryptedPassword)
+	assert.Equal(t, 90, userConfigs[0].PasswordExpire)
+	assert.True(t, userConfigs[0].ForceChange)
 	assert.Len(t, userConfigs[0].SSHKeys, 2)
 	assert.Contains(t, userConfigs[0].SSHKeys[0], "ssh-rsa AAAAB3")
 	assert.Contains(t, userConfigs[0].SSHKeys[1], "ssh-rsa BBBBB3")
@@ -56,6 +78,9 @@ func TestParse(t *testing.T) {
 	assert.Len(t, userConfigs[0].SecondaryGroups, 1)
 	assert.Equal(t, "wheel", userConfigs[0].SecondaryGroups[0])
 	assert.True(t, userConfigs[0].CreateHomeDir)
+	assert.Equal(t, "/bin/bash", userConfigs[0].Shell)
+	assert.Equal(t, "/var/lib/alpha", userConfigs[0].HomeDir)
+	assert.Equal(t, UserSudo{Policy: SudoPolicyCommands, Commands: []string{"/usr/bin/systemctl restart k3s"}}, userConfigs[0].Sudo)
 
 	assert.Equal(t, "beta", userConfigs[1].Username)
 	assert.Equal(t, 0, userConfigs[1].UID)
@@ -64,6 +89,9 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, userConfigs[1].PrimaryGroup, "")
 	assert.Len(t, userConfigs[1].SecondaryGroups, 0)
 	assert.False(t, userConfigs[1].CreateHomeDir)
+	assert.Empty(t, userConfigs[1].Shell)
+	assert.Empty(t, userConfigs[1].HomeDir)
+	assert.Empty(t, userConfigs[1].Sudo.Policy)
 
 	assert.Equal(t, "gamma", userConfigs[2].Username)
 	assert.Equal(t, 0, userConfigs[2].UID)
@@ -81,6 +109,7 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, "enable1", systemd.Enable[1])
 	require.Len(t, systemd.Disable, 1)
 	assert.Equal(t, "disable0", systemd.Disable[0])
+	assert.Equal(t, "multi-user.target", systemd.DefaultTarget)
 
 	// Operating System -> Suma
 	suma := definition.OperatingSystem.Suma
@@ -112,11 +141,213 @@ func TestParse(t *testing.T) {
 	}
 	assert.Equal(t, expectedAddRepos, pkgConfig.AdditionalRepos)
 	assert.Equal(t, "INTERNAL-USE-ONLY-foo-bar", pkgConfig.RegCode)
+	assert.Equal(t, []string{"kernel-default"}, pkgConfig.Locks)
+	assert.Equal(t, []string{"apparmor-parser"}, pkgConfig.Exclude)
+	assert.Equal(t, Kernel{Name: "kernel-default-base", Version: "6.4.0-150600.23.25.1"}, pkgConfig.Kernel)
+
+	// Operating System -> BootValidation
+	bootValidation := definition.OperatingSystem.BootValidation
+	assert.Equal(t, "validate.sh", bootValidation.Script)
+	assert.True(t, bootValidation.IgnoreFailure)
+
+	// Operating System -> SSHD
+	sshd := definition.OperatingSystem.SSHD
+	assert.Equal(t, []string{"ssh-ed25519"}, sshd.AllowedKeyTypes)
+	assert.Equal(t, []string{"ssh_host_ed25519_key"}, sshd.HostKeys)
+	assert.Equal(t, map[string]string{"PasswordAuthentication": "no"}, sshd.Options)
+
+	// Operating System -> Fail2ban
+	fail2ban := definition.OperatingSystem.Fail2ban
+	assert.Equal(t, "1h", fail2ban.BanTime)
+	assert.Equal(t, "10m", fail2ban.FindTime)
+	assert.Equal(t, 5, fail2ban.MaxRetry)
+	assert.Equal(t, []string{"10.0.0.0/8"}, fail2ban.IgnoreIPs)
+	assert.Equal(t, []Fail2banJail{{Name: "sshd", Port: "ssh,2222", MaxRetry: 3}}, fail2ban.Jails)
+
+	// Operating System -> Audit
+	audit := definition.OperatingSystem.Audit
+	assert.Equal(t, []string{"30-stig.rules"}, audit.RuleFiles)
+	assert.Equal(t, []string{"-w /etc/shadow -p wa -k shadow"}, audit.Rules)
+
+	// Operating System -> AppArmor
+	assert.Equal(t, []string{"usr.bin.workload"}, definition.OperatingSystem.AppArmor.Profiles)
+
+	// Operating System -> Networkd
+	assert.Equal(t, []string{"10-eth0.network", "20-br0.netdev"}, definition.OperatingSystem.Networkd.ConfigFiles)
+
+	// Operating System -> Network Profiles
+	networkProfiles := definition.OperatingSystem.NetworkProfiles
+	assert.Equal(t, "site.network", networkProfiles.SelectionArg())
+	assert.Equal(t, "office", networkProfiles.Default)
+	assert.Equal(t, []string{"office", "field"}, networkProfiles.Profiles)
+
+	// Operating System -> Podman
+	assert.Equal(t, []ContainerImage{{Name: "registry.example.com/workloads/app:1.0"}}, definition.OperatingSystem.Podman.Images)
+
+	// Operating System -> Image Archives
+	assert.Equal(t, "/var/lib/images", definition.OperatingSystem.ImageArchives.Path)
+	assert.Equal(t, []ContainerImage{{Name: "registry.example.com/workloads/batch:2.1"}}, definition.OperatingSystem.ImageArchives.Images)
+
+	// Operating System -> AutoUpdate
+	autoUpdate := definition.OperatingSystem.AutoUpdate
+	assert.True(t, autoUpdate.Enabled)
+	assert.Equal(t, "Sat *-*-* 02:00", autoUpdate.Schedule)
+	assert.Equal(t, "maint_window", autoUpdate.RebootPolicy)
+
+	// Operating System -> Hosts
+	expectedHosts := []HostEntry{
+		{IP: "192.168.1.10", Hostnames: []string{"registry.local", "registry"}},
+	}
+	assert.Equal(t, expectedHosts, definition.OperatingSystem.Hosts)
+
+	// Operating System -> Known Hosts
+	expectedKnownHosts := []KnownHost{
+		{Host: "git.example.com", Key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIPOAHN0CdWw9HIwQ/eAq2s5xUztoAjAXPJH88XRfllmN"},
+	}
+	assert.Equal(t, expectedKnownHosts, definition.OperatingSystem.KnownHosts)
+
+	// Operating System -> Journald
+	expectedJournald := Journald{
+		Storage:      "persistent",
+		SystemMaxUse: "1G",
+		MaxRetention: "1month",
+	}
+	assert.Equal(t, expectedJournald, definition.OperatingSystem.Journald)
+
+	// Operating System -> Udev
+	assert.Equal(t, []string{"70-net.rules"}, definition.OperatingSystem.Udev.Rules)
+
+	// Operating System -> PAM
+	assert.Equal(t, []string{"workload"}, definition.OperatingSystem.PAM.ConfigFiles)
+
+	// Operating System -> Scheduled Jobs
+	scheduledJobs := definition.OperatingSystem.ScheduledJobs
+	require.Len(t, scheduledJobs, 2)
+	assert.Equal(t, "prune-images", scheduledJobs[0].Name)
+	assert.Equal(t, "Sun *-*-* 03:00", scheduledJobs[0].Schedule)
+	assert.Equal(t, "podman image prune --all --force", scheduledJobs[0].Command)
+	assert.Empty(t, scheduledJobs[0].Backend)
+	assert.Equal(t, "rotate-exports", scheduledJobs[1].Name)
+	assert.Equal(t, "*/15 * * * *", scheduledJobs[1].Schedule)
+	assert.Equal(t, ScheduledJobBackendCron, scheduledJobs[1].Backend)
+
+	// Operating System -> Logrotate
+	expectedLogrotate := []LogRotation{
+		{
+			Name:      "workload",
+			Paths:     []string{"/var/log/workload/*.log"},
+			Frequency: LogRotationDaily,
+			Rotate:    7,
+			MaxSize:   "100M",
+			Compress:  true,
+		},
+	}
+	assert.Equal(t, expectedLogrotate, definition.OperatingSystem.Logrotate)
+
+	// Operating System -> Release
+	expectedLabels := map[string]string{
+		"SITE":     "berlin-02",
+		"CUSTOMER": "ACME Corp",
+	}
+	assert.Equal(t, expectedLabels, definition.OperatingSystem.Release.Labels)
+
+	// Operating System -> Remove
+	assert.Equal(t, []string{"/usr/share/doc", "/usr/share/man"}, definition.OperatingSystem.Remove)
+
+	// Operating System -> Strip Docs
+	assert.True(t, definition.OperatingSystem.StripDocs.Enabled)
+	assert.Equal(t, []string{"/usr/share/info/bash.info.gz", "/usr/share/info/coreutils.info.gz"}, definition.OperatingSystem.StripDocs.Exclude)
+
+	// Operating System -> Directories
+	expectedDirectories := []Directory{
+		{Path: "/var/lib/app/data", Owner: "alpha", Group: "1000", Mode: "0750"},
+		{Path: "/srv/shared"},
+	}
+	assert.Equal(t, expectedDirectories, definition.OperatingSystem.Directories)
+
+	// Operating System -> Environment
+	expectedEnvironment := map[string]EnvironmentVariable{
+		"AGENT_URL":   {Value: "https://agent.example.com:8443"},
+		"AGENT_TOKEN": {Value: "s3cr3t", Sensitive: true},
+	}
+	assert.Equal(t, expectedEnvironment, definition.OperatingSystem.Environment)
+
+	// Operating System -> ShellDefaults
+	expectedShellDefaults := ShellDefaults{
+		Editor:      "vim",
+		Pager:       "less -R",
+		Environment: map[string]string{"HISTSIZE": "5000"},
+	}
+	assert.Equal(t, expectedShellDefaults, definition.OperatingSystem.ShellDefaults)
+
+	// Operating System -> DNS
+	dns := definition.OperatingSystem.DNS
+	assert.Equal(t, []string{"9.9.9.9#dns.quad9.net", "[2620:fe::fe]:853#dns.quad9.net"}, dns.Servers)
+	assert.Equal(t, []string{"1.1.1.1#cloudflare-dns.com"}, dns.FallbackServers)
+	assert.Equal(t, "opportunistic", dns.DNSOverTLS)
+
+	// Operating System -> Dnsmasq
+	dnsmasq := definition.OperatingSystem.Dnsmasq
+	assert.Equal(t, []string{"eth1"}, dnsmasq.Interfaces)
+	assert.Equal(t, "sensors.lan", dnsmasq.Domain)
+	assert.Equal(t, []string{"9.9.9.9"}, dnsmasq.Upstreams)
+	assert.Equal(t, []DHCPRange{{Start: "192.168.50.100", End: "192.168.50.200", LeaseTime: "12h"}}, dnsmasq.Ranges)
+	assert.Equal(t, []StaticLease{{MAC: "52:54:00:aa:bb:cc", IP: "192.168.50.10", Hostname: "sensor-01"}}, dnsmasq.StaticLeases)
+
+	// Operating System -> Locales
+	locales := definition.OperatingSystem.Locales
+	assert.Equal(t, []string{"en_US.UTF-8", "de_DE"}, locales.Keep)
+	assert.Equal(t, "de_DE.UTF-8", locales.Default)
+
+	// Operating System -> Machine ID
+	assert.Equal(t, MachineID{Seed: "edge-fleet"}, definition.OperatingSystem.MachineID)
+
+	gpuDrivers := definition.OperatingSystem.GPUDrivers
+	assert.Equal(t, "550.90.07", gpuDrivers.Version)
+	assert.Equal(t, AddRepo{URL: "https://download.nvidia.com/suse/sle15sp6/"}, gpuDrivers.Repository)
+	assert.Equal(t, []string{"nvidia-open-driver-G06-signed-kmp-default", "nvidia-compute-utils-G06"}, gpuDrivers.Packages)
+	assert.Equal(t, GPUKernelModules{
+		Load:      []string{"nvidia"},
+		Options:   map[string]string{"nvidia": "NVreg_EnableGpuFirmware=1"},
+		Blacklist: []string{"nouveau"},
+	}, gpuDrivers.KernelModules)
+
+	// Operating System -> Zram
+	assert.Equal(t, Zram{Ratio: 0.5, Algorithm: "zstd"}, definition.OperatingSystem.Zram)
+
+	// Operating System -> Kdump
+	assert.Equal(t, Kdump{Memory: "512M", SavePath: "/var/lib/crash"}, definition.OperatingSystem.Kdump)
+
+	// Operating System -> ReadOnlyRoot
+	assert.Equal(t, ReadOnlyRoot{
+		Enabled: true,
+		Overlays: []Overlay{
+			{Path: "/usr/lib/app/cache", Type: OverlayTypeTmpfs, Size: "256m"},
+			{Path: "/usr/lib/app/data", Type: OverlayTypePersistent},
+		},
+	}, definition.OperatingSystem.ReadOnlyRoot)
+
+	// Operating System -> SecureBoot
+	assert.Equal(t, SecureBoot{
+		MOKCertificates: []string{"edge-mok.der"},
+		Signing: SecureBootSigning{
+			Key:         "edge-signing.key",
+			Certificate: "edge-signing.crt",
+			Kernel:      true,
+			Modules:     true,
+		},
+		Lockdown: LockdownIntegrity,
+	}, definition.OperatingSystem.SecureBoot)
 
 	// Operating System -> IsoConfiguration
 	installDevice := definition.OperatingSystem.IsoConfiguration.InstallDevice
 	assert.Equal(t, "/dev/sda", installDevice)
 
+	// Operating System -> RawConfiguration
+	rawConfiguration := definition.OperatingSystem.RawConfiguration
+	assert.Equal(t, DiskSize("32G"), rawConfiguration.DiskSize)
+	assert.True(t, rawConfiguration.ABPartitions)
+
 	// Operating System -> Time
 	time := definition.OperatingSystem.Time
 	assert.Equal(t, "Europe/London", time.Timezone)
@@ -146,10 +377,30 @@ func TestParse(t *testing.T) {
 	keymap := definition.OperatingSystem.Keymap
 	assert.Equal(t, "us", keymap)
 
+	// Operating System -> Hostname
+	hostname := definition.OperatingSystem.Hostname
+	assert.Equal(t, "kiosk-01.example.com", hostname)
+
+	// Operating System -> Autologin
+	assert.Equal(t, Autologin{User: "alpha", TTY: "tty1"}, definition.OperatingSystem.Autologin)
+
 	// EmbeddedArtifactRegistry
 	embeddedArtifactRegistry := definition.EmbeddedArtifactRegistry
 	assert.Equal(t, "hello-world:latest", embeddedArtifactRegistry.ContainerImages[0].Name)
 	assert.Equal(t, "ghcr.io/fluxcd/flux-cli@sha256:02aa820c3a9c57d67208afcfc4bce9661658c17d15940aea369da259d2b976dd", embeddedArtifactRegistry.ContainerImages[1].Name)
+	expectedMirrors := []RegistryMirror{
+		{
+			Registry:  "docker.io",
+			Endpoints: []string{"https://mirror.example.com"},
+		},
+		{
+			Registry:  "registry.suse.com",
+			Endpoints: []string{"https://mirror.example.com"},
+			Rewrite:   map[string]string{"^suse/(.*)": "suse-mirror/$1"},
+		},
+	}
+	assert.Equal(t, expectedMirrors, embeddedArtifactRegistry.Mirrors)
+	assert.Equal(t, []RegistryPullLimit{{Registry: "docker.io", Concurrency: 2, PullsPerMinute: 30}}, embeddedArtifactRegistry.PullLimits)
 
 	// Kubernetes
 	kubernetes := definition.Kubernetes
@@ -172,6 +423,8 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, "node3.suse.com", kubernetes.Nodes[2].Hostname)
 	assert.Equal(t, "agent", kubernetes.Nodes[2].Type)
 	assert.Equal(t, false, kubernetes.Nodes[2].Initialiser)
+	assert.Equal(t, []string{"zone=edge-1"}, kubernetes.Nodes[2].Labels)
+	assert.Equal(t, []string{"dedicated=gpu:NoSchedule"}, kubernetes.Nodes[2].Taints)
 	assert.Equal(t, "node4.suse.com", kubernetes.Nodes[3].Hostname)
 	assert.Equal(t, "server", kubernetes.Nodes[3].Type)
 	assert.Equal(t, false, kubernetes.Nodes[4].Initialiser)
@@ -181,6 +434,18 @@ func TestParse(t *testing.T) {
 
 	// Manifests
 	assert.Equal(t, "https://k8s.io/examples/application/nginx-app.yaml", kubernetes.Manifests.URLs[0])
+	assert.Equal(t, "ordered-manifests", kubernetes.Manifests.Directory)
+
+	// Install Gate
+	assert.Equal(t, InstallGateStrategyURL, kubernetes.InstallGate.Strategy)
+	assert.Equal(t, 30, kubernetes.InstallGate.Delay)
+	assert.Equal(t, "https://registry.suse.com", kubernetes.InstallGate.URL)
+	assert.Empty(t, kubernetes.InstallGate.Command)
+	assert.Equal(t, 600, kubernetes.InstallGate.Timeout)
+	assert.Equal(t, "/root/.kube/config", kubernetes.Kubeconfig.Path)
+	assert.Equal(t, "https://api.cluster01.hosted.on.edge.suse.com:6443", kubernetes.Kubeconfig.Server)
+	assert.Equal(t, "containerd.toml", kubernetes.Containerd.ConfigFile)
+	assert.Equal(t, ContainerdConfigModePartial, kubernetes.Containerd.Mode)
 
 	// Helm Charts
 	assert.Equal(t, "apache", kubernetes.Helm.Charts[0].Name)
@@ -190,10 +455,12 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, true, kubernetes.Helm.Charts[0].CreateNamespace)
 	assert.Equal(t, "apache-system", kubernetes.Helm.Charts[0].InstallationNamespace)
 	assert.Equal(t, "apache-values.yaml", kubernetes.Helm.Charts[0].ValuesFile)
+	assert.Equal(t, false, kubernetes.Helm.Charts[0].ApplyCRDsFirst)
 
 	assert.Equal(t, "metallb", kubernetes.Helm.Charts[1].Name)
 	assert.Equal(t, "suse-edge", kubernetes.Helm.Charts[1].RepositoryName)
 	assert.Equal(t, "0.14.3", kubernetes.Helm.Charts[1].Version)
+	assert.Equal(t, true, kubernetes.Helm.Charts[1].ApplyCRDsFirst)
 
 	// Helm Repositories
 	assert.Equal(t, "suse-edge", kubernetes.Helm.Repositories[0].Name)
@@ -239,6 +506,58 @@ operatingSystem:
 	assert.ErrorContains(t, err, "line 7: field zone not found in type image.Time")
 }
 
+func TestParse_KernelArgsAddRemove(t *testing.T) {
+	config := `
+apiVersion: 1.0
+operatingSystem:
+  kernelArgs:
+    add:
+      - console=ttyS1
+    remove:
+      - quiet
+      - console=tty0
+`
+
+	definition, err := ParseDefinition([]byte(config))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"console=ttyS1"}, definition.OperatingSystem.KernelArgs.Add)
+	assert.Equal(t, []string{"quiet", "console=tty0"}, definition.OperatingSystem.KernelArgs.Remove)
+}
+
+func TestParseBadConfig_KernelArgs(t *testing.T) {
+	tests := map[string]struct {
+		config        string
+		expectedError string
+	}{
+		"unknown field": {
+			config: `
+operatingSystem:
+  kernelArgs:
+    delete:
+      - quiet
+`,
+			expectedError: "line 4: field delete not found in type image.KernelArgs",
+		},
+		"scalar": {
+			config: `
+operatingSystem:
+  kernelArgs: quiet
+`,
+			expectedError: "line 3: kernelArgs must be either a list or a mapping",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseDefinition([]byte(test.config))
+
+			require.Error(t, err)
+			assert.ErrorContains(t, err, test.expectedError)
+		})
+	}
+}
+
 func TestArch_Short(t *testing.T) {
 	assert.Equal(t, "amd64", ArchTypeX86.Short())
 	assert.Equal(t, "arm64", ArchTypeARM.Short())
@@ -257,3 +576,26 @@ func TestDiskSize_ToMB(t *testing.T) {
 		DiskSize("10K").ToMB()
 	})
 }
+
+func TestDefinitionHash(t *testing.T) {
+	compact, err := ParseDefinition([]byte("apiVersion: 1.0\nimage: {imageType: raw, arch: x86_64}\n"))
+	require.NoError(t, err)
+
+	commented, err := ParseDefinition([]byte("# Edge node\napiVersion: 1.0\nimage:\n  arch: x86_64\n  imageType: raw\n"))
+	require.NoError(t, err)
+
+	different, err := ParseDefinition([]byte("apiVersion: 1.0\nimage: {imageType: iso, arch: x86_64}\n"))
+	require.NoError(t, err)
+
+	compactHash, err := DefinitionHash(compact)
+	require.NoError(t, err)
+	assert.Len(t, compactHash, 64)
+
+	commentedHash, err := DefinitionHash(commented)
+	require.NoError(t, err)
+	assert.Equal(t, compactHash, commentedHash)
+
+	differentHash, err := DefinitionHash(different)
+	require.NoError(t, err)
+	assert.NotEqual(t, compactHash, differentHash)
+}
@@ -0,0 +1,201 @@
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangeType describes how a field differs between two definitions.
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "+"
+	ChangeRemoved ChangeType = "-"
+	ChangeUpdated ChangeType = "~"
+)
+
+// DefinitionChange describes a field which differs between two definitions.
+type DefinitionChange struct {
+	Type ChangeType
+	// Path locates the field in the definition, e.g. "operatingSystem/users[0]/shell".
+	Path string
+	// Old is the value of the field in the first definition, unset for added fields.
+	Old any
+	// New is the value of the field in the second definition, unset for removed fields.
+	New any
+}
+
+func (c DefinitionChange) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("%s %s: %s", c.Type, c.Path, formatChangeValue(c.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("%s %s: %s", c.Type, c.Path, formatChangeValue(c.Old))
+	default:
+		return fmt.Sprintf("%s %s: %s -> %s", c.Type, c.Path, formatChangeValue(c.Old), formatChangeValue(c.New))
+	}
+}
+
+// DiffDefinitions compares the canonical renderings of two definitions, so that only the fields whose values
+// differ are reported regardless of the formatting and key ordering of the definition files. Fields left at
+// their default value are treated as unset, and list entries are compared by their position.
+func DiffDefinitions(from, to *Definition) ([]DefinitionChange, error) {
+	fromValues, err := definitionValues(from)
+	if err != nil {
+		return nil, fmt.Errorf("rendering first definition: %w", err)
+	}
+
+	toValues, err := definitionValues(to)
+	if err != nil {
+		return nil, fmt.Errorf("rendering second definition: %w", err)
+	}
+
+	var changes []DefinitionChange
+	diffValues("", fromValues, toValues, &changes)
+
+	return changes, nil
+}
+
+func definitionValues(definition *Definition) (any, error) {
+	data, err := RenderDefinition(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	var values any
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("parsing rendered definition: %w", err)
+	}
+
+	return pruneDefaults(values), nil
+}
+
+// pruneDefaults removes the fields left at their default value, which the canonical rendering lists as well.
+func pruneDefaults(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if pruned := pruneDefaults(field); pruned == nil {
+				delete(v, key)
+			} else {
+				v[key] = pruned
+			}
+		}
+
+		if len(v) == 0 {
+			return nil
+		}
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+
+		// Entries are kept even if unset, as the position of the following ones is significant
+		for i := range v {
+			v[i] = pruneDefaults(v[i])
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+	case int:
+		if v == 0 {
+			return nil
+		}
+	case float64:
+		if v == 0 {
+			return nil
+		}
+	case bool:
+		if !v {
+			return nil
+		}
+	}
+
+	return value
+}
+
+func diffValues(path string, from, to any, changes *[]DefinitionChange) {
+	switch {
+	case from == nil && to == nil:
+		return
+	case from == nil:
+		*changes = append(*changes, DefinitionChange{Type: ChangeAdded, Path: path, New: to})
+		return
+	case to == nil:
+		*changes = append(*changes, DefinitionChange{Type: ChangeRemoved, Path: path, Old: from})
+		return
+	}
+
+	fromMap, fromIsMap := from.(map[string]any)
+	toMap, toIsMap := to.(map[string]any)
+	if fromIsMap && toIsMap {
+		var keys []string
+		for key := range fromMap {
+			keys = append(keys, key)
+		}
+		for key := range toMap {
+			if _, ok := fromMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			diffValues(joinChangePath(path, key), fromMap[key], toMap[key], changes)
+		}
+
+		return
+	}
+
+	fromList, fromIsList := from.([]any)
+	toList, toIsList := to.([]any)
+	if fromIsList && toIsList {
+		for i := 0; i < max(len(fromList), len(toList)); i++ {
+			var fromEntry, toEntry any
+			if i < len(fromList) {
+				fromEntry = fromList[i]
+			}
+			if i < len(toList) {
+				toEntry = toList[i]
+			}
+
+			diffValues(fmt.Sprintf("%s[%d]", path, i), fromEntry, toEntry, changes)
+		}
+
+		return
+	}
+
+	if !equalValues(from, to) {
+		*changes = append(*changes, DefinitionChange{Type: ChangeUpdated, Path: path, Old: from, New: to})
+	}
+}
+
+func joinChangePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "/" + key
+}
+
+func equalValues(a, b any) bool {
+	return formatChangeValue(a) == formatChangeValue(b)
+}
+
+// formatChangeValue renders the value as compact JSON, quoting strings and listing nested fields on a single line.
+func formatChangeValue(value any) string {
+	if value == nil {
+		return "null"
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(data)
+}
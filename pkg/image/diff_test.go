@@ -0,0 +1,103 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDefinitions(t *testing.T) {
+	from, err := ParseDefinition([]byte(`
+apiVersion: 1.0
+image:
+  imageType: iso
+  arch: x86_64
+  baseImage: SL-Micro.x86_64-6.0-Base-SelfInstall-GM.install.iso
+  outputImageName: eib-image.iso
+operatingSystem:
+  users:
+    - username: alpha
+      shell: /bin/bash
+    - username: beta
+  packages:
+    packageList:
+      - vim
+`))
+	require.NoError(t, err)
+
+	// The same definition with reordered keys, a changed, an added and a removed field
+	to, err := ParseDefinition([]byte(`
+operatingSystem:
+  packages:
+    packageList:
+      - vim
+  users:
+    - shell: /bin/zsh
+      username: alpha
+  keymap: de
+image:
+  outputImageName: eib-image.iso
+  baseImage: SL-Micro.x86_64-6.0-Base-SelfInstall-GM.install.iso
+  arch: x86_64
+  imageType: iso
+apiVersion: 1.0
+`))
+	require.NoError(t, err)
+
+	changes, err := DiffDefinitions(from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, []DefinitionChange{
+		{Type: ChangeAdded, Path: "operatingSystem/keymap", New: "de"},
+		{Type: ChangeUpdated, Path: "operatingSystem/users[0]/shell", Old: "/bin/bash", New: "/bin/zsh"},
+		{Type: ChangeRemoved, Path: "operatingSystem/users[1]", Old: map[string]any{"username": "beta"}},
+	}, changes)
+}
+
+func TestDiffDefinitions_Identical(t *testing.T) {
+	data := []byte(`
+apiVersion: 1.0
+image:
+  imageType: raw
+  arch: x86_64
+kubernetes:
+  version: v1.30.3+k3s1
+`)
+
+	from, err := ParseDefinition(data)
+	require.NoError(t, err)
+
+	to, err := ParseDefinition(data)
+	require.NoError(t, err)
+
+	changes, err := DiffDefinitions(from, to)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDefinitionChange_String(t *testing.T) {
+	tests := map[string]struct {
+		change   DefinitionChange
+		expected string
+	}{
+		"Added": {
+			change:   DefinitionChange{Type: ChangeAdded, Path: "kubernetes/version", New: "v1.30.3+k3s1"},
+			expected: `+ kubernetes/version: "v1.30.3+k3s1"`,
+		},
+		"Removed": {
+			change:   DefinitionChange{Type: ChangeRemoved, Path: "operatingSystem/users[1]", Old: map[string]any{"username": "beta", "uid": 2000}},
+			expected: `- operatingSystem/users[1]: {"uid":2000,"username":"beta"}`,
+		},
+		"Updated": {
+			change:   DefinitionChange{Type: ChangeUpdated, Path: "operatingSystem/rawConfiguration/diskSize", Old: "32G", New: "64G"},
+			expected: `~ operatingSystem/rawConfiguration/diskSize: "32G" -> "64G"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.change.String())
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeKey is the top-level key listing the fragments merged into a definition
+const includeKey = "include"
+
+// ResolveIncludes merges the fragment files listed under the top-level 'include' key of the definition into it,
+// returning the resulting definition. The fragments are resolved relative to the image configuration directory
+// and may include further fragments themselves. The name of the definition file, if any, is used to detect
+// fragments including it back.
+//
+// Mappings are merged key by key and sequences are concatenated, with the included fragments coming first in
+// the order they are listed. Scalar values of the including file take precedence over those of its fragments,
+// and later fragments take precedence over earlier ones.
+func ResolveIncludes(data []byte, configDir, definitionFile string) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing definition: %w", err)
+	}
+
+	if len(root.Content) == 0 || !hasIncludes(root.Content[0]) {
+		return data, nil
+	}
+
+	var chain []string
+	if definitionFile != "" {
+		chain = append(chain, filepath.Clean(definitionFile))
+	}
+
+	resolved, err := resolveIncludes(root.Content[0], configDir, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(resolved)
+}
+
+// IncludedFiles returns the fragment files included by the definition, either directly or through other
+// fragments, relative to the image configuration directory and in the order they are first encountered.
+func IncludedFiles(data []byte, configDir string) ([]string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing definition: %w", err)
+	}
+
+	if len(root.Content) == 0 || !hasIncludes(root.Content[0]) {
+		return nil, nil
+	}
+
+	var included []string
+	if err := collectIncludes(root.Content[0], configDir, &included); err != nil {
+		return nil, err
+	}
+
+	return included, nil
+}
+
+func collectIncludes(document *yaml.Node, configDir string, included *[]string) error {
+	includes, err := extractIncludes(document)
+	if err != nil {
+		return err
+	}
+
+	for _, include := range includes {
+		if !filepath.IsLocal(include) {
+			return fmt.Errorf("include '%s' must be a path relative to the image configuration directory", include)
+		}
+
+		include = filepath.Clean(include)
+		if slices.Contains(*included, include) {
+			continue
+		}
+		*included = append(*included, include)
+
+		fragment, err := readFragment(filepath.Join(configDir, include), include)
+		if err != nil {
+			return err
+		}
+
+		if fragment == nil {
+			continue
+		}
+
+		if err = collectIncludes(fragment, configDir, included); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasIncludes(document *yaml.Node) bool {
+	return document.Kind == yaml.MappingNode && mappingIndex(document, includeKey) != -1
+}
+
+// resolveIncludes merges the fragments included by the document into it. The chain holds the files
+// currently being resolved, starting with the outermost one.
+func resolveIncludes(document *yaml.Node, configDir string, chain []string) (*yaml.Node, error) {
+	includes, err := extractIncludes(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *yaml.Node
+	for _, include := range includes {
+		if !filepath.IsLocal(include) {
+			return nil, fmt.Errorf("include '%s' must be a path relative to the image configuration directory", include)
+		}
+
+		include = filepath.Clean(include)
+		if slices.Contains(chain, include) {
+			return nil, fmt.Errorf("include cycle detected: %s", strings.Join(append(slices.Clone(chain), include), " -> "))
+		}
+
+		fragment, err := readFragment(filepath.Join(configDir, include), include)
+		if err != nil {
+			return nil, err
+		}
+
+		if fragment == nil {
+			continue
+		}
+
+		if fragment, err = resolveIncludes(fragment, configDir, append(slices.Clone(chain), include)); err != nil {
+			return nil, err
+		}
+
+		if merged, err = mergeNodes(merged, fragment, ""); err != nil {
+			return nil, fmt.Errorf("merging include '%s': %w", include, err)
+		}
+	}
+
+	return mergeNodes(merged, document, "")
+}
+
+// extractIncludes removes the include key from the document, returning the listed fragments.
+func extractIncludes(document *yaml.Node) ([]string, error) {
+	if document.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("definition must be a mapping")
+	}
+
+	index := mappingIndex(document, includeKey)
+	if index == -1 {
+		return nil, nil
+	}
+
+	var includes []string
+	if err := document.Content[index+1].Decode(&includes); err != nil {
+		return nil, fmt.Errorf("'%s' must be a list of file paths: %w", includeKey, err)
+	}
+
+	document.Content = slices.Delete(document.Content, index, index+2)
+	return includes, nil
+}
+
+// readFragment parses the fragment file, returning nil if it is empty.
+func readFragment(path, include string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("included file '%s' could not be found", include)
+		}
+
+		return nil, fmt.Errorf("reading included file '%s': %w", include, err)
+	}
+
+	var root yaml.Node
+	if err = yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing included file '%s': %w", include, err)
+	}
+
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	if root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("included file '%s' must be a mapping", include)
+	}
+
+	return root.Content[0], nil
+}
+
+// mergeNodes merges the override into the base node, both of which may be modified in the process.
+func mergeNodes(base, override *yaml.Node, path string) (*yaml.Node, error) {
+	if base == nil {
+		return override, nil
+	}
+
+	if base.Kind != override.Kind {
+		if isNull(base) || isNull(override) {
+			return override, nil
+		}
+
+		return nil, fmt.Errorf("conflicting value types for '%s'", path)
+	}
+
+	switch override.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(override.Content); i += 2 {
+			key, value := override.Content[i], override.Content[i+1]
+			keyPath := key.Value
+			if path != "" {
+				keyPath = path + "/" + key.Value
+			}
+
+			index := mappingIndex(base, key.Value)
+			if index == -1 {
+				base.Content = append(base.Content, key, value)
+				continue
+			}
+
+			merged, err := mergeNodes(base.Content[index+1], value, keyPath)
+			if err != nil {
+				return nil, err
+			}
+			base.Content[index+1] = merged
+		}
+
+		return base, nil
+	case yaml.SequenceNode:
+		base.Content = append(base.Content, override.Content...)
+		return base, nil
+	default:
+		return override, nil
+	}
+}
+
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
@@ -0,0 +1,196 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFragments(t *testing.T, fragments map[string]string) string {
+	configDir := t.TempDir()
+
+	for name, contents := range fragments {
+		path := filepath.Join(configDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	}
+
+	return configDir
+}
+
+func TestResolveIncludes_NoIncludes(t *testing.T) {
+	data := []byte("apiVersion: 1.0\nimage:\n  imageType: iso\n")
+
+	resolved, err := ResolveIncludes(data, t.TempDir(), "definition.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, data, resolved)
+}
+
+func TestResolveIncludes(t *testing.T) {
+	configDir := writeFragments(t, map[string]string{
+		"fragments/users.yaml": `
+include:
+  - fragments/groups.yaml
+operatingSystem:
+  users:
+    - username: ops
+      encryptedPassword: ops-password
+  time:
+    timezone: Europe/London
+`,
+		"fragments/groups.yaml": `
+operatingSystem:
+  groups:
+    - name: operators
+`,
+		"fragments/proxy.yaml": `
+operatingSystem:
+  proxy:
+    httpProxy: http://proxy.local:3128
+  time:
+    timezone: Europe/Berlin
+`,
+	})
+
+	data := []byte(`
+apiVersion: 1.0
+include:
+  - fragments/users.yaml
+  - fragments/proxy.yaml
+image:
+  imageType: ISO
+  arch: x86_64
+operatingSystem:
+  users:
+    - username: dev
+      encryptedPassword: dev-password
+  keymap: us
+`)
+
+	resolved, err := ResolveIncludes(data, configDir, "definition.yaml")
+	require.NoError(t, err)
+
+	definition, err := ParseDefinition(resolved)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.0", definition.APIVersion)
+	assert.Equal(t, TypeISO, definition.Image.ImageType)
+	assert.Equal(t, "us", definition.OperatingSystem.Keymap)
+	assert.Equal(t, "http://proxy.local:3128", definition.OperatingSystem.Proxy.HTTPProxy)
+
+	// Later fragments take precedence over earlier ones
+	assert.Equal(t, "Europe/Berlin", definition.OperatingSystem.Time.Timezone)
+
+	// Sequences are concatenated, fragments first
+	require.Len(t, definition.OperatingSystem.Users, 2)
+	assert.Equal(t, "ops", definition.OperatingSystem.Users[0].Username)
+	assert.Equal(t, "dev", definition.OperatingSystem.Users[1].Username)
+
+	// Nested includes
+	require.Len(t, definition.OperatingSystem.Groups, 1)
+	assert.Equal(t, "operators", definition.OperatingSystem.Groups[0].Name)
+}
+
+func TestResolveIncludes_DefinitionTakesPrecedence(t *testing.T) {
+	configDir := writeFragments(t, map[string]string{
+		"common.yaml": "image:\n  arch: aarch64\n  baseImage: common.iso\n",
+	})
+
+	data := []byte("include:\n  - common.yaml\nimage:\n  arch: x86_64\n")
+
+	resolved, err := ResolveIncludes(data, configDir, "")
+	require.NoError(t, err)
+
+	definition, err := ParseDefinition(resolved)
+	require.NoError(t, err)
+
+	assert.Equal(t, ArchTypeX86, definition.Image.Arch)
+	assert.Equal(t, "common.iso", definition.Image.BaseImage)
+}
+
+func TestResolveIncludes_Errors(t *testing.T) {
+	tests := map[string]struct {
+		Fragments     map[string]string
+		Definition    string
+		ExpectedError string
+	}{
+		`missing file`: {
+			Definition:    "include:\n  - missing.yaml\n",
+			ExpectedError: "included file 'missing.yaml' could not be found",
+		},
+		`cycle`: {
+			Fragments: map[string]string{
+				"a.yaml": "include:\n  - b.yaml\n",
+				"b.yaml": "include:\n  - ./a.yaml\n",
+			},
+			Definition:    "include:\n  - a.yaml\n",
+			ExpectedError: "include cycle detected: definition.yaml -> a.yaml -> b.yaml -> a.yaml",
+		},
+		`including the definition`: {
+			Fragments: map[string]string{
+				"a.yaml": "include:\n  - definition.yaml\n",
+			},
+			Definition:    "include:\n  - a.yaml\n",
+			ExpectedError: "include cycle detected: definition.yaml -> a.yaml -> definition.yaml",
+		},
+		`absolute path`: {
+			Definition:    "include:\n  - /etc/fragment.yaml\n",
+			ExpectedError: "include '/etc/fragment.yaml' must be a path relative to the image configuration directory",
+		},
+		`outside config dir`: {
+			Definition:    "include:\n  - ../fragment.yaml\n",
+			ExpectedError: "include '../fragment.yaml' must be a path relative to the image configuration directory",
+		},
+		`not a list`: {
+			Definition:    "include:\n  file: a.yaml\n",
+			ExpectedError: "'include' must be a list of file paths",
+		},
+		`fragment not a mapping`: {
+			Fragments: map[string]string{
+				"a.yaml": "- item\n",
+			},
+			Definition:    "include:\n  - a.yaml\n",
+			ExpectedError: "included file 'a.yaml' must be a mapping",
+		},
+		`conflicting types`: {
+			Fragments: map[string]string{
+				"a.yaml": "operatingSystem:\n  users:\n    username: ops\n",
+			},
+			Definition:    "include:\n  - a.yaml\noperatingSystem:\n  users:\n    - username: dev\n",
+			ExpectedError: "conflicting value types for 'operatingSystem/users'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := writeFragments(t, test.Fragments)
+
+			_, err := ResolveIncludes([]byte(test.Definition), configDir, "definition.yaml")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.ExpectedError)
+		})
+	}
+}
+
+func TestIncludedFiles(t *testing.T) {
+	configDir := writeFragments(t, map[string]string{
+		"fragments/users.yaml":  "include:\n  - fragments/groups.yaml\n",
+		"fragments/groups.yaml": "include:\n  - ./fragments/users.yaml\n",
+		"fragments/proxy.yaml":  "",
+	})
+
+	data := []byte("include:\n  - fragments/users.yaml\n  - fragments/proxy.yaml\n")
+
+	included, err := IncludedFiles(data, configDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fragments/users.yaml", "fragments/groups.yaml", "fragments/proxy.yaml"}, included)
+}
+
+func TestIncludedFiles_NoIncludes(t *testing.T) {
+	included, err := IncludedFiles([]byte("apiVersion: 1.0\n"), t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, included)
+}
@@ -0,0 +1,119 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// LatestAPIVersion is the definition schema version supported by this version of Edge Image Builder.
+	LatestAPIVersion = "1.0"
+
+	apiVersionKey = "apiVersion"
+)
+
+// migration upgrades a definition from one schema version to the next, renaming or restructuring its fields
+// in place on the parsed document.
+type migration struct {
+	from  string
+	to    string
+	apply func(document *yaml.Node) error
+}
+
+// migrations lists the upgrades between consecutive schema versions. None are needed yet, since every
+// supported definition is already on the latest schema version.
+var migrations []migration
+
+// MigrateDefinition upgrades the definition from its schema version to the latest one, returning the
+// migrated definition along with the version it was migrated from. Comments and field ordering are
+// preserved, and the migrated definition is parsed to make sure it is valid on the latest schema.
+func MigrateDefinition(data []byte) ([]byte, string, error) {
+	return migrateDefinition(data, migrations)
+}
+
+func migrateDefinition(data []byte, migrations []migration) ([]byte, string, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, "", fmt.Errorf("parsing definition: %w", err)
+	}
+
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, "", fmt.Errorf("definition must be a mapping")
+	}
+
+	document := root.Content[0]
+
+	index := mappingIndex(document, apiVersionKey)
+	if index == -1 || document.Content[index+1].Value == "" {
+		return nil, "", fmt.Errorf("definition does not specify the '%s' field", apiVersionKey)
+	}
+
+	versionNode := document.Content[index+1]
+	original := versionNode.Value
+
+	for version := original; version != LatestAPIVersion; {
+		m := findMigration(migrations, version)
+		if m == nil {
+			return nil, "", fmt.Errorf("schema version '%s' is not supported", version)
+		}
+
+		if err := m.apply(document); err != nil {
+			return nil, "", fmt.Errorf("migrating from schema version '%s' to '%s': %w", m.from, m.to, err)
+		}
+
+		version = m.to
+		versionNode.Value = version
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&root); err != nil {
+		return nil, "", fmt.Errorf("serializing migrated definition: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, "", fmt.Errorf("serializing migrated definition: %w", err)
+	}
+
+	migrated := buf.Bytes()
+	if err := validateMigratedDefinition(migrated); err != nil {
+		return nil, "", fmt.Errorf("validating migrated definition: %w", err)
+	}
+
+	return migrated, original, nil
+}
+
+// validateMigratedDefinition parses the migrated definition, ignoring any includes as the fragments
+// are migrated separately.
+func validateMigratedDefinition(data []byte) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+
+	if _, err := extractIncludes(root.Content[0]); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&root)
+	if err != nil {
+		return err
+	}
+
+	_, err = ParseDefinition(data)
+	return err
+}
+
+func findMigration(migrations []migration, from string) *migration {
+	for i := range migrations {
+		if migrations[i].from == from {
+			return &migrations[i]
+		}
+	}
+
+	return nil
+}
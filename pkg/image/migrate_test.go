@@ -0,0 +1,104 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateDefinition_Latest(t *testing.T) {
+	data := []byte(`# Edge node image
+apiVersion: 1.0
+include:
+  - fragments/users.yaml
+image:
+  imageType: iso
+  arch: x86_64
+`)
+
+	migrated, version, err := MigrateDefinition(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, LatestAPIVersion, version)
+	assert.Equal(t, string(data), string(migrated))
+}
+
+func TestMigrateDefinition(t *testing.T) {
+	// Fictional schema history: 0.8 names the image type 'type', 0.9 nests the architecture under 'platform'
+	migrations := []migration{
+		{
+			from: "0.9",
+			to:   LatestAPIVersion,
+			apply: func(document *yaml.Node) error {
+				img := document.Content[mappingIndex(document, "image")+1]
+				index := mappingIndex(img, "platform")
+				platform := img.Content[index+1]
+				img.Content[index] = platform.Content[0]
+				img.Content[index+1] = platform.Content[1]
+				return nil
+			},
+		},
+		{
+			from: "0.8",
+			to:   "0.9",
+			apply: func(document *yaml.Node) error {
+				img := document.Content[mappingIndex(document, "image")+1]
+				img.Content[mappingIndex(img, "type")].Value = "imageType"
+				return nil
+			},
+		},
+	}
+
+	data := []byte(`apiVersion: 0.8
+image:
+  # Installer image
+  type: iso
+  platform:
+    arch: aarch64
+`)
+
+	migrated, version, err := migrateDefinition(data, migrations)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.8", version)
+	assert.Equal(t, `apiVersion: 1.0
+image:
+  # Installer image
+  imageType: iso
+  arch: aarch64
+`, string(migrated))
+}
+
+func TestMigrateDefinition_Errors(t *testing.T) {
+	tests := map[string]struct {
+		Definition    string
+		ExpectedError string
+	}{
+		`missing version`: {
+			Definition:    "image:\n  imageType: iso\n",
+			ExpectedError: "definition does not specify the 'apiVersion' field",
+		},
+		`unsupported version`: {
+			Definition:    "apiVersion: 0.1\n",
+			ExpectedError: "schema version '0.1' is not supported",
+		},
+		`not a mapping`: {
+			Definition:    "- apiVersion: 1.0\n",
+			ExpectedError: "definition must be a mapping",
+		},
+		`invalid definition`: {
+			Definition:    "apiVersion: 1.0\nimage:\n  flavour: iso\n",
+			ExpectedError: "validating migrated definition",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := MigrateDefinition([]byte(test.Definition))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.ExpectedError)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package image
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	NodePathPartition = "partition"
+	NodePathSubvolume = "subvolume"
+	NodePathOverlay   = "overlay"
+	NodePathDirectory = "directory"
+	NodePathFile      = "file"
+)
+
+// NodePath is a path on the node which is either mounted or created by the image.
+type NodePath struct {
+	Path string
+	// Type is one of the NodePath types, e.g. NodePathOverlay.
+	Type string
+	// Description names the entry in messages, e.g. "tmpfs overlay" or "PAM configuration file".
+	Description string
+	// OverlayType is the type of overlays, either OverlayTypeTmpfs or OverlayTypePersistent.
+	OverlayType string
+	// Field is the path of the definition field configuring the entry, empty for the layout of the base image.
+	Field string
+}
+
+// IsMount returns whether a file system is mounted at the path.
+func (p *NodePath) IsMount() bool {
+	return p.Type == NodePathPartition || p.Type == NodePathSubvolume || p.Type == NodePathOverlay
+}
+
+// baseImageMounts are the partitions and btrfs subvolumes mounted by SL Micro base images.
+var baseImageMounts = []NodePath{
+	{Path: "/", Type: NodePathPartition, Description: "root partition"},
+	{Path: "/boot/efi", Type: NodePathPartition, Description: "EFI system partition"},
+	{Path: "/.snapshots", Type: NodePathSubvolume, Description: "btrfs subvolume"},
+	{Path: "/home", Type: NodePathSubvolume, Description: "btrfs subvolume"},
+	{Path: "/opt", Type: NodePathSubvolume, Description: "btrfs subvolume"},
+	{Path: "/root", Type: NodePathSubvolume, Description: "btrfs subvolume"},
+	{Path: "/srv", Type: NodePathSubvolume, Description: "btrfs subvolume"},
+	{Path: "/usr/local", Type: NodePathSubvolume, Description: "btrfs subvolume"},
+	{Path: "/var", Type: NodePathSubvolume, Description: "btrfs subvolume"},
+}
+
+// NodePaths returns the paths on the node which are mounted or created by the image, starting with the mounts of
+// the base image followed by the entries of the definition in the order they are configured. Entries of the
+// definition whose path is not absolute and normalized are left out.
+func (d *Definition) NodePaths() []NodePath {
+	paths := append([]NodePath{}, baseImageMounts...)
+
+	add := func(path string, p NodePath) {
+		if filepath.IsAbs(path) && filepath.Clean(path) == path {
+			p.Path = path
+			paths = append(paths, p)
+		}
+	}
+
+	operatingSystem := &d.OperatingSystem
+
+	if operatingSystem.ReadOnlyRoot.Enabled {
+		for _, overlay := range operatingSystem.ReadOnlyRoot.Overlays {
+			add(overlay.Path, NodePath{
+				Type:        NodePathOverlay,
+				Description: fmt.Sprintf("%s overlay", overlay.Type),
+				OverlayType: overlay.Type,
+				Field:       "operatingSystem/readOnlyRoot/overlays",
+			})
+		}
+	}
+
+	for _, directory := range operatingSystem.Directories {
+		add(directory.Path, NodePath{Type: NodePathDirectory, Description: "directory", Field: "operatingSystem/directories"})
+	}
+
+	for _, user := range operatingSystem.Users {
+		if user.HomeDir != "" {
+			add(user.HomeDir, NodePath{
+				Type:        NodePathDirectory,
+				Description: fmt.Sprintf("home directory of user '%s'", user.Username),
+				Field:       "operatingSystem/users/homeDir",
+			})
+		}
+	}
+
+	if operatingSystem.ImageArchives.Path != "" {
+		add(operatingSystem.ImageArchives.Path, NodePath{Type: NodePathDirectory, Description: "image archive directory", Field: "operatingSystem/imageArchives/path"})
+	}
+
+	if operatingSystem.Kdump.Memory != "" && operatingSystem.Kdump.SavePath != "" {
+		add(operatingSystem.Kdump.SavePath, NodePath{Type: NodePathDirectory, Description: "kdump save directory", Field: "operatingSystem/kdump/savePath"})
+	}
+
+	configFiles := []struct {
+		dir         string
+		names       []string
+		description string
+		field       string
+	}{
+		{dir: "/etc/systemd/network", names: operatingSystem.Networkd.ConfigFiles, description: "networkd configuration file", field: "operatingSystem/networkd/configFiles"},
+		{dir: "/etc/udev/rules.d", names: operatingSystem.Udev.Rules, description: "udev rule file", field: "operatingSystem/udev/rules"},
+		{dir: "/etc/pam.d", names: operatingSystem.PAM.ConfigFiles, description: "PAM configuration file", field: "operatingSystem/pam/configFiles"},
+		{dir: "/etc/audit/rules.d", names: operatingSystem.Audit.RuleFiles, description: "audit rule file", field: "operatingSystem/audit/ruleFiles"},
+	}
+	for _, files := range configFiles {
+		for _, name := range files.names {
+			add(filepath.Join(files.dir, name), NodePath{Type: NodePathFile, Description: files.description, Field: files.field})
+		}
+	}
+
+	if d.Kubernetes.Kubeconfig.Path != "" {
+		add(d.Kubernetes.Kubeconfig.Path, NodePath{Type: NodePathFile, Description: "kubeconfig", Field: "kubernetes/kubeconfig/path"})
+	}
+
+	return paths
+}
@@ -0,0 +1,52 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodePaths(t *testing.T) {
+	definition := &Definition{
+		OperatingSystem: OperatingSystem{
+			ReadOnlyRoot: ReadOnlyRoot{
+				Enabled:  true,
+				Overlays: []Overlay{{Path: "/usr/lib/app", Type: OverlayTypeTmpfs}},
+			},
+			Directories: []Directory{{Path: "/data"}, {Path: "relative"}, {Path: "/data/"}},
+			Users: []OperatingSystemUser{
+				{Username: "alice", HomeDir: "/data/alice"},
+				{Username: "bob"},
+			},
+			ImageArchives: ImageArchives{Path: "/var/lib/archives"},
+			Kdump:         Kdump{SavePath: "/var/crash/unused"},
+			Networkd:      Networkd{ConfigFiles: []string{"10-eth0.network"}},
+			Udev:          Udev{Rules: []string{"99-app.rules"}},
+			PAM:           PAM{ConfigFiles: []string{"sshd"}},
+			Audit:         Audit{RuleFiles: []string{"app.rules"}},
+		},
+		Kubernetes: Kubernetes{
+			Kubeconfig: Kubeconfig{Path: "/root/kubeconfig"},
+		},
+	}
+
+	paths := definition.NodePaths()
+
+	assert.Equal(t, baseImageMounts, paths[:len(baseImageMounts)])
+
+	expected := []NodePath{
+		{Path: "/usr/lib/app", Type: NodePathOverlay, Description: "tmpfs overlay", OverlayType: OverlayTypeTmpfs, Field: "operatingSystem/readOnlyRoot/overlays"},
+		{Path: "/data", Type: NodePathDirectory, Description: "directory", Field: "operatingSystem/directories"},
+		{Path: "/data/alice", Type: NodePathDirectory, Description: "home directory of user 'alice'", Field: "operatingSystem/users/homeDir"},
+		{Path: "/var/lib/archives", Type: NodePathDirectory, Description: "image archive directory", Field: "operatingSystem/imageArchives/path"},
+		{Path: "/etc/systemd/network/10-eth0.network", Type: NodePathFile, Description: "networkd configuration file", Field: "operatingSystem/networkd/configFiles"},
+		{Path: "/etc/udev/rules.d/99-app.rules", Type: NodePathFile, Description: "udev rule file", Field: "operatingSystem/udev/rules"},
+		{Path: "/etc/pam.d/sshd", Type: NodePathFile, Description: "PAM configuration file", Field: "operatingSystem/pam/configFiles"},
+		{Path: "/etc/audit/rules.d/app.rules", Type: NodePathFile, Description: "audit rule file", Field: "operatingSystem/audit/ruleFiles"},
+		{Path: "/root/kubeconfig", Type: NodePathFile, Description: "kubeconfig", Field: "kubernetes/kubeconfig/path"},
+	}
+	assert.Equal(t, expected, paths[len(baseImageMounts):])
+
+	assert.True(t, paths[len(baseImageMounts)].IsMount())
+	assert.False(t, paths[len(baseImageMounts)+1].IsMount())
+}
@@ -0,0 +1,305 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaFilename returns the name of the JSON schema of the given definition schema version within
+// a schema directory, e.g. "1.0.json".
+func SchemaFilename(apiVersion string) string {
+	return apiVersion + ".json"
+}
+
+// ValidateSchema validates the definition against the JSON schema of the apiVersion it declares, ahead of the strict
+// decoding of ParseDefinition, reporting every field which does not match the schema at once.
+//
+// The schemas are read from schemaDir, which holds a schema per supported version named after SchemaFilename.
+// If schemaDir is empty, the versions listed in version.SupportedSchemaVersions are validated against the schema
+// of the Definition type, while definitions of other versions are left to the validation of the definition.
+// Custom schemas may extend the definition with fields of their own, such definitions being decoded by
+// ParseExtendedDefinition instead.
+func ValidateSchema(data []byte, schemaDir string) error {
+	root, err := parseDocument(data)
+	if err != nil {
+		return err
+	}
+
+	var apiVersion string
+	if index := mappingIndex(root.Content[0], apiVersionKey); index != -1 {
+		apiVersion = root.Content[0].Content[index+1].Value
+	}
+
+	var schema *gojsonschema.Schema
+
+	if schemaDir == "" {
+		if !slices.Contains(version.SupportedSchemaVersions, apiVersion) {
+			return nil
+		}
+
+		schema, err = gojsonschema.NewSchema(gojsonschema.NewGoLoader(definitionSchema()))
+	} else {
+		if apiVersion == "" {
+			return fmt.Errorf("definition does not specify the '%s' field", apiVersionKey)
+		}
+
+		schema, err = loadSchema(schemaDir, apiVersion)
+	}
+	if err != nil {
+		return fmt.Errorf("loading schema of version '%s': %w", apiVersion, err)
+	}
+
+	result, err := validateDocument(schema, root)
+	if err != nil {
+		return err
+	}
+
+	if result.Valid() {
+		return nil
+	}
+
+	var failures []string
+	for _, e := range result.Errors() {
+		failures = append(failures, fmt.Sprintf("%s: %s", schemaFieldPath(e.Field()), e.Description()))
+	}
+
+	return fmt.Errorf("definition does not match the schema of version '%s': %s", apiVersion, strings.Join(failures, "; "))
+}
+
+// UnknownFields returns the paths of the fields of the definition which are not part of the Definition type, e.g.
+// "operatingSystem/users/0/loginShell", in the order they are reported by the schema validation. These are the fields
+// ignored by ParseExtendedDefinition.
+func UnknownFields(data []byte) ([]string, error) {
+	root, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(definitionSchema()))
+	if err != nil {
+		return nil, fmt.Errorf("loading definition schema: %w", err)
+	}
+
+	result, err := validateDocument(schema, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for _, e := range result.Errors() {
+		if e.Type() != "additional_property_not_allowed" {
+			continue
+		}
+
+		field := fmt.Sprint(e.Details()["property"])
+		if e.Field() != gojsonschema.STRING_CONTEXT_ROOT {
+			field = schemaFieldPath(e.Field()) + "/" + field
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// parseDocument parses the definition into a YAML node tree, whose document must be a mapping.
+func parseDocument(data []byte) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing definition: %w", err)
+	}
+
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("definition must be a mapping")
+	}
+
+	return &root, nil
+}
+
+// validateDocument validates the parsed definition against the schema.
+func validateDocument(schema *gojsonschema.Schema, root *yaml.Node) (*gojsonschema.Result, error) {
+	var document any
+	if err := root.Decode(&document); err != nil {
+		return nil, fmt.Errorf("decoding definition: %w", err)
+	}
+
+	document, err := jsonValue(document)
+	if err != nil {
+		return nil, fmt.Errorf("converting definition: %w", err)
+	}
+
+	result, err := schema.Validate(gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return nil, fmt.Errorf("validating definition: %w", err)
+	}
+
+	return result, nil
+}
+
+// loadSchema reads the schema of the given version from the schema directory, which must declare the
+// version as the only accepted value of its apiVersion property.
+func loadSchema(schemaDir, apiVersion string) (*gojsonschema.Schema, error) {
+	path := filepath.Join(schemaDir, SchemaFilename(apiVersion))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("no schema found at '%s'", path)
+		}
+
+		return nil, fmt.Errorf("reading schema: %w", err)
+	}
+
+	var declared struct {
+		Properties struct {
+			APIVersion struct {
+				Const *string  `json:"const"`
+				Enum  []string `json:"enum"`
+			} `json:"apiVersion"`
+		} `json:"properties"`
+	}
+	if err = json.Unmarshal(data, &declared); err != nil {
+		return nil, fmt.Errorf("parsing schema '%s': %w", path, err)
+	}
+
+	property := declared.Properties.APIVersion
+	switch {
+	case property.Const != nil:
+		if *property.Const != apiVersion {
+			return nil, fmt.Errorf("schema '%s' declares version '%s'", path, *property.Const)
+		}
+	case len(property.Enum) == 1:
+		if property.Enum[0] != apiVersion {
+			return nil, fmt.Errorf("schema '%s' declares version '%s'", path, property.Enum[0])
+		}
+	default:
+		return nil, fmt.Errorf("schema '%s' must declare its version as the 'const' of the '%s' property", path, apiVersionKey)
+	}
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, fmt.Errorf("compiling schema '%s': %w", path, err)
+	}
+
+	return schema, nil
+}
+
+// schemaFieldPath formats the field of a schema validation error in the same way as the fields of
+// validation failures, e.g. "operatingSystem/users/0/username".
+func schemaFieldPath(field string) string {
+	if field == gojsonschema.STRING_CONTEXT_ROOT {
+		return "(root)"
+	}
+
+	return strings.ReplaceAll(field, ".", "/")
+}
+
+// jsonValue converts a decoded YAML value into a value which can be serialized as JSON, rejecting mappings
+// with keys other than strings.
+func jsonValue(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+
+		return v, nil
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("mapping key '%v' is not a string", key)
+			}
+
+			convertedItem, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			converted[name] = convertedItem
+		}
+
+		return converted, nil
+	case []any:
+		for i, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// definitionSchema returns the JSON schema of the Definition type, which accepts the same documents as its
+// strict decoding: unknown fields are rejected, while strings accept any scalar and every field may be null.
+func definitionSchema() map[string]any {
+	return typeSchema(reflect.TypeOf(Definition{}))
+}
+
+var yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+func typeSchema(t reflect.Type) map[string]any {
+	// Types decoding themselves may accept several representations
+	if t.Implements(yamlUnmarshalerType) || reflect.PointerTo(t).Implements(yamlUnmarshalerType) {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		properties := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+
+			properties[name] = typeSchema(field.Type)
+		}
+
+		return map[string]any{"type": []string{"object", "null"}, "properties": properties, "additionalProperties": false}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": []string{"array", "null"}, "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": []string{"string", "number", "boolean", "null"}}
+	case reflect.Bool:
+		return map[string]any{"type": []string{"boolean", "null"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": []string{"integer", "null"}}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": []string{"number", "null"}}
+	default:
+		return map[string]any{}
+	}
+}
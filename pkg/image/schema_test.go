@@ -0,0 +1,211 @@
+package image
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const testSchema = `{
+  "type": "object",
+  "properties": {
+    "apiVersion": {"const": "1.0"},
+    "image": {
+      "type": "object",
+      "properties": {
+        "imageType": {"enum": ["iso", "raw"]}
+      },
+      "required": ["imageType"]
+    }
+  },
+  "required": ["image"]
+}`
+
+func writeTestSchema(t *testing.T, dir, apiVersion, contents string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SchemaFilename(apiVersion)), []byte(contents), 0o600))
+}
+
+func TestSupportedSchemaVersions(t *testing.T) {
+	assert.Contains(t, version.SupportedSchemaVersions, LatestAPIVersion)
+}
+
+func TestValidateSchema_Default(t *testing.T) {
+	// Setup
+	configData, err := os.ReadFile("./testdata/full-valid-example.yaml")
+	require.NoError(t, err)
+
+	// Test
+	err = ValidateSchema(configData, "")
+
+	// Verify
+	require.NoError(t, err)
+}
+
+func TestValidateSchema_DefaultMismatch(t *testing.T) {
+	// Setup
+	configData := []byte(`apiVersion: "1.0"
+image:
+  imageType: iso
+  baseImage: [slemicro.iso]
+operatingSystem:
+  users:
+    - username: alice
+      sshKeys: key
+  unknownField: true
+`)
+
+	// Test
+	err := ValidateSchema(configData, "")
+
+	// Verify
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "definition does not match the schema of version '1.0'")
+	assert.Contains(t, err.Error(), "image/baseImage: Invalid type.")
+	assert.Contains(t, err.Error(), "operatingSystem/users/0/sshKeys: Invalid type.")
+	assert.Contains(t, err.Error(), "operatingSystem: Additional property unknownField is not allowed")
+}
+
+func TestValidateSchema_DefaultUnsupportedVersion(t *testing.T) {
+	// Unsupported versions are reported by the validation of the definition
+	err := ValidateSchema([]byte("apiVersion: \"0.9\"\nunknown: true\n"), "")
+
+	require.NoError(t, err)
+}
+
+func TestValidateSchema_SchemaDir(t *testing.T) {
+	// Setup
+	schemaDir := t.TempDir()
+	writeTestSchema(t, schemaDir, "1.0", testSchema)
+
+	tests := map[string]struct {
+		configData    string
+		expectedError string
+	}{
+		"valid": {
+			configData: "apiVersion: \"1.0\"\nimage:\n  imageType: raw\n",
+		},
+		"invalid value": {
+			configData:    "apiVersion: \"1.0\"\nimage:\n  imageType: qcow2\n",
+			expectedError: "definition does not match the schema of version '1.0': image/imageType: image.imageType must be one of the following: \"iso\", \"raw\"",
+		},
+		"missing field": {
+			configData:    "apiVersion: \"1.0\"\n",
+			expectedError: "definition does not match the schema of version '1.0': (root): image is required",
+		},
+		"missing apiVersion": {
+			configData:    "image:\n  imageType: raw\n",
+			expectedError: "definition does not specify the 'apiVersion' field",
+		},
+		"missing schema": {
+			configData:    "apiVersion: \"2.0\"\n",
+			expectedError: "loading schema of version '2.0': no schema found at '" + filepath.Join(schemaDir, "2.0.json") + "'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateSchema([]byte(test.configData), schemaDir)
+
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateSchema_InvalidSchema(t *testing.T) {
+	configData := []byte("apiVersion: \"1.0\"\n")
+
+	tests := map[string]struct {
+		schema        string
+		expectedError string
+	}{
+		"mismatched version": {
+			schema:        `{"properties": {"apiVersion": {"const": "1.1"}}}`,
+			expectedError: "schema '%s' declares version '1.1'",
+		},
+		"mismatched enum version": {
+			schema:        `{"properties": {"apiVersion": {"enum": ["1.1"]}}}`,
+			expectedError: "schema '%s' declares version '1.1'",
+		},
+		"undeclared version": {
+			schema:        `{"properties": {"apiVersion": {"type": "string"}}}`,
+			expectedError: "schema '%s' must declare its version as the 'const' of the 'apiVersion' property",
+		},
+		"invalid JSON": {
+			schema:        `{"properties":`,
+			expectedError: "parsing schema '%s'",
+		},
+		"invalid schema": {
+			schema:        `{"properties": {"apiVersion": {"const": "1.0"}, "image": {"type": "mapping"}}}`,
+			expectedError: "compiling schema '%s'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schemaDir := t.TempDir()
+			writeTestSchema(t, schemaDir, "1.0", test.schema)
+
+			err := ValidateSchema(configData, schemaDir)
+
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "loading schema of version '1.0'")
+			assert.Contains(t, err.Error(), fmt.Sprintf(test.expectedError, filepath.Join(schemaDir, "1.0.json")))
+		})
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	configData := []byte(`apiVersion: "1.0"
+image:
+  imageType: iso
+  site: lab-1
+operatingSystem:
+  users:
+    - username: alice
+      loginShell: /bin/zsh
+owner: edge-team
+`)
+
+	fields, err := UnknownFields(configData)
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"image/site", "operatingSystem/users/0/loginShell", "owner"}, fields)
+}
+
+func TestUnknownFields_None(t *testing.T) {
+	configData, err := os.ReadFile("./testdata/full-valid-example.yaml")
+	require.NoError(t, err)
+
+	fields, err := UnknownFields(configData)
+
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestParseExtendedDefinition(t *testing.T) {
+	configData := []byte(`apiVersion: "1.0"
+image:
+  imageType: iso
+  site: lab-1
+owner: edge-team
+`)
+
+	_, err := ParseDefinition(configData)
+	require.Error(t, err)
+
+	definition, err := ParseExtendedDefinition(configData)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.0", definition.APIVersion)
+	assert.Equal(t, TypeISO, definition.Image.ImageType)
+}
@@ -1,6 +1,10 @@
 package validation
 
-import "github.com/suse-edge/edge-image-builder/pkg/image"
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
 
 const (
 	apiVersionComponent = "Definition Schema"
@@ -9,9 +13,10 @@ const (
 func validateAPIVersion(ctx *image.Context) *FailedValidation {
 	definitionVersion := ctx.ImageDefinition.APIVersion
 
-	if definitionVersion != "1.0" {
+	if definitionVersion != image.LatestAPIVersion {
 		return &FailedValidation{
-			UserMessage: "This version of Edge Image Builder only supports version '1.0' of the definition schema.",
+			Field:       "apiVersion",
+			UserMessage: fmt.Sprintf("This version of Edge Image Builder only supports version '%s' of the definition schema.", image.LatestAPIVersion),
 		}
 	}
 
@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// autologinTTYRegex matches the virtual consoles (tty1-tty63) and the serial consoles a getty can be started on
+var autologinTTYRegex = regexp.MustCompile(`^(tty([1-9]|[1-5][0-9]|6[0-3])|ttyS[0-9]+|ttyAMA[0-9]+|hvc[0-9]+)$`)
+
+func validateAutologin(os *image.OperatingSystem) []FailedValidation {
+	autologin := os.Autologin
+	if autologin == (image.Autologin{}) {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if autologin.User == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/autologin/user",
+			UserMessage: "The 'user' field is required in the 'autologin' section.",
+		})
+	}
+
+	if autologin.TTY != "" && !autologinTTYRegex.MatchString(autologin.TTY) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/autologin/tty",
+			UserMessage: fmt.Sprintf("The autologin 'tty' field '%s' must be a virtual console (tty1 to tty63) "+
+				"or a serial console (e.g. ttyS0, ttyAMA0 or hvc0), without the '/dev/' prefix.", autologin.TTY),
+		})
+	}
+
+	if autologin.User == "" {
+		return failures
+	}
+
+	var user *image.OperatingSystemUser
+	for i := range os.Users {
+		if os.Users[i].Username == autologin.User {
+			user = &os.Users[i]
+			break
+		}
+	}
+
+	if user == nil {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/autologin/user",
+			UserMessage: fmt.Sprintf("The autologin user '%s' must be configured in the 'users' section.", autologin.User),
+		})
+
+		return failures
+	}
+
+	failures = append(failures, FailedValidation{
+		Field: "operatingSystem/autologin",
+		UserMessage: fmt.Sprintf("The user '%s' will be logged in on %s without authentication, giving anyone "+
+			"with access to the console a shell on the node.", autologin.User, autologin.Console()),
+		Warning: true,
+	})
+
+	if user.Username == "root" || user.Sudo.Policy == image.SudoPolicyAll {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/autologin/user",
+			UserMessage: fmt.Sprintf("The autologin user '%s' has unrestricted privileges, consider logging in "+
+				"an unprivileged user instead.", autologin.User),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
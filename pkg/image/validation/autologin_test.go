@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateAutologin(t *testing.T) {
+	users := []image.OperatingSystemUser{
+		{Username: "root", EncryptedPassword: "$6$root"},
+		{Username: "kiosk", EncryptedPassword: "$6$kiosk"},
+		{Username: "admin", EncryptedPassword: "$6$admin", Sudo: image.UserSudo{Policy: image.SudoPolicyAll}},
+	}
+
+	unauthenticatedWarning := func(user, tty string) string {
+		return "The user '" + user + "' will be logged in on " + tty + " without authentication, giving anyone " +
+			"with access to the console a shell on the node."
+	}
+
+	tests := map[string]struct {
+		Autologin              image.Autologin
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`default tty`: {
+			Autologin: image.Autologin{User: "kiosk"},
+			ExpectedFailedMessages: []string{
+				unauthenticatedWarning("kiosk", "tty1"),
+			},
+			ExpectedWarnings: 1,
+		},
+		`serial console`: {
+			Autologin: image.Autologin{User: "kiosk", TTY: "ttyS0"},
+			ExpectedFailedMessages: []string{
+				unauthenticatedWarning("kiosk", "ttyS0"),
+			},
+			ExpectedWarnings: 1,
+		},
+		`missing user`: {
+			Autologin: image.Autologin{TTY: "tty2"},
+			ExpectedFailedMessages: []string{
+				"The 'user' field is required in the 'autologin' section.",
+			},
+		},
+		`undefined user`: {
+			Autologin: image.Autologin{User: "guest"},
+			ExpectedFailedMessages: []string{
+				"The autologin user 'guest' must be configured in the 'users' section.",
+			},
+		},
+		`invalid tty`: {
+			Autologin: image.Autologin{User: "kiosk", TTY: "/dev/tty1"},
+			ExpectedFailedMessages: []string{
+				"The autologin 'tty' field '/dev/tty1' must be a virtual console (tty1 to tty63) or a serial console (e.g. ttyS0, ttyAMA0 or hvc0), without the '/dev/' prefix.",
+				unauthenticatedWarning("kiosk", "/dev/tty1"),
+			},
+			ExpectedWarnings: 1,
+		},
+		`tty out of range`: {
+			Autologin: image.Autologin{User: "kiosk", TTY: "tty64"},
+			ExpectedFailedMessages: []string{
+				"The autologin 'tty' field 'tty64' must be a virtual console (tty1 to tty63) or a serial console (e.g. ttyS0, ttyAMA0 or hvc0), without the '/dev/' prefix.",
+				unauthenticatedWarning("kiosk", "tty64"),
+			},
+			ExpectedWarnings: 1,
+		},
+		`root user`: {
+			Autologin: image.Autologin{User: "root"},
+			ExpectedFailedMessages: []string{
+				unauthenticatedWarning("root", "tty1"),
+				"The autologin user 'root' has unrestricted privileges, consider logging in an unprivileged user instead.",
+			},
+			ExpectedWarnings: 2,
+		},
+		`unrestricted sudo`: {
+			Autologin: image.Autologin{User: "admin", TTY: "tty3"},
+			ExpectedFailedMessages: []string{
+				unauthenticatedWarning("admin", "tty3"),
+				"The autologin user 'admin' has unrestricted privileges, consider logging in an unprivileged user instead.",
+			},
+			ExpectedWarnings: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				Users:     users,
+				Autologin: test.Autologin,
+			}
+
+			failures := validateAutologin(&os)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+)
+
+// validateCertificates checks that the certificate files only contain CA certificates and that every chain
+// is coherent, i.e. each certificate is signed by the CA it names as its issuer when that is included.
+func validateCertificates(imageConfigDir string) []FailedValidation {
+	certsDir := filepath.Join(imageConfigDir, combustion.CertificatesDir)
+
+	entries, err := os.ReadDir(certsDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return []FailedValidation{{
+			UserMessage: fmt.Sprintf("The '%s' directory could not be read.", combustion.CertificatesDir),
+			Error:       err,
+		}}
+	}
+
+	var failures []FailedValidation
+
+	type certificate struct {
+		file string
+		cert *x509.Certificate
+	}
+
+	var certs []certificate
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !combustion.IsCertificateFile(name) {
+			continue
+		}
+
+		if strings.ContainsRune(name, '\'') || strings.ContainsFunc(name, unicode.IsControl) {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("The certificate file name %q cannot contain single quotes or control characters.", name),
+			})
+
+			continue
+		}
+
+		data, readErr := os.ReadFile(filepath.Join(certsDir, name))
+		if readErr != nil {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("The certificate file '%s' could not be read.", name),
+				Error:       readErr,
+			})
+
+			continue
+		}
+
+		parsed, parseErr := combustion.ParseCertificates(data)
+		if parseErr != nil {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("The certificate file '%s' must only contain PEM encoded certificates.", name),
+				Error:       parseErr,
+			})
+
+			continue
+		}
+
+		for _, cert := range parsed {
+			if !cert.BasicConstraintsValid || !cert.IsCA {
+				failures = append(failures, FailedValidation{
+					UserMessage: fmt.Sprintf("The certificate '%s' in the certificate file '%s' is not a CA certificate.", cert.Subject, name),
+				})
+
+				continue
+			}
+
+			certs = append(certs, certificate{file: name, cert: cert})
+		}
+	}
+
+	if len(certs) == 0 {
+		if len(failures) == 0 {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("The '%s' directory must contain at least one '.pem' or '.crt' certificate file.", combustion.CertificatesDir),
+			})
+		}
+
+		return failures
+	}
+
+	pool := make([]*x509.Certificate, 0, len(certs))
+	for _, c := range certs {
+		pool = append(pool, c.cert)
+	}
+
+	for _, c := range certs {
+		if combustion.CertificateIssuer(c.cert, pool) != nil {
+			continue
+		}
+
+		selfIssued := bytes.Equal(c.cert.RawIssuer, c.cert.RawSubject)
+		issuerIncluded := selfIssued
+		for _, candidate := range pool {
+			if bytes.Equal(c.cert.RawIssuer, candidate.RawSubject) {
+				issuerIncluded = true
+			}
+		}
+
+		if issuerIncluded {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("The certificate '%s' in the certificate file '%s' is not signed by its issuer '%s'.",
+					c.cert.Subject, c.file, c.cert.Issuer),
+			})
+
+			continue
+		}
+
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("The issuer '%s' of the certificate '%s' in the certificate file '%s' is not included "+
+				"in the '%s' directory, and must already be trusted on the node to complete the chain.",
+				c.cert.Issuer, c.cert.Subject, c.file, combustion.CertificatesDir),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
@@ -0,0 +1,171 @@
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificate struct {
+	raw []byte
+	key *ecdsa.PrivateKey
+	tpl *x509.Certificate
+}
+
+// newTestCertificate creates a certificate issued by the given certificate, or a self-signed one if none is given.
+func newTestCertificate(t *testing.T, name string, isCA bool, issuer *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		tpl.KeyUsage = x509.KeyUsageCertSign
+	}
+
+	parent, signer := tpl, key
+	if issuer != nil {
+		parent, signer = issuer.tpl, issuer.key
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+
+	return &testCertificate{raw: raw, key: key, tpl: tpl}
+}
+
+func encodeTestCertificates(certs ...*testCertificate) string {
+	var data []byte
+	for _, cert := range certs {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.raw})...)
+	}
+
+	return string(data)
+}
+
+func TestValidateCertificates(t *testing.T) {
+	root := newTestCertificate(t, "Root CA", true, nil)
+	intermediate := newTestCertificate(t, "Intermediate CA", true, root)
+	issuing := newTestCertificate(t, "Issuing CA", true, intermediate)
+	impostor := newTestCertificate(t, "Root CA", true, nil)
+	external := newTestCertificate(t, "External CA", true, newTestCertificate(t, "External Root CA", true, nil))
+	leaf := newTestCertificate(t, "server.example.com", false, root)
+
+	tests := map[string]struct {
+		Files                  map[string]string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {},
+		`single root CA`: {
+			Files: map[string]string{
+				"root.pem": encodeTestCertificates(root),
+			},
+		},
+		`chain across files`: {
+			Files: map[string]string{
+				"chain.pem": encodeTestCertificates(issuing, intermediate),
+				"root.crt":  encodeTestCertificates(root),
+				"README":    "not a certificate",
+			},
+		},
+		`no certificate files`: {
+			Files: map[string]string{
+				"README": "not a certificate",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'certificates' directory must contain at least one '.pem' or '.crt' certificate file.",
+			},
+		},
+		`invalid certificate file`: {
+			Files: map[string]string{
+				"invalid.pem": "not a certificate",
+				"root.pem":    encodeTestCertificates(root),
+			},
+			ExpectedFailedMessages: []string{
+				"The certificate file 'invalid.pem' must only contain PEM encoded certificates.",
+			},
+		},
+		`unsafe file name`: {
+			Files: map[string]string{
+				"o'root.pem": encodeTestCertificates(root),
+				"root.pem":   encodeTestCertificates(root),
+			},
+			ExpectedFailedMessages: []string{
+				"The certificate file name \"o'root.pem\" cannot contain single quotes or control characters.",
+			},
+		},
+		`not a CA`: {
+			Files: map[string]string{
+				"chain.pem": encodeTestCertificates(leaf, root),
+			},
+			ExpectedFailedMessages: []string{
+				"The certificate 'CN=server.example.com' in the certificate file 'chain.pem' is not a CA certificate.",
+			},
+		},
+		`broken issuer link`: {
+			Files: map[string]string{
+				"chain.pem": encodeTestCertificates(intermediate, impostor),
+			},
+			ExpectedFailedMessages: []string{
+				"The certificate 'CN=Intermediate CA' in the certificate file 'chain.pem' is not signed by its issuer 'CN=Root CA'.",
+			},
+		},
+		`issuer not included`: {
+			Files: map[string]string{
+				"external.pem": encodeTestCertificates(external),
+			},
+			ExpectedFailedMessages: []string{
+				"The issuer 'CN=External Root CA' of the certificate 'CN=External CA' in the certificate file 'external.pem' " +
+					"is not included in the 'certificates' directory, and must already be trusted on the node to complete the chain.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+
+			if test.Files != nil {
+				certsDir := filepath.Join(configDir, "certificates")
+				require.NoError(t, os.Mkdir(certsDir, 0o755))
+
+				for file, data := range test.Files {
+					require.NoError(t, os.WriteFile(filepath.Join(certsDir, file), []byte(data), 0o600))
+				}
+			}
+
+			failures := validateCertificates(configDir)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// validateChartImageDigests warns about the Helm charts whose values reference an image by tag which is pinned by
+// digest under 'embeddedArtifactRegistry', as only the pinned image is embedded. The referenced images are found
+// by the conventional 'image' fields of the values files, so the check is best-effort and may be skipped.
+func validateChartImageDigests(ctx *image.Context) []FailedValidation {
+	def := ctx.ImageDefinition
+	if ctx.SkipChartImageCheck || len(def.Kubernetes.Helm.Charts) == 0 {
+		return nil
+	}
+
+	pinned := map[string]string{}
+	for _, img := range def.EmbeddedArtifactRegistry.ContainerImages {
+		named, err := reference.ParseNormalizedNamed(img.Name)
+		if err != nil {
+			// Invalid references are reported by the validation of their section
+			continue
+		}
+
+		if _, isDigested := named.(reference.Digested); isDigested {
+			pinned[named.Name()] = img.Name
+		}
+	}
+
+	if len(pinned) == 0 {
+		return nil
+	}
+
+	var failures []FailedValidation
+	for _, chart := range def.Kubernetes.Helm.Charts {
+		if chart.ValuesFile == "" {
+			continue
+		}
+
+		valuesPath := filepath.Join(ctx.ImageConfigDir, combustion.K8sDir, combustion.HelmDir, combustion.ValuesDir, chart.ValuesFile)
+		data, err := os.ReadFile(valuesPath)
+		if err != nil {
+			// Missing values files are reported by the validation of the chart
+			continue
+		}
+
+		var values any
+		if err = yaml.Unmarshal(data, &values); err != nil {
+			zap.S().Debugf("Skipping image check of Helm chart values file '%s' which failed to parse: %s", chart.ValuesFile, err)
+			continue
+		}
+
+		var reported []string
+		for _, ref := range chartValuesImages(values) {
+			named, parseErr := reference.ParseNormalizedNamed(ref)
+			if parseErr != nil {
+				continue
+			}
+
+			if _, isDigested := named.(reference.Digested); isDigested {
+				continue
+			}
+
+			pinnedImage, ok := pinned[named.Name()]
+			if !ok || slices.Contains(reported, ref) {
+				continue
+			}
+			reported = append(reported, ref)
+
+			failures = append(failures, FailedValidation{
+				Field: "kubernetes/helm/charts/valuesFile",
+				UserMessage: fmt.Sprintf("The values file '%s' of the Helm chart '%s' references the image '%s' by tag, which is "+
+					"not embedded as the image is pinned by digest as '%s' under 'embeddedArtifactRegistry'. Reference the image "+
+					"by digest in the values file, or use --skip-chart-image-check if the values are not parsed accurately.",
+					chart.ValuesFile, chart.Name, ref, pinnedImage),
+				Warning: true,
+			})
+		}
+	}
+
+	return failures
+}
+
+// chartValuesImages lists the image references found in Helm chart values, either given as a single reference or
+// split into the 'registry', 'repository', 'tag' and 'digest' fields as is conventional for charts. Images are
+// looked up in the fields named 'image' or ending with 'Image' (e.g. 'initImage').
+func chartValuesImages(values any) []string {
+	var images []string
+
+	switch v := values.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if key == "image" || strings.HasSuffix(key, "Image") {
+				if ref := chartValuesImage(value); ref != "" {
+					images = append(images, ref)
+					continue
+				}
+			}
+
+			images = append(images, chartValuesImages(value)...)
+		}
+	case []any:
+		for _, value := range v {
+			images = append(images, chartValuesImages(value)...)
+		}
+	}
+
+	slices.Sort(images)
+
+	return images
+}
+
+func chartValuesImage(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		repository, _ := v["repository"].(string)
+		if repository == "" {
+			return ""
+		}
+
+		ref := repository
+		if registry, _ := v["registry"].(string); registry != "" {
+			ref = registry + "/" + repository
+		}
+
+		if digest, _ := v["digest"].(string); digest != "" {
+			return ref + "@" + digest
+		}
+
+		// Numerical tags are parsed as numbers
+		if tag := v["tag"]; tag != nil && tag != "" {
+			ref += ":" + fmt.Sprint(tag)
+		}
+
+		return ref
+	}
+
+	return ""
+}
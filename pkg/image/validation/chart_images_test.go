@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateChartImageDigests(t *testing.T) {
+	pinned := "docker.io/library/nginx@sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+
+	tests := map[string]struct {
+		Values                 string
+		ContainerImages        []image.ContainerImage
+		Skip                   bool
+		ExpectedFailedMessages []string
+	}{
+		`no pinned images`: {
+			Values:          "image: nginx:1.25\n",
+			ContainerImages: []image.ContainerImage{{Name: "nginx:1.25"}},
+		},
+		`image referenced by digest`: {
+			Values:          "image:\n  repository: nginx\n  digest: sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4\n",
+			ContainerImages: []image.ContainerImage{{Name: pinned}},
+		},
+		`unrelated tagged image`: {
+			Values:          "image: registry.example.com/app:1.0\n",
+			ContainerImages: []image.ContainerImage{{Name: pinned}},
+		},
+		`image referenced by tag`: {
+			Values:          "image: nginx:1.25\n",
+			ContainerImages: []image.ContainerImage{{Name: pinned}},
+			ExpectedFailedMessages: []string{
+				"The values file 'values.yaml' of the Helm chart 'web' references the image 'nginx:1.25' by tag, which is " +
+					"not embedded as the image is pinned by digest as '" + pinned + "' under 'embeddedArtifactRegistry'. " +
+					"Reference the image by digest in the values file, or use --skip-chart-image-check if the values are not parsed accurately.",
+			},
+		},
+		`split image fields`: {
+			Values: "controller:\n  initImage:\n    registry: docker.io\n    repository: library/nginx\n    tag: 1.25\n" +
+				"sidecars:\n  - image: nginx:1.25\n",
+			ContainerImages: []image.ContainerImage{{Name: pinned}},
+			ExpectedFailedMessages: []string{
+				"The values file 'values.yaml' of the Helm chart 'web' references the image 'docker.io/library/nginx:1.25' by tag, which is " +
+					"not embedded as the image is pinned by digest as '" + pinned + "' under 'embeddedArtifactRegistry'. " +
+					"Reference the image by digest in the values file, or use --skip-chart-image-check if the values are not parsed accurately.",
+				"The values file 'values.yaml' of the Helm chart 'web' references the image 'nginx:1.25' by tag, which is " +
+					"not embedded as the image is pinned by digest as '" + pinned + "' under 'embeddedArtifactRegistry'. " +
+					"Reference the image by digest in the values file, or use --skip-chart-image-check if the values are not parsed accurately.",
+			},
+		},
+		`skipped`: {
+			Values:          "image: nginx:1.25\n",
+			ContainerImages: []image.ContainerImage{{Name: pinned}},
+			Skip:            true,
+		},
+		`unparsable values`: {
+			Values:          "image: [nginx\n",
+			ContainerImages: []image.ContainerImage{{Name: pinned}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+			valuesDir := filepath.Join(configDir, "kubernetes", "helm", "values")
+			require.NoError(t, os.MkdirAll(valuesDir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(valuesDir, "values.yaml"), []byte(test.Values), 0o600))
+
+			ctx := &image.Context{
+				ImageConfigDir:      configDir,
+				SkipChartImageCheck: test.Skip,
+				ImageDefinition: &image.Definition{
+					EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+						ContainerImages: test.ContainerImages,
+					},
+					Kubernetes: image.Kubernetes{
+						Helm: image.Helm{
+							Charts: []image.HelmChart{{Name: "web", ValuesFile: "values.yaml"}},
+						},
+					},
+				},
+			}
+
+			failures := validateChartImageDigests(ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.True(t, foundValidation.Warning)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
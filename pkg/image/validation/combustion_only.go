@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// validateCombustionOnly checks the definition for building only the combustion ISO. The base image is not
+// required, as it is only used to resolve packages, and the options applied while assembling the image are ignored.
+func validateCombustionOnly(ctx *image.Context) []FailedValidation {
+	def := ctx.ImageDefinition
+
+	var failures []FailedValidation
+
+	assemblyOptions := []struct {
+		name string
+		set  bool
+	}{
+		{name: "image/outputFormat", set: def.Image.OutputFormat != ""},
+		{name: "image/qcow2Configuration", set: def.Image.Qcow2Configuration != image.Qcow2Configuration{}},
+		{name: "image/rootFilesystem", set: def.Image.RootFilesystem.Type != "" || len(def.Image.RootFilesystem.MountOptions) > 0},
+		{name: "operatingSystem/isoConfiguration", set: def.OperatingSystem.IsoConfiguration != image.IsoConfiguration{}},
+		{name: "operatingSystem/rawConfiguration", set: def.OperatingSystem.RawConfiguration != image.RawConfiguration{}},
+		{name: "operatingSystem/kernelArgs", set: len(def.OperatingSystem.KernelArgs.Add) > 0 || len(def.OperatingSystem.KernelArgs.Remove) > 0},
+		{name: "operatingSystem/remove", set: len(def.OperatingSystem.Remove) > 0},
+		{name: "operatingSystem/stripDocs", set: def.OperatingSystem.StripDocs.Enabled},
+		{name: "operatingSystem/packages/kernel", set: def.OperatingSystem.Packages.Kernel != image.Kernel{}},
+		{name: "operatingSystem/secureBoot/signing", set: def.OperatingSystem.SecureBoot.Signing != image.SecureBootSigning{}},
+		{name: "operatingSystem/secureBoot/lockdown", set: def.OperatingSystem.SecureBoot.Lockdown != ""},
+		{name: "operatingSystem/kdump/memory", set: def.OperatingSystem.Kdump.Memory != ""},
+	}
+
+	for _, option := range assemblyOptions {
+		if option.set {
+			failures = append(failures, FailedValidation{
+				Field:       option.name,
+				UserMessage: fmt.Sprintf("The '%s' field is applied while assembling the image and is ignored when building only the combustion ISO.", option.name),
+				Warning:     true,
+			})
+		}
+	}
+
+	if combustion.SkipRPMComponent(ctx) {
+		return failures
+	}
+
+	// Packages are resolved against the base image
+	if ctx.BaseImageOverride == "" && def.Image.BaseImage == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "image/baseImage",
+			UserMessage: "The 'baseImage' field is required in the 'image' section to resolve the configured packages, even when building only the combustion ISO.",
+		})
+	} else if _, err := os.Stat(ctx.BaseImagePath()); err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "image/baseImage",
+			UserMessage: fmt.Sprintf("The base image '%s' is required to resolve the configured packages, even when building only the combustion ISO, but it cannot be read.", ctx.BaseImagePath()),
+			Error:       err,
+		})
+	}
+
+	return failures
+}
@@ -0,0 +1,142 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateCombustionOnly(t *testing.T) {
+	imageConfigDir := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(imageConfigDir, "base-images"), os.ModePerm))
+	_, err := os.Create(filepath.Join(imageConfigDir, "base-images", "base-image.raw"))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		ImageDefinition        image.Definition
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`without base image`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					OutputImageName: "eib-created.iso",
+				},
+			},
+		},
+		`assembly options`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					OutputImageName: "eib-created.raw",
+					RootFilesystem: image.RootFilesystem{
+						MountOptions: []string{"noatime"},
+					},
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "32G",
+					},
+					Remove:    []string{"/usr/share/doc"},
+					StripDocs: image.StripDocs{Enabled: true},
+					SecureBoot: image.SecureBoot{
+						MOKCertificates: []string{"edge.der"},
+						Lockdown:        image.LockdownIntegrity,
+					},
+					Kdump: image.Kdump{Memory: "512M"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'image/rootFilesystem' field is applied while assembling the image and is ignored when building only the combustion ISO.",
+				"The 'operatingSystem/rawConfiguration' field is applied while assembling the image and is ignored when building only the combustion ISO.",
+				"The 'operatingSystem/remove' field is applied while assembling the image and is ignored when building only the combustion ISO.",
+				"The 'operatingSystem/stripDocs' field is applied while assembling the image and is ignored when building only the combustion ISO.",
+				"The 'operatingSystem/secureBoot/lockdown' field is applied while assembling the image and is ignored when building only the combustion ISO.",
+				"The 'operatingSystem/kdump/memory' field is applied while assembling the image and is ignored when building only the combustion ISO.",
+			},
+			ExpectedWarnings: 6,
+		},
+		`packages with base image`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.raw",
+					OutputImageName: "eib-created.raw",
+				},
+				OperatingSystem: image.OperatingSystem{
+					Packages: image.Packages{
+						PKGList: []string{"vim"},
+					},
+				},
+			},
+		},
+		`packages without base image`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					OutputImageName: "eib-created.raw",
+				},
+				OperatingSystem: image.OperatingSystem{
+					Packages: image.Packages{
+						PKGList: []string{"vim"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'baseImage' field is required in the 'image' section to resolve the configured packages, even when building only the combustion ISO.",
+			},
+		},
+		`packages with missing base image`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "missing.raw",
+					OutputImageName: "eib-created.raw",
+				},
+				OperatingSystem: image.OperatingSystem{
+					Packages: image.Packages{
+						PKGList: []string{"vim"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The base image '" + filepath.Join(imageConfigDir, "base-images", "missing.raw") +
+					"' is required to resolve the configured packages, even when building only the combustion ISO, but it cannot be read.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir:  imageConfigDir,
+				ImageDefinition: &test.ImageDefinition,
+				CombustionOnly:  true,
+			}
+			failures := validateImage(&ctx)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
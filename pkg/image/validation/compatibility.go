@@ -0,0 +1,268 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"go.uber.org/zap"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	multusAddon = "multus"
+	// anyCNIAddon matches any explicitly configured CNI.
+	anyCNIAddon = "*"
+	// flannelBackendAddonPrefix prefixes the Flannel backend configured for K3s, e.g. "flannel-backend=ipsec".
+	flannelBackendAddonPrefix = "flannel-backend="
+)
+
+// compatibility describes either the range of Kubernetes versions supported on a base image release, or a known
+// incompatible combination of a Kubernetes release and an add-on. Optional constraints which are left empty match any value.
+type compatibility struct {
+	// release makes the entry describe the versions supported on the base images of the release (e.g. "SL Micro 6.0").
+	release      string
+	distribution string
+	// minVersion and maxVersion limit the entry to a range of Kubernetes versions (inclusive, e.g. "v1.28").
+	// An empty maxVersion leaves the range open ended.
+	minVersion string
+	maxVersion string
+	arch       image.Arch
+	addon      string
+	message    string
+}
+
+// kubernetesCompatibility is the compatibility matrix checked during validation.
+//
+// Base image release entries list the Kubernetes versions supported by both K3s and RKE2 on each release,
+// ordered by release. Base images of releases which are not listed are not checked.
+//
+// All other entries describe incompatible combinations. Their message should state the problem along with
+// guidance on how to resolve it.
+var kubernetesCompatibility = []compatibility{
+	{release: "SLE Micro 5.5", minVersion: "v1.26", maxVersion: "v1.30"},
+	{release: "SL Micro 6.0", minVersion: "v1.28", maxVersion: "v1.31"},
+	{release: "SL Micro 6.1", minVersion: "v1.30"},
+	{
+		distribution: image.KubernetesDistroRKE2,
+		arch:         image.ArchTypeARM,
+		addon:        image.CNITypeCalico,
+		message:      "The 'calico' CNI is not supported by RKE2 on aarch64 platforms, use 'canal' instead.",
+	},
+	{
+		distribution: image.KubernetesDistroRKE2,
+		arch:         image.ArchTypeARM,
+		addon:        image.CNITypeCilium,
+		message:      "The 'cilium' CNI is not supported by RKE2 on aarch64 platforms, use 'canal' instead.",
+	},
+	{
+		distribution: image.KubernetesDistroRKE2,
+		arch:         image.ArchTypeARM,
+		addon:        multusAddon,
+		message:      "The 'multus' CNI plugin is not supported by RKE2 on aarch64 platforms.",
+	},
+	{
+		distribution: image.KubernetesDistroK3S,
+		addon:        anyCNIAddon,
+		message: "K3s does not support selecting a CNI through the 'cni' option and uses Flannel instead. " +
+			"Set 'flannel-backend: none' and deploy the desired CNI separately if Flannel is not suitable.",
+	},
+	{
+		distribution: image.KubernetesDistroK3S,
+		minVersion:   "v1.26",
+		addon:        flannelBackendAddonPrefix + "wireguard",
+		message:      "The 'wireguard' Flannel backend was removed in K3s v1.26, use 'wireguard-native' instead.",
+	},
+	{
+		distribution: image.KubernetesDistroK3S,
+		minVersion:   "v1.27",
+		addon:        flannelBackendAddonPrefix + "ipsec",
+		message:      "The 'ipsec' Flannel backend was removed in K3s v1.27, use 'wireguard-native' instead.",
+	},
+}
+
+func validateKubernetesCompatibility(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	def := ctx.ImageDefinition
+
+	distribution := kubernetesDistribution(def.Kubernetes.Version)
+	if distribution == "" {
+		return failures
+	}
+
+	addons, err := kubernetesAddons(ctx, distribution)
+	if err != nil {
+		// Issues with the configuration itself are surfaced when the cluster is configured
+		zap.S().Warnf("Skipping Kubernetes compatibility checks: %s", err)
+		return failures
+	}
+
+	matches := findIncompatibilities(kubernetesCompatibility, distribution, def.Kubernetes.Version, def.Image.Arch, addons)
+	for _, match := range matches {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/version",
+			UserMessage: match.message,
+			Warning:     true,
+		})
+	}
+
+	return failures
+}
+
+// validateBaseImageSupport fails if the Kubernetes version is known to be unsupported on the release of the base image.
+func validateBaseImageSupport(ctx *image.Context) *FailedValidation {
+	return findUnsupportedRelease(kubernetesCompatibility, ctx.ImageDefinition.Kubernetes.Version,
+		image.BaseImageRelease(ctx.BaseImagePath()))
+}
+
+func findUnsupportedRelease(matrix []compatibility, version, release string) *FailedValidation {
+	if !semver.IsValid(version) {
+		return nil
+	}
+
+	var supportedReleases []string
+	var support *compatibility
+	for i, entry := range matrix {
+		if entry.release == "" {
+			continue
+		}
+
+		if entry.release == release {
+			support = &matrix[i]
+		}
+
+		if versionInRange(version, entry.minVersion, entry.maxVersion) {
+			supportedReleases = append(supportedReleases, entry.release)
+		}
+	}
+
+	if support == nil || versionInRange(version, support.minVersion, support.maxVersion) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Kubernetes version '%s' is not supported on %s base images, which support %s.",
+		version, release, describeVersionRange(support.minVersion, support.maxVersion))
+	if len(supportedReleases) > 0 {
+		msg += fmt.Sprintf(" Use a base image of %s, or a supported Kubernetes version instead.", strings.Join(supportedReleases, " or "))
+	} else {
+		msg += " Use a supported Kubernetes version instead."
+	}
+
+	return &FailedValidation{
+		Field:       "kubernetes/version",
+		UserMessage: msg,
+	}
+}
+
+func describeVersionRange(minVersion, maxVersion string) string {
+	if maxVersion == "" {
+		return fmt.Sprintf("Kubernetes %s and later", minVersion)
+	}
+
+	return fmt.Sprintf("Kubernetes %s to %s", minVersion, maxVersion)
+}
+
+func kubernetesDistribution(version string) string {
+	switch {
+	case strings.Contains(version, image.KubernetesDistroRKE2):
+		return image.KubernetesDistroRKE2
+	case strings.Contains(version, image.KubernetesDistroK3S):
+		return image.KubernetesDistroK3S
+	default:
+		return ""
+	}
+}
+
+// kubernetesAddons returns the CNI related add-ons configured in the Kubernetes server config.
+func kubernetesAddons(ctx *image.Context, distribution string) ([]string, error) {
+	config, err := kubernetes.ParseKubernetesConfig(combustion.KubernetesConfigPath(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("parsing kubernetes config: %w", err)
+	}
+
+	var addons []string
+	if backend, ok := config["flannel-backend"].(string); ok && distribution == image.KubernetesDistroK3S {
+		addons = append(addons, flannelBackendAddonPrefix+backend)
+	}
+
+	if _, ok := config["cni"]; !ok {
+		if distribution == image.KubernetesDistroRKE2 {
+			// Matches the default selected when configuring the cluster
+			addons = append(addons, image.CNITypeCilium)
+		}
+
+		return addons, nil
+	}
+
+	cluster := kubernetes.Cluster{ServerConfig: config}
+	cni, multusEnabled, err := cluster.ExtractCNI()
+	if err != nil {
+		return nil, fmt.Errorf("extracting CNI: %w", err)
+	}
+
+	addons = append(addons, cni, anyCNIAddon)
+	if multusEnabled {
+		addons = append(addons, multusAddon)
+	}
+
+	return addons, nil
+}
+
+func findIncompatibilities(matrix []compatibility, distribution, version string, arch image.Arch, addons []string) []compatibility {
+	var matches []compatibility
+
+	for _, entry := range matrix {
+		if entry.release != "" || entry.distribution != distribution {
+			continue
+		}
+
+		if entry.arch != "" && entry.arch != arch {
+			continue
+		}
+
+		if !slices.Contains(addons, entry.addon) {
+			continue
+		}
+
+		if !versionInRange(version, entry.minVersion, entry.maxVersion) {
+			continue
+		}
+
+		matches = append(matches, entry)
+	}
+
+	return matches
+}
+
+func versionInRange(version, minVersion, maxVersion string) bool {
+	// Build metadata such as "+rke2r1" is ignored in comparisons
+	if !semver.IsValid(version) {
+		return minVersion == "" && maxVersion == ""
+	}
+
+	if minVersion != "" && semver.Compare(version, minVersion) < 0 {
+		return false
+	}
+
+	// Compare against the same precision as the upper bound, so that "v1.28" includes all v1.28.x releases
+	if maxVersion != "" && semver.Compare(truncateVersion(version, maxVersion), maxVersion) > 0 {
+		return false
+	}
+
+	return true
+}
+
+func truncateVersion(version, reference string) string {
+	switch strings.Count(reference, ".") {
+	case 0:
+		return semver.Major(version)
+	case 1:
+		return semver.MajorMinor(version)
+	default:
+		return semver.Canonical(version)
+	}
+}
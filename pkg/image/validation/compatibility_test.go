@@ -0,0 +1,295 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"golang.org/x/mod/semver"
+)
+
+func TestFindIncompatibilities(t *testing.T) {
+	matrix := []compatibility{
+		{
+			release:    "SL Micro 6.0",
+			minVersion: "v1.28",
+			maxVersion: "v1.31",
+		},
+		{
+			distribution: image.KubernetesDistroRKE2,
+			maxVersion:   "v1.28",
+			addon:        image.CNITypeCanal,
+			message:      "canal up to v1.28",
+		},
+		{
+			distribution: image.KubernetesDistroRKE2,
+			minVersion:   "v1.30.2",
+			addon:        image.CNITypeCanal,
+			message:      "canal from v1.30.2",
+		},
+		{
+			distribution: image.KubernetesDistroRKE2,
+			arch:         image.ArchTypeARM,
+			addon:        multusAddon,
+			message:      "multus on aarch64",
+		},
+	}
+
+	tests := []struct {
+		name             string
+		version          string
+		arch             image.Arch
+		addons           []string
+		expectedMessages []string
+	}{
+		{
+			name:    "Compatible",
+			version: "v1.29.4+rke2r1",
+			arch:    image.ArchTypeX86,
+			addons:  []string{image.CNITypeCanal, multusAddon},
+		},
+		{
+			name:             "Upper bound includes patch releases",
+			version:          "v1.28.9+rke2r1",
+			arch:             image.ArchTypeX86,
+			addons:           []string{image.CNITypeCanal},
+			expectedMessages: []string{"canal up to v1.28"},
+		},
+		{
+			name:             "Lower bound",
+			version:          "v1.30.2+rke2r1",
+			arch:             image.ArchTypeX86,
+			addons:           []string{image.CNITypeCanal},
+			expectedMessages: []string{"canal from v1.30.2"},
+		},
+		{
+			name:    "Below lower bound",
+			version: "v1.30.1+rke2r1",
+			arch:    image.ArchTypeX86,
+			addons:  []string{image.CNITypeCanal},
+		},
+		{
+			name:             "Architecture specific",
+			version:          "v1.29.4+rke2r1",
+			arch:             image.ArchTypeARM,
+			addons:           []string{image.CNITypeCilium, multusAddon},
+			expectedMessages: []string{"multus on aarch64"},
+		},
+		{
+			name:    "Other distribution",
+			version: "v1.28.9+k3s1",
+			arch:    image.ArchTypeARM,
+			addons:  []string{image.CNITypeCanal, multusAddon},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			distribution := kubernetesDistribution(test.version)
+			matches := findIncompatibilities(matrix, distribution, test.version, test.arch, test.addons)
+
+			var messages []string
+			for _, match := range matches {
+				messages = append(messages, match.message)
+			}
+
+			assert.Equal(t, test.expectedMessages, messages)
+		})
+	}
+}
+
+func TestValidateKubernetesCompatibility(t *testing.T) {
+	configDir, err := os.MkdirTemp("", "eib-compatibility-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(configDir)
+	}()
+
+	k8sConfigDir := filepath.Join(configDir, "kubernetes", "config")
+	require.NoError(t, os.MkdirAll(k8sConfigDir, os.ModePerm))
+
+	tests := map[string]struct {
+		Version                string
+		Arch                   image.Arch
+		ServerConfig           string
+		ExpectedFailedMessages []string
+	}{
+		`rke2 default cni`: {
+			Version: "v1.29.4+rke2r1",
+			Arch:    image.ArchTypeX86,
+		},
+		`rke2 default cni on aarch64`: {
+			Version: "v1.29.4+rke2r1",
+			Arch:    image.ArchTypeARM,
+			ExpectedFailedMessages: []string{
+				"The 'cilium' CNI is not supported by RKE2 on aarch64 platforms, use 'canal' instead.",
+			},
+		},
+		`rke2 canal with multus on aarch64`: {
+			Version:      "v1.29.4+rke2r1",
+			Arch:         image.ArchTypeARM,
+			ServerConfig: "cni:\n  - multus\n  - canal\n",
+			ExpectedFailedMessages: []string{
+				"The 'multus' CNI plugin is not supported by RKE2 on aarch64 platforms.",
+			},
+		},
+		`k3s without cni`: {
+			Version: "v1.29.4+k3s1",
+			Arch:    image.ArchTypeX86,
+		},
+		`k3s with removed flannel backend`: {
+			Version:      "v1.27.1+k3s1",
+			Arch:         image.ArchTypeX86,
+			ServerConfig: "flannel-backend: ipsec\n",
+			ExpectedFailedMessages: []string{
+				"The 'ipsec' Flannel backend was removed in K3s v1.27, use 'wireguard-native' instead.",
+			},
+		},
+		`k3s with flannel backend before its removal`: {
+			Version:      "v1.26.15+k3s1",
+			Arch:         image.ArchTypeX86,
+			ServerConfig: "flannel-backend: ipsec\n",
+		},
+		`k3s with cni`: {
+			Version:      "v1.29.4+k3s1",
+			Arch:         image.ArchTypeX86,
+			ServerConfig: "cni: calico\n",
+			ExpectedFailedMessages: []string{
+				"K3s does not support selecting a CNI through the 'cni' option and uses Flannel instead. " +
+					"Set 'flannel-backend: none' and deploy the desired CNI separately if Flannel is not suitable.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serverConfigPath := filepath.Join(k8sConfigDir, "server.yaml")
+			require.NoError(t, os.RemoveAll(serverConfigPath))
+			if test.ServerConfig != "" {
+				require.NoError(t, os.WriteFile(serverConfigPath, []byte(test.ServerConfig), 0o600))
+			}
+
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+				ImageDefinition: &image.Definition{
+					Image: image.Image{
+						Arch: test.Arch,
+					},
+					Kubernetes: image.Kubernetes{
+						Version: test.Version,
+					},
+				},
+			}
+
+			failures := validateKubernetesCompatibility(&ctx)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			for _, failure := range failures {
+				assert.True(t, failure.Warning)
+				assert.Contains(t, test.ExpectedFailedMessages, failure.UserMessage)
+			}
+		})
+	}
+}
+
+func TestValidateBaseImageSupport(t *testing.T) {
+	tests := map[string]struct {
+		BaseImage         string
+		BaseImageOverride string
+		Version           string
+		ExpectedMessage   string
+	}{
+		`supported`: {
+			BaseImage: "SL-Micro.x86_64-6.0-Base-GM.raw",
+			Version:   "v1.30.5+rke2r1",
+		},
+		`upper bound includes patch releases`: {
+			BaseImage: "SLE-Micro.x86_64-5.5.0-Default-GM.raw",
+			Version:   "v1.30.14+k3s1",
+		},
+		`open ended range`: {
+			BaseImage: "SL-Micro.x86_64-6.1-Base-GM.raw",
+			Version:   "v1.33.1+k3s1",
+		},
+		`unknown base image`: {
+			BaseImage: "base-image.iso",
+			Version:   "v1.20.0+k3s1",
+		},
+		`invalid version`: {
+			BaseImage: "SL-Micro.x86_64-6.0-Base-GM.raw",
+			Version:   "rke2",
+		},
+		`too new`: {
+			BaseImage: "SLE-Micro.x86_64-5.5.0-Default-SelfInstall-GM2.install.iso",
+			Version:   "v1.32.1+rke2r1",
+			ExpectedMessage: "Kubernetes version 'v1.32.1+rke2r1' is not supported on SLE Micro 5.5 base images, which support " +
+				"Kubernetes v1.26 to v1.30. Use a base image of SL Micro 6.1, or a supported Kubernetes version instead.",
+		},
+		`too old`: {
+			BaseImage: "SL-Micro.x86_64-6.1-Base-GM.raw",
+			Version:   "v1.28.9+k3s1",
+			ExpectedMessage: "Kubernetes version 'v1.28.9+k3s1' is not supported on SL Micro 6.1 base images, which support " +
+				"Kubernetes v1.30 and later. Use a base image of SLE Micro 5.5 or SL Micro 6.0, or a supported Kubernetes version instead.",
+		},
+		`overridden base image`: {
+			BaseImage:         "SL-Micro.x86_64-6.0-Base-GM.raw",
+			BaseImageOverride: "/images/SLE-Micro.x86_64-5.5.0-Default-GM.raw",
+			Version:           "v1.31.1+k3s1",
+			ExpectedMessage: "Kubernetes version 'v1.31.1+k3s1' is not supported on SLE Micro 5.5 base images, which support " +
+				"Kubernetes v1.26 to v1.30. Use a base image of SL Micro 6.0 or SL Micro 6.1, or a supported Kubernetes version instead.",
+		},
+		`not supported on any release`: {
+			BaseImage: "SL-Micro.x86_64-6.0-Base-GM.raw",
+			Version:   "v1.24.17+rke2r1",
+			ExpectedMessage: "Kubernetes version 'v1.24.17+rke2r1' is not supported on SL Micro 6.0 base images, which support " +
+				"Kubernetes v1.28 to v1.31. Use a supported Kubernetes version instead.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				BaseImageOverride: test.BaseImageOverride,
+				ImageDefinition: &image.Definition{
+					Image: image.Image{
+						BaseImage: test.BaseImage,
+					},
+					Kubernetes: image.Kubernetes{
+						Version: test.Version,
+					},
+				},
+			}
+
+			failure := validateBaseImageSupport(&ctx)
+			if test.ExpectedMessage == "" {
+				assert.Nil(t, failure)
+				return
+			}
+
+			require.NotNil(t, failure)
+			assert.Equal(t, test.ExpectedMessage, failure.UserMessage)
+			assert.False(t, failure.Warning)
+		})
+	}
+}
+
+func TestKubernetesCompatibilityMatrix(t *testing.T) {
+	for _, entry := range kubernetesCompatibility {
+		for _, version := range []string{entry.minVersion, entry.maxVersion} {
+			assert.True(t, version == "" || semver.IsValid(version), "invalid version '%s' in %+v", version, entry)
+		}
+
+		if entry.release != "" {
+			assert.NotEmpty(t, entry.minVersion, "missing minimum version in %+v", entry)
+			assert.Empty(t, entry.message, "unused message in %+v", entry)
+			continue
+		}
+
+		assert.NotEmpty(t, entry.distribution, "missing distribution in %+v", entry)
+		assert.NotEmpty(t, entry.addon, "missing add-on in %+v", entry)
+		assert.NotEmpty(t, entry.message, "missing message in %+v", entry)
+	}
+}
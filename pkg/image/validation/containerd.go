@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+var validContainerdConfigModes = []string{image.ContainerdConfigModeFull, image.ContainerdConfigModePartial}
+
+// containerdManagedSettings are configured by the Kubernetes distribution, which the nodes rely on
+var containerdManagedSettings = []struct {
+	key    toml.Key
+	reason string
+}{
+	{key: toml.Key{"root"}, reason: "the data directory of the distribution"},
+	{key: toml.Key{"state"}, reason: "the state directory of the distribution"},
+	{key: toml.Key{"grpc", "address"}, reason: "the socket the kubelet connects to"},
+	{key: toml.Key{"plugins", "io.containerd.grpc.v1.cri", "cni"}, reason: "the CNI plugins installed by the distribution"},
+	{key: toml.Key{"plugins", "io.containerd.cri.v1.runtime", "cni"}, reason: "the CNI plugins installed by the distribution"},
+	{key: toml.Key{"plugins", "io.containerd.grpc.v1.cri", "registry"}, reason: "the registry mirrors configured in registries.yaml"},
+	{key: toml.Key{"plugins", "io.containerd.cri.v1.images", "registry"}, reason: "the registry mirrors configured in registries.yaml"},
+	{key: toml.Key{"plugins", "io.containerd.grpc.v1.cri", "sandbox_image"}, reason: "the pause image embedded with the distribution"},
+	{key: toml.Key{"plugins", "io.containerd.cri.v1.images", "pinned_images", "sandbox"}, reason: "the pause image embedded with the distribution"},
+}
+
+func validateContainerd(ctx *image.Context) []FailedValidation {
+	containerd := &ctx.ImageDefinition.Kubernetes.Containerd
+
+	var failures []FailedValidation
+
+	if containerd.Mode != "" && !slices.Contains(validContainerdConfigModes, containerd.Mode) {
+		failures = append(failures, FailedValidation{
+			Field: "kubernetes/containerd/mode",
+			UserMessage: fmt.Sprintf("The 'mode' field in the 'containerd' section must be one of: %s",
+				strings.Join(validContainerdConfigModes, ", ")),
+		})
+	}
+
+	if containerd.ConfigFile == "" {
+		if containerd.Mode != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/containerd/configFile",
+				UserMessage: "The 'configFile' field is required when configuring the 'containerd' section.",
+			})
+		}
+
+		return failures
+	}
+
+	if !filepath.IsLocal(containerd.ConfigFile) {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/containerd/configFile",
+			UserMessage: fmt.Sprintf("The containerd 'configFile' field '%s' must be a relative path within the image configuration directory.", containerd.ConfigFile),
+		})
+
+		return failures
+	}
+
+	data, err := os.ReadFile(combustion.ContainerdConfigPath(ctx))
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/containerd/configFile",
+			UserMessage: fmt.Sprintf("The containerd configuration '%s' could not be read.", containerd.ConfigFile),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	if combustion.HasContainerdTemplateActions(data) {
+		failures = append(failures, FailedValidation{
+			Field: "kubernetes/containerd/configFile",
+			UserMessage: fmt.Sprintf("The containerd configuration '%s' contains template actions and cannot be validated until "+
+				"it is rendered on the node.", containerd.ConfigFile),
+			Warning: true,
+		})
+
+		return failures
+	}
+
+	var config map[string]any
+	metadata, err := toml.Decode(string(data), &config)
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/containerd/configFile",
+			UserMessage: fmt.Sprintf("The containerd configuration '%s' is not valid TOML.", containerd.ConfigFile),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	partial := combustion.ContainerdConfigMode(containerd) == image.ContainerdConfigModePartial
+	if partial && metadata.IsDefined("version") {
+		failures = append(failures, FailedValidation{
+			Field: "kubernetes/containerd/configFile",
+			UserMessage: fmt.Sprintf("The containerd configuration '%s' cannot set 'version' in the 'partial' mode, "+
+				"as it is already set by the configuration it is appended to.", containerd.ConfigFile),
+		})
+	}
+
+	for _, setting := range containerdManagedSettings {
+		if metadata.IsDefined(setting.key...) {
+			failures = append(failures, FailedValidation{
+				Field: "kubernetes/containerd/configFile",
+				UserMessage: fmt.Sprintf("The containerd configuration '%s' sets '%s', which overrides %s.",
+					containerd.ConfigFile, setting.key, setting.reason),
+				Warning: true,
+			})
+		}
+	}
+
+	return failures
+}
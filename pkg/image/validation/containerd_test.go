@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateContainerd(t *testing.T) {
+	tests := map[string]struct {
+		Containerd             image.Containerd
+		Config                 string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid partial config`: {
+			Containerd: image.Containerd{ConfigFile: "containerd.toml"},
+			Config: "[plugins.\"io.containerd.grpc.v1.cri\".containerd.runtimes.nvidia]\n" +
+				"  runtime_type = \"io.containerd.runc.v2\"\n",
+		},
+		`valid full config`: {
+			Containerd: image.Containerd{ConfigFile: "containerd.toml", Mode: image.ContainerdConfigModeFull},
+			Config:     "version = 2\n",
+		},
+		`invalid mode`: {
+			Containerd: image.Containerd{ConfigFile: "containerd.toml", Mode: "merge"},
+			Config:     "version = 2\n",
+			ExpectedFailedMessages: []string{
+				"The 'mode' field in the 'containerd' section must be one of: full, partial",
+			},
+		},
+		`missing config file`: {
+			Containerd: image.Containerd{Mode: image.ContainerdConfigModeFull},
+			ExpectedFailedMessages: []string{
+				"The 'configFile' field is required when configuring the 'containerd' section.",
+			},
+		},
+		`config file outside of the configuration directory`: {
+			Containerd: image.Containerd{ConfigFile: "../containerd.toml"},
+			ExpectedFailedMessages: []string{
+				"The containerd 'configFile' field '../containerd.toml' must be a relative path within the image configuration directory.",
+			},
+		},
+		`unreadable config file`: {
+			Containerd: image.Containerd{ConfigFile: "missing.toml"},
+			ExpectedFailedMessages: []string{
+				"The containerd configuration 'missing.toml' could not be read.",
+			},
+		},
+		`invalid TOML`: {
+			Containerd: image.Containerd{ConfigFile: "containerd.toml"},
+			Config:     "[debug\n",
+			ExpectedFailedMessages: []string{
+				"The containerd configuration 'containerd.toml' is not valid TOML.",
+			},
+		},
+		`template actions`: {
+			Containerd: image.Containerd{ConfigFile: "containerd.toml"},
+			Config:     "[plugins.\"io.containerd.grpc.v1.cri\".containerd.runtimes.nvidia]\n  base = \"{{ .NodeConfig.AgentConfig.Snapshotter }}\"\n",
+			ExpectedFailedMessages: []string{
+				"The containerd configuration 'containerd.toml' contains template actions and cannot be validated until it is rendered on the node.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`version in partial config`: {
+			Containerd: image.Containerd{ConfigFile: "containerd.toml"},
+			Config:     "version = 2\n",
+			ExpectedFailedMessages: []string{
+				"The containerd configuration 'containerd.toml' cannot set 'version' in the 'partial' mode, " +
+					"as it is already set by the configuration it is appended to.",
+			},
+		},
+		`settings managed by the distribution`: {
+			Containerd: image.Containerd{ConfigFile: "containerd.toml", Mode: image.ContainerdConfigModeFull},
+			Config: "version = 2\nroot = \"/data/containerd\"\n" +
+				"[plugins.\"io.containerd.grpc.v1.cri\"]\n  sandbox_image = \"registry.k8s.io/pause:3.9\"\n" +
+				"[plugins.\"io.containerd.grpc.v1.cri\".registry]\n  config_path = \"/etc/containerd/certs.d\"\n",
+			ExpectedFailedMessages: []string{
+				"The containerd configuration 'containerd.toml' sets 'root', which overrides the data directory of the distribution.",
+				"The containerd configuration 'containerd.toml' sets 'plugins.\"io.containerd.grpc.v1.cri\".registry', " +
+					"which overrides the registry mirrors configured in registries.yaml.",
+				"The containerd configuration 'containerd.toml' sets 'plugins.\"io.containerd.grpc.v1.cri\".sandbox_image', " +
+					"which overrides the pause image embedded with the distribution.",
+			},
+			ExpectedWarnings: 3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageConfigDir: t.TempDir(),
+				ImageDefinition: &image.Definition{
+					Kubernetes: image.Kubernetes{
+						Version:    "v1.30.3+rke2r1",
+						Containerd: test.Containerd,
+					},
+				},
+			}
+
+			if test.Config != "" {
+				configPath := filepath.Join(ctx.ImageConfigDir, test.Containerd.ConfigFile)
+				require.NoError(t, os.WriteFile(configPath, []byte(test.Config), 0o600))
+			}
+
+			failures := validateContainerd(ctx)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	customComponent = "Custom Scripts"
+)
+
+func validateCustomScripts(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	nonExecutable, err := combustion.NonExecutableCustomScripts(ctx.ImageConfigDir)
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The custom scripts directory could not be read.",
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	// Scripts are made executable during the build unless their permissions are explicitly preserved
+	if !ctx.PreserveScriptPermissions {
+		return failures
+	}
+
+	for _, script := range nonExecutable {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("The custom script '%s' is not executable and will fail to run during combustion.", script),
+			Warning:     true,
+		})
+	}
+
+	return failures
+}
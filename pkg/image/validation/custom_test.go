@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateCustomScripts(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-custom-scripts-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	scriptsDir := filepath.Join(imageConfigDir, "custom", "scripts")
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "10-executable.sh"), nil, 0o744))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "20-plain.sh"), nil, 0o644))
+
+	tests := map[string]struct {
+		ImageConfigDir            string
+		PreserveScriptPermissions bool
+		ExpectedFailedMessages    []string
+	}{
+		`no custom scripts`: {
+			ImageConfigDir:            t.TempDir(),
+			PreserveScriptPermissions: true,
+		},
+		`scripts made executable`: {
+			ImageConfigDir: imageConfigDir,
+		},
+		`permissions preserved`: {
+			ImageConfigDir:            imageConfigDir,
+			PreserveScriptPermissions: true,
+			ExpectedFailedMessages: []string{
+				"The custom script '20-plain.sh' is not executable and will fail to run during combustion.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir:            test.ImageConfigDir,
+				PreserveScriptPermissions: test.PreserveScriptPermissions,
+			}
+			failures := validateCustomScripts(&ctx)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.True(t, foundValidation.Warning)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
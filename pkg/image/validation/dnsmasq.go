@@ -0,0 +1,225 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+var (
+	interfaceNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,15}$`)
+	dhcpLeaseTimeRegex = regexp.MustCompile(`^([0-9]+[smhdw]?|infinite)$`)
+)
+
+func validateDnsmasq(os *image.OperatingSystem) []FailedValidation {
+	dnsmasq := &os.Dnsmasq
+
+	if !combustion.IsDnsmasqConfigured(dnsmasq) {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	failures = append(failures, validateDnsmasqInterfaces(dnsmasq.Interfaces)...)
+
+	if dnsmasq.Domain != "" && !hostnameRegex.MatchString(dnsmasq.Domain) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dnsmasq/domain",
+			UserMessage: "The 'domain' field of the 'dnsmasq' section must be a valid domain name (e.g. 'sensors.lan').",
+		})
+	}
+
+	for _, upstream := range dnsmasq.Upstreams {
+		if net.ParseIP(upstream) == nil {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dnsmasq/upstreams",
+				UserMessage: fmt.Sprintf("Entry '%s' in the dnsmasq 'upstreams' list must be an IP address.", upstream),
+			})
+		}
+	}
+
+	failures = append(failures, validateDHCPRanges(dnsmasq.Ranges)...)
+	failures = append(failures, validateStaticLeases(dnsmasq.StaticLeases, dnsmasq.Ranges)...)
+	failures = append(failures, validatePackageResolution(&os.Packages, combustion.DnsmasqPackage)...)
+
+	return failures
+}
+
+func validateDnsmasqInterfaces(interfaces []string) []FailedValidation {
+	if len(interfaces) == 0 {
+		return []FailedValidation{
+			{
+				Field: "operatingSystem/dnsmasq/interfaces",
+				UserMessage: "dnsmasq serves every interface of the node, including those facing the upstream network. " +
+					"Listing the served 'interfaces' is recommended.",
+				Warning: true,
+			},
+		}
+	}
+
+	var failures []FailedValidation
+
+	for _, name := range interfaces {
+		if !interfaceNameRegex.MatchString(name) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/dnsmasq/interfaces",
+				UserMessage: fmt.Sprintf("Entry '%s' in the dnsmasq 'interfaces' list must be a network interface name "+
+					"of up to 15 letters, digits, '_', '.' and '-'.", name),
+			})
+		}
+	}
+
+	for _, duplicate := range findDuplicates(interfaces) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dnsmasq/interfaces",
+			UserMessage: fmt.Sprintf("The interface '%s' is listed more than once in the dnsmasq 'interfaces' list.", duplicate),
+		})
+	}
+
+	return failures
+}
+
+func validateDHCPRanges(ranges []image.DHCPRange) []FailedValidation {
+	var failures []FailedValidation
+
+	var validRanges []image.DHCPRange
+
+	for _, r := range ranges {
+		start, end := net.ParseIP(r.Start).To4(), net.ParseIP(r.End).To4()
+
+		switch {
+		case start == nil || end == nil:
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dnsmasq/ranges",
+				UserMessage: fmt.Sprintf("The 'start' and 'end' fields of the DHCP range '%s-%s' must be IPv4 addresses.", r.Start, r.End),
+			})
+		case bytes.Compare(start, end) > 0:
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dnsmasq/ranges",
+				UserMessage: fmt.Sprintf("The start of the DHCP range '%s-%s' must not be after its end.", r.Start, r.End),
+			})
+		default:
+			validRanges = append(validRanges, r)
+		}
+
+		if r.LeaseTime != "" && !dhcpLeaseTimeRegex.MatchString(r.LeaseTime) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/dnsmasq/ranges/leaseTime",
+				UserMessage: fmt.Sprintf("The 'leaseTime' field of the DHCP range '%s-%s' must be a number of seconds, "+
+					"a duration such as '30m', '12h' or '7d', or 'infinite'.", r.Start, r.End),
+			})
+		}
+	}
+
+	for i, r := range validRanges {
+		for _, other := range validRanges[i+1:] {
+			if rangeContains(r, other.Start) || rangeContains(other, r.Start) {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/dnsmasq/ranges",
+					UserMessage: fmt.Sprintf("The DHCP ranges '%s-%s' and '%s-%s' overlap.", r.Start, r.End, other.Start, other.End),
+				})
+			}
+		}
+	}
+
+	return failures
+}
+
+func validateStaticLeases(leases []image.StaticLease, ranges []image.DHCPRange) []FailedValidation {
+	var failures []FailedValidation
+
+	if len(leases) != 0 && len(ranges) == 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dnsmasq/staticLeases",
+			UserMessage: "DHCP is only served on the networks of the dnsmasq 'ranges', at least one range is required for the 'staticLeases'.",
+		})
+	}
+
+	var macs, ips, hostnames []string
+
+	for _, lease := range leases {
+		if mac, err := net.ParseMAC(lease.MAC); err != nil || len(mac) != 6 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dnsmasq/staticLeases/mac",
+				UserMessage: fmt.Sprintf("The 'mac' field '%s' of a dnsmasq static lease must be a MAC address (e.g. '52:54:00:aa:bb:cc').", lease.MAC),
+			})
+		} else {
+			macs = append(macs, mac.String())
+		}
+
+		if net.ParseIP(lease.IP).To4() == nil {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dnsmasq/staticLeases/ip",
+				UserMessage: fmt.Sprintf("The 'ip' field '%s' of a dnsmasq static lease must be an IPv4 address.", lease.IP),
+			})
+		} else {
+			ips = append(ips, lease.IP)
+			failures = append(failures, staticLeaseRangeWarnings(lease, ranges)...)
+		}
+
+		if lease.Hostname != "" && !hostnameRegex.MatchString(lease.Hostname) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dnsmasq/staticLeases/hostname",
+				UserMessage: fmt.Sprintf("The 'hostname' field '%s' of a dnsmasq static lease must be a valid hostname.", lease.Hostname),
+			})
+		} else if lease.Hostname != "" {
+			hostnames = append(hostnames, lease.Hostname)
+		}
+	}
+
+	for _, duplicate := range findDuplicates(macs) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dnsmasq/staticLeases/mac",
+			UserMessage: fmt.Sprintf("The MAC address '%s' is used by more than one dnsmasq static lease.", duplicate),
+		})
+	}
+
+	for _, duplicate := range findDuplicates(ips) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dnsmasq/staticLeases/ip",
+			UserMessage: fmt.Sprintf("The IP address '%s' is used by more than one dnsmasq static lease.", duplicate),
+		})
+	}
+
+	for _, duplicate := range findDuplicates(hostnames) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dnsmasq/staticLeases/hostname",
+			UserMessage: fmt.Sprintf("The hostname '%s' is used by more than one dnsmasq static lease.", duplicate),
+		})
+	}
+
+	return failures
+}
+
+// staticLeaseRangeWarnings warns about static leases within a dynamic range, as their address may
+// already be leased to another device when the node boots with leases from a previous configuration.
+func staticLeaseRangeWarnings(lease image.StaticLease, ranges []image.DHCPRange) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, r := range ranges {
+		if rangeContains(r, lease.IP) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/dnsmasq/staticLeases/ip",
+				UserMessage: fmt.Sprintf("The static lease '%s' is within the DHCP range '%s-%s', its address may be leased "+
+					"to another device before %s requests it.", lease.IP, r.Start, r.End, lease.MAC),
+				Warning: true,
+			})
+		}
+	}
+
+	return failures
+}
+
+// rangeContains checks whether the IPv4 address is within the range, ranges with invalid bounds contain no address.
+func rangeContains(r image.DHCPRange, address string) bool {
+	ip, start, end := net.ParseIP(address).To4(), net.ParseIP(r.Start).To4(), net.ParseIP(r.End).To4()
+	if ip == nil || start == nil || end == nil {
+		return false
+	}
+
+	return bytes.Compare(ip, start) >= 0 && bytes.Compare(ip, end) <= 0
+}
@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateDnsmasq(t *testing.T) {
+	repositories := image.Packages{RegCode: "regcode"}
+
+	tests := map[string]struct {
+		OS                     image.OperatingSystem
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Dnsmasq: image.Dnsmasq{
+					Interfaces: []string{"eth1", "br-sensors"},
+					Domain:     "sensors.lan",
+					Upstreams:  []string{"9.9.9.9", "2620:fe::fe"},
+					Ranges: []image.DHCPRange{
+						{Start: "192.168.50.100", End: "192.168.50.200", LeaseTime: "12h"},
+						{Start: "192.168.60.100", End: "192.168.60.100", LeaseTime: "infinite"},
+					},
+					StaticLeases: []image.StaticLease{
+						{MAC: "52:54:00:aa:bb:cc", IP: "192.168.50.10", Hostname: "sensor-01"},
+						{MAC: "52:54:00:aa:bb:cd", IP: "192.168.50.11"},
+					},
+				},
+			},
+		},
+		`all interfaces`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Dnsmasq: image.Dnsmasq{
+					Upstreams: []string{"9.9.9.9"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"dnsmasq serves every interface of the node, including those facing the upstream network. Listing the served 'interfaces' is recommended.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`invalid fields`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Dnsmasq: image.Dnsmasq{
+					Interfaces: []string{"eth1", "a-very-long-interface", "eth1"},
+					Domain:     "-sensors",
+					Upstreams:  []string{"dns.quad9.net"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Entry 'a-very-long-interface' in the dnsmasq 'interfaces' list must be a network interface name of up to 15 letters, digits, '_', '.' and '-'.",
+				"The interface 'eth1' is listed more than once in the dnsmasq 'interfaces' list.",
+				"The 'domain' field of the 'dnsmasq' section must be a valid domain name (e.g. 'sensors.lan').",
+				"Entry 'dns.quad9.net' in the dnsmasq 'upstreams' list must be an IP address.",
+			},
+		},
+		`invalid ranges`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Dnsmasq: image.Dnsmasq{
+					Interfaces: []string{"eth1"},
+					Ranges: []image.DHCPRange{
+						{Start: "192.168.50.100", End: "fd00::1"},
+						{Start: "192.168.50.200", End: "192.168.50.100"},
+						{Start: "192.168.60.1", End: "192.168.60.100", LeaseTime: "1 day"},
+						{Start: "192.168.60.50", End: "192.168.60.150"},
+						{Start: "192.168.60.10", End: "192.168.60.20"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'start' and 'end' fields of the DHCP range '192.168.50.100-fd00::1' must be IPv4 addresses.",
+				"The start of the DHCP range '192.168.50.200-192.168.50.100' must not be after its end.",
+				"The 'leaseTime' field of the DHCP range '192.168.60.1-192.168.60.100' must be a number of seconds, a duration such as '30m', '12h' or '7d', or 'infinite'.",
+				"The DHCP ranges '192.168.60.1-192.168.60.100' and '192.168.60.50-192.168.60.150' overlap.",
+				"The DHCP ranges '192.168.60.1-192.168.60.100' and '192.168.60.10-192.168.60.20' overlap.",
+			},
+		},
+		`invalid static leases`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Dnsmasq: image.Dnsmasq{
+					Interfaces: []string{"eth1"},
+					Ranges:     []image.DHCPRange{{Start: "192.168.50.100", End: "192.168.50.200"}},
+					StaticLeases: []image.StaticLease{
+						{MAC: "52:54:00:aa:bb", IP: "192.168.50.300", Hostname: "sensor_01"},
+						{MAC: "52:54:00:aa:bb:cc", IP: "192.168.50.10", Hostname: "sensor-01"},
+						{MAC: "52-54-00-AA-BB-CC", IP: "192.168.50.10", Hostname: "sensor-01"},
+						{MAC: "52:54:00:aa:bb:ce", IP: "192.168.50.150"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'mac' field '52:54:00:aa:bb' of a dnsmasq static lease must be a MAC address (e.g. '52:54:00:aa:bb:cc').",
+				"The 'ip' field '192.168.50.300' of a dnsmasq static lease must be an IPv4 address.",
+				"The 'hostname' field 'sensor_01' of a dnsmasq static lease must be a valid hostname.",
+				"The static lease '192.168.50.150' is within the DHCP range '192.168.50.100-192.168.50.200', its address may be leased to another device before 52:54:00:aa:bb:ce requests it.",
+				"The MAC address '52:54:00:aa:bb:cc' is used by more than one dnsmasq static lease.",
+				"The IP address '192.168.50.10' is used by more than one dnsmasq static lease.",
+				"The hostname 'sensor-01' is used by more than one dnsmasq static lease.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`static leases without ranges`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Dnsmasq: image.Dnsmasq{
+					Interfaces:   []string{"eth1"},
+					StaticLeases: []image.StaticLease{{MAC: "52:54:00:aa:bb:cc", IP: "192.168.50.10"}},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"DHCP is only served on the networks of the dnsmasq 'ranges', at least one range is required for the 'staticLeases'.",
+			},
+		},
+		`no repositories`: {
+			OS: image.OperatingSystem{
+				Dnsmasq: image.Dnsmasq{
+					Interfaces: []string{"eth1"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'dnsmasq' package is installed from the configured repositories, its resolution may fail without either the 'sccRegistrationCode' or the 'additionalRepos' field.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateDnsmasq(&test.OS)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
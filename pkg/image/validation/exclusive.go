@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	exclusiveComponent = "Conflicting Sections"
+)
+
+// exclusiveSection is a part of the definition, or of the image configuration directory, which can conflict
+// with another section when configured.
+type exclusiveSection struct {
+	// description names the section in the messages, e.g. "'operatingSystem/hostname' field"
+	description string
+	// field is the path of the definition field configuring the section, empty for sections outside the definition
+	field string
+	set   func(ctx *image.Context) bool
+}
+
+// exclusionRule declares two sections which cannot be configured together and the reason why.
+type exclusionRule struct {
+	first  exclusiveSection
+	second exclusiveSection
+	reason string
+}
+
+var (
+	isoConfigurationSection = exclusiveSection{
+		description: "'operatingSystem/isoConfiguration' section",
+		field:       "operatingSystem/isoConfiguration",
+		set: func(ctx *image.Context) bool {
+			return ctx.ImageDefinition.OperatingSystem.IsoConfiguration.InstallDevice != ""
+		},
+	}
+	rawConfigurationSection = exclusiveSection{
+		description: "'operatingSystem/rawConfiguration' section",
+		field:       "operatingSystem/rawConfiguration",
+		set: func(ctx *image.Context) bool {
+			return ctx.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize != ""
+		},
+	}
+	networkdSection = exclusiveSection{
+		description: "'operatingSystem/networkd' section",
+		field:       "operatingSystem/networkd",
+		set: func(ctx *image.Context) bool {
+			return len(ctx.ImageDefinition.OperatingSystem.Networkd.ConfigFiles) != 0
+		},
+	}
+	nmstateSection = exclusiveSection{
+		description: fmt.Sprintf("nmstate configuration in the '%s' directory", combustion.NetworkConfigDir),
+		set: func(ctx *image.Context) bool {
+			_, err := os.Stat(filepath.Join(ctx.ImageConfigDir, combustion.NetworkConfigDir))
+			return err == nil
+		},
+	}
+	networkProfilesSection = exclusiveSection{
+		description: "'operatingSystem/networkProfiles' section",
+		field:       "operatingSystem/networkProfiles",
+		set: func(ctx *image.Context) bool {
+			return len(ctx.ImageDefinition.OperatingSystem.NetworkProfiles.Profiles) != 0
+		},
+	}
+	hostnameSection = exclusiveSection{
+		description: "'operatingSystem/hostname' field",
+		field:       "operatingSystem/hostname",
+		set: func(ctx *image.Context) bool {
+			return ctx.ImageDefinition.OperatingSystem.Hostname != ""
+		},
+	}
+	multipleNodesSection = exclusiveSection{
+		description: "'kubernetes/nodes' section listing multiple nodes",
+		field:       "kubernetes/nodes",
+		set: func(ctx *image.Context) bool {
+			return len(ctx.ImageDefinition.Kubernetes.Nodes) > 1
+		},
+	}
+)
+
+// exclusionRules lists the sections which cannot coexist. Features introducing a section that cannot be combined
+// with another one register their rule here instead of checking the conflict in their own validation.
+var exclusionRules = []exclusionRule{
+	{
+		first:  isoConfigurationSection,
+		second: rawConfigurationSection,
+		reason: "the image is installed onto a disk from the ISO or written as a disk image, regardless of the image type",
+	},
+	{
+		first:  networkdSection,
+		second: nmstateSection,
+		reason: "the network is either configured by systemd-networkd or by NetworkManager through nmstate",
+	},
+	{
+		first:  networkProfilesSection,
+		second: nmstateSection,
+		reason: "the network profiles replace the single network configuration",
+	},
+	{
+		first:  networkProfilesSection,
+		second: networkdSection,
+		reason: "the network profiles are applied by NetworkManager, which is disabled by systemd-networkd",
+	},
+	{
+		first:  hostnameSection,
+		second: multipleNodesSection,
+		reason: "the Kubernetes nodes are identified by their individual hostnames",
+	},
+}
+
+func validateExclusiveSections(ctx *image.Context) []FailedValidation {
+	return checkExclusionRules(ctx, exclusionRules)
+}
+
+func checkExclusionRules(ctx *image.Context, rules []exclusionRule) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, rule := range rules {
+		if rule.first.set(ctx) && rule.second.set(ctx) {
+			failures = append(failures, FailedValidation{
+				Field: rule.first.field,
+				UserMessage: fmt.Sprintf("The %s cannot be combined with the %s, as %s.",
+					rule.first.description, rule.second.description, rule.reason),
+			})
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,161 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateExclusiveSections(t *testing.T) {
+	networkConfigDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(networkConfigDir, "network"), 0o755))
+
+	tests := map[string]struct {
+		Definition             image.Definition
+		ImageConfigDir         string
+		ExpectedFailedMessages []string
+	}{
+		`no conflicts`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Hostname: "kiosk-01",
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+					},
+					Networkd: image.Networkd{
+						ConfigFiles: []string{"10-eth0.network"},
+					},
+				},
+				Kubernetes: image.Kubernetes{
+					Nodes: []image.Node{{Hostname: "kiosk-01", Type: "server"}},
+				},
+			},
+		},
+		`nmstate without networkd`: {
+			ImageConfigDir: networkConfigDir,
+		},
+		`iso and raw configuration`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'operatingSystem/isoConfiguration' section cannot be combined with the 'operatingSystem/rawConfiguration' section, " +
+					"as the image is installed onto a disk from the ISO or written as a disk image, regardless of the image type.",
+			},
+		},
+		`networkd and nmstate`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Networkd: image.Networkd{
+						ConfigFiles: []string{"10-eth0.network"},
+					},
+				},
+			},
+			ImageConfigDir: networkConfigDir,
+			ExpectedFailedMessages: []string{
+				"The 'operatingSystem/networkd' section cannot be combined with the nmstate configuration in the 'network' directory, " +
+					"as the network is either configured by systemd-networkd or by NetworkManager through nmstate.",
+			},
+		},
+		`network profiles and nmstate`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					NetworkProfiles: image.NetworkProfiles{
+						Default:  "office",
+						Profiles: []string{"office", "field"},
+					},
+				},
+			},
+			ImageConfigDir: networkConfigDir,
+			ExpectedFailedMessages: []string{
+				"The 'operatingSystem/networkProfiles' section cannot be combined with the nmstate configuration in the 'network' directory, " +
+					"as the network profiles replace the single network configuration.",
+			},
+		},
+		`network profiles and networkd`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Networkd: image.Networkd{
+						ConfigFiles: []string{"10-eth0.network"},
+					},
+					NetworkProfiles: image.NetworkProfiles{
+						Default:  "office",
+						Profiles: []string{"office", "field"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'operatingSystem/networkProfiles' section cannot be combined with the 'operatingSystem/networkd' section, " +
+					"as the network profiles are applied by NetworkManager, which is disabled by systemd-networkd.",
+			},
+		},
+		`hostname and multiple nodes`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Hostname: "kiosk-01",
+				},
+				Kubernetes: image.Kubernetes{
+					Nodes: []image.Node{
+						{Hostname: "node1.example.com", Type: "server"},
+						{Hostname: "node2.example.com", Type: "agent"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'operatingSystem/hostname' field cannot be combined with the 'kubernetes/nodes' section listing multiple nodes, " +
+					"as the Kubernetes nodes are identified by their individual hostnames.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			imageConfigDir := test.ImageConfigDir
+			if imageConfigDir == "" {
+				imageConfigDir = t.TempDir()
+			}
+
+			ctx := image.Context{
+				ImageConfigDir:  imageConfigDir,
+				ImageDefinition: &test.Definition,
+			}
+
+			failures := validateExclusiveSections(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.False(t, foundValidation.Warning)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestCheckExclusionRules(t *testing.T) {
+	set := exclusiveSection{description: "'set' section", set: func(*image.Context) bool { return true }}
+	unset := exclusiveSection{description: "'unset' section", set: func(*image.Context) bool { return false }}
+
+	rules := []exclusionRule{
+		{first: set, second: unset, reason: "only one is set"},
+		{first: unset, second: set, reason: "only one is set"},
+		{first: set, second: set, reason: "both are set"},
+	}
+
+	failures := checkExclusionRules(&image.Context{}, rules)
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "The 'set' section cannot be combined with the 'set' section, as both are set.", failures[0].UserMessage)
+}
@@ -0,0 +1,125 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+var (
+	// fail2banTimeRegex matches the durations accepted by fail2ban, either in seconds or with a unit suffix
+	fail2banTimeRegex     = regexp.MustCompile(`^[0-9]+(s|m|h|d|w|mo|y)?$`)
+	fail2banJailNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	fail2banPortRegex     = regexp.MustCompile(`^[a-zA-Z0-9-]+(:[0-9]+)?(,[a-zA-Z0-9-]+(:[0-9]+)?)*$`)
+)
+
+// fail2banReservedSections are the sections of the jail configuration which do not define a jail
+var fail2banReservedSections = []string{"DEFAULT", "INCLUDES"}
+
+func validateFail2ban(os *image.OperatingSystem) []FailedValidation {
+	fail2ban := &os.Fail2ban
+
+	var failures []FailedValidation
+
+	if len(fail2ban.Jails) == 0 {
+		if fail2ban.BanTime != "" || fail2ban.FindTime != "" || fail2ban.MaxRetry != 0 || len(fail2ban.IgnoreIPs) != 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/fail2ban/jails",
+				UserMessage: "At least one entry in the 'jails' field is required when configuring the 'fail2ban' section.",
+			})
+		}
+
+		return failures
+	}
+
+	failures = append(failures, validateFail2banLimits("'fail2ban' section", "operatingSystem/fail2ban", fail2ban.BanTime, fail2ban.FindTime, fail2ban.MaxRetry)...)
+
+	for _, ip := range fail2ban.IgnoreIPs {
+		if net.ParseIP(ip) == nil {
+			if _, _, err := net.ParseCIDR(ip); err != nil {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/fail2ban/ignoreIPs",
+					UserMessage: fmt.Sprintf("Entry '%s' in the fail2ban 'ignoreIPs' list must be an IP address or a network in CIDR notation.", ip),
+				})
+			}
+		}
+	}
+
+	failures = append(failures, validateFail2banJails(fail2ban.Jails)...)
+
+	failures = append(failures, validatePackageResolution(&os.Packages, combustion.Fail2banPackage)...)
+
+	return failures
+}
+
+func validateFail2banJails(jails []image.Fail2banJail) []FailedValidation {
+	var failures []FailedValidation
+
+	seenJails := make(map[string]bool)
+
+	for _, jail := range jails {
+		if !fail2banJailNameRegex.MatchString(jail.Name) || slices.Contains(fail2banReservedSections, jail.Name) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/fail2ban/jails/name",
+				UserMessage: fmt.Sprintf("The fail2ban jail name '%s' must only contain letters, digits, '_', '.' and '-', "+
+					"start with a letter or digit, and cannot be one of: DEFAULT, INCLUDES", jail.Name),
+			})
+			continue
+		}
+
+		if seenJails[jail.Name] {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/fail2ban/jails/name",
+				UserMessage: fmt.Sprintf("The fail2ban jail '%s' is listed more than once.", jail.Name),
+			})
+			continue
+		}
+		seenJails[jail.Name] = true
+
+		if jail.Port != "" && !fail2banPortRegex.MatchString(jail.Port) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/fail2ban/jails/port",
+				UserMessage: fmt.Sprintf("The 'port' field of the fail2ban jail '%s' must be a comma separated list of port "+
+					"numbers, service names or ranges (e.g. 'ssh,2222' or '8000:8080').", jail.Name),
+			})
+		}
+
+		failures = append(failures, validateFail2banLimits(fmt.Sprintf("fail2ban jail '%s'", jail.Name), "operatingSystem/fail2ban/jails", jail.BanTime, jail.FindTime, jail.MaxRetry)...)
+	}
+
+	return failures
+}
+
+func validateFail2banLimits(section, field, banTime, findTime string, maxRetry int) []FailedValidation {
+	var failures []FailedValidation
+
+	// A negative ban time bans the addresses permanently
+	if banTime != "" && banTime != "-1" && !fail2banTimeRegex.MatchString(banTime) {
+		failures = append(failures, FailedValidation{
+			Field: field + "/banTime",
+			UserMessage: fmt.Sprintf("The 'banTime' field of the %s must be a number of seconds, a duration such as '10m', "+
+				"'1h' or '1d', or '-1' to ban permanently.", section),
+		})
+	}
+
+	if findTime != "" && !fail2banTimeRegex.MatchString(findTime) {
+		failures = append(failures, FailedValidation{
+			Field: field + "/findTime",
+			UserMessage: fmt.Sprintf("The 'findTime' field of the %s must be a number of seconds or a duration such as "+
+				"'10m', '1h' or '1d'.", section),
+		})
+	}
+
+	if maxRetry < 0 {
+		failures = append(failures, FailedValidation{
+			Field:       field + "/maxRetry",
+			UserMessage: fmt.Sprintf("The 'maxRetry' field of the %s must be a positive number.", section),
+		})
+	}
+
+	return failures
+}
@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateFail2ban(t *testing.T) {
+	repositories := image.Packages{
+		AdditionalRepos: []image.AddRepo{{URL: "https://download.opensuse.org/repositories/backports"}},
+	}
+
+	tests := map[string]struct {
+		OS                     image.OperatingSystem
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Fail2ban: image.Fail2ban{
+					BanTime:   "1h",
+					FindTime:  "600",
+					MaxRetry:  5,
+					IgnoreIPs: []string{"10.0.0.0/8", "192.168.1.10", "fd00::/8"},
+					Jails: []image.Fail2banJail{
+						{Name: "sshd", Port: "ssh,2222", BanTime: "-1", MaxRetry: 3},
+						{Name: "cockpit", Port: "9090:9091", FindTime: "1d"},
+					},
+				},
+			},
+		},
+		`defaults without jails`: {
+			OS: image.OperatingSystem{
+				Fail2ban: image.Fail2ban{
+					BanTime: "1h",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"At least one entry in the 'jails' field is required when configuring the 'fail2ban' section.",
+			},
+		},
+		`invalid defaults`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Fail2ban: image.Fail2ban{
+					BanTime:   "1 hour",
+					FindTime:  "-1",
+					MaxRetry:  -1,
+					IgnoreIPs: []string{"10.0.0.0/33", "localhost"},
+					Jails:     []image.Fail2banJail{{Name: "sshd"}},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'banTime' field of the 'fail2ban' section must be a number of seconds, a duration such as '10m', '1h' or '1d', or '-1' to ban permanently.",
+				"The 'findTime' field of the 'fail2ban' section must be a number of seconds or a duration such as '10m', '1h' or '1d'.",
+				"The 'maxRetry' field of the 'fail2ban' section must be a positive number.",
+				"Entry '10.0.0.0/33' in the fail2ban 'ignoreIPs' list must be an IP address or a network in CIDR notation.",
+				"Entry 'localhost' in the fail2ban 'ignoreIPs' list must be an IP address or a network in CIDR notation.",
+			},
+		},
+		`invalid jails`: {
+			OS: image.OperatingSystem{
+				Packages: repositories,
+				Fail2ban: image.Fail2ban{
+					Jails: []image.Fail2banJail{
+						{Name: ""},
+						{Name: "DEFAULT"},
+						{Name: "ssh d"},
+						{Name: "sshd", Port: "22 2222", BanTime: "1x", FindTime: "1.5h", MaxRetry: -3},
+						{Name: "sshd"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The fail2ban jail name '' must only contain letters, digits, '_', '.' and '-', start with a letter or digit, and cannot be one of: DEFAULT, INCLUDES",
+				"The fail2ban jail name 'DEFAULT' must only contain letters, digits, '_', '.' and '-', start with a letter or digit, and cannot be one of: DEFAULT, INCLUDES",
+				"The fail2ban jail name 'ssh d' must only contain letters, digits, '_', '.' and '-', start with a letter or digit, and cannot be one of: DEFAULT, INCLUDES",
+				"The 'port' field of the fail2ban jail 'sshd' must be a comma separated list of port numbers, service names or ranges (e.g. 'ssh,2222' or '8000:8080').",
+				"The 'banTime' field of the fail2ban jail 'sshd' must be a number of seconds, a duration such as '10m', '1h' or '1d', or '-1' to ban permanently.",
+				"The 'findTime' field of the fail2ban jail 'sshd' must be a number of seconds or a duration such as '10m', '1h' or '1d'.",
+				"The 'maxRetry' field of the fail2ban jail 'sshd' must be a positive number.",
+				"The fail2ban jail 'sshd' is listed more than once.",
+			},
+		},
+		`no repositories`: {
+			OS: image.OperatingSystem{
+				Fail2ban: image.Fail2ban{
+					Jails: []image.Fail2banJail{{Name: "sshd"}},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'fail2ban' package is installed from the configured repositories, its resolution may fail without either the 'sccRegistrationCode' or the 'additionalRepos' field.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateFail2ban(&test.OS)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
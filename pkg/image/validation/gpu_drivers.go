@@ -0,0 +1,200 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	kernelFlavourDefault = "default"
+	kernelFlavourRT      = "rt"
+)
+
+var (
+	gpuDriverVersionRegex = regexp.MustCompile(`^\d+(\.\d+)*$`)
+	kernelModuleRegex     = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	// Kernel module packages (KMPs) are built for a single kernel flavour, named by their suffix
+	kmpFlavourRegex = regexp.MustCompile(`-kmp-([a-z0-9]+)$`)
+)
+
+func validateGPUDrivers(ctx *image.Context) []FailedValidation {
+	def := ctx.ImageDefinition
+	drivers := &def.OperatingSystem.GPUDrivers
+
+	var failures []FailedValidation
+
+	if len(drivers.Packages) == 0 && (drivers.Version != "" || drivers.Repository != image.AddRepo{}) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/gpuDrivers/packages",
+			UserMessage: "The 'packages' field is required in the 'gpuDrivers' section when a 'version' or 'repository' is specified.",
+		})
+	}
+
+	if drivers.Version != "" && !gpuDriverVersionRegex.MatchString(drivers.Version) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/gpuDrivers/version",
+			UserMessage: fmt.Sprintf("The GPU driver version '%s' must consist of numbers separated by dots (e.g. '550.90.07').", drivers.Version),
+		})
+	}
+
+	if drivers.Repository.Unsigned && drivers.Repository.URL == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/gpuDrivers/repository/url",
+			UserMessage: "The 'url' field is required for the 'repository' of the 'gpuDrivers' section.",
+		})
+	}
+
+	for _, p := range drivers.Packages {
+		switch {
+		case !packageNameRegex.MatchString(p):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/gpuDrivers/packages",
+				UserMessage: fmt.Sprintf("The GPU driver package '%s' is not a valid package name.", p),
+			})
+		case slices.Contains(def.OperatingSystem.Packages.PKGList, p):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/gpuDrivers/packages",
+				UserMessage: fmt.Sprintf("The GPU driver package '%s' is also listed in the 'packageList' field.", p),
+			})
+		}
+	}
+
+	if duplicates := findDuplicates(drivers.Packages); len(duplicates) > 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/gpuDrivers/packages",
+			UserMessage: fmt.Sprintf("The 'packages' field of the 'gpuDrivers' section contains duplicate packages: %s", strings.Join(duplicates, ", ")),
+		})
+	}
+
+	failures = append(failures, validateGPUKernelModules(&drivers.KernelModules)...)
+
+	if len(drivers.Packages) > 0 {
+		failures = append(failures, validateGPUDriverKernel(ctx, drivers.Packages)...)
+	}
+
+	return failures
+}
+
+func validateGPUKernelModules(modules *image.GPUKernelModules) []FailedValidation {
+	var failures []FailedValidation
+
+	lists := []struct {
+		field   string
+		modules []string
+	}{
+		{field: "load", modules: modules.Load},
+		{field: "blacklist", modules: modules.Blacklist},
+	}
+
+	for _, list := range lists {
+		for _, module := range list.modules {
+			if !kernelModuleRegex.MatchString(module) {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/gpuDrivers/kernelModules/" + list.field,
+					UserMessage: fmt.Sprintf("The kernel module '%s' under '%s' must only contain alphanumeric characters, '-' or '_'.", module, list.field),
+				})
+			}
+		}
+
+		if duplicates := findDuplicates(list.modules); len(duplicates) > 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/gpuDrivers/kernelModules/" + list.field,
+				UserMessage: fmt.Sprintf("The '%s' field of the 'kernelModules' section contains duplicate modules: %s", list.field, strings.Join(duplicates, ", ")),
+			})
+		}
+	}
+
+	var modulesWithOptions []string
+	for module := range modules.Options {
+		modulesWithOptions = append(modulesWithOptions, module)
+	}
+	slices.Sort(modulesWithOptions)
+
+	for _, module := range modulesWithOptions {
+		options := modules.Options[module]
+
+		if !kernelModuleRegex.MatchString(module) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/gpuDrivers/kernelModules/options",
+				UserMessage: fmt.Sprintf("The kernel module '%s' under 'options' must only contain alphanumeric characters, '-' or '_'.", module),
+			})
+		}
+
+		if strings.TrimSpace(options) == "" || strings.ContainsAny(options, "\n\r") {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/gpuDrivers/kernelModules/options",
+				UserMessage: fmt.Sprintf("The options of the kernel module '%s' must be a non-empty, single line of parameters.", module),
+			})
+		}
+	}
+
+	for _, module := range modules.Blacklist {
+		if slices.Contains(modules.Load, module) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/gpuDrivers/kernelModules/blacklist",
+				UserMessage: fmt.Sprintf("The kernel module '%s' cannot be both loaded and blacklisted.", module),
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateGPUDriverKernel warns about driver packages whose kernel modules are built for another kernel flavour
+// than the one of the base image. The check relies on the conventional names of the base images and kernel module
+// packages, so a warning is raised instead when the compatibility cannot be determined.
+func validateGPUDriverKernel(ctx *image.Context, packages []string) []FailedValidation {
+	baseImage := filepath.Base(ctx.BaseImagePath())
+
+	release := image.BaseImageRelease(baseImage)
+	if release == "" {
+		return []FailedValidation{{
+			Field: "operatingSystem/gpuDrivers/packages",
+			UserMessage: fmt.Sprintf("The compatibility of the GPU driver with the kernel of the base image '%s' cannot be determined, "+
+				"as the release of the base image is unknown.", baseImage),
+			Warning: true,
+		}}
+	}
+
+	flavour := kernelFlavourDefault
+	if strings.Contains(baseImage, "-RT-") {
+		flavour = kernelFlavourRT
+	}
+
+	var failures []FailedValidation
+	var kmpFound bool
+
+	for _, p := range packages {
+		match := kmpFlavourRegex.FindStringSubmatch(p)
+		if match == nil {
+			continue
+		}
+		kmpFound = true
+
+		if match[1] != flavour {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/gpuDrivers/packages",
+				UserMessage: fmt.Sprintf("The GPU driver package '%s' provides kernel modules for the '%s' kernel, "+
+					"while the %s base image '%s' uses the '%s' kernel. Use the '-kmp-%s' package instead.",
+					p, match[1], release, baseImage, flavour, flavour),
+				Warning: true,
+			})
+		}
+	}
+
+	if !kmpFound {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/gpuDrivers/packages",
+			UserMessage: "The compatibility of the GPU driver with the kernel of the base image cannot be determined, " +
+				"as none of its packages is a kernel module package ('-kmp-<flavour>').",
+			Warning: true,
+		})
+	}
+
+	return failures
+}
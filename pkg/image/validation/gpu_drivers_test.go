@@ -0,0 +1,144 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateGPUDrivers(t *testing.T) {
+	tests := map[string]struct {
+		BaseImage              string
+		PackageList            []string
+		GPUDrivers             image.GPUDrivers
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			BaseImage: "SL-Micro.x86_64-6.0-Base-GM.raw",
+			GPUDrivers: image.GPUDrivers{
+				Version:    "550.90.07",
+				Repository: image.AddRepo{URL: "https://download.nvidia.com/suse/sle15sp6/"},
+				Packages:   []string{"nvidia-open-driver-G06-signed-kmp-default", "nvidia-compute-utils-G06"},
+				KernelModules: image.GPUKernelModules{
+					Load:      []string{"nvidia", "nvidia-uvm"},
+					Options:   map[string]string{"nvidia": "NVreg_EnableGpuFirmware=1"},
+					Blacklist: []string{"nouveau"},
+				},
+			},
+		},
+		`kernel modules only`: {
+			GPUDrivers: image.GPUDrivers{
+				KernelModules: image.GPUKernelModules{
+					Blacklist: []string{"nouveau"},
+				},
+			},
+		},
+		`missing packages`: {
+			GPUDrivers: image.GPUDrivers{
+				Version:    "550",
+				Repository: image.AddRepo{Unsigned: true},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'packages' field is required in the 'gpuDrivers' section when a 'version' or 'repository' is specified.",
+				"The 'url' field is required for the 'repository' of the 'gpuDrivers' section.",
+			},
+		},
+		`invalid packages and version`: {
+			BaseImage:   "SL-Micro.x86_64-6.0-Base-GM.raw",
+			PackageList: []string{"nvidia-compute-utils-G06"},
+			GPUDrivers: image.GPUDrivers{
+				Version: "v550.90",
+				Packages: []string{"nvidia-open-driver-G06-signed-kmp-default", "nvidia-compute-utils-G06",
+					"nvidia driver", "nvidia-open-driver-G06-signed-kmp-default"},
+			},
+			ExpectedFailedMessages: []string{
+				"The GPU driver version 'v550.90' must consist of numbers separated by dots (e.g. '550.90.07').",
+				"The GPU driver package 'nvidia-compute-utils-G06' is also listed in the 'packageList' field.",
+				"The GPU driver package 'nvidia driver' is not a valid package name.",
+				"The 'packages' field of the 'gpuDrivers' section contains duplicate packages: nvidia-open-driver-G06-signed-kmp-default",
+			},
+		},
+		`invalid kernel modules`: {
+			GPUDrivers: image.GPUDrivers{
+				KernelModules: image.GPUKernelModules{
+					Load:      []string{"nvidia", "nouveau", "nvidia"},
+					Options:   map[string]string{"nvidia drm": "modeset=1", "nvidia": " "},
+					Blacklist: []string{"nouveau", "../nouveau"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'load' field of the 'kernelModules' section contains duplicate modules: nvidia",
+				"The kernel module '../nouveau' under 'blacklist' must only contain alphanumeric characters, '-' or '_'.",
+				"The options of the kernel module 'nvidia' must be a non-empty, single line of parameters.",
+				"The kernel module 'nvidia drm' under 'options' must only contain alphanumeric characters, '-' or '_'.",
+				"The kernel module 'nouveau' cannot be both loaded and blacklisted.",
+			},
+		},
+		`kernel flavour mismatch`: {
+			BaseImage: "SL-Micro.x86_64-6.0-Base-RT-GM.raw",
+			GPUDrivers: image.GPUDrivers{
+				Packages: []string{"nvidia-open-driver-G06-signed-kmp-default"},
+			},
+			ExpectedFailedMessages: []string{
+				"The GPU driver package 'nvidia-open-driver-G06-signed-kmp-default' provides kernel modules for the 'default' kernel, " +
+					"while the SL Micro 6.0 base image 'SL-Micro.x86_64-6.0-Base-RT-GM.raw' uses the 'rt' kernel. Use the '-kmp-rt' package instead.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`unknown base image release`: {
+			BaseImage: "custom.raw",
+			GPUDrivers: image.GPUDrivers{
+				Packages: []string{"nvidia-open-driver-G06-signed-kmp-default"},
+			},
+			ExpectedFailedMessages: []string{
+				"The compatibility of the GPU driver with the kernel of the base image 'custom.raw' cannot be determined, " +
+					"as the release of the base image is unknown.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`no kernel module package`: {
+			BaseImage: "SLE-Micro.x86_64-5.5.0-Default-GM.raw",
+			GPUDrivers: image.GPUDrivers{
+				Packages: []string{"nvidia-compute-utils-G06"},
+			},
+			ExpectedFailedMessages: []string{
+				"The compatibility of the GPU driver with the kernel of the base image cannot be determined, " +
+					"as none of its packages is a kernel module package ('-kmp-<flavour>').",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageDefinition: &image.Definition{
+					Image: image.Image{
+						BaseImage: test.BaseImage,
+					},
+					OperatingSystem: image.OperatingSystem{
+						Packages:   image.Packages{PKGList: test.PackageList},
+						GPUDrivers: test.GPUDrivers,
+					},
+				},
+			}
+
+			failures := validateGPUDrivers(ctx)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
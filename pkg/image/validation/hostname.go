@@ -0,0 +1,53 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// hostnameMaxLength is the maximum length of a Linux hostname (HOST_NAME_MAX)
+const hostnameMaxLength = 64
+
+func validateHostname(def *image.Definition, imageConfigDir string) []FailedValidation {
+	hostname := def.OperatingSystem.Hostname
+	if hostname == "" {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	switch {
+	case len(hostname) > hostnameMaxLength:
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/hostname",
+			UserMessage: fmt.Sprintf("The 'hostname' field cannot be longer than %d characters.", hostnameMaxLength),
+		})
+	case !hostnameRegex.MatchString(hostname):
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/hostname",
+			UserMessage: fmt.Sprintf("The 'hostname' field '%s' must be a valid RFC 1123 hostname, made of dot separated "+
+				"labels of alphanumeric characters and '-', which cannot start or end with '-'.", hostname),
+		})
+	case strings.EqualFold(hostname, "localhost") || strings.HasPrefix(strings.ToLower(hostname), "localhost."):
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/hostname",
+			UserMessage: fmt.Sprintf("The 'hostname' field '%s' is reserved and cannot be set as the hostname of the node.", hostname),
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(imageConfigDir, combustion.NetworkConfigDir)); err == nil {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/hostname",
+			UserMessage: fmt.Sprintf("The 'hostname' field overrides the per-node hostnames assigned by the network "+
+				"configuration in the '%s' directory, so every node will share the hostname '%s'.", combustion.NetworkConfigDir, hostname),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
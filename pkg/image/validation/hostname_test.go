@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateHostname(t *testing.T) {
+	networkConfigDir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(networkConfigDir, combustion.NetworkConfigDir), 0o755))
+
+	tests := map[string]struct {
+		Hostname               string
+		Nodes                  []image.Node
+		ImageConfigDir         string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`not defined with network configuration`: {
+			ImageConfigDir: networkConfigDir,
+		},
+		`valid short name`: {
+			Hostname: "kiosk-01",
+		},
+		`valid FQDN`: {
+			Hostname: "kiosk-01.store.example.com",
+			Nodes:    []image.Node{{Hostname: "kiosk-01.store.example.com", Type: "server"}},
+		},
+		`too long`: {
+			Hostname: strings.Repeat("a", 65),
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field cannot be longer than 64 characters.",
+			},
+		},
+		`invalid characters`: {
+			Hostname: "kiosk_01",
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field 'kiosk_01' must be a valid RFC 1123 hostname, made of dot separated labels of alphanumeric characters and '-', which cannot start or end with '-'.",
+			},
+		},
+		`leading hyphen`: {
+			Hostname: "-kiosk",
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field '-kiosk' must be a valid RFC 1123 hostname, made of dot separated labels of alphanumeric characters and '-', which cannot start or end with '-'.",
+			},
+		},
+		`localhost`: {
+			Hostname: "localhost.localdomain",
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field 'localhost.localdomain' is reserved and cannot be set as the hostname of the node.",
+			},
+		},
+		`network configuration`: {
+			Hostname:       "kiosk-01",
+			ImageConfigDir: networkConfigDir,
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field overrides the per-node hostnames assigned by the network configuration in the 'network' directory, so every node will share the hostname 'kiosk-01'.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			imageConfigDir := test.ImageConfigDir
+			if imageConfigDir == "" {
+				imageConfigDir = t.TempDir()
+			}
+
+			def := image.Definition{
+				OperatingSystem: image.OperatingSystem{Hostname: test.Hostname},
+				Kubernetes:      image.Kubernetes{Nodes: test.Nodes},
+			}
+
+			failures := validateHostname(&def, imageConfigDir)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -1,19 +1,100 @@
 package validation
 
 import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
 )
 
 const (
 	imageComponent = "Image"
+
+	gptSectorSize = 512
+	// gptEntryMinSize covers the partition type GUID, unique GUID and the first and last LBA of an entry
+	gptEntryMinSize = 48
 )
 
+// rootMountOptionRegex matches the generic and btrfs mount options supported for the root file system.
+var rootMountOptionRegex = regexp.MustCompile(`^(` +
+	`noatime|relatime|strictatime|nodiratime|lazytime|nolazytime|` +
+	`ssd|nossd|ssd_spread|nossd_spread|discard|discard=(sync|async)|nodiscard|autodefrag|noautodefrag|` +
+	`(compress|compress-force)(=(zlib(:[1-9])?|lzo|zstd(:([1-9]|1[0-5]))?|no))?|` +
+	`commit=[1-9][0-9]*|flushoncommit|noflushoncommit|barrier|nobarrier|datacow|nodatacow|datasum|nodatasum` +
+	`)$`)
+
+// riskyRootMountOptions are supported, but reported as warnings along with their consequence.
+var riskyRootMountOptions = map[string]string{
+	"nobarrier": "may corrupt the file system on power loss",
+	"nodatacow": "disables checksums and compression of newly written data",
+	"nodatasum": "disables checksums of newly written data",
+}
+
+// requiredField describes a field which must be set in the definition for a particular image type.
+type requiredField struct {
+	name    string
+	section string
+	isSet   func(def *image.Definition) bool
+	// condition, when set, limits the rule to the definitions it applies to, described by conditionDescription
+	condition            func(def *image.Definition) bool
+	conditionDescription string
+	// consequence, when set, reports the missing field as a warning describing its effect instead of an error
+	consequence string
+}
+
+// imageTypeRules lists the fields required for each image type, in addition to the ones common to all types.
+var imageTypeRules = map[string][]requiredField{
+	image.TypeISO: {
+		{
+			name:    "isoConfiguration/installDevice",
+			section: "operatingSystem",
+			isSet: func(def *image.Definition) bool {
+				return def.OperatingSystem.IsoConfiguration.InstallDevice != ""
+			},
+			condition: func(def *image.Definition) bool {
+				return len(def.Kubernetes.Nodes) == 0
+			},
+			consequence: "the installation will not run unattended and prompts for the disk to install to",
+		},
+		{
+			name:    "isoConfiguration/installDevice",
+			section: "operatingSystem",
+			isSet: func(def *image.Definition) bool {
+				return def.OperatingSystem.IsoConfiguration.InstallDevice != ""
+			},
+			// The nodes of a multi-node cluster are provisioned from the same image and join it on their own
+			condition: func(def *image.Definition) bool {
+				return len(def.Kubernetes.Nodes) != 0
+			},
+			conditionDescription: "Kubernetes nodes are configured",
+		},
+	},
+	image.TypeRAW: {
+		{
+			name:    "rawConfiguration/diskSize",
+			section: "operatingSystem",
+			isSet: func(def *image.Definition) bool {
+				return def.OperatingSystem.RawConfiguration.DiskSize != ""
+			},
+			// Raw base images are only sized for the operating system, leaving no room for the Kubernetes artefacts
+			condition: func(def *image.Definition) bool {
+				return def.Kubernetes.Version != ""
+			},
+			conditionDescription: "Kubernetes is configured",
+		},
+	},
+}
+
 func validateImage(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
 
@@ -24,48 +105,67 @@ func validateImage(ctx *image.Context) []FailedValidation {
 
 	if def.Image.ImageType == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "image/imageType",
 			UserMessage: "The 'imageType' field is required in the 'image' section.",
 		})
 	} else if !slices.Contains(validImageTypes, def.Image.ImageType) {
 		msg := fmt.Sprintf("The 'imageType' field must be one of: %s", strings.Join(validImageTypes, ", "))
 		failures = append(failures, FailedValidation{
+			Field:       "image/imageType",
 			UserMessage: msg,
 		})
 	}
 
 	if def.Image.Arch == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "image/arch",
 			UserMessage: "The 'arch' field is required in the 'image' section.",
 		})
 	} else if !slices.Contains(validArchTypes, string(def.Image.Arch)) {
 		msg := fmt.Sprintf("The 'arch' field must be one of: %s", strings.Join(validArchTypes, ", "))
 		failures = append(failures, FailedValidation{
+			Field:       "image/arch",
 			UserMessage: msg,
 		})
 	}
 
 	if def.Image.OutputImageName == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "image/outputImageName",
 			UserMessage: "The 'outputImageName' field is required in the 'image' section.",
 		})
 	}
 
-	if def.Image.BaseImage == "" {
+	failures = append(failures, validateOutputFormat(def)...)
+	failures = append(failures, validateSeedISOs(def)...)
+
+	if ctx.CombustionOnly {
+		return append(failures, validateCombustionOnly(ctx)...)
+	}
+
+	failures = append(failures, validateImageTypeRules(def)...)
+
+	if ctx.BaseImageOverride != "" {
+		failures = append(failures, validateBaseImageOverride(ctx.BaseImageOverride, def.Image.ImageType)...)
+	} else if def.Image.BaseImage == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "image/baseImage",
 			UserMessage: "The 'baseImage' field is required in the 'image' section.",
 		})
 	} else {
-		baseImageFilename := filepath.Join(ctx.ImageConfigDir, "base-images", def.Image.BaseImage)
+		baseImageFilename := ctx.BaseImagePath()
 		_, err := os.Stat(baseImageFilename)
 		if err != nil {
 			if os.IsNotExist(err) {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be found.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
+					Field:       "image/baseImage",
 					UserMessage: msg,
 				})
 			} else {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be read. See the logs for more information.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
+					Field:       "image/baseImage",
 					UserMessage: msg,
 					Error:       err,
 				})
@@ -73,5 +173,603 @@ func validateImage(ctx *image.Context) []FailedValidation {
 		}
 	}
 
+	failures = append(failures, validateBaseImageArch(ctx)...)
+	failures = append(failures, validateABPartitions(ctx)...)
+	failures = append(failures, validateRootFilesystem(def)...)
+
+	return failures
+}
+
+// validateBaseImageArch compares the architecture of the base image, as far as it can be determined,
+// with the one requested in the definition. Missing or invalid base images are reported separately.
+func validateBaseImageArch(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	arch := ctx.ImageDefinition.Image.Arch
+	if arch != image.ArchTypeX86 && arch != image.ArchTypeARM {
+		return failures
+	}
+
+	path := ctx.BaseImagePath()
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return failures
+	}
+
+	baseImageArch, err := detectBaseImageArch(path)
+	if err != nil {
+		zap.S().Warnf("Detecting the architecture of base image '%s' failed: %s", path, err)
+		return failures
+	}
+
+	if baseImageArch == "" {
+		zap.S().Infof("The architecture of base image '%s' could not be determined", path)
+		return failures
+	}
+
+	zap.S().Infof("Base image '%s' architecture: %s, requested architecture: %s", path, baseImageArch, arch)
+
+	if baseImageArch != arch {
+		failures = append(failures, FailedValidation{
+			Field: "image/arch",
+			UserMessage: fmt.Sprintf("The base image '%s' is built for the '%s' architecture, which does not match the 'arch' field ('%s') in the 'image' section.",
+				filepath.Base(path), baseImageArch, arch),
+			Warning: ctx.AllowArchMismatch,
+		})
+	}
+
+	return failures
+}
+
+// validateABPartitions checks that the base image can be split into two root slots of the requested disk size.
+// The second slot is added as a fourth partition following the root partition of the SL Micro raw image.
+func validateABPartitions(ctx *image.Context) []FailedValidation {
+	def := ctx.ImageDefinition
+	if !def.OperatingSystem.RawConfiguration.ABPartitions {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if def.Image.ImageType != image.TypeRAW {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/rawConfiguration/abPartitions",
+			UserMessage: fmt.Sprintf("The 'rawConfiguration/abPartitions' field can only be used when 'imageType' is '%s'.", image.TypeRAW),
+		})
+	}
+
+	diskSize := def.OperatingSystem.RawConfiguration.DiskSize
+	if diskSize == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/rawConfiguration/diskSize",
+			UserMessage: "The 'rawConfiguration/diskSize' field is required when 'rawConfiguration/abPartitions' is enabled.",
+		})
+	}
+
+	if len(failures) > 0 || !diskSize.IsValid() {
+		// Invalid disk sizes are reported separately
+		return failures
+	}
+
+	path := ctx.BaseImagePath()
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		// Missing or invalid base images are reported separately
+		return failures
+	}
+
+	entries, err := readGPTEntries(path)
+	if err != nil {
+		return append(failures, FailedValidation{
+			Field:       "image/baseImage",
+			UserMessage: fmt.Sprintf("The partition table of the base image '%s' could not be read.", filepath.Base(path)),
+			Error:       err,
+		})
+	}
+
+	if entries == nil {
+		return append(failures, FailedValidation{
+			Field:       "operatingSystem/rawConfiguration/abPartitions",
+			UserMessage: fmt.Sprintf("The base image '%s' must be partitioned with GPT to use 'rawConfiguration/abPartitions'.", filepath.Base(path)),
+		})
+	}
+
+	// SL Micro raw images boot via GRUB from an EFI partition (2) and keep the root file system on partition 3
+	const (
+		rootPartition  = 3
+		slotBPartition = 4
+	)
+
+	if len(entries) < rootPartition || !gptEntryUsed(entries[rootPartition-1]) {
+		return append(failures, FailedValidation{
+			Field:       "operatingSystem/rawConfiguration/abPartitions",
+			UserMessage: fmt.Sprintf("The base image '%s' does not contain the root partition expected by 'rawConfiguration/abPartitions'.", filepath.Base(path)),
+		})
+	}
+
+	if len(entries) >= slotBPartition && gptEntryUsed(entries[slotBPartition-1]) {
+		return append(failures, FailedValidation{
+			Field:       "operatingSystem/rawConfiguration/abPartitions",
+			UserMessage: fmt.Sprintf("The base image '%s' already uses partition %d, which is required for the second root slot.", filepath.Base(path), slotBPartition),
+		})
+	}
+
+	const mb = 1024 * 1024
+
+	root := entries[rootPartition-1]
+	rootStart := int64(binary.LittleEndian.Uint64(root[32:40])) * gptSectorSize
+	rootSize := (int64(binary.LittleEndian.Uint64(root[40:48]))+1)*gptSectorSize - rootStart
+
+	// Leave room for the backup partition table at the end of the disk
+	required := rootStart + 2*rootSize + mb
+	if diskSize.ToMB()*mb < required {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/rawConfiguration/diskSize",
+			UserMessage: fmt.Sprintf("The 'rawConfiguration/diskSize' field must be at least %dM to fit two root slots of the base image '%s'.",
+				(required+mb-1)/mb, filepath.Base(path)),
+		})
+	}
+
+	return failures
+}
+
+func gptEntryUsed(entry []byte) bool {
+	for _, b := range entry[0:16] {
+		if b != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateOutputFormat(def *image.Definition) []FailedValidation {
+	var failures []FailedValidation
+
+	validOutputFormats := []string{image.OutputFormatRAW, image.OutputFormatQCOW2}
+
+	switch def.Image.OutputFormat {
+	case "":
+	case image.OutputFormatRAW, image.OutputFormatQCOW2:
+		if def.Image.ImageType != image.TypeRAW {
+			msg := fmt.Sprintf("The 'outputFormat' field can only be used when 'imageType' is '%s'.", image.TypeRAW)
+			failures = append(failures, FailedValidation{
+				Field:       "image/outputFormat",
+				UserMessage: msg,
+			})
+		}
+	default:
+		msg := fmt.Sprintf("The 'outputFormat' field must be one of: %s", strings.Join(validOutputFormats, ", "))
+		failures = append(failures, FailedValidation{
+			Field:       "image/outputFormat",
+			UserMessage: msg,
+		})
+	}
+
+	if def.Image.Qcow2Configuration != (image.Qcow2Configuration{}) && def.Image.OutputFormat != image.OutputFormatQCOW2 {
+		msg := fmt.Sprintf("The 'qcow2Configuration' field can only be used when 'outputFormat' is '%s'.", image.OutputFormatQCOW2)
+		failures = append(failures, FailedValidation{
+			Field:       "image/qcow2Configuration",
+			UserMessage: msg,
+		})
+	}
+
 	return failures
 }
+
+func validateImageTypeRules(def *image.Definition) []FailedValidation {
+	var failures []FailedValidation
+
+	rules, ok := imageTypeRules[def.Image.ImageType]
+	if !ok {
+		// Missing or invalid image types are reported separately
+		return failures
+	}
+
+	log.AuditInfof("Validating required fields using the '%s' image type rules.", def.Image.ImageType)
+
+	for _, field := range rules {
+		if (field.condition != nil && !field.condition(def)) || field.isSet(def) {
+			continue
+		}
+
+		if field.consequence != "" {
+			msg := fmt.Sprintf("The '%s' field is not set in the '%s' section, %s.", field.name, field.section, field.consequence)
+			failures = append(failures, FailedValidation{
+				Field:       field.section + "/" + field.name,
+				UserMessage: msg,
+				Warning:     true,
+			})
+			continue
+		}
+
+		msg := fmt.Sprintf("The '%s' field is required in the '%s' section when 'imageType' is '%s'",
+			field.name, field.section, def.Image.ImageType)
+		if field.conditionDescription != "" {
+			msg += " and " + field.conditionDescription
+		}
+		msg += "."
+
+		failures = append(failures, FailedValidation{
+			Field:       field.section + "/" + field.name,
+			UserMessage: msg,
+		})
+	}
+
+	return failures
+}
+
+func validateBaseImageOverride(path, imageType string) []FailedValidation {
+	var failures []FailedValidation
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			failures = append(failures, FailedValidation{
+				Field:       "image/baseImage",
+				UserMessage: fmt.Sprintf("The base image override '%s' cannot be found.", path),
+			})
+		} else {
+			failures = append(failures, FailedValidation{
+				Field:       "image/baseImage",
+				UserMessage: fmt.Sprintf("The base image override '%s' cannot be read. See the logs for more information.", path),
+				Error:       err,
+			})
+		}
+
+		return failures
+	}
+
+	if !info.Mode().IsRegular() {
+		failures = append(failures, FailedValidation{
+			Field:       "image/baseImage",
+			UserMessage: fmt.Sprintf("The base image override '%s' must be a regular file.", path),
+		})
+
+		return failures
+	}
+
+	if imageType != image.TypeISO && imageType != image.TypeRAW {
+		// The image type itself is reported as invalid above
+		return failures
+	}
+
+	detectedType, err := detectBaseImageType(path)
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "image/baseImage",
+			UserMessage: fmt.Sprintf("The base image override '%s' cannot be read. See the logs for more information.", path),
+			Error:       err,
+		})
+	} else if detectedType != imageType {
+		failures = append(failures, FailedValidation{
+			Field:       "image/baseImage",
+			UserMessage: fmt.Sprintf("The base image override '%s' is not a valid '%s' image.", path, imageType),
+		})
+	}
+
+	return failures
+}
+
+// detectBaseImageType inspects the contents of the given file and returns the type of image
+// it holds or an empty string if neither of the supported image types is recognised.
+func detectBaseImageType(path string) (string, error) {
+	const (
+		// ISO 9660 images carry the "CD001" identifier in the primary volume descriptor
+		isoIdentifierOffset = 0x8001
+		isoIdentifier       = "CD001"
+
+		// Disk images carry the boot signature at the end of the first sector (MBR or protective MBR for GPT)
+		bootSignatureOffset = 510
+	)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	identifier := make([]byte, len(isoIdentifier))
+	if _, err = file.ReadAt(identifier, isoIdentifierOffset); err == nil && string(identifier) == isoIdentifier {
+		return image.TypeISO, nil
+	} else if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("reading iso identifier: %w", err)
+	}
+
+	signature := make([]byte, 2)
+	if _, err = file.ReadAt(signature, bootSignatureOffset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("reading boot signature: %w", err)
+	}
+
+	if signature[0] == 0x55 && signature[1] == 0xAA {
+		return image.TypeRAW, nil
+	}
+
+	return "", nil
+}
+
+// gptTypeGUID converts a partition type GUID to the mixed-endian form in which it is stored in the partition table.
+func gptTypeGUID(guid string) string {
+	b, err := hex.DecodeString(strings.ReplaceAll(guid, "-", ""))
+	if err != nil || len(b) != 16 {
+		panic(fmt.Sprintf("invalid GUID: %s", guid))
+	}
+
+	slices.Reverse(b[0:4])
+	slices.Reverse(b[4:6])
+	slices.Reverse(b[6:8])
+
+	return string(b)
+}
+
+// gptArchPartitionTypes lists the architecture specific root and /usr partition types of the
+// Discoverable Partitions Specification (https://uapi-group.org/specifications/specs/discoverable_partitions_specification/).
+var gptArchPartitionTypes = map[string]image.Arch{
+	gptTypeGUID("4F68BCE3-E8CD-4DB1-96E7-FBCAF984B709"): image.ArchTypeX86,
+	gptTypeGUID("8484680C-9521-48C6-9C11-B0720656F69E"): image.ArchTypeX86,
+	gptTypeGUID("B921B045-1DF0-41C3-AF44-4C6F280D3FAE"): image.ArchTypeARM,
+	gptTypeGUID("B0E01050-EE5F-4390-949A-9101B17104E9"): image.ArchTypeARM,
+}
+
+// baseImageArchRegex matches the architecture in the conventional image names (e.g. SL-Micro.x86_64-6.0-Base-GM.raw).
+var baseImageArchRegex = regexp.MustCompile(`(?:^|[._-])(x86_64|aarch64)(?:[._-]|$)`)
+
+// detectBaseImageArch inspects the contents of the given base image, falling back to its name, and returns
+// the architecture it is built for or an empty string if the architecture cannot be determined.
+func detectBaseImageArch(path string) (image.Arch, error) {
+	imageType, err := detectBaseImageType(path)
+	if err != nil {
+		return "", err
+	}
+
+	var arch image.Arch
+
+	switch imageType {
+	case image.TypeISO:
+		arch, err = detectISOArch(path)
+	case image.TypeRAW:
+		arch, err = detectGPTArch(path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if arch == "" {
+		if match := baseImageArchRegex.FindStringSubmatch(filepath.Base(path)); match != nil {
+			arch = image.Arch(match[1])
+		}
+	}
+
+	return arch, nil
+}
+
+// detectGPTArch looks for an architecture specific partition in the GUID partition table of a disk image.
+func detectGPTArch(path string) (image.Arch, error) {
+	entries, err := readGPTEntries(path)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if arch, ok := gptArchPartitionTypes[string(entry[0:16])]; ok {
+			return arch, nil
+		}
+	}
+
+	return "", nil
+}
+
+// readGPTEntries returns the raw entries of the GUID partition table of a disk image with 512 byte sectors,
+// or nil if the image is not partitioned with GPT.
+func readGPTEntries(path string) ([][]byte, error) {
+	const (
+		gptSignature = "EFI PART"
+		maxEntries   = 1024
+	)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 92)
+	if _, err = file.ReadAt(header, gptSectorSize); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading partition table header: %w", err)
+	}
+
+	if string(header[0:8]) != gptSignature {
+		// Not partitioned with GPT (e.g. MBR only)
+		return nil, nil
+	}
+
+	entriesLBA := binary.LittleEndian.Uint64(header[72:80])
+	entryCount := binary.LittleEndian.Uint32(header[80:84])
+	entrySize := binary.LittleEndian.Uint32(header[84:88])
+
+	if entrySize < gptEntryMinSize || entryCount > maxEntries {
+		return nil, fmt.Errorf("invalid partition table header")
+	}
+
+	data := make([]byte, int(entryCount)*int(entrySize))
+	if _, err = file.ReadAt(data, int64(entriesLBA)*gptSectorSize); err != nil {
+		return nil, fmt.Errorf("reading partition entries: %w", err)
+	}
+
+	entries := make([][]byte, 0, entryCount)
+	for offset := 0; offset < len(data); offset += int(entrySize) {
+		entries = append(entries, data[offset:offset+int(entrySize)])
+	}
+
+	return entries, nil
+}
+
+// detectISOArch looks for the architecture specific boot directory (e.g. /boot/x86_64) of an installer ISO.
+func detectISOArch(path string) (image.Arch, error) {
+	const (
+		sectorSize       = 2048
+		pvdOffset        = 16 * sectorSize
+		rootRecordOffset = 156
+	)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	rootRecord := make([]byte, 34)
+	if _, err = file.ReadAt(rootRecord, pvdOffset+rootRecordOffset); err != nil {
+		return "", fmt.Errorf("reading root directory record: %w", err)
+	}
+
+	root, err := readISODirectory(file, rootRecord)
+	if err != nil {
+		return "", fmt.Errorf("reading root directory: %w", err)
+	}
+
+	bootRecord, ok := root["BOOT"]
+	if !ok {
+		return "", nil
+	}
+
+	boot, err := readISODirectory(file, bootRecord)
+	if err != nil {
+		return "", fmt.Errorf("reading boot directory: %w", err)
+	}
+
+	for _, arch := range []image.Arch{image.ArchTypeX86, image.ArchTypeARM} {
+		if _, ok = boot[strings.ToUpper(string(arch))]; ok {
+			return arch, nil
+		}
+	}
+
+	return "", nil
+}
+
+// readISODirectory returns the records of the subdirectories in the ISO 9660 directory described by the given record.
+func readISODirectory(file *os.File, record []byte) (map[string][]byte, error) {
+	const (
+		sectorSize    = 2048
+		maxDirSize    = 1 << 20
+		directoryFlag = 0x02
+	)
+
+	extent := binary.LittleEndian.Uint32(record[2:6])
+	size := binary.LittleEndian.Uint32(record[10:14])
+	if size > maxDirSize {
+		return nil, fmt.Errorf("directory too large: %d bytes", size)
+	}
+
+	data := make([]byte, size)
+	if _, err := file.ReadAt(data, int64(extent)*sectorSize); err != nil {
+		return nil, err
+	}
+
+	directories := map[string][]byte{}
+
+	for offset := 0; offset < len(data); {
+		length := int(data[offset])
+		if length == 0 {
+			// Records do not span sectors, the remainder of the sector is padding
+			offset = (offset/sectorSize + 1) * sectorSize
+			continue
+		}
+
+		if length < 34 || offset+length > len(data) {
+			return nil, fmt.Errorf("invalid directory record")
+		}
+
+		entry := data[offset : offset+length]
+		nameLength := int(entry[32])
+		if entry[25]&directoryFlag != 0 && 33+nameLength <= length {
+			name := strings.ToUpper(string(entry[33 : 33+nameLength]))
+			directories[name] = entry
+		}
+
+		offset += length
+	}
+
+	return directories, nil
+}
+
+func validateRootFilesystem(def *image.Definition) []FailedValidation {
+	rootFilesystem := def.Image.RootFilesystem
+
+	var failures []FailedValidation
+
+	if rootFilesystem.Type != "" && rootFilesystem.Type != image.RootFilesystemBtrfs {
+		failures = append(failures, FailedValidation{
+			Field: "image/rootFilesystem/type",
+			UserMessage: fmt.Sprintf("The 'rootFilesystem/type' field must be '%s', as the root file system of the base images cannot be changed.",
+				image.RootFilesystemBtrfs),
+		})
+	}
+
+	if len(rootFilesystem.MountOptions) == 0 {
+		return failures
+	}
+
+	if def.Image.ImageType != image.TypeRAW {
+		failures = append(failures, FailedValidation{
+			Field:       "image/rootFilesystem/mountOptions",
+			UserMessage: fmt.Sprintf("The 'rootFilesystem/mountOptions' field can only be used when 'imageType' is '%s'.", image.TypeRAW),
+		})
+	}
+
+	seenOptions := make(map[string]string)
+	for _, option := range rootFilesystem.MountOptions {
+		name, _, _ := strings.Cut(option, "=")
+
+		switch {
+		case name == "ro" || name == "rw" || name == "subvol" || name == "subvolid":
+			failures = append(failures, FailedValidation{
+				Field:       "image/rootFilesystem/mountOptions",
+				UserMessage: fmt.Sprintf("The root mount option '%s' is managed by the base image and cannot be changed.", option),
+			})
+			continue
+		case !rootMountOptionRegex.MatchString(option):
+			failures = append(failures, FailedValidation{
+				Field:       "image/rootFilesystem/mountOptions",
+				UserMessage: fmt.Sprintf("The root mount option '%s' is not supported. See the documentation for the supported options.", option),
+			})
+			continue
+		}
+
+		key := rootMountOptionKey(name)
+		if seen, ok := seenOptions[key]; ok {
+			failures = append(failures, FailedValidation{
+				Field:       "image/rootFilesystem/mountOptions",
+				UserMessage: fmt.Sprintf("The root mount options '%s' and '%s' conflict, only one of them may be specified.", seen, option),
+			})
+		}
+		seenOptions[key] = option
+
+		if consequence, ok := riskyRootMountOptions[option]; ok {
+			failures = append(failures, FailedValidation{
+				Field:       "image/rootFilesystem/mountOptions",
+				UserMessage: fmt.Sprintf("The root mount option '%s' %s.", option, consequence),
+				Warning:     true,
+			})
+		}
+	}
+
+	return failures
+}
+
+// rootMountOptionKey groups the options overriding each other, matching how they are merged into the fstab
+// of the image (e.g. noatime and relatime, or ssd and nossd).
+func rootMountOptionKey(name string) string {
+	switch name {
+	case "atime", "noatime", "relatime", "strictatime":
+		return "atime"
+	}
+
+	return strings.TrimPrefix(name, "no")
+}
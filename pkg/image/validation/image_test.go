@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/binary"
 	"os"
 	"path/filepath"
 	"testing"
@@ -38,6 +39,97 @@ func TestValidateImage(t *testing.T) {
 					OutputImageName: "eib-created.iso",
 				},
 			},
+			ExpectedFailedMessages: []string{
+				"The 'isoConfiguration/installDevice' field is not set in the 'operatingSystem' section, the installation will not run unattended and prompts for the disk to install to.",
+			},
+		},
+		`complete valid unattended definition`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.iso",
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+				},
+			},
+		},
+		`complete valid raw definition`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.raw",
+				},
+			},
+		},
+		`qcow2 output`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.qcow2",
+					OutputFormat:    image.OutputFormatQCOW2,
+					Qcow2Configuration: image.Qcow2Configuration{
+						Compress: true,
+					},
+				},
+			},
+		},
+		`invalid output format`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.vmdk",
+					OutputFormat:    "vmdk",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'outputFormat' field must be one of: raw, qcow2",
+			},
+		},
+		`output format with iso`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.qcow2",
+					OutputFormat:    image.OutputFormatQCOW2,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'outputFormat' field can only be used when 'imageType' is 'raw'.",
+			},
+		},
+		`qcow2 configuration without qcow2 output`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeRAW,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "eib-created.raw",
+					Qcow2Configuration: image.Qcow2Configuration{
+						Compress: true,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'qcow2Configuration' field can only be used when 'outputFormat' is 'qcow2'.",
+			},
 		},
 		`missing all fields`: {
 			ImageDefinition: image.Definition{
@@ -75,6 +167,7 @@ func TestValidateImage(t *testing.T) {
 			},
 			ExpectedFailedMessages: []string{
 				"The specified base image 'not-there' cannot be found.",
+				"The 'isoConfiguration/installDevice' field is not set in the 'operatingSystem' section, the installation will not run unattended and prompts for the disk to install to.",
 			},
 		},
 	}
@@ -100,3 +193,606 @@ func TestValidateImage(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBaseImageOverride(t *testing.T) {
+	overrideDir, err := os.MkdirTemp("", "eib-base-image-override-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(overrideDir)
+	}()
+
+	isoContents := make([]byte, 0x8010)
+	copy(isoContents[0x8001:], "CD001")
+	isoImage := filepath.Join(overrideDir, "base.iso")
+	require.NoError(t, os.WriteFile(isoImage, isoContents, 0o600))
+
+	rawContents := make([]byte, 1024)
+	rawContents[510], rawContents[511] = 0x55, 0xAA
+	rawImage := filepath.Join(overrideDir, "base.raw")
+	require.NoError(t, os.WriteFile(rawImage, rawContents, 0o600))
+
+	emptyImage := filepath.Join(overrideDir, "empty.raw")
+	require.NoError(t, os.WriteFile(emptyImage, nil, 0o600))
+
+	tests := map[string]struct {
+		Path                   string
+		ImageType              string
+		ExpectedFailedMessages []string
+	}{
+		`valid iso`: {
+			Path:      isoImage,
+			ImageType: image.TypeISO,
+		},
+		`valid raw`: {
+			Path:      rawImage,
+			ImageType: image.TypeRAW,
+		},
+		`iso used for raw`: {
+			Path:      isoImage,
+			ImageType: image.TypeRAW,
+			ExpectedFailedMessages: []string{
+				"The base image override '" + isoImage + "' is not a valid 'raw' image.",
+			},
+		},
+		`raw used for iso`: {
+			Path:      rawImage,
+			ImageType: image.TypeISO,
+			ExpectedFailedMessages: []string{
+				"The base image override '" + rawImage + "' is not a valid 'iso' image.",
+			},
+		},
+		`unrecognised contents`: {
+			Path:      emptyImage,
+			ImageType: image.TypeRAW,
+			ExpectedFailedMessages: []string{
+				"The base image override '" + emptyImage + "' is not a valid 'raw' image.",
+			},
+		},
+		`not found`: {
+			Path:      filepath.Join(overrideDir, "missing.raw"),
+			ImageType: image.TypeRAW,
+			ExpectedFailedMessages: []string{
+				"The base image override '" + filepath.Join(overrideDir, "missing.raw") + "' cannot be found.",
+			},
+		},
+		`directory`: {
+			Path:      overrideDir,
+			ImageType: image.TypeRAW,
+			ExpectedFailedMessages: []string{
+				"The base image override '" + overrideDir + "' must be a regular file.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failedValidations := validateBaseImageOverride(test.Path, test.ImageType)
+			assert.Len(t, failedValidations, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failedValidations {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateImage_BaseImageOverride(t *testing.T) {
+	overrideDir, err := os.MkdirTemp("", "eib-base-image-override-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(overrideDir)
+	}()
+
+	rawContents := make([]byte, 1024)
+	rawContents[510], rawContents[511] = 0x55, 0xAA
+	rawImage := filepath.Join(overrideDir, "base.raw")
+	require.NoError(t, os.WriteFile(rawImage, rawContents, 0o600))
+
+	// The base image from the definition is neither required nor checked when overridden
+	ctx := image.Context{
+		ImageConfigDir: overrideDir,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       image.TypeRAW,
+				Arch:            image.ArchTypeX86,
+				OutputImageName: "eib-created.raw",
+			},
+		},
+		BaseImageOverride: rawImage,
+	}
+
+	failedValidations := validateImage(&ctx)
+	assert.Empty(t, failedValidations)
+}
+
+// isoDirectoryRecord creates an ISO 9660 directory record for a subdirectory.
+func isoDirectoryRecord(name string, extent uint32) []byte {
+	record := make([]byte, 34+len(name))
+	record[0] = byte(len(record))
+	binary.LittleEndian.PutUint32(record[2:6], extent)
+	binary.LittleEndian.PutUint32(record[10:14], 2048)
+	record[25] = 0x02
+	record[32] = byte(len(name))
+	copy(record[33:], name)
+
+	return record
+}
+
+func fakeISO(archDir string) []byte {
+	const sectorSize = 2048
+
+	contents := make([]byte, 20*sectorSize)
+	copy(contents[0x8001:], "CD001")
+
+	// The root directory refers to the boot directory, which in turn refers to the architecture directory
+	copy(contents[16*sectorSize+156:], isoDirectoryRecord("\x00", 17))
+	copy(contents[17*sectorSize:], isoDirectoryRecord("BOOT", 18))
+	copy(contents[18*sectorSize:], isoDirectoryRecord(archDir, 19))
+
+	return contents
+}
+
+func fakeGPT(partitionTypes ...string) []byte {
+	const sectorSize = 512
+
+	contents := make([]byte, 4*sectorSize)
+	contents[510], contents[511] = 0x55, 0xAA
+
+	header := contents[sectorSize:]
+	copy(header, "EFI PART")
+	binary.LittleEndian.PutUint64(header[72:80], 2)
+	binary.LittleEndian.PutUint32(header[80:84], uint32(len(partitionTypes)))
+	binary.LittleEndian.PutUint32(header[84:88], 128)
+
+	for i, partitionType := range partitionTypes {
+		copy(contents[2*sectorSize+i*128:], gptTypeGUID(partitionType))
+	}
+
+	return contents
+}
+
+func TestDetectBaseImageArch(t *testing.T) {
+	const (
+		linuxFilesystem = "0FC63DAF-8483-4772-8E79-3D69D8477DE4"
+		efiSystem       = "C12A7328-F81F-11D2-BA4B-00A0C93EC93B"
+		rootX86         = "4F68BCE3-E8CD-4DB1-96E7-FBCAF984B709"
+		usrARM          = "B0E01050-EE5F-4390-949A-9101B17104E9"
+	)
+
+	mbrContents := make([]byte, 1024)
+	mbrContents[510], mbrContents[511] = 0x55, 0xAA
+
+	tests := map[string]struct {
+		filename     string
+		contents     []byte
+		expectedArch image.Arch
+	}{
+		"x86_64 iso": {
+			filename:     "installer.iso",
+			contents:     fakeISO("X86_64"),
+			expectedArch: image.ArchTypeX86,
+		},
+		"aarch64 iso": {
+			filename:     "installer.iso",
+			contents:     fakeISO("AARCH64"),
+			expectedArch: image.ArchTypeARM,
+		},
+		"iso without arch directory": {
+			filename: "installer.iso",
+			contents: fakeISO("GRUB2"),
+		},
+		"x86_64 raw": {
+			filename:     "disk.raw",
+			contents:     fakeGPT(efiSystem, rootX86),
+			expectedArch: image.ArchTypeX86,
+		},
+		"aarch64 raw": {
+			filename:     "disk.raw",
+			contents:     fakeGPT(efiSystem, usrARM),
+			expectedArch: image.ArchTypeARM,
+		},
+		"generic partitions": {
+			filename: "disk.raw",
+			contents: fakeGPT(efiSystem, linuxFilesystem),
+		},
+		"generic partitions with arch in name": {
+			filename:     "SL-Micro.aarch64-6.0-Base-GM.raw",
+			contents:     fakeGPT(efiSystem, linuxFilesystem),
+			expectedArch: image.ArchTypeARM,
+		},
+		"mbr with arch in name": {
+			filename:     "SL-Micro.x86_64-6.0-Base-GM.raw",
+			contents:     mbrContents,
+			expectedArch: image.ArchTypeX86,
+		},
+		"contents take precedence over name": {
+			filename:     "SL-Micro.aarch64-6.0-Base-GM.raw",
+			contents:     fakeGPT(rootX86),
+			expectedArch: image.ArchTypeX86,
+		},
+		"unrecognised": {
+			filename: "base-x86_64ish.raw",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), test.filename)
+			require.NoError(t, os.WriteFile(path, test.contents, 0o600))
+
+			arch, err := detectBaseImageArch(path)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedArch, arch)
+		})
+	}
+
+}
+
+func TestValidateBaseImageArch(t *testing.T) {
+	imageConfigDir := t.TempDir()
+
+	baseImagesDir := filepath.Join(imageConfigDir, "base-images")
+	require.NoError(t, os.Mkdir(baseImagesDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(baseImagesDir, "installer.iso"), fakeISO("AARCH64"), 0o600))
+
+	expectedMessage := "The base image 'installer.iso' is built for the 'aarch64' architecture, " +
+		"which does not match the 'arch' field ('x86_64') in the 'image' section."
+
+	tests := map[string]struct {
+		arch              image.Arch
+		allowArchMismatch bool
+		expectedFailure   bool
+	}{
+		"matching": {
+			arch: image.ArchTypeARM,
+		},
+		"mismatch": {
+			arch:            image.ArchTypeX86,
+			expectedFailure: true,
+		},
+		"allowed mismatch": {
+			arch:              image.ArchTypeX86,
+			allowArchMismatch: true,
+			expectedFailure:   true,
+		},
+		"invalid arch": {
+			arch: "riscv64",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: imageConfigDir,
+				ImageDefinition: &image.Definition{
+					Image: image.Image{
+						Arch:      test.arch,
+						BaseImage: "installer.iso",
+					},
+				},
+				AllowArchMismatch: test.allowArchMismatch,
+			}
+
+			failures := validateBaseImageArch(&ctx)
+			if !test.expectedFailure {
+				assert.Empty(t, failures)
+				return
+			}
+
+			require.Len(t, failures, 1)
+			assert.Equal(t, expectedMessage, failures[0].UserMessage)
+			assert.Equal(t, test.allowArchMismatch, failures[0].Warning)
+		})
+	}
+}
+
+func TestValidateABPartitions(t *testing.T) {
+	const (
+		efiSystem       = "C12A7328-F81F-11D2-BA4B-00A0C93EC93B"
+		biosBoot        = "21686148-6449-6E6F-744E-656564454649"
+		linuxFilesystem = "0FC63DAF-8483-4772-8E79-3D69D8477DE4"
+	)
+
+	// The root partition starts at 3 MiB and spans 2 GiB
+	slMicro := fakeGPT(biosBoot, efiSystem, linuxFilesystem)
+	root := slMicro[2*512+2*128:]
+	binary.LittleEndian.PutUint64(root[32:40], 6144)
+	binary.LittleEndian.PutUint64(root[40:48], 6144+4*1024*1024-1)
+
+	imageConfigDir := t.TempDir()
+
+	baseImagesDir := filepath.Join(imageConfigDir, "base-images")
+	require.NoError(t, os.Mkdir(baseImagesDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(baseImagesDir, "sl-micro.raw"), slMicro, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(baseImagesDir, "four.raw"), fakeGPT(biosBoot, efiSystem, linuxFilesystem, linuxFilesystem), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(baseImagesDir, "two.raw"), fakeGPT(biosBoot, efiSystem), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(baseImagesDir, "mbr.raw"), make([]byte, 1024), 0o600))
+
+	tests := map[string]struct {
+		imageType              string
+		baseImage              string
+		diskSize               image.DiskSize
+		disabled               bool
+		expectedFailedMessages []string
+	}{
+		`disabled`: {
+			imageType: image.TypeISO,
+			baseImage: "mbr.raw",
+			disabled:  true,
+		},
+		`fits`: {
+			imageType: image.TypeRAW,
+			baseImage: "sl-micro.raw",
+			diskSize:  "8G",
+		},
+		`fits exactly`: {
+			imageType: image.TypeRAW,
+			baseImage: "sl-micro.raw",
+			diskSize:  "4100M",
+		},
+		`too small`: {
+			imageType: image.TypeRAW,
+			baseImage: "sl-micro.raw",
+			diskSize:  "4G",
+			expectedFailedMessages: []string{
+				"The 'rawConfiguration/diskSize' field must be at least 4100M to fit two root slots of the base image 'sl-micro.raw'.",
+			},
+		},
+		`iso`: {
+			imageType: image.TypeISO,
+			baseImage: "sl-micro.raw",
+			diskSize:  "8G",
+			expectedFailedMessages: []string{
+				"The 'rawConfiguration/abPartitions' field can only be used when 'imageType' is 'raw'.",
+			},
+		},
+		`missing disk size`: {
+			imageType: image.TypeRAW,
+			baseImage: "sl-micro.raw",
+			expectedFailedMessages: []string{
+				"The 'rawConfiguration/diskSize' field is required when 'rawConfiguration/abPartitions' is enabled.",
+			},
+		},
+		`not gpt`: {
+			imageType: image.TypeRAW,
+			baseImage: "mbr.raw",
+			diskSize:  "8G",
+			expectedFailedMessages: []string{
+				"The base image 'mbr.raw' must be partitioned with GPT to use 'rawConfiguration/abPartitions'.",
+			},
+		},
+		`missing root partition`: {
+			imageType: image.TypeRAW,
+			baseImage: "two.raw",
+			diskSize:  "8G",
+			expectedFailedMessages: []string{
+				"The base image 'two.raw' does not contain the root partition expected by 'rawConfiguration/abPartitions'.",
+			},
+		},
+		`partition in use`: {
+			imageType: image.TypeRAW,
+			baseImage: "four.raw",
+			diskSize:  "8G",
+			expectedFailedMessages: []string{
+				"The base image 'four.raw' already uses partition 4, which is required for the second root slot.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: imageConfigDir,
+				ImageDefinition: &image.Definition{
+					Image: image.Image{
+						ImageType: test.imageType,
+						BaseImage: test.baseImage,
+					},
+					OperatingSystem: image.OperatingSystem{
+						RawConfiguration: image.RawConfiguration{
+							DiskSize:     test.diskSize,
+							ABPartitions: !test.disabled,
+						},
+					},
+				},
+			}
+
+			failures := validateABPartitions(&ctx)
+
+			var foundMessages []string
+			for _, failure := range failures {
+				foundMessages = append(foundMessages, failure.UserMessage)
+			}
+
+			assert.ElementsMatch(t, test.expectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateRootFilesystem(t *testing.T) {
+	tests := map[string]struct {
+		ImageType              string
+		RootFilesystem         image.RootFilesystem
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {
+			ImageType: image.TypeISO,
+		},
+		`valid`: {
+			ImageType: image.TypeRAW,
+			RootFilesystem: image.RootFilesystem{
+				Type:         "btrfs",
+				MountOptions: []string{"noatime", "compress=zstd:3", "discard=async", "commit=120", "nossd"},
+			},
+		},
+		`unsupported type`: {
+			ImageType: image.TypeRAW,
+			RootFilesystem: image.RootFilesystem{
+				Type: "ext4",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'rootFilesystem/type' field must be 'btrfs', as the root file system of the base images cannot be changed.",
+			},
+		},
+		`iso image`: {
+			ImageType: image.TypeISO,
+			RootFilesystem: image.RootFilesystem{
+				MountOptions: []string{"noatime"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'rootFilesystem/mountOptions' field can only be used when 'imageType' is 'raw'.",
+			},
+		},
+		`invalid options`: {
+			ImageType: image.TypeRAW,
+			RootFilesystem: image.RootFilesystem{
+				MountOptions: []string{"rw", "subvol=/@", "compress=zstd:20", "data=journal", "noatime,nossd"},
+			},
+			ExpectedFailedMessages: []string{
+				"The root mount option 'rw' is managed by the base image and cannot be changed.",
+				"The root mount option 'subvol=/@' is managed by the base image and cannot be changed.",
+				"The root mount option 'compress=zstd:20' is not supported. See the documentation for the supported options.",
+				"The root mount option 'data=journal' is not supported. See the documentation for the supported options.",
+				"The root mount option 'noatime,nossd' is not supported. See the documentation for the supported options.",
+			},
+		},
+		`conflicting options`: {
+			ImageType: image.TypeRAW,
+			RootFilesystem: image.RootFilesystem{
+				MountOptions: []string{"noatime", "relatime", "ssd", "nossd"},
+			},
+			ExpectedFailedMessages: []string{
+				"The root mount options 'noatime' and 'relatime' conflict, only one of them may be specified.",
+				"The root mount options 'ssd' and 'nossd' conflict, only one of them may be specified.",
+			},
+		},
+		`risky options`: {
+			ImageType: image.TypeRAW,
+			RootFilesystem: image.RootFilesystem{
+				MountOptions: []string{"nobarrier", "nodatacow"},
+			},
+			ExpectedFailedMessages: []string{
+				"The root mount option 'nobarrier' may corrupt the file system on power loss.",
+				"The root mount option 'nodatacow' disables checksums and compression of newly written data.",
+			},
+			ExpectedWarnings: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := &image.Definition{
+				Image: image.Image{
+					ImageType:      test.ImageType,
+					RootFilesystem: test.RootFilesystem,
+				},
+			}
+
+			failures := validateRootFilesystem(def)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateImageTypeRules_InstallDeviceWarning(t *testing.T) {
+	def := image.Definition{
+		Image: image.Image{
+			ImageType: image.TypeISO,
+		},
+	}
+
+	failures := validateImageTypeRules(&def)
+	require.Len(t, failures, 1)
+	assert.True(t, failures[0].Warning)
+
+	def.OperatingSystem.IsoConfiguration.InstallDevice = "/dev/sda"
+	assert.Empty(t, validateImageTypeRules(&def))
+}
+
+func TestValidateImageTypeRules(t *testing.T) {
+	tests := map[string]struct {
+		Definition       image.Definition
+		ExpectedFailures []FailedValidation
+	}{
+		"ISO with install device": {
+			Definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeISO},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{InstallDevice: "/dev/sda"},
+				},
+				Kubernetes: image.Kubernetes{Nodes: []image.Node{{Hostname: "node1"}}},
+			},
+		},
+		"ISO with Kubernetes nodes and no install device": {
+			Definition: image.Definition{
+				Image:      image.Image{ImageType: image.TypeISO},
+				Kubernetes: image.Kubernetes{Nodes: []image.Node{{Hostname: "node1"}, {Hostname: "node2"}}},
+			},
+			ExpectedFailures: []FailedValidation{
+				{
+					Field: "operatingSystem/isoConfiguration/installDevice",
+					UserMessage: "The 'isoConfiguration/installDevice' field is required in the 'operatingSystem' section " +
+						"when 'imageType' is 'iso' and Kubernetes nodes are configured.",
+				},
+			},
+		},
+		"RAW without Kubernetes": {
+			Definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW},
+			},
+		},
+		"RAW with Kubernetes and disk size": {
+			Definition: image.Definition{
+				Image: image.Image{ImageType: image.TypeRAW},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{DiskSize: "64G"},
+				},
+				Kubernetes: image.Kubernetes{Version: "v1.30.3+rke2r1"},
+			},
+		},
+		"RAW with Kubernetes and no disk size": {
+			Definition: image.Definition{
+				Image:      image.Image{ImageType: image.TypeRAW},
+				Kubernetes: image.Kubernetes{Version: "v1.30.3+rke2r1"},
+			},
+			ExpectedFailures: []FailedValidation{
+				{
+					Field: "operatingSystem/rawConfiguration/diskSize",
+					UserMessage: "The 'rawConfiguration/diskSize' field is required in the 'operatingSystem' section " +
+						"when 'imageType' is 'raw' and Kubernetes is configured.",
+				},
+			},
+		},
+		"Unknown image type": {
+			Definition: image.Definition{
+				Image:      image.Image{ImageType: "vmdk"},
+				Kubernetes: image.Kubernetes{Version: "v1.30.3+rke2r1"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateImageTypeRules(&test.Definition)
+
+			assert.Equal(t, test.ExpectedFailures, failures)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// kdumpMemoryRegex matches the reservations accepted by the crashkernel kernel argument: a size optionally
+// followed by an offset or a placement, or a list of sizes reserved depending on the memory of the node
+var kdumpMemoryRegex = regexp.MustCompile(
+	`^([0-9]+[KMG](@[0-9]+[KMG]|,high|,low)?|[0-9]+[KMG]-([0-9]+[KMG])?:[0-9]+[KMG](,[0-9]+[KMG]-([0-9]+[KMG])?:[0-9]+[KMG])*(@[0-9]+[KMG])?)$`)
+
+func validateKdump(os *image.OperatingSystem) []FailedValidation {
+	kdump := &os.Kdump
+
+	var failures []FailedValidation
+
+	if kdump.Memory == "" {
+		if kdump.SavePath != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/kdump/memory",
+				UserMessage: "The 'memory' field is required when configuring the 'kdump' section.",
+			})
+		}
+
+		return failures
+	}
+
+	if !kdumpMemoryRegex.MatchString(kdump.Memory) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/kdump/memory",
+			UserMessage: "The 'memory' field of the 'kdump' section must be a valid 'crashkernel' reservation, " +
+				"either a size followed by one of 'K', 'M' or 'G' (e.g. '512M') or a list of sizes by memory range (e.g. '2G-64G:256M,64G-:512M').",
+		})
+	}
+
+	if slices.ContainsFunc(os.KernelArgs.Add, isCrashKernelArg) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/kdump/memory",
+			UserMessage: "The kdump 'memory' field cannot be used along with a 'crashkernel' kernel argument.",
+		})
+	}
+
+	if kdump.SavePath != "" {
+		failures = append(failures, validateKdumpSavePath(os)...)
+	}
+
+	failures = append(failures, validatePackageResolution(&os.Packages, combustion.KdumpPackage)...)
+
+	return failures
+}
+
+func validateKdumpSavePath(os *image.OperatingSystem) []FailedValidation {
+	p := os.Kdump.SavePath
+
+	switch {
+	case !filepath.IsAbs(p):
+		return []FailedValidation{{
+			Field:       "operatingSystem/kdump/savePath",
+			UserMessage: fmt.Sprintf("The kdump 'savePath' '%s' must be an absolute path.", p),
+		}}
+	case filepath.Clean(p) != p || p == "/":
+		return []FailedValidation{{
+			Field: "operatingSystem/kdump/savePath",
+			UserMessage: fmt.Sprintf("The kdump 'savePath' '%s' must be a normalized path below the root directory, "+
+				"without trailing slashes or '.' and '..' elements.", p),
+		}}
+	case strings.ContainsAny(p, `'"|\`) || strings.ContainsFunc(p, unicode.IsSpace) || strings.ContainsFunc(p, unicode.IsControl):
+		return []FailedValidation{{
+			Field:       "operatingSystem/kdump/savePath",
+			UserMessage: fmt.Sprintf("The kdump 'savePath' %q cannot contain quotes, pipes, backslashes, whitespace or control characters.", p),
+		}}
+	}
+
+	if !os.ReadOnlyRoot.Enabled || writablePathFor(p, writableRootPaths) != "" {
+		return nil
+	}
+
+	for _, overlay := range os.ReadOnlyRoot.Overlays {
+		if writablePathFor(p, []string{overlay.Path}) != "" {
+			return nil
+		}
+	}
+
+	return []FailedValidation{{
+		Field: "operatingSystem/kdump/savePath",
+		UserMessage: fmt.Sprintf("The kdump 'savePath' '%s' will be read-only at runtime, as it is neither below a writable path "+
+			"nor covered by an overlay.", p),
+	}}
+}
+
+func isCrashKernelArg(arg string) bool {
+	key, _, _ := strings.Cut(arg, "=")
+	return key == "crashkernel"
+}
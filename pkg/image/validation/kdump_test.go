@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateKdump(t *testing.T) {
+	tests := map[string]struct {
+		Kdump                  image.Kdump
+		KernelArgs             []string
+		ReadOnlyRoot           image.ReadOnlyRoot
+		PackageList            []string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid size`: {
+			Kdump:       image.Kdump{Memory: "512M", SavePath: "/var/lib/crash"},
+			PackageList: []string{"kdump"},
+		},
+		`valid placement`: {
+			Kdump:       image.Kdump{Memory: "1G,high"},
+			PackageList: []string{"kdump"},
+		},
+		`valid ranges`: {
+			Kdump:       image.Kdump{Memory: "2G-64G:256M,64G-:512M"},
+			PackageList: []string{"kdump"},
+		},
+		`save path only`: {
+			Kdump: image.Kdump{SavePath: "/var/crash"},
+			ExpectedFailedMessages: []string{
+				"The 'memory' field is required when configuring the 'kdump' section.",
+			},
+		},
+		`invalid memory`: {
+			Kdump:       image.Kdump{Memory: "512"},
+			PackageList: []string{"kdump"},
+			ExpectedFailedMessages: []string{
+				"The 'memory' field of the 'kdump' section must be a valid 'crashkernel' reservation, " +
+					"either a size followed by one of 'K', 'M' or 'G' (e.g. '512M') or a list of sizes by memory range (e.g. '2G-64G:256M,64G-:512M').",
+			},
+		},
+		`invalid range`: {
+			Kdump:       image.Kdump{Memory: "2G:256M"},
+			PackageList: []string{"kdump"},
+			ExpectedFailedMessages: []string{
+				"The 'memory' field of the 'kdump' section must be a valid 'crashkernel' reservation, " +
+					"either a size followed by one of 'K', 'M' or 'G' (e.g. '512M') or a list of sizes by memory range (e.g. '2G-64G:256M,64G-:512M').",
+			},
+		},
+		`crashkernel kernel argument`: {
+			Kdump:       image.Kdump{Memory: "512M"},
+			KernelArgs:  []string{"quiet", "crashkernel=256M"},
+			PackageList: []string{"kdump"},
+			ExpectedFailedMessages: []string{
+				"The kdump 'memory' field cannot be used along with a 'crashkernel' kernel argument.",
+			},
+		},
+		`relative save path`: {
+			Kdump:       image.Kdump{Memory: "512M", SavePath: "var/crash"},
+			PackageList: []string{"kdump"},
+			ExpectedFailedMessages: []string{
+				"The kdump 'savePath' 'var/crash' must be an absolute path.",
+			},
+		},
+		`unnormalized save path`: {
+			Kdump:       image.Kdump{Memory: "512M", SavePath: "/var/crash/"},
+			PackageList: []string{"kdump"},
+			ExpectedFailedMessages: []string{
+				"The kdump 'savePath' '/var/crash/' must be a normalized path below the root directory, " +
+					"without trailing slashes or '.' and '..' elements.",
+			},
+		},
+		`invalid save path characters`: {
+			Kdump:       image.Kdump{Memory: "512M", SavePath: "/var/crash|dumps"},
+			PackageList: []string{"kdump"},
+			ExpectedFailedMessages: []string{
+				`The kdump 'savePath' "/var/crash|dumps" cannot contain quotes, pipes, backslashes, whitespace or control characters.`,
+			},
+		},
+		`read-only save path`: {
+			Kdump:        image.Kdump{Memory: "512M", SavePath: "/crash"},
+			ReadOnlyRoot: image.ReadOnlyRoot{Enabled: true},
+			PackageList:  []string{"kdump"},
+			ExpectedFailedMessages: []string{
+				"The kdump 'savePath' '/crash' will be read-only at runtime, as it is neither below a writable path nor covered by an overlay.",
+			},
+		},
+		`save path covered by an overlay`: {
+			Kdump: image.Kdump{Memory: "512M", SavePath: "/crash/node"},
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Enabled:  true,
+				Overlays: []image.Overlay{{Path: "/crash", Type: image.OverlayTypePersistent}},
+			},
+			PackageList: []string{"kdump"},
+		},
+		`unresolved package`: {
+			Kdump: image.Kdump{Memory: "512M"},
+			ExpectedFailedMessages: []string{
+				"The 'kdump' package is installed from the configured repositories, its resolution may fail " +
+					"without either the 'sccRegistrationCode' or the 'additionalRepos' field.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				Kdump:        test.Kdump,
+				KernelArgs:   image.KernelArgs{Add: test.KernelArgs},
+				ReadOnlyRoot: test.ReadOnlyRoot,
+				Packages:     image.Packages{PKGList: test.PackageList},
+			}
+
+			failures := validateKdump(&os)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// kernelVersionRegex matches the version and release of a package (e.g. '6.4.0-150600.23.25.1'), without an epoch
+var kernelVersionRegex = regexp.MustCompile(`^[A-Za-z0-9_.+~]+-[A-Za-z0-9_.+~]+$`)
+
+func validateKernel(def *image.Definition) []FailedValidation {
+	packages := &def.OperatingSystem.Packages
+	kernel := packages.Kernel
+	if kernel == (image.Kernel{}) {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if def.Image.ImageType != image.TypeRAW {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/packages/kernel",
+			UserMessage: fmt.Sprintf("The 'packages/kernel' section can only be used when 'imageType' is '%s'.", image.TypeRAW),
+		})
+	}
+
+	if kernel.Version == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/packages/kernel/version",
+			UserMessage: "The 'version' field is required in the 'kernel' section.",
+		})
+	} else if !kernelVersionRegex.MatchString(kernel.Version) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/packages/kernel/version",
+			UserMessage: fmt.Sprintf("The kernel 'version' field '%s' must be the version and release of the package "+
+				"(e.g. '6.4.0-150600.23.25.1').", kernel.Version),
+		})
+	}
+
+	name := kernel.Package()
+	if !strings.HasPrefix(name, "kernel-") || !packageNameRegex.MatchString(name) || strings.ContainsAny(name, "*?") {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/packages/kernel/name",
+			UserMessage: fmt.Sprintf("The kernel 'name' field '%s' must be the name of a kernel package "+
+				"(e.g. 'kernel-default').", name),
+		})
+	}
+
+	if packages.RegCode == "" && len(packages.AdditionalRepos) == 0 {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/packages/kernel",
+			UserMessage: "The pinned kernel is resolved from the configured repositories, either the " +
+				"'sccRegistrationCode' or the 'additionalRepos' field is required.",
+		})
+	}
+
+	if slices.ContainsFunc(packages.PKGList, func(p string) bool {
+		return p == name || strings.HasPrefix(p, name+"=")
+	}) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/packages/packageList",
+			UserMessage: fmt.Sprintf("The pinned kernel '%s' cannot be listed in 'packageList'.", name),
+		})
+	}
+
+	lists := []struct {
+		field    string
+		packages []string
+	}{
+		{field: "locks", packages: packages.Locks},
+		{field: "exclude", packages: packages.Exclude},
+	}
+
+	for _, list := range lists {
+		for _, p := range list.packages {
+			if matched, _ := path.Match(p, name); matched {
+				failures = append(failures, FailedValidation{
+					Field: "operatingSystem/packages/" + list.field,
+					UserMessage: fmt.Sprintf("The pinned kernel '%s' cannot be matched by the '%s' field entry '%s'.",
+						name, list.field, p),
+				})
+			}
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateKernel(t *testing.T) {
+	tests := map[string]struct {
+		ImageType              string
+		Packages               image.Packages
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {
+			ImageType: image.TypeISO,
+		},
+		`valid`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Name: "kernel-default-base", Version: "6.4.0-150600.23.25.1"},
+				RegCode: "regcode",
+				Locks:   []string{"kernel-default"},
+			},
+		},
+		`default name with additional repos`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:          image.Kernel{Version: "6.4.0-150600.23.25.1"},
+				AdditionalRepos: []image.AddRepo{{URL: "https://foo.bar"}},
+			},
+		},
+		`iso image`: {
+			ImageType: image.TypeISO,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Version: "6.4.0-150600.23.25.1"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'packages/kernel' section can only be used when 'imageType' is 'raw'.",
+			},
+		},
+		`missing version`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Name: "kernel-rt"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'version' field is required in the 'kernel' section.",
+			},
+		},
+		`invalid version`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Version: "6.4.0 150600"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The kernel 'version' field '6.4.0 150600' must be the version and release of the package (e.g. '6.4.0-150600.23.25.1').",
+			},
+		},
+		`version without release`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Version: "6.4.0"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The kernel 'version' field '6.4.0' must be the version and release of the package (e.g. '6.4.0-150600.23.25.1').",
+			},
+		},
+		`invalid name`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Name: "kernel-*", Version: "6.4.0-150600.23.25.1"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The kernel 'name' field 'kernel-*' must be the name of a kernel package (e.g. 'kernel-default').",
+			},
+		},
+		`not a kernel package`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Name: "wget2", Version: "2.1.0-150600.1.1"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The kernel 'name' field 'wget2' must be the name of a kernel package (e.g. 'kernel-default').",
+			},
+		},
+		`no repositories`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel: image.Kernel{Version: "6.4.0-150600.23.25.1"},
+			},
+			ExpectedFailedMessages: []string{
+				"The pinned kernel is resolved from the configured repositories, either the 'sccRegistrationCode' or the 'additionalRepos' field is required.",
+			},
+		},
+		`listed in package list`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Version: "6.4.0-150600.23.25.1"},
+				PKGList: []string{"wget2", "kernel-default=6.4.0-150600.23.22.1"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The pinned kernel 'kernel-default' cannot be listed in 'packageList'.",
+			},
+		},
+		`locked and excluded`: {
+			ImageType: image.TypeRAW,
+			Packages: image.Packages{
+				Kernel:  image.Kernel{Name: "kernel-rt", Version: "6.4.0-150600.10.1"},
+				Locks:   []string{"kernel-*"},
+				Exclude: []string{"kernel-rt"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The pinned kernel 'kernel-rt' cannot be matched by the 'locks' field entry 'kernel-*'.",
+				"The pinned kernel 'kernel-rt' cannot be matched by the 'exclude' field entry 'kernel-rt'.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := image.Definition{
+				Image: image.Image{
+					ImageType: test.ImageType,
+				},
+				OperatingSystem: image.OperatingSystem{
+					Packages: test.Packages,
+				},
+			}
+
+			failures := validateKernel(&def)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
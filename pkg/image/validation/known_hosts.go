@@ -0,0 +1,158 @@
+package validation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"golang.org/x/crypto/ssh"
+)
+
+func validateKnownHosts(knownHosts []image.KnownHost) []FailedValidation {
+	var failures []FailedValidation
+
+	// Tracks the hosts each key is pinned for, identical entries being ignored by ssh
+	entries := make(map[string]bool)
+
+	for _, knownHost := range knownHosts {
+		if knownHost.Host == "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/knownHosts/host",
+				UserMessage: "The 'host' field is required for each entry in the 'knownHosts' section.",
+			})
+		} else if failure := validateKnownHostName(knownHost.Host); failure != nil {
+			failures = append(failures, *failure)
+		}
+
+		if knownHost.Key == "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/knownHosts/key",
+				UserMessage: fmt.Sprintf("The 'key' field is required for the 'knownHosts' entry '%s'.", knownHost.Host),
+			})
+			continue
+		}
+
+		key, failure := parseKnownHostKey(&knownHost)
+		if failure != nil {
+			failures = append(failures, *failure)
+			continue
+		}
+
+		if key.Type() == ssh.KeyAlgoDSA {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/knownHosts/key",
+				UserMessage: fmt.Sprintf("The 'knownHosts' entry '%s' pins a %s key, which is no longer supported by OpenSSH "+
+					"and will not be used to verify the server.", knownHost.Host, ssh.KeyAlgoDSA),
+				Warning: true,
+			})
+		}
+
+		entry := strings.ToLower(knownHost.Host) + " " + string(key.Marshal())
+		if entries[entry] {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/knownHosts/key",
+				UserMessage: fmt.Sprintf("The '%s' key of the 'knownHosts' entry '%s' is listed multiple times.", key.Type(), knownHost.Host),
+			})
+		}
+		entries[entry] = true
+	}
+
+	return failures
+}
+
+// validateKnownHostName checks the host is a hostname or IP address, optionally in the '[host]:port' form
+// ssh looks up servers listening on a port other than 22 with
+func validateKnownHostName(host string) *FailedValidation {
+	name := host
+
+	if strings.HasPrefix(host, "[") {
+		bracketed, portValue, err := net.SplitHostPort(host)
+		if err != nil || !strings.HasPrefix(host, "["+bracketed+"]:") {
+			return &FailedValidation{
+				Field:       "operatingSystem/knownHosts/host",
+				UserMessage: fmt.Sprintf("The 'knownHosts' host '%s' must be in the '[host]:port' form when specifying a port.", host),
+			}
+		}
+
+		port, err := strconv.Atoi(portValue)
+		if err != nil || port < 1 || port > 65535 {
+			return &FailedValidation{
+				Field:       "operatingSystem/knownHosts/host",
+				UserMessage: fmt.Sprintf("The 'knownHosts' host '%s' has an invalid port '%s'.", host, portValue),
+			}
+		}
+
+		if port == 22 {
+			return &FailedValidation{
+				Field: "operatingSystem/knownHosts/host",
+				UserMessage: fmt.Sprintf("The 'knownHosts' host '%s' must omit the default port 22, "+
+					"as ssh looks the server up by its name alone.", host),
+			}
+		}
+
+		name = bracketed
+	}
+
+	if _, err := netip.ParseAddr(name); err == nil {
+		return nil
+	}
+
+	if len(name) > 253 || !hostnameRegex.MatchString(name) {
+		return &FailedValidation{
+			Field:       "operatingSystem/knownHosts/host",
+			UserMessage: fmt.Sprintf("The 'knownHosts' host '%s' is not a valid hostname or IP address.", host),
+		}
+	}
+
+	return nil
+}
+
+func parseKnownHostKey(knownHost *image.KnownHost) (ssh.PublicKey, *FailedValidation) {
+	fields := strings.Fields(knownHost.Key)
+	if len(fields) < 2 {
+		return nil, &FailedValidation{
+			Field: "operatingSystem/knownHosts/key",
+			UserMessage: fmt.Sprintf("The 'key' of the 'knownHosts' entry '%s' must be an OpenSSH public key "+
+				"in the '<type> <base64>' form.", knownHost.Host),
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, &FailedValidation{
+			Field:       "operatingSystem/knownHosts/key",
+			UserMessage: fmt.Sprintf("The 'key' of the 'knownHosts' entry '%s' is not base64 encoded.", knownHost.Host),
+			Error:       err,
+		}
+	}
+
+	key, err := ssh.ParsePublicKey(data)
+	if err != nil {
+		return nil, &FailedValidation{
+			Field:       "operatingSystem/knownHosts/key",
+			UserMessage: fmt.Sprintf("The 'key' of the 'knownHosts' entry '%s' could not be parsed.", knownHost.Host),
+			Error:       err,
+		}
+	}
+
+	if _, ok := key.(*ssh.Certificate); ok {
+		return nil, &FailedValidation{
+			Field:       "operatingSystem/knownHosts/key",
+			UserMessage: fmt.Sprintf("The 'key' of the 'knownHosts' entry '%s' is a certificate, only plain host keys can be pinned.", knownHost.Host),
+		}
+	}
+
+	if key.Type() != fields[0] {
+		return nil, &FailedValidation{
+			Field: "operatingSystem/knownHosts/key",
+			UserMessage: fmt.Sprintf("The 'key' of the 'knownHosts' entry '%s' is declared as '%s' but holds a '%s' key.",
+				knownHost.Host, fields[0], key.Type()),
+		}
+	}
+
+	return key, nil
+}
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"golang.org/x/crypto/ssh"
+)
+
+// dsaHostKey is generated with ssh-keygen, as DSA keys cannot be generated without the deprecated crypto/dsa
+const dsaHostKey = "ssh-dss AAAAB3NzaC1kc3MAAACBAMzvGVW5MGCet7O4PsJg44Y9Y8xthIDUyT3TaIL3Vw+BDAjIk3Ktr7As/dP07/c+csf2PfYwB4ncKDZ1xwOLsB1CWDWRSk9KgtWShElQHMgqnIm7JRb1D85r/ZHuKFiamtbNWX0r/60Tl27XWltrIpu+3/bSl8uJORZjePouM/bJAAAAFQC1dJYdcPMGjSybtAe3obVgnzE6wwAAAIEArTUnbOz8fOJGP5I43RwArWzsDoCv7dsRLauFmFNdCgzIxZdVKSvBqKGwVv8Su+hWgSrrXTgRWKe1PeYVDAWmb/57gV7eyTVsZ0q98vLyIuBy1btJBtkqwQlJDDvpCroXbIFo6CRyRrL9WclPYijM0QJMsQhVYn0bP9oHT6wUFO4AAACBAL1h92KQVdKOHK25f6JENN1X7S9zUjg86jofR4VTDUhxwDUBZvqmpvR8nI+KXFFPTtZUEzOQpGXJarJxC55Yvd7N5kz6H5+37YD46tQvN1sEex95JVGh/hWB9Z6p9tLPaAK3fCM66D6U3fUjBNfmRSiDedAsIFp0nTcsi818yzHw"
+
+func TestValidateKnownHosts(t *testing.T) {
+	ed25519PublicKey, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	ed25519SSHKey, err := ssh.NewPublicKey(ed25519PublicKey)
+	require.NoError(t, err)
+	ed25519HostKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(ed25519SSHKey)))
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecdsaSSHKey, err := ssh.NewPublicKey(&ecdsaKey.PublicKey)
+	require.NoError(t, err)
+	ecdsaHostKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(ecdsaSSHKey)))
+
+	signer, err := ssh.NewSignerFromKey(ed25519Key)
+	require.NoError(t, err)
+
+	certificate := &ssh.Certificate{Key: ecdsaSSHKey, CertType: ssh.HostCert, ValidBefore: ssh.CertTimeInfinity}
+	require.NoError(t, certificate.SignCert(rand.Reader, signer))
+	certificateHostKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(certificate)))
+
+	ed25519Blob := strings.Fields(ed25519HostKey)[1]
+
+	tests := map[string]struct {
+		KnownHosts             []image.KnownHost
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {},
+		`valid`: {
+			KnownHosts: []image.KnownHost{
+				{Host: "git.example.com", Key: ed25519HostKey},
+				{Host: "git.example.com", Key: ecdsaHostKey},
+				{Host: "[registry.example.com]:2222", Key: ed25519HostKey + " registry"},
+				{Host: "192.168.1.10", Key: ed25519HostKey},
+				{Host: "[fd00::10]:2222", Key: ed25519HostKey},
+			},
+		},
+		`missing fields`: {
+			KnownHosts: []image.KnownHost{
+				{Key: ed25519HostKey},
+				{Host: "git.example.com"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'host' field is required for each entry in the 'knownHosts' section.",
+				"The 'key' field is required for the 'knownHosts' entry 'git.example.com'.",
+			},
+		},
+		`invalid hosts`: {
+			KnownHosts: []image.KnownHost{
+				{Host: "under_score.example.com", Key: ed25519HostKey},
+				{Host: "git.example.com:2222", Key: ed25519HostKey},
+				{Host: "[git.example.com]", Key: ed25519HostKey},
+				{Host: "[git.example.com]:70000", Key: ed25519HostKey},
+				{Host: "[git.example.com]:22", Key: ed25519HostKey},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'knownHosts' host 'under_score.example.com' is not a valid hostname or IP address.",
+				"The 'knownHosts' host 'git.example.com:2222' is not a valid hostname or IP address.",
+				"The 'knownHosts' host '[git.example.com]' must be in the '[host]:port' form when specifying a port.",
+				"The 'knownHosts' host '[git.example.com]:70000' has an invalid port '70000'.",
+				"The 'knownHosts' host '[git.example.com]:22' must omit the default port 22, as ssh looks the server up by its name alone.",
+			},
+		},
+		`invalid keys`: {
+			KnownHosts: []image.KnownHost{
+				{Host: "a.example.com", Key: ed25519Blob},
+				{Host: "b.example.com", Key: "ssh-ed25519 not-base64!"},
+				{Host: "c.example.com", Key: "ssh-ed25519 bm90IGEga2V5"},
+				{Host: "d.example.com", Key: "ecdsa-sha2-nistp256 " + ed25519Blob},
+				{Host: "e.example.com", Key: certificateHostKey},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'key' of the 'knownHosts' entry 'a.example.com' must be an OpenSSH public key in the '<type> <base64>' form.",
+				"The 'key' of the 'knownHosts' entry 'b.example.com' is not base64 encoded.",
+				"The 'key' of the 'knownHosts' entry 'c.example.com' could not be parsed.",
+				"The 'key' of the 'knownHosts' entry 'd.example.com' is declared as 'ecdsa-sha2-nistp256' but holds a 'ssh-ed25519' key.",
+				"The 'key' of the 'knownHosts' entry 'e.example.com' is a certificate, only plain host keys can be pinned.",
+			},
+		},
+		`deprecated key type`: {
+			KnownHosts: []image.KnownHost{
+				{Host: "legacy.example.com", Key: dsaHostKey},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'knownHosts' entry 'legacy.example.com' pins a ssh-dss key, which is no longer supported by OpenSSH and will not be used to verify the server.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`duplicates`: {
+			KnownHosts: []image.KnownHost{
+				{Host: "git.example.com", Key: ed25519HostKey},
+				{Host: "Git.example.com", Key: ed25519HostKey + " duplicate"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'ssh-ed25519' key of the 'knownHosts' entry 'Git.example.com' is listed multiple times.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateKnownHosts(test.KnownHosts)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
 	"go.uber.org/zap"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
@@ -22,7 +23,10 @@ const (
 	ociScheme    = "oci"
 )
 
-var validNodeTypes = []string{image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent}
+var (
+	validNodeTypes             = []string{image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent}
+	validInstallGateStrategies = []string{image.InstallGateStrategyDelay, image.InstallGateStrategyCommand, image.InstallGateStrategyURL}
+)
 
 func validateKubernetes(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
@@ -30,12 +34,36 @@ func validateKubernetes(ctx *image.Context) []FailedValidation {
 	var failures []FailedValidation
 
 	if !isKubernetesDefined(&def.Kubernetes) {
+		if def.Kubernetes.Kubeconfig != (image.Kubeconfig{}) {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/kubeconfig",
+				UserMessage: "The 'kubeconfig' section can only be used when a Kubernetes version is configured.",
+			})
+		}
+
+		if def.Kubernetes.Containerd != (image.Containerd{}) {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/containerd",
+				UserMessage: "The 'containerd' section can only be used when a Kubernetes version is configured.",
+			})
+		}
+
 		return failures
 	}
 
 	failures = append(failures, validateNodes(&def.Kubernetes)...)
+	failures = append(failures, validateNodeMetadata(&def.Kubernetes)...)
 	failures = append(failures, validateManifestURLs(&def.Kubernetes)...)
+	failures = append(failures, validateManifestsDirectory(&def.Kubernetes, ctx.ImageConfigDir)...)
 	failures = append(failures, validateHelm(&def.Kubernetes, ctx.ImageConfigDir)...)
+	failures = append(failures, validateInstallGate(&def.Kubernetes.InstallGate)...)
+	failures = append(failures, validateKubeconfig(&def.Kubernetes.Kubeconfig)...)
+	failures = append(failures, validateContainerd(ctx)...)
+	failures = append(failures, validateKubernetesCompatibility(ctx)...)
+
+	if failure := validateBaseImageSupport(ctx); failure != nil {
+		failures = append(failures, *failure)
+	}
 
 	return failures
 }
@@ -55,6 +83,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 
 	if k8s.Network.APIVIP == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/network/apiVIP",
 			UserMessage: "The 'apiVIP' field is required in the 'network' section when defining entries under 'nodes'.",
 		})
 	}
@@ -66,6 +95,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	for _, node := range k8s.Nodes {
 		if node.Hostname == "" {
 			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/nodes/hostname",
 				UserMessage: "The 'hostname' field is required for entries in the 'nodes' section.",
 			})
 		}
@@ -74,6 +104,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 			options := strings.Join(validNodeTypes, ", ")
 			msg := fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", options)
 			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/nodes/type",
 				UserMessage: msg,
 			})
 		}
@@ -85,6 +116,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 			if node.Type == image.KubernetesNodeTypeAgent {
 				msg := fmt.Sprintf("The node labeled with 'initialiser' must be of type '%s'.", image.KubernetesNodeTypeServer)
 				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/initializer",
 					UserMessage: msg,
 				})
 			}
@@ -98,6 +130,7 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 		duplicateValues := strings.Join(duplicates, ", ")
 		msg := fmt.Sprintf("The 'nodes' section contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/nodes/hostname",
 			UserMessage: msg,
 		})
 	}
@@ -105,12 +138,14 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	if !slices.Contains(nodeTypes, image.KubernetesNodeTypeServer) {
 		msg := fmt.Sprintf("There must be at least one node of type '%s' defined.", image.KubernetesNodeTypeServer)
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/nodes/type",
 			UserMessage: msg,
 		})
 	}
 
 	if len(initialisers) > 1 {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/nodes/initializer",
 			UserMessage: "Only one node may be specified as the cluster initializer.",
 		})
 	}
@@ -129,6 +164,7 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 	for _, manifest := range k8s.Manifests.URLs {
 		if !strings.HasPrefix(manifest, "http") {
 			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/manifests/urls",
 				UserMessage: "Entries in 'urls' must begin with either 'http://' or 'https://'.",
 			})
 		}
@@ -136,6 +172,7 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 		if _, exists := seenManifests[manifest]; exists {
 			msg := fmt.Sprintf("The 'urls' field contains duplicate entries: %s", manifest)
 			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/manifests/urls",
 				UserMessage: msg,
 			})
 		}
@@ -146,6 +183,195 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 	return failures
 }
 
+func validateManifestsDirectory(k8s *image.Kubernetes, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	dir := k8s.Manifests.Directory
+	if dir == "" {
+		return failures
+	}
+
+	if !filepath.IsLocal(dir) {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/manifests/directory",
+			UserMessage: fmt.Sprintf("The manifests 'directory' field '%s' must be a relative path within the image configuration directory.", dir),
+		})
+
+		return failures
+	}
+
+	manifestPaths, err := registry.ManifestPaths(filepath.Join(imageConfigDir, dir))
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/manifests/directory",
+			UserMessage: fmt.Sprintf("The manifests directory '%s' could not be read.", dir),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	if len(manifestPaths) == 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/manifests/directory",
+			UserMessage: fmt.Sprintf("The manifests directory '%s' does not contain any '.yaml' or '.yml' files.", dir),
+		})
+	}
+
+	for _, manifestPath := range manifestPaths {
+		if _, err = registry.ManifestKinds(manifestPath); err != nil {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/manifests/directory",
+				UserMessage: fmt.Sprintf("The manifest '%s' is not a valid Kubernetes manifest.", filepath.Base(manifestPath)),
+				Error:       err,
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateInstallGate(gate *image.InstallGate) []FailedValidation {
+	var failures []FailedValidation
+
+	if gate.Delay < 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/installGate/delay",
+			UserMessage: "The 'delay' field in the 'installGate' section cannot be negative.",
+		})
+	}
+
+	if gate.Timeout < 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/installGate/timeout",
+			UserMessage: "The 'timeout' field in the 'installGate' section cannot be negative.",
+		})
+	}
+
+	switch gate.Strategy {
+	case "":
+		if gate.Delay != 0 || gate.Command != "" || gate.URL != "" || gate.Timeout != 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/installGate/strategy",
+				UserMessage: "The 'strategy' field is required when configuring the 'installGate' section.",
+			})
+		}
+	case image.InstallGateStrategyDelay:
+		if gate.Delay == 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/installGate/delay",
+				UserMessage: "The 'delay' field is required when using the 'delay' install gate strategy.",
+			})
+		}
+	case image.InstallGateStrategyCommand:
+		if gate.Command == "" {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/installGate/command",
+				UserMessage: "The 'command' field is required when using the 'command' install gate strategy.",
+			})
+		}
+	case image.InstallGateStrategyURL:
+		if gate.URL == "" {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/installGate/url",
+				UserMessage: "The 'url' field is required when using the 'url' install gate strategy.",
+			})
+		} else if parsedURL, err := url.ParseRequestURI(gate.URL); err != nil || (parsedURL.Scheme != httpScheme && parsedURL.Scheme != httpsScheme) {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/installGate/url",
+				UserMessage: "The 'url' field in the 'installGate' section must begin with either 'http://' or 'https://'.",
+				Error:       err,
+			})
+		} else if strings.ContainsAny(gate.URL, installGateURLUnsafeChars) {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/installGate/url",
+				UserMessage: "The 'url' field in the 'installGate' section cannot contain whitespace, quotes or any of the characters: `$\\",
+			})
+		}
+	default:
+		options := strings.Join(validInstallGateStrategies, ", ")
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/installGate/strategy",
+			UserMessage: fmt.Sprintf("The 'strategy' field in the 'installGate' section must be one of: %s", options),
+		})
+	}
+
+	if gate.Command != "" && gate.Strategy != image.InstallGateStrategyCommand {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/installGate/command",
+			UserMessage: "The 'command' field can only be used with the 'command' install gate strategy.",
+		})
+	}
+
+	if gate.URL != "" && gate.Strategy != image.InstallGateStrategyURL {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/installGate/url",
+			UserMessage: "The 'url' field can only be used with the 'url' install gate strategy.",
+		})
+	}
+
+	return failures
+}
+
+// kubeconfigUnsafeChars would break the generated systemd unit if present in the kubeconfig path or server address
+const kubeconfigUnsafeChars = " \t\n\"'`$%|\\"
+
+// installGateURLUnsafeChars could be interpreted by the shell running the readiness check if present in the install
+// gate URL, although it is single-quoted in the check
+const installGateURLUnsafeChars = " \t\n\"'`$\\"
+
+func validateKubeconfig(kubeconfig *image.Kubeconfig) []FailedValidation {
+	var failures []FailedValidation
+
+	if kubeconfig.Path == "" {
+		if kubeconfig.Server != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/kubeconfig/path",
+				UserMessage: "The 'path' field is required when configuring the 'kubeconfig' section.",
+			})
+		}
+
+		return failures
+	}
+
+	switch {
+	case !filepath.IsAbs(kubeconfig.Path):
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/kubeconfig/path",
+			UserMessage: "The 'path' field in the 'kubeconfig' section must be an absolute path.",
+		})
+	case strings.HasSuffix(kubeconfig.Path, "/"):
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/kubeconfig/path",
+			UserMessage: "The 'path' field in the 'kubeconfig' section must be a file path, not a directory.",
+		})
+	case strings.ContainsAny(kubeconfig.Path, kubeconfigUnsafeChars):
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/kubeconfig/path",
+			UserMessage: "The 'path' field in the 'kubeconfig' section cannot contain whitespace, quotes or any of the characters: `$%|\\",
+		})
+	}
+
+	if kubeconfig.Server == "" {
+		return failures
+	}
+
+	if parsedURL, err := url.ParseRequestURI(kubeconfig.Server); err != nil || parsedURL.Scheme != httpsScheme || parsedURL.Host == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/kubeconfig/server",
+			UserMessage: "The 'server' field in the 'kubeconfig' section must be a URL beginning with 'https://'.",
+			Error:       err,
+		})
+	} else if strings.ContainsAny(kubeconfig.Server, kubeconfigUnsafeChars) {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/kubeconfig/server",
+			UserMessage: "The 'server' field in the 'kubeconfig' section cannot contain whitespace, quotes or any of the characters: `$%|\\",
+		})
+	}
+
+	return failures
+}
+
 func validateHelm(k8s *image.Kubernetes, imageConfigDir string) []FailedValidation {
 	var failures []FailedValidation
 
@@ -155,6 +381,7 @@ func validateHelm(k8s *image.Kubernetes, imageConfigDir string) []FailedValidati
 
 	if len(k8s.Helm.Repositories) == 0 {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories",
 			UserMessage: "Helm charts defined with no Helm repositories defined.",
 		})
 
@@ -166,11 +393,7 @@ func validateHelm(k8s *image.Kubernetes, imageConfigDir string) []FailedValidati
 		helmRepositoryNames = append(helmRepositoryNames, repo.Name)
 	}
 
-	if failure := validateHelmChartDuplicates(k8s.Helm.Charts); failure != "" {
-		failures = append(failures, FailedValidation{
-			UserMessage: failure,
-		})
-	}
+	failures = append(failures, validateHelmDuplicates(k8s.Helm)...)
 
 	seenHelmRepos := make(map[string]bool)
 	for _, chart := range k8s.Helm.Charts {
@@ -193,34 +416,40 @@ func validateChart(chart *image.HelmChart, repositoryNames []string, imageConfig
 
 	if chart.Name == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/charts/name",
 			UserMessage: "Helm chart 'name' field must be defined.",
 		})
 	}
 
 	if chart.RepositoryName == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/charts/repositoryName",
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' field for %q must be defined.", chart.Name),
 		})
 	} else if !slices.Contains(repositoryNames, chart.RepositoryName) {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/charts/repositoryName",
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' %q for Helm chart %q does not match the name of any defined repository.", chart.RepositoryName, chart.Name),
 		})
 	}
 
 	if chart.Version == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/charts/version",
 			UserMessage: fmt.Sprintf("Helm chart 'version' field for %q field must be defined.", chart.Name),
 		})
 	}
 
 	if chart.CreateNamespace && chart.TargetNamespace == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/charts/createNamespace",
 			UserMessage: fmt.Sprintf("Helm chart 'createNamespace' field for %q cannot be true without 'targetNamespace' being defined.", chart.Name),
 		})
 	}
 
 	if failure := validateHelmChartValues(chart.Name, chart.ValuesFile, imageConfigDir); failure != "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/charts/valuesFile",
 			UserMessage: failure,
 		})
 	}
@@ -235,6 +464,7 @@ func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, ima
 	if err != nil {
 		zap.S().Errorf("Helm repository URL '%s' could not be parsed: %s", repo.URL, err)
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/url",
 			UserMessage: fmt.Sprintf("Helm repository URL '%s' could not be parsed.", repo.URL),
 		})
 
@@ -248,6 +478,7 @@ func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, ima
 
 	if failure := validateHelmRepoCert(repo.Name, repo.CAFile, imageConfigDir); failure != "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/caFile",
 			UserMessage: failure,
 		})
 	}
@@ -260,10 +491,12 @@ func validateHelmRepoName(repo *image.HelmRepository, seenHelmRepos map[string]b
 
 	if repo.Name == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/name",
 			UserMessage: "Helm repository 'name' field must be defined.",
 		})
 	} else if !seenHelmRepos[repo.Name] {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/name",
 			UserMessage: fmt.Sprintf("Helm repository 'name' field for %q must match the 'repositoryName' field in at least one defined Helm chart.", repo.Name),
 		})
 	}
@@ -276,10 +509,12 @@ func validateHelmRepoURL(parsedURL *url.URL, repo *image.HelmRepository) []Faile
 
 	if repo.URL == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/url",
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must be defined.", repo.Name),
 		})
 	} else if parsedURL.Scheme != httpScheme && parsedURL.Scheme != httpsScheme && parsedURL.Scheme != ociScheme {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/url",
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must begin with either 'oci://', 'http://', or 'https://'.", repo.Name),
 		})
 	}
@@ -292,12 +527,14 @@ func validateHelmRepoAuth(repo *image.HelmRepository) []FailedValidation {
 
 	if repo.Authentication.Username != "" && repo.Authentication.Password == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/authentication/password",
 			UserMessage: fmt.Sprintf("Helm repository 'password' field not defined for %q.", repo.Name),
 		})
 	}
 
 	if repo.Authentication.Username == "" && repo.Authentication.Password != "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/authentication/username",
 			UserMessage: fmt.Sprintf("Helm repository 'username' field not defined for %q.", repo.Name),
 		})
 	}
@@ -310,42 +547,49 @@ func validateHelmRepoArgs(parsedURL *url.URL, repo *image.HelmRepository) []Fail
 
 	if repo.SkipTLSVerify && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/skipTLSVerify",
 			UserMessage: fmt.Sprintf("Helm repository 'plainHTTP' and 'skipTLSVerify' fields for %q cannot both be true.", repo.Name),
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && !repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/plainHTTP",
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'plainHTTP' field is false.", repo.Name),
 		})
 	}
 
 	if parsedURL.Scheme == httpsScheme && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/plainHTTP",
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'https://' but 'plainHTTP' field is true.", repo.Name),
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.SkipTLSVerify {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/skipTLSVerify",
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'skipTLSVerify' field is true.", repo.Name),
 		})
 	}
 
 	if repo.SkipTLSVerify && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/caFile",
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'skipTLSVerify' is true.", repo.Name),
 		})
 	}
 
 	if repo.PlainHTTP && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/caFile",
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'plainHTTP' is true.", repo.Name),
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/caFile",
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'caFile' field is defined.", repo.Name),
 		})
 	}
@@ -401,16 +645,78 @@ func validateHelmChartValues(chartName, valuesFile string, imageConfigDir string
 	return ""
 }
 
-func validateHelmChartDuplicates(charts []image.HelmChart) string {
-	seenHelmCharts := make(map[string]bool)
+// validateHelmDuplicates reports charts and repositories sharing a name along with the location of each
+// definition. Charts are installed as releases named after the chart, so such charts overwrite each other.
+func validateHelmDuplicates(helm image.Helm) []FailedValidation {
+	var failures []FailedValidation
+
+	var chartNames []string
+	for _, chart := range helm.Charts {
+		chartNames = append(chartNames, chart.Name)
+	}
+
+	for _, duplicate := range findDuplicateLocations(chartNames, "kubernetes/helm/charts") {
+		failures = append(failures, FailedValidation{
+			Field: "kubernetes/helm/charts/name",
+			UserMessage: fmt.Sprintf("Helm chart %q is defined multiple times, at %s. Charts with the same name overwrite each other when installed.",
+				duplicate.name, duplicate.locations),
+		})
+	}
+
+	var repositoryNames []string
+	for _, repo := range helm.Repositories {
+		repositoryNames = append(repositoryNames, repo.Name)
+	}
+
+	for _, duplicate := range findDuplicateLocations(repositoryNames, "kubernetes/helm/repositories") {
+		failures = append(failures, FailedValidation{
+			Field:       "kubernetes/helm/repositories/name",
+			UserMessage: fmt.Sprintf("Helm repository %q is defined multiple times, at %s.", duplicate.name, duplicate.locations),
+		})
+	}
+
+	return failures
+}
+
+type duplicateName struct {
+	name      string
+	locations string
+}
+
+// findDuplicateLocations lists each non-empty name occurring more than once, in order of first occurrence,
+// along with its locations in the given section, e.g. 'kubernetes/helm/charts[0]' and 'kubernetes/helm/charts[2]'.
+func findDuplicateLocations(names []string, section string) []duplicateName {
+	var order []string
+	indices := make(map[string][]int)
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+
+		if _, seen := indices[name]; !seen {
+			order = append(order, name)
+		}
+		indices[name] = append(indices[name], i)
+	}
 
-	for _, chart := range charts {
-		if _, exists := seenHelmCharts[chart.Name]; exists {
-			return fmt.Sprintf("The 'helmCharts' field contains duplicate entries: %s", chart.Name)
+	var duplicates []duplicateName
+	for _, name := range order {
+		positions := indices[name]
+		if len(positions) < 2 {
+			continue
 		}
 
-		seenHelmCharts[chart.Name] = true
+		var locations []string
+		for _, position := range positions {
+			locations = append(locations, fmt.Sprintf("'%s[%d]'", section, position))
+		}
+
+		last := len(locations) - 1
+		duplicates = append(duplicates, duplicateName{
+			name:      name,
+			locations: strings.Join(locations[:last], ", ") + " and " + locations[last],
+		})
 	}
 
-	return ""
+	return duplicates
 }
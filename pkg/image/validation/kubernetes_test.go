@@ -2,10 +2,13 @@ package validation
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -100,6 +103,26 @@ func TestValidateKubernetes(t *testing.T) {
 				"Helm chart 'repositoryName' \"another-apache-repo\" for Helm chart \"\" does not match the name of any defined repository.",
 			},
 		},
+		`kubeconfig without version`: {
+			K8s: image.Kubernetes{
+				Kubeconfig: image.Kubeconfig{
+					Path: "/root/.kube/config",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'kubeconfig' section can only be used when a Kubernetes version is configured.",
+			},
+		},
+		`containerd without version`: {
+			K8s: image.Kubernetes{
+				Containerd: image.Containerd{
+					ConfigFile: "containerd.toml",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'containerd' section can only be used when a Kubernetes version is configured.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -413,6 +436,317 @@ func TestValidateManifestURLs(t *testing.T) {
 	}
 }
 
+func TestValidateManifestsDirectory(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-manifests-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	validDir := filepath.Join(imageConfigDir, "valid")
+	require.NoError(t, os.Mkdir(validDir, os.ModePerm))
+	validManifest := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: foo\n"
+	require.NoError(t, os.WriteFile(filepath.Join(validDir, "01-namespace.yaml"), []byte(validManifest), 0o600))
+
+	invalidDir := filepath.Join(imageConfigDir, "invalid")
+	require.NoError(t, os.Mkdir(invalidDir, os.ModePerm))
+	invalidManifest := "metadata:\n  name: foo\n"
+	require.NoError(t, os.WriteFile(filepath.Join(invalidDir, "01-invalid.yaml"), []byte(invalidManifest), 0o600))
+
+	emptyDir := filepath.Join(imageConfigDir, "empty")
+	require.NoError(t, os.Mkdir(emptyDir, os.ModePerm))
+
+	tests := map[string]struct {
+		Directory              string
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			Directory: "valid",
+		},
+		`outside config dir`: {
+			Directory: "../valid",
+			ExpectedFailedMessages: []string{
+				"The manifests 'directory' field '../valid' must be a relative path within the image configuration directory.",
+			},
+		},
+		`missing`: {
+			Directory: "missing",
+			ExpectedFailedMessages: []string{
+				"The manifests directory 'missing' could not be read.",
+			},
+		},
+		`empty`: {
+			Directory: "empty",
+			ExpectedFailedMessages: []string{
+				"The manifests directory 'empty' does not contain any '.yaml' or '.yml' files.",
+			},
+		},
+		`invalid manifest`: {
+			Directory: "invalid",
+			ExpectedFailedMessages: []string{
+				"The manifest '01-invalid.yaml' is not a valid Kubernetes manifest.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := image.Kubernetes{
+				Manifests: image.Manifests{
+					Directory: test.Directory,
+				},
+			}
+			failures := validateManifestsDirectory(&k, imageConfigDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateInstallGate(t *testing.T) {
+	tests := map[string]struct {
+		Gate                   image.InstallGate
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {
+			Gate: image.InstallGate{},
+		},
+		`valid delay`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyDelay,
+				Delay:    60,
+			},
+		},
+		`valid command`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyCommand,
+				Command:  "ping -c 1 registry.suse.com",
+				Delay:    10,
+				Timeout:  300,
+			},
+		},
+		`valid url`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				URL:      "https://registry.suse.com",
+			},
+		},
+		`missing strategy`: {
+			Gate: image.InstallGate{
+				Delay: 60,
+			},
+			ExpectedFailedMessages: []string{
+				"The 'strategy' field is required when configuring the 'installGate' section.",
+			},
+		},
+		`invalid strategy`: {
+			Gate: image.InstallGate{
+				Strategy: "wait",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'strategy' field in the 'installGate' section must be one of: delay, command, url",
+			},
+		},
+		`delay missing`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyDelay,
+			},
+			ExpectedFailedMessages: []string{
+				"The 'delay' field is required when using the 'delay' install gate strategy.",
+			},
+		},
+		`command missing`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyCommand,
+				URL:      "https://registry.suse.com",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'command' field is required when using the 'command' install gate strategy.",
+				"The 'url' field can only be used with the 'url' install gate strategy.",
+			},
+		},
+		`url missing`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				Command:  "true",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'url' field is required when using the 'url' install gate strategy.",
+				"The 'command' field can only be used with the 'command' install gate strategy.",
+			},
+		},
+		`invalid url`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				URL:      "registry.suse.com",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'url' field in the 'installGate' section must begin with either 'http://' or 'https://'.",
+			},
+		},
+		`url with query and percent encoding`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				URL:      "https://registry.suse.com/ready?node=a%20b&wait=1",
+			},
+		},
+		`url with shell metacharacters`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				URL:      "https://registry.suse.com/$(rm -rf /)",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'url' field in the 'installGate' section cannot contain whitespace, quotes or any of the characters: `$\\",
+			},
+		},
+		`url with quotes and backticks`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyURL,
+				URL:      "https://registry.suse.com/?q=\"`reboot`",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'url' field in the 'installGate' section cannot contain whitespace, quotes or any of the characters: `$\\",
+			},
+		},
+		`negative values`: {
+			Gate: image.InstallGate{
+				Strategy: image.InstallGateStrategyCommand,
+				Command:  "true",
+				Delay:    -1,
+				Timeout:  -1,
+			},
+			ExpectedFailedMessages: []string{
+				"The 'delay' field in the 'installGate' section cannot be negative.",
+				"The 'timeout' field in the 'installGate' section cannot be negative.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gate := test.Gate
+			failures := validateInstallGate(&gate)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateKubeconfig(t *testing.T) {
+	tests := map[string]struct {
+		Kubeconfig             image.Kubeconfig
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {
+			Kubeconfig: image.Kubeconfig{},
+		},
+		`valid path`: {
+			Kubeconfig: image.Kubeconfig{
+				Path: "/root/.kube/config",
+			},
+		},
+		`valid path and server`: {
+			Kubeconfig: image.Kubeconfig{
+				Path:   "/opt/cluster/kubeconfig.yaml",
+				Server: "https://api.cluster.local:6443",
+			},
+		},
+		`missing path`: {
+			Kubeconfig: image.Kubeconfig{
+				Server: "https://api.cluster.local:6443",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field is required when configuring the 'kubeconfig' section.",
+			},
+		},
+		`relative path`: {
+			Kubeconfig: image.Kubeconfig{
+				Path: ".kube/config",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field in the 'kubeconfig' section must be an absolute path.",
+			},
+		},
+		`directory path`: {
+			Kubeconfig: image.Kubeconfig{
+				Path: "/root/.kube/",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field in the 'kubeconfig' section must be a file path, not a directory.",
+			},
+		},
+		`unsafe path`: {
+			Kubeconfig: image.Kubeconfig{
+				Path: "/root/my kubeconfig",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field in the 'kubeconfig' section cannot contain whitespace, quotes or any of the characters: `$%|\\",
+			},
+		},
+		`invalid server`: {
+			Kubeconfig: image.Kubeconfig{
+				Path:   "/root/.kube/config",
+				Server: "api.cluster.local:6443",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'server' field in the 'kubeconfig' section must be a URL beginning with 'https://'.",
+			},
+		},
+		`plain http server`: {
+			Kubeconfig: image.Kubeconfig{
+				Path:   "/root/.kube/config",
+				Server: "http://api.cluster.local:6443",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'server' field in the 'kubeconfig' section must be a URL beginning with 'https://'.",
+			},
+		},
+		`unsafe server`: {
+			Kubeconfig: image.Kubeconfig{
+				Path:   "/root/.kube/config",
+				Server: "https://api.cluster.local:6443/|rm",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'server' field in the 'kubeconfig' section cannot contain whitespace, quotes or any of the characters: `$%|\\",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeconfig := test.Kubeconfig
+			failures := validateKubeconfig(&kubeconfig)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
 func TestValidateHelmCharts(t *testing.T) {
 	tests := map[string]struct {
 		K8s                    image.Kubernetes
@@ -605,7 +939,51 @@ func TestValidateHelmCharts(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'helmCharts' field contains duplicate entries: apache",
+				"Helm chart \"apache\" is defined multiple times, at 'kubernetes/helm/charts[0]' and 'kubernetes/helm/charts[1]'. " +
+					"Charts with the same name overwrite each other when installed.",
+			},
+		},
+		`helm chart and repository duplicate names`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+						},
+						{
+							Name:           "metallb",
+							RepositoryName: "apache-repo",
+							Version:        "0.14.3",
+						},
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.1",
+						},
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.2",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
+						{
+							Name: "apache-repo",
+							URL:  "https://charts.bitnami.com/bitnami",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart \"apache\" is defined multiple times, at 'kubernetes/helm/charts[0]', 'kubernetes/helm/charts[2]' and 'kubernetes/helm/charts[3]'. " +
+					"Charts with the same name overwrite each other when installed.",
+				"Helm repository \"apache-repo\" is defined multiple times, at 'kubernetes/helm/repositories[0]' and 'kubernetes/helm/repositories[1]'.",
 			},
 		},
 		`helm chart invalid values file`: {
@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// supportedLocales are the UTF-8 locales provided by glibc on SUSE based images, without their codeset.
+var supportedLocales = []string{
+	"aa_DJ", "aa_ER", "aa_ET", "af_ZA", "am_ET", "an_ES", "ar_AE", "ar_BH", "ar_DZ", "ar_EG", "ar_IN", "ar_IQ",
+	"ar_JO", "ar_KW", "ar_LB", "ar_LY", "ar_MA", "ar_OM", "ar_QA", "ar_SA", "ar_SD", "ar_SY", "ar_TN", "ar_YE",
+	"as_IN", "ast_ES", "az_AZ", "be_BY", "bg_BG", "bn_BD", "bn_IN", "bo_CN", "bo_IN", "br_FR", "bs_BA", "ca_AD",
+	"ca_ES", "ca_FR", "ca_IT", "cs_CZ", "cy_GB", "da_DK", "de_AT", "de_BE", "de_CH", "de_DE", "de_IT", "de_LI",
+	"de_LU", "el_CY", "el_GR", "en_AG", "en_AU", "en_BW", "en_CA", "en_DK", "en_GB", "en_HK", "en_IE", "en_IL",
+	"en_IN", "en_NG", "en_NZ", "en_PH", "en_SC", "en_SG", "en_US", "en_ZA", "en_ZM", "en_ZW", "es_AR", "es_BO",
+	"es_CL", "es_CO", "es_CR", "es_CU", "es_DO", "es_EC", "es_ES", "es_GT", "es_HN", "es_MX", "es_NI", "es_PA",
+	"es_PE", "es_PR", "es_PY", "es_SV", "es_US", "es_UY", "es_VE", "et_EE", "eu_ES", "fa_IR", "fi_FI", "fil_PH",
+	"fo_FO", "fr_BE", "fr_CA", "fr_CH", "fr_FR", "fr_LU", "fy_NL", "ga_IE", "gd_GB", "gl_ES", "gu_IN", "he_IL",
+	"hi_IN", "hr_HR", "hu_HU", "hy_AM", "id_ID", "is_IS", "it_CH", "it_IT", "ja_JP", "ka_GE", "kk_KZ", "km_KH",
+	"kn_IN", "ko_KR", "ku_TR", "ky_KG", "lb_LU", "lt_LT", "lv_LV", "mk_MK", "ml_IN", "mn_MN", "mr_IN", "ms_MY",
+	"mt_MT", "my_MM", "nb_NO", "ne_NP", "nl_AW", "nl_BE", "nl_NL", "nn_NO", "oc_FR", "or_IN", "pa_IN", "pa_PK",
+	"pl_PL", "pt_BR", "pt_PT", "ro_RO", "ru_RU", "ru_UA", "si_LK", "sk_SK", "sl_SI", "so_SO", "sq_AL", "sr_ME",
+	"sr_RS", "sv_FI", "sv_SE", "sw_KE", "ta_IN", "ta_LK", "te_IN", "tg_TJ", "th_TH", "tk_TM", "tr_CY", "tr_TR",
+	"uk_UA", "ur_IN", "ur_PK", "uz_UZ", "vi_VN", "wa_BE", "xh_ZA", "yi_US", "zh_CN", "zh_HK", "zh_SG", "zh_TW",
+	"zu_ZA",
+}
+
+func validateLocales(locales *image.Locales) []FailedValidation {
+	var failures []FailedValidation
+
+	seen := make(map[string]bool)
+	for _, locale := range locales.Keep {
+		if failure := validateLocale("operatingSystem/locales/keep", locale); failure != nil {
+			failures = append(failures, *failure)
+			continue
+		}
+
+		name := combustion.LocaleName(locale)
+		if seen[name] {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/locales/keep",
+				UserMessage: fmt.Sprintf("Locale '%s' is listed in 'keep' more than once.", name),
+			})
+		}
+		seen[name] = true
+	}
+
+	if locales.Default == "" {
+		return failures
+	}
+
+	if failure := validateLocale("operatingSystem/locales/default", locales.Default); failure != nil {
+		failures = append(failures, *failure)
+	} else if len(locales.Keep) > 0 && !seen[combustion.LocaleName(locales.Default)] {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/locales/default",
+			UserMessage: fmt.Sprintf("The default locale '%s' must be listed in 'keep'.", locales.Default),
+		})
+	}
+
+	return failures
+}
+
+func validateLocale(field, locale string) *FailedValidation {
+	name, codeset, found := strings.Cut(locale, ".")
+	if found && !strings.EqualFold(codeset, "UTF-8") && !strings.EqualFold(codeset, "utf8") {
+		return &FailedValidation{
+			Field:       field,
+			UserMessage: fmt.Sprintf("Locale '%s' is invalid, only UTF-8 locales are supported.", locale),
+		}
+	}
+
+	if !slices.Contains(supportedLocales, name) {
+		return &FailedValidation{
+			Field:       field,
+			UserMessage: fmt.Sprintf("Locale '%s' is not supported. Locales are given as a language and territory, e.g. en_US.UTF-8.", locale),
+		}
+	}
+
+	return nil
+}
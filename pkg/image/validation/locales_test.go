@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateLocales(t *testing.T) {
+	tests := map[string]struct {
+		Locales                image.Locales
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {},
+		`valid`: {
+			Locales: image.Locales{
+				Keep:    []string{"en_US.UTF-8", "de_DE", "fr_FR.utf8"},
+				Default: "de_DE.UTF-8",
+			},
+		},
+		`default only`: {
+			Locales: image.Locales{
+				Default: "en_GB.UTF-8",
+			},
+		},
+		`unsupported`: {
+			Locales: image.Locales{
+				Keep:    []string{"en_US.ISO-8859-1", "english", "xx_YY.UTF-8"},
+				Default: "en",
+			},
+			ExpectedFailedMessages: []string{
+				"Locale 'en_US.ISO-8859-1' is invalid, only UTF-8 locales are supported.",
+				"Locale 'english' is not supported. Locales are given as a language and territory, e.g. en_US.UTF-8.",
+				"Locale 'xx_YY.UTF-8' is not supported. Locales are given as a language and territory, e.g. en_US.UTF-8.",
+				"Locale 'en' is not supported. Locales are given as a language and territory, e.g. en_US.UTF-8.",
+			},
+		},
+		`duplicates`: {
+			Locales: image.Locales{
+				Keep: []string{"en_US.UTF-8", "en_US"},
+			},
+			ExpectedFailedMessages: []string{
+				"Locale 'en_US' is listed in 'keep' more than once.",
+			},
+		},
+		`default not kept`: {
+			Locales: image.Locales{
+				Keep:    []string{"en_US.UTF-8"},
+				Default: "de_DE.UTF-8",
+			},
+			ExpectedFailedMessages: []string{
+				"The default locale 'de_DE.UTF-8' must be listed in 'keep'.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateLocales(&test.Locales)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+var (
+	logRotationFrequencies = []string{image.LogRotationDaily, image.LogRotationWeekly, image.LogRotationMonthly, image.LogRotationYearly}
+
+	// logRotationNameRegex restricts names to characters usable in the names of the configuration files
+	logRotationNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+	// logRotationSizeRegex matches the sizes accepted by logrotate, given in bytes or with a k, M or G suffix
+	logRotationSizeRegex = regexp.MustCompile(`^[1-9][0-9]*[kMG]?$`)
+)
+
+func validateLogrotate(rotations []image.LogRotation) []FailedValidation {
+	var failures []FailedValidation
+
+	seenNames := make(map[string]bool)
+	// logrotate skips every configuration listing a file which is already rotated by another one
+	seenPaths := make(map[string]string)
+
+	for _, rotation := range rotations {
+		switch {
+		case rotation.Name == "":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/logrotate/name",
+				UserMessage: "The 'name' field is required for each entry in the 'logrotate' section.",
+			})
+		case !logRotationNameRegex.MatchString(rotation.Name):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/logrotate/name",
+				UserMessage: fmt.Sprintf("Logrotate name '%s' may only contain letters, digits, '-' and '_', and must start with a letter or digit.", rotation.Name),
+			})
+		case seenNames[rotation.Name]:
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/logrotate/name",
+				UserMessage: fmt.Sprintf("Logrotate name '%s' is defined more than once.", rotation.Name),
+			})
+		}
+		seenNames[rotation.Name] = true
+
+		if len(rotation.Paths) == 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/logrotate/paths",
+				UserMessage: fmt.Sprintf("At least one entry in 'paths' is required for logrotate '%s'.", rotation.Name),
+			})
+		}
+
+		for _, p := range rotation.Paths {
+			if failure := validateLogRotationPath(rotation.Name, p); failure != nil {
+				failures = append(failures, *failure)
+				continue
+			}
+
+			if other, ok := seenPaths[p]; ok {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/logrotate/paths",
+					UserMessage: fmt.Sprintf("Path '%s' of logrotate '%s' is already rotated by logrotate '%s'.", p, rotation.Name, other),
+				})
+				continue
+			}
+			seenPaths[p] = rotation.Name
+		}
+
+		if rotation.Frequency != "" && !slices.Contains(logRotationFrequencies, rotation.Frequency) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/logrotate/frequency",
+				UserMessage: fmt.Sprintf("The 'frequency' of logrotate '%s' must be one of: %s.", rotation.Name, strings.Join(logRotationFrequencies, ", ")),
+			})
+		}
+
+		if rotation.Rotate < 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/logrotate/rotate",
+				UserMessage: fmt.Sprintf("The 'rotate' field of logrotate '%s' cannot be negative.", rotation.Name),
+			})
+		}
+
+		if rotation.MaxSize != "" && !logRotationSizeRegex.MatchString(rotation.MaxSize) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/logrotate/maxSize",
+				UserMessage: fmt.Sprintf("The 'maxSize' of logrotate '%s' must be a positive integer optionally followed by one of 'k', 'M' or 'G' (e.g. '100M').",
+					rotation.Name),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateLogRotationPath(name, p string) *FailedValidation {
+	var message string
+
+	switch {
+	case !filepath.IsAbs(p):
+		message = fmt.Sprintf("Path '%s' of logrotate '%s' must be absolute.", p, name)
+	case filepath.Clean(p) != p || p == "/":
+		message = fmt.Sprintf("Path '%s' of logrotate '%s' must be a normalized path to a file, without trailing slashes or '.' and '..' elements.", p, name)
+	case strings.ContainsAny(p, "\"'{}#") || strings.ContainsFunc(p, unicode.IsSpace) || strings.ContainsFunc(p, unicode.IsControl):
+		message = fmt.Sprintf("Path %q of logrotate '%s' cannot contain quotes, braces, '#', whitespace or control characters.", p, name)
+	default:
+		if _, err := filepath.Match(p, ""); err != nil {
+			message = fmt.Sprintf("Path '%s' of logrotate '%s' is not a valid glob pattern.", p, name)
+		}
+	}
+
+	if message == "" {
+		return nil
+	}
+
+	return &FailedValidation{Field: "operatingSystem/logrotate/paths", UserMessage: message}
+}
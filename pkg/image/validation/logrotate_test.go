@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateLogrotate(t *testing.T) {
+	tests := map[string]struct {
+		Logrotate              []image.LogRotation
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {},
+		`valid`: {
+			Logrotate: []image.LogRotation{
+				{
+					Name:      "workload",
+					Paths:     []string{"/var/log/workload/*.log", "/var/lib/workload/[a-z]*.log"},
+					Frequency: image.LogRotationDaily,
+					Rotate:    7,
+					MaxSize:   "100M",
+					Compress:  true,
+				},
+				{
+					Name:    "agent",
+					Paths:   []string{"/var/log/agent.log"},
+					MaxSize: "512k",
+				},
+			},
+		},
+		`invalid names`: {
+			Logrotate: []image.LogRotation{
+				{Paths: []string{"/var/log/a.log"}},
+				{Name: "-agent", Paths: []string{"/var/log/b.log"}},
+				{Name: "agent", Paths: []string{"/var/log/c.log"}},
+				{Name: "agent", Paths: []string{"/var/log/d.log"}},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'name' field is required for each entry in the 'logrotate' section.",
+				"Logrotate name '-agent' may only contain letters, digits, '-' and '_', and must start with a letter or digit.",
+				"Logrotate name 'agent' is defined more than once.",
+			},
+		},
+		`invalid paths`: {
+			Logrotate: []image.LogRotation{
+				{Name: "empty"},
+				{
+					Name: "workload",
+					Paths: []string{
+						"var/log/workload.log",
+						"/var/log/workload/",
+						"/var/log/my app.log",
+						"/var/log/{a,b}.log",
+						"/var/log/[a.log",
+						"/var/log/shared.log",
+					},
+				},
+				{Name: "agent", Paths: []string{"/var/log/shared.log"}},
+			},
+			ExpectedFailedMessages: []string{
+				"At least one entry in 'paths' is required for logrotate 'empty'.",
+				"Path 'var/log/workload.log' of logrotate 'workload' must be absolute.",
+				"Path '/var/log/workload/' of logrotate 'workload' must be a normalized path to a file, without trailing slashes or '.' and '..' elements.",
+				`Path "/var/log/my app.log" of logrotate 'workload' cannot contain quotes, braces, '#', whitespace or control characters.`,
+				`Path "/var/log/{a,b}.log" of logrotate 'workload' cannot contain quotes, braces, '#', whitespace or control characters.`,
+				"Path '/var/log/[a.log' of logrotate 'workload' is not a valid glob pattern.",
+				"Path '/var/log/shared.log' of logrotate 'agent' is already rotated by logrotate 'workload'.",
+			},
+		},
+		`invalid policy`: {
+			Logrotate: []image.LogRotation{
+				{
+					Name:      "workload",
+					Paths:     []string{"/var/log/workload.log"},
+					Frequency: "hourly",
+					Rotate:    -1,
+					MaxSize:   "100MB",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'frequency' of logrotate 'workload' must be one of: daily, weekly, monthly, yearly.",
+				"The 'rotate' field of logrotate 'workload' cannot be negative.",
+				"The 'maxSize' of logrotate 'workload' must be a positive integer optionally followed by one of 'k', 'M' or 'G' (e.g. '100M').",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateLogrotate(test.Logrotate)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
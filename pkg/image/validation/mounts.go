@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	mountsComponent = "Mount Points"
+)
+
+// validateMounts checks the paths mounted or created on the node by the different sections of the definition,
+// along with the mounts of the base image, for entries which would hide or replace each other. Conflicts within
+// a single section are reported by the validation of the section itself.
+func validateMounts(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	paths := ctx.ImageDefinition.NodePaths()
+	for i := range paths {
+		for j := i + 1; j < len(paths); j++ {
+			first, second := &paths[i], &paths[j]
+			if first.Field == second.Field {
+				continue
+			}
+
+			reason := pathConflict(first, second)
+			if reason == "" {
+				continue
+			}
+
+			failures = append(failures, FailedValidation{
+				Field:       second.Field,
+				UserMessage: fmt.Sprintf("The %s conflicts with the %s: %s", describeNodePath(second), describeNodePath(first), reason),
+			})
+		}
+	}
+
+	return failures
+}
+
+// pathConflict returns why the given paths cannot both be mounted or created, or an empty string if they can.
+func pathConflict(first, second *image.NodePath) string {
+	switch {
+	case first.Path == second.Path:
+		return samePathConflict(first, second)
+	case isBelow(second.Path, first.Path):
+		return nestedPathConflict(first)
+	case isBelow(first.Path, second.Path):
+		return nestedPathConflict(second)
+	default:
+		return ""
+	}
+}
+
+func samePathConflict(first, second *image.NodePath) string {
+	if first.Type == image.NodePathFile || second.Type == image.NodePathFile {
+		return "the path cannot be both a file and another entry."
+	}
+
+	if first.IsMount() && second.IsMount() {
+		return "only one file system can be mounted at the path."
+	}
+
+	// Directories are created before the mounts of the node, the root of the mounted file system replaces them
+	for _, entry := range []*image.NodePath{first, second} {
+		switch {
+		case entry.Type == image.NodePathPartition:
+			return "the ownership and permissions of the directory are replaced by those of the mounted partition."
+		case entry.Type == image.NodePathOverlay && entry.OverlayType == image.OverlayTypeTmpfs:
+			return "the ownership and permissions of the directory are replaced by those of the tmpfs overlay."
+		}
+	}
+
+	return ""
+}
+
+// nestedPathConflict returns why no entry can be located below the parent entry, if any.
+func nestedPathConflict(parent *image.NodePath) string {
+	switch {
+	case parent.Type == image.NodePathFile:
+		return fmt.Sprintf("'%s' is a file and cannot contain other entries.", parent.Path)
+	case parent.Type == image.NodePathOverlay && parent.OverlayType == image.OverlayTypeTmpfs:
+		return fmt.Sprintf("the contents created below '%s' at build time are hidden by the empty tmpfs overlay at runtime.", parent.Path)
+	default:
+		return ""
+	}
+}
+
+func describeNodePath(p *image.NodePath) string {
+	if p.Field == "" {
+		return fmt.Sprintf("%s '%s' of the base image", p.Description, p.Path)
+	}
+
+	return fmt.Sprintf("%s '%s' configured in '%s'", p.Description, p.Path, p.Field)
+}
+
+func isBelow(p, parent string) bool {
+	return strings.HasPrefix(p, strings.TrimSuffix(parent, "/")+"/")
+}
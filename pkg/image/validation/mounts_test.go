@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateMounts(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+		ExpectedFields         []string
+	}{
+		`not defined`: {},
+		`valid`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					ReadOnlyRoot: image.ReadOnlyRoot{
+						Enabled: true,
+						Overlays: []image.Overlay{
+							{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs},
+							{Path: "/usr/lib/app/data", Type: image.OverlayTypePersistent},
+						},
+					},
+					Directories: []image.Directory{
+						{Path: "/usr/lib/app/data"},
+						{Path: "/usr/lib/app/data/db"},
+						{Path: "/var/lib/app"},
+						{Path: "/var/lib/archives"},
+					},
+					ImageArchives: image.ImageArchives{Path: "/var/lib/archives"},
+					PAM:           image.PAM{ConfigFiles: []string{"sshd"}},
+				},
+			},
+		},
+		`directory below a tmpfs overlay`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					ReadOnlyRoot: image.ReadOnlyRoot{
+						Enabled:  true,
+						Overlays: []image.Overlay{{Path: "/usr/lib/app", Type: image.OverlayTypeTmpfs}},
+					},
+					Directories: []image.Directory{{Path: "/usr/lib/app/data"}},
+					Kdump:       image.Kdump{Memory: "512M", SavePath: "/usr/lib/app/crash"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The directory '/usr/lib/app/data' configured in 'operatingSystem/directories' conflicts with the tmpfs overlay " +
+					"'/usr/lib/app' configured in 'operatingSystem/readOnlyRoot/overlays': the contents created below '/usr/lib/app' " +
+					"at build time are hidden by the empty tmpfs overlay at runtime.",
+				"The kdump save directory '/usr/lib/app/crash' configured in 'operatingSystem/kdump/savePath' conflicts with the tmpfs overlay " +
+					"'/usr/lib/app' configured in 'operatingSystem/readOnlyRoot/overlays': the contents created below '/usr/lib/app' " +
+					"at build time are hidden by the empty tmpfs overlay at runtime.",
+			},
+			ExpectedFields: []string{"operatingSystem/directories", "operatingSystem/kdump/savePath"},
+		},
+		`directory at a tmpfs overlay`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					ReadOnlyRoot: image.ReadOnlyRoot{
+						Enabled:  true,
+						Overlays: []image.Overlay{{Path: "/usr/lib/app", Type: image.OverlayTypeTmpfs}},
+					},
+					Directories: []image.Directory{{Path: "/usr/lib/app"}},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The directory '/usr/lib/app' configured in 'operatingSystem/directories' conflicts with the tmpfs overlay " +
+					"'/usr/lib/app' configured in 'operatingSystem/readOnlyRoot/overlays': the ownership and permissions of the " +
+					"directory are replaced by those of the tmpfs overlay.",
+			},
+			ExpectedFields: []string{"operatingSystem/directories"},
+		},
+		`directory at a partition`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Directories: []image.Directory{{Path: "/boot/efi"}},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The directory '/boot/efi' configured in 'operatingSystem/directories' conflicts with the EFI system partition " +
+					"'/boot/efi' of the base image: the ownership and permissions of the directory are replaced by those of the mounted partition.",
+			},
+			ExpectedFields: []string{"operatingSystem/directories"},
+		},
+		`files and directories`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Directories: []image.Directory{{Path: "/etc/pam.d/sshd"}, {Path: "/etc/rancher/kubeconfig/certs"}},
+					PAM:         image.PAM{ConfigFiles: []string{"sshd"}},
+				},
+				Kubernetes: image.Kubernetes{
+					Kubeconfig: image.Kubeconfig{Path: "/etc/rancher/kubeconfig"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The PAM configuration file '/etc/pam.d/sshd' configured in 'operatingSystem/pam/configFiles' conflicts with the " +
+					"directory '/etc/pam.d/sshd' configured in 'operatingSystem/directories': the path cannot be both a file and another entry.",
+				"The kubeconfig '/etc/rancher/kubeconfig' configured in 'kubernetes/kubeconfig/path' conflicts with the directory " +
+					"'/etc/rancher/kubeconfig/certs' configured in 'operatingSystem/directories': '/etc/rancher/kubeconfig' is a file " +
+					"and cannot contain other entries.",
+			},
+			ExpectedFields: []string{"operatingSystem/pam/configFiles", "kubernetes/kubeconfig/path"},
+		},
+		`overlays without read-only root`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					ReadOnlyRoot: image.ReadOnlyRoot{
+						Overlays: []image.Overlay{{Path: "/usr/lib/app", Type: image.OverlayTypeTmpfs}},
+					},
+					Directories: []image.Directory{{Path: "/usr/lib/app/data"}},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageDefinition: &test.Definition,
+			}
+
+			failures := validateMounts(&ctx)
+
+			var messages, fields []string
+			for _, failure := range failures {
+				assert.False(t, failure.Warning)
+				messages = append(messages, failure.UserMessage)
+				fields = append(fields, failure.Field)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, messages)
+			assert.Equal(t, test.ExpectedFields, fields)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// networkProfileNameRegex restricts profile names to characters usable in directory names and on the kernel
+// command line without quoting
+var networkProfileNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// kernelArgKeyRegex matches the key of a kernel command line argument, optionally namespaced by dots
+var kernelArgKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+func validateNetworkProfiles(profiles *image.NetworkProfiles, imageConfigDir string) []FailedValidation {
+	if profiles.KernelArg == "" && profiles.Default == "" && len(profiles.Profiles) == 0 {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if len(profiles.Profiles) == 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkProfiles/profiles",
+			UserMessage: "The 'profiles' field is required in the 'networkProfiles' section.",
+		})
+	}
+
+	if profiles.KernelArg != "" && !kernelArgKeyRegex.MatchString(profiles.KernelArg) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/networkProfiles/kernelArg",
+			UserMessage: fmt.Sprintf("The network profiles 'kernelArg' field '%s' must be a kernel command line key "+
+				"(e.g. '%s'), without a value.", profiles.KernelArg, image.DefaultNetworkProfileKernelArg),
+		})
+	}
+
+	for _, duplicate := range findDuplicates(profiles.Profiles) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkProfiles/profiles",
+			UserMessage: fmt.Sprintf("The network profile '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, profile := range profiles.Profiles {
+		failures = append(failures, validateNetworkProfile(profile, imageConfigDir)...)
+	}
+
+	switch {
+	case profiles.Default == "":
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/networkProfiles/default",
+			UserMessage: "The 'default' field is required in the 'networkProfiles' section, naming the profile applied " +
+				"when the kernel argument is not set.",
+		})
+	case len(profiles.Profiles) != 0 && !slices.Contains(profiles.Profiles, profiles.Default):
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkProfiles/default",
+			UserMessage: fmt.Sprintf("The default network profile '%s' must be listed under 'profiles'.", profiles.Default),
+		})
+	}
+
+	if len(profiles.Profiles) == 1 {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/networkProfiles/profiles",
+			UserMessage: fmt.Sprintf("Only the network profile '%s' is embedded and always applied, consider providing "+
+				"its configuration in the '%s' directory instead.", profiles.Profiles[0], combustion.NetworkConfigDir),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
+
+func validateNetworkProfile(profile, imageConfigDir string) []FailedValidation {
+	if !networkProfileNameRegex.MatchString(profile) {
+		return []FailedValidation{{
+			Field: "operatingSystem/networkProfiles/profiles",
+			UserMessage: fmt.Sprintf("The network profile name '%s' may only contain letters, digits, '-' and '_', "+
+				"and must start with a letter or a digit.", profile),
+		}}
+	}
+
+	profileDir := filepath.Join(imageConfigDir, combustion.NetworkProfilesDir, profile)
+
+	entries, err := os.ReadDir(profileDir)
+	if err != nil {
+		return []FailedValidation{{
+			Field: "operatingSystem/networkProfiles/profiles",
+			UserMessage: fmt.Sprintf("The configuration of the network profile '%s' must be provided in the '%s' directory.",
+				profile, filepath.Join(combustion.NetworkProfilesDir, profile)),
+			Error: err,
+		}}
+	}
+
+	if len(entries) == 0 {
+		return []FailedValidation{{
+			Field: "operatingSystem/networkProfiles/profiles",
+			UserMessage: fmt.Sprintf("The '%s' directory of the network profile '%s' is empty.",
+				filepath.Join(combustion.NetworkProfilesDir, profile), profile),
+		}}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateNetworkProfiles(t *testing.T) {
+	configDir := t.TempDir()
+	for _, profile := range []string{"office", "field"} {
+		profileDir := filepath.Join(configDir, "network-profiles", profile)
+		require.NoError(t, os.MkdirAll(profileDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(profileDir, "host.yaml"), []byte("interfaces: []"), 0o600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "network-profiles", "empty"), 0o755))
+
+	tests := map[string]struct {
+		NetworkProfiles        image.NetworkProfiles
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			NetworkProfiles: image.NetworkProfiles{
+				KernelArg: "site.network",
+				Default:   "field",
+				Profiles:  []string{"office", "field"},
+			},
+		},
+		`single profile`: {
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "office",
+				Profiles: []string{"office"},
+			},
+			ExpectedFailedMessages: []string{
+				"Only the network profile 'office' is embedded and always applied, consider providing its configuration in the 'network' directory instead.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`missing profiles`: {
+			NetworkProfiles: image.NetworkProfiles{
+				Default: "office",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'profiles' field is required in the 'networkProfiles' section.",
+			},
+		},
+		`missing default`: {
+			NetworkProfiles: image.NetworkProfiles{
+				Profiles: []string{"office", "field"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'default' field is required in the 'networkProfiles' section, naming the profile applied when the kernel argument is not set.",
+			},
+		},
+		`unknown default`: {
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "lab",
+				Profiles: []string{"office", "field"},
+			},
+			ExpectedFailedMessages: []string{
+				"The default network profile 'lab' must be listed under 'profiles'.",
+			},
+		},
+		`duplicate profiles`: {
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "office",
+				Profiles: []string{"office", "field", "office"},
+			},
+			ExpectedFailedMessages: []string{
+				"The network profile 'office' is specified more than once.",
+			},
+		},
+		`invalid kernel arg`: {
+			NetworkProfiles: image.NetworkProfiles{
+				KernelArg: "network=office",
+				Default:   "office",
+				Profiles:  []string{"office", "field"},
+			},
+			ExpectedFailedMessages: []string{
+				"The network profiles 'kernelArg' field 'network=office' must be a kernel command line key (e.g. 'eib.network_profile'), without a value.",
+			},
+		},
+		`invalid profile name`: {
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "office",
+				Profiles: []string{"office", "../field"},
+			},
+			ExpectedFailedMessages: []string{
+				"The network profile name '../field' may only contain letters, digits, '-' and '_', and must start with a letter or a digit.",
+			},
+		},
+		`missing and empty configuration`: {
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "office",
+				Profiles: []string{"office", "lab", "empty"},
+			},
+			ExpectedFailedMessages: []string{
+				"The configuration of the network profile 'lab' must be provided in the 'network-profiles/lab' directory.",
+				"The 'network-profiles/empty' directory of the network profile 'empty' is empty.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateNetworkProfiles(&test.NetworkProfiles, configDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	taintEffectNoSchedule       = "NoSchedule"
+	taintEffectPreferNoSchedule = "PreferNoSchedule"
+	taintEffectNoExecute        = "NoExecute"
+
+	maxLabelPrefixLength = 253
+)
+
+var (
+	validTaintEffects = []string{taintEffectNoSchedule, taintEffectPreferNoSchedule, taintEffectNoExecute}
+
+	labelNameRegex   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	labelPrefixRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+	// The kubelet refuses to register nodes with labels in the kubernetes.io and k8s.io
+	// namespaces other than the ones below and those in the kubelet and node namespaces.
+	allowedKubernetesLabels = []string{
+		"kubernetes.io/hostname",
+		"kubernetes.io/os",
+		"kubernetes.io/arch",
+		"beta.kubernetes.io/os",
+		"beta.kubernetes.io/arch",
+		"beta.kubernetes.io/instance-type",
+		"failure-domain.beta.kubernetes.io/region",
+		"failure-domain.beta.kubernetes.io/zone",
+		"topology.kubernetes.io/region",
+		"topology.kubernetes.io/zone",
+	}
+)
+
+func validateNodeMetadata(k8s *image.Kubernetes) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, node := range k8s.Nodes {
+		name := "node"
+		if node.Hostname != "" {
+			name = fmt.Sprintf("node '%s'", node.Hostname)
+		}
+
+		var labelKeys []string
+		for _, label := range node.Labels {
+			key, value, _ := strings.Cut(label, "=")
+			labelKeys = append(labelKeys, key)
+
+			if !strings.Contains(label, "=") {
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/labels",
+					UserMessage: fmt.Sprintf("Label '%s' of %s must be in the form of 'key=value'.", label, name),
+				})
+				continue
+			}
+
+			if msg := validateLabelKey(key); msg != "" {
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/labels",
+					UserMessage: fmt.Sprintf("Label '%s' of %s is invalid: %s", label, name, msg),
+				})
+			} else if isRestrictedLabel(key) {
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/labels",
+					UserMessage: fmt.Sprintf("Label '%s' of %s uses a namespace which the kubelet does not allow nodes to set.", label, name),
+				})
+			}
+
+			if msg := validateLabelValue(value); msg != "" {
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/labels",
+					UserMessage: fmt.Sprintf("Label '%s' of %s is invalid: %s", label, name, msg),
+				})
+			}
+		}
+
+		for _, duplicate := range findDuplicates(labelKeys) {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/nodes/labels",
+				UserMessage: fmt.Sprintf("Duplicate label key '%s' found for %s.", duplicate, name),
+			})
+		}
+
+		var taintKeys []string
+		for _, taint := range node.Taints {
+			keyValue, effect, found := strings.Cut(taint, ":")
+			key, value, _ := strings.Cut(keyValue, "=")
+			taintKeys = append(taintKeys, fmt.Sprintf("%s:%s", key, effect))
+
+			if !found {
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/taints",
+					UserMessage: fmt.Sprintf("Taint '%s' of %s must be in the form of 'key[=value]:effect'.", taint, name),
+				})
+				continue
+			}
+
+			if msg := validateLabelKey(key); msg != "" {
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/taints",
+					UserMessage: fmt.Sprintf("Taint '%s' of %s is invalid: %s", taint, name, msg),
+				})
+			}
+
+			if msg := validateLabelValue(value); msg != "" {
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/taints",
+					UserMessage: fmt.Sprintf("Taint '%s' of %s is invalid: %s", taint, name, msg),
+				})
+			}
+
+			if !slices.Contains(validTaintEffects, effect) {
+				options := strings.Join(validTaintEffects, ", ")
+				failures = append(failures, FailedValidation{
+					Field:       "kubernetes/nodes/taints",
+					UserMessage: fmt.Sprintf("Taint '%s' of %s must use one of the following effects: %s", taint, name, options),
+				})
+			}
+		}
+
+		for _, duplicate := range findDuplicates(taintKeys) {
+			failures = append(failures, FailedValidation{
+				Field:       "kubernetes/nodes/taints",
+				UserMessage: fmt.Sprintf("Duplicate taint '%s' found for %s.", duplicate, name),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateLabelKey(key string) string {
+	prefix, name, found := strings.Cut(key, "/")
+	if !found {
+		name = key
+	} else if prefix == "" || len(prefix) > maxLabelPrefixLength || !labelPrefixRegex.MatchString(prefix) {
+		return fmt.Sprintf("the key prefix must be a lowercase DNS subdomain of at most %d characters.", maxLabelPrefixLength)
+	}
+
+	if !labelNameRegex.MatchString(name) {
+		return "the key name must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character."
+	}
+
+	return ""
+}
+
+func validateLabelValue(value string) string {
+	if value != "" && !labelNameRegex.MatchString(value) {
+		return "the value must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character."
+	}
+
+	return ""
+}
+
+func isRestrictedLabel(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found || slices.Contains(allowedKubernetesLabels, key) {
+		return false
+	}
+
+	inNamespace := func(namespace string) bool {
+		return prefix == namespace || strings.HasSuffix(prefix, "."+namespace)
+	}
+
+	if inNamespace("kubelet.kubernetes.io") || inNamespace("node.kubernetes.io") {
+		return false
+	}
+
+	return inNamespace("kubernetes.io") || inNamespace("k8s.io")
+}
@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateNodeMetadata(t *testing.T) {
+	tests := map[string]struct {
+		Nodes                  []image.Node
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {
+			Nodes: []image.Node{
+				{Hostname: "node1.suse.com", Type: image.KubernetesNodeTypeServer},
+			},
+		},
+		`valid`: {
+			Nodes: []image.Node{
+				{
+					Hostname: "node1.suse.com",
+					Type:     image.KubernetesNodeTypeServer,
+					Labels:   []string{"zone=edge-1", "example.com/gpu=", "node.kubernetes.io/exclude-from-external-load-balancers=true", "topology.kubernetes.io/zone=a"},
+					Taints:   []string{"dedicated=gpu:NoSchedule", "dedicated=gpu:NoExecute", "maintenance:PreferNoSchedule"},
+				},
+			},
+		},
+		`single node without hostname`: {
+			Nodes: []image.Node{
+				{Labels: []string{"zone=edge-1"}, Taints: []string{"edge:NoSchedule"}},
+			},
+		},
+		`invalid labels`: {
+			Nodes: []image.Node{
+				{
+					Hostname: "node1.suse.com",
+					Labels:   []string{"zone", "-zone=a", "Example.com/zone=a", "zone2=a b", "node-role.kubernetes.io/worker=true"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Label 'zone' of node 'node1.suse.com' must be in the form of 'key=value'.",
+				"Label '-zone=a' of node 'node1.suse.com' is invalid: the key name must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character.",
+				"Label 'Example.com/zone=a' of node 'node1.suse.com' is invalid: the key prefix must be a lowercase DNS subdomain of at most 253 characters.",
+				"Label 'zone2=a b' of node 'node1.suse.com' is invalid: the value must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character.",
+				"Label 'node-role.kubernetes.io/worker=true' of node 'node1.suse.com' uses a namespace which the kubelet does not allow nodes to set.",
+			},
+		},
+		`invalid taints`: {
+			Nodes: []image.Node{
+				{
+					Taints: []string{"dedicated=gpu", "dedicated=gpu:NoRun", "=gpu:NoSchedule"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Taint 'dedicated=gpu' of node must be in the form of 'key[=value]:effect'.",
+				"Taint 'dedicated=gpu:NoRun' of node must use one of the following effects: NoSchedule, PreferNoSchedule, NoExecute",
+				"Taint '=gpu:NoSchedule' of node is invalid: the key name must be at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character.",
+			},
+		},
+		`duplicates`: {
+			Nodes: []image.Node{
+				{
+					Hostname: "node1.suse.com",
+					Labels:   []string{"zone=a", "zone=b"},
+					Taints:   []string{"dedicated=gpu:NoSchedule", "dedicated=cpu:NoSchedule"},
+				},
+				{
+					Hostname: "node2.suse.com",
+					Labels:   []string{"zone=a"},
+					Taints:   []string{"dedicated=gpu:NoSchedule"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Duplicate label key 'zone' found for node 'node1.suse.com'.",
+				"Duplicate taint 'dedicated:NoSchedule' found for node 'node1.suse.com'.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k8s := image.Kubernetes{Nodes: test.Nodes}
+			failures := validateNodeMetadata(&k8s)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
@@ -1,17 +1,183 @@
 package validation
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	osComponent = "Operating System"
 )
 
+// Package names may contain wildcards, but must not start with a dash as they would be parsed as zypper options
+var packageNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.+*?][A-Za-z0-9_.+*?-]*$`)
+
+// packageSpecUnsafeRegex matches the characters of a 'packageList' entry which the shell would interpret
+// when the entry is handed to the package manager during the dependency resolution
+var packageSpecUnsafeRegex = regexp.MustCompile(`[\s'"$` + "`" + `;&|<>(){}\\!]`)
+
+// hostnameRegex matches a hostname made of dot separated RFC 1123 labels
+var hostnameRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*$`)
+
+// sudoPolicies are the values accepted for the sudo policy of a user
+var sudoPolicies = []string{image.SudoPolicyNone, image.SudoPolicyAll, image.SudoPolicyCommands}
+
+// sudoersSpecialChars must be escaped in a sudoers command, which is not supported to keep the rules readable
+const sudoersSpecialChars = `,:=\`
+
+// udevRuleFieldRegex matches a single 'key operator "value"' field of a udev rule, see udev(7)
+// environmentVariableNameRegex matches the portable environment variable names accepted by shells
+var environmentVariableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// networkdDNSRegex matches the DNS setting of a .network file
+var networkdDNSRegex = regexp.MustCompile(`(?m)^\s*DNS\s*=`)
+
+// directoryModeRegex matches an octal permission mode, optionally including the special bits
+var machineIDRegex = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+var directoryModeRegex = regexp.MustCompile(`^[0-7]{3,4}$`)
+
+var udevRuleFieldRegex = regexp.MustCompile(`^[A-Za-z_]+(\{[^}]*\})?\s*(==|!=|\+=|-=|:=|=)\s*"(\\.|[^"\\])*"$`)
+
+// journaldStorages are the values accepted by the journald 'Storage' setting
+var journaldStorages = []string{"volatile", "persistent", "auto", "none"}
+
+// journaldSizeRegex matches a size in bytes, optionally followed by one of the base 1024 units accepted by journald
+var journaldSizeRegex = regexp.MustCompile(`^(\d+)([KMGTPE]?)$`)
+
+// timeSpanRegex matches a systemd time span (e.g. '1month' or '2w 3d'), see systemd.time(7)
+var timeSpanRegex = regexp.MustCompile(`^(\d+\s*(us|usec|ms|msec|s|sec|second|seconds|m|min|minute|minutes|h|hr|hour|hours|` +
+	`d|day|days|w|week|weeks|M|month|months|y|year|years)\s*)+$`)
+
+// rebootPolicies are the reboot strategies supported by rebootmgr
+var rebootPolicies = []string{"best-effort", "instantly", "maint_window", "off"}
+
+// calendarShorthands are the special expressions accepted by systemd in place of a full calendar event
+var calendarShorthands = []string{
+	"minutely", "hourly", "daily", "weekly", "monthly", "yearly", "annually", "quarterly", "semiannually",
+}
+
+var (
+	// calendarValue matches a single date or time component (e.g. '*', '5', '1..5', '*/15' or '0,30')
+	calendarValue   = `(?:\*|\d+(?:\.\.\d+)?)(?:/\d+)?`
+	calendarValues  = calendarValue + `(?:,` + calendarValue + `)*`
+	calendarWeekday = `(?:mon|tue|wed|thu|fri|sat|sun)[a-z]*(?:\.\.(?:mon|tue|wed|thu|fri|sat|sun)[a-z]*)?`
+
+	// calendarComponents are the parts of a calendar event in the order systemd expects them, each being optional
+	calendarComponents = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)^` + calendarWeekday + `(?:,` + calendarWeekday + `)*$`),
+		regexp.MustCompile(`^` + calendarValues + `-` + calendarValues + `(?:-` + calendarValues + `)?$`),
+		regexp.MustCompile(`^` + calendarValues + `:` + calendarValues + `(?::` + calendarValues + `)?$`),
+	}
+)
+
+// scheduledJobNameRegex restricts job names to characters usable in unit and file names
+var scheduledJobNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+var scheduledJobBackends = []string{image.ScheduledJobBackendTimer, image.ScheduledJobBackendCron}
+
+// cronShorthands are the special strings accepted by cronie in place of the five time and date fields
+var cronShorthands = []string{"@reboot", "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// cronFieldRegex matches a single crontab time or date field (e.g. '*', '*/15', '1-5', 'mon-fri' or '0,30')
+var cronFieldRegex = regexp.MustCompile(`(?i)^(?:\*|[0-9a-z]+(?:-[0-9a-z]+)?)(?:/\d+)?(?:,(?:\*|[0-9a-z]+(?:-[0-9a-z]+)?)(?:/\d+)?)*$`)
+
+// releaseLabelNameRegex matches the os-release style variable names, which are prefixed with EIB_LABEL_ in /etc/eib-release
+var releaseLabelNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// criticalPaths are required for the node to boot, be configured by combustion, or be administered. Removing
+// any of them, or a directory containing them, is only reported as a warning when explicitly allowed.
+var criticalPaths = []string{
+	"/bin", "/boot/efi", "/boot/grub2", "/dev", "/etc/fstab", "/etc/group", "/etc/passwd", "/etc/shadow",
+	"/etc/systemd", "/lib", "/lib64", "/proc", "/root", "/run", "/sbin", "/sys", "/usr/bin", "/usr/lib64",
+	"/usr/lib/modules", "/usr/lib/systemd", "/usr/sbin", "/var",
+}
+
+var knownShells = []string{
+	"/bin/bash", "/usr/bin/bash",
+	"/bin/sh", "/usr/bin/sh",
+	"/bin/zsh", "/usr/bin/zsh",
+	"/bin/false", "/usr/bin/false",
+	"/sbin/nologin", "/usr/sbin/nologin",
+}
+
+// nonInteractiveShells deny logging in, typically for service accounts.
+var nonInteractiveShells = []string{
+	"/bin/false", "/usr/bin/false",
+	"/sbin/nologin", "/usr/sbin/nologin",
+}
+
+// defaultGroups are present in SLE Micro without having to be defined under 'groups'
+var defaultGroups = []string{
+	"root", "bin", "daemon", "sys", "tty", "disk", "lp", "mail", "news", "uucp", "man", "kmem", "shadow",
+	"audio", "video", "cdrom", "dialout", "floppy", "tape", "wheel", "users", "nobody", "nogroup", "utmp",
+	"lock", "input", "kvm", "render", "sgx", "trusted", "systemd-journal",
+}
+
+var validDefaultTargets = []string{
+	"multi-user.target",
+	"graphical.target",
+	"rescue.target",
+	"emergency.target",
+}
+
+// apparmorProfileRegex matches the start of a profile block, either named (e.g. 'profile foo') or
+// attached to an executable path (e.g. '/usr/bin/foo'), optionally followed by flags
+var apparmorProfileRegex = regexp.MustCompile(`^\s*(profile\s+\S+|/\S+)[^{]*\{`)
+
+var sshdKeywordRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+var validSSHKeyTypes = []string{
+	"ssh-ed25519",
+	"ecdsa-sha2-nistp256",
+	"ecdsa-sha2-nistp384",
+	"ecdsa-sha2-nistp521",
+	"rsa-sha2-256",
+	"rsa-sha2-512",
+	"ssh-rsa",
+	"sk-ssh-ed25519@openssh.com",
+	"sk-ecdsa-sha2-nistp256@openssh.com",
+	"ssh-ed25519-cert-v01@openssh.com",
+	"ecdsa-sha2-nistp256-cert-v01@openssh.com",
+	"ecdsa-sha2-nistp384-cert-v01@openssh.com",
+	"ecdsa-sha2-nistp521-cert-v01@openssh.com",
+	"rsa-sha2-256-cert-v01@openssh.com",
+	"rsa-sha2-512-cert-v01@openssh.com",
+	"ssh-rsa-cert-v01@openssh.com",
+	"sk-ssh-ed25519-cert-v01@openssh.com",
+	"sk-ecdsa-sha2-nistp256-cert-v01@openssh.com",
+}
+
+// auditRuleOptions are the auditctl options accepted in rule files, mapped to whether they require an argument
+var auditRuleOptions = map[string]bool{
+	"-a": true, "-A": true, "-d": true, "-w": true, "-W": true,
+	"-b": true, "-e": true, "-f": true, "-r": true, "--backlog_wait_time": true,
+	"-D": false, "-c": false, "-i": false, "--loginuid-immutable": false,
+}
+
+// sshdManagedKeywords are generated from the dedicated 'sshd' fields and may not be set as options
+var sshdManagedKeywords = map[string]string{
+	"hostkey":                  "hostKeys",
+	"hostkeyalgorithms":        "allowedKeyTypes",
+	"pubkeyacceptedalgorithms": "allowedKeyTypes",
+	"pubkeyacceptedkeytypes":   "allowedKeyTypes",
+}
+
 func validateOperatingSystem(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
 
@@ -23,9 +189,45 @@ func validateOperatingSystem(ctx *image.Context) []FailedValidation {
 	failures = append(failures, validateUsers(&def.OperatingSystem)...)
 	failures = append(failures, validateSuma(&def.OperatingSystem)...)
 	failures = append(failures, validatePackages(&def.OperatingSystem)...)
+	failures = append(failures, validateKernel(def)...)
 	failures = append(failures, validateTimeSync(&def.OperatingSystem)...)
 	failures = append(failures, validateIsoConfig(def)...)
 	failures = append(failures, validateRawConfig(def)...)
+	failures = append(failures, validateBootValidation(&def.OperatingSystem.BootValidation, ctx.ImageConfigDir)...)
+	failures = append(failures, validateSSHD(&def.OperatingSystem.SSHD, ctx.ImageConfigDir)...)
+	failures = append(failures, validateFail2ban(&def.OperatingSystem)...)
+	failures = append(failures, validateAudit(&def.OperatingSystem.Audit, ctx.ImageConfigDir)...)
+	failures = append(failures, validateAppArmor(&def.OperatingSystem.AppArmor, ctx.ImageConfigDir)...)
+	failures = append(failures, validateNetworkd(&def.OperatingSystem.Networkd, ctx.ImageConfigDir)...)
+	failures = append(failures, validateNetworkProfiles(&def.OperatingSystem.NetworkProfiles, ctx.ImageConfigDir)...)
+	failures = append(failures, validatePodman(def)...)
+	failures = append(failures, validateImageArchives(&def.OperatingSystem.ImageArchives)...)
+	failures = append(failures, validateAutoUpdate(&def.OperatingSystem)...)
+	failures = append(failures, validateHostname(def, ctx.ImageConfigDir)...)
+	failures = append(failures, validateAutologin(&def.OperatingSystem)...)
+	failures = append(failures, validateHosts(def.OperatingSystem.Hosts)...)
+	failures = append(failures, validateKnownHosts(def.OperatingSystem.KnownHosts)...)
+	failures = append(failures, validateJournald(&def.OperatingSystem.Journald)...)
+	failures = append(failures, validateUdev(&def.OperatingSystem.Udev, ctx.ImageConfigDir)...)
+	failures = append(failures, validatePAM(&def.OperatingSystem.PAM, ctx.ImageConfigDir)...)
+	failures = append(failures, validateScheduledJobs(&def.OperatingSystem)...)
+	failures = append(failures, validateLogrotate(def.OperatingSystem.Logrotate)...)
+	failures = append(failures, validateRelease(&def.OperatingSystem.Release)...)
+	failures = append(failures, validateRemove(def.OperatingSystem.Remove, ctx.AllowCriticalRemovals)...)
+	failures = append(failures, validateStripDocs(&def.OperatingSystem)...)
+	failures = append(failures, validateDirectories(&def.OperatingSystem)...)
+	failures = append(failures, validateEnvironment(def.OperatingSystem.Environment)...)
+	failures = append(failures, validateShellDefaults(&def.OperatingSystem)...)
+	failures = append(failures, validateDNS(&def.OperatingSystem.DNS, &def.OperatingSystem.Networkd, ctx.ImageConfigDir)...)
+	failures = append(failures, validateDnsmasq(&def.OperatingSystem)...)
+	failures = append(failures, validateLocales(&def.OperatingSystem.Locales)...)
+	failures = append(failures, validateMachineID(&def.OperatingSystem.MachineID)...)
+	failures = append(failures, validateGPUDrivers(ctx)...)
+	failures = append(failures, validateZram(&def.OperatingSystem)...)
+	failures = append(failures, validateKdump(&def.OperatingSystem)...)
+	failures = append(failures, validateReadOnlyRoot(&def.OperatingSystem)...)
+	failures = append(failures, validateSecureBoot(def, ctx.ImageConfigDir)...)
+	failures = append(failures, validateCertificates(ctx.ImageConfigDir)...)
 
 	return failures
 }
@@ -34,7 +236,7 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
 	seenKeys := make(map[string]bool)
-	for _, arg := range os.KernelArgs {
+	for _, arg := range os.KernelArgs.Add {
 		key := arg
 
 		parts := strings.SplitN(arg, "=", 2)
@@ -43,6 +245,7 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 			key, value = parts[0], parts[1]
 			if key == "" || value == "" {
 				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/kernelArgs/add",
 					UserMessage: "Kernel arguments must be specified as 'key=value'.",
 				})
 			}
@@ -50,12 +253,53 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 
 		if _, exists := seenKeys[key]; exists {
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/kernelArgs/add",
 				UserMessage: fmt.Sprintf("Duplicate kernel argument found: %s", key),
 			})
 		}
 		seenKeys[key] = true
 	}
 
+	failures = append(failures, validateKernelArgRemovals(&os.KernelArgs)...)
+
+	return failures
+}
+
+func validateKernelArgRemovals(kernelArgs *image.KernelArgs) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, arg := range kernelArgs.Remove {
+		// Removals are applied as patterns against the existing command line, so they are restricted to single tokens
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"") {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/kernelArgs/remove",
+				UserMessage: fmt.Sprintf("The kernel argument removal '%s' must not be empty or contain whitespace or quotes.", arg),
+			})
+			continue
+		}
+
+		if strings.HasPrefix(arg, "=") {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/kernelArgs/remove",
+				UserMessage: fmt.Sprintf("The kernel argument removal '%s' must be specified as 'key' or 'key=value'.", arg),
+			})
+		}
+
+		if slices.Contains(kernelArgs.Add, arg) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/kernelArgs/remove",
+				UserMessage: fmt.Sprintf("The kernel argument '%s' cannot be both added and removed.", arg),
+			})
+		}
+	}
+
+	for _, duplicate := range findDuplicates(kernelArgs.Remove) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/kernelArgs/remove",
+			UserMessage: fmt.Sprintf("Duplicate kernel argument removal found: %s", duplicate),
+		})
+	}
+
 	return failures
 }
 
@@ -66,6 +310,7 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		duplicateValues := strings.Join(duplicates, ", ")
 		msg := fmt.Sprintf("Systemd enable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/systemd/enable",
 			UserMessage: msg,
 		})
 	}
@@ -74,6 +319,16 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		duplicateValues := strings.Join(duplicates, ", ")
 		msg := fmt.Sprintf("Systemd disable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/systemd/disable",
+			UserMessage: msg,
+		})
+	}
+
+	if os.Systemd.DefaultTarget != "" && !slices.Contains(validDefaultTargets, os.Systemd.DefaultTarget) {
+		msg := fmt.Sprintf("Systemd default target '%s' is not valid; it must be one of: %s.",
+			os.Systemd.DefaultTarget, strings.Join(validDefaultTargets, ", "))
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/systemd/defaultTarget",
 			UserMessage: msg,
 		})
 	}
@@ -83,6 +338,7 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 			if enableItem == disableItem {
 				msg := fmt.Sprintf("Systemd conflict found, '%s' is both enabled and disabled.", enableItem)
 				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/systemd/disable",
 					UserMessage: msg,
 				})
 			}
@@ -101,6 +357,7 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 	for _, group := range os.Groups {
 		if group.Name == "" {
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/groups/name",
 				UserMessage: "The 'name' field is required for all entries under 'groups'.",
 			})
 		}
@@ -108,6 +365,7 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 		if seenGroupNames[group.Name] {
 			msg := fmt.Sprintf("Duplicate group name found: %s", group.Name)
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/groups/name",
 				UserMessage: msg,
 			})
 		}
@@ -120,10 +378,16 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 func validateUsers(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
+	definedGroups := make(map[string]bool)
+	for _, group := range os.Groups {
+		definedGroups[group.Name] = true
+	}
+
 	seenUsernames := make(map[string]bool)
 	for _, user := range os.Users {
 		if user.Username == "" {
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users/username",
 				UserMessage: "The 'username' field is required for all entries under 'users'.",
 			})
 		}
@@ -131,12 +395,14 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 		if user.EncryptedPassword == "" && len(user.SSHKeys) == 0 {
 			msg := fmt.Sprintf("User '%s' must have either a password or at least one SSH key.", user.Username)
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users",
 				UserMessage: msg,
 			})
 		}
 
 		if !user.CreateHomeDir && len(user.SSHKeys) > 0 {
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users/createHomeDir",
 				UserMessage: "The 'createHomeDir' attribute must be set to 'true' if at least one SSH key is specified.",
 			})
 		}
@@ -144,10 +410,239 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 		if seenUsernames[user.Username] {
 			msg := fmt.Sprintf("Duplicate username found: %s", user.Username)
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users/username",
 				UserMessage: msg,
 			})
 		}
 		seenUsernames[user.Username] = true
+
+		failures = append(failures, validateUserSettings(&user, definedGroups)...)
+		failures = append(failures, validateUserSudo(&user)...)
+		failures = append(failures, validateUserPasswordExpiry(&user)...)
+	}
+
+	return failures
+}
+
+func validateUserSettings(user *image.OperatingSystemUser, definedGroups map[string]bool) []FailedValidation {
+	var failures []FailedValidation
+
+	if user.Username == "root" {
+		if user.Shell != "" || user.HomeDir != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users",
+				UserMessage: "The 'shell' and 'homeDir' fields cannot be set for the root user.",
+			})
+		}
+
+		return failures
+	}
+
+	if user.Shell != "" {
+		if !isPlainAbsolutePath(user.Shell) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users/shell",
+				UserMessage: fmt.Sprintf("The shell '%s' of user '%s' must be an absolute path.", user.Shell, user.Username),
+			})
+		} else if !slices.Contains(knownShells, user.Shell) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/users/shell",
+				UserMessage: fmt.Sprintf("The shell '%s' of user '%s' is not a commonly available shell; "+
+					"make sure it is installed in the image.", user.Shell, user.Username),
+				Warning: true,
+			})
+		}
+	}
+
+	if user.HomeDir != "" && (!isPlainAbsolutePath(user.HomeDir) || user.HomeDir == "/") {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/users/homeDir",
+			UserMessage: fmt.Sprintf("The home directory '%s' of user '%s' must be an absolute path.", user.HomeDir, user.Username),
+		})
+	}
+
+	for _, duplicate := range findDuplicates(user.SecondaryGroups) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/users/secondaryGroups",
+			UserMessage: fmt.Sprintf("The group '%s' is listed more than once in the secondary groups of user '%s'.", duplicate, user.Username),
+		})
+	}
+
+	groups := user.SecondaryGroups
+	if user.PrimaryGroup != "" {
+		groups = append([]string{user.PrimaryGroup}, groups...)
+	}
+
+	reportedGroups := make(map[string]bool)
+	for _, group := range groups {
+		if !definedGroups[group] && !slices.Contains(defaultGroups, group) && !reportedGroups[group] {
+			reportedGroups[group] = true
+
+			groupField := "operatingSystem/users/secondaryGroups"
+			if group == user.PrimaryGroup {
+				groupField = "operatingSystem/users/primaryGroup"
+			}
+
+			failures = append(failures, FailedValidation{
+				Field: groupField,
+				UserMessage: fmt.Sprintf("The group '%s' of user '%s' is neither defined under 'groups' nor a default "+
+					"group of the operating system.", group, user.Username),
+				Warning: true,
+			})
+		}
+	}
+
+	return failures
+}
+
+// isPlainAbsolutePath checks that the path is absolute, normalized and contains no whitespace.
+func isPlainAbsolutePath(path string) bool {
+	return filepath.IsAbs(path) && filepath.Clean(path) == path && !strings.ContainsFunc(path, unicode.IsSpace)
+}
+
+func validateUserSudo(user *image.OperatingSystemUser) []FailedValidation {
+	var failures []FailedValidation
+
+	sudo := user.Sudo
+	if sudo.Policy == "" {
+		if len(sudo.Commands) != 0 || sudo.NoPassword {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users/sudo/policy",
+				UserMessage: fmt.Sprintf("The sudo 'policy' of user '%s' must be set when configuring 'commands' or 'noPassword'.", user.Username),
+			})
+		}
+
+		return failures
+	}
+
+	if !slices.Contains(sudoPolicies, sudo.Policy) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/users/sudo/policy",
+			UserMessage: fmt.Sprintf("The sudo 'policy' of user '%s' must be one of: %s.", user.Username, strings.Join(sudoPolicies, ", ")),
+		})
+
+		return failures
+	}
+
+	if user.Username == "root" && sudo.Policy != image.SudoPolicyNone {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/users/sudo/policy",
+			UserMessage: "The sudo 'policy' cannot be set for the root user, which already has unrestricted privileges.",
+		})
+	}
+
+	switch sudo.Policy {
+	case image.SudoPolicyNone:
+		if len(sudo.Commands) != 0 || sudo.NoPassword {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/users/sudo",
+				UserMessage: fmt.Sprintf("The sudo 'commands' and 'noPassword' fields of user '%s' conflict with the '%s' policy.",
+					user.Username, image.SudoPolicyNone),
+			})
+		}
+	case image.SudoPolicyAll:
+		if len(sudo.Commands) != 0 {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/users/sudo/commands",
+				UserMessage: fmt.Sprintf("The sudo 'commands' of user '%s' conflict with the '%s' policy, which grants all commands.",
+					user.Username, image.SudoPolicyAll),
+			})
+		}
+	case image.SudoPolicyCommands:
+		if len(sudo.Commands) == 0 {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/users/sudo/commands",
+				UserMessage: fmt.Sprintf("The sudo 'commands' of user '%s' must be provided when using the '%s' policy.",
+					user.Username, image.SudoPolicyCommands),
+			})
+		}
+
+		for _, command := range sudo.Commands {
+			failures = append(failures, validateSudoCommand(user.Username, command)...)
+		}
+
+		for _, duplicate := range findDuplicates(sudo.Commands) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/users/sudo/commands",
+				UserMessage: fmt.Sprintf("The sudo command '%s' is listed more than once for user '%s'.", duplicate, user.Username),
+			})
+		}
+	}
+
+	if sudo.Policy != image.SudoPolicyNone && !sudo.NoPassword && user.EncryptedPassword == "" && user.Username != "root" {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/users/sudo/noPassword",
+			UserMessage: fmt.Sprintf("User '%s' has no password, so the sudo privileges requiring one cannot be used; "+
+				"consider setting 'noPassword'.", user.Username),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
+
+func validateUserPasswordExpiry(user *image.OperatingSystemUser) []FailedValidation {
+	var failures []FailedValidation
+
+	if user.PasswordExpire < 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/users/passwordExpire",
+			UserMessage: fmt.Sprintf("The 'passwordExpire' field of user '%s' must be a positive number of days.", user.Username),
+		})
+	}
+
+	if user.PasswordExpire == 0 && !user.ForceChange {
+		return failures
+	}
+
+	// An expired password has to be changed before logging in, including with an SSH key
+	if user.EncryptedPassword == "" {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/users/encryptedPassword",
+			UserMessage: fmt.Sprintf("The 'passwordExpire' and 'forceChange' fields of user '%s' require a password, "+
+				"otherwise the user is locked out once the password expires.", user.Username),
+		})
+	}
+
+	if user.ForceChange && slices.Contains(nonInteractiveShells, user.Shell) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/users/forceChange",
+			UserMessage: fmt.Sprintf("User '%s' is forced to change the password on first login, but the shell '%s' "+
+				"does not allow logging in interactively.", user.Username, user.Shell),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
+
+// validateSudoCommand checks a command is well-formed in a sudoers rule, in a similar fashion to 'visudo -c'.
+func validateSudoCommand(username, command string) []FailedValidation {
+	if strings.ContainsFunc(command, unicode.IsControl) {
+		return []FailedValidation{{
+			Field: "operatingSystem/users/sudo/commands",
+			UserMessage: fmt.Sprintf("The sudo command %q of user '%s' must not contain control characters such as newlines.",
+				command, username),
+		}}
+	}
+
+	var failures []FailedValidation
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 || !isPlainAbsolutePath(fields[0]) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/users/sudo/commands",
+			UserMessage: fmt.Sprintf("The sudo command '%s' of user '%s' must start with the absolute path of the executable.",
+				command, username),
+		})
+	}
+
+	if strings.ContainsAny(command, sudoersSpecialChars) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/users/sudo/commands",
+			UserMessage: fmt.Sprintf("The sudo command '%s' of user '%s' must not contain any of the characters '%s'.",
+				command, username, sudoersSpecialChars),
+		})
 	}
 
 	return failures
@@ -161,16 +656,19 @@ func validateSuma(os *image.OperatingSystem) []FailedValidation {
 	}
 	if os.Suma.Host == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/suma/host",
 			UserMessage: "The 'host' field is required for the 'suma' section.",
 		})
 	}
 	if strings.HasPrefix(os.Suma.Host, "http") {
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/suma/host",
 			UserMessage: "The suma 'host' field may not contain 'http://' or 'https://'",
 		})
 	}
 	if os.Suma.ActivationKey == "" {
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/suma/activationKey",
 			UserMessage: "The 'activationKey' field is required for the 'suma' section.",
 		})
 	}
@@ -183,18 +681,33 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 
 	if slices.Contains(os.Packages.PKGList, "") {
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/packages/packageList",
 			UserMessage: "The 'packageList' field cannot contain empty values.",
 		})
 	}
 
+	for _, p := range os.Packages.PKGList {
+		if packageSpecUnsafeRegex.MatchString(p) {
+			msg := fmt.Sprintf("The 'packageList' field contains an invalid package: '%s'. Entries must be package names, "+
+				"optionally followed by '=' and a version, and cannot contain whitespace or shell metacharacters.", p)
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/packages/packageList",
+				UserMessage: msg,
+			})
+		}
+	}
+
 	if duplicates := findDuplicates(os.Packages.PKGList); len(duplicates) > 0 {
 		duplicateValues := strings.Join(duplicates, ", ")
 		msg := fmt.Sprintf("The 'packageList' field contains duplicate packages: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/packages/packageList",
 			UserMessage: msg,
 		})
 	}
 
+	failures = append(failures, validatePackageLocks(&os.Packages)...)
+
 	// It is possible to only provide `additionalRepos` without listing any packages
 	// under `packageList` in the cases where RPMs are side-loaded under the `/rpms` directory.
 	if len(os.Packages.AdditionalRepos) > 0 {
@@ -204,6 +717,7 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 			if repo.URL == "" {
 				msg := "The 'url' field is required for all entries under 'additionalRepos'."
 				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/packages/additionalRepos/url",
 					UserMessage: msg,
 				})
 			}
@@ -215,6 +729,71 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 			duplicateValues := strings.Join(duplicates, ", ")
 			msg := fmt.Sprintf("The 'additionalRepos' field contains duplicate repos: %s", duplicateValues)
 			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/packages/additionalRepos",
+				UserMessage: msg,
+			})
+		}
+	}
+
+	return failures
+}
+
+func validatePackageLocks(packages *image.Packages) []FailedValidation {
+	var failures []FailedValidation
+
+	lists := []struct {
+		field    string
+		packages []string
+	}{
+		{field: "locks", packages: packages.Locks},
+		{field: "exclude", packages: packages.Exclude},
+	}
+
+	for _, list := range lists {
+		for _, p := range list.packages {
+			if !packageNameRegex.MatchString(p) {
+				msg := fmt.Sprintf("The '%s' field contains an invalid package name: '%s'", list.field, p)
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/packages/" + list.field,
+					UserMessage: msg,
+				})
+			}
+		}
+
+		if duplicates := findDuplicates(list.packages); len(duplicates) > 0 {
+			duplicateValues := strings.Join(duplicates, ", ")
+			msg := fmt.Sprintf("The '%s' field contains duplicate packages: %s", list.field, duplicateValues)
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/packages/" + list.field,
+				UserMessage: msg,
+			})
+		}
+	}
+
+	for _, p := range packages.Exclude {
+		if slices.Contains(packages.PKGList, p) {
+			msg := fmt.Sprintf("Package conflict found, '%s' is both listed in 'packageList' and excluded.", p)
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/packages/exclude",
+				UserMessage: msg,
+			})
+		}
+
+		if slices.Contains(packages.Locks, p) {
+			msg := fmt.Sprintf("Package conflict found, '%s' is both locked and excluded.", p)
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/packages/exclude",
+				UserMessage: msg,
+			})
+		}
+	}
+
+	// Locks are applied before the installation, which prevents locked packages from being installed
+	for _, p := range packages.Locks {
+		if slices.Contains(packages.PKGList, p) {
+			msg := fmt.Sprintf("Package conflict found, '%s' is both listed in 'packageList' and locked.", p)
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/packages/locks",
 				UserMessage: msg,
 			})
 		}
@@ -229,6 +808,7 @@ func validateIsoConfig(def *image.Definition) []FailedValidation {
 	if def.Image.ImageType != image.TypeISO && def.OperatingSystem.IsoConfiguration.InstallDevice != "" {
 		msg := fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO)
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/isoConfiguration/installDevice",
 			UserMessage: msg,
 		})
 	}
@@ -246,13 +826,7 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 	if def.Image.ImageType != image.TypeRAW {
 		msg := fmt.Sprintf("The 'rawConfiguration/diskSize' field can only be used when 'imageType' is '%s'.", image.TypeRAW)
 		failures = append(failures, FailedValidation{
-			UserMessage: msg,
-		})
-	}
-
-	if def.OperatingSystem.IsoConfiguration.InstallDevice != "" {
-		msg := "You cannot simultaneously configure rawConfiguration and isoConfiguration, regardless of image type."
-		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/rawConfiguration/diskSize",
 			UserMessage: msg,
 		})
 	}
@@ -260,6 +834,7 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 	if !def.OperatingSystem.RawConfiguration.DiskSize.IsValid() {
 		msg := "The 'rawConfiguration/diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'."
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/rawConfiguration/diskSize",
 			UserMessage: msg,
 		})
 	}
@@ -277,9 +852,1421 @@ func validateTimeSync(os *image.OperatingSystem) []FailedValidation {
 	if len(os.Time.NtpConfiguration.Pools) == 0 && len(os.Time.NtpConfiguration.Servers) == 0 {
 		msg := "If you're wanting to wait for NTP synchronization at boot, please ensure that you provide at least one NTP time source."
 		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/time/ntp/forceWait",
 			UserMessage: msg,
 		})
 	}
 
 	return failures
 }
+
+func validateBootValidation(bootValidation *image.BootValidation, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	if bootValidation.Script == "" {
+		if bootValidation.IgnoreFailure {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/bootValidation/ignoreFailure",
+				UserMessage: "The 'ignoreFailure' field within 'bootValidation' requires a 'script' to be specified.",
+			})
+		}
+
+		return failures
+	}
+
+	if !filepath.IsLocal(bootValidation.Script) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/bootValidation/script",
+			UserMessage: fmt.Sprintf("The boot validation 'script' field '%s' must be a relative path within the image configuration directory.", bootValidation.Script),
+		})
+
+		return failures
+	}
+
+	scriptPath := filepath.Join(imageConfigDir, bootValidation.Script)
+
+	info, err := os.Stat(scriptPath)
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/bootValidation/script",
+			UserMessage: fmt.Sprintf("The boot validation script '%s' could not be read.", bootValidation.Script),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	if !info.Mode().IsRegular() {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/bootValidation/script",
+			UserMessage: fmt.Sprintf("The boot validation script '%s' must be a regular file.", bootValidation.Script),
+		})
+
+		return failures
+	}
+
+	data, err := os.ReadFile(scriptPath)
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/bootValidation/script",
+			UserMessage: fmt.Sprintf("The boot validation script '%s' could not be read.", bootValidation.Script),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	// The script is executed directly, so it must declare its interpreter
+	if !bytes.HasPrefix(data, []byte("#!")) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/bootValidation/script",
+			UserMessage: fmt.Sprintf("The boot validation script '%s' must start with an interpreter line (e.g. '#!/bin/bash').", bootValidation.Script),
+		})
+	}
+
+	return failures
+}
+
+func validateSSHD(sshd *image.SSHD, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, keyType := range sshd.AllowedKeyTypes {
+		if !slices.Contains(validSSHKeyTypes, keyType) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/sshd/allowedKeyTypes",
+				UserMessage: fmt.Sprintf("The sshd 'allowedKeyTypes' entry '%s' is not a valid key type.", keyType),
+			})
+		}
+	}
+
+	for _, duplicate := range findDuplicates(sshd.AllowedKeyTypes) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/sshd/allowedKeyTypes",
+			UserMessage: fmt.Sprintf("The sshd 'allowedKeyTypes' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, duplicate := range findDuplicates(sshd.HostKeys) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/sshd/hostKeys",
+			UserMessage: fmt.Sprintf("The sshd 'hostKeys' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, hostKey := range sshd.HostKeys {
+		failures = append(failures, validateHostKey(hostKey, sshd.AllowedKeyTypes, imageConfigDir)...)
+	}
+
+	for keyword, value := range sshd.Options {
+		if !sshdKeywordRegex.MatchString(keyword) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/sshd/options",
+				UserMessage: fmt.Sprintf("The sshd option '%s' is not a valid sshd keyword.", keyword),
+			})
+			continue
+		}
+
+		lowerKeyword := strings.ToLower(keyword)
+		if field, ok := sshdManagedKeywords[lowerKeyword]; ok {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/sshd/options",
+				UserMessage: fmt.Sprintf("The sshd option '%s' cannot be set directly; use the '%s' field instead.", keyword, field),
+			})
+			continue
+		}
+
+		// A Match block would apply to all subsequently generated options
+		if lowerKeyword == "match" || lowerKeyword == "include" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/sshd/options",
+				UserMessage: fmt.Sprintf("The sshd option '%s' is not supported.", keyword),
+			})
+			continue
+		}
+
+		if strings.TrimSpace(value) == "" || strings.ContainsAny(value, "\r\n") {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/sshd/options",
+				UserMessage: fmt.Sprintf("The sshd option '%s' must have a single line, non-empty value.", keyword),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateHostKey(hostKey string, allowedKeyTypes []string, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	if !filepath.IsLocal(hostKey) || filepath.Base(hostKey) != hostKey || strings.HasSuffix(hostKey, ".pub") {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/sshd/hostKeys",
+			UserMessage: fmt.Sprintf("The sshd 'hostKeys' entry '%s' must be the name of a private key file in the '%s' directory.", hostKey, combustion.SSHDConfigDir),
+		})
+
+		return failures
+	}
+
+	data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.SSHDConfigDir, hostKey))
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/sshd/hostKeys",
+			UserMessage: fmt.Sprintf("The sshd host key '%s' could not be read.", hostKey),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	// The parsing errors never include the key material, so they are safe to be logged
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/sshd/hostKeys",
+				UserMessage: fmt.Sprintf("The sshd host key '%s' must not be protected by a passphrase.", hostKey),
+			})
+		} else {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/sshd/hostKeys",
+				UserMessage: fmt.Sprintf("The sshd host key '%s' is not a valid private key.", hostKey),
+				Error:       err,
+			})
+		}
+
+		return failures
+	}
+
+	if len(allowedKeyTypes) == 0 {
+		return failures
+	}
+
+	keyType := signer.PublicKey().Type()
+	accepted := []string{keyType}
+	if keyType == ssh.KeyAlgoRSA {
+		// RSA keys may be used with any of the RSA signature algorithms
+		accepted = append(accepted, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSASHA512)
+	}
+
+	if !slices.ContainsFunc(accepted, func(a string) bool { return slices.Contains(allowedKeyTypes, a) }) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/sshd/hostKeys",
+			UserMessage: fmt.Sprintf("The sshd host key '%s' of type '%s' is not permitted by 'allowedKeyTypes'.", hostKey, keyType),
+		})
+	}
+
+	return failures
+}
+
+func validateAudit(audit *image.Audit, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, duplicate := range findDuplicates(audit.RuleFiles) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/audit/ruleFiles",
+			UserMessage: fmt.Sprintf("The audit 'ruleFiles' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, ruleFile := range audit.RuleFiles {
+		failures = append(failures, validateAuditRuleFile(ruleFile, imageConfigDir)...)
+	}
+
+	for _, rule := range audit.Rules {
+		if strings.TrimSpace(rule) == "" || strings.ContainsAny(rule, "\r\n") {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/audit/rules",
+				UserMessage: "Audit rules must be single line and non-empty.",
+			})
+			continue
+		}
+
+		if problem := checkAuditRule(rule); problem != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/audit/rules",
+				UserMessage: fmt.Sprintf("The audit rule '%s' appears to be malformed: %s.", rule, problem),
+				Warning:     true,
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateAuditRuleFile(ruleFile, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	// augenrules only loads files with the '.rules' extension
+	if !filepath.IsLocal(ruleFile) || filepath.Base(ruleFile) != ruleFile || filepath.Ext(ruleFile) != ".rules" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/audit/ruleFiles",
+			UserMessage: fmt.Sprintf("The audit 'ruleFiles' entry '%s' must be the name of a '.rules' file in the '%s' directory.", ruleFile, combustion.AuditConfigDir),
+		})
+
+		return failures
+	}
+
+	if ruleFile == combustion.AuditInlineRulesFile {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/audit/ruleFiles",
+			UserMessage: fmt.Sprintf("The audit rule file name '%s' is reserved for the inline audit rules.", ruleFile),
+		})
+
+		return failures
+	}
+
+	data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.AuditConfigDir, ruleFile))
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/audit/ruleFiles",
+			UserMessage: fmt.Sprintf("The audit rule file '%s' could not be read.", ruleFile),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		rule := strings.TrimSpace(line)
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+
+		if problem := checkAuditRule(rule); problem != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/audit/ruleFiles",
+				UserMessage: fmt.Sprintf("The audit rule on line %d of '%s' appears to be malformed: %s.", i+1, ruleFile, problem),
+				Warning:     true,
+			})
+		}
+	}
+
+	return failures
+}
+
+// checkAuditRule performs a minimal syntax check of an audit rule, returning a description
+// of the problem if the rule is obviously malformed and an empty string otherwise.
+func checkAuditRule(rule string) string {
+	fields := strings.Fields(rule)
+	option := fields[0]
+
+	if !strings.HasPrefix(option, "-") {
+		return "it must start with an auditctl option"
+	}
+
+	requiresArgument, known := auditRuleOptions[option]
+	if !known {
+		return fmt.Sprintf("the option '%s' is not recognized", option)
+	}
+
+	if !requiresArgument {
+		return ""
+	}
+
+	if len(fields) < 2 || strings.HasPrefix(fields[1], "-") {
+		return fmt.Sprintf("the option '%s' requires a value", option)
+	}
+
+	switch option {
+	case "-a", "-A", "-d":
+		if !strings.Contains(fields[1], ",") {
+			return fmt.Sprintf("the option '%s' requires an action and a list (e.g. 'always,exit')", option)
+		}
+	case "-w", "-W":
+		if !strings.HasPrefix(fields[1], "/") {
+			return fmt.Sprintf("the option '%s' requires an absolute path", option)
+		}
+	}
+
+	return ""
+}
+
+func validateAppArmor(apparmor *image.AppArmor, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, duplicate := range findDuplicates(apparmor.Profiles) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/apparmor/profiles",
+			UserMessage: fmt.Sprintf("The apparmor 'profiles' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, profile := range apparmor.Profiles {
+		failures = append(failures, validateAppArmorProfile(profile, imageConfigDir)...)
+	}
+
+	return failures
+}
+
+func validateAppArmorProfile(profile, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	if !filepath.IsLocal(profile) || filepath.Base(profile) != profile {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/apparmor/profiles",
+			UserMessage: fmt.Sprintf("The apparmor 'profiles' entry '%s' must be the name of a file in the '%s' directory.", profile, combustion.AppArmorConfigDir),
+		})
+
+		return failures
+	}
+
+	data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.AppArmorConfigDir, profile))
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/apparmor/profiles",
+			UserMessage: fmt.Sprintf("The AppArmor profile '%s' could not be read.", profile),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	var depth int
+	var hasProfile, unbalanced bool
+	for _, line := range strings.Split(string(data), "\n") {
+		// Comments and directives such as '#include' never contain braces
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		if depth == 0 && apparmorProfileRegex.MatchString(line) {
+			hasProfile = true
+		}
+
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth < 0 {
+			unbalanced = true
+			break
+		}
+	}
+
+	if !hasProfile {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/apparmor/profiles",
+			UserMessage: fmt.Sprintf("The AppArmor profile '%s' does not define a profile.", profile),
+		})
+	}
+
+	if unbalanced || depth != 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/apparmor/profiles",
+			UserMessage: fmt.Sprintf("The AppArmor profile '%s' has unbalanced braces.", profile),
+		})
+	}
+
+	return failures
+}
+
+func validateNetworkd(networkd *image.Networkd, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	if len(networkd.ConfigFiles) == 0 {
+		return failures
+	}
+
+	for _, duplicate := range findDuplicates(networkd.ConfigFiles) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkd/configFiles",
+			UserMessage: fmt.Sprintf("The networkd 'configFiles' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, configFile := range networkd.ConfigFiles {
+		failures = append(failures, validateNetworkdConfigFile(configFile, imageConfigDir)...)
+	}
+
+	return failures
+}
+
+func validateNetworkdConfigFile(configFile, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	if !filepath.IsLocal(configFile) || filepath.Base(configFile) != configFile {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkd/configFiles",
+			UserMessage: fmt.Sprintf("The networkd 'configFiles' entry '%s' must be the name of a file in the '%s' directory.", configFile, combustion.NetworkdConfigDir),
+		})
+
+		return failures
+	}
+
+	// Each file type requires a different section to take effect
+	var requiredSection string
+	switch filepath.Ext(configFile) {
+	case ".network":
+		requiredSection = "[Match]"
+	case ".netdev":
+		requiredSection = "[NetDev]"
+	default:
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkd/configFiles",
+			UserMessage: fmt.Sprintf("The networkd 'configFiles' entry '%s' must have either the '.network' or the '.netdev' extension.", configFile),
+		})
+
+		return failures
+	}
+
+	data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.NetworkdConfigDir, configFile))
+	if err != nil {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkd/configFiles",
+			UserMessage: fmt.Sprintf("The networkd configuration file '%s' could not be read.", configFile),
+			Error:       err,
+		})
+
+		return failures
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == requiredSection {
+			return failures
+		}
+	}
+
+	if requiredSection == "[NetDev]" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkd/configFiles",
+			UserMessage: fmt.Sprintf("The networkd configuration file '%s' does not contain a '[NetDev]' section.", configFile),
+		})
+	} else {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/networkd/configFiles",
+			UserMessage: fmt.Sprintf("The networkd configuration file '%s' does not contain a '[Match]' section and applies to every interface.", configFile),
+			Warning:     true,
+		})
+	}
+
+	return failures
+}
+
+func validatePodman(def *image.Definition) []FailedValidation {
+	var failures []FailedValidation
+
+	images := def.OperatingSystem.Podman.Images
+	if len(images) == 0 {
+		return failures
+	}
+
+	if def.Kubernetes.Version != "" {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/podman/images",
+			UserMessage: "The podman 'images' are not available to Kubernetes, which uses its own container runtime. " +
+				"Images used by Kubernetes workloads should be listed under 'embeddedArtifactRegistry' instead.",
+			Warning: true,
+		})
+	}
+
+	registryImages := map[string]bool{}
+	for _, img := range def.EmbeddedArtifactRegistry.ContainerImages {
+		registryImages[img.Name] = true
+	}
+
+	failures = append(failures, validateArchivedImages(images, "podman", "operatingSystem/podman/images")...)
+
+	for _, img := range images {
+		if registryImages[img.Name] {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/podman/images/name",
+				UserMessage: fmt.Sprintf("The podman image '%s' is also listed under 'embeddedArtifactRegistry' and will be embedded twice.", img.Name),
+				Warning:     true,
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateImageArchives(archives *image.ImageArchives) []FailedValidation {
+	var failures []FailedValidation
+
+	if len(archives.Images) == 0 {
+		if archives.Path != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/imageArchives/images",
+				UserMessage: "The 'images' field is required when configuring the 'imageArchives' section.",
+			})
+		}
+
+		return failures
+	}
+
+	switch {
+	case archives.Path == "":
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/imageArchives/path",
+			UserMessage: "The 'path' field is required when configuring the 'imageArchives' section.",
+		})
+	case !filepath.IsAbs(archives.Path) || filepath.Clean(archives.Path) == "/":
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/imageArchives/path",
+			UserMessage: fmt.Sprintf("The 'path' field '%s' in the 'imageArchives' section must be an absolute path to a directory other than '/'.", archives.Path),
+		})
+	case strings.ContainsRune(archives.Path, '\'') || strings.ContainsFunc(archives.Path, unicode.IsControl):
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/imageArchives/path",
+			UserMessage: fmt.Sprintf("The 'path' field %q in the 'imageArchives' section cannot contain single quotes or control characters.", archives.Path),
+		})
+	}
+
+	failures = append(failures, validateArchivedImages(archives.Images, "archived", "operatingSystem/imageArchives/images")...)
+
+	return failures
+}
+
+// validateArchivedImages checks the images saved as archives, which retain the tag of the image as its name.
+func validateArchivedImages(images []image.ContainerImage, kind, field string) []FailedValidation {
+	var failures []FailedValidation
+
+	seenImages := map[string]bool{}
+	for _, img := range images {
+		if img.Name == "" {
+			failures = append(failures, FailedValidation{
+				Field:       field + "/name",
+				UserMessage: fmt.Sprintf("The 'name' field is required for each entry in the %s 'images'.", kind),
+			})
+
+			continue
+		}
+
+		if seenImages[img.Name] {
+			failures = append(failures, FailedValidation{
+				Field:       field + "/name",
+				UserMessage: fmt.Sprintf("Duplicate image name '%s' found in the %s 'images'.", img.Name, kind),
+			})
+		}
+		seenImages[img.Name] = true
+
+		named, err := reference.ParseNormalizedNamed(img.Name)
+		if err != nil {
+			failures = append(failures, FailedValidation{
+				Field:       field + "/name",
+				UserMessage: fmt.Sprintf("The %s image '%s' is not a valid image reference.", kind, img.Name),
+				Error:       err,
+			})
+
+			continue
+		}
+
+		// Images are loaded by name, which is not retained for references by digest
+		if _, isDigested := named.(reference.Digested); isDigested {
+			failures = append(failures, FailedValidation{
+				Field:       field + "/name",
+				UserMessage: fmt.Sprintf("The %s image '%s' must be referenced by tag rather than by digest.", kind, img.Name),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateAutoUpdate(os *image.OperatingSystem) []FailedValidation {
+	var failures []FailedValidation
+
+	autoUpdate := os.AutoUpdate
+
+	if autoUpdate.Schedule != "" {
+		if !autoUpdate.Enabled {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/autoUpdate/schedule",
+				UserMessage: "The autoUpdate 'schedule' field can only be used when 'enabled' is set.",
+			})
+		} else if !isCalendarEvent(autoUpdate.Schedule) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/autoUpdate/schedule",
+				UserMessage: fmt.Sprintf("The autoUpdate 'schedule' '%s' is not a valid systemd calendar expression (e.g. 'daily' or 'Mon..Fri *-*-* 03:00').",
+					autoUpdate.Schedule),
+			})
+		}
+	}
+
+	if autoUpdate.RebootPolicy != "" && !slices.Contains(rebootPolicies, autoUpdate.RebootPolicy) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/autoUpdate/rebootPolicy",
+			UserMessage: fmt.Sprintf("The autoUpdate 'rebootPolicy' must be one of: %s.", strings.Join(rebootPolicies, ", ")),
+		})
+	}
+
+	if autoUpdate.Enabled && slices.Contains(os.Systemd.Disable, "transactional-update.timer") {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/systemd/disable",
+			UserMessage: "Automatic updates are enabled, but 'transactional-update.timer' is disabled in the 'systemd' section.",
+		})
+	}
+
+	return failures
+}
+
+// isCalendarEvent checks the expression against the systemd calendar event syntax
+// (see systemd.time(7)), which is fully evaluated only by systemd on the node.
+func isCalendarEvent(expression string) bool {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return false
+	}
+
+	if slices.Contains(calendarShorthands, strings.ToLower(expression)) {
+		return true
+	}
+
+	fields := strings.Fields(expression)
+	if fields[len(fields)-1] == "UTC" {
+		fields = fields[:len(fields)-1]
+	}
+
+	matched := 0
+	for _, component := range calendarComponents {
+		if matched < len(fields) && component.MatchString(fields[matched]) {
+			matched++
+		}
+	}
+
+	return matched > 0 && matched == len(fields)
+}
+
+func validateHosts(hosts []image.HostEntry) []FailedValidation {
+	var failures []FailedValidation
+
+	// Tracks the IP address each hostname is mapped to, as only the first mapping is used when resolving
+	hostnameIPs := make(map[string]string)
+
+	for _, host := range hosts {
+		if host.IP == "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/hosts/ip",
+				UserMessage: "The 'ip' field is required for each entry in the 'hosts' section.",
+			})
+		} else if _, err := netip.ParseAddr(host.IP); err != nil {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/hosts/ip",
+				UserMessage: fmt.Sprintf("The 'hosts' entry IP address '%s' is not a valid IPv4 or IPv6 address.", host.IP),
+				Error:       err,
+			})
+		}
+
+		if len(host.Hostnames) == 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/hosts/hostnames",
+				UserMessage: fmt.Sprintf("The 'hosts' entry for '%s' must list at least one hostname.", host.IP),
+			})
+		}
+
+		for _, hostname := range host.Hostnames {
+			if len(hostname) > 253 || !hostnameRegex.MatchString(hostname) {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/hosts/hostnames",
+					UserMessage: fmt.Sprintf("The 'hosts' hostname '%s' is not a valid hostname.", hostname),
+				})
+				continue
+			}
+
+			key := strings.ToLower(hostname)
+			ip, found := hostnameIPs[key]
+			switch {
+			case !found:
+				hostnameIPs[key] = host.IP
+			case ip != host.IP:
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/hosts/hostnames",
+					UserMessage: fmt.Sprintf("The 'hosts' hostname '%s' is mapped to multiple IP addresses: %s, %s.", hostname, ip, host.IP),
+				})
+			}
+		}
+	}
+
+	return failures
+}
+
+func validateJournald(journald *image.Journald) []FailedValidation {
+	var failures []FailedValidation
+
+	if journald.Storage != "" && !slices.Contains(journaldStorages, journald.Storage) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/journald/storage",
+			UserMessage: fmt.Sprintf("The journald 'storage' must be one of: %s.", strings.Join(journaldStorages, ", ")),
+		})
+	}
+
+	sizes := []struct {
+		field string
+		value string
+	}{
+		{field: "systemMaxUse", value: journald.SystemMaxUse},
+		{field: "systemMaxFileSize", value: journald.SystemMaxFileSize},
+		{field: "runtimeMaxUse", value: journald.RuntimeMaxUse},
+	}
+
+	for _, size := range sizes {
+		if size.value != "" && !journaldSizeRegex.MatchString(size.value) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/journald/" + size.field,
+				UserMessage: fmt.Sprintf("The journald '%s' value '%s' must be a size in bytes, optionally followed by "+
+					"one of the units K, M, G, T, P or E (e.g. '500M').", size.field, size.value),
+			})
+		}
+	}
+
+	if maxUse, ok := parseJournaldSize(journald.SystemMaxUse); ok {
+		if maxFileSize, ok := parseJournaldSize(journald.SystemMaxFileSize); ok && maxFileSize > maxUse {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/journald/systemMaxFileSize",
+				UserMessage: fmt.Sprintf("The journald 'systemMaxFileSize' '%s' exceeds the 'systemMaxUse' '%s'.",
+					journald.SystemMaxFileSize, journald.SystemMaxUse),
+				Warning: true,
+			})
+		}
+	}
+
+	if journald.MaxRetention != "" && !timeSpanRegex.MatchString(journald.MaxRetention) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/journald/maxRetention",
+			UserMessage: fmt.Sprintf("The journald 'maxRetention' value '%s' must be a systemd time span (e.g. '1month' or '2w').",
+				journald.MaxRetention),
+		})
+	}
+
+	if journald.Storage == "volatile" || journald.Storage == "none" {
+		if journald.SystemMaxUse != "" || journald.SystemMaxFileSize != "" {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/journald/storage",
+				UserMessage: fmt.Sprintf("The journald 'systemMaxUse' and 'systemMaxFileSize' settings have no effect with the '%s' storage.",
+					journald.Storage),
+				Warning: true,
+			})
+		}
+	}
+
+	return failures
+}
+
+// parseJournaldSize returns the size in bytes, if the value is a valid journald size.
+func parseJournaldSize(value string) (uint64, bool) {
+	matches := journaldSizeRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, false
+	}
+
+	size, err := strconv.ParseUint(matches[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if matches[2] != "" {
+		size <<= 10 * (strings.Index("KMGTPE", matches[2]) + 1)
+	}
+
+	return size, true
+}
+
+func validateUdev(udev *image.Udev, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, duplicate := range findDuplicates(udev.Rules) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/udev/rules",
+			UserMessage: fmt.Sprintf("The udev 'rules' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, rule := range udev.Rules {
+		failures = append(failures, validateUdevRuleFile(rule, imageConfigDir)...)
+	}
+
+	return failures
+}
+
+func validateUdevRuleFile(ruleFile, imageConfigDir string) []FailedValidation {
+	if !filepath.IsLocal(ruleFile) || filepath.Base(ruleFile) != ruleFile {
+		return []FailedValidation{{
+			Field:       "operatingSystem/udev/rules",
+			UserMessage: fmt.Sprintf("The udev 'rules' entry '%s' must be the name of a file in the '%s' directory.", ruleFile, combustion.UdevConfigDir),
+		}}
+	}
+
+	if filepath.Ext(ruleFile) != ".rules" {
+		return []FailedValidation{{
+			Field:       "operatingSystem/udev/rules",
+			UserMessage: fmt.Sprintf("The udev rule file '%s' must have the '.rules' extension, as other files are ignored by udev.", ruleFile),
+		}}
+	}
+
+	data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.UdevConfigDir, ruleFile))
+	if err != nil {
+		return []FailedValidation{{
+			Field:       "operatingSystem/udev/rules",
+			UserMessage: fmt.Sprintf("The udev rule file '%s' could not be read.", ruleFile),
+			Error:       err,
+		}}
+	}
+
+	var failures []FailedValidation
+	var rule string
+	var ruleStart int
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if rule == "" {
+			ruleStart = i + 1
+		}
+
+		// Rules may span multiple lines, each but the last ending with a backslash
+		if continued, found := strings.CutSuffix(line, "\\"); found {
+			rule += continued
+			if i != len(lines)-1 {
+				continue
+			}
+		} else {
+			rule += line
+		}
+
+		if !isValidUdevRule(rule) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/udev/rules",
+				UserMessage: fmt.Sprintf("Line %d of the udev rule file '%s' is not a valid udev rule.", ruleStart, ruleFile),
+			})
+		}
+
+		rule = ""
+	}
+
+	return failures
+}
+
+// isValidUdevRule checks the rule consists of comma separated 'key operator "value"' fields. Comments and
+// empty lines are valid, while the keys and values themselves are only interpreted by udev on the node.
+func isValidUdevRule(rule string) bool {
+	rule = strings.TrimSpace(rule)
+	if rule == "" || strings.HasPrefix(rule, "#") {
+		return true
+	}
+
+	fields := splitUdevRule(rule)
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+
+		// A trailing comma is accepted by udev
+		if field == "" && i == len(fields)-1 && i != 0 {
+			continue
+		}
+
+		if !udevRuleFieldRegex.MatchString(field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitUdevRule splits the rule on the commas separating its fields, ignoring those within quoted values.
+func splitUdevRule(rule string) []string {
+	var fields []string
+	var quoted, escaped bool
+
+	start := 0
+	for i, r := range rule {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			fields = append(fields, rule[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(fields, rule[start:])
+}
+
+func validateScheduledJobs(os *image.OperatingSystem) []FailedValidation {
+	var failures []FailedValidation
+
+	seenNames := make(map[string]bool)
+	var cronJobs []string
+
+	for _, job := range os.ScheduledJobs {
+		switch {
+		case job.Name == "":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/scheduledJobs/name",
+				UserMessage: "The 'name' field is required for each entry in the 'scheduledJobs' section.",
+			})
+		case !scheduledJobNameRegex.MatchString(job.Name):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/scheduledJobs/name",
+				UserMessage: fmt.Sprintf("Scheduled job name '%s' may only contain letters, digits, '-' and '_', and must start with a letter or digit.", job.Name),
+			})
+		case seenNames[job.Name]:
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/scheduledJobs/name",
+				UserMessage: fmt.Sprintf("Scheduled job name '%s' is defined more than once.", job.Name),
+			})
+		}
+		seenNames[job.Name] = true
+
+		if strings.TrimSpace(job.Command) == "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/scheduledJobs/command",
+				UserMessage: fmt.Sprintf("The 'command' field is required for scheduled job '%s'.", job.Name),
+			})
+		}
+
+		if job.Backend != "" && !slices.Contains(scheduledJobBackends, job.Backend) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/scheduledJobs/backend",
+				UserMessage: fmt.Sprintf("The 'backend' of scheduled job '%s' must be one of: %s.", job.Name, strings.Join(scheduledJobBackends, ", ")),
+			})
+			continue
+		}
+
+		switch {
+		case job.Schedule == "":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/scheduledJobs/schedule",
+				UserMessage: fmt.Sprintf("The 'schedule' field is required for scheduled job '%s'.", job.Name),
+			})
+		case job.Backend == image.ScheduledJobBackendCron:
+			if !isCronSchedule(job.Schedule) {
+				failures = append(failures, FailedValidation{
+					Field: "operatingSystem/scheduledJobs/schedule",
+					UserMessage: fmt.Sprintf("The 'schedule' '%s' of scheduled job '%s' is not a valid crontab schedule (e.g. '@daily' or '*/15 * * * *').",
+						job.Schedule, job.Name),
+				})
+			}
+		case !isCalendarEvent(job.Schedule):
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/scheduledJobs/schedule",
+				UserMessage: fmt.Sprintf("The 'schedule' '%s' of scheduled job '%s' is not a valid systemd calendar expression (e.g. 'daily' or 'Mon..Fri *-*-* 03:00').",
+					job.Schedule, job.Name),
+			})
+		}
+
+		if job.Backend == image.ScheduledJobBackendCron {
+			cronJobs = append(cronJobs, job.Name)
+		}
+	}
+
+	if len(cronJobs) > 0 && !slices.Contains(os.Packages.PKGList, "cronie") {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/scheduledJobs/backend",
+			UserMessage: fmt.Sprintf("Scheduled jobs [%s] use the 'cron' backend, which requires a cron daemon to be present in the image "+
+				"(e.g. by adding 'cronie' to the 'packageList').", strings.Join(cronJobs, ", ")),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
+
+// isCronSchedule checks the five time and date fields of a crontab entry, see crontab(5)
+func isCronSchedule(schedule string) bool {
+	if slices.Contains(cronShorthands, strings.ToLower(strings.TrimSpace(schedule))) {
+		return true
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return false
+	}
+
+	for _, field := range fields {
+		if !cronFieldRegex.MatchString(field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateRelease checks that the labels can be written to /etc/eib-release as os-release style assignments,
+// which are parsed by shells as well as os-release(5) readers, without any quoting or escaping.
+func validateRelease(release *image.Release) []FailedValidation {
+	var failures []FailedValidation
+
+	names := make([]string, 0, len(release.Labels))
+	for name := range release.Labels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !releaseLabelNameRegex.MatchString(name) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/release/labels",
+				UserMessage: fmt.Sprintf("Release label '%s' may only contain uppercase letters, digits and '_', and must start with a letter.", name),
+			})
+		}
+
+		if strings.ContainsAny(release.Labels[name], "\"\\$`") || strings.ContainsFunc(release.Labels[name], unicode.IsControl) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/release/labels",
+				UserMessage: fmt.Sprintf("The value of release label '%s' cannot contain double quotes, backslashes, '$', '`' or control characters.", name),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateRemove(paths []string, allowCritical bool) []FailedValidation {
+	var failures []FailedValidation
+
+	seenPaths := make(map[string]bool)
+
+	for _, p := range paths {
+		switch {
+		case p == "":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/remove",
+				UserMessage: "Entries in the 'remove' list cannot be empty.",
+			})
+			continue
+		case !filepath.IsAbs(p):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/remove",
+				UserMessage: fmt.Sprintf("Path '%s' in the 'remove' list must be absolute.", p),
+			})
+			continue
+		case filepath.Clean(p) != p:
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/remove",
+				UserMessage: fmt.Sprintf("Path '%s' in the 'remove' list must be normalized, without trailing slashes or '.' and '..' elements (e.g. '%s').",
+					p, filepath.Clean(p)),
+			})
+			continue
+		case p == "/":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/remove",
+				UserMessage: "The root directory cannot be removed.",
+			})
+			continue
+		case strings.ContainsRune(p, '\'') || strings.ContainsFunc(p, unicode.IsControl):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/remove",
+				UserMessage: fmt.Sprintf("Path %q in the 'remove' list cannot contain single quotes or control characters.", p),
+			})
+			continue
+		case seenPaths[p]:
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/remove",
+				UserMessage: fmt.Sprintf("Path '%s' is listed more than once in the 'remove' list.", p),
+			})
+			continue
+		}
+		seenPaths[p] = true
+
+		if critical := containedCriticalPath(p); critical != "" {
+			msg := fmt.Sprintf("Path '%s' in the 'remove' list is a critical system path.", p)
+			if critical != p {
+				msg = fmt.Sprintf("Path '%s' in the 'remove' list contains the critical system path '%s'.", p, critical)
+			}
+
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/remove",
+				UserMessage: msg + " Removing it may leave the node unable to boot or be configured.",
+				Warning:     allowCritical,
+			})
+		}
+	}
+
+	return failures
+}
+
+// containedCriticalPath returns the critical path which is either the given path or located under it.
+func containedCriticalPath(p string) string {
+	for _, critical := range criticalPaths {
+		if critical == p || strings.HasPrefix(critical, p+"/") {
+			return critical
+		}
+	}
+
+	return ""
+}
+
+func validateDirectories(os *image.OperatingSystem) []FailedValidation {
+	if len(os.Directories) == 0 {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	// Only the users and groups created by the build are known, any other account has to be given by its ID
+	users := map[string]bool{"root": true}
+	groups := map[string]bool{"root": true}
+	for _, group := range os.Groups {
+		groups[group.Name] = true
+	}
+	for _, user := range os.Users {
+		users[user.Username] = true
+		if user.PrimaryGroup != "" {
+			groups[user.PrimaryGroup] = true
+		}
+		for _, group := range user.SecondaryGroups {
+			groups[group] = true
+		}
+	}
+
+	seenPaths := make(map[string]bool)
+
+	for _, directory := range os.Directories {
+		p := directory.Path
+
+		switch {
+		case p == "":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/directories/path",
+				UserMessage: "The 'path' field is required for each entry in 'directories'.",
+			})
+		case !filepath.IsAbs(p):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/directories/path",
+				UserMessage: fmt.Sprintf("Directory '%s' must be an absolute path.", p),
+			})
+		case filepath.Clean(p) != p || p == "/":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/directories/path",
+				UserMessage: fmt.Sprintf("Directory '%s' must be a normalized path below the root directory, without trailing slashes or '.' and '..' elements.", p),
+			})
+		case strings.ContainsRune(p, '\'') || strings.ContainsFunc(p, unicode.IsControl):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/directories/path",
+				UserMessage: fmt.Sprintf("Directory %q cannot contain single quotes or control characters.", p),
+			})
+		case seenPaths[p]:
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/directories/path",
+				UserMessage: fmt.Sprintf("Directory '%s' is defined more than once in 'directories'.", p),
+			})
+		}
+		seenPaths[p] = true
+
+		if directory.Owner != "" && !users[directory.Owner] && !isNumericID(directory.Owner) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/directories/owner",
+				UserMessage: fmt.Sprintf("Owner '%s' of directory '%s' must be 'root', a user defined under 'users' or a numeric user ID.", directory.Owner, p),
+			})
+		}
+
+		if directory.Group != "" && !groups[directory.Group] && !isNumericID(directory.Group) {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/directories/group",
+				UserMessage: fmt.Sprintf("Group '%s' of directory '%s' must be 'root', a group defined under 'groups' or assigned to a user, or a numeric group ID.",
+					directory.Group, p),
+			})
+		}
+
+		if directory.Mode != "" && !directoryModeRegex.MatchString(directory.Mode) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/directories/mode",
+				UserMessage: fmt.Sprintf("Mode '%s' of directory '%s' must be an octal permission mode (e.g. '0750').", directory.Mode, p),
+			})
+		}
+	}
+
+	return failures
+}
+
+func isNumericID(id string) bool {
+	_, err := strconv.ParseUint(id, 10, 32)
+	return err == nil
+}
+
+func validateEnvironment(variables map[string]image.EnvironmentVariable) []FailedValidation {
+	var failures []FailedValidation
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !environmentVariableNameRegex.MatchString(name) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/environment",
+				UserMessage: fmt.Sprintf("Environment variable '%s' may only contain letters, digits and '_', and cannot start with a digit.", name),
+			})
+		}
+
+		// /etc/environment is read by pam_env, but also by shells and as systemd EnvironmentFile, which differ
+		// in how they treat escape sequences and expansions
+		if value := variables[name].Value; strings.ContainsAny(value, "\"\\$`") || strings.ContainsFunc(value, unicode.IsControl) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/environment/value",
+				UserMessage: fmt.Sprintf("The value of environment variable '%s' cannot contain double quotes, backslashes, '$', '`' or control characters.", name),
+			})
+		}
+	}
+
+	return failures
+}
+
+var dnsOverTLSModes = []string{image.DNSOverTLSYes, image.DNSOverTLSOpportunistic, image.DNSOverTLSNo}
+
+func validateDNS(dns *image.DNS, networkd *image.Networkd, imageConfigDir string) []FailedValidation {
+	if !combustion.IsDNSConfigured(dns) {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if len(dns.Servers) == 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dns/servers",
+			UserMessage: "At least one entry in 'servers' is required when configuring 'dns'.",
+		})
+	}
+
+	if dns.DNSOverTLS != "" && !slices.Contains(dnsOverTLSModes, dns.DNSOverTLS) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/dns/dnsOverTLS",
+			UserMessage: fmt.Sprintf("The DNS 'dnsOverTLS' value '%s' is invalid, it must be one of: %s.", dns.DNSOverTLS, strings.Join(dnsOverTLSModes, ", ")),
+		})
+	}
+
+	strictTLS := dns.DNSOverTLS == "" || dns.DNSOverTLS == image.DNSOverTLSYes
+	seenServers := make(map[string]bool)
+
+	for i, server := range slices.Concat(dns.Servers, dns.FallbackServers) {
+		serversField := "operatingSystem/dns/servers"
+		if i >= len(dns.Servers) {
+			serversField = "operatingSystem/dns/fallbackServers"
+		}
+
+		serverName, err := parseDNSServer(server)
+		if err != nil {
+			failures = append(failures, FailedValidation{
+				Field:       serversField,
+				UserMessage: fmt.Sprintf("DNS server '%s' must be an IP address with an optional port and TLS server name (e.g. '9.9.9.9:853#dns.quad9.net').", server),
+				Error:       err,
+			})
+			continue
+		}
+
+		if seenServers[server] {
+			failures = append(failures, FailedValidation{
+				Field:       serversField,
+				UserMessage: fmt.Sprintf("DNS server '%s' is listed more than once in 'servers' and 'fallbackServers'.", server),
+			})
+		}
+		seenServers[server] = true
+
+		if strictTLS && serverName == "" {
+			failures = append(failures, FailedValidation{
+				Field: serversField,
+				UserMessage: fmt.Sprintf("DNS server '%s' does not specify a TLS server name, which is recommended with 'dnsOverTLS: yes' "+
+					"to validate the certificate of the server (e.g. '9.9.9.9#dns.quad9.net').", server),
+				Warning: true,
+			})
+		}
+	}
+
+	failures = append(failures, validateDNSConflicts(networkd, imageConfigDir)...)
+
+	return failures
+}
+
+// parseDNSServer checks a server given as ADDRESS[:PORT][#NAME], with IPv6 addresses in brackets when a port
+// is specified, and returns its TLS server name.
+func parseDNSServer(server string) (string, error) {
+	address, serverName, _ := strings.Cut(server, "#")
+
+	if _, err := netip.ParseAddrPort(address); err != nil {
+		if _, err = netip.ParseAddr(address); err != nil {
+			return "", fmt.Errorf("parsing address: %w", err)
+		}
+	}
+
+	if serverName != "" && (len(serverName) > 253 || !hostnameRegex.MatchString(serverName)) {
+		return "", fmt.Errorf("invalid server name: %s", serverName)
+	}
+
+	return serverName, nil
+}
+
+// validateDNSConflicts fails for network configurations setting their own DNS servers, which would either be
+// ignored or bypass the servers of the 'dns' section.
+func validateDNSConflicts(networkd *image.Networkd, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	networkDir := filepath.Join(imageConfigDir, combustion.NetworkConfigDir)
+	entries, _ := os.ReadDir(networkDir)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(networkDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var config map[string]any
+		if err = yaml.Unmarshal(data, &config); err != nil {
+			// Invalid configurations are reported when the network configuration is generated
+			continue
+		}
+
+		if _, ok := config["dns-resolver"]; ok {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dns",
+				UserMessage: fmt.Sprintf("The nmstate configuration file '%s' configures a 'dns-resolver', which conflicts with the 'dns' section.", entry.Name()),
+			})
+		}
+	}
+
+	for _, configFile := range networkd.ConfigFiles {
+		data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.NetworkdConfigDir, configFile))
+		if err != nil {
+			// Missing files are reported by the networkd validation
+			continue
+		}
+
+		if networkdDNSRegex.Match(data) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/dns",
+				UserMessage: fmt.Sprintf("The networkd configuration file '%s' sets 'DNS=', which conflicts with the 'dns' section.", configFile),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateMachineID(machineID *image.MachineID) []FailedValidation {
+	var set int
+	for _, isSet := range []bool{machineID.Clear, machineID.Value != "", machineID.Seed != ""} {
+		if isSet {
+			set++
+		}
+	}
+
+	if set > 1 {
+		return []FailedValidation{{
+			Field:       "operatingSystem/machineID",
+			UserMessage: "Only one of 'clear', 'value' or 'seed' may be specified in 'machineID'.",
+		}}
+	}
+
+	var failures []FailedValidation
+
+	if machineID.Value != "" && (!machineIDRegex.MatchString(machineID.Value) || strings.Trim(machineID.Value, "0") == "") {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/machineID/value",
+			UserMessage: fmt.Sprintf("The machine ID '%s' is invalid, it must be 32 lowercase hexadecimal characters and not all zeros.", machineID.Value),
+		})
+	}
+
+	if machineID.Value != "" || machineID.Seed != "" {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/machineID/value",
+			UserMessage: "All nodes deployed from the image share the same machine ID, which may cause conflicts (e.g. DHCP leases) " +
+				"when deploying more than one node. Use 'clear' to generate a machine ID on each node instead.",
+			Warning: true,
+		})
+	}
+
+	return failures
+}
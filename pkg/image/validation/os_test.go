@@ -1,11 +1,19 @@
 package validation
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestValidateOperatingSystem(t *testing.T) {
@@ -22,7 +30,7 @@ func TestValidateOperatingSystem(t *testing.T) {
 					ImageType: image.TypeISO,
 				},
 				OperatingSystem: image.OperatingSystem{
-					KernelArgs: []string{"foo=bar", "baz"},
+					KernelArgs: image.KernelArgs{Add: []string{"foo=bar", "baz"}},
 					Systemd: image.Systemd{
 						Enable:  []string{"runMe"},
 						Disable: []string{"dontRunMe"},
@@ -65,7 +73,7 @@ func TestValidateOperatingSystem(t *testing.T) {
 					ImageType: image.TypeRAW,
 				},
 				OperatingSystem: image.OperatingSystem{
-					KernelArgs: []string{"foo="},
+					KernelArgs: image.KernelArgs{Add: []string{"foo="}},
 					Systemd: image.Systemd{
 						Enable:  []string{"confusedUser"},
 						Disable: []string{"confusedUser"},
@@ -105,7 +113,6 @@ func TestValidateOperatingSystem(t *testing.T) {
 				"The 'host' field is required for the 'suma' section.",
 				fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO),
 				"The 'rawConfiguration/diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
-				"You cannot simultaneously configure rawConfiguration and isoConfiguration, regardless of image type.",
 			},
 		},
 	}
@@ -138,12 +145,12 @@ func TestValidateKernelArgs(t *testing.T) {
 	}{
 		`valid test`: {
 			OS: image.OperatingSystem{
-				KernelArgs: []string{"foo=bar", "baz"},
+				KernelArgs: image.KernelArgs{Add: []string{"foo=bar", "baz"}},
 			},
 		},
 		`no key`: {
 			OS: image.OperatingSystem{
-				KernelArgs: []string{"foo="},
+				KernelArgs: image.KernelArgs{Add: []string{"foo="}},
 			},
 			ExpectedFailedMessages: []string{
 				"Kernel arguments must be specified as 'key=value'.",
@@ -151,7 +158,7 @@ func TestValidateKernelArgs(t *testing.T) {
 		},
 		`no value`: {
 			OS: image.OperatingSystem{
-				KernelArgs: []string{"=bar"},
+				KernelArgs: image.KernelArgs{Add: []string{"=bar"}},
 			},
 			ExpectedFailedMessages: []string{
 				"Kernel arguments must be specified as 'key=value'.",
@@ -159,12 +166,46 @@ func TestValidateKernelArgs(t *testing.T) {
 		},
 		`duplicate key`: {
 			OS: image.OperatingSystem{
-				KernelArgs: []string{"foo=bar", "foo=wombat"},
+				KernelArgs: image.KernelArgs{Add: []string{"foo=bar", "foo=wombat"}},
 			},
 			ExpectedFailedMessages: []string{
 				"Duplicate kernel argument found: foo",
 			},
 		},
+		`valid removals`: {
+			OS: image.OperatingSystem{
+				KernelArgs: image.KernelArgs{
+					Add:    []string{"console=ttyS1"},
+					Remove: []string{"console", "quiet", "splash=silent"},
+				},
+			},
+		},
+		`invalid removals`: {
+			OS: image.OperatingSystem{
+				KernelArgs: image.KernelArgs{
+					Remove: []string{"", "foo bar", "it's", "=bar", "quiet", "quiet"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The kernel argument removal '' must not be empty or contain whitespace or quotes.",
+				"The kernel argument removal 'foo bar' must not be empty or contain whitespace or quotes.",
+				"The kernel argument removal 'it's' must not be empty or contain whitespace or quotes.",
+				"The kernel argument removal '=bar' must be specified as 'key' or 'key=value'.",
+				"Duplicate kernel argument removal found: quiet",
+			},
+		},
+		`added and removed`: {
+			OS: image.OperatingSystem{
+				KernelArgs: image.KernelArgs{
+					Add:    []string{"quiet", "console=tty0"},
+					Remove: []string{"quiet", "console=tty0"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The kernel argument 'quiet' cannot be both added and removed.",
+				"The kernel argument 'console=tty0' cannot be both added and removed.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -219,6 +260,19 @@ func TestValidateSystemd(t *testing.T) {
 				"Systemd conflict found, 'bar' is both enabled and disabled.",
 			},
 		},
+		`valid default target`: {
+			Systemd: image.Systemd{
+				DefaultTarget: "multi-user.target",
+			},
+		},
+		`invalid default target`: {
+			Systemd: image.Systemd{
+				DefaultTarget: "multi-user",
+			},
+			ExpectedFailedMessages: []string{
+				"Systemd default target 'multi-user' is not valid; it must be one of: multi-user.target, graphical.target, rescue.target, emergency.target.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -315,6 +369,7 @@ func TestValidateGroups(t *testing.T) {
 func TestValidateUsers(t *testing.T) {
 	tests := map[string]struct {
 		Users                  []image.OperatingSystemUser
+		Groups                 []image.OperatingSystemGroup
 		ExpectedFailedMessages []string
 	}{
 		`no users`: {
@@ -376,12 +431,71 @@ func TestValidateUsers(t *testing.T) {
 				"The 'createHomeDir' attribute must be set to 'true' if at least one SSH key is specified.",
 			},
 		},
+		`valid shell, home and groups`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "svc",
+					EncryptedPassword: "foo",
+					Shell:             "/usr/sbin/nologin",
+					HomeDir:           "/var/lib/svc",
+					PrimaryGroup:      "svc",
+					SecondaryGroups:   []string{"wheel", "systemd-journal"},
+				},
+			},
+			Groups: []image.OperatingSystemGroup{
+				{Name: "svc"},
+			},
+		},
+		`invalid shell and home`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "svc",
+					EncryptedPassword: "foo",
+					Shell:             "bash",
+					HomeDir:           "/var/lib/../svc",
+				},
+				{
+					Username:          "fish",
+					EncryptedPassword: "foo",
+					Shell:             "/usr/bin/fish",
+					HomeDir:           "/",
+				},
+				{
+					Username:          "root",
+					EncryptedPassword: "foo",
+					Shell:             "/bin/zsh",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The shell 'bash' of user 'svc' must be an absolute path.",
+				"The home directory '/var/lib/../svc' of user 'svc' must be an absolute path.",
+				"The shell '/usr/bin/fish' of user 'fish' is not a commonly available shell; make sure it is installed in the image.",
+				"The home directory '/' of user 'fish' must be an absolute path.",
+				"The 'shell' and 'homeDir' fields cannot be set for the root user.",
+			},
+		},
+		`unknown and duplicate groups`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "svc",
+					EncryptedPassword: "foo",
+					PrimaryGroup:      "missing",
+					SecondaryGroups:   []string{"missing", "users", "other", "other"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The group 'other' is listed more than once in the secondary groups of user 'svc'.",
+				"The group 'missing' of user 'svc' is neither defined under 'groups' nor a default group of the operating system.",
+				"The group 'other' of user 'svc' is neither defined under 'groups' nor a default group of the operating system.",
+			},
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			os := image.OperatingSystem{
-				Users: test.Users,
+				Users:  test.Users,
+				Groups: test.Groups,
 			}
 			failures := validateUsers(&os)
 			assert.Len(t, failures, len(test.ExpectedFailedMessages))
@@ -495,6 +609,23 @@ func TestPackages(t *testing.T) {
 				"The 'packageList' field contains duplicate packages: foo, bar",
 			},
 		},
+		`versioned package`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo=1.2.3", "bar-devel"},
+				RegCode: "regcode",
+			},
+		},
+		`invalid packages`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo bar", "baz>=1.0", "qux;reboot"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'packageList' field contains an invalid package: 'foo bar'. Entries must be package names, optionally followed by '=' and a version, and cannot contain whitespace or shell metacharacters.",
+				"The 'packageList' field contains an invalid package: 'baz>=1.0'. Entries must be package names, optionally followed by '=' and a version, and cannot contain whitespace or shell metacharacters.",
+				"The 'packageList' field contains an invalid package: 'qux;reboot'. Entries must be package names, optionally followed by '=' and a version, and cannot contain whitespace or shell metacharacters.",
+			},
+		},
 		`duplicate repos`: {
 			Packages: image.Packages{
 				AdditionalRepos: []image.AddRepo{
@@ -528,6 +659,47 @@ func TestPackages(t *testing.T) {
 				"The 'url' field is required for all entries under 'additionalRepos'.",
 			},
 		},
+		`valid locks`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo"},
+				Locks:   []string{"kernel-default", "kernel-firmware-*"},
+				Exclude: []string{"bar"},
+			},
+		},
+		`invalid lock names`: {
+			Packages: image.Packages{
+				Locks:   []string{"-kernel", "kernel default"},
+				Exclude: []string{""},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'locks' field contains an invalid package name: '-kernel'",
+				"The 'locks' field contains an invalid package name: 'kernel default'",
+				"The 'exclude' field contains an invalid package name: ''",
+			},
+		},
+		`duplicate locks`: {
+			Packages: image.Packages{
+				Locks:   []string{"foo", "foo"},
+				Exclude: []string{"bar", "bar"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'locks' field contains duplicate packages: foo",
+				"The 'exclude' field contains duplicate packages: bar",
+			},
+		},
+		`lock conflicts`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo", "bar"},
+				Locks:   []string{"foo", "baz"},
+				Exclude: []string{"bar", "baz"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"Package conflict found, 'bar' is both listed in 'packageList' and excluded.",
+				"Package conflict found, 'baz' is both locked and excluded.",
+				"Package conflict found, 'foo' is both listed in 'packageList' and locked.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -778,3 +950,1744 @@ func TestValidateTimeSync(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBootValidation(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-boot-validation-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	require.NoError(t, os.WriteFile(filepath.Join(imageConfigDir, "valid.sh"), []byte("#!/bin/bash\nexit 0\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(imageConfigDir, "no-shebang.sh"), []byte("exit 0\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(imageConfigDir, "empty.sh"), []byte{}, 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(imageConfigDir, "dir"), os.ModePerm))
+
+	tests := map[string]struct {
+		BootValidation         image.BootValidation
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			BootValidation: image.BootValidation{
+				Script:        "valid.sh",
+				IgnoreFailure: true,
+			},
+		},
+		`ignore failure without script`: {
+			BootValidation: image.BootValidation{
+				IgnoreFailure: true,
+			},
+			ExpectedFailedMessages: []string{
+				"The 'ignoreFailure' field within 'bootValidation' requires a 'script' to be specified.",
+			},
+		},
+		`outside config dir`: {
+			BootValidation: image.BootValidation{
+				Script: "../valid.sh",
+			},
+			ExpectedFailedMessages: []string{
+				"The boot validation 'script' field '../valid.sh' must be a relative path within the image configuration directory.",
+			},
+		},
+		`missing`: {
+			BootValidation: image.BootValidation{
+				Script: "missing.sh",
+			},
+			ExpectedFailedMessages: []string{
+				"The boot validation script 'missing.sh' could not be read.",
+			},
+		},
+		`directory`: {
+			BootValidation: image.BootValidation{
+				Script: "dir",
+			},
+			ExpectedFailedMessages: []string{
+				"The boot validation script 'dir' must be a regular file.",
+			},
+		},
+		`no interpreter line`: {
+			BootValidation: image.BootValidation{
+				Script: "no-shebang.sh",
+			},
+			ExpectedFailedMessages: []string{
+				"The boot validation script 'no-shebang.sh' must start with an interpreter line (e.g. '#!/bin/bash').",
+			},
+		},
+		`empty`: {
+			BootValidation: image.BootValidation{
+				Script: "empty.sh",
+			},
+			ExpectedFailedMessages: []string{
+				"The boot validation script 'empty.sh' must start with an interpreter line (e.g. '#!/bin/bash').",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bv := test.BootValidation
+			failures := validateBootValidation(&bv, imageConfigDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateSSHD(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-sshd-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	sshdDir := filepath.Join(imageConfigDir, "sshd")
+	require.NoError(t, os.Mkdir(sshdDir, os.ModePerm))
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(ed25519Key, "")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(sshdDir, "ssh_host_ed25519_key"), pem.EncodeToMemory(block), 0o600))
+
+	block, err = ssh.MarshalPrivateKeyWithPassphrase(ed25519Key, "", []byte("secret"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(sshdDir, "encrypted_key"), pem.EncodeToMemory(block), 0o600))
+
+	require.NoError(t, os.WriteFile(filepath.Join(sshdDir, "invalid_key"), []byte("not a key"), 0o600))
+
+	tests := map[string]struct {
+		SSHD                   image.SSHD
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			SSHD: image.SSHD{
+				AllowedKeyTypes: []string{"ssh-ed25519", "rsa-sha2-512"},
+				HostKeys:        []string{"ssh_host_ed25519_key"},
+				Options: map[string]string{
+					"PasswordAuthentication": "no",
+					"MaxAuthTries":           "3",
+				},
+			},
+		},
+		`invalid and duplicate key types`: {
+			SSHD: image.SSHD{
+				AllowedKeyTypes: []string{"ssh-dss", "ssh-ed25519", "ssh-ed25519"},
+			},
+			ExpectedFailedMessages: []string{
+				"The sshd 'allowedKeyTypes' entry 'ssh-dss' is not a valid key type.",
+				"The sshd 'allowedKeyTypes' entry 'ssh-ed25519' is specified more than once.",
+			},
+		},
+		`invalid host keys`: {
+			SSHD: image.SSHD{
+				HostKeys: []string{
+					"../ssh_host_ed25519_key",
+					"ssh_host_ed25519_key.pub",
+					"missing_key",
+					"encrypted_key",
+					"invalid_key",
+					"invalid_key",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The sshd 'hostKeys' entry '../ssh_host_ed25519_key' must be the name of a private key file in the 'sshd' directory.",
+				"The sshd 'hostKeys' entry 'ssh_host_ed25519_key.pub' must be the name of a private key file in the 'sshd' directory.",
+				"The sshd host key 'missing_key' could not be read.",
+				"The sshd host key 'encrypted_key' must not be protected by a passphrase.",
+				"The sshd host key 'invalid_key' is not a valid private key.",
+				"The sshd host key 'invalid_key' is not a valid private key.",
+				"The sshd 'hostKeys' entry 'invalid_key' is specified more than once.",
+			},
+		},
+		`host key type not allowed`: {
+			SSHD: image.SSHD{
+				AllowedKeyTypes: []string{"rsa-sha2-512"},
+				HostKeys:        []string{"ssh_host_ed25519_key"},
+			},
+			ExpectedFailedMessages: []string{
+				"The sshd host key 'ssh_host_ed25519_key' of type 'ssh-ed25519' is not permitted by 'allowedKeyTypes'.",
+			},
+		},
+		`invalid options`: {
+			SSHD: image.SSHD{
+				Options: map[string]string{
+					"Not-A-Keyword":   "yes",
+					"HostKey":         "/etc/ssh/other",
+					"Match":           "User root",
+					"Banner":          "line1\nline2",
+					"PermitRootLogin": " ",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The sshd option 'Not-A-Keyword' is not a valid sshd keyword.",
+				"The sshd option 'HostKey' cannot be set directly; use the 'hostKeys' field instead.",
+				"The sshd option 'Match' is not supported.",
+				"The sshd option 'Banner' must have a single line, non-empty value.",
+				"The sshd option 'PermitRootLogin' must have a single line, non-empty value.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sshd := test.SSHD
+			failures := validateSSHD(&sshd, imageConfigDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateAudit(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-audit-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	auditDir := filepath.Join(imageConfigDir, "audit")
+	require.NoError(t, os.Mkdir(auditDir, os.ModePerm))
+
+	validRules := "# Identity\n-D\n-b 8192\n-w /etc/passwd -p wa -k identity\n\n-a always,exit -F arch=b64 -S adjtimex -k time-change\n-e 2\n"
+	require.NoError(t, os.WriteFile(filepath.Join(auditDir, "30-valid.rules"), []byte(validRules), 0o600))
+
+	malformedRules := "-w etc/shadow -p wa\n# comment\nw /etc/group\n-a always\n-x foo\n-b\n"
+	require.NoError(t, os.WriteFile(filepath.Join(auditDir, "40-malformed.rules"), []byte(malformedRules), 0o600))
+
+	tests := map[string]struct {
+		Audit                  image.Audit
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {},
+		`valid`: {
+			Audit: image.Audit{
+				RuleFiles: []string{"30-valid.rules"},
+				Rules:     []string{"-w /etc/shadow -p wa -k shadow", "-a exit,always -F arch=b64 -S mount"},
+			},
+		},
+		`invalid rule files`: {
+			Audit: image.Audit{
+				RuleFiles: []string{"../30-valid.rules", "30-valid.conf", "missing.rules", "50-eib.rules", "30-valid.rules", "30-valid.rules"},
+			},
+			ExpectedFailedMessages: []string{
+				"The audit 'ruleFiles' entry '../30-valid.rules' must be the name of a '.rules' file in the 'audit' directory.",
+				"The audit 'ruleFiles' entry '30-valid.conf' must be the name of a '.rules' file in the 'audit' directory.",
+				"The audit rule file 'missing.rules' could not be read.",
+				"The audit rule file name '50-eib.rules' is reserved for the inline audit rules.",
+				"The audit 'ruleFiles' entry '30-valid.rules' is specified more than once.",
+			},
+		},
+		`malformed rule file`: {
+			Audit: image.Audit{
+				RuleFiles: []string{"40-malformed.rules"},
+			},
+			ExpectedFailedMessages: []string{
+				"The audit rule on line 1 of '40-malformed.rules' appears to be malformed: the option '-w' requires an absolute path.",
+				"The audit rule on line 3 of '40-malformed.rules' appears to be malformed: it must start with an auditctl option.",
+				"The audit rule on line 4 of '40-malformed.rules' appears to be malformed: the option '-a' requires an action and a list (e.g. 'always,exit').",
+				"The audit rule on line 5 of '40-malformed.rules' appears to be malformed: the option '-x' is not recognized.",
+				"The audit rule on line 6 of '40-malformed.rules' appears to be malformed: the option '-b' requires a value.",
+			},
+			ExpectedWarnings: 5,
+		},
+		`invalid inline rules`: {
+			Audit: image.Audit{
+				Rules: []string{"", "-D\n-e 2", "-w -p wa"},
+			},
+			ExpectedFailedMessages: []string{
+				"Audit rules must be single line and non-empty.",
+				"Audit rules must be single line and non-empty.",
+				"The audit rule '-w -p wa' appears to be malformed: the option '-w' requires a value.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			audit := test.Audit
+			failures := validateAudit(&audit, imageConfigDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateAppArmor(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-apparmor-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	apparmorDir := filepath.Join(imageConfigDir, "apparmor")
+	require.NoError(t, os.Mkdir(apparmorDir, os.ModePerm))
+
+	profiles := map[string]string{
+		"usr.bin.workload": "#include <tunables/global>\n\n/usr/bin/workload flags=(complain) {\n  #include <abstractions/base>\n  /etc/workload/** r,\n  ^hat {\n  }\n}\n",
+		"named":            "abi <abi/3.0>,\nprofile named /opt/named/bin/* {\n  capability net_bind_service,\n}\n",
+		"unbalanced":       "profile unbalanced {\n  /etc/** r,\n",
+		"closing":          "}\nprofile closing {\n}\n",
+		"no-profile":       "# Only tunables\n@{HOME}=/home/*/\n",
+	}
+	for name, contents := range profiles {
+		require.NoError(t, os.WriteFile(filepath.Join(apparmorDir, name), []byte(contents), 0o600))
+	}
+
+	tests := map[string]struct {
+		AppArmor               image.AppArmor
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			AppArmor: image.AppArmor{
+				Profiles: []string{"usr.bin.workload", "named"},
+			},
+		},
+		`invalid entries`: {
+			AppArmor: image.AppArmor{
+				Profiles: []string{"../named", "missing", "named", "named"},
+			},
+			ExpectedFailedMessages: []string{
+				"The apparmor 'profiles' entry '../named' must be the name of a file in the 'apparmor' directory.",
+				"The AppArmor profile 'missing' could not be read.",
+				"The apparmor 'profiles' entry 'named' is specified more than once.",
+			},
+		},
+		`invalid syntax`: {
+			AppArmor: image.AppArmor{
+				Profiles: []string{"unbalanced", "closing", "no-profile"},
+			},
+			ExpectedFailedMessages: []string{
+				"The AppArmor profile 'unbalanced' has unbalanced braces.",
+				"The AppArmor profile 'closing' does not define a profile.",
+				"The AppArmor profile 'closing' has unbalanced braces.",
+				"The AppArmor profile 'no-profile' does not define a profile.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			apparmor := test.AppArmor
+			failures := validateAppArmor(&apparmor, imageConfigDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkd(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-networkd-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	networkdDir := filepath.Join(imageConfigDir, "networkd")
+	require.NoError(t, os.Mkdir(networkdDir, os.ModePerm))
+
+	configFiles := map[string]string{
+		"10-eth0.network": "[Match]\nName=eth0\n\n[Network]\nDHCP=yes\n",
+		"20-br0.netdev":   "[NetDev]\nName=br0\nKind=bridge\n",
+		"30-any.network":  "[Network]\nDHCP=yes\n",
+		"40-bond0.netdev": "[Match]\nName=bond0\n",
+		"50-eth1.link":    "[Match]\nOriginalName=eth1\n",
+	}
+	for name, contents := range configFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(networkdDir, name), []byte(contents), 0o600))
+	}
+
+	tests := map[string]struct {
+		Networkd               image.Networkd
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {},
+		`valid`: {
+			Networkd: image.Networkd{
+				ConfigFiles: []string{"10-eth0.network", "20-br0.netdev"},
+			},
+		},
+		`invalid entries`: {
+			Networkd: image.Networkd{
+				ConfigFiles: []string{"../10-eth0.network", "missing.network", "50-eth1.link", "20-br0.netdev", "20-br0.netdev"},
+			},
+			ExpectedFailedMessages: []string{
+				"The networkd 'configFiles' entry '../10-eth0.network' must be the name of a file in the 'networkd' directory.",
+				"The networkd configuration file 'missing.network' could not be read.",
+				"The networkd 'configFiles' entry '50-eth1.link' must have either the '.network' or the '.netdev' extension.",
+				"The networkd 'configFiles' entry '20-br0.netdev' is specified more than once.",
+			},
+		},
+		`missing sections`: {
+			Networkd: image.Networkd{
+				ConfigFiles: []string{"30-any.network", "40-bond0.netdev"},
+			},
+			ExpectedFailedMessages: []string{
+				"The networkd configuration file '30-any.network' does not contain a '[Match]' section and applies to every interface.",
+				"The networkd configuration file '40-bond0.netdev' does not contain a '[NetDev]' section.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			networkd := test.Networkd
+			failures := validateNetworkd(&networkd, imageConfigDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidatePodman(t *testing.T) {
+	digested := "registry.example.com/app@sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {},
+		`valid`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Podman: image.Podman{
+						Images: []image.ContainerImage{
+							{Name: "registry.example.com/workloads/app:1.0"},
+							{Name: "nginx"},
+						},
+					},
+				},
+			},
+		},
+		`invalid images`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Podman: image.Podman{
+						Images: []image.ContainerImage{
+							{Name: ""},
+							{Name: "nginx"},
+							{Name: "nginx"},
+							{Name: "Invalid/App:1.0"},
+							{Name: digested},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'name' field is required for each entry in the podman 'images'.",
+				"Duplicate image name 'nginx' found in the podman 'images'.",
+				"The podman image 'Invalid/App:1.0' is not a valid image reference.",
+				"The podman image '" + digested + "' must be referenced by tag rather than by digest.",
+			},
+		},
+		`combined with kubernetes`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Podman: image.Podman{
+						Images: []image.ContainerImage{
+							{Name: "nginx"},
+						},
+					},
+				},
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "nginx"},
+					},
+				},
+				Kubernetes: image.Kubernetes{
+					Version: "v1.30.3+k3s1",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The podman 'images' are not available to Kubernetes, which uses its own container runtime. " +
+					"Images used by Kubernetes workloads should be listed under 'embeddedArtifactRegistry' instead.",
+				"The podman image 'nginx' is also listed under 'embeddedArtifactRegistry' and will be embedded twice.",
+			},
+			ExpectedWarnings: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			failures := validatePodman(&def)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateImageArchives(t *testing.T) {
+	tests := map[string]struct {
+		ImageArchives          image.ImageArchives
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			ImageArchives: image.ImageArchives{
+				Path: "/var/lib/images",
+				Images: []image.ContainerImage{
+					{Name: "registry.example.com/workloads/app:1.0"},
+					{Name: "nginx"},
+				},
+			},
+		},
+		`missing images`: {
+			ImageArchives: image.ImageArchives{
+				Path: "/var/lib/images",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'images' field is required when configuring the 'imageArchives' section.",
+			},
+		},
+		`missing path`: {
+			ImageArchives: image.ImageArchives{
+				Images: []image.ContainerImage{
+					{Name: "nginx"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field is required when configuring the 'imageArchives' section.",
+			},
+		},
+		`relative path`: {
+			ImageArchives: image.ImageArchives{
+				Path: "var/lib/images",
+				Images: []image.ContainerImage{
+					{Name: "nginx"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field 'var/lib/images' in the 'imageArchives' section must be an absolute path to a directory other than '/'.",
+			},
+		},
+		`root path`: {
+			ImageArchives: image.ImageArchives{
+				Path: "/",
+				Images: []image.ContainerImage{
+					{Name: "nginx"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field '/' in the 'imageArchives' section must be an absolute path to a directory other than '/'.",
+			},
+		},
+		`unsafe path`: {
+			ImageArchives: image.ImageArchives{
+				Path: "/var/lib/o'images",
+				Images: []image.ContainerImage{
+					{Name: "nginx"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field \"/var/lib/o'images\" in the 'imageArchives' section cannot contain single quotes or control characters.",
+			},
+		},
+		`invalid images`: {
+			ImageArchives: image.ImageArchives{
+				Path: "/var/lib/images",
+				Images: []image.ContainerImage{
+					{Name: ""},
+					{Name: "nginx"},
+					{Name: "nginx"},
+					{Name: "Invalid/App:1.0"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'name' field is required for each entry in the archived 'images'.",
+				"Duplicate image name 'nginx' found in the archived 'images'.",
+				"The archived image 'Invalid/App:1.0' is not a valid image reference.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			archives := test.ImageArchives
+			failures := validateImageArchives(&archives)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateAutoUpdate(t *testing.T) {
+	tests := map[string]struct {
+		AutoUpdate             image.AutoUpdate
+		SystemdDisable         []string
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`enabled with defaults`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true},
+		},
+		`shorthand schedule`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, Schedule: "weekly", RebootPolicy: "maint_window"},
+		},
+		`full calendar schedule`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, Schedule: "Mon..Fri *-*-* 03:00:00 UTC", RebootPolicy: "instantly"},
+		},
+		`time only schedule`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, Schedule: "*:0/15"},
+		},
+		`date only schedule`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, Schedule: "*-*-01,15"},
+		},
+		`weekday list schedule`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, Schedule: "Sat,Sun 02:30"},
+		},
+		`reboot policy only`: {
+			AutoUpdate: image.AutoUpdate{RebootPolicy: "off"},
+		},
+		`invalid schedule`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, Schedule: "every night"},
+			ExpectedFailedMessages: []string{
+				"The autoUpdate 'schedule' 'every night' is not a valid systemd calendar expression (e.g. 'daily' or 'Mon..Fri *-*-* 03:00').",
+			},
+		},
+		`schedule without enabled`: {
+			AutoUpdate: image.AutoUpdate{Schedule: "daily"},
+			ExpectedFailedMessages: []string{
+				"The autoUpdate 'schedule' field can only be used when 'enabled' is set.",
+			},
+		},
+		`invalid reboot policy`: {
+			AutoUpdate: image.AutoUpdate{Enabled: true, RebootPolicy: "sometimes"},
+			ExpectedFailedMessages: []string{
+				"The autoUpdate 'rebootPolicy' must be one of: best-effort, instantly, maint_window, off.",
+			},
+		},
+		`timer disabled`: {
+			AutoUpdate:     image.AutoUpdate{Enabled: true},
+			SystemdDisable: []string{"transactional-update.timer"},
+			ExpectedFailedMessages: []string{
+				"Automatic updates are enabled, but 'transactional-update.timer' is disabled in the 'systemd' section.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				AutoUpdate: test.AutoUpdate,
+				Systemd: image.Systemd{
+					Disable: test.SystemdDisable,
+				},
+			}
+			failures := validateAutoUpdate(&os)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateHosts(t *testing.T) {
+	tests := map[string]struct {
+		Hosts                  []image.HostEntry
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			Hosts: []image.HostEntry{
+				{IP: "192.168.1.10", Hostnames: []string{"registry.local", "registry"}},
+				{IP: "fd00::10", Hostnames: []string{"mirror.local"}},
+				{IP: "192.168.1.10", Hostnames: []string{"registry.local"}},
+			},
+		},
+		`missing fields`: {
+			Hosts: []image.HostEntry{
+				{Hostnames: []string{"registry.local"}},
+				{IP: "192.168.1.10"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'ip' field is required for each entry in the 'hosts' section.",
+				"The 'hosts' entry for '192.168.1.10' must list at least one hostname.",
+			},
+		},
+		`invalid values`: {
+			Hosts: []image.HostEntry{
+				{IP: "192.168.1.300", Hostnames: []string{"registry.local"}},
+				{IP: "192.168.1.11", Hostnames: []string{"-registry", "under_score.local"}},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'hosts' entry IP address '192.168.1.300' is not a valid IPv4 or IPv6 address.",
+				"The 'hosts' hostname '-registry' is not a valid hostname.",
+				"The 'hosts' hostname 'under_score.local' is not a valid hostname.",
+			},
+		},
+		`conflicting mappings`: {
+			Hosts: []image.HostEntry{
+				{IP: "192.168.1.10", Hostnames: []string{"registry.local"}},
+				{IP: "192.168.1.11", Hostnames: []string{"Registry.local"}},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'hosts' hostname 'Registry.local' is mapped to multiple IP addresses: 192.168.1.10, 192.168.1.11.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateHosts(test.Hosts)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateUserSudo(t *testing.T) {
+	tests := map[string]struct {
+		User                   image.OperatingSystemUser
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {
+			User: image.OperatingSystemUser{Username: "ops"},
+		},
+		`all without password`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				Sudo:     image.UserSudo{Policy: image.SudoPolicyAll, NoPassword: true},
+			},
+		},
+		`commands`: {
+			User: image.OperatingSystemUser{
+				Username:          "ops",
+				EncryptedPassword: "$6$salt$hash",
+				Sudo: image.UserSudo{
+					Policy:   image.SudoPolicyCommands,
+					Commands: []string{"/usr/bin/systemctl restart k3s", "/usr/bin/journalctl"},
+				},
+			},
+		},
+		`none`: {
+			User: image.OperatingSystemUser{
+				Username: "guest",
+				Sudo:     image.UserSudo{Policy: image.SudoPolicyNone},
+			},
+		},
+		`invalid policy`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				Sudo:     image.UserSudo{Policy: "some"},
+			},
+			ExpectedFailedMessages: []string{
+				"The sudo 'policy' of user 'ops' must be one of: none, all, commands.",
+			},
+		},
+		`missing policy`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				Sudo:     image.UserSudo{NoPassword: true},
+			},
+			ExpectedFailedMessages: []string{
+				"The sudo 'policy' of user 'ops' must be set when configuring 'commands' or 'noPassword'.",
+			},
+		},
+		`conflicting fields`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				Sudo:     image.UserSudo{Policy: image.SudoPolicyNone, NoPassword: true},
+			},
+			ExpectedFailedMessages: []string{
+				"The sudo 'commands' and 'noPassword' fields of user 'ops' conflict with the 'none' policy.",
+			},
+		},
+		`commands with all`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				Sudo:     image.UserSudo{Policy: image.SudoPolicyAll, NoPassword: true, Commands: []string{"/usr/bin/ls"}},
+			},
+			ExpectedFailedMessages: []string{
+				"The sudo 'commands' of user 'ops' conflict with the 'all' policy, which grants all commands.",
+			},
+		},
+		`malformed commands`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				Sudo: image.UserSudo{
+					Policy:     image.SudoPolicyCommands,
+					NoPassword: true,
+					Commands:   []string{"systemctl", "/usr/bin/env A=B", "/usr/bin/ls\nops ALL=(ALL) ALL", "/usr/bin/id", "/usr/bin/id"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The sudo command 'systemctl' of user 'ops' must start with the absolute path of the executable.",
+				"The sudo command '/usr/bin/env A=B' of user 'ops' must not contain any of the characters ',:=\\'.",
+				"The sudo command \"/usr/bin/ls\\nops ALL=(ALL) ALL\" of user 'ops' must not contain control characters such as newlines.",
+				"The sudo command '/usr/bin/id' is listed more than once for user 'ops'.",
+			},
+		},
+		`missing commands`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				Sudo:     image.UserSudo{Policy: image.SudoPolicyCommands, NoPassword: true},
+			},
+			ExpectedFailedMessages: []string{
+				"The sudo 'commands' of user 'ops' must be provided when using the 'commands' policy.",
+			},
+		},
+		`root`: {
+			User: image.OperatingSystemUser{
+				Username: "root",
+				Sudo:     image.UserSudo{Policy: image.SudoPolicyAll},
+			},
+			ExpectedFailedMessages: []string{
+				"The sudo 'policy' cannot be set for the root user, which already has unrestricted privileges.",
+			},
+		},
+		`password required without password`: {
+			User: image.OperatingSystemUser{
+				Username: "ops",
+				SSHKeys:  []string{"ssh-ed25519 key"},
+				Sudo:     image.UserSudo{Policy: image.SudoPolicyAll},
+			},
+			ExpectedFailedMessages: []string{
+				"User 'ops' has no password, so the sudo privileges requiring one cannot be used; consider setting 'noPassword'.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			user := test.User
+			failures := validateUserSudo(&user)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateUserPasswordExpiry(t *testing.T) {
+	tests := map[string]struct {
+		User                   image.OperatingSystemUser
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {
+			User: image.OperatingSystemUser{Username: "ops", SSHKeys: []string{"ssh-ed25519 key"}},
+		},
+		`valid`: {
+			User: image.OperatingSystemUser{
+				Username:          "ops",
+				EncryptedPassword: "$6$salt$hash",
+				PasswordExpire:    90,
+				ForceChange:       true,
+			},
+		},
+		`negative expiry`: {
+			User: image.OperatingSystemUser{
+				Username:          "ops",
+				EncryptedPassword: "$6$salt$hash",
+				PasswordExpire:    -1,
+			},
+			ExpectedFailedMessages: []string{
+				"The 'passwordExpire' field of user 'ops' must be a positive number of days.",
+			},
+		},
+		`no password`: {
+			User: image.OperatingSystemUser{
+				Username:       "ops",
+				SSHKeys:        []string{"ssh-ed25519 key"},
+				PasswordExpire: 90,
+			},
+			ExpectedFailedMessages: []string{
+				"The 'passwordExpire' and 'forceChange' fields of user 'ops' require a password, " +
+					"otherwise the user is locked out once the password expires.",
+			},
+		},
+		`forced change without login`: {
+			User: image.OperatingSystemUser{
+				Username:          "svc",
+				EncryptedPassword: "$6$salt$hash",
+				Shell:             "/usr/sbin/nologin",
+				ForceChange:       true,
+			},
+			ExpectedFailedMessages: []string{
+				"User 'svc' is forced to change the password on first login, but the shell '/usr/sbin/nologin' " +
+					"does not allow logging in interactively.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			user := test.User
+			failures := validateUserPasswordExpiry(&user)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateJournald(t *testing.T) {
+	tests := map[string]struct {
+		Journald               image.Journald
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {},
+		`valid`: {
+			Journald: image.Journald{
+				Storage:           "persistent",
+				SystemMaxUse:      "1G",
+				SystemMaxFileSize: "128M",
+				RuntimeMaxUse:     "52428800",
+				MaxRetention:      "2w 3d",
+			},
+		},
+		`invalid values`: {
+			Journald: image.Journald{
+				Storage:       "disk",
+				SystemMaxUse:  "1GB",
+				RuntimeMaxUse: "-5M",
+				MaxRetention:  "forever",
+			},
+			ExpectedFailedMessages: []string{
+				"The journald 'storage' must be one of: volatile, persistent, auto, none.",
+				"The journald 'systemMaxUse' value '1GB' must be a size in bytes, optionally followed by one of the units K, M, G, T, P or E (e.g. '500M').",
+				"The journald 'runtimeMaxUse' value '-5M' must be a size in bytes, optionally followed by one of the units K, M, G, T, P or E (e.g. '500M').",
+				"The journald 'maxRetention' value 'forever' must be a systemd time span (e.g. '1month' or '2w').",
+			},
+		},
+		`file size exceeding max use`: {
+			Journald: image.Journald{
+				SystemMaxUse:      "512M",
+				SystemMaxFileSize: "1G",
+			},
+			ExpectedFailedMessages: []string{
+				"The journald 'systemMaxFileSize' '1G' exceeds the 'systemMaxUse' '512M'.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`system limits with volatile storage`: {
+			Journald: image.Journald{
+				Storage:      "volatile",
+				SystemMaxUse: "1G",
+			},
+			ExpectedFailedMessages: []string{
+				"The journald 'systemMaxUse' and 'systemMaxFileSize' settings have no effect with the 'volatile' storage.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			journald := test.Journald
+			failures := validateJournald(&journald)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateUdev(t *testing.T) {
+	imageConfigDir, err := os.MkdirTemp("", "eib-udev-tests-")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(imageConfigDir)
+	}()
+
+	udevDir := filepath.Join(imageConfigDir, "udev")
+	require.NoError(t, os.Mkdir(udevDir, os.ModePerm))
+
+	rules := map[string]string{
+		"70-net.rules": "# Persistent interface names\n\n" +
+			`SUBSYSTEM=="net", ACTION=="add", ATTR{address}=="52:54:00:12:34:56", NAME="lan0"` + "\n" +
+			`SUBSYSTEM=="block", KERNEL=="sd*", \` + "\n" +
+			`  ENV{ID_SERIAL}=="disk\"one", SYMLINK+="data",` + "\n",
+		"80-broken.rules": `SUBSYSTEM=="net"` + "\n" +
+			`ACTION=="add" NAME="lan1"` + "\n" +
+			`KERNEL=sd*` + "\n",
+		"notes.txt": `SUBSYSTEM=="net"` + "\n",
+	}
+	for name, contents := range rules {
+		require.NoError(t, os.WriteFile(filepath.Join(udevDir, name), []byte(contents), 0o600))
+	}
+
+	tests := map[string]struct {
+		Udev                   image.Udev
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			Udev: image.Udev{
+				Rules: []string{"70-net.rules"},
+			},
+		},
+		`invalid entries`: {
+			Udev: image.Udev{
+				Rules: []string{"../70-net.rules", "missing.rules", "notes.txt", "70-net.rules", "70-net.rules"},
+			},
+			ExpectedFailedMessages: []string{
+				"The udev 'rules' entry '../70-net.rules' must be the name of a file in the 'udev' directory.",
+				"The udev rule file 'missing.rules' could not be read.",
+				"The udev rule file 'notes.txt' must have the '.rules' extension, as other files are ignored by udev.",
+				"The udev 'rules' entry '70-net.rules' is specified more than once.",
+			},
+		},
+		`invalid syntax`: {
+			Udev: image.Udev{
+				Rules: []string{"80-broken.rules"},
+			},
+			ExpectedFailedMessages: []string{
+				"Line 2 of the udev rule file '80-broken.rules' is not a valid udev rule.",
+				"Line 3 of the udev rule file '80-broken.rules' is not a valid udev rule.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			udev := test.Udev
+			failures := validateUdev(&udev, imageConfigDir)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateScheduledJobs(t *testing.T) {
+	tests := map[string]struct {
+		OS                     image.OperatingSystem
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {
+			OS: image.OperatingSystem{},
+		},
+		`valid`: {
+			OS: image.OperatingSystem{
+				ScheduledJobs: []image.ScheduledJob{
+					{
+						Name:     "prune-images",
+						Schedule: "Sun *-*-* 03:00",
+						Command:  "podman image prune --all --force",
+					},
+					{
+						Name:     "trim",
+						Schedule: "weekly",
+						Command:  "fstrim --all",
+						Backend:  image.ScheduledJobBackendTimer,
+					},
+					{
+						Name:     "rotate_exports",
+						Schedule: "*/15 9-17 * * mon-fri",
+						Command:  "find /var/exports -mtime +7 -delete",
+						Backend:  image.ScheduledJobBackendCron,
+					},
+					{
+						Name:     "report",
+						Schedule: "@daily",
+						Command:  "/usr/local/bin/report",
+						Backend:  image.ScheduledJobBackendCron,
+					},
+				},
+				Packages: image.Packages{
+					PKGList: []string{"cronie"},
+				},
+			},
+		},
+		`missing fields`: {
+			OS: image.OperatingSystem{
+				ScheduledJobs: []image.ScheduledJob{
+					{},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'name' field is required for each entry in the 'scheduledJobs' section.",
+				"The 'command' field is required for scheduled job ''.",
+				"The 'schedule' field is required for scheduled job ''.",
+			},
+		},
+		`invalid and duplicate names`: {
+			OS: image.OperatingSystem{
+				ScheduledJobs: []image.ScheduledJob{
+					{
+						Name:     "-backup",
+						Schedule: "daily",
+						Command:  "backup",
+					},
+					{
+						Name:     "backup job",
+						Schedule: "daily",
+						Command:  "backup",
+					},
+					{
+						Name:     "cleanup",
+						Schedule: "daily",
+						Command:  "cleanup",
+					},
+					{
+						Name:     "cleanup",
+						Schedule: "hourly",
+						Command:  "cleanup",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Scheduled job name '-backup' may only contain letters, digits, '-' and '_', and must start with a letter or digit.",
+				"Scheduled job name 'backup job' may only contain letters, digits, '-' and '_', and must start with a letter or digit.",
+				"Scheduled job name 'cleanup' is defined more than once.",
+			},
+		},
+		`invalid schedules`: {
+			OS: image.OperatingSystem{
+				ScheduledJobs: []image.ScheduledJob{
+					{
+						Name:     "timer",
+						Schedule: "*/15 * * * *",
+						Command:  "true",
+					},
+					{
+						Name:     "cron",
+						Schedule: "daily",
+						Command:  "true",
+						Backend:  image.ScheduledJobBackendCron,
+					},
+					{
+						Name:     "cron-fields",
+						Schedule: "0 3 * *",
+						Command:  "true",
+						Backend:  image.ScheduledJobBackendCron,
+					},
+				},
+				Packages: image.Packages{
+					PKGList: []string{"cronie"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'schedule' '*/15 * * * *' of scheduled job 'timer' is not a valid systemd calendar expression (e.g. 'daily' or 'Mon..Fri *-*-* 03:00').",
+				"The 'schedule' 'daily' of scheduled job 'cron' is not a valid crontab schedule (e.g. '@daily' or '*/15 * * * *').",
+				"The 'schedule' '0 3 * *' of scheduled job 'cron-fields' is not a valid crontab schedule (e.g. '@daily' or '*/15 * * * *').",
+			},
+		},
+		`invalid backend`: {
+			OS: image.OperatingSystem{
+				ScheduledJobs: []image.ScheduledJob{
+					{
+						Name:     "backup",
+						Schedule: "daily",
+						Command:  "backup",
+						Backend:  "anacron",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'backend' of scheduled job 'backup' must be one of: timer, cron.",
+			},
+		},
+		`cron without cronie`: {
+			OS: image.OperatingSystem{
+				ScheduledJobs: []image.ScheduledJob{
+					{
+						Name:     "backup",
+						Schedule: "0 3 * * *",
+						Command:  "backup",
+						Backend:  image.ScheduledJobBackendCron,
+					},
+					{
+						Name:     "report",
+						Schedule: "@hourly",
+						Command:  "report",
+						Backend:  image.ScheduledJobBackendCron,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Scheduled jobs [backup, report] use the 'cron' backend, which requires a cron daemon to be present in the image " +
+					"(e.g. by adding 'cronie' to the 'packageList').",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := test.OS
+			failures := validateScheduledJobs(&os)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateRelease(t *testing.T) {
+	tests := map[string]struct {
+		Release                image.Release
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {},
+		`valid`: {
+			Release: image.Release{
+				Labels: map[string]string{
+					"SITE":      "berlin-02",
+					"CUSTOMER":  "ACME Corp.",
+					"RACK_2":    "it's the second one",
+					"EMPTY_TAG": "",
+				},
+			},
+		},
+		`invalid names`: {
+			Release: image.Release{
+				Labels: map[string]string{
+					"site":     "berlin-02",
+					"2ND_SITE": "munich",
+					"SITE-ID":  "42",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Release label '2ND_SITE' may only contain uppercase letters, digits and '_', and must start with a letter.",
+				"Release label 'SITE-ID' may only contain uppercase letters, digits and '_', and must start with a letter.",
+				"Release label 'site' may only contain uppercase letters, digits and '_', and must start with a letter.",
+			},
+		},
+		`invalid values`: {
+			Release: image.Release{
+				Labels: map[string]string{
+					"QUOTED":    `"berlin"`,
+					"VARIABLE":  "$HOME",
+					"COMMAND":   "`id`",
+					"ESCAPED":   `a\b`,
+					"MULTILINE": "first\nsecond",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The value of release label 'COMMAND' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of release label 'ESCAPED' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of release label 'MULTILINE' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of release label 'QUOTED' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of release label 'VARIABLE' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			release := test.Release
+			failures := validateRelease(&release)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateRemove(t *testing.T) {
+	tests := map[string]struct {
+		Paths                  []string
+		AllowCritical          bool
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			Paths: []string{"/usr/share/doc", "/usr/share/locale/zh_CN", "/usr/share/man/man 1"},
+		},
+		`invalid paths`: {
+			Paths: []string{"", "usr/share/doc", "/usr/share/doc/", "/usr/share/../lib", "/", "/usr/share/it's", "/usr/share/doc", "/usr/share/doc"},
+			ExpectedFailedMessages: []string{
+				"Entries in the 'remove' list cannot be empty.",
+				"Path 'usr/share/doc' in the 'remove' list must be absolute.",
+				"Path '/usr/share/doc/' in the 'remove' list must be normalized, without trailing slashes or '.' and '..' elements (e.g. '/usr/share/doc').",
+				"Path '/usr/share/../lib' in the 'remove' list must be normalized, without trailing slashes or '.' and '..' elements (e.g. '/usr/lib').",
+				"The root directory cannot be removed.",
+				"Path \"/usr/share/it's\" in the 'remove' list cannot contain single quotes or control characters.",
+				"Path '/usr/share/doc' is listed more than once in the 'remove' list.",
+			},
+		},
+		`critical paths`: {
+			Paths: []string{"/etc", "/usr/lib", "/boot/grub2/themes"},
+			ExpectedFailedMessages: []string{
+				"Path '/etc' in the 'remove' list contains the critical system path '/etc/fstab'. " +
+					"Removing it may leave the node unable to boot or be configured.",
+				"Path '/usr/lib' in the 'remove' list contains the critical system path '/usr/lib/modules'. " +
+					"Removing it may leave the node unable to boot or be configured.",
+			},
+		},
+		`allowed critical paths`: {
+			Paths:         []string{"/etc/systemd", "/"},
+			AllowCritical: true,
+			ExpectedFailedMessages: []string{
+				"Path '/etc/systemd' in the 'remove' list is a critical system path. Removing it may leave the node unable to boot or be configured.",
+				"The root directory cannot be removed.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateRemove(test.Paths, test.AllowCritical)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateDirectories(t *testing.T) {
+	tests := map[string]struct {
+		OS                     image.OperatingSystem
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {},
+		`valid`: {
+			OS: image.OperatingSystem{
+				Groups: []image.OperatingSystemGroup{{Name: "workloads"}},
+				Users: []image.OperatingSystemUser{
+					{Username: "app", PrimaryGroup: "apps", SecondaryGroups: []string{"wheel"}},
+				},
+				Directories: []image.Directory{
+					{Path: "/var/lib/app"},
+					{Path: "/srv/data", Owner: "app", Group: "workloads", Mode: "0750"},
+					{Path: "/srv/cache", Owner: "app", Group: "apps", Mode: "2775"},
+					{Path: "/srv/shared", Owner: "1000", Group: "wheel", Mode: "755"},
+					{Path: "/opt/root", Owner: "root", Group: "65534"},
+				},
+			},
+		},
+		`invalid paths`: {
+			OS: image.OperatingSystem{
+				Directories: []image.Directory{
+					{Path: ""},
+					{Path: "srv/data"},
+					{Path: "/srv/data/"},
+					{Path: "/"},
+					{Path: "/srv/it's"},
+					{Path: "/srv/data"},
+					{Path: "/srv/data"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field is required for each entry in 'directories'.",
+				"Directory 'srv/data' must be an absolute path.",
+				"Directory '/srv/data/' must be a normalized path below the root directory, without trailing slashes or '.' and '..' elements.",
+				"Directory '/' must be a normalized path below the root directory, without trailing slashes or '.' and '..' elements.",
+				"Directory \"/srv/it's\" cannot contain single quotes or control characters.",
+				"Directory '/srv/data' is defined more than once in 'directories'.",
+			},
+		},
+		`invalid ownership and mode`: {
+			OS: image.OperatingSystem{
+				Users: []image.OperatingSystemUser{{Username: "app"}},
+				Groups: []image.OperatingSystemGroup{
+					{Name: "workloads"},
+				},
+				Directories: []image.Directory{
+					{Path: "/srv/data", Owner: "nobody", Group: "app", Mode: "0800"},
+					{Path: "/srv/cache", Owner: "-1", Group: "$(id)", Mode: "rwxr-x---"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Owner 'nobody' of directory '/srv/data' must be 'root', a user defined under 'users' or a numeric user ID.",
+				"Group 'app' of directory '/srv/data' must be 'root', a group defined under 'groups' or assigned to a user, or a numeric group ID.",
+				"Mode '0800' of directory '/srv/data' must be an octal permission mode (e.g. '0750').",
+				"Owner '-1' of directory '/srv/cache' must be 'root', a user defined under 'users' or a numeric user ID.",
+				"Group '$(id)' of directory '/srv/cache' must be 'root', a group defined under 'groups' or assigned to a user, or a numeric group ID.",
+				"Mode 'rwxr-x---' of directory '/srv/cache' must be an octal permission mode (e.g. '0750').",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := test.OS
+			failures := validateDirectories(&os)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	tests := map[string]struct {
+		Environment            map[string]image.EnvironmentVariable
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {},
+		`valid`: {
+			Environment: map[string]image.EnvironmentVariable{
+				"AGENT_URL":   {Value: "https://agent.example.com:8443/path?a=b&c='d'"},
+				"_token":      {Value: "s3cr3t", Sensitive: true},
+				"EMPTY_VALUE": {},
+			},
+		},
+		`invalid names`: {
+			Environment: map[string]image.EnvironmentVariable{
+				"1AGENT":    {Value: "a"},
+				"AGENT-URL": {Value: "b"},
+				"AGENT URL": {Value: "c"},
+			},
+			ExpectedFailedMessages: []string{
+				"Environment variable '1AGENT' may only contain letters, digits and '_', and cannot start with a digit.",
+				"Environment variable 'AGENT URL' may only contain letters, digits and '_', and cannot start with a digit.",
+				"Environment variable 'AGENT-URL' may only contain letters, digits and '_', and cannot start with a digit.",
+			},
+		},
+		`invalid values`: {
+			Environment: map[string]image.EnvironmentVariable{
+				"QUOTE":     {Value: `say "hi"`},
+				"BACKSLASH": {Value: `C:\path`},
+				"EXPANSION": {Value: "$HOME/bin"},
+				"COMMAND":   {Value: "`id`"},
+				"NEWLINE":   {Value: "a\nb"},
+			},
+			ExpectedFailedMessages: []string{
+				"The value of environment variable 'BACKSLASH' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of environment variable 'COMMAND' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of environment variable 'EXPANSION' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of environment variable 'NEWLINE' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"The value of environment variable 'QUOTE' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateEnvironment(test.Environment)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateDNS(t *testing.T) {
+	configDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, combustion.NetworkConfigDir), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, combustion.NetworkConfigDir, "node1.yaml"),
+		[]byte("dns-resolver:\n  config:\n    server:\n      - 192.168.100.1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, combustion.NetworkConfigDir, "node2.yaml"),
+		[]byte("interfaces:\n  - name: eth0\n    type: ethernet\n"), 0o600))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, combustion.NetworkdConfigDir), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, combustion.NetworkdConfigDir, "10-static.network"),
+		[]byte("[Match]\nName=eth0\n\n[Network]\nAddress=192.168.1.10/24\nDNS=192.168.1.1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, combustion.NetworkdConfigDir, "20-dhcp.network"),
+		[]byte("[Match]\nName=eth1\n\n[Network]\nDHCP=yes\n"), 0o600))
+
+	tests := map[string]struct {
+		DNS                    image.DNS
+		Networkd               image.Networkd
+		ConfigDir              string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {
+			ConfigDir: configDir,
+		},
+		`valid`: {
+			DNS: image.DNS{
+				Servers:         []string{"9.9.9.9#dns.quad9.net", "[2620:fe::fe]:853#dns.quad9.net", "1.1.1.1:853#one.one.one.one"},
+				FallbackServers: []string{"2001:4860:4860::8888#dns.google"},
+			},
+			Networkd: image.Networkd{ConfigFiles: []string{"20-dhcp.network"}},
+		},
+		`opportunistic without server names`: {
+			DNS: image.DNS{
+				Servers:    []string{"9.9.9.9"},
+				DNSOverTLS: image.DNSOverTLSOpportunistic,
+			},
+		},
+		`strict without server names`: {
+			DNS: image.DNS{
+				Servers: []string{"9.9.9.9", "1.1.1.1#cloudflare-dns.com"},
+			},
+			ExpectedFailedMessages: []string{
+				"DNS server '9.9.9.9' does not specify a TLS server name, which is recommended with 'dnsOverTLS: yes' " +
+					"to validate the certificate of the server (e.g. '9.9.9.9#dns.quad9.net').",
+			},
+			ExpectedWarnings: 1,
+		},
+		`invalid servers`: {
+			DNS: image.DNS{
+				FallbackServers: []string{"dns.quad9.net", "9.9.9.9:99999#dns.quad9.net", "9.9.9.9#dns_quad9", "8.8.8.8#dns.google", "8.8.8.8#dns.google"},
+				DNSOverTLS:      "strict",
+			},
+			ExpectedFailedMessages: []string{
+				"At least one entry in 'servers' is required when configuring 'dns'.",
+				"The DNS 'dnsOverTLS' value 'strict' is invalid, it must be one of: yes, opportunistic, no.",
+				"DNS server 'dns.quad9.net' must be an IP address with an optional port and TLS server name (e.g. '9.9.9.9:853#dns.quad9.net').",
+				"DNS server '9.9.9.9:99999#dns.quad9.net' must be an IP address with an optional port and TLS server name (e.g. '9.9.9.9:853#dns.quad9.net').",
+				"DNS server '9.9.9.9#dns_quad9' must be an IP address with an optional port and TLS server name (e.g. '9.9.9.9:853#dns.quad9.net').",
+				"DNS server '8.8.8.8#dns.google' is listed more than once in 'servers' and 'fallbackServers'.",
+			},
+		},
+		`conflicting network configuration`: {
+			DNS: image.DNS{
+				Servers: []string{"9.9.9.9#dns.quad9.net"},
+			},
+			Networkd:  image.Networkd{ConfigFiles: []string{"10-static.network", "20-dhcp.network", "missing.network"}},
+			ConfigDir: configDir,
+			ExpectedFailedMessages: []string{
+				"The nmstate configuration file 'node1.yaml' configures a 'dns-resolver', which conflicts with the 'dns' section.",
+				"The networkd configuration file '10-static.network' sets 'DNS=', which conflicts with the 'dns' section.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := test.ConfigDir
+			if dir == "" {
+				dir = t.TempDir()
+			}
+
+			failures := validateDNS(&test.DNS, &test.Networkd, dir)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateMachineID(t *testing.T) {
+	sharedWarning := "All nodes deployed from the image share the same machine ID, which may cause conflicts (e.g. DHCP leases) " +
+		"when deploying more than one node. Use 'clear' to generate a machine ID on each node instead."
+
+	tests := map[string]struct {
+		MachineID              image.MachineID
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`clear`: {
+			MachineID: image.MachineID{Clear: true},
+		},
+		`value`: {
+			MachineID:              image.MachineID{Value: "4c4c4544003910528052b4c04f4e5432"},
+			ExpectedFailedMessages: []string{sharedWarning},
+			ExpectedWarnings:       1,
+		},
+		`seed`: {
+			MachineID:              image.MachineID{Seed: "edge-fleet"},
+			ExpectedFailedMessages: []string{sharedWarning},
+			ExpectedWarnings:       1,
+		},
+		`mutually exclusive`: {
+			MachineID: image.MachineID{Clear: true, Seed: "edge-fleet"},
+			ExpectedFailedMessages: []string{
+				"Only one of 'clear', 'value' or 'seed' may be specified in 'machineID'.",
+			},
+		},
+		`invalid value`: {
+			MachineID: image.MachineID{Value: "4C4C4544-0039-1052-8052-B4C04F4E5432"},
+			ExpectedFailedMessages: []string{
+				"The machine ID '4C4C4544-0039-1052-8052-B4C04F4E5432' is invalid, it must be 32 lowercase hexadecimal characters and not all zeros.",
+				sharedWarning,
+			},
+			ExpectedWarnings: 1,
+		},
+		`all zeros`: {
+			MachineID: image.MachineID{Value: "00000000000000000000000000000000"},
+			ExpectedFailedMessages: []string{
+				"The machine ID '00000000000000000000000000000000' is invalid, it must be 32 lowercase hexadecimal characters and not all zeros.",
+				sharedWarning,
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateMachineID(&test.MachineID)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// criticalPAMStacks are the services and shared stacks through which users log in to the node, which can
+// lock them out if misconfigured
+var criticalPAMStacks = []string{
+	"common-account", "common-auth", "common-password", "common-session",
+	"login", "other", "passwd", "sshd", "su", "su-l", "sudo", "systemd-user",
+}
+
+var (
+	pamModuleTypes = []string{"account", "auth", "password", "session"}
+	pamControls    = []string{"required", "requisite", "sufficient", "optional", "include", "substack"}
+)
+
+func validatePAM(pam *image.PAM, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, duplicate := range findDuplicates(pam.ConfigFiles) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/pam/configFiles",
+			UserMessage: fmt.Sprintf("The pam 'configFiles' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	for _, configFile := range pam.ConfigFiles {
+		failures = append(failures, validatePAMConfigFile(configFile, imageConfigDir)...)
+	}
+
+	return failures
+}
+
+func validatePAMConfigFile(configFile, imageConfigDir string) []FailedValidation {
+	if !filepath.IsLocal(configFile) || filepath.Base(configFile) != configFile {
+		return []FailedValidation{{
+			Field:       "operatingSystem/pam/configFiles",
+			UserMessage: fmt.Sprintf("The pam 'configFiles' entry '%s' must be the name of a file in the '%s' directory.", configFile, combustion.PAMConfigDir),
+		}}
+	}
+
+	data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.PAMConfigDir, configFile))
+	if err != nil {
+		return []FailedValidation{{
+			Field:       "operatingSystem/pam/configFiles",
+			UserMessage: fmt.Sprintf("The PAM configuration file '%s' could not be read.", configFile),
+			Error:       err,
+		}}
+	}
+
+	var failures []FailedValidation
+
+	for i, line := range strings.Split(string(data), "\n") {
+		if !isValidPAMRule(line) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/pam/configFiles",
+				UserMessage: fmt.Sprintf("Line %d of the PAM configuration file '%s' is not a valid PAM rule.", i+1, configFile),
+			})
+		}
+	}
+
+	if slices.Contains(criticalPAMStacks, configFile) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/pam/configFiles",
+			UserMessage: fmt.Sprintf("The PAM configuration file '%s' replaces a stack used to log in to the node, "+
+				"a mistake in which can lock all users out.", configFile),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
+
+// isValidPAMRule checks the rule consists of a module type, a control and a module, or includes another
+// configuration file. Comments and empty lines are valid, while the existence of the modules is only checked
+// by PAM on the node.
+func isValidPAMRule(rule string) bool {
+	rule = strings.TrimSpace(rule)
+	if rule == "" || strings.HasPrefix(rule, "#") {
+		return true
+	}
+
+	fields := strings.Fields(rule)
+
+	if fields[0] == "@include" {
+		return len(fields) == 2
+	}
+
+	// A leading dash silences the errors of missing modules
+	if !slices.Contains(pamModuleTypes, strings.TrimPrefix(fields[0], "-")) {
+		return false
+	}
+
+	// Controls given as '[value=action ...]' may contain whitespace
+	if rest := strings.TrimSpace(strings.TrimPrefix(rule, fields[0])); strings.HasPrefix(rest, "[") {
+		_, module, found := strings.Cut(rest, "]")
+		return found && strings.TrimSpace(module) != ""
+	}
+
+	return len(fields) >= 3 && slices.Contains(pamControls, fields[1])
+}
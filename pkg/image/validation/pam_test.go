@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidatePAM(t *testing.T) {
+	imageConfigDir := t.TempDir()
+
+	pamDir := filepath.Join(imageConfigDir, "pam")
+	require.NoError(t, os.Mkdir(pamDir, os.ModePerm))
+
+	configFiles := map[string]string{
+		"workload": "#%PAM-1.0\n\n" +
+			"auth     requisite  pam_nologin.so\n" +
+			"auth     [success=1 default=ignore]  pam_workload.so debug\n" +
+			"-auth    optional   pam_gnome_keyring.so\n" +
+			"account  include    common-account\n" +
+			"@include common-session\n",
+		"broken": "auth required\n" +
+			"login required pam_unix.so\n" +
+			"auth mandatory pam_unix.so\n" +
+			"auth [success=1 pam_unix.so\n",
+		"common-auth": "auth required pam_unix.so try_first_pass\n",
+	}
+	for name, contents := range configFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(pamDir, name), []byte(contents), 0o600))
+	}
+
+	tests := map[string]struct {
+		PAM                    image.PAM
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not configured`: {},
+		`valid`: {
+			PAM: image.PAM{
+				ConfigFiles: []string{"workload"},
+			},
+		},
+		`invalid entries`: {
+			PAM: image.PAM{
+				ConfigFiles: []string{"../workload", "missing", "workload", "workload"},
+			},
+			ExpectedFailedMessages: []string{
+				"The pam 'configFiles' entry 'workload' is specified more than once.",
+				"The pam 'configFiles' entry '../workload' must be the name of a file in the 'pam' directory.",
+				"The PAM configuration file 'missing' could not be read.",
+			},
+		},
+		`invalid syntax`: {
+			PAM: image.PAM{
+				ConfigFiles: []string{"broken"},
+			},
+			ExpectedFailedMessages: []string{
+				"Line 1 of the PAM configuration file 'broken' is not a valid PAM rule.",
+				"Line 2 of the PAM configuration file 'broken' is not a valid PAM rule.",
+				"Line 3 of the PAM configuration file 'broken' is not a valid PAM rule.",
+				"Line 4 of the PAM configuration file 'broken' is not a valid PAM rule.",
+			},
+		},
+		`critical stack`: {
+			PAM: image.PAM{
+				ConfigFiles: []string{"common-auth"},
+			},
+			ExpectedFailedMessages: []string{
+				"The PAM configuration file 'common-auth' replaces a stack used to log in to the node, a mistake in which can lock all users out.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pam := test.PAM
+			failures := validatePAM(&pam, imageConfigDir)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// writableRootPaths are kept writable on a read-only root file system, either as separately mounted subvolumes,
+// as the overlay of /etc or as pseudo and temporary file systems mounted at runtime
+var writableRootPaths = []string{
+	"/boot", "/dev", "/etc", "/home", "/opt", "/proc", "/root", "/run", "/srv", "/sys", "/tmp", "/usr/local", "/var",
+}
+
+var overlaySizeRegex = regexp.MustCompile(`^([1-9][0-9]*[kKmMgG]?|([1-9][0-9]?|100)%)$`)
+
+func validateReadOnlyRoot(os *image.OperatingSystem) []FailedValidation {
+	readOnlyRoot := &os.ReadOnlyRoot
+
+	var failures []FailedValidation
+
+	if !readOnlyRoot.Enabled {
+		if len(readOnlyRoot.Overlays) > 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/readOnlyRoot/enabled",
+				UserMessage: "The 'overlays' field in the 'readOnlyRoot' section requires 'enabled' to be set.",
+			})
+		}
+
+		return failures
+	}
+
+	var overlayPaths []string
+
+	for _, overlay := range readOnlyRoot.Overlays {
+		p := overlay.Path
+
+		switch {
+		case p == "":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/readOnlyRoot/overlays/path",
+				UserMessage: "The 'path' field is required for each entry in 'overlays'.",
+			})
+		case !filepath.IsAbs(p):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/readOnlyRoot/overlays/path",
+				UserMessage: fmt.Sprintf("Overlay '%s' must be an absolute path.", p),
+			})
+		case filepath.Clean(p) != p || p == "/":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/readOnlyRoot/overlays/path",
+				UserMessage: fmt.Sprintf("Overlay '%s' must be a normalized path below the root directory, without trailing slashes or '.' and '..' elements.", p),
+			})
+		case strings.ContainsRune(p, '\'') || strings.ContainsFunc(p, unicode.IsSpace) || strings.ContainsFunc(p, unicode.IsControl):
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/readOnlyRoot/overlays/path",
+				UserMessage: fmt.Sprintf("Overlay %q cannot contain single quotes, whitespace or control characters.", p),
+			})
+		default:
+			if writable := writablePathFor(p, writableRootPaths); writable != "" {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/readOnlyRoot/overlays/path",
+					UserMessage: fmt.Sprintf("Overlay '%s' is not needed, as '%s' remains writable on a read-only root file system.", p, writable),
+				})
+			} else if other := overlapsOverlay(p, overlayPaths); other != "" {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/readOnlyRoot/overlays/path",
+					UserMessage: fmt.Sprintf("Overlay '%s' overlaps with overlay '%s'.", p, other),
+				})
+			}
+
+			overlayPaths = append(overlayPaths, p)
+		}
+
+		switch overlay.Type {
+		case image.OverlayTypeTmpfs:
+			if overlay.Size != "" && !overlaySizeRegex.MatchString(overlay.Size) {
+				failures = append(failures, FailedValidation{
+					Field: "operatingSystem/readOnlyRoot/overlays/size",
+					UserMessage: fmt.Sprintf("The 'size' of overlay '%s' must be a positive integer optionally followed by one of 'k', 'm' or 'g', "+
+						"or a percentage of the memory (e.g. '256m' or '10%%').", p),
+				})
+			}
+		case image.OverlayTypePersistent:
+			if overlay.Size != "" {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/readOnlyRoot/overlays/size",
+					UserMessage: fmt.Sprintf("The 'size' field can only be set for overlays of type '%s' (overlay '%s').", image.OverlayTypeTmpfs, p),
+				})
+			}
+		default:
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/readOnlyRoot/overlays/type",
+				UserMessage: fmt.Sprintf("The 'type' of overlay '%s' must be either '%s' or '%s'.", p, image.OverlayTypeTmpfs, image.OverlayTypePersistent),
+			})
+		}
+	}
+
+	// Directories created by the build can still be populated, but nothing outside the writable paths can be
+	// written to them at runtime
+	for _, directory := range os.Directories {
+		if directory.Path == "" || !filepath.IsAbs(directory.Path) {
+			continue
+		}
+
+		if writablePathFor(directory.Path, writableRootPaths) == "" && writablePathFor(directory.Path, overlayPaths) == "" {
+			failures = append(failures, FailedValidation{
+				Field: "operatingSystem/directories/path",
+				UserMessage: fmt.Sprintf("Directory '%s' will be read-only at runtime, as it is neither below a writable path nor covered by an overlay.",
+					directory.Path),
+				Warning: true,
+			})
+		}
+	}
+
+	return failures
+}
+
+// writablePathFor returns the path the given one is equal to or located below, if any.
+func writablePathFor(p string, paths []string) string {
+	for _, writable := range paths {
+		if p == writable || strings.HasPrefix(p, writable+"/") {
+			return writable
+		}
+	}
+
+	return ""
+}
+
+// overlapsOverlay returns the overlay the given path is equal to, located below or a parent of, if any.
+func overlapsOverlay(p string, overlayPaths []string) string {
+	for _, other := range overlayPaths {
+		if p == other || strings.HasPrefix(p, other+"/") || strings.HasPrefix(other, p+"/") {
+			return other
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateReadOnlyRoot(t *testing.T) {
+	tests := map[string]struct {
+		ReadOnlyRoot           image.ReadOnlyRoot
+		Directories            []image.Directory
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Enabled: true,
+				Overlays: []image.Overlay{
+					{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs, Size: "256m"},
+					{Path: "/usr/lib/app/data", Type: image.OverlayTypePersistent},
+					{Path: "/usr/lib/app/spool", Type: image.OverlayTypeTmpfs, Size: "10%"},
+				},
+			},
+			Directories: []image.Directory{{Path: "/var/lib/app"}, {Path: "/usr/lib/app/data/db"}},
+		},
+		`overlays without enabled`: {
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Overlays: []image.Overlay{{Path: "/usr/lib/app", Type: image.OverlayTypeTmpfs}},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'overlays' field in the 'readOnlyRoot' section requires 'enabled' to be set.",
+			},
+		},
+		`invalid paths`: {
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Enabled: true,
+				Overlays: []image.Overlay{
+					{Type: image.OverlayTypeTmpfs},
+					{Path: "usr/lib/app", Type: image.OverlayTypeTmpfs},
+					{Path: "/usr/lib/app/", Type: image.OverlayTypeTmpfs},
+					{Path: "/", Type: image.OverlayTypeTmpfs},
+					{Path: "/usr/lib/my app", Type: image.OverlayTypeTmpfs},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'path' field is required for each entry in 'overlays'.",
+				"Overlay 'usr/lib/app' must be an absolute path.",
+				"Overlay '/usr/lib/app/' must be a normalized path below the root directory, without trailing slashes or '.' and '..' elements.",
+				"Overlay '/' must be a normalized path below the root directory, without trailing slashes or '.' and '..' elements.",
+				`Overlay "/usr/lib/my app" cannot contain single quotes, whitespace or control characters.`,
+			},
+		},
+		`writable paths`: {
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Enabled: true,
+				Overlays: []image.Overlay{
+					{Path: "/var/lib/app", Type: image.OverlayTypePersistent},
+					{Path: "/etc", Type: image.OverlayTypeTmpfs},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Overlay '/var/lib/app' is not needed, as '/var' remains writable on a read-only root file system.",
+				"Overlay '/etc' is not needed, as '/etc' remains writable on a read-only root file system.",
+			},
+		},
+		`overlapping overlays`: {
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Enabled: true,
+				Overlays: []image.Overlay{
+					{Path: "/usr/lib/app", Type: image.OverlayTypePersistent},
+					{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs},
+					{Path: "/usr/lib", Type: image.OverlayTypeTmpfs},
+					{Path: "/usr/lib/app", Type: image.OverlayTypeTmpfs},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Overlay '/usr/lib/app/cache' overlaps with overlay '/usr/lib/app'.",
+				"Overlay '/usr/lib' overlaps with overlay '/usr/lib/app'.",
+				"Overlay '/usr/lib/app' overlaps with overlay '/usr/lib/app'.",
+			},
+		},
+		`invalid types and sizes`: {
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Enabled: true,
+				Overlays: []image.Overlay{
+					{Path: "/usr/lib/a", Type: "overlay"},
+					{Path: "/usr/lib/b", Type: image.OverlayTypeTmpfs, Size: "1T"},
+					{Path: "/usr/lib/c", Type: image.OverlayTypeTmpfs, Size: "150%"},
+					{Path: "/usr/lib/d", Type: image.OverlayTypePersistent, Size: "1G"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'type' of overlay '/usr/lib/a' must be either 'tmpfs' or 'persistent'.",
+				"The 'size' of overlay '/usr/lib/b' must be a positive integer optionally followed by one of 'k', 'm' or 'g', " +
+					"or a percentage of the memory (e.g. '256m' or '10%').",
+				"The 'size' of overlay '/usr/lib/c' must be a positive integer optionally followed by one of 'k', 'm' or 'g', " +
+					"or a percentage of the memory (e.g. '256m' or '10%').",
+				"The 'size' field can only be set for overlays of type 'tmpfs' (overlay '/usr/lib/d').",
+			},
+		},
+		`uncovered directory`: {
+			ReadOnlyRoot: image.ReadOnlyRoot{Enabled: true},
+			Directories:  []image.Directory{{Path: "/usr/lib/app"}, {Path: "/srv/app"}},
+			ExpectedFailedMessages: []string{
+				"Directory '/usr/lib/app' will be read-only at runtime, as it is neither below a writable path nor covered by an overlay.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				ReadOnlyRoot: test.ReadOnlyRoot,
+				Directories:  test.Directories,
+			}
+
+			failures := validateReadOnlyRoot(&os)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
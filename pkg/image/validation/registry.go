@@ -2,7 +2,14 @@ package validation
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -14,6 +21,13 @@ func validateEmbeddedArtifactRegistry(ctx *image.Context) []FailedValidation {
 	var failures []FailedValidation
 
 	failures = append(failures, validateContainerImages(&ctx.ImageDefinition.EmbeddedArtifactRegistry)...)
+	failures = append(failures, validateRegistryMirrors(ctx)...)
+	failures = append(failures, validateRegistryPullLimits(ctx)...)
+	failures = append(failures, validateChartImageDigests(ctx)...)
+
+	if failure := validateMutableTags(ctx); failure != nil {
+		failures = append(failures, *failure)
+	}
 
 	return failures
 }
@@ -25,6 +39,7 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 	for _, cImage := range ear.ContainerImages {
 		if cImage.Name == "" {
 			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/images/name",
 				UserMessage: "The 'name' field is required for each entry in 'images'.",
 			})
 		}
@@ -32,6 +47,7 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 		if seenContainerImages[cImage.Name] {
 			msg := fmt.Sprintf("Duplicate image name '%s' found in the 'images' section.", cImage.Name)
 			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/images/name",
 				UserMessage: msg,
 			})
 		}
@@ -40,3 +56,221 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 
 	return failures
 }
+
+func validateRegistryMirrors(ctx *image.Context) []FailedValidation {
+	mirrors := ctx.ImageDefinition.EmbeddedArtifactRegistry.Mirrors
+	if len(mirrors) == 0 {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if ctx.ImageDefinition.Kubernetes.Version == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "embeddedArtifactRegistry/mirrors",
+			UserMessage: "Registry mirrors are only configured for the container runtime of Kubernetes, but no Kubernetes version is specified.",
+			Warning:     true,
+		})
+	}
+
+	embeddedRegistries := embeddedImageRegistries(ctx)
+	seenRegistries := make(map[string]bool)
+
+	for _, mirror := range mirrors {
+		switch {
+		case mirror.Registry == "":
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/mirrors/registry",
+				UserMessage: "The 'registry' field is required for each entry in 'mirrors'.",
+			})
+		case !isRegistryHost(mirror.Registry):
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/mirrors/registry",
+				UserMessage: fmt.Sprintf("Registry mirror '%s' must be a registry host name with an optional port (e.g. 'registry.example.com:5000') or '*'.", mirror.Registry),
+			})
+		case seenRegistries[mirror.Registry]:
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/mirrors/registry",
+				UserMessage: fmt.Sprintf("Registry mirror '%s' is defined more than once.", mirror.Registry),
+			})
+		}
+		seenRegistries[mirror.Registry] = true
+
+		if len(mirror.Endpoints) == 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/mirrors/endpoints",
+				UserMessage: fmt.Sprintf("Registry mirror '%s' must define at least one endpoint.", mirror.Registry),
+			})
+		}
+
+		for _, endpoint := range mirror.Endpoints {
+			parsedURL, err := url.Parse(endpoint)
+			if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+				failures = append(failures, FailedValidation{
+					Field:       "embeddedArtifactRegistry/mirrors/endpoints",
+					UserMessage: fmt.Sprintf("Endpoint '%s' of registry mirror '%s' must be an 'http' or 'https' URL.", endpoint, mirror.Registry),
+				})
+			}
+		}
+
+		for pattern := range mirror.Rewrite {
+			if _, err := regexp.Compile(pattern); err != nil {
+				failures = append(failures, FailedValidation{
+					Field:       "embeddedArtifactRegistry/mirrors/rewrite",
+					UserMessage: fmt.Sprintf("Rewrite pattern '%s' of registry mirror '%s' is not a valid regular expression.", pattern, mirror.Registry),
+					Error:       err,
+				})
+			}
+		}
+
+		// The rewrite rules apply to every endpoint of the registry, including the embedded artifact registry,
+		// which serves the images under the repository names they were embedded with
+		if len(mirror.Rewrite) > 0 && embeddedRegistries[mirror.Registry] {
+			failures = append(failures, FailedValidation{
+				Field: "embeddedArtifactRegistry/mirrors/rewrite",
+				UserMessage: fmt.Sprintf("Registry mirror '%s' cannot define rewrite rules, since images of this registry are served "+
+					"from the embedded artifact registry under their original repository names.", mirror.Registry),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateRegistryPullLimits(ctx *image.Context) []FailedValidation {
+	limits := ctx.ImageDefinition.EmbeddedArtifactRegistry.PullLimits
+	if len(limits) == 0 {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if !combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
+		failures = append(failures, FailedValidation{
+			Field:       "embeddedArtifactRegistry/pullLimits",
+			UserMessage: "Registry pull limits only apply to the images of the embedded artifact registry, but no images are embedded.",
+			Warning:     true,
+		})
+	}
+
+	seenRegistries := make(map[string]bool)
+
+	for _, limit := range limits {
+		switch {
+		case limit.Registry == "":
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/pullLimits/registry",
+				UserMessage: "The 'registry' field is required for each entry in 'pullLimits'.",
+			})
+		case !isRegistryHost(limit.Registry):
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/pullLimits/registry",
+				UserMessage: fmt.Sprintf("Pull limit registry '%s' must be a registry host name with an optional port (e.g. 'registry.example.com:5000') or '*'.", limit.Registry),
+			})
+		case seenRegistries[limit.Registry]:
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/pullLimits/registry",
+				UserMessage: fmt.Sprintf("Pull limits of registry '%s' are defined more than once.", limit.Registry),
+			})
+		}
+		seenRegistries[limit.Registry] = true
+
+		if limit.Concurrency < 0 || limit.PullsPerMinute < 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/pullLimits",
+				UserMessage: fmt.Sprintf("The 'concurrency' and 'pullsPerMinute' fields of the pull limits of registry '%s' cannot be negative.", limit.Registry),
+			})
+		} else if limit.Concurrency == 0 && limit.PullsPerMinute == 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "embeddedArtifactRegistry/pullLimits",
+				UserMessage: fmt.Sprintf("The pull limits of registry '%s' must set 'concurrency', 'pullsPerMinute' or both.", limit.Registry),
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateMutableTags reports every embedded image referenced by the mutable 'latest' tag, whether explicitly or
+// by omitting the tag. References by digest are immutable and are not reported.
+func validateMutableTags(ctx *image.Context) *FailedValidation {
+	def := ctx.ImageDefinition
+
+	var offenders []string
+	findOffenders := func(images []image.ContainerImage, section string) {
+		for i, img := range images {
+			named, err := reference.ParseNormalizedNamed(img.Name)
+			if err != nil {
+				// Invalid references are reported by the validation of their section
+				continue
+			}
+
+			if _, isDigested := named.(reference.Digested); isDigested {
+				continue
+			}
+
+			tagged, isTagged := named.(reference.Tagged)
+			if !isTagged || tagged.Tag() == "latest" {
+				offenders = append(offenders, fmt.Sprintf("'%s' (%s[%d])", img.Name, section, i))
+			}
+		}
+	}
+
+	findOffenders(def.EmbeddedArtifactRegistry.ContainerImages, "embeddedArtifactRegistry/images")
+	findOffenders(def.OperatingSystem.Podman.Images, "operatingSystem/podman/images")
+	findOffenders(def.OperatingSystem.ImageArchives.Images, "operatingSystem/imageArchives/images")
+
+	if len(offenders) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("The following images use the mutable 'latest' tag, either explicitly or by omitting the tag: %s. "+
+		"Reference them by a fixed tag or digest instead.", strings.Join(offenders, ", "))
+	if !ctx.ForbidLatestTags {
+		msg += " Use --forbid-latest-tags to fail the build on these images."
+	}
+
+	return &FailedValidation{
+		Field:       "embeddedArtifactRegistry/images/name",
+		UserMessage: msg,
+		Warning:     !ctx.ForbidLatestTags,
+	}
+}
+
+// embeddedImageRegistries returns the registries whose images are served from the embedded artifact registry on
+// the node, as far as they are known before the build: docker.io and the registries of the listed images.
+func embeddedImageRegistries(ctx *image.Context) map[string]bool {
+	registries := make(map[string]bool)
+	if !combustion.IsEmbeddedArtifactRegistryConfigured(ctx) || ctx.ImageDefinition.Kubernetes.Version == "" {
+		return registries
+	}
+
+	registries["docker.io"] = true
+	for _, containerImage := range ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages {
+		if named, err := reference.ParseNormalizedNamed(containerImage.Name); err == nil {
+			registries[reference.Domain(named)] = true
+		}
+	}
+
+	return registries
+}
+
+func isRegistryHost(registry string) bool {
+	if registry == "*" {
+		return true
+	}
+
+	host := registry
+	if h, port, err := net.SplitHostPort(registry); err == nil {
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return false
+		}
+		host = h
+	}
+
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	return len(host) <= 253 && hostnameRegex.MatchString(host)
+}
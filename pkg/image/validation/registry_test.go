@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -19,7 +20,7 @@ func TestValidateEmbeddedArtifactRegistry(t *testing.T) {
 			Registry: image.EmbeddedArtifactRegistry{
 				ContainerImages: []image.ContainerImage{
 					{
-						Name: "foo",
+						Name: "foo:1.0",
 					},
 				},
 			},
@@ -128,3 +129,317 @@ func TestValidateContainerImages(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRegistryMirrors(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`no mirrors`: {},
+		`valid`: {
+			Definition: image.Definition{
+				Kubernetes: image.Kubernetes{Version: "v1.29.0+rke2r1"},
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "nginx:1.25"},
+					},
+					Mirrors: []image.RegistryMirror{
+						{
+							Registry:  "docker.io",
+							Endpoints: []string{"https://mirror.example.com"},
+						},
+						{
+							Registry:  "registry.suse.com:5000",
+							Endpoints: []string{"https://mirror.example.com", "http://10.0.0.5:5000/v2"},
+							Rewrite:   map[string]string{"^suse/(.*)": "suse-mirror/$1"},
+						},
+						{
+							Registry:  "*",
+							Endpoints: []string{"https://mirror.example.com"},
+						},
+					},
+				},
+			},
+		},
+		`invalid`: {
+			Definition: image.Definition{
+				Kubernetes: image.Kubernetes{Version: "v1.29.0+rke2r1"},
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					Mirrors: []image.RegistryMirror{
+						{
+							Endpoints: []string{"https://mirror.example.com"},
+						},
+						{
+							Registry:  "https://quay.io",
+							Endpoints: []string{"https://mirror.example.com"},
+						},
+						{
+							Registry: "quay.io:99999",
+						},
+						{
+							Registry:  "ghcr.io",
+							Endpoints: []string{"mirror.example.com", "ftp://mirror.example.com"},
+							Rewrite:   map[string]string{"^(foo": "bar"},
+						},
+						{
+							Registry:  "ghcr.io",
+							Endpoints: []string{"https://mirror.example.com"},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'registry' field is required for each entry in 'mirrors'.",
+				"Registry mirror 'https://quay.io' must be a registry host name with an optional port (e.g. 'registry.example.com:5000') or '*'.",
+				"Registry mirror 'quay.io:99999' must be a registry host name with an optional port (e.g. 'registry.example.com:5000') or '*'.",
+				"Registry mirror 'quay.io:99999' must define at least one endpoint.",
+				"Endpoint 'mirror.example.com' of registry mirror 'ghcr.io' must be an 'http' or 'https' URL.",
+				"Endpoint 'ftp://mirror.example.com' of registry mirror 'ghcr.io' must be an 'http' or 'https' URL.",
+				"Rewrite pattern '^(foo' of registry mirror 'ghcr.io' is not a valid regular expression.",
+				"Registry mirror 'ghcr.io' is defined more than once.",
+			},
+		},
+		`rewrite of embedded images`: {
+			Definition: image.Definition{
+				Kubernetes: image.Kubernetes{Version: "v1.29.0+rke2r1"},
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "quay.io/podman/hello:latest"},
+					},
+					Mirrors: []image.RegistryMirror{
+						{
+							Registry:  "quay.io",
+							Endpoints: []string{"https://mirror.example.com"},
+							Rewrite:   map[string]string{"^podman/(.*)": "quay/podman/$1"},
+						},
+						{
+							Registry:  "docker.io",
+							Endpoints: []string{"https://mirror.example.com"},
+							Rewrite:   map[string]string{"^library/(.*)": "docker/library/$1"},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Registry mirror 'quay.io' cannot define rewrite rules, since images of this registry are served " +
+					"from the embedded artifact registry under their original repository names.",
+				"Registry mirror 'docker.io' cannot define rewrite rules, since images of this registry are served " +
+					"from the embedded artifact registry under their original repository names.",
+			},
+		},
+		`without kubernetes`: {
+			Definition: image.Definition{
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					Mirrors: []image.RegistryMirror{
+						{
+							Registry:  "docker.io",
+							Endpoints: []string{"https://mirror.example.com"},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Registry mirrors are only configured for the container runtime of Kubernetes, but no Kubernetes version is specified.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			definition := test.Definition
+			ctx := image.Context{
+				ImageConfigDir:  t.TempDir(),
+				ImageDefinition: &definition,
+			}
+
+			failures := validateRegistryMirrors(&ctx)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateRegistryPullLimits(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`no pull limits`: {},
+		`valid`: {
+			Definition: image.Definition{
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "nginx:1.25"},
+					},
+					PullLimits: []image.RegistryPullLimit{
+						{Registry: "docker.io", Concurrency: 2, PullsPerMinute: 10},
+						{Registry: "registry.suse.com:5000", Concurrency: 4},
+						{Registry: "*", PullsPerMinute: 60},
+					},
+				},
+			},
+		},
+		`invalid`: {
+			Definition: image.Definition{
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "nginx:1.25"},
+					},
+					PullLimits: []image.RegistryPullLimit{
+						{Concurrency: 1},
+						{Registry: "https://quay.io", Concurrency: 1},
+						{Registry: "ghcr.io", Concurrency: -1},
+						{Registry: "ghcr.io", PullsPerMinute: 5},
+						{Registry: "docker.io"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'registry' field is required for each entry in 'pullLimits'.",
+				"Pull limit registry 'https://quay.io' must be a registry host name with an optional port (e.g. 'registry.example.com:5000') or '*'.",
+				"The 'concurrency' and 'pullsPerMinute' fields of the pull limits of registry 'ghcr.io' cannot be negative.",
+				"Pull limits of registry 'ghcr.io' are defined more than once.",
+				"The pull limits of registry 'docker.io' must set 'concurrency', 'pullsPerMinute' or both.",
+			},
+		},
+		`without embedded images`: {
+			Definition: image.Definition{
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					PullLimits: []image.RegistryPullLimit{
+						{Registry: "docker.io", Concurrency: 2},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Registry pull limits only apply to the images of the embedded artifact registry, but no images are embedded.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			definition := test.Definition
+			ctx := image.Context{
+				ImageConfigDir:  t.TempDir(),
+				ImageDefinition: &definition,
+			}
+
+			failures := validateRegistryPullLimits(&ctx)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
+
+func TestValidateMutableTags(t *testing.T) {
+	tests := map[string]struct {
+		Definition       image.Definition
+		ForbidLatestTags bool
+		ExpectedMessage  string
+		ExpectedWarning  bool
+	}{
+		`no images`: {
+			Definition: image.Definition{},
+		},
+		`fixed tags and digests`: {
+			Definition: image.Definition{
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "nginx:1.25"},
+						{Name: "registry.suse.com/suse/sle-micro@sha256:4c4be0a2c4a4b866e0ed4f4e7b5d93656b0ec8d1a1bf7dffc44aee5b4f4bfe8b"},
+					},
+				},
+				OperatingSystem: image.OperatingSystem{
+					Podman: image.Podman{
+						Images: []image.ContainerImage{
+							{Name: "docker.io/library/busybox:1.36"},
+						},
+					},
+				},
+			},
+		},
+		`latest and untagged images`: {
+			Definition: image.Definition{
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "nginx:1.25"},
+						{Name: "nginx:latest"},
+						{Name: "registry.example.com/app"},
+						{Name: "not a reference"},
+					},
+				},
+				OperatingSystem: image.OperatingSystem{
+					Podman: image.Podman{
+						Images: []image.ContainerImage{
+							{Name: "busybox"},
+						},
+					},
+				},
+			},
+			ExpectedMessage: "The following images use the mutable 'latest' tag, either explicitly or by omitting the tag: " +
+				"'nginx:latest' (embeddedArtifactRegistry/images[1]), 'registry.example.com/app' (embeddedArtifactRegistry/images[2]), " +
+				"'busybox' (operatingSystem/podman/images[0]). Reference them by a fixed tag or digest instead. " +
+				"Use --forbid-latest-tags to fail the build on these images.",
+			ExpectedWarning: true,
+		},
+		`latest tags forbidden`: {
+			Definition: image.Definition{
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					ContainerImages: []image.ContainerImage{
+						{Name: "nginx"},
+					},
+				},
+			},
+			ForbidLatestTags: true,
+			ExpectedMessage: "The following images use the mutable 'latest' tag, either explicitly or by omitting the tag: " +
+				"'nginx' (embeddedArtifactRegistry/images[0]). Reference them by a fixed tag or digest instead.",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			definition := test.Definition
+			ctx := image.Context{
+				ImageDefinition:  &definition,
+				ForbidLatestTags: test.ForbidLatestTags,
+			}
+
+			failure := validateMutableTags(&ctx)
+			if test.ExpectedMessage == "" {
+				assert.Nil(t, failure)
+				return
+			}
+
+			require.NotNil(t, failure)
+			assert.Equal(t, test.ExpectedMessage, failure.UserMessage)
+			assert.Equal(t, test.ExpectedWarning, failure.Warning)
+		})
+	}
+}
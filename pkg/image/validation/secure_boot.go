@@ -0,0 +1,274 @@
+package validation
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+var lockdownModes = []string{image.LockdownIntegrity, image.LockdownConfidentiality}
+
+func validateSecureBoot(def *image.Definition, imageConfigDir string) []FailedValidation {
+	secureBoot := &def.OperatingSystem.SecureBoot
+
+	var failures []FailedValidation
+
+	if secureBoot.Lockdown != "" {
+		if !slices.Contains(lockdownModes, secureBoot.Lockdown) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/secureBoot/lockdown",
+				UserMessage: fmt.Sprintf("The secureBoot 'lockdown' field must be one of: %s.", strings.Join(lockdownModes, ", ")),
+			})
+		}
+
+		if slices.ContainsFunc(def.OperatingSystem.KernelArgs.Add, isLockdownKernelArg) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/secureBoot/lockdown",
+				UserMessage: "The secureBoot 'lockdown' field cannot be used along with a 'lockdown' kernel argument.",
+			})
+		}
+	}
+
+	for _, duplicate := range findDuplicates(secureBoot.MOKCertificates) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/secureBoot/mokCertificates",
+			UserMessage: fmt.Sprintf("The secureBoot 'mokCertificates' entry '%s' is specified more than once.", duplicate),
+		})
+	}
+
+	var mokCertificates []*x509.Certificate
+	for _, file := range secureBoot.MOKCertificates {
+		cert, failure := parseMOKCertificate(file, imageConfigDir)
+		if failure != nil {
+			failures = append(failures, *failure)
+			continue
+		}
+
+		if time.Now().After(cert.NotAfter) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/secureBoot/mokCertificates",
+				UserMessage: fmt.Sprintf("The MOK certificate '%s' expired on %s.", file, cert.NotAfter.Format(time.DateOnly)),
+				Warning:     true,
+			})
+		}
+
+		mokCertificates = append(mokCertificates, cert)
+	}
+
+	// The enrollment is confirmed in the MOK manager with the root password
+	if len(secureBoot.MOKCertificates) != 0 && !hasRootPassword(&def.OperatingSystem) {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/secureBoot/mokCertificates",
+			UserMessage: "Enrolling the secureBoot 'mokCertificates' requires the 'root' user to be configured with an 'encryptedPassword', " +
+				"which confirms the enrollment in the MOK manager.",
+		})
+	}
+
+	failures = append(failures, validateSecureBootSigning(&secureBoot.Signing, mokCertificates, imageConfigDir)...)
+
+	return failures
+}
+
+func validateSecureBootSigning(signing *image.SecureBootSigning, mokCertificates []*x509.Certificate, imageConfigDir string) []FailedValidation {
+	if !signing.Kernel && !signing.Modules {
+		if signing.Key != "" || signing.Certificate != "" {
+			return []FailedValidation{{
+				Field:       "operatingSystem/secureBoot/signing",
+				UserMessage: "The secureBoot 'signing' section must enable signing the 'kernel' and/or the 'modules' when a key is specified.",
+			}}
+		}
+
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if signing.Key == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/secureBoot/signing/key",
+			UserMessage: "The secureBoot 'signing' section requires the 'key' field to sign the boot artefacts.",
+		})
+	}
+
+	if signing.Certificate == "" {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/secureBoot/signing/certificate",
+			UserMessage: "The secureBoot 'signing' section requires the 'certificate' field to sign the boot artefacts.",
+		})
+	}
+
+	if len(failures) != 0 {
+		return failures
+	}
+
+	key, failure := parseSigningKey(signing.Key, imageConfigDir)
+	if failure != nil {
+		failures = append(failures, *failure)
+	}
+
+	cert, failure := parseSigningCertificate(signing.Certificate, imageConfigDir)
+	if failure != nil {
+		failures = append(failures, *failure)
+	}
+
+	if key == nil || cert == nil {
+		return failures
+	}
+
+	if publicKey, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !publicKey.Equal(cert.PublicKey) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/secureBoot/signing/key",
+			UserMessage: fmt.Sprintf("The signing key '%s' does not match the signing certificate '%s'.", signing.Key, signing.Certificate),
+		})
+	}
+
+	if time.Now().After(cert.NotAfter) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/secureBoot/signing/certificate",
+			UserMessage: fmt.Sprintf("The signing certificate '%s' expired on %s.", signing.Certificate, cert.NotAfter.Format(time.DateOnly)),
+			Warning:     true,
+		})
+	}
+
+	enrolled := slices.ContainsFunc(mokCertificates, func(mok *x509.Certificate) bool {
+		return mok.Equal(cert)
+	})
+	if !enrolled {
+		failures = append(failures, FailedValidation{
+			Field: "operatingSystem/secureBoot/signing/certificate",
+			UserMessage: fmt.Sprintf("The signing certificate '%s' is not among the 'mokCertificates', the signed boot artefacts "+
+				"are only trusted if it is already enrolled on the nodes or in their firmware.", signing.Certificate),
+			Warning: true,
+		})
+	}
+
+	return failures
+}
+
+func parseMOKCertificate(file, imageConfigDir string) (*x509.Certificate, *FailedValidation) {
+	data, failure := readSecureBootFile("mokCertificates", "operatingSystem/secureBoot/mokCertificates", file, imageConfigDir)
+	if failure != nil {
+		return nil, failure
+	}
+
+	// mokutil only imports DER encoded certificates
+	if block, _ := pem.Decode(data); block != nil {
+		return nil, &FailedValidation{
+			Field:       "operatingSystem/secureBoot/mokCertificates",
+			UserMessage: fmt.Sprintf("The MOK certificate '%s' must be DER encoded, not PEM encoded.", file),
+		}
+	}
+
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, &FailedValidation{
+			Field:       "operatingSystem/secureBoot/mokCertificates",
+			UserMessage: fmt.Sprintf("The MOK certificate '%s' is not a valid DER encoded certificate.", file),
+			Error:       err,
+		}
+	}
+
+	return cert, nil
+}
+
+func parseSigningCertificate(file, imageConfigDir string) (*x509.Certificate, *FailedValidation) {
+	data, failure := readSecureBootFile("certificate", "operatingSystem/secureBoot/signing/certificate", file, imageConfigDir)
+	if failure != nil {
+		return nil, failure
+	}
+
+	certs, err := combustion.ParseCertificates(data)
+	if err != nil || len(certs) != 1 {
+		return nil, &FailedValidation{
+			Field:       "operatingSystem/secureBoot/signing/certificate",
+			UserMessage: fmt.Sprintf("The signing certificate '%s' must contain a single PEM encoded certificate.", file),
+			Error:       err,
+		}
+	}
+
+	return certs[0], nil
+}
+
+// parseSigningKey parses the PEM encoded RSA key, the only type the kernel images can be signed with.
+func parseSigningKey(file, imageConfigDir string) (crypto.Signer, *FailedValidation) {
+	data, failure := readSecureBootFile("key", "operatingSystem/secureBoot/signing/key", file, imageConfigDir)
+	if failure != nil {
+		return nil, failure
+	}
+
+	invalid := &FailedValidation{
+		Field:       "operatingSystem/secureBoot/signing/key",
+		UserMessage: fmt.Sprintf("The signing key '%s' must be an unencrypted PEM encoded RSA private key.", file),
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, invalid
+	}
+
+	var key any
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, invalid
+	}
+
+	if err != nil {
+		invalid.Error = err
+		return nil, invalid
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, invalid
+	}
+
+	return rsaKey, nil
+}
+
+func readSecureBootFile(field, fieldPath, file, imageConfigDir string) ([]byte, *FailedValidation) {
+	if !filepath.IsLocal(file) || filepath.Base(file) != file || strings.ContainsAny(file, `'" `) {
+		return nil, &FailedValidation{
+			Field: fieldPath,
+			UserMessage: fmt.Sprintf("The secureBoot '%s' entry '%s' must be the name of a file in the '%s' directory, without quotes or spaces.",
+				field, file, combustion.SecureBootDir),
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(imageConfigDir, combustion.SecureBootDir, file))
+	if err != nil {
+		return nil, &FailedValidation{
+			Field:       fieldPath,
+			UserMessage: fmt.Sprintf("The secureBoot '%s' file '%s' could not be read.", field, file),
+			Error:       err,
+		}
+	}
+
+	return data, nil
+}
+
+func isLockdownKernelArg(arg string) bool {
+	key, _, _ := strings.Cut(arg, "=")
+	return key == "lockdown"
+}
+
+func hasRootPassword(operatingSystem *image.OperatingSystem) bool {
+	return slices.ContainsFunc(operatingSystem.Users, func(user image.OperatingSystemUser) bool {
+		return user.Username == "root" && user.EncryptedPassword != ""
+	})
+}
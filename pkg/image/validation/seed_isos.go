@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/build"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"golang.org/x/crypto/ssh"
+)
+
+func validateSeedISOs(def *image.Definition) []FailedValidation {
+	seeds := def.Image.SeedISOs
+	if len(seeds) == 0 {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	var hostnames, macAddresses, addresses []string
+	for i := range seeds {
+		seed := &seeds[i]
+
+		switch {
+		case seed.Hostname == "":
+			failures = append(failures, FailedValidation{
+				Field:       "image/seedISOs/hostname",
+				UserMessage: "The 'hostname' field is required for each entry in 'seedISOs'.",
+			})
+		case len(seed.Hostname) > 253 || !hostnameRegex.MatchString(seed.Hostname):
+			failures = append(failures, FailedValidation{
+				Field:       "image/seedISOs/hostname",
+				UserMessage: fmt.Sprintf("The seed ISO hostname '%s' must be a valid hostname.", seed.Hostname),
+			})
+		default:
+			hostnames = append(hostnames, strings.ToLower(seed.Hostname))
+		}
+
+		for _, key := range seed.SSHKeys {
+			if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err != nil {
+				failures = append(failures, FailedValidation{
+					Field:       "image/seedISOs/sshKeys",
+					UserMessage: fmt.Sprintf("An 'sshKeys' entry of the seed ISO '%s' is not a valid OpenSSH public key.", seed.Hostname),
+					Error:       err,
+				})
+			}
+		}
+
+		failures = append(failures, validateSeedNetwork(seed.Hostname, &seed.Network)...)
+
+		if seed.Network.MACAddress != "" {
+			macAddresses = append(macAddresses, strings.ToLower(seed.Network.MACAddress))
+		}
+
+		for _, address := range seed.Network.Addresses {
+			if ip, _, err := net.ParseCIDR(address); err == nil {
+				addresses = append(addresses, ip.String())
+			}
+		}
+	}
+
+	// Each seed identifies a distinct node
+	for _, duplicate := range findDuplicates(hostnames) {
+		failures = append(failures, FailedValidation{
+			Field:       "image/seedISOs/hostname",
+			UserMessage: fmt.Sprintf("The hostname '%s' is used by more than one entry in 'seedISOs'.", duplicate),
+		})
+	}
+
+	for _, duplicate := range findDuplicates(macAddresses) {
+		failures = append(failures, FailedValidation{
+			Field:       "image/seedISOs/network/macAddress",
+			UserMessage: fmt.Sprintf("The MAC address '%s' is used by more than one entry in 'seedISOs'.", duplicate),
+		})
+	}
+
+	for _, duplicate := range findDuplicates(addresses) {
+		failures = append(failures, FailedValidation{
+			Field:       "image/seedISOs/network/addresses",
+			UserMessage: fmt.Sprintf("The address '%s' is used by more than one entry in 'seedISOs'.", duplicate),
+		})
+	}
+
+	failures = append(failures, validatePackageResolution(&def.OperatingSystem.Packages, build.CloudInitPackage)...)
+
+	return failures
+}
+
+func validateSeedNetwork(hostname string, network *image.SeedNetwork) []FailedValidation {
+	if network.Interface == "" {
+		if network.MACAddress != "" || len(network.Addresses) != 0 || network.Gateway != "" || len(network.DNS) != 0 {
+			return []FailedValidation{{
+				Field:       "image/seedISOs/network/interface",
+				UserMessage: fmt.Sprintf("The 'interface' field is required in the 'network' of the seed ISO '%s'.", hostname),
+			}}
+		}
+
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if !interfaceNameRegex.MatchString(network.Interface) {
+		failures = append(failures, FailedValidation{
+			Field: "image/seedISOs/network/interface",
+			UserMessage: fmt.Sprintf("The 'interface' of the seed ISO '%s' must be a network interface name "+
+				"of up to 15 letters, digits, '_', '.' and '-'.", hostname),
+		})
+	}
+
+	if network.MACAddress != "" {
+		if mac, err := net.ParseMAC(network.MACAddress); err != nil || len(mac) != 6 {
+			failures = append(failures, FailedValidation{
+				Field:       "image/seedISOs/network/macAddress",
+				UserMessage: fmt.Sprintf("The 'macAddress' of the seed ISO '%s' must be a MAC address (e.g. '52:54:00:12:34:56').", hostname),
+			})
+		}
+	}
+
+	for _, address := range network.Addresses {
+		if _, _, err := net.ParseCIDR(address); err != nil {
+			failures = append(failures, FailedValidation{
+				Field:       "image/seedISOs/network/addresses",
+				UserMessage: fmt.Sprintf("Entry '%s' in the 'addresses' of the seed ISO '%s' must be an IP address in CIDR notation.", address, hostname),
+			})
+		}
+	}
+
+	if network.Gateway != "" {
+		if len(network.Addresses) == 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "image/seedISOs/network/gateway",
+				UserMessage: fmt.Sprintf("The 'gateway' of the seed ISO '%s' requires static 'addresses'.", hostname),
+			})
+		} else if net.ParseIP(network.Gateway) == nil {
+			failures = append(failures, FailedValidation{
+				Field:       "image/seedISOs/network/gateway",
+				UserMessage: fmt.Sprintf("The 'gateway' of the seed ISO '%s' must be an IP address.", hostname),
+			})
+		}
+	}
+
+	for _, dns := range network.DNS {
+		if net.ParseIP(dns) == nil {
+			failures = append(failures, FailedValidation{
+				Field:       "image/seedISOs/network/dns",
+				UserMessage: fmt.Sprintf("Entry '%s' in the 'dns' of the seed ISO '%s' must be an IP address.", dns, hostname),
+			})
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,141 @@
+package validation
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestValidateSeedISOs(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sshKey, err := ssh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshKey))) + " admin@example.com"
+
+	staticNetwork := image.SeedNetwork{
+		Interface:  "eth0",
+		MACAddress: "52:54:00:12:34:56",
+		Addresses:  []string{"192.168.1.10/24"},
+		Gateway:    "192.168.1.1",
+		DNS:        []string{"192.168.1.1"},
+	}
+
+	tests := map[string]struct {
+		SeedISOs               []image.SeedISO
+		PackageList            []string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			SeedISOs: []image.SeedISO{
+				{Hostname: "node1", SSHKeys: []string{authorizedKey}, Network: staticNetwork},
+				{Hostname: "node2", Network: image.SeedNetwork{Interface: "eth0"}},
+				{Hostname: "node3.example.com"},
+			},
+			PackageList: []string{"cloud-init"},
+		},
+		`unlisted cloud-init`: {
+			SeedISOs: []image.SeedISO{{Hostname: "node1"}},
+			ExpectedFailedMessages: []string{
+				"The 'cloud-init' package is installed from the configured repositories, its resolution may fail without either the 'sccRegistrationCode' or the 'additionalRepos' field.",
+			},
+			ExpectedWarnings: 1,
+		},
+		`invalid hostnames`: {
+			SeedISOs:    []image.SeedISO{{}, {Hostname: "node_1"}},
+			PackageList: []string{"cloud-init"},
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field is required for each entry in 'seedISOs'.",
+				"The seed ISO hostname 'node_1' must be a valid hostname.",
+			},
+		},
+		`invalid SSH key`: {
+			SeedISOs:    []image.SeedISO{{Hostname: "node1", SSHKeys: []string{"ssh-ed25519 bm90IGEga2V5"}}},
+			PackageList: []string{"cloud-init"},
+			ExpectedFailedMessages: []string{
+				"An 'sshKeys' entry of the seed ISO 'node1' is not a valid OpenSSH public key.",
+			},
+		},
+		`network without interface`: {
+			SeedISOs:    []image.SeedISO{{Hostname: "node1", Network: image.SeedNetwork{Addresses: []string{"192.168.1.10/24"}}}},
+			PackageList: []string{"cloud-init"},
+			ExpectedFailedMessages: []string{
+				"The 'interface' field is required in the 'network' of the seed ISO 'node1'.",
+			},
+		},
+		`invalid network`: {
+			SeedISOs: []image.SeedISO{{Hostname: "node1", Network: image.SeedNetwork{
+				Interface:  "eth0 eth1",
+				MACAddress: "52:54:00:12:34",
+				Addresses:  []string{"192.168.1.10"},
+				Gateway:    "gateway",
+				DNS:        []string{"dns.example.com"},
+			}}},
+			PackageList: []string{"cloud-init"},
+			ExpectedFailedMessages: []string{
+				"The 'interface' of the seed ISO 'node1' must be a network interface name of up to 15 letters, digits, '_', '.' and '-'.",
+				"The 'macAddress' of the seed ISO 'node1' must be a MAC address (e.g. '52:54:00:12:34:56').",
+				"Entry '192.168.1.10' in the 'addresses' of the seed ISO 'node1' must be an IP address in CIDR notation.",
+				"The 'gateway' of the seed ISO 'node1' must be an IP address.",
+				"Entry 'dns.example.com' in the 'dns' of the seed ISO 'node1' must be an IP address.",
+			},
+		},
+		`gateway without addresses`: {
+			SeedISOs:    []image.SeedISO{{Hostname: "node1", Network: image.SeedNetwork{Interface: "eth0", Gateway: "192.168.1.1"}}},
+			PackageList: []string{"cloud-init"},
+			ExpectedFailedMessages: []string{
+				"The 'gateway' of the seed ISO 'node1' requires static 'addresses'.",
+			},
+		},
+		`duplicate nodes`: {
+			SeedISOs: []image.SeedISO{
+				{Hostname: "node1", Network: staticNetwork},
+				{Hostname: "NODE1", Network: image.SeedNetwork{
+					Interface:  "eth0",
+					MACAddress: "52:54:00:12:34:56",
+					Addresses:  []string{"192.168.1.10/16"},
+				}},
+			},
+			PackageList: []string{"cloud-init"},
+			ExpectedFailedMessages: []string{
+				"The hostname 'node1' is used by more than one entry in 'seedISOs'.",
+				"The MAC address '52:54:00:12:34:56' is used by more than one entry in 'seedISOs'.",
+				"The address '192.168.1.10' is used by more than one entry in 'seedISOs'.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := image.Definition{
+				Image: image.Image{SeedISOs: test.SeedISOs},
+				OperatingSystem: image.OperatingSystem{
+					Packages: image.Packages{PKGList: test.PackageList},
+				},
+			}
+
+			failures := validateSeedISOs(&def)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// preinstalledShellCommands are the editors and pagers included in the base images
+var preinstalledShellCommands = []string{"cat", "less", "more", "vi", "vim"}
+
+var shellCommandNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+func validateShellDefaults(os *image.OperatingSystem) []FailedValidation {
+	defaults := &os.ShellDefaults
+
+	var failures []FailedValidation
+
+	failures = append(failures, validateShellCommand("editor", defaults.Editor, os.Packages.PKGList)...)
+	failures = append(failures, validateShellCommand("pager", defaults.Pager, os.Packages.PKGList)...)
+
+	names := make([]string, 0, len(defaults.Environment))
+	for name := range defaults.Environment {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if !environmentVariableNameRegex.MatchString(name) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/shellDefaults/environment",
+				UserMessage: fmt.Sprintf("Shell defaults variable '%s' may only contain letters, digits and '_', and cannot start with a digit.", name),
+			})
+		}
+
+		switch {
+		case (name == "EDITOR" || name == "VISUAL") && defaults.Editor != "",
+			name == "PAGER" && defaults.Pager != "":
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/shellDefaults/environment",
+				UserMessage: fmt.Sprintf("Shell defaults variable '%s' cannot be set along with the 'editor' and 'pager' fields which set it.", name),
+			})
+		}
+
+		if !isPlainShellValue(defaults.Environment[name]) {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/shellDefaults/environment",
+				UserMessage: fmt.Sprintf("The value of shell defaults variable '%s' cannot contain double quotes, backslashes, '$', '`' or control characters.", name),
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateShellCommand checks the command starts with the absolute path or the name of an executable,
+// warning about names which are neither included in the base images nor installed as a package of the same name.
+func validateShellCommand(field, command string, packages []string) []FailedValidation {
+	if command == "" {
+		return nil
+	}
+
+	if !isPlainShellValue(command) {
+		return []FailedValidation{{
+			Field:       "operatingSystem/shellDefaults/" + field,
+			UserMessage: fmt.Sprintf("The '%s' field in the 'shellDefaults' section cannot contain double quotes, backslashes, '$', '`' or control characters.", field),
+		}}
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return []FailedValidation{{
+			Field:       "operatingSystem/shellDefaults/" + field,
+			UserMessage: fmt.Sprintf("The '%s' field in the 'shellDefaults' section must name a command.", field),
+		}}
+	}
+
+	executable := fields[0]
+
+	if filepath.IsAbs(executable) {
+		if filepath.Clean(executable) != executable {
+			return []FailedValidation{{
+				Field:       "operatingSystem/shellDefaults/" + field,
+				UserMessage: fmt.Sprintf("The '%s' field in the 'shellDefaults' section must start with a normalized path to an executable, found '%s'.", field, executable),
+			}}
+		}
+
+		return nil
+	}
+
+	if !shellCommandNameRegex.MatchString(executable) {
+		return []FailedValidation{{
+			Field: "operatingSystem/shellDefaults/" + field,
+			UserMessage: fmt.Sprintf("The '%s' field in the 'shellDefaults' section must start with the name or absolute path of an executable, found '%s'.",
+				field, executable),
+		}}
+	}
+
+	if !slices.Contains(preinstalledShellCommands, executable) && !slices.Contains(packages, executable) {
+		return []FailedValidation{{
+			Field: "operatingSystem/shellDefaults/" + field,
+			UserMessage: fmt.Sprintf("The %s '%s' is not included in the base image, make sure it is installed (e.g. by adding the package providing it to the 'packageList').",
+				field, executable),
+			Warning: true,
+		}}
+	}
+
+	return nil
+}
+
+// isPlainShellValue checks the value can be double quoted in a shell script without being expanded.
+func isPlainShellValue(value string) bool {
+	return !strings.ContainsAny(value, "\"\\$`") && !strings.ContainsFunc(value, unicode.IsControl)
+}
@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateShellDefaults(t *testing.T) {
+	tests := map[string]struct {
+		ShellDefaults          image.ShellDefaults
+		PackageList            []string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid`: {
+			ShellDefaults: image.ShellDefaults{
+				Editor:      "vim",
+				Pager:       "less -R",
+				Environment: map[string]string{"HISTSIZE": "5000", "LESS": "-FRX"},
+			},
+		},
+		`absolute paths`: {
+			ShellDefaults: image.ShellDefaults{
+				Editor: "/opt/tools/bin/micro",
+				Pager:  "/usr/bin/most",
+			},
+		},
+		`installed package`: {
+			ShellDefaults: image.ShellDefaults{Editor: "nano"},
+			PackageList:   []string{"nano"},
+		},
+		`not installed`: {
+			ShellDefaults: image.ShellDefaults{Editor: "nano"},
+			ExpectedFailedMessages: []string{
+				"The editor 'nano' is not included in the base image, make sure it is installed (e.g. by adding the package providing it to the 'packageList').",
+			},
+			ExpectedWarnings: 1,
+		},
+		`invalid commands`: {
+			ShellDefaults: image.ShellDefaults{
+				Editor: "vim; rm -rf /",
+				Pager:  "$HOME/bin/pager",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'editor' field in the 'shellDefaults' section must start with the name or absolute path of an executable, found 'vim;'.",
+				"The 'pager' field in the 'shellDefaults' section cannot contain double quotes, backslashes, '$', '`' or control characters.",
+			},
+		},
+		`blank and unnormalized commands`: {
+			ShellDefaults: image.ShellDefaults{
+				Editor: "  ",
+				Pager:  "/usr/bin/../bin/less",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'editor' field in the 'shellDefaults' section must name a command.",
+				"The 'pager' field in the 'shellDefaults' section must start with a normalized path to an executable, found '/usr/bin/../bin/less'.",
+			},
+		},
+		`invalid environment`: {
+			ShellDefaults: image.ShellDefaults{
+				Editor: "vi",
+				Environment: map[string]string{
+					"1ST":    "value",
+					"VISUAL": "vim",
+					"PROMPT": "`hostname`",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Shell defaults variable '1ST' may only contain letters, digits and '_', and cannot start with a digit.",
+				"The value of shell defaults variable 'PROMPT' cannot contain double quotes, backslashes, '$', '`' or control characters.",
+				"Shell defaults variable 'VISUAL' cannot be set along with the 'editor' and 'pager' fields which set it.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				ShellDefaults: test.ShellDefaults,
+				Packages:      image.Packages{PKGList: test.PackageList},
+			}
+
+			failures := validateShellDefaults(&os)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
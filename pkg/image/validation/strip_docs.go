@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func validateStripDocs(os *image.OperatingSystem) []FailedValidation {
+	stripDocs := &os.StripDocs
+
+	var failures []FailedValidation
+
+	if !stripDocs.Enabled {
+		if len(stripDocs.Exclude) != 0 {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/stripDocs/exclude",
+				UserMessage: "The 'exclude' field in the 'stripDocs' section requires the documentation to be stripped through the 'enabled' field.",
+			})
+		}
+
+		return failures
+	}
+
+	seenPaths := make(map[string]bool)
+
+	for _, p := range stripDocs.Exclude {
+		if failure := validateStripDocsExclude(p); failure != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/stripDocs/exclude",
+				UserMessage: failure,
+			})
+			continue
+		}
+
+		if seenPaths[p] {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/stripDocs/exclude",
+				UserMessage: fmt.Sprintf("Path '%s' is listed more than once in the 'stripDocs' exclude list.", p),
+			})
+			continue
+		}
+		seenPaths[p] = true
+
+		for _, removed := range os.Remove {
+			if removed == p || strings.HasPrefix(p, removed+"/") {
+				failures = append(failures, FailedValidation{
+					Field:       "operatingSystem/stripDocs/exclude",
+					UserMessage: fmt.Sprintf("Path '%s' in the 'stripDocs' exclude list is deleted by the '%s' entry of the 'remove' list.", p, removed),
+				})
+			}
+		}
+	}
+
+	return failures
+}
+
+func validateStripDocsExclude(p string) string {
+	switch {
+	case p == "":
+		return "Entries in the 'stripDocs' exclude list cannot be empty."
+	case !filepath.IsAbs(p):
+		return fmt.Sprintf("Path '%s' in the 'stripDocs' exclude list must be absolute.", p)
+	case filepath.Clean(p) != p:
+		return fmt.Sprintf("Path '%s' in the 'stripDocs' exclude list must be normalized, without trailing slashes or '.' and '..' elements (e.g. '%s').",
+			p, filepath.Clean(p))
+	case strings.ContainsAny(p, "'*?[\\") || strings.ContainsFunc(p, unicode.IsControl):
+		return fmt.Sprintf("Path %q in the 'stripDocs' exclude list cannot contain single quotes, wildcards, backslashes or control characters.", p)
+	}
+
+	for _, dir := range image.DocumentationDirs {
+		if p == dir || strings.HasPrefix(p, dir+"/") {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("Path '%s' in the 'stripDocs' exclude list must be located under one of: %s",
+		p, strings.Join(image.DocumentationDirs, ", "))
+}
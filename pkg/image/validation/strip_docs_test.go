@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateStripDocs(t *testing.T) {
+	tests := map[string]struct {
+		OS                     image.OperatingSystem
+		ExpectedFailedMessages []string
+	}{
+		`not defined`: {},
+		`enabled`: {
+			OS: image.OperatingSystem{
+				StripDocs: image.StripDocs{Enabled: true},
+			},
+		},
+		`valid exclude list`: {
+			OS: image.OperatingSystem{
+				Remove: []string{"/usr/share/locale", "/usr/share/doc/packages/vim"},
+				StripDocs: image.StripDocs{
+					Enabled: true,
+					Exclude: []string{"/usr/share/doc/packages/openssl", "/usr/share/man", "/usr/share/info/bash.info.gz"},
+				},
+			},
+		},
+		`exclude list without stripping`: {
+			OS: image.OperatingSystem{
+				StripDocs: image.StripDocs{
+					Exclude: []string{"/usr/share/doc/packages/openssl"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'exclude' field in the 'stripDocs' section requires the documentation to be stripped through the 'enabled' field.",
+			},
+		},
+		`invalid exclude paths`: {
+			OS: image.OperatingSystem{
+				StripDocs: image.StripDocs{
+					Enabled: true,
+					Exclude: []string{
+						"",
+						"usr/share/doc",
+						"/usr/share/doc/",
+						"/usr/share/doc/../man",
+						"/usr/share/doc/it's",
+						"/usr/share/doc/packages/*",
+						"/usr/share/docs",
+						"/etc/motd",
+						"/usr/share/man/man1",
+						"/usr/share/man/man1",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Entries in the 'stripDocs' exclude list cannot be empty.",
+				"Path 'usr/share/doc' in the 'stripDocs' exclude list must be absolute.",
+				"Path '/usr/share/doc/' in the 'stripDocs' exclude list must be normalized, without trailing slashes or '.' and '..' elements (e.g. '/usr/share/doc').",
+				"Path '/usr/share/doc/../man' in the 'stripDocs' exclude list must be normalized, without trailing slashes or '.' and '..' elements (e.g. '/usr/share/man').",
+				"Path \"/usr/share/doc/it's\" in the 'stripDocs' exclude list cannot contain single quotes, wildcards, backslashes or control characters.",
+				"Path \"/usr/share/doc/packages/*\" in the 'stripDocs' exclude list cannot contain single quotes, wildcards, backslashes or control characters.",
+				"Path '/usr/share/docs' in the 'stripDocs' exclude list must be located under one of: /usr/share/doc, /usr/share/man, /usr/share/info",
+				"Path '/etc/motd' in the 'stripDocs' exclude list must be located under one of: /usr/share/doc, /usr/share/man, /usr/share/info",
+				"Path '/usr/share/man/man1' is listed more than once in the 'stripDocs' exclude list.",
+			},
+		},
+		`excluded paths removed`: {
+			OS: image.OperatingSystem{
+				Remove: []string{"/usr/share/doc", "/usr/share/man/man1"},
+				StripDocs: image.StripDocs{
+					Enabled: true,
+					Exclude: []string{"/usr/share/doc/packages/openssl", "/usr/share/man/man1", "/usr/share/man/man8"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Path '/usr/share/doc/packages/openssl' in the 'stripDocs' exclude list is deleted by the '/usr/share/doc' entry of the 'remove' list.",
+				"Path '/usr/share/man/man1' in the 'stripDocs' exclude list is deleted by the '/usr/share/man/man1' entry of the 'remove' list.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			failures := validateStripDocs(&test.OS)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
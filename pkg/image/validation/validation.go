@@ -1,12 +1,20 @@
 package validation
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
 type FailedValidation struct {
+	// Field is the path of the definition field the issue concerns (e.g. "operatingSystem/zram/size"),
+	// empty if the issue does not concern a single field.
+	Field       string
 	UserMessage string
 	Error       error
+	// Warning indicates the issue does not prevent the build unless strict validation is requested.
+	Warning bool
 }
 
 type validateComponent func(ctx *image.Context) []FailedValidation
@@ -23,10 +31,13 @@ func ValidateDefinition(ctx *image.Context) map[string][]FailedValidation {
 	}
 
 	validations := map[string]validateComponent{
-		imageComponent:    validateImage,
-		osComponent:       validateOperatingSystem,
-		registryComponent: validateEmbeddedArtifactRegistry,
-		k8sComponent:      validateKubernetes,
+		imageComponent:     validateImage,
+		osComponent:        validateOperatingSystem,
+		registryComponent:  validateEmbeddedArtifactRegistry,
+		k8sComponent:       validateKubernetes,
+		customComponent:    validateCustomScripts,
+		exclusiveComponent: validateExclusiveSections,
+		mountsComponent:    validateMounts,
 	}
 	for componentName, v := range validations {
 		componentFailures := v(ctx)
@@ -52,3 +63,20 @@ func findDuplicates(items []string) []string {
 
 	return duplicates
 }
+
+// validatePackageResolution warns about packages installed by the build for a section of the definition, which
+// are not part of the base image and are resolved from the configured repositories.
+func validatePackageResolution(packages *image.Packages, pkg string) []FailedValidation {
+	if slices.Contains(packages.PKGList, pkg) || packages.RegCode != "" || len(packages.AdditionalRepos) != 0 {
+		return nil
+	}
+
+	return []FailedValidation{
+		{
+			Field: "operatingSystem/packages/packageList",
+			UserMessage: fmt.Sprintf("The '%s' package is installed from the configured repositories, its resolution may fail "+
+				"without either the 'sccRegistrationCode' or the 'additionalRepos' field.", pkg),
+			Warning: true,
+		},
+	}
+}
@@ -1,8 +1,14 @@
 package validation
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,6 +45,11 @@ func TestValidateDefinition(t *testing.T) {
 					OutputImageName: "output.iso",
 				},
 			},
+			Expected: map[string][]string{
+				imageComponent: {
+					"The 'isoConfiguration/installDevice' field is not set in the 'operatingSystem' section, the installation will not run unattended and prompts for the disk to install to.",
+				},
+			},
 		},
 		`one error from each`: {
 			Definition: image.Definition{
@@ -49,7 +60,7 @@ func TestValidateDefinition(t *testing.T) {
 					OutputImageName: "output.iso",
 				},
 				OperatingSystem: image.OperatingSystem{
-					KernelArgs: []string{"foo="},
+					KernelArgs: image.KernelArgs{Add: []string{"foo="}},
 				},
 				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
 					ContainerImages: []image.ContainerImage{
@@ -115,3 +126,87 @@ func TestValidateDefinition(t *testing.T) {
 		})
 	}
 }
+
+// TestFailedValidationFields verifies the definition paths set as the field of the validation failures
+// against the YAML keys of the image definition.
+func TestFailedValidationFields(t *testing.T) {
+	paths := map[string]bool{}
+	collectDefinitionPaths(reflect.TypeOf(image.Definition{}), "", paths)
+
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var found int
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(f, func(node ast.Node) bool {
+			kv, ok := node.(*ast.KeyValueExpr)
+			if !ok {
+				return true
+			}
+
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok || (key.Name != "Field" && key.Name != "field") {
+				return true
+			}
+
+			value := kv.Value
+			// Fields of lists or sections are appended to their parent, e.g. "operatingSystem/packages/" + list.field
+			if binary, ok := value.(*ast.BinaryExpr); ok && binary.Op == token.ADD {
+				value = binary.X
+			}
+
+			lit, ok := value.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+
+			path, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+
+			// The 'field' keys of other structures name fields relative to their section
+			if key.Name == "field" && !strings.Contains(path, "/") {
+				return true
+			}
+
+			path = strings.TrimSuffix(path, "/")
+			assert.Truef(t, paths[path], "%s: '%s' is not a field of the image definition", fset.Position(lit.Pos()), path)
+			found++
+			return true
+		})
+	}
+
+	assert.NotZero(t, found)
+}
+
+func collectDefinitionPaths(t reflect.Type, prefix string, paths map[string]bool) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		path := name
+		if prefix != "" {
+			path = prefix + "/" + name
+		}
+		paths[path] = true
+
+		collectDefinitionPaths(field.Type, path, paths)
+	}
+}
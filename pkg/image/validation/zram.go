@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const zramMaxRatio = 2
+
+// validZramAlgorithms are the compression algorithms supported by the zram kernel module
+var validZramAlgorithms = []string{"lzo", "lzo-rle", "lz4", "lz4hc", "zstd", "842", "deflate"}
+
+func validateZram(os *image.OperatingSystem) []FailedValidation {
+	zram := &os.Zram
+
+	var failures []FailedValidation
+
+	if zram.Size == "" && zram.Ratio == 0 {
+		if zram.Algorithm != "" {
+			failures = append(failures, FailedValidation{
+				Field:       "operatingSystem/zram",
+				UserMessage: "Either the 'size' or the 'ratio' field is required when configuring the 'zram' section.",
+			})
+		}
+
+		return failures
+	}
+
+	if zram.Size != "" && zram.Ratio != 0 {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/zram",
+			UserMessage: "The 'size' and 'ratio' fields in the 'zram' section cannot be used together.",
+		})
+	}
+
+	if zram.Size != "" && !zram.Size.IsValid() {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/zram/size",
+			UserMessage: "The 'size' field in the 'zram' section must be a positive integer followed by one of 'M', 'G' or 'T' (e.g. '4G').",
+		})
+	}
+
+	if zram.Size == "" && (zram.Ratio <= 0 || zram.Ratio > zramMaxRatio) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/zram/ratio",
+			UserMessage: fmt.Sprintf("The 'ratio' field in the 'zram' section must be greater than 0 and at most %d (e.g. 0.5 for half of the memory).", zramMaxRatio),
+		})
+	}
+
+	if zram.Algorithm != "" && !slices.Contains(validZramAlgorithms, zram.Algorithm) {
+		failures = append(failures, FailedValidation{
+			Field:       "operatingSystem/zram/algorithm",
+			UserMessage: fmt.Sprintf("The 'algorithm' field in the 'zram' section must be one of: %s", strings.Join(validZramAlgorithms, ", ")),
+		})
+	}
+
+	failures = append(failures, validatePackageResolution(&os.Packages, combustion.ZramGeneratorPackage)...)
+
+	return failures
+}
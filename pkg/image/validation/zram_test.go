@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateZram(t *testing.T) {
+	tests := map[string]struct {
+		Zram                   image.Zram
+		PackageList            []string
+		ExpectedFailedMessages []string
+		ExpectedWarnings       int
+	}{
+		`not defined`: {},
+		`valid size`: {
+			Zram:        image.Zram{Size: "4G", Algorithm: "zstd"},
+			PackageList: []string{"zram-generator"},
+		},
+		`valid ratio`: {
+			Zram:        image.Zram{Ratio: 0.5},
+			PackageList: []string{"zram-generator"},
+		},
+		`algorithm only`: {
+			Zram: image.Zram{Algorithm: "zstd"},
+			ExpectedFailedMessages: []string{
+				"Either the 'size' or the 'ratio' field is required when configuring the 'zram' section.",
+			},
+		},
+		`size and ratio`: {
+			Zram:        image.Zram{Size: "4G", Ratio: 0.5},
+			PackageList: []string{"zram-generator"},
+			ExpectedFailedMessages: []string{
+				"The 'size' and 'ratio' fields in the 'zram' section cannot be used together.",
+			},
+		},
+		`invalid size`: {
+			Zram:        image.Zram{Size: "4"},
+			PackageList: []string{"zram-generator"},
+			ExpectedFailedMessages: []string{
+				"The 'size' field in the 'zram' section must be a positive integer followed by one of 'M', 'G' or 'T' (e.g. '4G').",
+			},
+		},
+		`invalid ratio`: {
+			Zram:        image.Zram{Ratio: 3},
+			PackageList: []string{"zram-generator"},
+			ExpectedFailedMessages: []string{
+				"The 'ratio' field in the 'zram' section must be greater than 0 and at most 2 (e.g. 0.5 for half of the memory).",
+			},
+		},
+		`negative ratio`: {
+			Zram:        image.Zram{Ratio: -0.5},
+			PackageList: []string{"zram-generator"},
+			ExpectedFailedMessages: []string{
+				"The 'ratio' field in the 'zram' section must be greater than 0 and at most 2 (e.g. 0.5 for half of the memory).",
+			},
+		},
+		`invalid algorithm`: {
+			Zram:        image.Zram{Ratio: 0.5, Algorithm: "gzip"},
+			PackageList: []string{"zram-generator"},
+			ExpectedFailedMessages: []string{
+				"The 'algorithm' field in the 'zram' section must be one of: lzo, lzo-rle, lz4, lz4hc, zstd, 842, deflate",
+			},
+		},
+		`unlisted zram-generator`: {
+			Zram: image.Zram{Ratio: 0.5},
+			ExpectedFailedMessages: []string{
+				"The 'zram-generator' package is installed from the configured repositories, its resolution may fail without either the 'sccRegistrationCode' or the 'additionalRepos' field.",
+			},
+			ExpectedWarnings: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				Zram:     test.Zram,
+				Packages: image.Packages{PKGList: test.PackageList},
+			}
+
+			failures := validateZram(&os)
+
+			var foundMessages []string
+			var foundWarnings int
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				if foundValidation.Warning {
+					foundWarnings++
+				}
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedWarnings, foundWarnings)
+		})
+	}
+}
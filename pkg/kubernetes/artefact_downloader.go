@@ -41,9 +41,11 @@ type cache interface {
 
 type ArtefactDownloader struct {
 	Cache cache
+	// Offline copies the artefacts from the cache instead of downloading them, failing on any uncached artefact.
+	Offline bool
 }
 
-func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error {
+func (d ArtefactDownloader) DownloadRKE2Artefacts(ctx context.Context, arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error {
 	if !strings.Contains(version, image.KubernetesDistroRKE2) {
 		return fmt.Errorf("invalid RKE2 version: '%s'", version)
 	}
@@ -57,12 +59,12 @@ func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni
 		return fmt.Errorf("gathering RKE2 image artefacts: %w", err)
 	}
 
-	if err = d.downloadArtefacts(artefacts, rke2ReleaseURL, version, imagesPath); err != nil {
+	if err = d.downloadArtefacts(ctx, artefacts, rke2ReleaseURL, version, imagesPath); err != nil {
 		return fmt.Errorf("downloading RKE2 image artefacts: %w", err)
 	}
 
 	artefacts = rke2InstallerArtefacts(arch)
-	if err = d.downloadArtefacts(artefacts, rke2ReleaseURL, version, installPath); err != nil {
+	if err = d.downloadArtefacts(ctx, artefacts, rke2ReleaseURL, version, installPath); err != nil {
 		return fmt.Errorf("downloading RKE2 install artefacts: %w", err)
 	}
 
@@ -115,18 +117,18 @@ func rke2ImageArtefacts(cni string, multusEnabled bool, arch image.Arch) ([]stri
 	return artefacts, nil
 }
 
-func (d ArtefactDownloader) DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error {
+func (d ArtefactDownloader) DownloadK3sArtefacts(ctx context.Context, arch image.Arch, version, installPath, imagesPath string) error {
 	if !strings.Contains(version, image.KubernetesDistroK3S) {
 		return fmt.Errorf("invalid k3s version: '%s'", version)
 	}
 
 	artefacts := k3sImageArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, k3sReleaseURL, version, imagesPath); err != nil {
+	if err := d.downloadArtefacts(ctx, artefacts, k3sReleaseURL, version, imagesPath); err != nil {
 		return fmt.Errorf("downloading k3s image artefacts: %w", err)
 	}
 
 	artefacts = k3sInstallerArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, k3sReleaseURL, version, installPath); err != nil {
+	if err := d.downloadArtefacts(ctx, artefacts, k3sReleaseURL, version, installPath); err != nil {
 		return fmt.Errorf("downloading k3s install artefacts: %w", err)
 	}
 
@@ -154,7 +156,9 @@ func k3sImageArtefacts(arch image.Arch) []string {
 	}
 }
 
-func (d ArtefactDownloader) downloadArtefacts(artefacts []string, releaseURL, version, destinationPath string) error {
+func (d ArtefactDownloader) downloadArtefacts(ctx context.Context, artefacts []string, releaseURL, version, destinationPath string) error {
+	batch := http.NewBatch(len(artefacts))
+
 	for _, artefact := range artefacts {
 		url := fmt.Sprintf(releaseURL, version, artefact)
 		path := filepath.Join(destinationPath, artefact)
@@ -165,13 +169,22 @@ func (d ArtefactDownloader) downloadArtefacts(artefacts []string, releaseURL, ve
 			return fmt.Errorf("retrieving artefact '%s' from cache: %w", artefact, err)
 		}
 
-		if !copied {
-			if err = d.downloadArtefact(url, path, cacheKey); err != nil {
-				return fmt.Errorf("downloading artefact '%s': %w", artefact, err)
-			}
+		if copied {
+			batch.Skip()
+			continue
+		}
+
+		if d.Offline {
+			return fmt.Errorf("artefact '%s' is not cached", artefact)
+		}
+
+		if err = d.downloadArtefact(ctx, batch, url, path, cacheKey); err != nil {
+			return fmt.Errorf("downloading artefact '%s': %w", artefact, err)
 		}
 	}
 
+	batch.Done()
+
 	return nil
 }
 
@@ -194,10 +207,10 @@ func (d ArtefactDownloader) copyArtefactFromCache(cacheKey, destPath string) (bo
 	return true, nil
 }
 
-func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
+func (d ArtefactDownloader) downloadArtefact(ctx context.Context, batch *http.Batch, url, path, cacheKey string) error {
 	reader, writer := io.Pipe()
 
-	errGroup, ctx := errgroup.WithContext(context.Background())
+	errGroup, ctx := errgroup.WithContext(ctx)
 
 	errGroup.Go(func() error {
 		defer func() {
@@ -206,7 +219,7 @@ func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
 			}
 		}()
 
-		if err := http.DownloadFile(ctx, url, path, writer); err != nil {
+		if err := batch.DownloadFile(ctx, url, path, writer); err != nil {
 			return fmt.Errorf("downloading artefact: %w", err)
 		}
 		return nil
@@ -223,6 +236,32 @@ func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
 	return errGroup.Wait()
 }
 
+// ArtefactIdentifiers returns the cache identifiers of the artefacts downloaded for the given Kubernetes version,
+// the CNI and Multus only applying to RKE2.
+func ArtefactIdentifiers(arch image.Arch, version, cni string, multusEnabled bool) ([]string, error) {
+	var artefacts []string
+
+	switch {
+	case strings.Contains(version, image.KubernetesDistroRKE2):
+		imageArtefacts, err := rke2ImageArtefacts(cni, multusEnabled, arch)
+		if err != nil {
+			return nil, fmt.Errorf("gathering RKE2 image artefacts: %w", err)
+		}
+		artefacts = append(imageArtefacts, rke2InstallerArtefacts(arch)...)
+	case strings.Contains(version, image.KubernetesDistroK3S):
+		artefacts = append(k3sImageArtefacts(arch), k3sInstallerArtefacts(arch)...)
+	default:
+		return nil, fmt.Errorf("invalid Kubernetes version: '%s'", version)
+	}
+
+	identifiers := make([]string, 0, len(artefacts))
+	for _, artefact := range artefacts {
+		identifiers = append(identifiers, cacheIdentifier(version, artefact))
+	}
+
+	return identifiers, nil
+}
+
 func cacheIdentifier(version, artefact string) string {
 	return fmt.Sprintf("%s/%s", version, artefact)
 }
@@ -1,10 +1,17 @@
 package kubernetes
 
 import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -150,3 +157,128 @@ func TestK3sImageArtefacts(t *testing.T) {
 	armArtefacts := []string{"k3s-airgap-images-arm64.tar.zst"}
 	assert.Equal(t, armArtefacts, k3sImageArtefacts(image.ArchTypeARM))
 }
+
+func TestArtefactIdentifiers(t *testing.T) {
+	identifiers, err := ArtefactIdentifiers(image.ArchTypeX86, "v1.30.3+rke2r1", image.CNITypeCilium, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"v1.30.3+rke2r1/rke2-images-core.linux-amd64.tar.zst",
+		"v1.30.3+rke2r1/rke2-images-cilium.linux-amd64.tar.zst",
+		"v1.30.3+rke2r1/rke2.linux-amd64.tar.gz",
+		"v1.30.3+rke2r1/sha256sum-amd64.txt",
+	}, identifiers)
+
+	identifiers, err = ArtefactIdentifiers(image.ArchTypeARM, "v1.30.3+k3s1", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"v1.30.3+k3s1/k3s-airgap-images-arm64.tar.zst",
+		"v1.30.3+k3s1/k3s-arm64",
+	}, identifiers)
+
+	_, err = ArtefactIdentifiers(image.ArchTypeX86, "v1.30.3+rke2r1", "", false)
+	require.ErrorContains(t, err, "gathering RKE2 image artefacts: CNI not specified")
+
+	_, err = ArtefactIdentifiers(image.ArchTypeX86, "v1.30.3", "", false)
+	require.ErrorContains(t, err, "invalid Kubernetes version: 'v1.30.3'")
+}
+
+// fakeCache stores the artefacts as files of a temporary directory.
+type fakeCache struct {
+	dir   string
+	files map[string]string
+}
+
+func newFakeCache(t *testing.T) *fakeCache {
+	return &fakeCache{dir: t.TempDir(), files: map[string]string{}}
+}
+
+func (c *fakeCache) Get(artefact string) (string, error) {
+	path, ok := c.files[artefact]
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+
+	return path, nil
+}
+
+func (c *fakeCache) Put(artefact string, reader io.Reader) error {
+	if _, ok := c.files[artefact]; ok {
+		return fs.ErrExist
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(c.dir, filepath.Base(artefact))
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	c.files[artefact] = path
+
+	return nil
+}
+
+func TestDownloadK3sArtefacts_Offline(t *testing.T) {
+	c := newFakeCache(t)
+	require.NoError(t, c.Put("v1.30.3+k3s1/k3s-airgap-images-amd64.tar.zst", strings.NewReader("images")))
+
+	installPath := t.TempDir()
+	imagesPath := t.TempDir()
+	downloader := ArtefactDownloader{Cache: c, Offline: true}
+
+	// Uncached artefacts are not downloaded
+	err := downloader.DownloadK3sArtefacts(context.Background(), image.ArchTypeX86, "v1.30.3+k3s1", installPath, imagesPath)
+	require.EqualError(t, err, "downloading k3s install artefacts: artefact 'k3s' is not cached")
+
+	contents, err := os.ReadFile(filepath.Join(imagesPath, "k3s-airgap-images-amd64.tar.zst"))
+	require.NoError(t, err)
+	assert.Equal(t, "images", string(contents))
+
+	require.NoError(t, c.Put("v1.30.3+k3s1/k3s", strings.NewReader("binary")))
+	require.NoError(t, downloader.DownloadK3sArtefacts(context.Background(), image.ArchTypeX86, "v1.30.3+k3s1", installPath, imagesPath))
+	assert.FileExists(t, filepath.Join(installPath, "k3s"))
+}
+
+func TestDownloadInstallScript_Offline(t *testing.T) {
+	c := newFakeCache(t)
+	destinationPath := t.TempDir()
+	downloader := ScriptDownloader{Cache: c, Offline: true}
+
+	_, err := downloader.DownloadInstallScript(context.Background(), image.KubernetesDistroRKE2, destinationPath)
+	require.EqualError(t, err, "the rke2 install script is not cached")
+
+	require.NoError(t, c.Put(InstallScriptIdentifier(image.KubernetesDistroRKE2), strings.NewReader("#!/bin/sh\n")))
+
+	installer, err := downloader.DownloadInstallScript(context.Background(), image.KubernetesDistroRKE2, destinationPath)
+	require.NoError(t, err)
+	assert.Equal(t, "rke2_installer.sh", installer)
+
+	info, err := os.Stat(filepath.Join(destinationPath, installer))
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode().Perm())
+
+	_, err = ScriptDownloader{Offline: true}.DownloadInstallScript(context.Background(), image.KubernetesDistroRKE2, destinationPath)
+	require.EqualError(t, err, "no cache to copy the rke2 install script from")
+}
+
+func TestCacheScript(t *testing.T) {
+	c := newFakeCache(t)
+	downloader := ScriptDownloader{Cache: c}
+
+	scriptPath := filepath.Join(t.TempDir(), "k3s_installer.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("first"), 0o600))
+	require.NoError(t, downloader.cacheScript(image.KubernetesDistroK3S, scriptPath))
+
+	// An already cached script is kept
+	require.NoError(t, os.WriteFile(scriptPath, []byte("second"), 0o600))
+	require.NoError(t, downloader.cacheScript(image.KubernetesDistroK3S, scriptPath))
+
+	cachedPath, err := c.Get("install-scripts/k3s_installer.sh")
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(cachedPath)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(contents))
+}
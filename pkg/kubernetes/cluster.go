@@ -27,6 +27,8 @@ const (
 	disableKey      = "disable"
 	clusterInitKey  = "cluster-init"
 	selinuxKey      = "selinux"
+	nodeLabelKey    = "node-label"
+	nodeTaintKey    = "node-taint"
 )
 
 type Cluster struct {
@@ -40,6 +42,9 @@ type Cluster struct {
 	ServerConfig map[string]any
 	// AgentConfig contains the agent configurations in multi node clusters.
 	AgentConfig map[string]any
+	// NodeConfigs contains the configurations of the nodes in multi node clusters
+	// which are registered with labels or taints, keyed by their hostname.
+	NodeConfigs map[string]map[string]any
 }
 
 func NewCluster(kubernetes *image.Kubernetes, configPath string) (*Cluster, error) {
@@ -51,6 +56,9 @@ func NewCluster(kubernetes *image.Kubernetes, configPath string) (*Cluster, erro
 
 	if len(kubernetes.Nodes) < 2 {
 		setSingleNodeConfigDefaults(kubernetes, serverConfig)
+		if len(kubernetes.Nodes) == 1 {
+			appendNodeMetadata(serverConfig, &kubernetes.Nodes[0])
+		}
 		return &Cluster{ServerConfig: serverConfig}, nil
 	}
 
@@ -85,12 +93,48 @@ func NewCluster(kubernetes *image.Kubernetes, configPath string) (*Cluster, erro
 		return nil, fmt.Errorf("failed to determine cluster initialiser")
 	}
 
-	return &Cluster{
+	cluster := &Cluster{
 		InitialiserName:   initialiser,
 		InitialiserConfig: initialiserConfig,
 		ServerConfig:      serverConfig,
 		AgentConfig:       agentConfig,
-	}, nil
+	}
+	cluster.NodeConfigs = nodeConfigs(kubernetes.Nodes, cluster)
+
+	return cluster, nil
+}
+
+// nodeConfigs derives the configurations of the nodes registered with labels or taints
+// from the configuration matching their role in the cluster.
+func nodeConfigs(nodes []image.Node, cluster *Cluster) map[string]map[string]any {
+	var configs map[string]map[string]any
+
+	for i := range nodes {
+		node := &nodes[i]
+		if len(node.Labels) == 0 && len(node.Taints) == 0 {
+			continue
+		}
+
+		baseConfig := cluster.AgentConfig
+		if node.Hostname == cluster.InitialiserName {
+			baseConfig = cluster.InitialiserConfig
+		} else if node.Type == image.KubernetesNodeTypeServer {
+			baseConfig = cluster.ServerConfig
+		}
+
+		config := map[string]any{}
+		for k, v := range baseConfig {
+			config[k] = v
+		}
+		appendNodeMetadata(config, node)
+
+		if configs == nil {
+			configs = map[string]map[string]any{}
+		}
+		configs[node.Hostname] = config
+	}
+
+	return configs
 }
 
 func ParseKubernetesConfig(configFile string) (map[string]any, error) {
@@ -277,6 +321,38 @@ func appendDisabledServices(config map[string]any, service string) {
 	}
 }
 
+func appendNodeMetadata(config map[string]any, node *image.Node) {
+	for _, label := range node.Labels {
+		appendConfigValue(config, nodeLabelKey, label)
+	}
+
+	for _, taint := range node.Taints {
+		appendConfigValue(config, nodeTaintKey, taint)
+	}
+}
+
+// appendConfigValue appends the value to a list of the config without modifying
+// lists which may be shared with other configs.
+func appendConfigValue(config map[string]any, key, value string) {
+	var values []any
+
+	switch v := config[key].(type) {
+	case nil:
+	case string:
+		values = append(values, v)
+	case []string:
+		for _, s := range v {
+			values = append(values, s)
+		}
+	case []any:
+		values = append(values, v...)
+	default:
+		zap.S().Warnf("Ignoring invalid '%s' value: %v", key, v)
+	}
+
+	config[key] = append(values, value)
+}
+
 func ServersCount(nodes []image.Node) int {
 	var servers int
 
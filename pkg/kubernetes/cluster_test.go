@@ -82,6 +82,103 @@ func TestNewCluster_SingleNode_ExistingConfig(t *testing.T) {
 	assert.Nil(t, cluster.AgentConfig)
 }
 
+func TestNewCluster_SingleNode_NodeMetadata(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.29.0+k3s1",
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+				Labels:   []string{"zone=edge-1"},
+				Taints:   []string{"dedicated=gpu:NoSchedule"},
+			},
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	require.NotNil(t, cluster.ServerConfig)
+	assert.Equal(t, []any{"zone=edge-1"}, cluster.ServerConfig["node-label"])
+	assert.Equal(t, []any{"dedicated=gpu:NoSchedule"}, cluster.ServerConfig["node-taint"])
+	assert.Nil(t, cluster.NodeConfigs)
+}
+
+func TestNewCluster_MultiNode_NodeMetadata(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.29.0+rke2r1",
+		Network: image.Network{
+			APIVIP: "192.168.122.50",
+		},
+		Nodes: []image.Node{
+			{
+				Hostname: "node1.suse.com",
+				Type:     "server",
+				Labels:   []string{"zone=edge-1"},
+			},
+			{
+				Hostname: "node2.suse.com",
+				Type:     "server",
+			},
+			{
+				Hostname: "node3.suse.com",
+				Type:     "server",
+				Taints:   []string{"CriticalAddonsOnly=true:NoExecute"},
+			},
+			{
+				Hostname: "node4.suse.com",
+				Type:     "agent",
+				Labels:   []string{"zone=edge-2", "example.com/gpu=true"},
+				Taints:   []string{"dedicated=gpu:NoSchedule"},
+			},
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	require.Len(t, cluster.NodeConfigs, 3)
+	assert.NotContains(t, cluster.NodeConfigs, "node2.suse.com")
+
+	initialiserConfig := cluster.NodeConfigs["node1.suse.com"]
+	assert.Equal(t, []any{"zone=edge-1"}, initialiserConfig["node-label"])
+	assert.Nil(t, initialiserConfig["node-taint"])
+	assert.Nil(t, initialiserConfig["server"])
+	assert.Equal(t, cluster.InitialiserConfig["token"], initialiserConfig["token"])
+
+	serverConfig := cluster.NodeConfigs["node3.suse.com"]
+	assert.Nil(t, serverConfig["node-label"])
+	assert.Equal(t, []any{"CriticalAddonsOnly=true:NoExecute"}, serverConfig["node-taint"])
+	assert.Equal(t, "https://192.168.122.50:9345", serverConfig["server"])
+	assert.Equal(t, cluster.ServerConfig["tls-san"], serverConfig["tls-san"])
+
+	agentConfig := cluster.NodeConfigs["node4.suse.com"]
+	assert.Equal(t, []any{"zone=edge-2", "example.com/gpu=true"}, agentConfig["node-label"])
+	assert.Equal(t, []any{"dedicated=gpu:NoSchedule"}, agentConfig["node-taint"])
+	assert.Equal(t, "https://192.168.122.50:9345", agentConfig["server"])
+	assert.Nil(t, agentConfig["tls-san"])
+
+	// The shared configs remain unchanged
+	assert.Nil(t, cluster.InitialiserConfig["node-label"])
+	assert.Nil(t, cluster.ServerConfig["node-taint"])
+	assert.Nil(t, cluster.AgentConfig["node-label"])
+}
+
+func TestAppendNodeMetadata_ExistingValues(t *testing.T) {
+	config := map[string]any{
+		"node-label": "region=eu",
+		"node-taint": []any{"edge=true:NoSchedule"},
+	}
+
+	appendNodeMetadata(config, &image.Node{
+		Labels: []string{"zone=edge-1"},
+		Taints: []string{"dedicated=gpu:NoSchedule"},
+	})
+
+	assert.Equal(t, []any{"region=eu", "zone=edge-1"}, config["node-label"])
+	assert.Equal(t, []any{"edge=true:NoSchedule", "dedicated=gpu:NoSchedule"}, config["node-taint"])
+}
+
 func TestNewCluster_MultiNodeRKE2_MissingConfig(t *testing.T) {
 	kubernetes := &image.Kubernetes{
 		Version: "v1.29.0+rke2r1",
@@ -2,7 +2,9 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -16,9 +18,15 @@ const (
 	k3sInstallScriptURL  = "https://get.k3s.io"
 )
 
-type ScriptDownloader struct{}
+// ScriptDownloader downloads the install scripts of the Kubernetes distributions.
+type ScriptDownloader struct {
+	// Cache optionally keeps a copy of the downloaded install scripts.
+	Cache cache
+	// Offline copies the install scripts from the cache instead of downloading them.
+	Offline bool
+}
 
-func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath string) (string, error) {
+func (d ScriptDownloader) DownloadInstallScript(ctx context.Context, distribution, destinationPath string) (string, error) {
 	var scriptURL string
 
 	switch distribution {
@@ -33,7 +41,15 @@ func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath st
 	installer := fmt.Sprintf("%s_installer.sh", distribution)
 	destinationPath = filepath.Join(destinationPath, installer)
 
-	if err := http.DownloadFile(context.Background(), scriptURL, destinationPath, nil); err != nil {
+	if d.Offline {
+		if err := d.copyScriptFromCache(distribution, destinationPath); err != nil {
+			return "", err
+		}
+
+		return installer, nil
+	}
+
+	if err := http.DownloadFile(ctx, scriptURL, destinationPath, nil); err != nil {
 		return "", fmt.Errorf("downloading script: %w", err)
 	}
 
@@ -41,5 +57,53 @@ func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath st
 		return "", fmt.Errorf("modifying script permissions: %w", err)
 	}
 
+	if d.Cache != nil {
+		if err := d.cacheScript(distribution, destinationPath); err != nil {
+			return "", fmt.Errorf("caching script: %w", err)
+		}
+	}
+
 	return installer, nil
 }
+
+func (d ScriptDownloader) copyScriptFromCache(distribution, destinationPath string) error {
+	if d.Cache == nil {
+		return fmt.Errorf("no cache to copy the %s install script from", distribution)
+	}
+
+	sourcePath, err := d.Cache.Get(InstallScriptIdentifier(distribution))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("the %s install script is not cached", distribution)
+		}
+
+		return fmt.Errorf("querying cache: %w", err)
+	}
+
+	if err = fileio.CopyFile(sourcePath, destinationPath, fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("copying script from cache: %w", err)
+	}
+
+	return nil
+}
+
+// cacheScript keeps a copy of the install script for offline builds, unless one is already cached. Builds with
+// downloads always use the latest script.
+func (d ScriptDownloader) cacheScript(distribution, scriptPath string) error {
+	script, err := os.Open(scriptPath)
+	if err != nil {
+		return fmt.Errorf("opening script: %w", err)
+	}
+	defer script.Close()
+
+	if err = d.Cache.Put(InstallScriptIdentifier(distribution), script); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+
+	return nil
+}
+
+// InstallScriptIdentifier returns the cache identifier of the install script of the given distribution.
+func InstallScriptIdentifier(distribution string) string {
+	return fmt.Sprintf("install-scripts/%s_installer.sh", distribution)
+}
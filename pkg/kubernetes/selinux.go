@@ -49,9 +49,9 @@ func SELinuxRepository(version string) (image.AddRepo, error) {
 	}, nil
 }
 
-func DownloadSELinuxRPMsSigningKey(gpgKeysDir string) error {
+func DownloadSELinuxRPMsSigningKey(ctx context.Context, gpgKeysDir string) error {
 	const rancherSigningKeyURL = "https://rpm.rancher.io/public.key"
 	var signingKeyPath = filepath.Join(gpgKeysDir, "rancher-public.key")
 
-	return http.DownloadFile(context.Background(), rancherSigningKeyURL, signingKeyPath, nil)
+	return http.DownloadFile(ctx, rancherSigningKeyURL, signingKeyPath, nil)
 }
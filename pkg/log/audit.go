@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/text/cases"
@@ -42,20 +43,34 @@ func AuditError(message string) {
 }
 
 func AuditComponentSuccessful(component string) {
-	message := formatComponentStatus(component, messageSuccess)
+	message := formatComponentStatus(component, colorize(messageSuccess, colorGreen))
 	Audit(message)
 }
 
 func AuditComponentSkipped(component string) {
-	message := formatComponentStatus(component, messageSkipped)
+	message := formatComponentStatus(component, colorize(messageSkipped, colorYellow))
 	Audit(message)
 }
 
 func AuditComponentFailed(component string) {
-	message := formatComponentStatus(component, messageFailed)
+	message := formatComponentStatus(component, colorize(messageFailed, colorRed))
 	Audit(message)
 }
 
+// AuditPhaseStarted marks the start of a build phase, numbered among the enabled phases. The format of the
+// phase markers is kept stable for tools parsing the build output.
+func AuditPhaseStarted(phase string, number, total int) {
+	AuditInfo(formatPhase(phase, number, total))
+}
+
+func AuditPhaseCompleted(phase string, number, total int, elapsed time.Duration) {
+	AuditInfof("%s completed in %s", formatPhase(phase, number, total), elapsed.Round(time.Second))
+}
+
+func AuditPhaseFailed(phase string, number, total int, elapsed time.Duration) {
+	AuditInfof("%s failed after %s", formatPhase(phase, number, total), elapsed.Round(time.Second))
+}
+
 func doAudit(message string, logFunc func(args ...any)) {
 	fmt.Println(message)
 	if logFunc != nil {
@@ -66,6 +81,7 @@ func doAudit(message string, logFunc func(args ...any)) {
 func formatComponentStatus(component, status string) string {
 	// Example output:
 	// Component ... [STATUS]
+	// The status may be wrapped in color codes, so its length is not used in the calculation.
 
 	name := cases.Title(language.English).String(component)
 	numDots := lineLength - (len(name) + 2 + 9) // 2=spaces before/after dots, 9=status msg + []
@@ -74,3 +90,10 @@ func formatComponentStatus(component, status string) string {
 	message := fmt.Sprintf("%s %s [%s]", name, dots, status)
 	return message
 }
+
+func formatPhase(phase string, number, total int) string {
+	// Example output:
+	// ==> Phase: combustion (2/3)
+
+	return fmt.Sprintf("==> Phase: %s (%d/%d)", phase, number, total)
+}
@@ -42,3 +42,7 @@ func TestFormatComponentStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatPhase(t *testing.T) {
+	assert.Equal(t, "==> Phase: combustion (2/3)", formatPhase("combustion", 2, 3))
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"os"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+
+	noColorEnvVar = "NO_COLOR"
+)
+
+var colorEnabled = detectColor()
+
+// DisableColor turns off ANSI color codes in console output, regardless of whether
+// the output is attached to a terminal.
+func DisableColor() {
+	colorEnabled = false
+}
+
+// detectColor enables color only when stdout is a terminal and the NO_COLOR
+// environment variable (https://no-color.org) is not set.
+func detectColor() bool {
+	if _, set := os.LookupEnv(noColorEnvVar); set {
+		return false
+	}
+
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func colorize(message, color string) string {
+	if !colorEnabled {
+		return message
+	}
+
+	return color + message + colorReset
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColorize(t *testing.T) {
+	defer func(enabled bool) {
+		colorEnabled = enabled
+	}(colorEnabled)
+
+	tests := []struct {
+		testName string
+		enabled  bool
+		expected string
+	}{
+		{
+			testName: "Color enabled",
+			enabled:  true,
+			expected: "\033[32mSUCCESS\033[0m",
+		},
+		{
+			testName: "Color disabled",
+			enabled:  false,
+			expected: "SUCCESS",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			colorEnabled = test.enabled
+
+			found := colorize(messageSuccess, colorGreen)
+			assert.Equal(t, test.expected, found)
+		})
+	}
+}
+
+func TestDetectColor_NoColorEnvVar(t *testing.T) {
+	t.Setenv(noColorEnvVar, "")
+
+	assert.False(t, detectColor())
+}
@@ -5,7 +5,10 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func ConfigureGlobalLogger(logFilename string) {
+// rotatingLog is the rotating destination of the global logger, if rotation is enabled.
+var rotatingLog *rotatingFile
+
+func ConfigureGlobalLogger(logFilename string, rotation Rotation) {
 	logConfig := zap.NewProductionConfig()
 	logConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
 	logConfig.Encoding = "console"
@@ -13,8 +16,30 @@ func ConfigureGlobalLogger(logFilename string) {
 	logConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	logConfig.OutputPaths = []string{logFilename}
 
-	logger := zap.Must(logConfig.Build())
+	var logger *zap.Logger
+	if rotation.Enabled() {
+		logFile, err := newRotatingFile(logFilename, rotation)
+		if err != nil {
+			panic(err)
+		}
+		rotatingLog = logFile
+
+		core := zapcore.NewCore(zapcore.NewConsoleEncoder(logConfig.EncoderConfig), logFile, logConfig.Level)
+		logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+	} else {
+		logger = zap.Must(logConfig.Build())
+	}
 
 	// Set our configured logger to be accessed globally by zap.L()
 	zap.ReplaceGlobals(logger)
 }
+
+// RotatedLogFiles returns the names of the backups of the global log file which were created
+// through rotation, newest first. The most recent log content is always in the configured file.
+func RotatedLogFiles() []string {
+	if rotatingLog == nil {
+		return nil
+	}
+
+	return rotatingLog.Backups()
+}
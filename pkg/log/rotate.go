@@ -0,0 +1,153 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+)
+
+const bytesPerMegabyte = 1024 * 1024
+
+// Rotation configures the rotation of a log file. The zero value disables rotation.
+type Rotation struct {
+	// MaxSizeMB is the size in megabytes after which the log file is rotated.
+	MaxSizeMB int
+	// MaxAge is the duration after which the log file is rotated.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated log files to retain; older ones are removed.
+	MaxBackups int
+}
+
+func (r Rotation) Enabled() bool {
+	return r.MaxSizeMB > 0 || r.MaxAge > 0
+}
+
+// rotatingFile is a log destination which moves the current file aside once it exceeds
+// the configured size or age, so that the most recent content is always retained in
+// the original file name and older content in numbered backups (e.g. eib-build.log.1).
+type rotatingFile struct {
+	mu       sync.Mutex
+	filename string
+	rotation Rotation
+	now      func() time.Time
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newRotatingFile(filename string, rotation Rotation) (*rotatingFile, error) {
+	r := &rotatingFile{
+		filename: filename,
+		rotation: rotation,
+		now:      time.Now,
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shouldRotate(len(p)) {
+		if err := r.rotate(); err != nil {
+			return 0, fmt.Errorf("rotating log file: %w", err)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Sync()
+}
+
+// Backups returns the names of the rotated log files which are currently present, newest first.
+func (r *rotatingFile) Backups() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var backups []string
+	for i := 1; i <= r.rotation.MaxBackups; i++ {
+		name := r.backupName(i)
+		if _, err := os.Stat(name); err == nil {
+			backups = append(backups, name)
+		}
+	}
+
+	return backups
+}
+
+func (r *rotatingFile) shouldRotate(writeSize int) bool {
+	// Never rotate an empty file, otherwise a single oversized entry would cause an endless rotation
+	if r.size == 0 {
+		return false
+	}
+
+	if r.rotation.MaxSizeMB > 0 && r.size+int64(writeSize) > int64(r.rotation.MaxSizeMB)*bytesPerMegabyte {
+		return true
+	}
+
+	return r.rotation.MaxAge > 0 && r.now().Sub(r.openedAt) >= r.rotation.MaxAge
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("closing log file: %w", err)
+	}
+
+	if r.rotation.MaxBackups > 0 {
+		for i := r.rotation.MaxBackups - 1; i > 0; i-- {
+			if err := os.Rename(r.backupName(i), r.backupName(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("renaming log backup: %w", err)
+			}
+		}
+
+		if err := os.Rename(r.filename, r.backupName(1)); err != nil {
+			return fmt.Errorf("renaming log file: %w", err)
+		}
+	} else if err := os.Remove(r.filename); err != nil {
+		return fmt.Errorf("removing log file: %w", err)
+	}
+
+	return r.open()
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fileio.NonExecutablePerms)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("inspecting log file: %w", err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = r.now()
+
+	return nil
+}
+
+func (r *rotatingFile) backupName(index int) string {
+	return fmt.Sprintf("%s.%d", r.filename, index)
+}
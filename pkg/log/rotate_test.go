@@ -0,0 +1,122 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRotatingFile(t *testing.T, rotation Rotation) (*rotatingFile, func()) {
+	tmpDir, err := os.MkdirTemp("", "eib-log-rotation-")
+	require.NoError(t, err)
+
+	r, err := newRotatingFile(filepath.Join(tmpDir, "eib-build.log"), rotation)
+	require.NoError(t, err)
+
+	return r, func() {
+		_ = r.file.Close()
+		_ = os.RemoveAll(tmpDir)
+	}
+}
+
+func TestRotationEnabled(t *testing.T) {
+	assert.False(t, Rotation{}.Enabled())
+	assert.False(t, Rotation{MaxBackups: 3}.Enabled())
+	assert.True(t, Rotation{MaxSizeMB: 1}.Enabled())
+	assert.True(t, Rotation{MaxAge: time.Hour}.Enabled())
+}
+
+func TestRotatingFile_SizeRotation(t *testing.T) {
+	// Setup
+	r, teardown := setupRotatingFile(t, Rotation{MaxSizeMB: 1, MaxBackups: 2})
+	defer teardown()
+
+	halfMegabyte := strings.Repeat("a", bytesPerMegabyte/2)
+
+	// Test
+	for _, entry := range []string{"first", "second", "third", "fourth"} {
+		_, err := r.Write([]byte(entry + halfMegabyte))
+		require.NoError(t, err)
+	}
+
+	// Verify
+	current, err := os.ReadFile(r.filename)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(current), "fourth"))
+
+	backups := r.Backups()
+	require.Len(t, backups, 2)
+	assert.Equal(t, r.filename+".1", backups[0])
+	assert.Equal(t, r.filename+".2", backups[1])
+
+	newest, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(newest), "third"))
+
+	oldest, err := os.ReadFile(backups[1])
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(oldest), "second"))
+
+	_, err = os.Stat(r.filename + ".3")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRotatingFile_AgeRotation(t *testing.T) {
+	// Setup
+	r, teardown := setupRotatingFile(t, Rotation{MaxAge: time.Hour, MaxBackups: 1})
+	defer teardown()
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	r.openedAt = now
+
+	_, err := r.Write([]byte("old\n"))
+	require.NoError(t, err)
+
+	_, err = r.Write([]byte("recent\n"))
+	require.NoError(t, err)
+	assert.Empty(t, r.Backups())
+
+	// Test
+	now = now.Add(time.Hour)
+	_, err = r.Write([]byte("newest\n"))
+	require.NoError(t, err)
+
+	// Verify
+	current, err := os.ReadFile(r.filename)
+	require.NoError(t, err)
+	assert.Equal(t, "newest\n", string(current))
+
+	backups := r.Backups()
+	require.Len(t, backups, 1)
+
+	previous, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "old\nrecent\n", string(previous))
+}
+
+func TestRotatingFile_NoBackups(t *testing.T) {
+	// Setup
+	r, teardown := setupRotatingFile(t, Rotation{MaxSizeMB: 1})
+	defer teardown()
+
+	megabyte := strings.Repeat("a", bytesPerMegabyte)
+
+	// Test
+	_, err := r.Write([]byte(megabyte))
+	require.NoError(t, err)
+
+	_, err = r.Write([]byte("latest\n"))
+	require.NoError(t, err)
+
+	// Verify
+	current, err := os.ReadFile(r.filename)
+	require.NoError(t, err)
+	assert.Equal(t, "latest\n", string(current))
+	assert.Empty(t, r.Backups())
+}
@@ -0,0 +1,312 @@
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/archive"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/pkg/docker/config"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspec "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+	"go.uber.org/zap"
+)
+
+const (
+	// ArtifactType identifies images built by EIB in the registry.
+	ArtifactType = "application/vnd.suse.edge.image.v1"
+	// ReportMediaType is the media type of the build report stored as the artifact configuration.
+	ReportMediaType = "application/vnd.suse.edge.image.report.v1+json"
+	// imageMediaTypeFormat is the media type of the built image, specialized by its format (e.g. iso).
+	imageMediaTypeFormat = "application/vnd.suse.edge.image.layer.v1.%s"
+)
+
+var ErrNoCredentials = errors.New("no credentials found")
+
+// ParseReference parses a registry reference (e.g. registry.example.com/images/edge:1.0) to push to.
+// References without a tag default to 'latest'.
+func ParseReference(ref string) (types.ImageReference, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, fmt.Errorf("parsing reference: %w", err)
+	}
+
+	if _, isDigested := named.(reference.Digested); isDigested {
+		return nil, fmt.Errorf("reference '%s' must not contain a digest", ref)
+	}
+
+	imageRef, err := docker.NewReference(reference.TagNameOnly(named))
+	if err != nil {
+		return nil, fmt.Errorf("creating registry reference: %w", err)
+	}
+
+	return imageRef, nil
+}
+
+// CheckAuth verifies that the credentials configured for the registry of the reference are
+// accepted by the registry. It returns ErrNoCredentials if no credentials are configured.
+func CheckAuth(ctx context.Context, sys *types.SystemContext, imageRef types.ImageReference) error {
+	named := imageRef.DockerReference()
+	registry := reference.Domain(named)
+
+	creds, err := config.GetCredentials(sys, named.Name())
+	if err != nil {
+		return fmt.Errorf("reading credentials for '%s': %w", registry, err)
+	}
+
+	if creds.IdentityToken != "" {
+		// Identity tokens are exchanged during the push and cannot be verified upfront
+		zap.S().Infof("Using an identity token for registry '%s'", registry)
+		return nil
+	}
+
+	if creds.Username == "" && creds.Password == "" {
+		return ErrNoCredentials
+	}
+
+	if err = docker.CheckAuth(ctx, sys, creds.Username, creds.Password, registry); err != nil {
+		return fmt.Errorf("authenticating with registry '%s': %w", registry, err)
+	}
+
+	return nil
+}
+
+// Push packages the image along with the build report as an OCI artifact and pushes it to the
+// referenced registry. The artifact is assembled as an OCI layout in the given directory.
+// The digest of the pushed manifest is returned.
+func Push(ctx context.Context, sys *types.SystemContext, imageRef types.ImageReference, imagePath string,
+	buildReport *report.Report, layoutDir string,
+) (digest.Digest, error) {
+	if err := writeLayout(layoutDir, imagePath, buildReport); err != nil {
+		return "", fmt.Errorf("writing OCI layout: %w", err)
+	}
+
+	srcRef, err := layout.NewReference(layoutDir, "")
+	if err != nil {
+		return "", fmt.Errorf("creating layout reference: %w", err)
+	}
+
+	policyContext, err := newPolicyContext()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = policyContext.Destroy()
+	}()
+
+	manifestBytes, err := copy.Image(ctx, policyContext, imageRef, srcRef, &copy.Options{
+		DestinationCtx: sys,
+	})
+	if err != nil {
+		return "", fmt.Errorf("pushing artifact: %w", err)
+	}
+
+	manifestDigest, err := manifest.Digest(manifestBytes)
+	if err != nil {
+		return "", fmt.Errorf("calculating manifest digest: %w", err)
+	}
+
+	return manifestDigest, nil
+}
+
+// ImageArchiver stores container images as archives which can be loaded by podman.
+type ImageArchiver struct{}
+
+func (ImageArchiver) SaveImage(ctx context.Context, containerImage string, arch image.Arch, archivePath string) error {
+	return SaveImage(ctx, nil, containerImage, arch, archivePath)
+}
+
+// SaveImage pulls the container image for the given architecture from its registry and stores it as
+// a docker archive, which retains the name of the image when loaded (e.g. through 'podman load').
+// References without a tag default to 'latest', while references containing a digest are rejected.
+func SaveImage(ctx context.Context, sys *types.SystemContext, containerImage string, arch image.Arch, archivePath string) error {
+	srcRef, err := ParseReference(containerImage)
+	if err != nil {
+		return err
+	}
+
+	tagged, ok := srcRef.DockerReference().(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("reference '%s' is not tagged", containerImage)
+	}
+
+	destRef, err := archive.NewReference(archivePath, tagged)
+	if err != nil {
+		return fmt.Errorf("creating archive reference: %w", err)
+	}
+
+	srcCtx := &types.SystemContext{}
+	if sys != nil {
+		*srcCtx = *sys
+	}
+	srcCtx.OSChoice = "linux"
+	srcCtx.ArchitectureChoice = arch.Short()
+
+	policyContext, err := newPolicyContext()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = policyContext.Destroy()
+	}()
+
+	if _, err = copy.Image(ctx, policyContext, destRef, srcRef, &copy.Options{
+		SourceCtx:      srcCtx,
+		DestinationCtx: sys,
+	}); err != nil {
+		return fmt.Errorf("pulling image '%s': %w", containerImage, err)
+	}
+
+	return nil
+}
+
+func newPolicyContext() (*signature.PolicyContext, error) {
+	policyContext, err := signature.NewPolicyContext(&signature.Policy{
+		Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating policy context: %w", err)
+	}
+
+	return policyContext, nil
+}
+
+func writeLayout(layoutDir, imagePath string, buildReport *report.Report) error {
+	blobsDir := filepath.Join(layoutDir, "blobs", digest.Canonical.String())
+	if err := os.MkdirAll(blobsDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating blobs directory: %w", err)
+	}
+
+	reportData, err := json.Marshal(buildReport)
+	if err != nil {
+		return fmt.Errorf("serializing build report: %w", err)
+	}
+
+	reportDescriptor, err := writeBlob(blobsDir, ReportMediaType, reportData)
+	if err != nil {
+		return fmt.Errorf("writing build report: %w", err)
+	}
+
+	imageDescriptor, err := writeImageBlob(blobsDir, imagePath, buildReport)
+	if err != nil {
+		return fmt.Errorf("writing image: %w", err)
+	}
+
+	artifactManifest := imgspecv1.Manifest{
+		Versioned:    imgspec.Versioned{SchemaVersion: 2},
+		MediaType:    imgspecv1.MediaTypeImageManifest,
+		ArtifactType: ArtifactType,
+		Config:       reportDescriptor,
+		Layers:       []imgspecv1.Descriptor{imageDescriptor},
+		Annotations: map[string]string{
+			imgspecv1.AnnotationCreated: buildReport.Created,
+		},
+	}
+
+	manifestData, err := json.Marshal(artifactManifest)
+	if err != nil {
+		return fmt.Errorf("serializing manifest: %w", err)
+	}
+
+	manifestDescriptor, err := writeBlob(blobsDir, imgspecv1.MediaTypeImageManifest, manifestData)
+	if err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	index := imgspecv1.Index{
+		Versioned: imgspec.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageIndex,
+		Manifests: []imgspecv1.Descriptor{manifestDescriptor},
+	}
+
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("serializing index: %w", err)
+	}
+
+	if err = os.WriteFile(filepath.Join(layoutDir, imgspecv1.ImageIndexFile), indexData, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+
+	layoutData, err := json.Marshal(imgspecv1.ImageLayout{Version: imgspecv1.ImageLayoutVersion})
+	if err != nil {
+		return fmt.Errorf("serializing layout: %w", err)
+	}
+
+	if err = os.WriteFile(filepath.Join(layoutDir, imgspecv1.ImageLayoutFile), layoutData, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing layout: %w", err)
+	}
+
+	return nil
+}
+
+func writeBlob(blobsDir, mediaType string, data []byte) (imgspecv1.Descriptor, error) {
+	blobDigest := digest.FromBytes(data)
+
+	if err := os.WriteFile(filepath.Join(blobsDir, blobDigest.Encoded()), data, fileio.NonExecutablePerms); err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+
+	return imgspecv1.Descriptor{
+		MediaType: mediaType,
+		Digest:    blobDigest,
+		Size:      int64(len(data)),
+	}, nil
+}
+
+// writeImageBlob adds the image to the layout, avoiding a copy of the potentially large file when possible.
+func writeImageBlob(blobsDir, imagePath string, buildReport *report.Report) (imgspecv1.Descriptor, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return imgspecv1.Descriptor{}, fmt.Errorf("opening image: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return imgspecv1.Descriptor{}, fmt.Errorf("calculating image digest: %w", err)
+	}
+
+	blobDigest := digest.NewDigestFromEncoded(digest.SHA256, hex.EncodeToString(hash.Sum(nil)))
+	blobPath := filepath.Join(blobsDir, blobDigest.Encoded())
+
+	if err = os.Link(imagePath, blobPath); err != nil && !errors.Is(err, os.ErrExist) {
+		zap.S().Infof("Hard linking the image into the OCI layout failed, copying it instead: %s", err)
+
+		if err = fileio.CopyFile(imagePath, blobPath, fileio.NonExecutablePerms); err != nil {
+			return imgspecv1.Descriptor{}, fmt.Errorf("copying image: %w", err)
+		}
+	}
+
+	format := buildReport.OutputFormat
+	if format == "" {
+		format = buildReport.ImageType
+	}
+
+	return imgspecv1.Descriptor{
+		MediaType: fmt.Sprintf(imageMediaTypeFormat, format),
+		Digest:    blobDigest,
+		Size:      size,
+		Annotations: map[string]string{
+			imgspecv1.AnnotationTitle: buildReport.ImageName,
+		},
+	}, nil
+}
@@ -0,0 +1,186 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containers/image/v5/types"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/report"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := map[string]struct {
+		ref           string
+		expected      string
+		expectedError string
+	}{
+		"tagged": {
+			ref:      "registry.example.com/images/edge:1.0",
+			expected: "//registry.example.com/images/edge:1.0",
+		},
+		"untagged": {
+			ref:      "registry.example.com:5000/edge",
+			expected: "//registry.example.com:5000/edge:latest",
+		},
+		"digest": {
+			ref:           "registry.example.com/edge@sha256:" + "a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4",
+			expectedError: "must not contain a digest",
+		},
+		"invalid": {
+			ref:           "Registry.example.com/Edge:1.0",
+			expectedError: "parsing reference",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ref, err := ParseReference(test.ref)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, ref.StringWithinTransport())
+		})
+	}
+}
+
+func TestWriteLayout(t *testing.T) {
+	// Setup
+	tmpDir := t.TempDir()
+
+	imagePath := filepath.Join(tmpDir, "edge.iso")
+	require.NoError(t, os.WriteFile(imagePath, []byte("iso contents"), 0o600))
+
+	buildReport := &report.Report{ImageName: "edge.iso", ImageType: "iso", Created: "2024-01-01T00:00:00Z"}
+	layoutDir := filepath.Join(tmpDir, "layout")
+
+	// Test
+	err := writeLayout(layoutDir, imagePath, buildReport)
+
+	// Verify
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(layoutDir, imgspecv1.ImageLayoutFile))
+
+	indexData, err := os.ReadFile(filepath.Join(layoutDir, imgspecv1.ImageIndexFile))
+	require.NoError(t, err)
+
+	var index imgspecv1.Index
+	require.NoError(t, json.Unmarshal(indexData, &index))
+	require.Len(t, index.Manifests, 1)
+
+	manifestData, err := os.ReadFile(filepath.Join(layoutDir, "blobs", "sha256", index.Manifests[0].Digest.Encoded()))
+	require.NoError(t, err)
+
+	var artifactManifest imgspecv1.Manifest
+	require.NoError(t, json.Unmarshal(manifestData, &artifactManifest))
+
+	assert.Equal(t, ArtifactType, artifactManifest.ArtifactType)
+	assert.Equal(t, ReportMediaType, artifactManifest.Config.MediaType)
+	assert.Equal(t, "2024-01-01T00:00:00Z", artifactManifest.Annotations[imgspecv1.AnnotationCreated])
+
+	require.Len(t, artifactManifest.Layers, 1)
+	layer := artifactManifest.Layers[0]
+	assert.Equal(t, "application/vnd.suse.edge.image.layer.v1.iso", layer.MediaType)
+	assert.Equal(t, int64(len("iso contents")), layer.Size)
+	assert.Equal(t, "edge.iso", layer.Annotations[imgspecv1.AnnotationTitle])
+
+	layerData, err := os.ReadFile(filepath.Join(layoutDir, "blobs", "sha256", layer.Digest.Encoded()))
+	require.NoError(t, err)
+	assert.Equal(t, "iso contents", string(layerData))
+
+	reportData, err := os.ReadFile(filepath.Join(layoutDir, "blobs", "sha256", artifactManifest.Config.Digest.Encoded()))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imageName":"edge.iso","imageType":"iso","arch":"","baseImage":"","eibVersion":"","created":"2024-01-01T00:00:00Z"}`,
+		string(reportData))
+}
+
+func TestPush(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+
+	imagePath := filepath.Join(tmpDir, "edge.raw")
+	require.NoError(t, os.WriteFile(imagePath, []byte("raw contents"), 0o600))
+
+	imageRef, err := ParseReference(serverURL.Host + "/edge/image:1.0")
+	require.NoError(t, err)
+
+	sys := &types.SystemContext{
+		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+		AuthFilePath:                filepath.Join(tmpDir, "auth.json"),
+	}
+	buildReport := &report.Report{ImageName: "edge.raw", ImageType: "raw"}
+
+	// Test
+	pushedDigest, err := Push(context.Background(), sys, imageRef, imagePath, buildReport, filepath.Join(tmpDir, "layout"))
+
+	// Verify
+	require.NoError(t, err)
+	assert.NotEmpty(t, pushedDigest)
+
+	assert.ErrorIs(t, CheckAuth(context.Background(), sys, imageRef), ErrNoCredentials)
+}
+
+func TestSaveImage(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	containerImage := serverURL.Host + "/workloads/app:1.0"
+
+	img, err := random.Image(1024, 1)
+	require.NoError(t, err)
+
+	tag, err := name.NewTag(containerImage, name.Insecure)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(tag, img))
+
+	tmpDir := t.TempDir()
+	sys := &types.SystemContext{
+		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+		AuthFilePath:                filepath.Join(tmpDir, "auth.json"),
+	}
+	archivePath := filepath.Join(tmpDir, "app.tar")
+
+	// Test
+	err = SaveImage(context.Background(), sys, containerImage, image.ArchTypeX86, archivePath)
+
+	// Verify
+	require.NoError(t, err)
+
+	info, err := os.Stat(archivePath)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Size())
+}
+
+func TestSaveImage_InvalidReference(t *testing.T) {
+	digested := "registry.example.com/app@sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+
+	err := SaveImage(context.Background(), nil, digested, image.ArchTypeX86, filepath.Join(t.TempDir(), "app.tar"))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "must not contain a digest")
+}
@@ -35,13 +35,14 @@ type Podman struct {
 // New setups a podman listening service and returns a connected podman client.
 //
 // Parameters:
+//   - ctx - context of the podman commands, canceling it aborts the commands in progress
 //   - out - location for podman to output any logs created as a result of podman commands
-func New(out string) (*Podman, error) {
+func New(ctx context.Context, out string) (*Podman, error) {
 	if err := setupAPIListener(out); err != nil {
 		return nil, fmt.Errorf("creating new podman instance: %w", err)
 	}
 
-	conn, err := bindings.NewConnection(context.Background(), fmt.Sprintf(podmanSocketURI, podmanSocketPath))
+	conn, err := bindings.NewConnection(ctx, fmt.Sprintf(podmanSocketURI, podmanSocketPath))
 	if err != nil {
 		return nil, fmt.Errorf("creating new podman connection: %w", err)
 	}
@@ -2,17 +2,30 @@ package registry
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	crdAPIGroup = "apiextensions.k8s.io"
+	crdKind     = "CustomResourceDefinition"
 )
 
 type HelmChart struct {
 	CRD             HelmCRD
 	ContainerImages []string
+	// CRDsManifest holds the custom resource definitions of the chart, which are applied ahead of the
+	// release if requested through 'applyCRDsFirst'.
+	CRDsManifest string
+	// CRDNames are the names of the custom resource definitions in CRDsManifest.
+	CRDNames []string
 }
 
 func HelmCharts(helm *image.Helm, valuesDir, buildDir, kubeVersion string, helmClient image.HelmClient) ([]*HelmChart, error) {
@@ -69,6 +82,13 @@ func handleChart(chart *image.HelmChart, repo *image.HelmRepository, valuesDir,
 		ContainerImages: images,
 	}
 
+	if chart.ApplyCRDsFirst {
+		helmChart.CRDsManifest, helmChart.CRDNames, err = getChartCRDs(chart, helmClient, chartPath)
+		if err != nil {
+			return nil, fmt.Errorf("getting chart CRDs: %w", err)
+		}
+	}
+
 	return &helmChart, nil
 }
 
@@ -119,6 +139,58 @@ func getChartContainerImages(chart *image.HelmChart, helmClient image.HelmClient
 	return images, nil
 }
 
+// getChartCRDs returns the custom resource definitions shipped in the 'crds' directories of the chart,
+// along with their names, verifying that each of the resources is a custom resource definition.
+func getChartCRDs(chart *image.HelmChart, helmClient image.HelmClient, chartPath string) (string, []string, error) {
+	manifest, err := helmClient.ShowCRDs(chartPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("showing chart CRDs: %w", err)
+	}
+
+	var names []string
+
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for i := 1; ; i++ {
+		var resource struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+
+		err = decoder.Decode(&resource)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("decoding resource #%d of chart '%s': %w", i, chart.Name, err)
+		}
+
+		if resource.APIVersion == "" && resource.Kind == "" {
+			// Empty documents, e.g. those only holding comments
+			continue
+		}
+
+		if resource.Kind != crdKind || !strings.HasPrefix(resource.APIVersion, crdAPIGroup+"/") {
+			return "", nil, fmt.Errorf("resource #%d of chart '%s' is a '%s' resource, not a %s",
+				i, chart.Name, resource.Kind, crdKind)
+		}
+
+		if resource.Metadata.Name == "" {
+			return "", nil, fmt.Errorf("%s #%d of chart '%s' is missing 'metadata.name'", crdKind, i, chart.Name)
+		}
+
+		names = append(names, resource.Metadata.Name)
+	}
+
+	if len(names) == 0 {
+		return "", nil, nil
+	}
+
+	return manifest, names, nil
+}
+
 func mapChartRepos(helm *image.Helm) map[string]*image.HelmRepository {
 	chartRepoMap := make(map[string]*image.HelmRepository)
 
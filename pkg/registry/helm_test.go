@@ -17,6 +17,7 @@ type mockHelmClient struct {
 	registryLoginFunc func(repository *image.HelmRepository) error
 	pullFunc          func(chart string, repository *image.HelmRepository, version, destDir string) (string, error)
 	templateFunc      func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error)
+	showCRDsFunc      func(chartPath string) (string, error)
 }
 
 func (m mockHelmClient) AddRepo(repository *image.HelmRepository) error {
@@ -47,6 +48,13 @@ func (m mockHelmClient) Template(chart, repository, version, valuesFilePath, kub
 	panic("not implemented")
 }
 
+func (m mockHelmClient) ShowCRDs(chartPath string) (string, error) {
+	if m.showCRDsFunc != nil {
+		return m.showCRDsFunc(chartPath)
+	}
+	panic("not implemented")
+}
+
 func TestHelmCharts_ValuesFileNotFoundError(t *testing.T) {
 	helm := &image.Helm{
 		Charts: []image.HelmChart{
@@ -408,3 +416,88 @@ func TestMapChartRepos(t *testing.T) {
 
 	assert.True(t, reflect.DeepEqual(expectedMap, mapChartRepos(helm)))
 }
+
+func TestGetChartCRDs(t *testing.T) {
+	chart := &image.HelmChart{Name: "cert-manager"}
+
+	tests := map[string]struct {
+		manifest      string
+		expectedNames []string
+		expectedError string
+	}{
+		`no CRDs`: {
+			manifest: "",
+		},
+		`valid CRDs`: {
+			manifest: `---
+# Source: cert-manager/crds/certificates.yaml
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: certificates.cert-manager.io
+---
+# Source: cert-manager/crds/issuers.yaml
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: issuers.cert-manager.io
+`,
+			expectedNames: []string{"certificates.cert-manager.io", "issuers.cert-manager.io"},
+		},
+		`invalid YAML`: {
+			manifest:      "apiVersion: [",
+			expectedError: "decoding resource #1 of chart 'cert-manager'",
+		},
+		`not a CRD`: {
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`,
+			expectedError: "resource #1 of chart 'cert-manager' is a 'ConfigMap' resource, not a CustomResourceDefinition",
+		},
+		`missing name`: {
+			manifest: `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+`,
+			expectedError: "CustomResourceDefinition #1 of chart 'cert-manager' is missing 'metadata.name'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			helmClient := mockHelmClient{
+				showCRDsFunc: func(chartPath string) (string, error) {
+					assert.Equal(t, "cert-manager.tgz", chartPath)
+					return test.manifest, nil
+				},
+			}
+
+			manifest, names, err := getChartCRDs(chart, helmClient, "cert-manager.tgz")
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedNames, names)
+			if len(test.expectedNames) == 0 {
+				assert.Empty(t, manifest)
+			} else {
+				assert.Equal(t, test.manifest, manifest)
+			}
+		})
+	}
+}
+
+func TestGetChartCRDs_ShowFailure(t *testing.T) {
+	helmClient := mockHelmClient{
+		showCRDsFunc: func(chartPath string) (string, error) {
+			return "", fmt.Errorf("chart not found")
+		},
+	}
+
+	_, _, err := getChartCRDs(&image.HelmChart{Name: "cert-manager"}, helmClient, "cert-manager.tgz")
+	require.EqualError(t, err, "showing chart CRDs: chart not found")
+}
@@ -15,11 +15,46 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-func ManifestImages(manifestURLs []string, manifestsDir string) ([]string, error) {
+var knownKinds = []string{
+	"Namespace",
+	"Pod",
+	"Deployment",
+	"StatefulSet",
+	"DaemonSet",
+	"ReplicaSet",
+	"Job",
+	"CronJob",
+	"Service",
+	"ServiceAccount",
+	"ConfigMap",
+	"Secret",
+	"Ingress",
+	"IngressClass",
+	"NetworkPolicy",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"StorageClass",
+	"Role",
+	"RoleBinding",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"CustomResourceDefinition",
+	"PodDisruptionBudget",
+	"PriorityClass",
+	"LimitRange",
+	"ResourceQuota",
+	"HorizontalPodAutoscaler",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+	"HelmChart",
+	"HelmChartConfig",
+}
+
+func ManifestImages(ctx context.Context, manifestURLs []string, manifestsDirs ...string) ([]string, error) {
 	var manifestPaths []string
 
 	if len(manifestURLs) != 0 {
-		paths, err := DownloadManifests(manifestURLs, os.TempDir())
+		paths, err := DownloadManifests(ctx, manifestURLs, os.TempDir())
 		if err != nil {
 			return nil, fmt.Errorf("downloading manifests: %w", err)
 		}
@@ -27,7 +62,11 @@ func ManifestImages(manifestURLs []string, manifestsDir string) ([]string, error
 		manifestPaths = append(manifestPaths, paths...)
 	}
 
-	if manifestsDir != "" {
+	for _, manifestsDir := range manifestsDirs {
+		if manifestsDir == "" {
+			continue
+		}
+
 		paths, err := getManifestPaths(manifestsDir)
 		if err != nil {
 			return nil, fmt.Errorf("getting local manifest paths: %w", err)
@@ -85,6 +124,50 @@ func readManifest(manifestPath string) ([]map[string]any, error) {
 	return manifests, nil
 }
 
+// ManifestKinds parses the manifest at the given path and returns the kinds of all resources in it.
+// An error is returned if any of the resources is not a valid Kubernetes object.
+func ManifestKinds(manifestPath string) ([]string, error) {
+	manifests, err := readManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var kinds []string
+
+	for i, manifest := range manifests {
+		if manifest == nil {
+			continue
+		}
+
+		apiVersion, _ := manifest["apiVersion"].(string)
+		kind, _ := manifest["kind"].(string)
+		if apiVersion == "" || kind == "" {
+			return nil, fmt.Errorf("resource #%d in manifest '%s' is missing 'apiVersion' and/or 'kind'", i+1, manifestPath)
+		}
+
+		kinds = append(kinds, kind)
+	}
+
+	return kinds, nil
+}
+
+// IsKnownKind indicates whether the given kind is a built-in Kubernetes resource
+// or a resource natively supported by K3s and RKE2.
+func IsKnownKind(kind string) bool {
+	return slices.Contains(knownKinds, kind)
+}
+
+// ManifestPaths returns the paths to all YAML manifests in the given directory, sorted in lexical order.
+func ManifestPaths(manifestsDir string) ([]string, error) {
+	paths, err := getManifestPaths(manifestsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(paths)
+	return paths, nil
+}
+
 func storeManifestImages(resource map[string]any, images map[string]bool) {
 	var k8sKinds = []string{
 		"Pod",
@@ -150,17 +233,21 @@ func getManifestPaths(src string) ([]string, error) {
 	return manifestPaths, nil
 }
 
-func DownloadManifests(manifestURLs []string, destPath string) ([]string, error) {
+func DownloadManifests(ctx context.Context, manifestURLs []string, destPath string) ([]string, error) {
 	var manifestPaths []string
 
+	batch := http.NewBatch(len(manifestURLs))
+
 	for index, manifestURL := range manifestURLs {
 		filePath := filepath.Join(destPath, fmt.Sprintf("dl-manifest-%d.yaml", index+1))
 		manifestPaths = append(manifestPaths, filePath)
 
-		if err := http.DownloadFile(context.Background(), manifestURL, filePath, nil); err != nil {
+		if err := batch.DownloadFile(ctx, manifestURL, filePath, nil); err != nil {
 			return nil, fmt.Errorf("downloading manifest '%s': %w", manifestURL, err)
 		}
 	}
 
+	batch.Done()
+
 	return manifestPaths, nil
 }
@@ -3,6 +3,7 @@
 package registry
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -27,7 +28,7 @@ func TestDownloadManifests(t *testing.T) {
 	}
 
 	// Test
-	manifestPaths, err := DownloadManifests(manifestURLs, manifestDownloadDest)
+	manifestPaths, err := DownloadManifests(context.Background(), manifestURLs, manifestDownloadDest)
 
 	// Verify
 	require.NoError(t, err)
@@ -66,7 +67,7 @@ func TestManifestImages(t *testing.T) {
 	manifestURLs := []string{"https://k8s.io/examples/application/nginx-app.yaml"}
 
 	// Test
-	containerImages, err := ManifestImages(manifestURLs, manifestSrcDir)
+	containerImages, err := ManifestImages(context.Background(), manifestURLs, manifestSrcDir)
 
 	// Verify
 	require.NoError(t, err)
@@ -1,6 +1,7 @@
 package registry
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -70,6 +71,44 @@ func TestReadManifest_EmptyManifest(t *testing.T) {
 	assert.Error(t, err, "invalid manifest")
 }
 
+func TestManifestKinds(t *testing.T) {
+	// Setup
+	manifestPath := filepath.Join("testdata", "sample-crd.yaml")
+
+	// Test
+	kinds, err := ManifestKinds(manifestPath)
+
+	// Verify
+	require.NoError(t, err)
+	require.NotEmpty(t, kinds)
+	assert.Equal(t, "Deployment", kinds[0])
+}
+
+func TestManifestKinds_MissingKind(t *testing.T) {
+	// Setup
+	dir, err := os.MkdirTemp("", "eib-manifest-kinds-")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, os.RemoveAll(dir))
+	}()
+
+	manifestPath := filepath.Join(dir, "missing-kind.yaml")
+	require.NoError(t, os.WriteFile(manifestPath, []byte("apiVersion: v1\nmetadata:\n  name: foo\n"), fileio.NonExecutablePerms))
+
+	// Test
+	_, err = ManifestKinds(manifestPath)
+
+	// Verify
+	require.ErrorContains(t, err, "resource #1 in manifest")
+	require.ErrorContains(t, err, "is missing 'apiVersion' and/or 'kind'")
+}
+
+func TestIsKnownKind(t *testing.T) {
+	assert.True(t, IsKnownKind("Deployment"))
+	assert.True(t, IsKnownKind("HelmChart"))
+	assert.False(t, IsKnownKind("MyCustomResource"))
+}
+
 func TestStoreManifestImages(t *testing.T) {
 	// Setup
 	var extractedImagesSet = make(map[string]bool)
@@ -189,7 +228,7 @@ func TestManifestImages_InvalidURL(t *testing.T) {
 	}
 
 	// Test
-	_, err := ManifestImages(manifestURLs, "")
+	_, err := ManifestImages(context.Background(), manifestURLs, "")
 
 	// Verify
 	require.ErrorContains(t, err, "downloading manifests: downloading manifest 'k8s.io/examples/application/nginx-app.yaml': executing request: Get \"k8s.io/examples/application/nginx-app.yaml\": unsupported protocol scheme \"\"")
@@ -197,7 +236,7 @@ func TestManifestImages_InvalidURL(t *testing.T) {
 
 func TestManifestImages_LocalManifestDirNotDefined(t *testing.T) {
 	// Test
-	containerImages, err := ManifestImages(nil, "")
+	containerImages, err := ManifestImages(context.Background(), nil, "")
 
 	// Verify
 	require.NoError(t, err)
@@ -209,7 +248,7 @@ func TestManifestImages_InvalidLocalManifestsDir(t *testing.T) {
 	localManifestsDir := "does-not-exist"
 
 	// Test
-	_, err := ManifestImages(nil, localManifestsDir)
+	_, err := ManifestImages(context.Background(), nil, localManifestsDir)
 
 	// Verify
 	require.ErrorContains(t, err, "getting local manifest paths: reading manifest source dir 'does-not-exist': open does-not-exist: no such file or directory")
@@ -220,7 +259,7 @@ func TestDownloadManifests_NoManifest(t *testing.T) {
 	manifestDownloadDest := ""
 
 	// Test
-	manifestPaths, err := DownloadManifests(nil, manifestDownloadDest)
+	manifestPaths, err := DownloadManifests(context.Background(), nil, manifestDownloadDest)
 
 	// Verify
 	require.NoError(t, err)
@@ -233,7 +272,7 @@ func TestDownloadManifests_InvalidURL(t *testing.T) {
 	manifestDownloadDest := ""
 
 	// Test
-	manifestPaths, err := DownloadManifests(manifestURLs, manifestDownloadDest)
+	manifestPaths, err := DownloadManifests(context.Background(), manifestURLs, manifestDownloadDest)
 
 	// Verify
 	require.ErrorContains(t, err, "downloading manifest 'k8s.io/examples/application/nginx-app.yaml': executing request: Get \"k8s.io/examples/application/nginx-app.yaml\": unsupported protocol scheme \"")
@@ -252,7 +291,7 @@ func TestManifestImages_InvalidLocalManifest(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test
-	_, err = ManifestImages(nil, localManifestsSrcDir)
+	_, err = ManifestImages(context.Background(), nil, localManifestsSrcDir)
 
 	// Verify
 	require.ErrorContains(t, err, "reading manifest: error unmarshalling manifest yaml")
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+)
+
+// PackerBuilderType identifies the builds of EIB in Packer manifests.
+const PackerBuilderType = "edge-image-builder"
+
+// PackerManifest is the manifest written by the Packer manifest post-processor, listing the artifacts of each run.
+type PackerManifest struct {
+	Builds      []PackerBuild `json:"builds"`
+	LastRunUUID string        `json:"last_run_uuid"`
+}
+
+// PackerBuild describes the artifact of a build in a Packer manifest.
+type PackerBuild struct {
+	Name          string       `json:"name"`
+	BuilderType   string       `json:"builder_type"`
+	BuildTime     int64        `json:"build_time"`
+	Files         []PackerFile `json:"files"`
+	ArtifactID    string       `json:"artifact_id"`
+	PackerRunUUID string       `json:"packer_run_uuid"`
+	// CustomData carries the fields of the build report describing the image.
+	CustomData map[string]string `json:"custom_data"`
+}
+
+// PackerFile is a file of the artifact of a build.
+type PackerFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// NewPackerBuild describes the build in the format of Packer manifests, recording the files making up its artifact.
+func NewPackerBuild(report *Report, files []PackerFile) (*PackerBuild, error) {
+	created, err := time.Parse(time.RFC3339, report.Created)
+	if err != nil {
+		return nil, fmt.Errorf("parsing creation time: %w", err)
+	}
+
+	customData := map[string]string{
+		"image_type":  report.ImageType,
+		"arch":        report.Arch,
+		"base_image":  report.BaseImage,
+		"eib_version": report.EIBVersion,
+	}
+
+	optionalData := map[string]string{
+		"output_format":      report.OutputFormat,
+		"base_image_release": report.BaseImageRelease,
+		"definition_hash":    report.DefinitionHash,
+		"kubernetes_version": report.KubernetesVersion,
+	}
+	for key, value := range optionalData {
+		if value != "" {
+			customData[key] = value
+		}
+	}
+
+	return &PackerBuild{
+		Name:          report.ImageName,
+		BuilderType:   PackerBuilderType,
+		BuildTime:     created.Unix(),
+		Files:         files,
+		ArtifactID:    report.ImageName,
+		PackerRunUUID: uuid.NewString(),
+		CustomData:    customData,
+	}, nil
+}
+
+// ReadPackerManifest parses the Packer manifest at the given path, returning an empty manifest if it does not exist.
+func ReadPackerManifest(path string) (*PackerManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &PackerManifest{}, nil
+		}
+
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	var manifest PackerManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// WritePackerManifest appends the build to the Packer manifest at the given path, which is created if it does
+// not exist. As with the Packer manifest post-processor, earlier builds are retained and the build is recorded
+// as the last run.
+func WritePackerManifest(build *PackerBuild, path string) error {
+	manifest, err := ReadPackerManifest(path)
+	if err != nil {
+		return err
+	}
+
+	manifest.Builds = append(manifest.Builds, *build)
+	manifest.LastRunUUID = build.PackerRunUUID
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing manifest: %w", err)
+	}
+
+	if err = os.WriteFile(path, append(data, '\n'), fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestNewPackerBuild(t *testing.T) {
+	definition := &image.Definition{
+		Image: image.Image{
+			ImageType:       image.TypeRAW,
+			Arch:            image.ArchTypeX86,
+			BaseImage:       "SL-Micro.x86_64-6.0-Base-GM2.raw",
+			OutputImageName: "edge.raw",
+		},
+	}
+
+	report := New(definition, time.Date(2024, time.May, 1, 10, 0, 0, 0, time.UTC))
+	report.DefinitionHash = "abc123"
+
+	files := []PackerFile{{Name: "/eib/edge.raw", Size: 1024}}
+	build, err := NewPackerBuild(report, files)
+	require.NoError(t, err)
+
+	assert.Equal(t, "edge.raw", build.Name)
+	assert.Equal(t, PackerBuilderType, build.BuilderType)
+	assert.Equal(t, int64(1714557600), build.BuildTime)
+	assert.Equal(t, files, build.Files)
+	assert.Equal(t, "edge.raw", build.ArtifactID)
+	assert.NotEmpty(t, build.PackerRunUUID)
+	assert.Equal(t, map[string]string{
+		"image_type":         image.TypeRAW,
+		"arch":               string(image.ArchTypeX86),
+		"base_image":         "SL-Micro.x86_64-6.0-Base-GM2.raw",
+		"base_image_release": "SL Micro 6.0",
+		"definition_hash":    "abc123",
+		"eib_version":        report.EIBVersion,
+	}, build.CustomData)
+}
+
+func TestWritePackerManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "packer-manifest.json")
+
+	first := &PackerBuild{Name: "edge.raw", BuilderType: PackerBuilderType, PackerRunUUID: "first"}
+	require.NoError(t, WritePackerManifest(first, path))
+
+	second := &PackerBuild{Name: "edge.iso", BuilderType: PackerBuilderType, PackerRunUUID: "second"}
+	require.NoError(t, WritePackerManifest(second, path))
+
+	manifest, err := ReadPackerManifest(path)
+	require.NoError(t, err)
+	assert.Equal(t, []PackerBuild{*first, *second}, manifest.Builds)
+	assert.Equal(t, "second", manifest.LastRunUUID)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"last_run_uuid": "second"`)
+	assert.Contains(t, string(data), `"builder_type": "edge-image-builder"`)
+}
+
+func TestReadPackerManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	manifest, err := ReadPackerManifest(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Builds)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("not json"), 0o600))
+
+	_, err = ReadPackerManifest(invalid)
+	require.ErrorContains(t, err, "parsing manifest")
+}
@@ -0,0 +1,584 @@
+package report
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+
+	filenamePrefix = "build-report"
+)
+
+// Formats lists the supported report formats, the first one being the default.
+var Formats = []string{FormatJSON, FormatYAML}
+
+// Report describes the built image. The fields are named identically in all formats.
+type Report struct {
+	ImageName         string            `json:"imageName" yaml:"imageName"`
+	ImageType         string            `json:"imageType" yaml:"imageType"`
+	OutputFormat      string            `json:"outputFormat,omitempty" yaml:"outputFormat,omitempty"`
+	Qcow2             *Qcow2            `json:"qcow2,omitempty" yaml:"qcow2,omitempty"`
+	Arch              string            `json:"arch" yaml:"arch"`
+	BaseImage         string            `json:"baseImage" yaml:"baseImage"`
+	BaseImageOverride string            `json:"baseImageOverride,omitempty" yaml:"baseImageOverride,omitempty"`
+	BaseImageRelease  string            `json:"baseImageRelease,omitempty" yaml:"baseImageRelease,omitempty"`
+	DefinitionHash    string            `json:"definitionHash,omitempty" yaml:"definitionHash,omitempty"`
+	KubernetesVersion string            `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+	KubeconfigPath    string            `json:"kubeconfigPath,omitempty" yaml:"kubeconfigPath,omitempty"`
+	ContainerdConfig  *ContainerdConfig `json:"containerdConfig,omitempty" yaml:"containerdConfig,omitempty"`
+	HelmCharts        []string          `json:"helmCharts,omitempty" yaml:"helmCharts,omitempty"`
+	HelmChartCRDs     []HelmChartCRDs   `json:"helmChartCRDs,omitempty" yaml:"helmChartCRDs,omitempty"`
+	NodeMetadata      []NodeMetadata    `json:"nodeMetadata,omitempty" yaml:"nodeMetadata,omitempty"`
+	PasswordPolicies  []PasswordPolicy  `json:"passwordPolicies,omitempty" yaml:"passwordPolicies,omitempty"`
+	AutoUpdate        *AutoUpdate       `json:"autoUpdate,omitempty" yaml:"autoUpdate,omitempty"`
+	ScheduledJobs     []string          `json:"scheduledJobs,omitempty" yaml:"scheduledJobs,omitempty"`
+	Logrotate         []LogRotation     `json:"logrotate,omitempty" yaml:"logrotate,omitempty"`
+	RootSlots         []RootSlot        `json:"rootSlots,omitempty" yaml:"rootSlots,omitempty"`
+	Mounts            []Mount           `json:"mounts,omitempty" yaml:"mounts,omitempty"`
+	Directories       []string          `json:"directories,omitempty" yaml:"directories,omitempty"`
+	Environment       []string          `json:"environment,omitempty" yaml:"environment,omitempty"`
+	ShellDefaults     *ShellDefaults    `json:"shellDefaults,omitempty" yaml:"shellDefaults,omitempty"`
+	Locales           []string          `json:"locales,omitempty" yaml:"locales,omitempty"`
+	GPUDriver         *GPUDriver        `json:"gpuDriver,omitempty" yaml:"gpuDriver,omitempty"`
+	Zram              *Zram             `json:"zram,omitempty" yaml:"zram,omitempty"`
+	Kdump             *Kdump            `json:"kdump,omitempty" yaml:"kdump,omitempty"`
+	PAMConfigFiles    []string          `json:"pamConfigFiles,omitempty" yaml:"pamConfigFiles,omitempty"`
+	MachineIDPolicy   string            `json:"machineIDPolicy,omitempty" yaml:"machineIDPolicy,omitempty"`
+	Hostname          string            `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	RootMountOptions  string            `json:"rootMountOptions,omitempty" yaml:"rootMountOptions,omitempty"`
+	ImageArchives     []ImageArchive    `json:"imageArchives,omitempty" yaml:"imageArchives,omitempty"`
+	Certificates      []Certificate     `json:"certificates,omitempty" yaml:"certificates,omitempty"`
+	ReadOnlyRoot      *ReadOnlyRoot     `json:"readOnlyRoot,omitempty" yaml:"readOnlyRoot,omitempty"`
+	SecureBoot        *SecureBoot       `json:"secureBoot,omitempty" yaml:"secureBoot,omitempty"`
+	Autologin         *Autologin        `json:"autologin,omitempty" yaml:"autologin,omitempty"`
+	KnownHosts        []KnownHost       `json:"knownHosts,omitempty" yaml:"knownHosts,omitempty"`
+	Kernel            *Kernel           `json:"kernel,omitempty" yaml:"kernel,omitempty"`
+	NetworkProfiles   *NetworkProfiles  `json:"networkProfiles,omitempty" yaml:"networkProfiles,omitempty"`
+	StripDocs         *StripDocs        `json:"stripDocs,omitempty" yaml:"stripDocs,omitempty"`
+	Fail2ban          *Fail2ban         `json:"fail2ban,omitempty" yaml:"fail2ban,omitempty"`
+	Dnsmasq           *Dnsmasq          `json:"dnsmasq,omitempty" yaml:"dnsmasq,omitempty"`
+	Packages          *Packages         `json:"packages,omitempty" yaml:"packages,omitempty"`
+	Downloads         []Download        `json:"downloads,omitempty" yaml:"downloads,omitempty"`
+	CombustionISO     string            `json:"combustionISO,omitempty" yaml:"combustionISO,omitempty"`
+	SeedISOs          []SeedISO         `json:"seedISOs,omitempty" yaml:"seedISOs,omitempty"`
+	EIBVersion        string            `json:"eibVersion" yaml:"eibVersion"`
+	Created           string            `json:"created" yaml:"created"`
+}
+
+// SeedISO is a cloud-init seed ISO built for a node alongside the image.
+type SeedISO struct {
+	Hostname string `json:"hostname" yaml:"hostname"`
+	Path     string `json:"path" yaml:"path"`
+}
+
+// ContainerdConfig describes the containerd configuration override installed on the Kubernetes nodes.
+type ContainerdConfig struct {
+	ConfigFile string `json:"configFile" yaml:"configFile"`
+	Mode       string `json:"mode" yaml:"mode"`
+}
+
+// AutoUpdate describes the automatic OS update policy embedded in the image.
+type AutoUpdate struct {
+	Enabled      bool   `json:"enabled" yaml:"enabled"`
+	Schedule     string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	RebootPolicy string `json:"rebootPolicy,omitempty" yaml:"rebootPolicy,omitempty"`
+}
+
+// HelmChartCRDs describes the custom resource definitions of a Helm chart which are applied ahead of its release.
+type HelmChartCRDs struct {
+	Chart    string   `json:"chart" yaml:"chart"`
+	Manifest string   `json:"manifest" yaml:"manifest"`
+	CRDs     []string `json:"crds" yaml:"crds"`
+}
+
+// NodeMetadata describes the labels and taints a Kubernetes node is registered with.
+type NodeMetadata struct {
+	Hostname string   `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	Labels   []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Taints   []string `json:"taints,omitempty" yaml:"taints,omitempty"`
+}
+
+// ImageArchive describes a container image archive placed on the node.
+type ImageArchive struct {
+	Image string `json:"image" yaml:"image"`
+	Path  string `json:"path" yaml:"path"`
+	// Size is the size of the archive in bytes.
+	Size int64 `json:"size" yaml:"size"`
+}
+
+// Certificate describes a file of CA certificates installed in the trust store of the node, listing the
+// subjects of its chain from the root CA down.
+type Certificate struct {
+	File     string   `json:"file" yaml:"file"`
+	Subjects []string `json:"subjects" yaml:"subjects"`
+}
+
+// LogRotation describes a logrotate configuration installed on the node.
+type LogRotation struct {
+	Name  string   `json:"name" yaml:"name"`
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
+// ShellDefaults describes the defaults set for the login shells of the node.
+type ShellDefaults struct {
+	Editor      string            `json:"editor,omitempty" yaml:"editor,omitempty"`
+	Pager       string            `json:"pager,omitempty" yaml:"pager,omitempty"`
+	Environment map[string]string `json:"environment,omitempty" yaml:"environment,omitempty"`
+}
+
+// Zram describes the zram swap device configured on the node.
+type Zram struct {
+	Size      string  `json:"size,omitempty" yaml:"size,omitempty"`
+	Ratio     float64 `json:"ratio,omitempty" yaml:"ratio,omitempty"`
+	Algorithm string  `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+}
+
+// Kdump describes the memory reserved for the crash kernel and where kdump saves the dumps to.
+type Kdump struct {
+	Memory   string `json:"memory" yaml:"memory"`
+	SavePath string `json:"savePath" yaml:"savePath"`
+}
+
+// SecureBoot describes the Secure Boot configuration of the image. The signed artefacts are only known once
+// the image is assembled.
+type SecureBoot struct {
+	MOKCertificates []string `json:"mokCertificates,omitempty" yaml:"mokCertificates,omitempty"`
+	Lockdown        string   `json:"lockdown,omitempty" yaml:"lockdown,omitempty"`
+	SignKernel      bool     `json:"signKernel" yaml:"signKernel"`
+	SignModules     bool     `json:"signModules" yaml:"signModules"`
+	SignedKernels   []string `json:"signedKernels,omitempty" yaml:"signedKernels,omitempty"`
+	SignedModules   []string `json:"signedModules,omitempty" yaml:"signedModules,omitempty"`
+}
+
+// Autologin describes the console a user is logged in on without authentication.
+type Autologin struct {
+	User string `json:"user" yaml:"user"`
+	TTY  string `json:"tty" yaml:"tty"`
+}
+
+// KnownHost describes an SSH host key pinned in the system-wide known_hosts.
+type KnownHost struct {
+	Host        string `json:"host" yaml:"host"`
+	Type        string `json:"type" yaml:"type"`
+	Fingerprint string `json:"fingerprint,omitempty" yaml:"fingerprint,omitempty"`
+}
+
+// Dnsmasq describes the DHCP and DNS service provided by dnsmasq on the node.
+type Dnsmasq struct {
+	Interfaces   []string `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+	Domain       string   `json:"domain,omitempty" yaml:"domain,omitempty"`
+	Upstreams    []string `json:"upstreams,omitempty" yaml:"upstreams,omitempty"`
+	Ranges       []string `json:"ranges,omitempty" yaml:"ranges,omitempty"`
+	StaticLeases int      `json:"staticLeases" yaml:"staticLeases"`
+}
+
+// Fail2ban describes the fail2ban jails enabled on the node.
+type Fail2ban struct {
+	Jails []string `json:"jails" yaml:"jails"`
+}
+
+// Qcow2 describes the sizes of the converted qcow2 image, in bytes.
+type Qcow2 struct {
+	VirtualSize int64 `json:"virtualSize" yaml:"virtualSize"`
+	ActualSize  int64 `json:"actualSize" yaml:"actualSize"`
+}
+
+// StripDocs describes the documentation stripped from the base image and the space this reclaimed.
+type StripDocs struct {
+	Exclude     []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	ReclaimedKB int64    `json:"reclaimedKB" yaml:"reclaimedKB"`
+}
+
+// NetworkProfiles describes the network profiles embedded in the image, one of which is applied on first boot.
+type NetworkProfiles struct {
+	KernelArg string   `json:"kernelArg" yaml:"kernelArg"`
+	Default   string   `json:"default" yaml:"default"`
+	Profiles  []string `json:"profiles" yaml:"profiles"`
+}
+
+// Kernel describes the kernel pinned in place of the one of the base image.
+type Kernel struct {
+	Package string `json:"package" yaml:"package"`
+	Version string `json:"version" yaml:"version"`
+	Release string `json:"release,omitempty" yaml:"release,omitempty"`
+}
+
+// Download describes the timing of an artifact downloaded by the build, recorded with --profile-artifacts.
+type Download struct {
+	Artifact string `json:"artifact" yaml:"artifact"`
+	Source   string `json:"source" yaml:"source"`
+	Bytes    int64  `json:"bytes" yaml:"bytes"`
+	Started  string `json:"started" yaml:"started"`
+	Finished string `json:"finished" yaml:"finished"`
+	// Seconds is the duration of the download.
+	Seconds float64 `json:"seconds" yaml:"seconds"`
+}
+
+// ReadOnlyRoot describes the writable overlays of the read-only root file system of the node.
+type ReadOnlyRoot struct {
+	Overlays []Overlay `json:"overlays" yaml:"overlays"`
+}
+
+// Overlay describes a writable mount over a directory of the read-only root file system.
+type Overlay struct {
+	Path string `json:"path" yaml:"path"`
+	Type string `json:"type" yaml:"type"`
+	Size string `json:"size,omitempty" yaml:"size,omitempty"`
+	// UpperDir is the directory storing the changes of persistent overlays.
+	UpperDir string `json:"upperDir,omitempty" yaml:"upperDir,omitempty"`
+}
+
+// Packages describes the RPMs embedded in the image, i.e. the closure of the requested packages and
+// side-loaded RPMs over the dependencies missing from the base image.
+type Packages struct {
+	Requested []string          `json:"requested" yaml:"requested"`
+	Resolved  []ResolvedPackage `json:"resolved" yaml:"resolved"`
+	Count     int               `json:"count" yaml:"count"`
+	// Size is the total size of the resolved RPMs in bytes.
+	Size int64 `json:"size" yaml:"size"`
+}
+
+// ResolvedPackage describes an RPM embedded in the image.
+type ResolvedPackage struct {
+	File string `json:"file" yaml:"file"`
+	Size int64  `json:"size" yaml:"size"`
+}
+
+// GPUDriver describes the GPU driver embedded in the image.
+type GPUDriver struct {
+	Version  string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Packages []string `json:"packages" yaml:"packages"`
+}
+
+// PasswordPolicy describes the password expiry configured for a user.
+type PasswordPolicy struct {
+	Username    string `json:"username" yaml:"username"`
+	ExpireDays  int    `json:"expireDays,omitempty" yaml:"expireDays,omitempty"`
+	ForceChange bool   `json:"forceChange,omitempty" yaml:"forceChange,omitempty"`
+}
+
+// RootSlot describes one of the A/B root partitions of a raw image, as found in the assembled image.
+type RootSlot struct {
+	Name      string `json:"name" yaml:"name"`
+	Partition int    `json:"partition" yaml:"partition"`
+	Label     string `json:"label" yaml:"label"`
+	SizeMB    int64  `json:"sizeMB" yaml:"sizeMB"`
+	Default   bool   `json:"default" yaml:"default"`
+}
+
+// Mount describes a file system mounted on the node, either by the base image or as configured in the definition.
+type Mount struct {
+	Path        string `json:"path" yaml:"path"`
+	Description string `json:"description" yaml:"description"`
+	// Field is the definition field configuring the mount, empty for the mounts of the base image.
+	Field string `json:"field,omitempty" yaml:"field,omitempty"`
+}
+
+// New describes the image built from the given definition at the given time.
+func New(definition *image.Definition, created time.Time) *Report {
+	var autoUpdate *AutoUpdate
+	if au := definition.OperatingSystem.AutoUpdate; au != (image.AutoUpdate{}) {
+		autoUpdate = &AutoUpdate{
+			Enabled:      au.Enabled,
+			Schedule:     au.Schedule,
+			RebootPolicy: au.RebootPolicy,
+		}
+	}
+
+	var helmCharts []string
+	for _, chart := range definition.Kubernetes.Helm.Charts {
+		if !slices.Contains(helmCharts, chart.Name) {
+			helmCharts = append(helmCharts, chart.Name)
+		}
+	}
+
+	var nodeMetadata []NodeMetadata
+	for _, node := range definition.Kubernetes.Nodes {
+		if len(node.Labels) > 0 || len(node.Taints) > 0 {
+			nodeMetadata = append(nodeMetadata, NodeMetadata{
+				Hostname: node.Hostname,
+				Labels:   node.Labels,
+				Taints:   node.Taints,
+			})
+		}
+	}
+
+	var containerdConfig *ContainerdConfig
+	if containerd := definition.Kubernetes.Containerd; containerd.ConfigFile != "" {
+		containerdConfig = &ContainerdConfig{
+			ConfigFile: containerd.ConfigFile,
+			Mode:       containerd.Mode,
+		}
+
+		if containerdConfig.Mode == "" {
+			containerdConfig.Mode = image.ContainerdConfigModePartial
+		}
+	}
+
+	var passwordPolicies []PasswordPolicy
+	for _, user := range definition.OperatingSystem.Users {
+		if user.PasswordExpire > 0 || user.ForceChange {
+			passwordPolicies = append(passwordPolicies, PasswordPolicy{
+				Username:    user.Username,
+				ExpireDays:  user.PasswordExpire,
+				ForceChange: user.ForceChange,
+			})
+		}
+	}
+
+	var scheduledJobs []string
+	for _, job := range definition.OperatingSystem.ScheduledJobs {
+		scheduledJobs = append(scheduledJobs, job.Name)
+	}
+
+	var logrotate []LogRotation
+	for _, rotation := range definition.OperatingSystem.Logrotate {
+		logrotate = append(logrotate, LogRotation{
+			Name:  rotation.Name,
+			Paths: rotation.Paths,
+		})
+	}
+
+	var directories []string
+	for _, directory := range definition.OperatingSystem.Directories {
+		directories = append(directories, directory.Path)
+	}
+
+	// Only the names are reported, keeping sensitive values out of the report
+	var environment []string
+	for name := range definition.OperatingSystem.Environment {
+		environment = append(environment, name)
+	}
+	slices.Sort(environment)
+
+	var machineIDPolicy string
+	switch machineID := definition.OperatingSystem.MachineID; {
+	case machineID.Clear:
+		machineIDPolicy = "clear"
+	case machineID.Value != "":
+		machineIDPolicy = "fixed"
+	case machineID.Seed != "":
+		machineIDPolicy = "seeded"
+	}
+
+	var gpuDriver *GPUDriver
+	if drivers := definition.OperatingSystem.GPUDrivers; len(drivers.Packages) > 0 {
+		gpuDriver = &GPUDriver{
+			Version:  drivers.Version,
+			Packages: drivers.Packages,
+		}
+	}
+
+	var shellDefaults *ShellDefaults
+	if d := definition.OperatingSystem.ShellDefaults; d.Editor != "" || d.Pager != "" || len(d.Environment) != 0 {
+		shellDefaults = &ShellDefaults{
+			Editor:      d.Editor,
+			Pager:       d.Pager,
+			Environment: d.Environment,
+		}
+	}
+
+	var zram *Zram
+	if z := definition.OperatingSystem.Zram; z.Size != "" || z.Ratio != 0 {
+		zram = &Zram{
+			Size:      string(z.Size),
+			Ratio:     z.Ratio,
+			Algorithm: z.Algorithm,
+		}
+	}
+
+	var kdump *Kdump
+	if k := definition.OperatingSystem.Kdump; k.Memory != "" {
+		kdump = &Kdump{
+			Memory:   k.Memory,
+			SavePath: k.SavePath,
+		}
+
+		if kdump.SavePath == "" {
+			kdump.SavePath = image.KdumpDefaultSavePath
+		}
+	}
+
+	var secureBoot *SecureBoot
+	if sb := definition.OperatingSystem.SecureBoot; len(sb.MOKCertificates) != 0 || sb.Lockdown != "" || sb.Signing.Kernel || sb.Signing.Modules {
+		secureBoot = &SecureBoot{
+			MOKCertificates: sb.MOKCertificates,
+			Lockdown:        sb.Lockdown,
+			SignKernel:      sb.Signing.Kernel,
+			SignModules:     sb.Signing.Modules,
+		}
+	}
+
+	var autologin *Autologin
+	if a := definition.OperatingSystem.Autologin; a.User != "" {
+		autologin = &Autologin{
+			User: a.User,
+			TTY:  a.Console(),
+		}
+	}
+
+	var knownHosts []KnownHost
+	for _, knownHost := range definition.OperatingSystem.KnownHosts {
+		knownHosts = append(knownHosts, newKnownHost(&knownHost))
+	}
+
+	var kernel *Kernel
+	if k := definition.OperatingSystem.Packages.Kernel; k.Version != "" {
+		kernel = &Kernel{
+			Package: k.Package(),
+			Version: k.Version,
+		}
+	}
+
+	var dnsmasq *Dnsmasq
+	if d := &definition.OperatingSystem.Dnsmasq; len(d.Interfaces) != 0 || d.Domain != "" || len(d.Upstreams) != 0 ||
+		len(d.Ranges) != 0 || len(d.StaticLeases) != 0 {
+		dnsmasq = &Dnsmasq{
+			Interfaces:   d.Interfaces,
+			Domain:       d.Domain,
+			Upstreams:    d.Upstreams,
+			StaticLeases: len(d.StaticLeases),
+		}
+		for _, r := range d.Ranges {
+			dnsmasq.Ranges = append(dnsmasq.Ranges, r.Start+"-"+r.End)
+		}
+	}
+
+	var fail2ban *Fail2ban
+	for _, jail := range definition.OperatingSystem.Fail2ban.Jails {
+		if fail2ban == nil {
+			fail2ban = &Fail2ban{}
+		}
+		fail2ban.Jails = append(fail2ban.Jails, jail.Name)
+	}
+
+	var stripDocs *StripDocs
+	if definition.OperatingSystem.StripDocs.Enabled {
+		stripDocs = &StripDocs{Exclude: definition.OperatingSystem.StripDocs.Exclude}
+	}
+
+	var networkProfiles *NetworkProfiles
+	if p := &definition.OperatingSystem.NetworkProfiles; len(p.Profiles) != 0 {
+		networkProfiles = &NetworkProfiles{
+			KernelArg: p.SelectionArg(),
+			Default:   p.Default,
+			Profiles:  p.Profiles,
+		}
+	}
+
+	var mounts []Mount
+	for _, p := range definition.NodePaths() {
+		if p.IsMount() {
+			mounts = append(mounts, Mount{Path: p.Path, Description: p.Description, Field: p.Field})
+		}
+	}
+	slices.SortStableFunc(mounts, func(a, b Mount) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return &Report{
+		ImageName:         definition.Image.OutputImageName,
+		ImageType:         definition.Image.ImageType,
+		OutputFormat:      definition.Image.OutputFormat,
+		Arch:              string(definition.Image.Arch),
+		BaseImage:         definition.Image.BaseImage,
+		BaseImageRelease:  image.BaseImageRelease(definition.Image.BaseImage),
+		KubernetesVersion: definition.Kubernetes.Version,
+		KubeconfigPath:    definition.Kubernetes.Kubeconfig.Path,
+		ContainerdConfig:  containerdConfig,
+		HelmCharts:        helmCharts,
+		NodeMetadata:      nodeMetadata,
+		PasswordPolicies:  passwordPolicies,
+		AutoUpdate:        autoUpdate,
+		ScheduledJobs:     scheduledJobs,
+		Logrotate:         logrotate,
+		Mounts:            mounts,
+		Directories:       directories,
+		Environment:       environment,
+		ShellDefaults:     shellDefaults,
+		Locales:           definition.OperatingSystem.Locales.Keep,
+		GPUDriver:         gpuDriver,
+		Zram:              zram,
+		Kdump:             kdump,
+		PAMConfigFiles:    definition.OperatingSystem.PAM.ConfigFiles,
+		MachineIDPolicy:   machineIDPolicy,
+		Hostname:          definition.OperatingSystem.Hostname,
+		SecureBoot:        secureBoot,
+		Autologin:         autologin,
+		KnownHosts:        knownHosts,
+		Kernel:            kernel,
+		NetworkProfiles:   networkProfiles,
+		StripDocs:         stripDocs,
+		Fail2ban:          fail2ban,
+		Dnsmasq:           dnsmasq,
+		EIBVersion:        version.GetVersion(),
+		Created:           created.UTC().Format(time.RFC3339),
+	}
+}
+
+// newKnownHost describes a pinned host key, leaving the fingerprint unset if the key cannot be parsed.
+func newKnownHost(knownHost *image.KnownHost) KnownHost {
+	fields := strings.Fields(knownHost.Key)
+
+	entry := KnownHost{Host: knownHost.Host}
+	if len(fields) < 2 {
+		return entry
+	}
+
+	entry.Type = fields[0]
+
+	if data, err := base64.StdEncoding.DecodeString(fields[1]); err == nil {
+		if key, err := ssh.ParsePublicKey(data); err == nil {
+			entry.Fingerprint = ssh.FingerprintSHA256(key)
+		}
+	}
+
+	return entry
+}
+
+// Marshal serializes the report in the given format.
+func Marshal(report *Report, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(data, '\n'), nil
+	case FormatYAML:
+		return yaml.Marshal(report)
+	default:
+		return nil, fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// Write stores the report in the given format in the directory and returns the path to the written file.
+func Write(report *Report, dir, format string) (string, error) {
+	data, err := Marshal(report, format)
+	if err != nil {
+		return "", fmt.Errorf("serializing report: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s.%s", filenamePrefix, format))
+	if err = os.WriteFile(path, data, fileio.NonExecutablePerms); err != nil {
+		return "", fmt.Errorf("writing report: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,606 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNew(t *testing.T) {
+	definition := &image.Definition{
+		Image: image.Image{
+			ImageType:       image.TypeRAW,
+			OutputFormat:    image.OutputFormatQCOW2,
+			Arch:            image.ArchTypeX86,
+			BaseImage:       "slemicro.raw",
+			OutputImageName: "edge.qcow2",
+		},
+		Kubernetes: image.Kubernetes{
+			Version: "v1.29.0+k3s1",
+		},
+	}
+
+	report := New(definition, time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)))
+
+	assert.Equal(t, "edge.qcow2", report.ImageName)
+	assert.Equal(t, "raw", report.ImageType)
+	assert.Equal(t, "qcow2", report.OutputFormat)
+	assert.Equal(t, "x86_64", report.Arch)
+	assert.Equal(t, "slemicro.raw", report.BaseImage)
+	assert.Empty(t, report.BaseImageRelease)
+	assert.Equal(t, "v1.29.0+k3s1", report.KubernetesVersion)
+	assert.Nil(t, report.AutoUpdate)
+	assert.Nil(t, report.HelmCharts)
+	assert.Nil(t, report.NodeMetadata)
+	assert.Nil(t, report.PasswordPolicies)
+	assert.Nil(t, report.ContainerdConfig)
+	assert.Nil(t, report.ScheduledJobs)
+	assert.Nil(t, report.Logrotate)
+	assert.Nil(t, report.Directories)
+	assert.Nil(t, report.Environment)
+	assert.Nil(t, report.ShellDefaults)
+	assert.Nil(t, report.Locales)
+	assert.Nil(t, report.GPUDriver)
+	assert.Nil(t, report.Zram)
+	assert.Nil(t, report.Kdump)
+	assert.Nil(t, report.PAMConfigFiles)
+	assert.Nil(t, report.ImageArchives)
+	assert.Nil(t, report.Certificates)
+	assert.Empty(t, report.MachineIDPolicy)
+	assert.Empty(t, report.Hostname)
+	assert.Nil(t, report.SecureBoot)
+	assert.Nil(t, report.Autologin)
+	assert.Nil(t, report.KnownHosts)
+	assert.Nil(t, report.Kernel)
+	assert.Nil(t, report.NetworkProfiles)
+	assert.Nil(t, report.StripDocs)
+	assert.Nil(t, report.Fail2ban)
+	assert.Nil(t, report.Dnsmasq)
+	assert.Nil(t, report.RootSlots)
+	assert.NotEmpty(t, report.EIBVersion)
+	assert.Equal(t, "2024-05-01T10:00:00Z", report.Created)
+}
+
+func TestNewAutoUpdate(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			AutoUpdate: image.AutoUpdate{
+				Enabled:      true,
+				Schedule:     "Sat *-*-* 02:00",
+				RebootPolicy: "maint_window",
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	require.NotNil(t, report.AutoUpdate)
+	assert.True(t, report.AutoUpdate.Enabled)
+	assert.Equal(t, "Sat *-*-* 02:00", report.AutoUpdate.Schedule)
+	assert.Equal(t, "maint_window", report.AutoUpdate.RebootPolicy)
+}
+
+func TestNewNodeMetadata(t *testing.T) {
+	definition := &image.Definition{
+		Kubernetes: image.Kubernetes{
+			Nodes: []image.Node{
+				{
+					Hostname: "node1.suse.com",
+					Type:     image.KubernetesNodeTypeServer,
+				},
+				{
+					Hostname: "node2.suse.com",
+					Type:     image.KubernetesNodeTypeAgent,
+					Labels:   []string{"zone=edge-1"},
+					Taints:   []string{"dedicated=gpu:NoSchedule"},
+				},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []NodeMetadata{
+		{
+			Hostname: "node2.suse.com",
+			Labels:   []string{"zone=edge-1"},
+			Taints:   []string{"dedicated=gpu:NoSchedule"},
+		},
+	}, report.NodeMetadata)
+}
+
+func TestNewKubeconfigPath(t *testing.T) {
+	definition := &image.Definition{
+		Kubernetes: image.Kubernetes{
+			Version: "v1.29.0+rke2r1",
+			Kubeconfig: image.Kubeconfig{
+				Path: "/root/.kube/config",
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, "/root/.kube/config", report.KubeconfigPath)
+}
+
+func TestNewContainerdConfig(t *testing.T) {
+	definition := &image.Definition{
+		Kubernetes: image.Kubernetes{
+			Version: "v1.29.0+rke2r1",
+			Containerd: image.Containerd{
+				ConfigFile: "containerd.toml",
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, &ContainerdConfig{ConfigFile: "containerd.toml", Mode: image.ContainerdConfigModePartial}, report.ContainerdConfig)
+}
+
+func TestNewHelmCharts(t *testing.T) {
+	definition := &image.Definition{
+		Kubernetes: image.Kubernetes{
+			Version: "v1.29.0+rke2r1",
+			Helm: image.Helm{
+				Charts: []image.HelmChart{
+					{Name: "metallb", RepositoryName: "suse-edge", Version: "0.14.3"},
+					{Name: "endpoint-copier-operator", RepositoryName: "suse-edge", Version: "0.2.0"},
+				},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []string{"metallb", "endpoint-copier-operator"}, report.HelmCharts)
+}
+
+func TestNewBaseImageRelease(t *testing.T) {
+	definition := &image.Definition{
+		Image: image.Image{
+			BaseImage: "SL-Micro.x86_64-6.0-Base-SelfInstall-GM2.install.iso",
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, "SL Micro 6.0", report.BaseImageRelease)
+}
+
+func TestNewScheduledJobs(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			ScheduledJobs: []image.ScheduledJob{
+				{Name: "prune-images", Schedule: "weekly", Command: "podman image prune --all --force"},
+				{Name: "trim", Schedule: "@daily", Command: "fstrim --all", Backend: image.ScheduledJobBackendCron},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []string{"prune-images", "trim"}, report.ScheduledJobs)
+}
+
+func TestNewDirectories(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Directories: []image.Directory{
+				{Path: "/var/lib/app"},
+				{Path: "/srv/data", Owner: "1000", Mode: "0750"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []string{"/var/lib/app", "/srv/data"}, report.Directories)
+}
+
+func TestNewEnvironment(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Environment: map[string]image.EnvironmentVariable{
+				"AGENT_URL":   {Value: "https://agent.example.com"},
+				"AGENT_TOKEN": {Value: "s3cr3t", Sensitive: true},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []string{"AGENT_TOKEN", "AGENT_URL"}, report.Environment)
+
+	data, err := Marshal(report, FormatJSON)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+}
+
+func TestNewMounts(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			ReadOnlyRoot: image.ReadOnlyRoot{
+				Enabled: true,
+				Overlays: []image.Overlay{
+					{Path: "/usr/lib/app/data", Type: image.OverlayTypePersistent},
+					{Path: "/usr/lib/app/cache", Type: image.OverlayTypeTmpfs},
+				},
+			},
+			Directories: []image.Directory{
+				{Path: "/usr/lib/app/data/db"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	expected := []Mount{
+		{Path: "/", Description: "root partition"},
+		{Path: "/.snapshots", Description: "btrfs subvolume"},
+		{Path: "/boot/efi", Description: "EFI system partition"},
+		{Path: "/home", Description: "btrfs subvolume"},
+		{Path: "/opt", Description: "btrfs subvolume"},
+		{Path: "/root", Description: "btrfs subvolume"},
+		{Path: "/srv", Description: "btrfs subvolume"},
+		{Path: "/usr/lib/app/cache", Description: "tmpfs overlay", Field: "operatingSystem/readOnlyRoot/overlays"},
+		{Path: "/usr/lib/app/data", Description: "persistent overlay", Field: "operatingSystem/readOnlyRoot/overlays"},
+		{Path: "/usr/local", Description: "btrfs subvolume"},
+		{Path: "/var", Description: "btrfs subvolume"},
+	}
+	assert.Equal(t, expected, report.Mounts)
+}
+
+func TestMarshal(t *testing.T) {
+	report := &Report{
+		ImageName:  "edge.iso",
+		ImageType:  "iso",
+		Arch:       "x86_64",
+		BaseImage:  "slemicro.iso",
+		EIBVersion: "1.1.0",
+		Created:    "2024-01-01T00:00:00Z",
+	}
+
+	expected := map[string]any{
+		"imageName":  "edge.iso",
+		"imageType":  "iso",
+		"arch":       "x86_64",
+		"baseImage":  "slemicro.iso",
+		"eibVersion": "1.1.0",
+		"created":    "2024-01-01T00:00:00Z",
+	}
+
+	// Both formats must use the same field names
+	jsonData, err := Marshal(report, FormatJSON)
+	require.NoError(t, err)
+
+	var fromJSON map[string]any
+	require.NoError(t, json.Unmarshal(jsonData, &fromJSON))
+	assert.Equal(t, expected, fromJSON)
+
+	yamlData, err := Marshal(report, FormatYAML)
+	require.NoError(t, err)
+
+	var fromYAML map[string]any
+	require.NoError(t, yaml.Unmarshal(yamlData, &fromYAML))
+	assert.Equal(t, expected, fromYAML)
+
+	_, err = Marshal(report, "xml")
+	assert.EqualError(t, err, "unsupported report format: xml")
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	report := &Report{ImageName: "edge.raw", ImageType: "raw", OutputFormat: "qcow2"}
+
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			path, err := Write(report, dir, format)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(dir, "build-report."+format), path)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "outputFormat")
+			assert.NotContains(t, string(data), "kubernetesVersion")
+		})
+	}
+}
+
+func TestNewMachineIDPolicy(t *testing.T) {
+	tests := map[string]struct {
+		MachineID      image.MachineID
+		ExpectedPolicy string
+	}{
+		`clear`: {
+			MachineID:      image.MachineID{Clear: true},
+			ExpectedPolicy: "clear",
+		},
+		`value`: {
+			MachineID:      image.MachineID{Value: "4c4c4544003910528052b4c04f4e5432"},
+			ExpectedPolicy: "fixed",
+		},
+		`seed`: {
+			MachineID:      image.MachineID{Seed: "edge-fleet"},
+			ExpectedPolicy: "seeded",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			definition := &image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					MachineID: test.MachineID,
+				},
+			}
+
+			report := New(definition, time.Now())
+			assert.Equal(t, test.ExpectedPolicy, report.MachineIDPolicy)
+		})
+	}
+}
+
+func TestNewHostname(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Hostname: "kiosk-01.example.com",
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, "kiosk-01.example.com", report.Hostname)
+}
+
+func TestNewSecureBoot(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			SecureBoot: image.SecureBoot{
+				MOKCertificates: []string{"edge.der"},
+				Signing: image.SecureBootSigning{
+					Key:         "edge.key",
+					Certificate: "edge.crt",
+					Kernel:      true,
+				},
+				Lockdown: image.LockdownIntegrity,
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, &SecureBoot{
+		MOKCertificates: []string{"edge.der"},
+		Lockdown:        "integrity",
+		SignKernel:      true,
+	}, report.SecureBoot)
+}
+
+func TestNewAutologin(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Autologin: image.Autologin{
+				User: "kiosk",
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, &Autologin{User: "kiosk", TTY: "tty1"}, report.Autologin)
+}
+
+func TestNewKnownHosts(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			KnownHosts: []image.KnownHost{
+				{Host: "git.example.com", Key: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIPOAHN0CdWw9HIwQ/eAq2s5xUztoAjAXPJH88XRfllmN git"},
+				{Host: "[registry.example.com]:2222", Key: "ecdsa-sha2-nistp256 invalid"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, []KnownHost{
+		{Host: "git.example.com", Type: "ssh-ed25519", Fingerprint: "SHA256:Ofw3dNqTsgzgS2rmTJctJmwPuX9ab9dSbJPOaLiQiSk"},
+		{Host: "[registry.example.com]:2222", Type: "ecdsa-sha2-nistp256"},
+	}, report.KnownHosts)
+}
+
+func TestNewKernel(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Packages: image.Packages{
+				Kernel: image.Kernel{Version: "6.4.0-150600.23.25.1"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, &Kernel{Package: "kernel-default", Version: "6.4.0-150600.23.25.1"}, report.Kernel)
+}
+
+func TestNewNetworkProfiles(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			NetworkProfiles: image.NetworkProfiles{
+				Default:  "office",
+				Profiles: []string{"office", "field"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, &NetworkProfiles{
+		KernelArg: "eib.network_profile",
+		Default:   "office",
+		Profiles:  []string{"office", "field"},
+	}, report.NetworkProfiles)
+}
+
+func TestNewStripDocs(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			StripDocs: image.StripDocs{
+				Enabled: true,
+				Exclude: []string{"/usr/share/doc/packages/openssl"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, &StripDocs{Exclude: []string{"/usr/share/doc/packages/openssl"}}, report.StripDocs)
+}
+
+func TestNewFail2ban(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Fail2ban: image.Fail2ban{
+				BanTime: "1h",
+				Jails:   []image.Fail2banJail{{Name: "sshd", MaxRetry: 3}, {Name: "cockpit"}},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, &Fail2ban{Jails: []string{"sshd", "cockpit"}}, report.Fail2ban)
+}
+
+func TestNewDnsmasq(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Dnsmasq: image.Dnsmasq{
+				Interfaces: []string{"eth1"},
+				Upstreams:  []string{"9.9.9.9"},
+				Ranges:     []image.DHCPRange{{Start: "192.168.50.100", End: "192.168.50.200", LeaseTime: "12h"}},
+				StaticLeases: []image.StaticLease{
+					{MAC: "52:54:00:aa:bb:cc", IP: "192.168.50.10"},
+				},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+	assert.Equal(t, &Dnsmasq{
+		Interfaces:   []string{"eth1"},
+		Upstreams:    []string{"9.9.9.9"},
+		Ranges:       []string{"192.168.50.100-192.168.50.200"},
+		StaticLeases: 1,
+	}, report.Dnsmasq)
+}
+
+func TestNewGPUDriver(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			GPUDrivers: image.GPUDrivers{
+				Version:  "550.90.07",
+				Packages: []string{"nvidia-open-driver-G06-signed-kmp-default", "nvidia-compute-utils-G06"},
+				KernelModules: image.GPUKernelModules{
+					Blacklist: []string{"nouveau"},
+				},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	require.NotNil(t, report.GPUDriver)
+	assert.Equal(t, "550.90.07", report.GPUDriver.Version)
+	assert.Equal(t, []string{"nvidia-open-driver-G06-signed-kmp-default", "nvidia-compute-utils-G06"}, report.GPUDriver.Packages)
+}
+
+func TestNewLogrotate(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Logrotate: []image.LogRotation{
+				{Name: "workload", Paths: []string{"/var/log/workload/*.log"}, Frequency: image.LogRotationDaily, Rotate: 7},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []LogRotation{{Name: "workload", Paths: []string{"/var/log/workload/*.log"}}}, report.Logrotate)
+}
+
+func TestNewShellDefaults(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			ShellDefaults: image.ShellDefaults{
+				Editor:      "vim",
+				Environment: map[string]string{"HISTSIZE": "5000"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, &ShellDefaults{Editor: "vim", Environment: map[string]string{"HISTSIZE": "5000"}}, report.ShellDefaults)
+}
+
+func TestNewZram(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Zram: image.Zram{
+				Ratio:     0.5,
+				Algorithm: "zstd",
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, &Zram{Ratio: 0.5, Algorithm: "zstd"}, report.Zram)
+}
+
+func TestNewKdump(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Kdump: image.Kdump{
+				Memory: "512M",
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, &Kdump{Memory: "512M", SavePath: "/var/crash"}, report.Kdump)
+}
+
+func TestNewPAMConfigFiles(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			PAM: image.PAM{
+				ConfigFiles: []string{"workload", "common-auth"},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []string{"workload", "common-auth"}, report.PAMConfigFiles)
+}
+
+func TestNewPasswordPolicies(t *testing.T) {
+	definition := &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Users: []image.OperatingSystemUser{
+				{Username: "root", EncryptedPassword: "$6$salt$hash", ForceChange: true},
+				{Username: "ops", EncryptedPassword: "$6$salt$hash"},
+				{Username: "admin", EncryptedPassword: "$6$salt$hash", PasswordExpire: 90},
+			},
+		},
+	}
+
+	report := New(definition, time.Now())
+
+	assert.Equal(t, []PasswordPolicy{
+		{Username: "root", ForceChange: true},
+		{Username: "admin", ExpireDays: 90},
+	}, report.PasswordPolicies)
+}
@@ -36,14 +36,17 @@ type TarballImageBuilder struct {
 	imgType string
 	// imgImporter used to import the tarball archive as a container image
 	imgImporter ImageImporter
+	// env holds additional environment variables of the guestfish runs extracting the image contents
+	env []string
 }
 
-func NewTarballBuilder(workDir, imgPath, imgType string, importer ImageImporter) *TarballImageBuilder {
+func NewTarballBuilder(workDir, imgPath, imgType string, env []string, importer ImageImporter) *TarballImageBuilder {
 	return &TarballImageBuilder{
 		dir:         workDir,
 		imgPath:     imgPath,
 		imgType:     imgType,
 		imgImporter: importer,
+		env:         env,
 	}
 }
 
@@ -134,6 +137,11 @@ func (t *TarballImageBuilder) prepareTarballImageCmd(log io.Writer) *exec.Cmd {
 	cmd := exec.Command(scriptPath)
 	cmd.Stdout = log
 	cmd.Stderr = log
+
+	if len(t.env) != 0 {
+		cmd.Env = append(os.Environ(), t.env...)
+	}
+
 	return cmd
 }
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
@@ -178,13 +179,15 @@ func (r *Resolver) prepareLocalRPMs(localRPMConfig *image.LocalRPMConfig) error
 
 func (r *Resolver) writeRPMResolutionScript(localRPMConfig *image.LocalRPMConfig, packages *image.Packages) error {
 	values := struct {
-		RegCode      string
-		AddRepo      []image.AddRepo
-		CacheDir     string
-		PKGList      string
-		LocalRPMList string
-		LocalGPGList string
-		NoGPGCheck   bool
+		RegCode       string
+		AddRepo       []image.AddRepo
+		CacheDir      string
+		PKGList       string
+		LocalRPMList  string
+		LocalGPGList  string
+		NoGPGCheck    bool
+		KernelName    string
+		KernelVersion string
 	}{
 		RegCode:    packages.RegCode,
 		AddRepo:    packages.AdditionalRepos,
@@ -192,8 +195,16 @@ func (r *Resolver) writeRPMResolutionScript(localRPMConfig *image.LocalRPMConfig
 		NoGPGCheck: packages.NoGPGCheck,
 	}
 
-	if len(packages.PKGList) > 0 {
-		values.PKGList = strings.Join(packages.PKGList, " ")
+	pkgList := packages.PKGList
+	if packages.Kernel.Version != "" {
+		// The pinned kernel is only resolved here, it is installed while assembling the image
+		values.KernelName = packages.Kernel.Package()
+		values.KernelVersion = packages.Kernel.Version
+		pkgList = append(slices.Clone(pkgList), packages.Kernel.Package()+"="+packages.Kernel.Version)
+	}
+
+	if len(pkgList) > 0 {
+		values.PKGList = strings.Join(pkgList, " ")
 	}
 
 	if localRPMConfig != nil {
@@ -2,13 +2,15 @@
 set -euo pipefail
 
 #  Template Fields
-#  RegCode      - scc.suse.com registration code
-#  AddRepo      - additional third-party repositories that will be used in the resolution process
-#  CacheDir     - zypper cache directory where all rpm dependencies will be downloaded to
-#  PKGList      - list of packages for which to do the dependency resolution
-#  LocalRPMList - list of local RPMs for which dependency resolution has to be done
-#  LocalGPGList - list of local GPG keys that will be imported in the resolver image
-#  NoGPGCheck   - when set to true skips the GPG validation for all third-party repositories and local RPMs
+#  RegCode       - scc.suse.com registration code
+#  AddRepo       - additional third-party repositories that will be used in the resolution process
+#  CacheDir      - zypper cache directory where all rpm dependencies will be downloaded to
+#  PKGList       - list of packages for which to do the dependency resolution
+#  LocalRPMList  - list of local RPMs for which dependency resolution has to be done
+#  LocalGPGList  - list of local GPG keys that will be imported in the resolver image
+#  NoGPGCheck    - when set to true skips the GPG validation for all third-party repositories and local RPMs
+#  KernelName    - name of the pinned kernel package, empty if the kernel of the base image is kept
+#  KernelVersion - version and release of the pinned kernel package
 
 {{ if ne .RegCode "" }}
 suseconnect -r {{ .RegCode }}
@@ -38,6 +40,15 @@ rpm --import {{ .LocalGPGList }}
 rpm -Kv {{ .LocalRPMList }}
 {{ end -}}
 
+{{ if .KernelName -}}
+# Fails early listing the available versions, rather than with the generic resolution error
+KERNEL_VERSIONS=$(zypper --non-interactive search --details --match-exact --type package {{.KernelName}} | awk -F'|' '{gsub(/ /, "", $2); gsub(/ /, "", $4)} $2 == "{{.KernelName}}" {print $4}' | sort -u || true)
+if ! echo "$KERNEL_VERSIONS" | grep -xF '{{.KernelVersion}}' > /dev/null; then
+  echo "[ERROR] Version {{.KernelVersion}} of the kernel package {{.KernelName}} is not available in the configured repositories, found: $(echo $KERNEL_VERSIONS)"
+  exit 104
+fi
+
+{{ end -}}
 zypper \
   --pkg-cache-dir {{.CacheDir}} \
   --gpg-auto-import-keys \
@@ -49,6 +60,17 @@ zypper \
   --force-resolution \
   --auto-agree-with-licenses \
   --allow-vendor-change \
-  -n {{.PKGList}} {{.LocalRPMList}}
+  -n {{.PKGList}} {{.LocalRPMList}} || {
+  status=$?
+  case $status in
+    104)
+      echo "[ERROR] Some of the requested packages were not found in the configured repositories" ;;
+    4)
+      echo "[ERROR] The dependencies of the requested packages could not be resolved" ;;
+    *)
+      echo "[ERROR] Resolving the dependencies of the requested packages failed with zypper exit code $status" ;;
+  esac
+  exit $status
+}
 
 touch {{.CacheDir}}/zypper-success
\ No newline at end of file
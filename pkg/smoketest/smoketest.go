@@ -0,0 +1,216 @@
+package smoketest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMarker is printed on the serial console once the system has booted and is ready for logins.
+	DefaultMarker = "login:"
+
+	kvmDevice = "/dev/kvm"
+	memoryMB  = 2048
+	cpus      = 2
+)
+
+var ErrTimeout = errors.New("timed out waiting for the boot to complete")
+
+// aarch64Firmware lists the locations of the UEFI firmware required to boot aarch64 virtual machines.
+var aarch64Firmware = []string{
+	"/usr/share/qemu/aavmf-aarch64-code.bin",
+	"/usr/share/qemu/qemu-uefi-aarch64.bin",
+	"/usr/share/AAVMF/AAVMF_CODE.fd",
+	"/usr/share/edk2/aarch64/QEMU_EFI.fd",
+}
+
+type Config struct {
+	// QEMUPath is the path to the QEMU system emulator matching the architecture of the image.
+	QEMUPath string
+	// ImagePath is the path to the image to boot. The image itself is never modified.
+	ImagePath string
+	// ImageFormat is the disk format of the image (e.g. raw or qcow2).
+	ImageFormat string
+	// Arch is the architecture of the image.
+	Arch image.Arch
+	// LogPath is the file the serial console output of the boot is captured in.
+	LogPath string
+	// Marker is the serial console output signaling a successful boot.
+	Marker string
+	// Timeout is the maximum duration to wait for the marker.
+	Timeout time.Duration
+}
+
+// FindQEMU returns the path to the QEMU system emulator for the given architecture.
+func FindQEMU(arch image.Arch) (string, error) {
+	executable := fmt.Sprintf("qemu-system-%s", arch)
+
+	path, err := exec.LookPath(executable)
+	if err != nil {
+		return "", fmt.Errorf("looking up %s: %w", executable, err)
+	}
+
+	return path, nil
+}
+
+// Run boots the image in a headless virtual machine and waits for the marker to be printed on the
+// serial console. The virtual machine is stopped once the marker is found or the timeout expires.
+func Run(config *Config) error {
+	args, err := qemuArgs(config, kvmAvailable(config.Arch))
+	if err != nil {
+		return fmt.Errorf("assembling QEMU arguments: %w", err)
+	}
+
+	logFile, err := os.OpenFile(config.LogPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fileio.NonExecutablePerms)
+	if err != nil {
+		return fmt.Errorf("creating boot log: %w", err)
+	}
+	defer logFile.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, config.QEMUPath, args...)
+	cmd.Stderr = logFile
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("connecting to the serial console: %w", err)
+	}
+
+	zap.S().Infof("Running smoke test: %s", cmd.String())
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("starting QEMU: %w", err)
+	}
+
+	found, scanErr := scanForMarker(stdout, logFile, []byte(config.Marker))
+
+	// The virtual machine has served its purpose once the marker is found
+	cancel()
+	waitErr := cmd.Wait()
+
+	switch {
+	case found:
+		return nil
+	case ctx.Err() != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return ErrTimeout
+	case scanErr != nil:
+		return fmt.Errorf("reading the serial console: %w", scanErr)
+	case waitErr != nil:
+		return fmt.Errorf("QEMU exited before the boot completed: %w", waitErr)
+	default:
+		return errors.New("QEMU exited before the boot completed")
+	}
+}
+
+// scanForMarker copies the output into the log until the marker is found or the output ends.
+func scanForMarker(output io.Reader, log io.Writer, marker []byte) (bool, error) {
+	buf := make([]byte, 4096)
+
+	// The end of the previous read is retained, as the marker may be split across reads
+	var window []byte
+
+	for {
+		n, err := output.Read(buf)
+		if n > 0 {
+			if _, writeErr := log.Write(buf[:n]); writeErr != nil {
+				return false, fmt.Errorf("writing boot log: %w", writeErr)
+			}
+
+			window = append(window, buf[:n]...)
+			if bytes.Contains(window, marker) {
+				return true, nil
+			}
+
+			if keep := len(marker) - 1; len(window) > keep {
+				window = window[len(window)-keep:]
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, os.ErrClosed) {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+}
+
+func qemuArgs(config *Config, kvm bool) ([]string, error) {
+	format := config.ImageFormat
+	if format == "" {
+		format = image.OutputFormatRAW
+	}
+
+	args := []string{
+		"-m", fmt.Sprint(memoryMB),
+		"-smp", fmt.Sprint(cpus),
+		"-nographic",
+		"-monitor", "none",
+		"-serial", "stdio",
+		"-nic", "user,model=virtio-net-pci",
+		// The snapshot keeps the changes made during the boot (e.g. by combustion) out of the built image
+		"-snapshot",
+		"-drive", fmt.Sprintf("file=%s,format=%s,if=virtio", config.ImagePath, format),
+	}
+
+	switch config.Arch {
+	case image.ArchTypeX86:
+		args = append(args, "-machine", "q35")
+	case image.ArchTypeARM:
+		firmware, err := findFirmware(aarch64Firmware)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-machine", "virt", "-bios", firmware)
+	default:
+		return nil, fmt.Errorf("unsupported architecture: %s", config.Arch)
+	}
+
+	if kvm {
+		args = append(args, "-enable-kvm", "-cpu", "host")
+	} else {
+		args = append(args, "-cpu", "max")
+	}
+
+	return args, nil
+}
+
+func findFirmware(candidates []string) (string, error) {
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("no UEFI firmware found in: %v", candidates)
+}
+
+// kvmAvailable checks whether hardware acceleration can be used, which greatly reduces the boot time.
+// Acceleration is only possible when the image matches the architecture of the host.
+func kvmAvailable(arch image.Arch) bool {
+	hostArch := map[string]image.Arch{"amd64": image.ArchTypeX86, "arm64": image.ArchTypeARM}[runtime.GOARCH]
+	if hostArch != arch {
+		return false
+	}
+
+	f, err := os.OpenFile(kvmDevice, os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+
+	_ = f.Close()
+	return true
+}
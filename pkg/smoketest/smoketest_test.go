@@ -0,0 +1,129 @@
+package smoketest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestQEMUArgs(t *testing.T) {
+	config := &Config{
+		ImagePath: "/eib/edge.raw",
+		Arch:      image.ArchTypeX86,
+	}
+
+	args, err := qemuArgs(config, false)
+	require.NoError(t, err)
+
+	joined := strings.Join(args, " ")
+	assert.Contains(t, joined, "-serial stdio")
+	assert.Contains(t, joined, "-snapshot")
+	assert.Contains(t, joined, "-drive file=/eib/edge.raw,format=raw,if=virtio")
+	assert.Contains(t, joined, "-machine q35")
+	assert.Contains(t, joined, "-cpu max")
+	assert.NotContains(t, joined, "-enable-kvm")
+}
+
+func TestQEMUArgs_KVMAndQCOW2(t *testing.T) {
+	config := &Config{
+		ImagePath:   "/eib/edge.qcow2",
+		ImageFormat: image.OutputFormatQCOW2,
+		Arch:        image.ArchTypeX86,
+	}
+
+	args, err := qemuArgs(config, true)
+	require.NoError(t, err)
+
+	joined := strings.Join(args, " ")
+	assert.Contains(t, joined, "-drive file=/eib/edge.qcow2,format=qcow2,if=virtio")
+	assert.Contains(t, joined, "-enable-kvm -cpu host")
+}
+
+func TestQEMUArgs_UnsupportedArch(t *testing.T) {
+	_, err := qemuArgs(&Config{Arch: "ppc64le"}, false)
+	assert.EqualError(t, err, "unsupported architecture: ppc64le")
+}
+
+func TestScanForMarker(t *testing.T) {
+	output := "Welcome to SUSE Linux Micro\n\nlocalhost login: "
+
+	var log bytes.Buffer
+	found, err := scanForMarker(iotest.OneByteReader(strings.NewReader(output)), &log, []byte(DefaultMarker))
+
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, output[:len(output)-1], log.String())
+}
+
+func TestScanForMarker_NotFound(t *testing.T) {
+	var log bytes.Buffer
+	found, err := scanForMarker(strings.NewReader("Kernel panic"), &log, []byte(DefaultMarker))
+
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, "Kernel panic", log.String())
+}
+
+func writeFakeQEMU(t *testing.T, dir, script string) string {
+	path := filepath.Join(dir, "qemu-system-x86_64")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o700))
+
+	return path
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		script        string
+		expectedLog   string
+		expectedError string
+	}{
+		"booted": {
+			script:      `echo "Booting"; printf "localhost login: "; exec sleep 30`,
+			expectedLog: "Booting\nlocalhost login: ",
+		},
+		"timeout": {
+			script:        `echo "Booting"; exec sleep 30`,
+			expectedLog:   "Booting\n",
+			expectedError: ErrTimeout.Error(),
+		},
+		"exited": {
+			script:        `echo "Kernel panic"; exit 1`,
+			expectedLog:   "Kernel panic\n",
+			expectedError: "QEMU exited before the boot completed: exit status 1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			config := &Config{
+				QEMUPath:  writeFakeQEMU(t, tmpDir, test.script),
+				ImagePath: filepath.Join(tmpDir, "edge.raw"),
+				Arch:      image.ArchTypeX86,
+				LogPath:   filepath.Join(tmpDir, "smoke-test.log"),
+				Marker:    DefaultMarker,
+				Timeout:   time.Second,
+			}
+
+			err := Run(config)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+
+			log, err := os.ReadFile(config.LogPath)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedLog, string(log))
+		})
+	}
+}
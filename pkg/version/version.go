@@ -7,6 +7,10 @@ import (
 
 var version string
 
+// SupportedSchemaVersions are the versions of the image definition schema accepted by this version of Edge Image
+// Builder, unless definitions are validated against the schemas of an alternate schema directory.
+var SupportedSchemaVersions = []string{"1.0"}
+
 func GetVersion() string {
 	if version != "" {
 		return version